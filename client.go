@@ -16,16 +16,26 @@ import (
 
 // Client represents a configured schemaflow client instance.
 type Client struct {
-	openaiClient *openai.Client // Legacy OpenAI client
-	apiKey       string
-	provider     llm.Provider
-	providerName string
-	timeout      time.Duration
-	maxRetries   int
-	retryBackoff time.Duration
-	logger       *telemetry.Logger
-	debugMode    bool
-	mu           sync.RWMutex
+	openaiClient  *openai.Client // Legacy OpenAI client
+	apiKey        string
+	provider      llm.Provider
+	providerName  string
+	override      *llm.ProviderConfig // explicit config of a provider built by name; nil for a provider instance
+	organization  string
+	project       string
+	timeout       time.Duration
+	maxRetries    int
+	retryBackoff  time.Duration
+	logger        *telemetry.Logger
+	debugMode     bool
+	slowThreshold time.Duration
+	defaults      *OpOptions
+	persona       string
+	interceptors  []Interceptor
+	scrubbers     []OutboundScrubber
+	restorers     []InboundRestorer
+	scoped        bool // a clone, whose settings apply only to its runs
+	mu            sync.RWMutex
 }
 
 // NewClient creates a new client with custom configuration
@@ -33,6 +43,7 @@ func NewClient(apiKey string) *Client {
 	client := &Client{
 		apiKey:       apiKey,
 		providerName: "openai",
+		override:     &llm.ProviderConfig{},
 		timeout:      30 * time.Second,
 		maxRetries:   3,
 		retryBackoff: 1 * time.Second,
@@ -56,6 +67,69 @@ func NewClient(apiKey string) *Client {
 	return client
 }
 
+// Clone returns an independent copy of the client. Setters on the copy do not
+// change the parent or the process-wide provider, so a shared base client can
+// be specialized per request. The exceptions are WithSlowThreshold,
+// WithOperationLog, WithRequestTracking and the logger level set by
+// WithDebug, which remain process-wide. Operations use the copy's provider
+// when run under one of its runs:
+//
+//	anthropic := base.Clone().WithProvider("anthropic")
+//	run := anthropic.NewRun(ctx)
+//	summary, err := schemaflow.SummarizeCtx(run, text, schemaflow.NewSummarizeOptions())
+//
+// Providers are shared until the copy configures its own.
+func (client *Client) Clone() *Client {
+	client.mu.RLock()
+	defer client.mu.RUnlock()
+	var override *llm.ProviderConfig
+	if client.override != nil {
+		config := *client.override
+		config.ExtraHeaders = cloneStringMap(config.ExtraHeaders)
+		override = &config
+	}
+	var defaults *OpOptions
+	if client.defaults != nil {
+		copied := *client.defaults
+		copied.RequestMetadata = cloneStringMap(copied.RequestMetadata)
+		defaults = &copied
+	}
+	return &Client{
+		openaiClient:  client.openaiClient,
+		apiKey:        client.apiKey,
+		provider:      client.provider,
+		providerName:  client.providerName,
+		override:      override,
+		organization:  client.organization,
+		project:       client.project,
+		timeout:       client.timeout,
+		maxRetries:    client.maxRetries,
+		retryBackoff:  client.retryBackoff,
+		logger:        client.logger,
+		debugMode:     client.debugMode,
+		slowThreshold: client.slowThreshold,
+		defaults:      defaults,
+		persona:       client.persona,
+		interceptors:  append([]Interceptor(nil), client.interceptors...),
+		scrubbers:     append([]OutboundScrubber(nil), client.scrubbers...),
+		restorers:     append([]InboundRestorer(nil), client.restorers...),
+		scoped:        true,
+	}
+}
+
+// Provider returns the client's configured provider.
+func (client *Client) Provider() llm.Provider {
+	client.mu.RLock()
+	defer client.mu.RUnlock()
+	return client.provider
+}
+
+// Capabilities reports the optional features of the client's provider, such
+// as streaming or enforced JSON output.
+func (client *Client) Capabilities() ProviderCapabilities {
+	return llm.CapabilitiesOf(client.Provider())
+}
+
 // WithTimeout sets a custom timeout for the client
 func (client *Client) WithTimeout(timeout time.Duration) *Client {
 	client.mu.Lock()
@@ -97,6 +171,7 @@ func (client *Client) WithProviderConfig(providerName string, config llm.Provide
 
 	providerName = normalizeProviderName(providerName)
 	client.providerName = providerName
+	client.override = &config
 
 	provider, err := llm.CreateProvider(providerName, client.providerConfig(providerName, config))
 	if err != nil {
@@ -105,7 +180,9 @@ func (client *Client) WithProviderConfig(providerName string, config llm.Provide
 	}
 
 	client.provider = provider
-	ops.SetDefaultProvider(provider)
+	if !client.scoped {
+		ops.SetDefaultProvider(provider)
+	}
 	client.logger.Info("Provider configured", "provider", providerName)
 
 	return client
@@ -123,11 +200,61 @@ func (client *Client) WithProviderInstance(provider llm.Provider) *Client {
 
 	client.provider = provider
 	client.providerName = provider.Name()
-	ops.SetDefaultProvider(provider)
+	client.override = nil
+	if !client.scoped {
+		ops.SetDefaultProvider(provider)
+	}
 	client.logger.Info("Provider configured", "provider", provider.Name(), "mode", "instance")
 	return client
 }
 
+// WithOrganization attributes the client's provider calls to a billing
+// organization, sent as the OpenAI-Organization header by OpenAI-compatible
+// providers. Providers without organization headers ignore it. An explicit
+// ProviderConfig.OrgID takes precedence.
+//
+// Example:
+//
+//	client := schemaflow.NewClient(apiKey).
+//	    WithOrganization("org-analytics").
+//	    WithProjectHeader("proj_reporting")
+func (client *Client) WithOrganization(id string) *Client {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.organization = id
+	client.rebuildProvider()
+	return client
+}
+
+// WithProjectHeader attributes the client's provider calls to a billing
+// project, sent as the OpenAI-Project header by the OpenAI provider.
+// Providers without project headers ignore it. An explicit
+// ProviderConfig.ProjectID takes precedence.
+func (client *Client) WithProjectHeader(id string) *Client {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.project = id
+	client.rebuildProvider()
+	return client
+}
+
+// rebuildProvider recreates a provider built by name so it picks up changed
+// client settings. Provider instances are left alone, as is the current
+// provider when the rebuild fails. Callers hold client.mu.
+func (client *Client) rebuildProvider() {
+	if client.override == nil {
+		return
+	}
+	provider, err := llm.CreateProvider(client.providerName, client.providerConfig(client.providerName, *client.override))
+	if err != nil {
+		return
+	}
+	if !client.scoped && client.provider != nil && ops.DefaultProvider() == client.provider {
+		ops.SetDefaultProvider(provider)
+	}
+	client.provider = provider
+}
+
 // WithDebug enables debug mode for the client
 func (client *Client) WithDebug(enabled bool) *Client {
 	client.mu.Lock()
@@ -139,12 +266,249 @@ func (client *Client) WithDebug(enabled bool) *Client {
 	return client
 }
 
+// WithSlowThreshold logs a warning and records the "slow_operation" metric,
+// tagged with the operation name, whenever an operation takes longer than d.
+// The operation is timed from its start across all of its LLM calls, so a
+// run of individually fast calls still counts, and it is reported once. Like
+// the provider, the threshold applies process-wide.
+// A zero duration disables the check.
+func (client *Client) WithSlowThreshold(d time.Duration) *Client {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.slowThreshold = d
+	ops.SetSlowThreshold(d)
+	return client
+}
+
+// WithDefaultOptions sets options applied to every operation run under one
+// of the client's runs unless the call overrides them, with precedence
+// call > default > client. Mode and Intelligence are always taken from opts;
+// other fields apply when non-zero. The defaults belong to this client only:
+// other clients and calls outside its runs do not see them.
+//
+//	client.WithDefaultOptions(schemaflow.OpOptions{Mode: schemaflow.TransformMode, Intelligence: schemaflow.Smart})
+//	run := client.NewRun(ctx)
+//	schemaflow.ExtractCtx[Invoice](run, doc, schemaflow.NewExtractOptions())                               // Smart
+//	schemaflow.ExtractCtx[Invoice](run, doc, schemaflow.NewExtractOptions().WithIntelligence(schemaflow.Fast)) // Fast
+func (client *Client) WithDefaultOptions(opts OpOptions) *Client {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.defaults = &opts
+	return client
+}
+
+// WithPersona places system text before the system prompt of every
+// operation run under one of the client's runs, such as "You are a strict
+// financial auditor.", keeping each operation's own instructions after it.
+// Like WithDefaultOptions it belongs to this client only; a call's
+// WithPersona, then a Persona in the default options, replaces it. An empty
+// persona removes it.
+//
+//	client.WithPersona("You are a strict financial auditor. Flag anything unverifiable.")
+//	run := client.NewRun(ctx)
+//	schemaflow.ExtractCtx[Invoice](run, doc, schemaflow.NewExtractOptions())                                       // auditor
+//	schemaflow.ExtractCtx[Invoice](run, doc, schemaflow.NewExtractOptions().WithPersona("You are a friendly guide.")) // guide
+func (client *Client) WithPersona(persona string) *Client {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.persona = persona
+	return client
+}
+
+// WithOperationLog calls sink with one structured record per LLM call an
+// operation makes: operation, provider, model, latency, token usage, status
+// and a prompt preview. PII and secrets in the preview are masked by default;
+// pass RedactOptions to choose the categories, patterns and mask. Like the
+// provider, the sink applies process-wide; nil disables it.
+//
+//	client.WithOperationLog(schemaflow.OperationLogWriter(os.Stderr)) // JSON lines
+func (client *Client) WithOperationLog(sink func(OperationLog), redact ...RedactOptions) *Client {
+	ops.SetOperationLog(sink, redact...)
+	return client
+}
+
+// WithInterceptor adds an interceptor around every provider completion made
+// under one of the client's runs, in the style of gRPC interceptors: it
+// receives the next CompletionFunc and returns one that wraps it.
+// Interceptors run in the order they are added, belong to this client only
+// (clones start with a copy of the chain) and are not applied to streaming.
+//
+//	client.WithInterceptor(func(next schemaflow.CompletionFunc) schemaflow.CompletionFunc {
+//	    return func(ctx context.Context, req schemaflow.CompletionRequest) (schemaflow.CompletionResponse, error) {
+//	        req.Headers = map[string]string{"X-Tenant": tenantID(ctx)}
+//	        return next(ctx, req)
+//	    }
+//	})
+func (client *Client) WithInterceptor(interceptor Interceptor) *Client {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	// Runs already started keep the chain they were given
+	client.interceptors = append(client.interceptors[:len(client.interceptors):len(client.interceptors)], interceptor)
+	return client
+}
+
+// WithResponseCache answers repeated deterministic requests (any mode but
+// Creative, with no explicit temperature above zero) from cache instead of
+// the provider. It is added as an interceptor, so it applies to the client's
+// runs and interceptors added after it do not run on a cache hit. Use
+// NewDiskCache for a cache that survives restarts.
+//
+//	cache, err := schemaflow.NewDiskCache(".schemaflow-cache", 256<<20)
+//	client.WithResponseCache(cache)
+func (client *Client) WithResponseCache(cache Cache) *Client {
+	return client.WithInterceptor(llm.CacheInterceptor(cache))
+}
+
+// WithOutboundScrubber rewrites every provider request just before it is
+// sent, after all interceptors, so teams can tokenize or mask PII that must
+// not leave the network. Pair it with WithInboundRestorer to put the
+// originals back into responses. Like interceptors, scrubbers belong to this
+// client only and apply under its runs (clones start with a copy), but they
+// also cover streaming. They run after any process-wide scrubbers.
+//
+//	vault := newTokenVault()
+//	client.WithOutboundScrubber(func(req schemaflow.CompletionRequest) schemaflow.CompletionRequest {
+//	    req.UserPrompt = vault.Tokenize(req.UserPrompt)
+//	    return req
+//	}).WithInboundRestorer(func(resp schemaflow.CompletionResponse) schemaflow.CompletionResponse {
+//	    resp.Content = vault.Detokenize(resp.Content)
+//	    return resp
+//	})
+func (client *Client) WithOutboundScrubber(scrubber OutboundScrubber) *Client {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.scrubbers = append(client.scrubbers[:len(client.scrubbers):len(client.scrubbers)], scrubber)
+	return client
+}
+
+// WithInboundRestorer rewrites every successful provider response as soon as
+// it arrives, before interceptors and operations see it, typically to undo a
+// WithOutboundScrubber. For streaming calls only the assembled response is
+// restored, not the fragments delivered along the way. Restorers apply under
+// the client's runs, the last added first, so they unwind scrubbers in
+// reverse order.
+func (client *Client) WithInboundRestorer(restorer InboundRestorer) *Client {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.restorers = append(client.restorers[:len(client.restorers):len(client.restorers)], restorer)
+	return client
+}
+
+// WithCircuitBreaker stops calling a failing provider: after
+// cfg.FailureThreshold consecutive provider failures, operations fail fast
+// with ErrCircuitOpen until cfg.OpenDuration has elapsed, then
+// cfg.HalfOpenProbes trial calls decide whether to close the breaker. It is
+// added as an interceptor, so it applies to the client's runs, which share
+// one breaker.
+//
+//	client.WithCircuitBreaker(schemaflow.CBConfig{FailureThreshold: 5, OpenDuration: time.Minute})
+func (client *Client) WithCircuitBreaker(cfg CBConfig) *Client {
+	return client.WithInterceptor(ops.CircuitBreakerInterceptor(cfg))
+}
+
 // WithRequestTracking configures global request and correlation tracking behavior.
 func (client *Client) WithRequestTracking(cfg requesttracking.Config) *Client {
 	requesttracking.Configure(cfg)
 	return client
 }
 
+// RunContext groups the operations of one unit of work, such as an incoming
+// request, under a single context: they share a correlation ID and cancellation,
+// and their token usage and cost roll up in one place. It is a
+// context.Context, so pass it to the Ctx operation variants.
+type RunContext struct {
+	context.Context
+	client        *Client
+	usage         *ops.RunUsage
+	meta          *ops.ResultMetaRecorder
+	correlationID string
+}
+
+// NewRun starts a RunContext derived from ctx. The run reuses ctx's
+// correlation ID or generates one, and its operations use the client's
+// provider, default options, persona, interceptors, scrubbers and restorers.
+//
+//	run := client.NewRun(r.Context())
+//	invoice, _ := schemaflow.ExtractCtx[Invoice](run, body, schemaflow.NewExtractOptions())
+//	summary, _ := schemaflow.SummarizeCtx(run, invoice.Notes, schemaflow.NewSummarizeOptions())
+//	log.Printf("run %s used %d tokens", run.CorrelationID(), run.Usage().TotalTokens)
+func (client *Client) NewRun(ctx context.Context) *RunContext {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	correlationID := requesttracking.FromContext(ctx).CorrelationID
+	if correlationID == "" {
+		correlationID = requesttracking.NewID("run")
+		ctx = requesttracking.WithCorrelationID(ctx, correlationID)
+	}
+	client.mu.RLock()
+	scope := &ops.Scope{
+		Provider:     client.provider,
+		Defaults:     client.defaults,
+		Persona:      client.persona,
+		Interceptors: client.interceptors,
+		Scrubbers:    client.scrubbers,
+		Restorers:    client.restorers,
+	}
+	client.mu.RUnlock()
+	ctx = ops.WithScope(ctx, scope)
+	ctx, usage := ops.WithRunUsage(ctx)
+	ctx, meta := ops.WithResultMeta(ctx)
+	return &RunContext{Context: ctx, client: client, usage: usage, meta: meta, correlationID: correlationID}
+}
+
+// Client returns the client that started the run.
+func (run *RunContext) Client() *Client {
+	return run.client
+}
+
+// Defaults returns the client's default operation options, if any were set
+// with WithDefaultOptions.
+func (run *RunContext) Defaults() (OpOptions, bool) {
+	run.client.mu.RLock()
+	defer run.client.mu.RUnlock()
+	if run.client.defaults == nil {
+		return OpOptions{}, false
+	}
+	return *run.client.defaults, true
+}
+
+// CorrelationID returns the correlation ID shared by the run's operations.
+func (run *RunContext) CorrelationID() string {
+	return run.correlationID
+}
+
+// Usage returns the combined token usage of every LLM call made in the run.
+func (run *RunContext) Usage() TokenUsage {
+	_, usage, _ := run.usage.Totals()
+	return usage
+}
+
+// Cost returns the combined cost in USD of every LLM call made in the run.
+func (run *RunContext) Cost() float64 {
+	_, _, cost := run.usage.Totals()
+	return cost
+}
+
+// Calls returns the number of LLM calls made in the run.
+func (run *RunContext) Calls() int {
+	calls, _, _ := run.usage.Totals()
+	return calls
+}
+
+// ResultMeta returns the provider metadata (finish reason, model, system
+// fingerprint and safety flags) of every LLM call made in the run, in
+// completion order.
+func (run *RunContext) ResultMeta() []ResultMeta {
+	return run.meta.All()
+}
+
+// LastResultMeta returns the provider metadata of the run's most recent LLM
+// call, and false when the run has made none.
+func (run *RunContext) LastResultMeta() (ResultMeta, bool) {
+	return run.meta.Last()
+}
+
 // Global configuration
 var (
 	defaultClient *Client
@@ -294,6 +658,8 @@ func (client *Client) providerConfig(providerName string, override llm.ProviderC
 	cfg := llm.ProviderConfig{
 		APIKey:       resolveProviderAPIKey(providerName, client.apiKey),
 		BaseURL:      resolveProviderBaseURL(providerName),
+		OrgID:        client.organization,
+		ProjectID:    client.project,
 		Timeout:      client.timeout,
 		MaxRetries:   client.maxRetries,
 		RetryBackoff: client.retryBackoff,
@@ -309,6 +675,9 @@ func (client *Client) providerConfig(providerName string, override llm.ProviderC
 	if override.OrgID != "" {
 		cfg.OrgID = override.OrgID
 	}
+	if override.ProjectID != "" {
+		cfg.ProjectID = override.ProjectID
+	}
 	if override.Timeout > 0 {
 		cfg.Timeout = override.Timeout
 	}
@@ -2,10 +2,17 @@ package schemaflow
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/monstercameron/schemaflow/internal/llm"
+	"github.com/monstercameron/schemaflow/internal/ops"
 	"github.com/monstercameron/schemaflow/internal/requesttracking"
 )
 
@@ -76,6 +83,63 @@ func TestWithProviderInstance(t *testing.T) {
 	}
 }
 
+func TestWithOrganizationAndProjectSendOpenAIBillingHeaders(t *testing.T) {
+	previous := ops.DefaultProvider()
+	defer ops.SetDefaultProvider(previous)
+
+	var organization, project string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		organization = r.Header.Get("OpenAI-Organization")
+		project = r.Header.Get("OpenAI-Project")
+		w.Write([]byte(`{"output": [{"type": "message", "content": [{"type": "output_text", "text": "ok"}]}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("sk-test").
+		WithProviderConfig("openai", ProviderConfig{BaseURL: server.URL}).
+		WithOrganization("org-analytics").
+		WithProjectHeader("proj_reporting")
+
+	if _, err := client.Provider().Complete(context.Background(), llm.CompletionRequest{Model: "gpt-5-nano", UserPrompt: "hi"}); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if organization != "org-analytics" || project != "proj_reporting" {
+		t.Errorf("expected billing headers on the request, got organization %q, project %q", organization, project)
+	}
+	if ops.DefaultProvider() != client.Provider() {
+		t.Error("expected the rebuilt provider to replace the client's default provider")
+	}
+
+	// An explicit provider config wins over the client-wide setting
+	client.WithProviderConfig("openai", ProviderConfig{BaseURL: server.URL, OrgID: "org-override"})
+	if _, err := client.Provider().Complete(context.Background(), llm.CompletionRequest{Model: "gpt-5-nano", UserPrompt: "hi"}); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if organization != "org-override" || project != "proj_reporting" {
+		t.Errorf("expected the explicit organization to win, got organization %q, project %q", organization, project)
+	}
+}
+
+func TestClientCapabilities(t *testing.T) {
+	local := NewClient("").Capabilities()
+	if local.Streaming || local.JSONMode || local.Vision || local.Embeddings {
+		t.Errorf("expected the local provider to report no capabilities, got %+v", local)
+	}
+
+	openAI := NewClient("sk-test").Capabilities()
+	if !openAI.JSONMode {
+		t.Errorf("expected the OpenAI provider to report JSON mode, got %+v", openAI)
+	}
+	if openAI.Streaming {
+		t.Errorf("expected the OpenAI provider to report no streaming, got %+v", openAI)
+	}
+
+	stub := NewClient("").WithProviderInstance(&stubProvider{name: "stub"}).Capabilities()
+	if stub != (ProviderCapabilities{}) {
+		t.Errorf("expected a provider without a report to have no capabilities, got %+v", stub)
+	}
+}
+
 func TestRequestTrackingHelpers(t *testing.T) {
 	original := GetRequestTrackingConfig()
 	t.Cleanup(func() { ConfigureRequestTracking(original) })
@@ -123,3 +187,359 @@ func TestRequestTrackingHelpers(t *testing.T) {
 		t.Fatal("expected request tracking to be disabled")
 	}
 }
+
+func TestClientCloneIsIndependent(t *testing.T) {
+	base := NewClient("").
+		WithTimeout(10 * time.Second).
+		WithProviderInstance(&stubProvider{name: "base-provider"})
+
+	clone := base.Clone().
+		WithTimeout(time.Minute).
+		WithProviderInstance(&stubProvider{name: "clone-provider"})
+
+	if got := base.Provider().Name(); got != "base-provider" {
+		t.Fatalf("parent provider = %s, want base-provider", got)
+	}
+	if got := clone.Provider().Name(); got != "clone-provider" {
+		t.Fatalf("clone provider = %s, want clone-provider", got)
+	}
+	if base.timeout != 10*time.Second || clone.timeout != time.Minute {
+		t.Fatalf("timeouts = %v/%v, want 10s/1m", base.timeout, clone.timeout)
+	}
+
+	// Parent and clones can be configured and read concurrently
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			variant := base.Clone().WithRetries(i).WithProviderInstance(&stubProvider{name: "variant"})
+			if variant.Provider().Name() != "variant" || variant.maxRetries != i {
+				t.Errorf("variant %d not configured independently", i)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			base.WithRetryBackoff(time.Second)
+			_ = base.Provider().Name()
+		}()
+	}
+	wg.Wait()
+
+	if got := base.Provider().Name(); got != "base-provider" {
+		t.Fatalf("parent provider after concurrent clones = %s, want base-provider", got)
+	}
+}
+
+type countingProvider struct {
+	stubProvider
+	calls atomic.Int32
+}
+
+func (provider *countingProvider) Complete(context.Context, llm.CompletionRequest) (llm.CompletionResponse, error) {
+	provider.calls.Add(1)
+	return llm.CompletionResponse{Content: "A summary.", Provider: provider.name}, nil
+}
+
+func TestClientCloneRunsUseTheCloneProvider(t *testing.T) {
+	previous := ops.DefaultProvider()
+	defer ops.SetDefaultProvider(previous)
+
+	baseProvider := &countingProvider{stubProvider: stubProvider{name: "base"}}
+	base := NewClient("").WithProviderInstance(baseProvider)
+
+	clones := make([]*countingProvider, 8)
+	var wg sync.WaitGroup
+	for i := range clones {
+		clones[i] = &countingProvider{stubProvider: stubProvider{name: "clone"}}
+		wg.Add(1)
+		go func(provider *countingProvider) {
+			defer wg.Done()
+			run := base.Clone().WithProviderInstance(provider).NewRun(context.Background())
+			if _, err := SummarizeCtx(run, "A long text about clones.", NewSummarizeOptions()); err != nil {
+				t.Errorf("SummarizeCtx() error = %v", err)
+			}
+		}(clones[i])
+	}
+	wg.Wait()
+
+	if ops.DefaultProvider() != baseProvider {
+		t.Fatalf("process-wide provider = %v, want the base client's", ops.DefaultProvider())
+	}
+	for i, provider := range clones {
+		if got := provider.calls.Load(); got != 1 {
+			t.Errorf("clone %d provider calls = %d, want 1", i, got)
+		}
+	}
+	if got := baseProvider.calls.Load(); got != 0 {
+		t.Errorf("base provider calls = %d, want 0", got)
+	}
+}
+
+func TestClientInterceptorsApplyOnlyToTheirRuns(t *testing.T) {
+	previous := ops.DefaultProvider()
+	defer ops.SetDefaultProvider(previous)
+
+	counting := func(calls *atomic.Int32) Interceptor {
+		return func(next CompletionFunc) CompletionFunc {
+			return func(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+				calls.Add(1)
+				return next(ctx, req)
+			}
+		}
+	}
+	var baseCalls, cloneCalls atomic.Int32
+	base := NewClient("").
+		WithProviderInstance(&countingProvider{stubProvider: stubProvider{name: "base"}}).
+		WithInterceptor(counting(&baseCalls))
+	clone := base.Clone().WithInterceptor(counting(&cloneCalls))
+
+	summarize := func(ctx context.Context) {
+		t.Helper()
+		if _, err := SummarizeCtx(ctx, "A long text about interceptors.", NewSummarizeOptions()); err != nil {
+			t.Fatalf("SummarizeCtx() error = %v", err)
+		}
+	}
+	summarize(base.NewRun(context.Background()))
+	summarize(clone.NewRun(context.Background()))
+	summarize(context.Background())
+
+	// The clone inherits the base chain; the base never sees the clone's
+	// interceptor, and calls outside a run see neither
+	if got := baseCalls.Load(); got != 2 {
+		t.Errorf("base interceptor calls = %d, want 2", got)
+	}
+	if got := cloneCalls.Load(); got != 1 {
+		t.Errorf("clone interceptor calls = %d, want 1", got)
+	}
+}
+
+func TestClientScrubbersApplyOnlyToTheirRuns(t *testing.T) {
+	previous := ops.DefaultProvider()
+	defer ops.SetDefaultProvider(previous)
+
+	var baseScrubs, cloneScrubs atomic.Int32
+	scrubbing := func(calls *atomic.Int32) OutboundScrubber {
+		return func(req CompletionRequest) CompletionRequest {
+			calls.Add(1)
+			return req
+		}
+	}
+	base := NewClient("").
+		WithProviderInstance(&countingProvider{stubProvider: stubProvider{name: "base"}}).
+		WithOutboundScrubber(scrubbing(&baseScrubs))
+	clone := base.Clone().
+		WithOutboundScrubber(scrubbing(&cloneScrubs)).
+		WithInboundRestorer(func(resp CompletionResponse) CompletionResponse {
+			resp.Content = strings.Replace(resp.Content, "A summary", "A restored summary", 1)
+			return resp
+		})
+
+	summarize := func(ctx context.Context) string {
+		t.Helper()
+		summary, err := SummarizeCtx(ctx, "A long text about scrubbers.", NewSummarizeOptions())
+		if err != nil {
+			t.Fatalf("SummarizeCtx() error = %v", err)
+		}
+		return summary
+	}
+	if got := summarize(base.NewRun(context.Background())); strings.Contains(got, "restored") {
+		t.Errorf("base run summary = %q, want the clone's restorer not applied", got)
+	}
+	if got := summarize(clone.NewRun(context.Background())); !strings.Contains(got, "restored") {
+		t.Errorf("clone run summary = %q, want the clone's restorer applied", got)
+	}
+	if got := summarize(context.Background()); strings.Contains(got, "restored") {
+		t.Errorf("summary outside a run = %q, want no restorer applied", got)
+	}
+
+	// The clone inherits the base scrubbers; nothing leaks into the parent or
+	// into calls outside a run
+	if got := baseScrubs.Load(); got != 2 {
+		t.Errorf("base scrubber calls = %d, want 2", got)
+	}
+	if got := cloneScrubs.Load(); got != 1 {
+		t.Errorf("clone scrubber calls = %d, want 1", got)
+	}
+}
+
+func TestWithResponseCacheAnswersRepeatedRequestsWithDefaultSettings(t *testing.T) {
+	previous := ops.DefaultProvider()
+	defer ops.SetDefaultProvider(previous)
+
+	provider := &countingProvider{stubProvider: stubProvider{name: "cached"}}
+	client := NewClient("").WithProviderInstance(provider).WithResponseCache(NewMemoryCache(16))
+	run := client.NewRun(context.Background())
+
+	summarize := func(opts SummarizeOptions) {
+		t.Helper()
+		if _, err := SummarizeCtx(run, "A long text about caching.", opts); err != nil {
+			t.Fatalf("SummarizeCtx() error = %v", err)
+		}
+	}
+	summarize(NewSummarizeOptions())
+	summarize(NewSummarizeOptions())
+	if got := provider.calls.Load(); got != 1 {
+		t.Fatalf("provider calls = %d, want the repeat served from cache", got)
+	}
+
+	// Creative requests want a fresh answer each time
+	summarize(NewSummarizeOptions().WithMode(Creative))
+	summarize(NewSummarizeOptions().WithMode(Creative))
+	if got := provider.calls.Load(); got != 3 {
+		t.Errorf("provider calls = %d, want creative requests to bypass the cache", got)
+	}
+}
+
+type modelRecordingProvider struct {
+	stubProvider
+	models []string
+}
+
+func (provider *modelRecordingProvider) Complete(_ context.Context, req llm.CompletionRequest) (llm.CompletionResponse, error) {
+	provider.models = append(provider.models, req.Model)
+	return llm.CompletionResponse{Content: `{"name": "Ada"}`, Provider: provider.name}, nil
+}
+
+func TestClientDefaultOptionsPrecedence(t *testing.T) {
+	t.Setenv("SCHEMAFLOW_MODEL_SMART", "smart-model")
+	t.Setenv("SCHEMAFLOW_MODEL_FAST", "fast-model")
+	t.Setenv("SCHEMAFLOW_MODEL_QUICK", "quick-model")
+	previous := ops.DefaultProvider()
+	defer ops.SetDefaultProvider(previous)
+
+	provider := &modelRecordingProvider{stubProvider: stubProvider{name: "recording"}}
+	client := NewClient("").
+		WithProviderInstance(provider).
+		WithDefaultOptions(OpOptions{Mode: TransformMode, Intelligence: Smart})
+	run := client.NewRun(context.Background())
+
+	type person struct {
+		Name string `json:"name"`
+	}
+	calls := []ExtractOptions{
+		NewExtractOptions(),                         // default applies over the built-in Fast
+		NewExtractOptions().WithIntelligence(Quick), // per-call override
+		NewExtractOptions().WithIntelligence(Fast),  // overrides even when equal to the built-in value
+	}
+	for _, opts := range calls {
+		if _, err := ExtractCtx[person](run, "Ada", opts); err != nil {
+			t.Fatalf("ExtractCtx() error = %v", err)
+		}
+	}
+
+	// The defaults belong to the client: calls outside its runs and runs of
+	// a client without defaults keep the built-in Fast
+	if _, err := Extract[person]("Ada", NewExtractOptions()); err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	other := client.Clone().WithDefaultOptions(OpOptions{Mode: TransformMode, Intelligence: Quick}).NewRun(context.Background())
+	if _, err := ExtractCtx[person](other, "Ada", NewExtractOptions()); err != nil {
+		t.Fatalf("ExtractCtx() error = %v", err)
+	}
+	if _, err := ExtractCtx[person](run, "Ada", NewExtractOptions()); err != nil {
+		t.Fatalf("ExtractCtx() error = %v", err)
+	}
+
+	want := []string{"smart-model", "quick-model", "fast-model", "fast-model", "quick-model", "smart-model"}
+	if !reflect.DeepEqual(provider.models, want) {
+		t.Fatalf("models = %v, want %v", provider.models, want)
+	}
+}
+
+func TestWithPersonaPrefixesSystemPromptAndYieldsToCall(t *testing.T) {
+	previous := ops.DefaultProvider()
+	defer ops.SetDefaultProvider(previous)
+	client := NewClient("").WithProviderInstance(&stubProvider{name: "stub"})
+	client.WithPersona("You are a strict financial auditor.")
+	run := client.NewRun(context.Background())
+
+	type figure struct {
+		Revenue string `json:"revenue"`
+	}
+	render := func(ctx context.Context, opts ExtractOptions) string {
+		t.Helper()
+		_, err := ExtractCtx[figure](ctx, "Q3 revenue rose 12% on higher subscription sales.", opts.WithDryRun(true))
+		plan, ok := AsDryRun(err)
+		if !ok {
+			t.Fatalf("expected a dry-run result, got %v", err)
+		}
+		return plan.RenderedPrompt
+	}
+
+	rendered := render(run, NewExtractOptions())
+	if !strings.HasPrefix(rendered, "You are a strict financial auditor.\n\n") {
+		t.Errorf("expected the persona before the system prompt, got %q", rendered)
+	}
+	if !strings.Contains(rendered, "data extraction") {
+		t.Errorf("expected the operation's own instructions after the persona, got %q", rendered)
+	}
+
+	rendered = render(run, NewExtractOptions().WithPersona("You are a helpful assistant."))
+	if !strings.HasPrefix(rendered, "You are a helpful assistant.") || strings.Contains(rendered, "auditor") {
+		t.Errorf("expected the call's persona to replace the client's, got %q", rendered)
+	}
+
+	// The persona belongs to the client, not to calls outside its runs
+	if rendered = render(context.Background(), NewExtractOptions()); strings.Contains(rendered, "auditor") {
+		t.Errorf("expected no persona outside the client's runs, got %q", rendered)
+	}
+}
+
+type usageProvider struct {
+	stubProvider
+	correlationIDs []string
+}
+
+func (provider *usageProvider) Complete(_ context.Context, req llm.CompletionRequest) (llm.CompletionResponse, error) {
+	provider.correlationIDs = append(provider.correlationIDs, req.Metadata[llm.MetadataCorrelationID])
+	content := `{"name": "Ada"}`
+	if strings.Contains(req.SystemPrompt, "summarization") {
+		content = "Ada wrote the first program."
+	}
+	return llm.CompletionResponse{
+		Content:  content,
+		Provider: provider.name,
+		Usage:    TokenUsage{PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120},
+	}, nil
+}
+
+func TestRunContextAggregatesUsageAcrossOperations(t *testing.T) {
+	provider := &usageProvider{stubProvider: stubProvider{name: "usage"}}
+	client := NewClient("").WithProviderInstance(provider)
+
+	type person struct {
+		Name string `json:"name"`
+	}
+	run := client.NewRun(context.Background())
+	if _, err := ExtractCtx[person](run, "Ada Lovelace", NewExtractOptions()); err != nil {
+		t.Fatalf("ExtractCtx() error = %v", err)
+	}
+	if _, err := ExtractCtx[person](run, "Ada, a mathematician", NewExtractOptions()); err != nil {
+		t.Fatalf("ExtractCtx() error = %v", err)
+	}
+	if _, err := SummarizeCtx(run, "Ada Lovelace wrote the first published program for the Analytical Engine.", NewSummarizeOptions()); err != nil {
+		t.Fatalf("SummarizeCtx() error = %v", err)
+	}
+
+	want := TokenUsage{PromptTokens: 300, CompletionTokens: 60, TotalTokens: 360}
+	if run.Calls() != 3 || run.Usage() != want {
+		t.Fatalf("run calls = %d, usage = %+v; want 3 calls and %+v", run.Calls(), run.Usage(), want)
+	}
+	for _, id := range provider.correlationIDs {
+		if id != run.CorrelationID() {
+			t.Errorf("provider saw correlation ID %q, want %q", id, run.CorrelationID())
+		}
+	}
+	if run.Client() != client {
+		t.Error("Client() should return the client that started the run")
+	}
+
+	// Operations outside the run are not counted
+	if _, err := Extract[person]("Grace Hopper", NewExtractOptions()); err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if run.Calls() != 3 {
+		t.Errorf("run calls = %d after an operation outside the run, want 3", run.Calls())
+	}
+}
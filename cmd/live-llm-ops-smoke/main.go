@@ -548,7 +548,7 @@ func testNormalizeBatch() error {
 	if err != nil {
 		return err
 	}
-	return req(len(out) == 2, "normalizebatch wrong count")
+	return req(len(out.All()) == 2, "normalizebatch wrong count")
 }
 func testSemanticMatch() error {
 	opts := schemaflow.NewMatchOptions()
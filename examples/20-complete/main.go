@@ -75,7 +75,7 @@ func main() {
 	result2, err := schemaflow.Complete(partial2,
 		schemaflow.NewCompleteOptions().
 			WithMaxLength(150).
-			WithTemperature(0.3). // Lower temp for code
+			WithTemperature(0.15). // Lower temp for code
 			WithIntelligence(schemaflow.Fast))
 
 	if err != nil {
@@ -125,7 +125,7 @@ func main() {
 	result4, err := schemaflow.Complete(partial4,
 		schemaflow.NewCompleteOptions().
 			WithMaxLength(100).
-			WithTemperature(1.0). // Higher temp for creativity
+			WithTemperature(0.5). // Higher temp for creativity
 			WithIntelligence(schemaflow.Fast))
 
 	if err != nil {
@@ -200,7 +200,7 @@ func main() {
 	result6, err := schemaflow.CompleteField(product,
 		schemaflow.NewCompleteFieldOptions("Description").
 			WithMaxLength(150).
-			WithTemperature(0.5). // Moderate creativity for product copy
+			WithTemperature(0.25). // Moderate creativity for product copy
 			WithIntelligence(schemaflow.Fast))
 
 	if err != nil {
@@ -10,19 +10,36 @@ import (
 type (
 	CommonOptions = ops.CommonOptions
 
-	Mode  = types.Mode
-	Speed = types.Speed
+	Mode     = types.Mode
+	Speed    = types.Speed
+	JSONMode = types.JSONMode
+
+	TruncationPolicy = types.TruncationPolicy
 
 	ExtractOptions             = ops.ExtractOptions
+	GroundedResult[T any]      = ops.GroundedResult[T]
+	ConsistentResult[T any]    = ops.ConsistentResult[T]
+	FieldGrounding             = ops.FieldGrounding
+	Candidate                  = ops.Candidate
+	FieldAlternatives          = ops.FieldAlternatives
+	AlternativesResult[T any]  = ops.AlternativesResult[T]
+	ExtractSnapshot[T any]     = ops.ExtractSnapshot[T]
+	TransformResult[U any]     = ops.TransformResult[U]
 	TransformOptions           = ops.TransformOptions
 	GenerateOptions            = ops.GenerateOptions
+	JSONSchema                 = ops.JSONSchema
+	RelatedEntity              = ops.RelatedEntity
+	Reference                  = ops.Reference
 	ChooseOptions              = ops.ChooseOptions
 	FilterOptions              = ops.FilterOptions
 	SortOptions                = ops.SortOptions
+	SortKey                    = ops.SortKey
 	ClassifyOptions            = ops.ClassifyOptions
 	ClassifyResult[C any]      = ops.ClassifyResult[C]
 	ScoreOptions               = ops.ScoreOptions
 	ScoreResult                = ops.ScoreResult
+	Criterion                  = ops.Criterion
+	Rubric                     = ops.Rubric
 	CompareOptions             = ops.CompareOptions
 	CompareResult[T any]       = ops.CompareResult[T]
 	SimilarOptions             = ops.SimilarOptions
@@ -30,12 +47,15 @@ type (
 	InferOptions               = ops.InferOptions
 	DiffOptions                = ops.DiffOptions
 	DiffResult                 = ops.DiffResult
+	DiffSeriesResult           = ops.DiffSeriesResult
 	ExplainOptions             = ops.ExplainOptions
 	ExplainResult              = ops.ExplainResult
 	ParseOptions               = ops.ParseOptions
 	ParseResult[T any]         = ops.ParseResult[T]
 	SummarizeOptions           = ops.SummarizeOptions
 	SummarizeResult            = ops.SummarizeResult
+	StructuredSummary          = ops.StructuredSummary
+	SummarySection             = ops.SummarySection
 	RewriteOptions             = ops.RewriteOptions
 	RewriteResult              = ops.RewriteResult
 	TranslateOptions           = ops.TranslateOptions
@@ -62,6 +82,7 @@ type (
 	ClusterResult[T any]       = ops.ClusterResult[T]
 	RankOptions                = ops.RankOptions
 	RankResult[T any]          = ops.RankResult[T]
+	ShortlistScorer            = ops.ShortlistScorer
 	CompressOptions            = ops.CompressOptions
 	CompressResult[T any]      = ops.CompressResult[T]
 	DecomposeOptions           = ops.DecomposeOptions
@@ -70,6 +91,9 @@ type (
 	EnrichResult[T any]        = ops.EnrichResult[T]
 	NormalizeOptions           = ops.NormalizeOptions
 	NormalizeResult[T any]     = ops.NormalizeResult[T]
+	BatchResult[T any]         = ops.BatchResult[T]
+	BatchError                 = ops.BatchError
+	BatchOptions               = ops.BatchOptions
 	MatchOptions               = ops.MatchOptions
 	MatchPair[S any, T any]    = ops.MatchPair[S, T]
 	MatchResult[S any, T any]  = ops.MatchResult[S, T]
@@ -116,6 +140,14 @@ const (
 	Smart = types.Smart
 	Fast  = types.Fast
 	Quick = types.Quick
+
+	JSONModeAuto = types.JSONModeAuto
+	JSONModeOn   = types.JSONModeOn
+	JSONModeOff  = types.JSONModeOff
+
+	TruncationError = types.TruncationError
+	TruncateInput   = types.TruncateInput
+	SummarizeInput  = types.SummarizeInput
 )
 
 var (
@@ -127,6 +159,7 @@ var (
 	NewSortOptions          = ops.NewSortOptions
 	NewClassifyOptions      = ops.NewClassifyOptions
 	NewScoreOptions         = ops.NewScoreOptions
+	NewRubric               = ops.NewRubric
 	NewCompareOptions       = ops.NewCompareOptions
 	NewSimilarOptions       = ops.NewSimilarOptions
 	NewInferOptions         = ops.NewInferOptions
@@ -151,6 +184,7 @@ var (
 	NewDecomposeOptions     = ops.NewDecomposeOptions
 	NewEnrichOptions        = ops.NewEnrichOptions
 	NewNormalizeOptions     = ops.NewNormalizeOptions
+	NewBatchOptions         = ops.NewBatchOptions
 	NewMatchOptions         = ops.NewMatchOptions
 	NewCritiqueOptions      = ops.NewCritiqueOptions
 	NewSynthesizeOptions    = ops.NewSynthesizeOptions
@@ -162,14 +196,62 @@ func Extract[T any](input any, opts ExtractOptions) (T, error) {
 	return ops.Extract[T](input, opts)
 }
 
+func ExtractLike(input, example any, opts ExtractOptions) (any, error) {
+	return ops.ExtractLike(input, example, opts)
+}
+
+func MarshalResult(result any) ([]byte, error) {
+	return ops.MarshalResult(result)
+}
+
+func UnmarshalResult[R any](data []byte) (R, error) {
+	return ops.UnmarshalResult[R](data)
+}
+
+func ExtractConsistent[T any](input any, opts ExtractOptions) (ConsistentResult[T], error) {
+	return ops.ExtractConsistent[T](input, opts)
+}
+
+func ExtractGrounded[T any](input any, opts ExtractOptions) (GroundedResult[T], error) {
+	return ops.ExtractGrounded[T](input, opts)
+}
+
+func PIIFields(value any) []string {
+	return ops.PIIFields(value)
+}
+
+func RedactStruct[T any](value T) T {
+	return ops.RedactStruct(value)
+}
+
+func ExtractAlternatives[T any](input any, opts ExtractOptions) (AlternativesResult[T], error) {
+	return ops.ExtractAlternatives[T](input, opts)
+}
+
+func ExtractStream[T any](input any, opts ExtractOptions) (<-chan ExtractSnapshot[T], error) {
+	return ops.ExtractStream[T](input, opts)
+}
+
 func Transform[T any, U any](input T, opts TransformOptions) (U, error) {
 	return ops.Transform[T, U](input, opts)
 }
 
+func TransformWithMetadata[T any, U any](input T, opts TransformOptions) (TransformResult[U], error) {
+	return ops.TransformWithMetadata[T, U](input, opts)
+}
+
 func Generate[T any](prompt string, opts GenerateOptions) (T, error) {
 	return ops.Generate[T](prompt, opts)
 }
 
+func Entity[T any](name string, count int, into *[]T) RelatedEntity {
+	return ops.Entity(name, count, into)
+}
+
+func GenerateRelated(prompt string, entities []RelatedEntity, references []Reference, opts GenerateOptions) error {
+	return ops.GenerateRelated(prompt, entities, references, opts)
+}
+
 func Choose[T any](options []T, opts ChooseOptions) (T, error) {
 	return ops.Choose(options, opts)
 }
@@ -186,10 +268,18 @@ func Classify[T any, C any](input T, opts ClassifyOptions) (ClassifyResult[C], e
 	return ops.Classify[T, C](input, opts)
 }
 
+func RegisterEnum[C ~string](values ...C) {
+	ops.RegisterEnum(values...)
+}
+
 func Score[T any](input T, opts ScoreOptions) (ScoreResult, error) {
 	return ops.Score(input, opts)
 }
 
+func ScoreWithRubric[T any](item T, rubric Rubric, opts ScoreOptions) (ScoreResult, error) {
+	return ops.ScoreWithRubric(item, rubric, opts)
+}
+
 func Compare[T any](left, right T, opts CompareOptions) (CompareResult[T], error) {
 	return ops.Compare(left, right, opts)
 }
@@ -206,6 +296,10 @@ func Diff[T any](oldValue, newValue T, opts DiffOptions) (DiffResult, error) {
 	return ops.Diff(oldValue, newValue, opts)
 }
 
+func DiffSeries[T any](versions []T, opts DiffOptions) (DiffSeriesResult, error) {
+	return ops.DiffSeries(versions, opts)
+}
+
 func Explain(input any, opts ExplainOptions) (ExplainResult, error) {
 	return ops.Explain(input, opts)
 }
@@ -222,6 +316,10 @@ func SummarizeWithMetadata(input string, opts SummarizeOptions) (SummarizeResult
 	return ops.SummarizeWithMetadata(input, opts)
 }
 
+func SummarizeStructured[T any](input string, opts SummarizeOptions) (T, error) {
+	return ops.SummarizeStructured[T](input, opts)
+}
+
 func Rewrite(input string, opts RewriteOptions) (string, error) {
 	return ops.Rewrite(input, opts)
 }
@@ -318,10 +416,26 @@ func NormalizeText(input string, opts NormalizeOptions) (string, error) {
 	return ops.NormalizeText(input, opts)
 }
 
-func NormalizeBatch[T any](items []T, opts NormalizeOptions) ([]NormalizeResult[T], error) {
+func NormalizeBatch[T any](items []T, opts NormalizeOptions) (BatchResult[NormalizeResult[T]], error) {
 	return ops.NormalizeBatch[T](items, opts)
 }
 
+func MapOp[T any, U any](items []T, op func(T) (U, error), opts BatchOptions) (BatchResult[U], error) {
+	return ops.MapOp[T, U](items, op, opts)
+}
+
+func ClassifyEach[T any, C any](items []T, opts ClassifyOptions, batch BatchOptions) (BatchResult[ClassifyResult[C]], error) {
+	return ops.ClassifyEach[T, C](items, opts, batch)
+}
+
+func ScoreEach[T any](items []T, opts ScoreOptions, batch BatchOptions) (BatchResult[ScoreResult], error) {
+	return ops.ScoreEach[T](items, opts, batch)
+}
+
+func SummarizeEach(inputs []string, opts SummarizeOptions, batch BatchOptions) (BatchResult[string], error) {
+	return ops.SummarizeEach(inputs, opts, batch)
+}
+
 func SemanticMatch[S any, T any](sources []S, targets []T, opts MatchOptions) (MatchResult[S, T], error) {
 	return ops.SemanticMatch[S, T](sources, targets, opts)
 }
@@ -2,6 +2,7 @@ package fluent
 
 import (
 	"context"
+	"time"
 )
 
 // ExtractRequest is a fluent builder for Extract.
@@ -70,20 +71,117 @@ func (r ExtractRequest[T]) CorrelationID(correlationID string) ExtractRequest[T]
 	return r
 }
 
+func (r ExtractRequest[T]) PreserveLanguage(enabled bool) ExtractRequest[T] {
+	r.opts = r.opts.WithPreserveLanguage(enabled)
+	return r
+}
+
+func (r ExtractRequest[T]) InjectionGuard(enabled bool) ExtractRequest[T] {
+	r.opts = r.opts.WithInjectionGuard(enabled)
+	return r
+}
+
+func (r ExtractRequest[T]) ReasoningEffort(effort string) ExtractRequest[T] {
+	r.opts = r.opts.WithReasoningEffort(effort)
+	return r
+}
+
+func (r ExtractRequest[T]) JSONMode(mode JSONMode) ExtractRequest[T] {
+	r.opts = r.opts.WithJSONMode(mode)
+	return r
+}
+
+func (r ExtractRequest[T]) Persona(persona string) ExtractRequest[T] {
+	r.opts = r.opts.WithPersona(persona)
+	return r
+}
+
+func (r ExtractRequest[T]) MaxInputBytes(n int) ExtractRequest[T] {
+	r.opts = r.opts.WithMaxInputBytes(n)
+	return r
+}
+
+func (r ExtractRequest[T]) MaxPromptTokens(n int) ExtractRequest[T] {
+	r.opts = r.opts.WithMaxPromptTokens(n)
+	return r
+}
+
+func (r ExtractRequest[T]) TruncationPolicy(policy TruncationPolicy) ExtractRequest[T] {
+	r.opts = r.opts.WithTruncationPolicy(policy)
+	return r
+}
+
 func (r ExtractRequest[T]) Partial(allow bool) ExtractRequest[T] {
 	r.opts = r.opts.WithAllowPartial(allow)
 	return r
 }
 
+func (r ExtractRequest[T]) FieldSteering(steering map[string]string) ExtractRequest[T] {
+	r.opts = r.opts.WithFieldSteering(steering)
+	return r
+}
+
 func (r ExtractRequest[T]) SchemaHints(hints map[string]string) ExtractRequest[T] {
 	r.opts = r.opts.WithSchemaHints(hints)
 	return r
 }
 
+func (r ExtractRequest[T]) Timezone(loc *time.Location) ExtractRequest[T] {
+	r.opts = r.opts.WithTimezone(loc)
+	return r
+}
+
+func (r ExtractRequest[T]) DateLayouts(layouts ...string) ExtractRequest[T] {
+	r.opts = r.opts.WithDateLayouts(layouts...)
+	return r
+}
+
+func (r ExtractRequest[T]) Locale(locale string) ExtractRequest[T] {
+	r.opts = r.opts.WithLocale(locale)
+	return r
+}
+
+func (r ExtractRequest[T]) ConsistencyRules(rules ...string) ExtractRequest[T] {
+	r.opts = r.opts.WithConsistencyRules(append([]string(nil), rules...))
+	return r
+}
+
+func (r ExtractRequest[T]) Grounded(enabled bool) ExtractRequest[T] {
+	r.opts = r.opts.WithGroundedExtraction(enabled)
+	return r
+}
+
+func (r ExtractRequest[T]) Alternatives(enabled bool) ExtractRequest[T] {
+	r.opts = r.opts.WithAlternatives(enabled)
+	return r
+}
+
+func (r ExtractRequest[T]) ExpectedCount(min, max int) ExtractRequest[T] {
+	r.opts = r.opts.WithExpectedCount(min, max)
+	return r
+}
+
+func (r ExtractRequest[T]) FieldEscalation(threshold float64, intelligence Speed) ExtractRequest[T] {
+	r.opts = r.opts.WithFieldEscalation(threshold, intelligence)
+	return r
+}
+
 func (r ExtractRequest[T]) Run() (T, error) {
 	return Extract[T](r.input, r.opts)
 }
 
+func (r ExtractRequest[T]) RunGrounded() (GroundedResult[T], error) {
+	return ExtractGrounded[T](r.input, r.opts)
+}
+
+func (r ExtractRequest[T]) RunAlternatives() (AlternativesResult[T], error) {
+	return ExtractAlternatives[T](r.input, r.opts)
+}
+
+func (r ExtractRequest[T]) RunStream() (<-chan ExtractSnapshot[T], error) {
+	return ExtractStream[T](r.input, r.opts)
+}
+
 // TransformRequest is a fluent builder for Transform.
 type TransformRequest[T any, U any] struct {
 	input T
@@ -140,6 +238,11 @@ func (r TransformRequest[T, U]) Merge(strategy string) TransformRequest[T, U] {
 	return r
 }
 
+func (r TransformRequest[T, U]) CopyMatchingFields(enabled bool) TransformRequest[T, U] {
+	r.opts = r.opts.WithCopyMatchingFields(enabled)
+	return r
+}
+
 func (r TransformRequest[T, U]) Context(ctx context.Context) TransformRequest[T, U] {
 	r.opts.CommonOptions = r.opts.CommonOptions.WithContext(ctx)
 	return r
@@ -159,6 +262,10 @@ func (r TransformRequest[T, U]) Run() (U, error) {
 	return Transform[T, U](r.input, r.opts)
 }
 
+func (r TransformRequest[T, U]) RunDetailed() (TransformResult[U], error) {
+	return TransformWithMetadata[T, U](r.input, r.opts)
+}
+
 // GenerateRequest is a fluent builder for Generate.
 type GenerateRequest[T any] struct {
 	prompt string
@@ -220,6 +327,16 @@ func (r GenerateRequest[T]) Style(style string) GenerateRequest[T] {
 	return r
 }
 
+func (r GenerateRequest[T]) AllowedValues(allowed map[string][]string) GenerateRequest[T] {
+	r.opts = r.opts.WithAllowedValues(allowed)
+	return r
+}
+
+func (r GenerateRequest[T]) SchemaConstraints(schema JSONSchema) GenerateRequest[T] {
+	r.opts = r.opts.WithSchemaConstraints(schema)
+	return r
+}
+
 func (r GenerateRequest[T]) Context(ctx context.Context) GenerateRequest[T] {
 	r.opts.CommonOptions = r.opts.CommonOptions.WithContext(ctx)
 	return r
@@ -310,6 +427,16 @@ func (r ChooseRequest[T]) Reasoning(enabled bool) ChooseRequest[T] {
 	return r
 }
 
+func (r ChooseRequest[T]) Shortlist(embeddingTopK int) ChooseRequest[T] {
+	r.opts = r.opts.WithShortlistStage(embeddingTopK)
+	return r
+}
+
+// Prefilter is Shortlist under its earlier name.
+func (r ChooseRequest[T]) Prefilter(topK int) ChooseRequest[T] {
+	return r.Shortlist(topK)
+}
+
 func (r ChooseRequest[T]) Run() (T, error) {
 	return Choose[T](r.options, r.opts)
 }
@@ -385,6 +512,16 @@ func (r FilterRequest[T]) MinConfidence(confidence float64) FilterRequest[T] {
 	return r
 }
 
+func (r FilterRequest[T]) Shortlist(embeddingTopK int) FilterRequest[T] {
+	r.opts = r.opts.WithShortlistStage(embeddingTopK)
+	return r
+}
+
+// Prefilter is Shortlist under its earlier name.
+func (r FilterRequest[T]) Prefilter(topK int) FilterRequest[T] {
+	return r.Shortlist(topK)
+}
+
 func (r FilterRequest[T]) Run() ([]T, error) {
 	return Filter[T](r.items, r.opts)
 }
@@ -415,6 +552,21 @@ func (r SortRequest[T]) By(criteria string) SortRequest[T] {
 	return r
 }
 
+func (r SortRequest[T]) Keys(keys ...SortKey) SortRequest[T] {
+	r.opts = r.opts.WithKeys(keys)
+	return r
+}
+
+func (r SortRequest[T]) Shortlist(embeddingTopK int) SortRequest[T] {
+	r.opts = r.opts.WithShortlistStage(embeddingTopK)
+	return r
+}
+
+// Prefilter is Shortlist under its earlier name.
+func (r SortRequest[T]) Prefilter(topK int) SortRequest[T] {
+	return r.Shortlist(topK)
+}
+
 func (r SortRequest[T]) Steer(steering string) SortRequest[T] {
 	r.opts = r.opts.WithSteering(steering)
 	return r
@@ -515,6 +515,12 @@ func (r AuditRequest[T]) Categories(categories ...string) AuditRequest[T] {
 	return r.WithOptions(opts)
 }
 
+func (r AuditRequest[T]) HashReport(enabled bool) AuditRequest[T] {
+	opts := r.opts
+	opts.HashReport = enabled
+	return r.WithOptions(opts)
+}
+
 func (r AuditRequest[T]) Run() (AuditResult[T], error) {
 	return Audit[T](r.input, r.opts)
 }
@@ -41,6 +41,14 @@ func (r ClassifyRequest[T, C]) MultiLabel(allow bool) ClassifyRequest[T, C] {
 	return r.WithOptions(opts)
 }
 
+func (r ClassifyRequest[T, C]) AllowOther(allow bool) ClassifyRequest[T, C] {
+	return r.WithOptions(r.opts.WithAllowOther(allow))
+}
+
+func (r ClassifyRequest[T, C]) ReviewBand(low, high float64) ClassifyRequest[T, C] {
+	return r.WithOptions(r.opts.WithReviewBand(low, high))
+}
+
 func (r ClassifyRequest[T, C]) Run() (ClassifyResult[C], error) {
 	return Classify[T, C](r.input, r.opts)
 }
@@ -169,6 +177,10 @@ func (r SimilarRequest[T]) Aspects(aspects ...string) SimilarRequest[T] {
 	return r.WithOptions(opts)
 }
 
+func (r SimilarRequest[T]) AspectWeights(weights map[string]float64) SimilarRequest[T] {
+	return r.WithOptions(r.opts.WithAspectWeights(weights))
+}
+
 func (r SimilarRequest[T]) Threshold(threshold float64) SimilarRequest[T] {
 	opts := r.opts
 	opts.SimilarityThreshold = threshold
@@ -319,6 +331,10 @@ func (r ParseRequest[T]) FormatHints(hints ...string) ParseRequest[T] {
 	return r.WithOptions(opts)
 }
 
+func (r ParseRequest[T]) HeaderAliases(aliases map[string]string) ParseRequest[T] {
+	return r.WithOptions(r.opts.WithHeaderAliases(aliases))
+}
+
 func (r ParseRequest[T]) Run() (ParseResult[T], error) {
 	return Parse[T](r.input, r.opts)
 }
@@ -356,6 +372,10 @@ func (r SummarizeRequest) MaxLength(max int) SummarizeRequest {
 	return r.WithOptions(opts)
 }
 
+func (r SummarizeRequest) Query(query string) SummarizeRequest {
+	return r.WithOptions(r.opts.WithQuery(query))
+}
+
 func (r SummarizeRequest) Run() (string, error) {
 	return Summarize(r.input, r.opts)
 }
@@ -444,6 +464,14 @@ func (r TranslateRequest) To(language string) TranslateRequest {
 	return r.WithOptions(opts)
 }
 
+func (r TranslateRequest) Formality(formality string) TranslateRequest {
+	return r.WithOptions(r.opts.WithFormality(formality))
+}
+
+func (r TranslateRequest) Dialect(dialect string) TranslateRequest {
+	return r.WithOptions(r.opts.WithDialect(dialect))
+}
+
 func (r TranslateRequest) Run() (string, error) {
 	return Translate(r.input, r.opts)
 }
@@ -618,6 +646,12 @@ func (r RedactTextRequest) Categories(categories ...string) RedactTextRequest {
 	return r.WithOptions(opts)
 }
 
+func (r RedactTextRequest) NeverRedact(patterns ...string) RedactTextRequest {
+	opts := r.opts
+	opts.NeverRedact = append([]string(nil), patterns...)
+	return r.WithOptions(opts)
+}
+
 func (r RedactTextRequest) Run() (RedactLLMResult, error) {
 	return RedactLLM(r.input, r.opts)
 }
@@ -2,6 +2,7 @@ package fluent
 
 import (
 	"context"
+	"time"
 
 	"github.com/monstercameron/schemaflow/internal/types"
 )
@@ -68,6 +69,96 @@ func (r commonRequest[Self, Opt]) Quick() Self {
 	return r.Intelligence(Quick)
 }
 
+func (r commonRequest[Self, Opt]) Temperature(temperature float64) Self {
+	return r.lift(r.mutate(r.opts, func(common CommonOptions) CommonOptions {
+		return common.WithTemperature(temperature)
+	}))
+}
+
+func (r commonRequest[Self, Opt]) RawTemperature(temperature float64) Self {
+	return r.lift(r.mutate(r.opts, func(common CommonOptions) CommonOptions {
+		return common.WithRawTemperature(temperature)
+	}))
+}
+
+func (r commonRequest[Self, Opt]) DryRun(enabled bool) Self {
+	return r.lift(r.mutate(r.opts, func(common CommonOptions) CommonOptions {
+		return common.WithDryRun(enabled)
+	}))
+}
+
+func (r commonRequest[Self, Opt]) PreserveLanguage(enabled bool) Self {
+	return r.lift(r.mutate(r.opts, func(common CommonOptions) CommonOptions {
+		return common.WithPreserveLanguage(enabled)
+	}))
+}
+
+func (r commonRequest[Self, Opt]) InjectionGuard(enabled bool) Self {
+	return r.lift(r.mutate(r.opts, func(common CommonOptions) CommonOptions {
+		return common.WithInjectionGuard(enabled)
+	}))
+}
+
+func (r commonRequest[Self, Opt]) SemanticCache(threshold float64) Self {
+	return r.lift(r.mutate(r.opts, func(common CommonOptions) CommonOptions {
+		return common.WithSemanticCache(threshold)
+	}))
+}
+
+func (r commonRequest[Self, Opt]) ReasoningEffort(effort string) Self {
+	return r.lift(r.mutate(r.opts, func(common CommonOptions) CommonOptions {
+		return common.WithReasoningEffort(effort)
+	}))
+}
+
+func (r commonRequest[Self, Opt]) JSONMode(mode JSONMode) Self {
+	return r.lift(r.mutate(r.opts, func(common CommonOptions) CommonOptions {
+		return common.WithJSONMode(mode)
+	}))
+}
+
+func (r commonRequest[Self, Opt]) Persona(persona string) Self {
+	return r.lift(r.mutate(r.opts, func(common CommonOptions) CommonOptions {
+		return common.WithPersona(persona)
+	}))
+}
+
+func (r commonRequest[Self, Opt]) MaxInputBytes(n int) Self {
+	return r.lift(r.mutate(r.opts, func(common CommonOptions) CommonOptions {
+		return common.WithMaxInputBytes(n)
+	}))
+}
+
+func (r commonRequest[Self, Opt]) MaxPromptTokens(n int) Self {
+	return r.lift(r.mutate(r.opts, func(common CommonOptions) CommonOptions {
+		return common.WithMaxPromptTokens(n)
+	}))
+}
+
+func (r commonRequest[Self, Opt]) TruncationPolicy(policy TruncationPolicy) Self {
+	return r.lift(r.mutate(r.opts, func(common CommonOptions) CommonOptions {
+		return common.WithTruncationPolicy(policy)
+	}))
+}
+
+func (r commonRequest[Self, Opt]) AutoChunk(enabled bool) Self {
+	return r.lift(r.mutate(r.opts, func(common CommonOptions) CommonOptions {
+		return common.WithAutoChunk(enabled)
+	}))
+}
+
+func (r commonRequest[Self, Opt]) ChunkCache(enabled bool) Self {
+	return r.lift(r.mutate(r.opts, func(common CommonOptions) CommonOptions {
+		return common.WithChunkCache(enabled)
+	}))
+}
+
+func (r commonRequest[Self, Opt]) Deadline(d time.Duration) Self {
+	return r.lift(r.mutate(r.opts, func(common CommonOptions) CommonOptions {
+		return common.WithDeadline(d)
+	}))
+}
+
 func (r commonRequest[Self, Opt]) Context(ctx context.Context) Self {
 	return r.lift(r.mutate(r.opts, func(common CommonOptions) CommonOptions {
 		return common.WithContext(ctx)
@@ -152,6 +243,92 @@ func (r opRequest[Self, Opt]) Quick() Self {
 	return r.Intelligence(Quick)
 }
 
+func (r opRequest[Self, Opt]) Temperature(temperature float64) Self {
+	return r.lift(r.mutate(r.opts, func(op types.OpOptions) types.OpOptions {
+		op.Temperature = &temperature
+		op.RawTemperature = false
+		return op
+	}))
+}
+
+func (r opRequest[Self, Opt]) RawTemperature(temperature float64) Self {
+	return r.lift(r.mutate(r.opts, func(op types.OpOptions) types.OpOptions {
+		op.Temperature = &temperature
+		op.RawTemperature = true
+		return op
+	}))
+}
+
+func (r opRequest[Self, Opt]) DryRun(enabled bool) Self {
+	return r.lift(r.mutate(r.opts, func(op types.OpOptions) types.OpOptions {
+		op.DryRun = enabled
+		return op
+	}))
+}
+
+func (r opRequest[Self, Opt]) PreserveLanguage(enabled bool) Self {
+	return r.lift(r.mutate(r.opts, func(op types.OpOptions) types.OpOptions {
+		op.PreserveLanguage = enabled
+		return op
+	}))
+}
+
+func (r opRequest[Self, Opt]) InjectionGuard(enabled bool) Self {
+	return r.lift(r.mutate(r.opts, func(op types.OpOptions) types.OpOptions {
+		op.InjectionGuard = enabled
+		return op
+	}))
+}
+
+func (r opRequest[Self, Opt]) SemanticCache(threshold float64) Self {
+	return r.lift(r.mutate(r.opts, func(op types.OpOptions) types.OpOptions {
+		op.SemanticCacheThreshold = threshold
+		return op
+	}))
+}
+
+func (r opRequest[Self, Opt]) ReasoningEffort(effort string) Self {
+	return r.lift(r.mutate(r.opts, func(op types.OpOptions) types.OpOptions {
+		op.ReasoningEffort = effort
+		return op
+	}))
+}
+
+func (r opRequest[Self, Opt]) JSONMode(mode JSONMode) Self {
+	return r.lift(r.mutate(r.opts, func(op types.OpOptions) types.OpOptions {
+		op.JSONMode = mode
+		return op
+	}))
+}
+
+func (r opRequest[Self, Opt]) Persona(persona string) Self {
+	return r.lift(r.mutate(r.opts, func(op types.OpOptions) types.OpOptions {
+		op.Persona = persona
+		return op
+	}))
+}
+
+func (r opRequest[Self, Opt]) MaxInputBytes(n int) Self {
+	return r.lift(r.mutate(r.opts, func(op types.OpOptions) types.OpOptions {
+		op.MaxInputBytes = n
+		return op
+	}))
+}
+
+func (r opRequest[Self, Opt]) MaxPromptTokens(n int) Self {
+	return r.lift(r.mutate(r.opts, func(op types.OpOptions) types.OpOptions {
+		op.MaxPromptTokens = n
+		return op
+	}))
+}
+
+func (r opRequest[Self, Opt]) TruncationPolicy(policy TruncationPolicy) Self {
+	return r.lift(r.mutate(r.opts, func(op types.OpOptions) types.OpOptions {
+		op.TruncationPolicy = policy
+		return op
+	}))
+}
+
 func (r opRequest[Self, Opt]) Context(ctx context.Context) Self {
 	return r.lift(r.mutate(r.opts, func(op types.OpOptions) types.OpOptions {
 		op.Context = ctx
@@ -76,6 +76,14 @@ func (r ClusterRequest[T]) Clusters(n int) ClusterRequest[T] {
 	return r.WithOptions(opts)
 }
 
+func (r ClusterRequest[T]) KRange(min, max int) ClusterRequest[T] {
+	return r.WithOptions(r.opts.WithKRange(min, max))
+}
+
+func (r ClusterRequest[T]) Hierarchy(levels int) ClusterRequest[T] {
+	return r.WithOptions(r.opts.WithHierarchy(levels))
+}
+
 func (r ClusterRequest[T]) Run() (ClusterResult[T], error) {
 	return Cluster[T](r.items, r.opts)
 }
@@ -125,6 +133,16 @@ func (r RankRequest[T]) MinScore(score float64) RankRequest[T] {
 	return r.WithOptions(opts)
 }
 
+func (r RankRequest[T]) Shortlist(embeddingTopK int) RankRequest[T] {
+	opts := r.opts
+	opts.ShortlistTopK = embeddingTopK
+	return r.WithOptions(opts)
+}
+
+func (r RankRequest[T]) Diversity(lambda float64) RankRequest[T] {
+	return r.WithOptions(r.opts.WithDiversity(lambda))
+}
+
 func (r RankRequest[T]) Run() (RankResult[T], error) {
 	return Rank[T](r.items, r.opts)
 }
@@ -404,7 +422,7 @@ func NormalizingBatch[T any](items []T) NormalizeBatchRequest[T] {
 	return newNormalizeBatchRequest(items, NewNormalizeOptions())
 }
 
-func (r NormalizeBatchRequest[T]) Run() ([]NormalizeResult[T], error) {
+func (r NormalizeBatchRequest[T]) Run() (BatchResult[NormalizeResult[T]], error) {
 	return NormalizeBatch[T](r.items, r.opts)
 }
 
@@ -0,0 +1,130 @@
+package llm
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+	"sync"
+
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+// Cache stores provider responses under request keys. Implementations must
+// be safe for concurrent use; a failed Set only costs a later cache miss.
+type Cache interface {
+	// Get returns the value stored under key and whether it was found
+	Get(key string) ([]byte, bool)
+
+	// Set stores value under key, replacing any previous value
+	Set(key string, value []byte)
+}
+
+// MemoryCache is an in-process Cache holding a bounded number of entries;
+// the least recently used entry is evicted first
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List
+	entries    map[string]*list.Element
+}
+
+type memoryCacheEntry struct {
+	key   string
+	value []byte
+}
+
+// NewMemoryCache creates a MemoryCache holding up to maxEntries entries (0
+// means unlimited)
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns the value stored under key
+func (cache *MemoryCache) Get(key string) ([]byte, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	element, ok := cache.entries[key]
+	if !ok {
+		return nil, false
+	}
+	cache.order.MoveToFront(element)
+	return element.Value.(*memoryCacheEntry).value, true
+}
+
+// Set stores value under key, evicting the least recently used entry when full
+func (cache *MemoryCache) Set(key string, value []byte) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if element, ok := cache.entries[key]; ok {
+		element.Value.(*memoryCacheEntry).value = value
+		cache.order.MoveToFront(element)
+		return
+	}
+	cache.entries[key] = cache.order.PushFront(&memoryCacheEntry{key: key, value: value})
+	if cache.maxEntries > 0 && cache.order.Len() > cache.maxEntries {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*memoryCacheEntry).key)
+	}
+}
+
+// untrustedFenceID matches the per-call ID of the markers operations put
+// around untrusted input
+var untrustedFenceID = regexp.MustCompile(`(<<<(?:END_)?UNTRUSTED_DATA )[^>]*>>>`)
+
+// CacheKey identifies a request by everything that shapes its response:
+// model, prompts, sampling and format. Metadata, headers and the per-call IDs
+// of untrusted-input markers are not part of the key, so requests differing
+// only in those share an entry.
+func CacheKey(req CompletionRequest) string {
+	req.SystemPrompt = untrustedFenceID.ReplaceAllString(req.SystemPrompt, "${1}>>>")
+	req.UserPrompt = untrustedFenceID.ReplaceAllString(req.UserPrompt, "${1}>>>")
+	data, _ := json.Marshal(struct {
+		Model           string  `json:"model"`
+		SystemPrompt    string  `json:"system"`
+		UserPrompt      string  `json:"user"`
+		Temperature     float64 `json:"temperature"`
+		MaxTokens       int     `json:"max_tokens"`
+		ResponseFormat  string  `json:"format"`
+		ReasoningEffort string  `json:"reasoning_effort"`
+	}{req.Model, req.SystemPrompt, req.UserPrompt, req.Temperature, req.MaxTokens, req.ResponseFormat, req.ReasoningEffort})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// CacheInterceptor answers repeated requests from cache instead of the
+// provider. Only deterministic requests (marked Deterministic, or sent at
+// temperature 0) are cached, and only complete responses are stored; a cached
+// response reports no token usage, since it cost nothing.
+func CacheInterceptor(cache Cache) Interceptor {
+	return func(next CompletionFunc) CompletionFunc {
+		return func(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+			if !req.Deterministic && req.Temperature > 0 {
+				return next(ctx, req)
+			}
+			key := CacheKey(req)
+			if data, ok := cache.Get(key); ok {
+				var cached CompletionResponse
+				if json.Unmarshal(data, &cached) == nil {
+					cached.Usage = types.TokenUsage{}
+					return cached, nil
+				}
+			}
+
+			resp, err := next(ctx, req)
+			if err == nil && resp.FinishReason != FinishReasonLength {
+				if data, marshalErr := json.Marshal(resp); marshalErr == nil {
+					cache.Set(key, data)
+				}
+			}
+			return resp, err
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package llm
+
+// ProviderCapabilities describes optional features of a provider so callers
+// can branch on them instead of attempting a call and handling the failure
+type ProviderCapabilities struct {
+	// Vision is true when the provider's API accepts image inputs
+	Vision bool
+
+	// Streaming is true when the provider implements StreamingProvider
+	Streaming bool
+
+	// Embeddings is true when the provider's API offers an embeddings endpoint
+	Embeddings bool
+
+	// JSONMode is true when JSON responses are enforced by the API rather
+	// than requested through the prompt alone
+	JSONMode bool
+
+	// Reasoning is true when the provider forwards a reasoning effort to its
+	// reasoning models
+	Reasoning bool
+}
+
+// CapabilityReporter is implemented by providers that describe their features
+type CapabilityReporter interface {
+	Capabilities() ProviderCapabilities
+}
+
+// CapabilitiesOf returns the capabilities a provider reports. Providers that
+// do not implement CapabilityReporter report only what their type reveals.
+func CapabilitiesOf(provider Provider) ProviderCapabilities {
+	if provider == nil {
+		return ProviderCapabilities{}
+	}
+	if reporter, ok := provider.(CapabilityReporter); ok {
+		return reporter.Capabilities()
+	}
+	_, streaming := provider.(StreamingProvider)
+	return ProviderCapabilities{Streaming: streaming}
+}
+
+// compatibleCapabilities lists the API features of OpenAI-compatible vendors;
+// all of them receive response_format for JSON requests
+var compatibleCapabilities = map[string]ProviderCapabilities{
+	"openrouter": {Vision: true, JSONMode: true},
+	"cerebras":   {JSONMode: true},
+	"deepseek":   {JSONMode: true},
+	"qwen":       {Vision: true, Embeddings: true, JSONMode: true},
+	"zai":        {Vision: true, Embeddings: true, JSONMode: true},
+}
+
+// Capabilities reports the OpenAI Responses API features used by the provider
+func (provider *OpenAIProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{Vision: true, Embeddings: true, JSONMode: true, Reasoning: true}
+}
+
+// Capabilities reports the Anthropic Messages API features; JSON output is
+// requested through the prompt only
+func (provider *AnthropicProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{Vision: true}
+}
+
+// Capabilities reports the features of the provider's vendor
+func (provider *OpenAICompatibleProvider) Capabilities() ProviderCapabilities {
+	if capabilities, ok := compatibleCapabilities[provider.name]; ok {
+		return capabilities
+	}
+	return ProviderCapabilities{JSONMode: true}
+}
+
+// Capabilities reports streaming only when a stream chunk size is configured
+func (provider *LocalProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{Streaming: provider.streamChunkSize > 0}
+}
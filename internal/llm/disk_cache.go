@@ -0,0 +1,163 @@
+package llm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// diskCacheMagic starts every entry file; a checksum of the value follows it
+var diskCacheMagic = []byte("sfcache1")
+
+const diskCacheExt = ".entry"
+
+// DiskCache is a Cache persisted as one file per entry under a directory, so
+// entries survive restarts and can be shared by processes on the same
+// machine. When the entries outgrow maxBytes, the least recently used are
+// removed. Entries that fail their checksum, such as files truncated by a
+// crash, read as misses and are deleted.
+type DiskCache struct {
+	mu       sync.Mutex
+	dir      string
+	maxBytes int64
+}
+
+// NewDiskCache opens (creating if needed) a disk cache in dir holding up to
+// maxBytes of entries (0 means unlimited)
+func NewDiskCache(dir string, maxBytes int64) (*DiskCache, error) {
+	if maxBytes < 0 {
+		return nil, fmt.Errorf("disk cache size cannot be negative, got %d", maxBytes)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &DiskCache{dir: dir, maxBytes: maxBytes}, nil
+}
+
+// entryPath names the file of key; keys are hashed so any string is safe
+func (cache *DiskCache) entryPath(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(cache.dir, hex.EncodeToString(sum[:])+diskCacheExt)
+}
+
+// Get returns the value stored under key, treating unreadable or corrupt
+// entries as misses
+func (cache *DiskCache) Get(key string) ([]byte, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	path := cache.entryPath(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	value, ok := decodeDiskEntry(data)
+	if !ok {
+		os.Remove(path)
+		return nil, false
+	}
+	// The modification time orders entries for eviction
+	now := time.Now()
+	os.Chtimes(path, now, now)
+	return value, true
+}
+
+// Set stores value under key, then evicts the least recently used entries
+// while the cache exceeds its size limit. Values larger than the limit are
+// not stored.
+func (cache *DiskCache) Set(key string, value []byte) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	data := encodeDiskEntry(value)
+	if cache.maxBytes > 0 && int64(len(data)) > cache.maxBytes {
+		return
+	}
+
+	// Write to a temporary file and rename so readers never see a partial entry
+	path := cache.entryPath(key)
+	tmp, err := os.CreateTemp(cache.dir, "tmp-*")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil || os.Rename(tmp.Name(), path) != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+
+	if cache.maxBytes > 0 {
+		cache.evict(path)
+	}
+}
+
+// evict removes the least recently used entries until the cache fits its
+// limit, keeping the entry just written at keep
+func (cache *DiskCache) evict(keep string) {
+	dirEntries, err := os.ReadDir(cache.dir)
+	if err != nil {
+		return
+	}
+	type entryFile struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []entryFile
+	var total int64
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() || !strings.HasSuffix(dirEntry.Name(), diskCacheExt) {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, entryFile{filepath.Join(cache.dir, dirEntry.Name()), info.Size(), info.ModTime()})
+		total += info.Size()
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for _, file := range files {
+		if total <= cache.maxBytes {
+			return
+		}
+		if file.path == keep {
+			continue
+		}
+		if os.Remove(file.path) == nil {
+			total -= file.size
+		}
+	}
+}
+
+// encodeDiskEntry prefixes value with the magic header and its checksum
+func encodeDiskEntry(value []byte) []byte {
+	sum := sha256.Sum256(value)
+	data := make([]byte, 0, len(diskCacheMagic)+len(sum)+len(value))
+	data = append(data, diskCacheMagic...)
+	data = append(data, sum[:]...)
+	return append(data, value...)
+}
+
+// decodeDiskEntry returns the value of an entry file whose header and
+// checksum are intact
+func decodeDiskEntry(data []byte) ([]byte, bool) {
+	headerLen := len(diskCacheMagic) + sha256.Size
+	if len(data) < headerLen || !bytes.Equal(data[:len(diskCacheMagic)], diskCacheMagic) {
+		return nil, false
+	}
+	value := data[headerLen:]
+	sum := sha256.Sum256(value)
+	if !bytes.Equal(data[len(diskCacheMagic):headerLen], sum[:]) {
+		return nil, false
+	}
+	return value, true
+}
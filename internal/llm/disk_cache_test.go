@@ -0,0 +1,133 @@
+package llm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+func TestDiskCachePersistsAcrossRestarts(t *testing.T) {
+	dir := t.TempDir()
+
+	first, err := NewDiskCache(dir, 0)
+	if err != nil {
+		t.Fatalf("NewDiskCache failed: %v", err)
+	}
+	first.Set("greeting", []byte("hello"))
+
+	// A new instance on the same directory stands in for a restarted process
+	restarted, err := NewDiskCache(dir, 0)
+	if err != nil {
+		t.Fatalf("NewDiskCache failed: %v", err)
+	}
+	if value, ok := restarted.Get("greeting"); !ok || string(value) != "hello" {
+		t.Fatalf("expected a hit for greeting after restart, got %q, %v", value, ok)
+	}
+	if _, ok := restarted.Get("missing"); ok {
+		t.Error("expected a miss for an unknown key")
+	}
+
+	// The provider response cache hits across restarts too
+	calls := 0
+	complete := func(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+		calls++
+		return CompletionResponse{Content: "cached answer", Usage: types.TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}}, nil
+	}
+	req := CompletionRequest{Model: "gpt-test", SystemPrompt: "system", UserPrompt: "question"}
+	if _, err := CacheInterceptor(first)(complete)(context.Background(), req); err != nil {
+		t.Fatalf("first call failed: %v", err)
+	}
+	resp, err := CacheInterceptor(restarted)(complete)(context.Background(), req)
+	if err != nil {
+		t.Fatalf("cached call failed: %v", err)
+	}
+	if calls != 1 || resp.Content != "cached answer" || resp.Usage.TotalTokens != 0 {
+		t.Errorf("expected a cache hit without usage, got calls=%d resp=%+v", calls, resp)
+	}
+}
+
+func TestDiskCacheTreatsCorruptEntriesAsMisses(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewDiskCache(dir, 0)
+	if err != nil {
+		t.Fatalf("NewDiskCache failed: %v", err)
+	}
+	cache.Set("report", []byte(`{"content":"done"}`))
+
+	// Flip a byte of the stored value, as a torn write or bad disk would
+	path := cache.entryPath("report")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading entry failed: %v", err)
+	}
+	data[len(data)-2] ^= 0xff
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("writing entry failed: %v", err)
+	}
+
+	if value, ok := cache.Get("report"); ok {
+		t.Fatalf("expected a corrupt entry to miss, got %q", value)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the corrupt entry to be deleted, stat error = %v", err)
+	}
+
+	// Truncated files miss as well
+	if err := os.WriteFile(cache.entryPath("short"), []byte("sfc"), 0o644); err != nil {
+		t.Fatalf("writing entry failed: %v", err)
+	}
+	if _, ok := cache.Get("short"); ok {
+		t.Error("expected a truncated entry to miss")
+	}
+}
+
+func TestDiskCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	value := []byte(strings.Repeat("x", 100))
+	entrySize := int64(len(encodeDiskEntry(value)))
+
+	cache, err := NewDiskCache(dir, 2*entrySize)
+	if err != nil {
+		t.Fatalf("NewDiskCache failed: %v", err)
+	}
+	cache.Set("a", value)
+	cache.Set("b", value)
+	backdate(t, cache.entryPath("a"), 2)
+	backdate(t, cache.entryPath("b"), 1)
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("expected a hit for a")
+	}
+	cache.Set("c", value)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("expected the least recently used entry b to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("expected %s to be kept", key)
+		}
+	}
+
+	entries, _ := filepath.Glob(filepath.Join(dir, "*"+diskCacheExt))
+	if len(entries) != 2 {
+		t.Errorf("expected 2 entries on disk, got %d", len(entries))
+	}
+}
+
+// backdate moves a file's modification time hoursAgo into the past
+func backdate(t *testing.T, path string, hoursAgo int) {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat failed: %v", err)
+	}
+	when := info.ModTime().Add(-time.Duration(hoursAgo) * time.Hour)
+	if err := os.Chtimes(path, when, when); err != nil {
+		t.Fatalf("chtimes failed: %v", err)
+	}
+}
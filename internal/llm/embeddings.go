@@ -0,0 +1,101 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// Embedder is implemented by providers that can embed text as vectors
+type Embedder interface {
+	// Embed returns one vector per text, in order
+	Embed(ctx context.Context, texts []string) ([][]float64, error)
+}
+
+// Embed embeds texts with OpenAI's text-embedding-3-small model
+func (provider *OpenAIProvider) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	resp, err := provider.client.CreateEmbeddings(ctx, openai.EmbeddingRequestStrings{
+		Input: texts,
+		Model: openai.SmallEmbedding3,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("OpenAI embeddings: %w", err)
+	}
+	if len(resp.Data) != len(texts) {
+		return nil, fmt.Errorf("OpenAI embeddings: got %d vectors for %d texts", len(resp.Data), len(texts))
+	}
+
+	vectors := make([][]float64, len(texts))
+	for _, item := range resp.Data {
+		if item.Index < 0 || item.Index >= len(texts) {
+			return nil, fmt.Errorf("OpenAI embeddings: unexpected index %d", item.Index)
+		}
+		vector := make([]float64, len(item.Embedding))
+		for i, value := range item.Embedding {
+			vector[i] = float64(value)
+		}
+		vectors[item.Index] = vector
+	}
+	return vectors, nil
+}
+
+// lexicalDimensions is the size of LexicalEmbedding vectors
+const lexicalDimensions = 1024
+
+// LexicalEmbedding returns a unit-length vector of hashed words and word
+// trigrams. It needs no provider and captures word overlap, so reordered or
+// lightly reworded texts score close to 1, but it has no notion of synonyms.
+func LexicalEmbedding(text string) []float64 {
+	vector := make([]float64, lexicalDimensions)
+	add := func(feature string, weight float64) {
+		hash := fnv.New32a()
+		hash.Write([]byte(feature))
+		vector[hash.Sum32()%lexicalDimensions] += weight
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		add("w:"+word, 1)
+		padded := []rune("^" + word + "$")
+		for i := 0; i+3 <= len(padded); i++ {
+			add("t:"+string(padded[i:i+3]), 0.5)
+		}
+	}
+
+	norm := 0.0
+	for _, value := range vector {
+		norm += value * value
+	}
+	if norm > 0 {
+		norm = math.Sqrt(norm)
+		for i := range vector {
+			vector[i] /= norm
+		}
+	}
+	return vector
+}
+
+// CosineSimilarity returns the cosine of the angle between a and b, or 0 when
+// their lengths differ or either is zero
+func CosineSimilarity(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
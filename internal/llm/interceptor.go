@@ -0,0 +1,40 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+)
+
+// CompletionFunc sends one completion request; Provider.Complete has this shape
+type CompletionFunc func(ctx context.Context, req CompletionRequest) (CompletionResponse, error)
+
+// Interceptor wraps a CompletionFunc to run custom logic around a provider
+// call, such as refreshing credentials, adding headers or recording metrics.
+// It may change the request, skip next, or inspect the response and error.
+type Interceptor func(next CompletionFunc) CompletionFunc
+
+// ChainInterceptors wraps complete in interceptors; the first interceptor is
+// the outermost, so it sees the request first and the response last
+func ChainInterceptors(complete CompletionFunc, interceptors ...Interceptor) CompletionFunc {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		complete = interceptors[i](complete)
+	}
+	return complete
+}
+
+type requestHeadersKey struct{}
+
+// withRequestHeaders carries per-request headers to an HTTP transport
+func withRequestHeaders(ctx context.Context, headers map[string]string) context.Context {
+	if len(headers) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, requestHeadersKey{}, headers)
+}
+
+// setRequestHeaders adds the request's Headers to an outgoing HTTP request
+func setRequestHeaders(httpReq *http.Request, headers map[string]string) {
+	for key, value := range headers {
+		httpReq.Header.Set(key, value)
+	}
+}
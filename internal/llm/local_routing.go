@@ -0,0 +1,51 @@
+package llm
+
+import (
+	"context"
+	"strings"
+)
+
+// LocalHandler produces the completion content for a local request
+type LocalHandler func(context.Context, CompletionRequest) (string, error)
+
+// InferOperation returns the name of the operation that built a request
+// (e.g., "extract", "classify"), as stamped into its MetadataOperation entry,
+// or "" for requests no operation stamped
+func InferOperation(req CompletionRequest) string {
+	return strings.ToLower(req.Metadata[MetadataOperation])
+}
+
+// WithOpHandler registers a handler for one operation (e.g., "classify").
+// Requests are routed with InferOperation, or the router set by WithOpRouter;
+// requests without a matching handler fall back to WithHandler, then to the
+// built-in mock responses.
+func (provider *LocalProvider) WithOpHandler(operation string, handler LocalHandler) *LocalProvider {
+	if provider.opHandlers == nil {
+		provider.opHandlers = make(map[string]LocalHandler)
+	}
+	provider.opHandlers[strings.ToLower(operation)] = handler
+	return provider
+}
+
+// WithOpRouter replaces InferOperation for routing requests to op handlers
+func (provider *LocalProvider) WithOpRouter(router func(CompletionRequest) string) *LocalProvider {
+	provider.opRouter = router
+	return provider
+}
+
+// resolveHandler returns the handler for a request, or nil for the mock response
+func (provider *LocalProvider) resolveHandler(req CompletionRequest) LocalHandler {
+	if len(provider.opHandlers) > 0 {
+		route := InferOperation
+		if provider.opRouter != nil {
+			route = provider.opRouter
+		}
+		if handler, ok := provider.opHandlers[strings.ToLower(route(req))]; ok {
+			return handler
+		}
+	}
+	if provider.handler != nil {
+		return provider.handler
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package llm
+
+import (
+	"sort"
+)
+
+// Well-known CompletionRequest.Metadata keys
+const (
+	MetadataRunID         = "run_id"
+	MetadataRequestID     = "request_id"
+	MetadataCorrelationID = "correlation_id"
+	MetadataUserID        = "user_id"
+	MetadataPipeline      = "pipeline"
+	MetadataPipelineStep  = "pipeline_step"
+	MetadataBatchItem     = "batch_item"
+	MetadataOperation     = "operation"
+)
+
+// OpenAI accepts at most 16 metadata pairs with values up to 512 characters
+const (
+	maxProviderMetadataPairs = 16
+	maxProviderMetadataValue = 512
+)
+
+// providerMetadata bounds metadata to what provider APIs accept, keeping
+// well-known keys first and the rest in key order
+func providerMetadata(metadata map[string]string) map[string]string {
+	if len(metadata) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	priority := map[string]int{MetadataRunID: 1, MetadataRequestID: 2, MetadataCorrelationID: 3, MetadataUserID: 4}
+	sort.Slice(keys, func(i, j int) bool {
+		pi, pj := priority[keys[i]], priority[keys[j]]
+		if pi != pj {
+			return pi != 0 && (pj == 0 || pi < pj)
+		}
+		return keys[i] < keys[j]
+	})
+
+	bounded := make(map[string]string, min(len(keys), maxProviderMetadataPairs))
+	for _, key := range keys[:min(len(keys), maxProviderMetadataPairs)] {
+		value := metadata[key]
+		if len(value) > maxProviderMetadataValue {
+			value = value[:maxProviderMetadataValue]
+		}
+		bounded[key] = value
+	}
+	return bounded
+}
+
+// metadataUser picks the identifier sent in a provider's single user field:
+// an explicit user ID, else the run ID, else the request ID
+func metadataUser(metadata map[string]string) string {
+	for _, key := range []string{MetadataUserID, MetadataRunID, MetadataRequestID} {
+		if value := metadata[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"os"
 	"sort"
@@ -41,6 +42,23 @@ type CompletionRequest struct {
 	Temperature    float64
 	MaxTokens      int
 	ResponseFormat string // "json" or "text"
+
+	// ReasoningEffort ("low", "medium" or "high") is sent to providers that
+	// report the Reasoning capability; empty uses the provider default
+	ReasoningEffort string
+
+	// Deterministic marks a request whose operation asks for a reproducible
+	// answer, so a response cache may answer it whatever its temperature.
+	// Providers ignore it.
+	Deterministic bool
+
+	// Metadata is forwarded to providers that accept request metadata (OpenAI
+	// metadata, Anthropic metadata.user_id, the user field of compatible APIs)
+	Metadata map[string]string
+
+	// Headers are added to the HTTP request by HTTP-based providers, after
+	// their own headers; interceptors use them to inject custom headers
+	Headers map[string]string
 }
 
 // CompletionResponse represents a unified response format
@@ -50,13 +68,26 @@ type CompletionResponse struct {
 	Model        string
 	Provider     string
 	FinishReason string
+
+	// SystemFingerprint identifies the backend configuration that served the
+	// completion, when the provider reports one
+	SystemFingerprint string
+
+	// SafetyFlags lists the provider's safety signals for the completion,
+	// such as "content_filter" or "refusal"
+	SafetyFlags []string
 }
 
+// FinishReasonLength is the FinishReason of a completion cut off by the
+// request's MaxTokens limit
+const FinishReasonLength = "length"
+
 // ProviderConfig contains provider-specific configuration
 type ProviderConfig struct {
 	APIKey       string
 	BaseURL      string
 	OrgID        string
+	ProjectID    string
 	Timeout      time.Duration
 	MaxRetries   int
 	RetryBackoff time.Duration
@@ -86,6 +117,13 @@ func NewOpenAIProvider(config ProviderConfig) (*OpenAIProvider, error) {
 		return nil, fmt.Errorf("OpenAI API key is required")
 	}
 
+	if config.ProjectID != "" {
+		headers := make(map[string]string, len(config.ExtraHeaders)+1)
+		maps.Copy(headers, config.ExtraHeaders)
+		headers["OpenAI-Project"] = config.ProjectID
+		config.ExtraHeaders = headers
+	}
+
 	client, config, err := newOpenAIClient(config, "")
 	if err != nil {
 		return nil, fmt.Errorf("OpenAI %w", err)
@@ -134,14 +172,21 @@ func (provider *OpenAIProvider) Complete(ctx context.Context, req CompletionRequ
 		}
 	}
 	if supportsReasoningControls(req.Model) {
+		effort := req.ReasoningEffort
+		if effort == "" {
+			effort = reasoningEffort(req.Model)
+		}
 		requestBody["reasoning"] = map[string]string{
-			"effort": reasoningEffort(req.Model),
+			"effort": effort,
 		}
 		textConfig["verbosity"] = "low"
 	}
 	if len(textConfig) > 0 {
 		requestBody["text"] = textConfig
 	}
+	if metadata := providerMetadata(req.Metadata); metadata != nil {
+		requestBody["metadata"] = metadata
+	}
 
 	jsonData, err := json.Marshal(requestBody)
 	if err != nil {
@@ -160,6 +205,10 @@ func (provider *OpenAIProvider) Complete(ctx context.Context, req CompletionRequ
 	if provider.config.OrgID != "" {
 		httpReq.Header.Set("OpenAI-Organization", provider.config.OrgID)
 	}
+	if provider.config.ProjectID != "" {
+		httpReq.Header.Set("OpenAI-Project", provider.config.ProjectID)
+	}
+	setRequestHeaders(httpReq, req.Headers)
 
 	// Use a custom HTTP client or default
 	client := &http.Client{
@@ -223,11 +272,24 @@ func (provider *OpenAIProvider) Complete(ctx context.Context, req CompletionRequ
 		return CompletionResponse{}, fmt.Errorf("empty response from OpenAI")
 	}
 
+	finishReason := "stop" // Responses API doesn't return finish_reason; incomplete_details reports truncation
+	var safetyFlags []string
+	if response.Status == "incomplete" {
+		switch response.IncompleteReason.Reason {
+		case "max_output_tokens":
+			finishReason = FinishReasonLength
+		case "content_filter":
+			finishReason = "content_filter"
+			safetyFlags = []string{"content_filter"}
+		}
+	}
+
 	return CompletionResponse{
 		Content:      content,
 		Provider:     provider.Name(),
 		Model:        response.Model,
-		FinishReason: "stop", // Responses API doesn't explicitly return finish_reason in the same way, assuming stop
+		FinishReason: finishReason,
+		SafetyFlags:  safetyFlags,
 		Usage: types.TokenUsage{
 			PromptTokens:     response.Usage.InputTokens,
 			CompletionTokens: response.Usage.OutputTokens,
@@ -307,14 +369,12 @@ func newOpenAIClient(config ProviderConfig, defaultBaseURL string) (*openai.Clie
 		clientConfig.OrgID = config.OrgID
 	}
 
-	if len(config.ExtraHeaders) > 0 {
-		clientConfig.HTTPClient = &http.Client{
-			Transport: &customTransport{
-				transport: http.DefaultTransport,
-				headers:   config.ExtraHeaders,
-			},
-			Timeout: config.Timeout,
-		}
+	clientConfig.HTTPClient = &http.Client{
+		Transport: &customTransport{
+			transport: http.DefaultTransport,
+			headers:   config.ExtraHeaders,
+		},
+		Timeout: config.Timeout,
 	}
 
 	return openai.NewClientWithConfig(clientConfig), config, nil
@@ -395,6 +455,10 @@ func (provider *AnthropicProvider) Complete(ctx context.Context, req CompletionR
 		requestBody["max_tokens"] = req.MaxTokens
 	}
 
+	if user := metadataUser(req.Metadata); user != "" {
+		requestBody["metadata"] = map[string]string{"user_id": user}
+	}
+
 	jsonData, err := json.Marshal(requestBody)
 	if err != nil {
 		return CompletionResponse{}, fmt.Errorf("failed to marshal request: %w", err)
@@ -409,6 +473,7 @@ func (provider *AnthropicProvider) Complete(ctx context.Context, req CompletionR
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("x-api-key", provider.apiKey)
 	httpReq.Header.Set("anthropic-version", "2023-06-01")
+	setRequestHeaders(httpReq, req.Headers)
 
 	// Use a custom HTTP client or default
 	client := &http.Client{
@@ -437,7 +502,8 @@ func (provider *AnthropicProvider) Complete(ctx context.Context, req CompletionR
 			InputTokens  int `json:"input_tokens"`
 			OutputTokens int `json:"output_tokens"`
 		} `json:"usage"`
-		Model string `json:"model"`
+		Model      string `json:"model"`
+		StopReason string `json:"stop_reason"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
@@ -456,11 +522,26 @@ func (provider *AnthropicProvider) Complete(ctx context.Context, req CompletionR
 		}
 	}
 
+	// Anthropic's stop reasons are passed through, except max_tokens, which
+	// maps to FinishReasonLength
+	finishReason := response.StopReason
+	switch finishReason {
+	case "":
+		finishReason = "stop"
+	case "max_tokens":
+		finishReason = FinishReasonLength
+	}
+	var safetyFlags []string
+	if response.StopReason == "refusal" {
+		safetyFlags = []string{"refusal"}
+	}
+
 	return CompletionResponse{
 		Content:      content,
 		Provider:     provider.Name(),
 		Model:        response.Model,
-		FinishReason: "stop",
+		FinishReason: finishReason,
+		SafetyFlags:  safetyFlags,
 		Usage: types.TokenUsage{
 			PromptTokens:     response.Usage.InputTokens,
 			CompletionTokens: response.Usage.OutputTokens,
@@ -500,7 +581,8 @@ type OpenRouterProvider struct {
 	*OpenAICompatibleProvider
 }
 
-// customTransport is a http.RoundTripper that adds custom headers
+// customTransport is a http.RoundTripper that adds the configured headers,
+// then any per-request headers carried by the request context
 type customTransport struct {
 	transport http.RoundTripper
 	headers   map[string]string
@@ -510,6 +592,9 @@ func (t *customTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	for k, v := range t.headers {
 		req.Header.Set(k, v)
 	}
+	if headers, ok := req.Context().Value(requestHeadersKey{}).(map[string]string); ok {
+		setRequestHeaders(req, headers)
+	}
 	return t.transport.RoundTrip(req)
 }
 
@@ -537,6 +622,7 @@ func (provider *OpenAICompatibleProvider) Complete(ctx context.Context, req Comp
 	chatRequest := openai.ChatCompletionRequest{
 		Model:    req.Model,
 		Messages: messages,
+		User:     metadataUser(req.Metadata),
 	}
 
 	if req.Temperature > 0 {
@@ -553,7 +639,7 @@ func (provider *OpenAICompatibleProvider) Complete(ctx context.Context, req Comp
 		}
 	}
 
-	completion, err := provider.client.CreateChatCompletion(ctx, chatRequest)
+	completion, err := provider.client.CreateChatCompletion(withRequestHeaders(ctx, req.Headers), chatRequest)
 	if err != nil {
 		return CompletionResponse{}, fmt.Errorf("%s completion failed: %w", provider.name, err)
 	}
@@ -562,11 +648,18 @@ func (provider *OpenAICompatibleProvider) Complete(ctx context.Context, req Comp
 		return CompletionResponse{}, fmt.Errorf("no completion choices returned")
 	}
 
+	var safetyFlags []string
+	if completion.Choices[0].FinishReason == openai.FinishReasonContentFilter {
+		safetyFlags = []string{"content_filter"}
+	}
+
 	return CompletionResponse{
-		Content:      completion.Choices[0].Message.Content,
-		Provider:     provider.Name(),
-		Model:        completion.Model,
-		FinishReason: string(completion.Choices[0].FinishReason),
+		Content:           completion.Choices[0].Message.Content,
+		Provider:          provider.Name(),
+		Model:             completion.Model,
+		FinishReason:      string(completion.Choices[0].FinishReason),
+		SystemFingerprint: completion.SystemFingerprint,
+		SafetyFlags:       safetyFlags,
 		Usage: types.TokenUsage{
 			PromptTokens:     completion.Usage.PromptTokens,
 			CompletionTokens: completion.Usage.CompletionTokens,
@@ -683,8 +776,12 @@ func NewZAIProvider(config ProviderConfig) (*ZAIProvider, error) {
 
 // LocalProvider implements Provider for local/mock models
 type LocalProvider struct {
-	config  ProviderConfig
-	handler func(context.Context, CompletionRequest) (string, error)
+	config     ProviderConfig
+	handler    func(context.Context, CompletionRequest) (string, error)
+	opHandlers map[string]LocalHandler
+	opRouter   func(CompletionRequest) string
+
+	streamChunkSize int
 }
 
 // NewLocalProvider creates a new local/mock provider
@@ -705,8 +802,8 @@ func (provider *LocalProvider) Complete(ctx context.Context, req CompletionReque
 	var content string
 	var err error
 
-	if provider.handler != nil {
-		content, err = provider.handler(ctx, req)
+	if handler := provider.resolveHandler(req); handler != nil {
+		content, err = handler(ctx, req)
 		if err != nil {
 			return CompletionResponse{}, err
 		}
@@ -334,6 +334,43 @@ func TestProviders(t *testing.T) {
 			t.Errorf("Expected custom response, got: %s", resp.Content)
 		}
 	})
+
+	t.Run("LocalProviderWithOpHandler", func(t *testing.T) {
+		provider, _ := NewLocalProvider(ProviderConfig{})
+		provider.
+			WithHandler(func(ctx context.Context, req CompletionRequest) (string, error) {
+				return "fallback", nil
+			}).
+			WithOpHandler("Classify", func(ctx context.Context, req CompletionRequest) (string, error) {
+				return "classified", nil
+			})
+
+		cases := map[string]string{
+			"classify": "classified",
+			"extract":  "fallback",
+			"":         "fallback",
+		}
+		for operation, want := range cases {
+			// The prompt names another operation; routing follows the stamp
+			req := CompletionRequest{
+				SystemPrompt: "You are a classification expert. Classify the input.",
+				Metadata:     map[string]string{MetadataOperation: operation},
+			}
+			resp, err := provider.Complete(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if resp.Content != want {
+				t.Errorf("operation %q routed to %q, want %q", operation, resp.Content, want)
+			}
+		}
+
+		provider.WithOpRouter(func(req CompletionRequest) string { return "classify" })
+		resp, _ := provider.Complete(context.Background(), CompletionRequest{SystemPrompt: "anything"})
+		if resp.Content != "classified" {
+			t.Errorf("expected custom router to select classify handler, got %q", resp.Content)
+		}
+	})
 }
 
 func TestOpenAIProviderUsesSupportedReasoningEffortForGPT54(t *testing.T) {
@@ -726,3 +763,68 @@ func TestVendorFactoriesRegistered(t *testing.T) {
 		}
 	}
 }
+
+func TestBuiltInProvidersReportCapabilities(t *testing.T) {
+	for _, name := range []string{"anthropic", "cerebras", "deepseek", "openai", "openrouter", "qwen", "zai"} {
+		provider, err := CreateProvider(name, ProviderConfig{APIKey: "test-key"})
+		if err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+		if _, ok := provider.(CapabilityReporter); !ok {
+			t.Errorf("expected %s to report its capabilities", name)
+		}
+		capabilities := CapabilitiesOf(provider)
+		if _, streams := provider.(StreamingProvider); capabilities.Streaming != streams {
+			t.Errorf("%s reports streaming=%v but StreamingProvider=%v", name, capabilities.Streaming, streams)
+		}
+		if wantJSON := name != "anthropic"; capabilities.JSONMode != wantJSON {
+			t.Errorf("%s reports JSON mode=%v, want %v", name, capabilities.JSONMode, wantJSON)
+		}
+	}
+
+	local, _ := NewLocalProvider(ProviderConfig{})
+	if CapabilitiesOf(local).Streaming {
+		t.Error("expected the local provider not to stream by default")
+	}
+	if !CapabilitiesOf(local.WithStreamChunkSize(8)).Streaming {
+		t.Error("expected the local provider to stream once a chunk size is set")
+	}
+}
+
+func TestNativeTemperatureMapping(t *testing.T) {
+	tests := []struct {
+		provider string
+		want     float64
+	}{
+		{"openai", 2.0},
+		{"openrouter", 2.0},
+		{"deepseek", 2.0},
+		{"qwen", 2.0},
+		{"cerebras", 1.5},
+		{"anthropic", 1.0},
+		{"zai", 1.0},
+		{"local", 1.0},
+		{"some-compatible-vendor", 2.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			if got := NativeTemperature(tt.provider, 1.0); got != tt.want {
+				t.Errorf("NativeTemperature(%q, 1.0) = %v, want %v", tt.provider, got, tt.want)
+			}
+			if got := NativeTemperature(tt.provider, 0.5); got != tt.want/2 {
+				t.Errorf("NativeTemperature(%q, 0.5) = %v, want %v", tt.provider, got, tt.want/2)
+			}
+		})
+	}
+
+	if got := NativeTemperature("openai", 1.7); got != 2.0 {
+		t.Errorf("expected normalized values above 1 to clamp, got %v", got)
+	}
+	if got := ClampTemperature("anthropic", 1.8); got != 1.0 {
+		t.Errorf("expected raw anthropic temperature to clamp to 1.0, got %v", got)
+	}
+	if got := ClampTemperature("openai", 1.8); got != 1.8 {
+		t.Errorf("expected raw openai temperature to pass through, got %v", got)
+	}
+}
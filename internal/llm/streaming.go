@@ -0,0 +1,45 @@
+package llm
+
+import (
+	"context"
+)
+
+// StreamingProvider is implemented by providers that can deliver completion
+// content incrementally
+type StreamingProvider interface {
+	Provider
+
+	// CompleteStream calls onDelta with each content fragment as it arrives and
+	// returns the assembled response once the stream ends
+	CompleteStream(ctx context.Context, req CompletionRequest, onDelta func(delta string)) (CompletionResponse, error)
+}
+
+// WithStreamChunkSize enables streaming of local responses in fragments of
+// size bytes. Without it CompleteStream delivers the content in one fragment
+// and Capabilities reports no streaming.
+func (provider *LocalProvider) WithStreamChunkSize(size int) *LocalProvider {
+	provider.streamChunkSize = size
+	return provider
+}
+
+// CompleteStream produces the same content as Complete, delivered in fixed-size
+// fragments so streaming consumers can be exercised without a network
+func (provider *LocalProvider) CompleteStream(ctx context.Context, req CompletionRequest, onDelta func(delta string)) (CompletionResponse, error) {
+	resp, err := provider.Complete(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+
+	size := provider.streamChunkSize
+	if size <= 0 {
+		size = max(len(resp.Content), 1)
+	}
+	for start := 0; start < len(resp.Content); start += size {
+		if err := ctx.Err(); err != nil {
+			return CompletionResponse{}, err
+		}
+		end := min(start+size, len(resp.Content))
+		onDelta(resp.Content[start:end])
+	}
+	return resp, nil
+}
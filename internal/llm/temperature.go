@@ -0,0 +1,64 @@
+package llm
+
+// SchemaFlow exposes sampling temperature on a provider-agnostic 0-1 scale.
+// NativeTemperature maps that value linearly onto each provider's native
+// range so the same setting produces comparable randomness everywhere:
+//
+//	provider                          native range   normalized 1.0 ->
+//	openai, openrouter, deepseek, qwen 0.0 - 2.0      2.0
+//	cerebras                          0.0 - 1.5      1.5
+//	anthropic, zai                    0.0 - 1.0      1.0
+//	local, mock                       0.0 - 1.0      1.0
+//	any other (OpenAI-compatible)     0.0 - 2.0      2.0
+//
+// Raw temperatures bypass the mapping and are only clamped to the native range.
+var nativeTemperatureMax = map[string]float64{
+	"openai":     2.0,
+	"openrouter": 2.0,
+	"deepseek":   2.0,
+	"qwen":       2.0,
+	"cerebras":   1.5,
+	"anthropic":  1.0,
+	"zai":        1.0,
+	"local":      1.0,
+	"mock":       1.0,
+}
+
+// defaultNativeTemperatureMax follows the OpenAI convention used by most compatible APIs.
+const defaultNativeTemperatureMax = 2.0
+
+// NativeTemperatureMax returns the upper bound of a provider's native temperature range.
+func NativeTemperatureMax(providerName string) float64 {
+	if max, ok := nativeTemperatureMax[normalizeProviderName(providerName)]; ok {
+		return max
+	}
+	return defaultNativeTemperatureMax
+}
+
+// NativeTemperature maps a normalized 0-1 temperature to the provider's native range.
+// Values outside 0-1 are clamped first.
+func NativeTemperature(providerName string, normalized float64) float64 {
+	return clampFloat(normalized, 0, 1) * NativeTemperatureMax(providerName)
+}
+
+// NormalizeTemperature maps a temperature on the OpenAI 0-2 range, the scale
+// the mode defaults were tuned on, onto the normalized 0-1 scale. Values
+// outside 0-2 are clamped first.
+func NormalizeTemperature(openAIScale float64) float64 {
+	return clampFloat(openAIScale, 0, defaultNativeTemperatureMax) / defaultNativeTemperatureMax
+}
+
+// ClampTemperature clamps a raw (native) temperature to the provider's supported range.
+func ClampTemperature(providerName string, raw float64) float64 {
+	return clampFloat(raw, 0, NativeTemperatureMax(providerName))
+}
+
+func clampFloat(value, min, max float64) float64 {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
@@ -0,0 +1,131 @@
+// package ops - Concurrency limiting for parallel batches, with optional AIMD adaptation
+package ops
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// AdaptiveConfig bounds adaptive batch concurrency
+type AdaptiveConfig struct {
+	// Min is the lowest concurrency the batch backs off to (at least 1)
+	Min int
+
+	// Max is the highest concurrency the batch ramps up to
+	Max int
+}
+
+// latencyBackoffFactor is how far above its smoothed baseline a call's
+// latency must rise before concurrency is reduced
+const latencyBackoffFactor = 1.5
+
+// concurrencyLimiter admits at most limit callers at a time. When adaptive, the
+// limit grows by one after a full window of healthy calls and is cut
+// multiplicatively on rate-limit errors (halved) or rising latency (by a
+// quarter), at most once per window so calls already in flight when it was
+// cut do not cut it again. The latency baseline follows every successful
+// call, so a lasting shift in latency becomes the new normal.
+type concurrencyLimiter struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	limit    int
+	inFlight int
+
+	adaptive bool
+	min, max int
+	baseline time.Duration // smoothed latency of successful calls
+	streak   int           // healthy calls since the limit last changed
+	cooldown int           // calls to complete before the limit may be cut again
+}
+
+// newConcurrencyLimiter starts at initial, clamped to the adaptive bounds when set
+func newConcurrencyLimiter(initial int, adaptive *AdaptiveConfig) *concurrencyLimiter {
+	limiter := &concurrencyLimiter{limit: max(initial, 1)}
+	limiter.cond = sync.NewCond(&limiter.mu)
+	if adaptive != nil {
+		limiter.adaptive = true
+		limiter.min = max(adaptive.Min, 1)
+		limiter.max = max(adaptive.Max, limiter.min)
+		limiter.limit = min(max(limiter.limit, limiter.min), limiter.max)
+	}
+	return limiter
+}
+
+// acquire blocks until a slot is free, reporting false if ctx ends first
+func (limiter *concurrencyLimiter) acquire(ctx context.Context) bool {
+	stop := context.AfterFunc(ctx, func() {
+		limiter.mu.Lock()
+		defer limiter.mu.Unlock()
+		limiter.cond.Broadcast()
+	})
+	defer stop()
+
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	for limiter.inFlight >= limiter.limit {
+		if ctx.Err() != nil {
+			return false
+		}
+		limiter.cond.Wait()
+	}
+	if ctx.Err() != nil {
+		return false
+	}
+	limiter.inFlight++
+	return true
+}
+
+// release frees a slot and, when adaptive, adjusts the limit from the call's
+// latency and error
+func (limiter *concurrencyLimiter) release(latency time.Duration, err error) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	limiter.inFlight--
+	defer limiter.cond.Broadcast()
+
+	if !limiter.adaptive {
+		return
+	}
+	if limiter.cooldown > 0 {
+		limiter.cooldown--
+	}
+	switch {
+	case isRateLimitError(err):
+		limiter.decrease(limiter.limit / 2)
+	case err != nil:
+		// Other failures say nothing about provider load
+	case limiter.baseline == 0:
+		limiter.baseline = latency
+	default:
+		slow := float64(latency) > float64(limiter.baseline)*latencyBackoffFactor
+		limiter.baseline = (limiter.baseline*7 + latency) / 8
+		if slow {
+			limiter.decrease(limiter.limit * 3 / 4)
+			return
+		}
+		limiter.streak++
+		if limiter.streak >= limiter.limit && limiter.limit < limiter.max {
+			limiter.limit++
+			limiter.streak = 0
+		}
+	}
+}
+
+// decrease cuts the limit to to, unless it was already cut within the
+// current window
+func (limiter *concurrencyLimiter) decrease(to int) {
+	limiter.streak = 0
+	if limiter.cooldown > 0 {
+		return
+	}
+	limiter.cooldown = limiter.limit
+	limiter.limit = max(to, limiter.min)
+}
+
+// current returns the limit in effect
+func (limiter *concurrencyLimiter) current() int {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	return limiter.limit
+}
@@ -0,0 +1,73 @@
+// package ops - Restricting generated fields to caller-provided value sets
+package ops
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// allowedValuesRule renders WithAllowedValues as a system prompt rule, one
+// line per path in path order
+func allowedValuesRule(allowed map[string][]string) string {
+	var lines []string
+	for _, path := range slices.Sorted(maps.Keys(allowed)) {
+		quoted := make([]string, len(allowed[path]))
+		for i, value := range allowed[path] {
+			quoted[i] = strconv.Quote(value)
+		}
+		lines = append(lines, fmt.Sprintf("\n  - %s: %s", path, strings.Join(quoted, ", ")))
+	}
+	return "\n- These fields must use one of the listed values exactly, for every element of arrays along the path; never invent other values:" + strings.Join(lines, "")
+}
+
+// allowedValueViolations lists the values of a JSON response that fall
+// outside their path's allowed set, as "path: value". Missing and null values
+// are not violations.
+func allowedValueViolations(response string, allowed map[string][]string) []string {
+	var raw any
+	decoder := json.NewDecoder(strings.NewReader(cleanJSON(response)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil
+	}
+	var violations []string
+	for _, path := range slices.Sorted(maps.Keys(allowed)) {
+		collectAllowedViolations(raw, strings.Split(path, "."), "", allowed[path], &violations)
+	}
+	return violations
+}
+
+func collectAllowedViolations(value any, segments []string, path string, allowed []string, violations *[]string) {
+	if items, ok := value.([]any); ok {
+		for i, item := range items {
+			collectAllowedViolations(item, segments, joinPath(path, strconv.Itoa(i)), allowed, violations)
+		}
+		return
+	}
+	if len(segments) == 0 {
+		var text string
+		switch v := value.(type) {
+		case nil:
+			return
+		case string:
+			text = v
+		case json.Number:
+			text = v.String()
+		default:
+			text = fmt.Sprint(v)
+		}
+		if !slices.Contains(allowed, text) {
+			*violations = append(*violations, fmt.Sprintf("%s: %q", path, text))
+		}
+		return
+	}
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return
+	}
+	collectAllowedViolations(obj[segments[0]], segments[1:], joinPath(path, segments[0]), allowed, violations)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 
@@ -27,6 +28,14 @@ type ClassifyResult[C any] struct {
 	// Reasoning explains why this category was chosen
 	Reasoning string `json:"reasoning,omitempty"`
 
+	// Other is true when the input fit none of the categories and Category
+	// holds the escape label (see ClassifyOptions.WithAllowOther)
+	Other bool `json:"other,omitempty"`
+
+	// NeedsReview is true when Confidence lies in the band set with
+	// ClassifyOptions.WithReviewBand, whatever the category
+	NeedsReview bool `json:"needs_review,omitempty"`
+
 	// Metadata contains additional operation information
 	Metadata map[string]any `json:"metadata,omitempty"`
 }
@@ -58,11 +67,35 @@ type ClassifyAlternative[C any] struct {
 //	result, err := Classify[Review, Sentiment](review,
 //	    NewClassifyOptions().WithCategories([]string{"positive", "negative"}))
 //
+//	// Or register the constants once and let Sentiment supply the categories
+//	RegisterEnum(SentimentPositive, SentimentNegative)
+//	result, err := Classify[Review, Sentiment](review, NewClassifyOptions())
+//
 //	// Multi-label classification
 //	result, err := Classify[Article, string](article, NewClassifyOptions().
 //	    WithCategories([]string{"tech", "business", "sports"}).
 //	    WithMultiLabel(true).
 //	    WithMaxCategories(2))
+//
+//	// Allow an "other" label for off-topic input
+//	result, err := Classify[string, string](ticket, NewClassifyOptions().
+//	    WithCategories([]string{"billing", "shipping"}).
+//	    WithAllowOther(true))
+//	if result.Other {
+//	    fmt.Println("No category fits:", result.Reasoning)
+//	}
+//
+//	// Route borderline moderation decisions to a human
+//	result, err := Classify[string, string](comment, NewClassifyOptions().
+//	    WithCategories([]string{"allow", "remove"}).
+//	    WithReviewBand(0.4, 0.75))
+//	if result.NeedsReview {
+//	    queueForModerator(comment, result)
+//	}
+//
+// With AllowOther, "other" is only accepted when no listed category reaches
+// MinConfidence among the alternatives; otherwise the strongest alternative is
+// used instead. An "other" answer without reasoning is rejected.
 func Classify[T any, C any](input T, opts ClassifyOptions) (ClassifyResult[C], error) {
 	log := logger.GetLogger()
 	log.Debug("Starting classify operation")
@@ -70,6 +103,12 @@ func Classify[T any, C any](input T, opts ClassifyOptions) (ClassifyResult[C], e
 	var result ClassifyResult[C]
 	result.Metadata = make(map[string]any)
 
+	// A registered enum type supplies or constrains the categories
+	opts, enum, err := withEnumCategories[C](opts)
+	if err != nil {
+		return result, fmt.Errorf("invalid options: %w", err)
+	}
+
 	// Validate options
 	if err := opts.Validate(); err != nil {
 		return result, fmt.Errorf("invalid options: %w", err)
@@ -77,6 +116,7 @@ func Classify[T any, C any](input T, opts ClassifyOptions) (ClassifyResult[C], e
 
 	categories := opts.Categories
 	opt := opts.toOpOptions()
+	opt.Operation = "classify"
 
 	// Build classification instructions
 	var instructions []string
@@ -119,6 +159,14 @@ func Classify[T any, C any](input T, opts ClassifyOptions) (ClassifyResult[C], e
 
 	categoriesJSON, _ := json.Marshal(categories)
 
+	otherRules := ""
+	if opts.AllowOther {
+		otherRules = fmt.Sprintf(`
+- If and only if NONE of the available categories genuinely applies, use the category %q
+- When using %q, explain in "reasoning" why each available category does not fit, and still list the closest categories in "alternatives"
+- Never use %q merely because the input is ambiguous between available categories`, opts.otherLabel(), opts.otherLabel(), opts.otherLabel())
+	}
+
 	systemPrompt := fmt.Sprintf(`You are a classification expert. Classify the input into the most appropriate category.
 
 Available Categories: %s
@@ -127,13 +175,13 @@ Rules:
 - Choose the most appropriate category based on semantic meaning
 - Provide a confidence score between 0.0 and 1.0
 - Include alternative classifications if relevant (sorted by confidence)
-- Provide brief reasoning for the classification
+- Provide brief reasoning for the classification%s
 
 Return a JSON object with these fields:
 - "category": the primary category (must be one of the available categories)
 - "confidence": number between 0 and 1
 - "alternatives": array of {category, confidence} for other relevant categories
-- "reasoning": brief explanation of the classification`, string(categoriesJSON))
+- "reasoning": brief explanation of the classification`, string(categoriesJSON), otherRules)
 
 	userPrompt := fmt.Sprintf("Classify this input:\n%s", inputStr)
 
@@ -144,6 +192,7 @@ Return a JSON object with these fields:
 			Input:      inputStr,
 			Categories: categories,
 			Reason:     err.Error(),
+			Cause:      err,
 		}
 	}
 
@@ -175,13 +224,44 @@ Return a JSON object with these fields:
 		return result, fmt.Errorf("failed to parse classification response: %w", err)
 	}
 
+	// Accept the escape label only when no listed category genuinely applies
+	isOther := false
+	if opts.AllowOther && strings.EqualFold(strings.TrimSpace(llmResult.Category), opts.otherLabel()) {
+		bestIdx := -1
+		for i, alt := range llmResult.Alternatives {
+			if alt.Confidence < opts.MinConfidence || !containsFold(categories, alt.Category) {
+				continue
+			}
+			if bestIdx < 0 || alt.Confidence > llmResult.Alternatives[bestIdx].Confidence {
+				bestIdx = i
+			}
+		}
+		switch {
+		case bestIdx >= 0:
+			best := llmResult.Alternatives[bestIdx]
+			log.Warn("Classify rejected other label; a category applies", "category", best.Category, "confidence", best.Confidence)
+			result.Metadata["other_rejected"] = true
+			llmResult.Category = best.Category
+			llmResult.Confidence = best.Confidence
+		case strings.TrimSpace(llmResult.Reasoning) == "":
+			return result, types.ClassifyError{
+				Input:      inputStr,
+				Categories: categories,
+				Reason:     fmt.Sprintf("%q returned without reasoning", opts.otherLabel()),
+				Confidence: llmResult.Confidence,
+			}
+		default:
+			isOther = true
+			llmResult.Category = opts.otherLabel()
+		}
+	}
+
 	// Validate the returned category
-	found := false
+	found := isOther
 	for _, cat := range categories {
-		if strings.EqualFold(llmResult.Category, cat) {
+		if !found && strings.EqualFold(llmResult.Category, cat) {
 			found = true
 			llmResult.Category = cat // normalize case
-			break
 		}
 	}
 
@@ -205,9 +285,19 @@ Return a JSON object with these fields:
 	result.Category = category
 	result.Confidence = llmResult.Confidence
 	result.Reasoning = llmResult.Reasoning
+	result.Other = isOther
+	result.NeedsReview = opts.needsReview(result.Confidence)
 
 	// Convert alternatives
 	for _, alt := range llmResult.Alternatives {
+		if enum != nil && !containsFold(enum, alt.Category) {
+			continue
+		}
+		for _, value := range enum {
+			if strings.EqualFold(alt.Category, value) {
+				alt.Category = value
+			}
+		}
 		var altCat C
 		altJSON, _ := json.Marshal(alt.Category)
 		if err := json.Unmarshal(altJSON, &altCat); err == nil {
@@ -218,10 +308,20 @@ Return a JSON object with these fields:
 		}
 	}
 
-	log.Debug("Classify operation completed", "category", llmResult.Category, "confidence", result.Confidence)
+	log.Debug("Classify operation completed", "category", llmResult.Category, "confidence", result.Confidence, "needsReview", result.NeedsReview)
 	return result, nil
 }
 
+// containsFold reports whether values contains target, ignoring case
+func containsFold(values []string, target string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, target) {
+			return true
+		}
+	}
+	return false
+}
+
 // formatInput converts any input to a string representation for the LLM
 func formatInput(input any) string {
 	if str, ok := input.(string); ok {
@@ -293,6 +393,7 @@ func Score[T any](input T, opts ScoreOptions) (ScoreResult, error) {
 	}
 
 	opt := opts.toOpOptions()
+	opt.Operation = "score"
 
 	// Build scoring instructions
 	var instructions []string
@@ -366,6 +467,7 @@ Return a JSON object with these fields:
 		return result, types.ScoreError{
 			Input:  input,
 			Reason: err.Error(),
+			Cause:  err,
 		}
 	}
 
@@ -489,6 +591,7 @@ func Compare[T any](itemA, itemB T, opts CompareOptions) (CompareResult[T], erro
 	}
 
 	opt := opts.toOpOptions()
+	opt.Operation = "compare"
 
 	// Build comparison instructions
 	var instructions []string
@@ -552,6 +655,7 @@ Return a JSON object with these fields:
 			A:      itemA,
 			B:      itemB,
 			Reason: err.Error(),
+			Cause:  err,
 		}
 	}
 
@@ -616,8 +720,9 @@ Return a JSON object with these fields:
 // SimilarOptions configures the Similar operation
 type SimilarOptions struct {
 	types.OpOptions
-	SimilarityThreshold float64  // Threshold for similarity (0-1)
-	Aspects             []string // Specific aspects to compare
+	SimilarityThreshold float64            // Threshold for similarity (0-1)
+	Aspects             []string           // Specific aspects to compare
+	AspectWeights       map[string]float64 // Relative weight of each aspect in the final score
 }
 
 // NewSimilarOptions creates SimilarOptions with defaults
@@ -636,6 +741,18 @@ func (opts SimilarOptions) Validate() error {
 	if opts.SimilarityThreshold < 0 || opts.SimilarityThreshold > 1 {
 		return fmt.Errorf("similarity threshold must be between 0 and 1, got %f", opts.SimilarityThreshold)
 	}
+	if len(opts.AspectWeights) > 0 {
+		total := 0.0
+		for aspect, weight := range opts.AspectWeights {
+			if weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+				return fmt.Errorf("aspect %q has invalid weight %v", aspect, weight)
+			}
+			total += weight
+		}
+		if total == 0 {
+			return fmt.Errorf("aspect weights must not all be zero")
+		}
+	}
 	return nil
 }
 
@@ -651,6 +768,15 @@ func (opts SimilarOptions) WithAspects(aspects []string) SimilarOptions {
 	return opts
 }
 
+// WithAspectWeights compares the weighted aspects and computes the overall
+// score as their weighted mean, so heavily weighted aspects dominate it.
+// Weights are relative and normalized to sum to 1; each aspect's share of the
+// score is reported in SimilarResult.AspectContributions.
+func (opts SimilarOptions) WithAspectWeights(weights map[string]float64) SimilarOptions {
+	opts.AspectWeights = weights
+	return opts
+}
+
 // SimilarResult contains the results of similarity analysis.
 type SimilarResult struct {
 	// IsSimilar indicates whether the items meet the similarity threshold
@@ -671,6 +797,10 @@ type SimilarResult struct {
 	// Explanation describes why the items are or aren't similar
 	Explanation string `json:"explanation"`
 
+	// AspectContributions breaks the score down by weighted aspect when
+	// WithAspectWeights is used; the contributions sum to Score
+	AspectContributions []AspectContribution `json:"aspect_contributions,omitempty"`
+
 	// Metadata contains additional operation information
 	Metadata map[string]any `json:"metadata,omitempty"`
 }
@@ -682,6 +812,14 @@ type AspectMatch struct {
 	Reason string  `json:"reason,omitempty"`
 }
 
+// AspectContribution is one weighted aspect's share of a similarity score
+type AspectContribution struct {
+	Aspect       string  `json:"aspect"`
+	Weight       float64 `json:"weight"`       // normalized weight (weights sum to 1)
+	Score        float64 `json:"score"`        // the aspect's similarity (0.0-1.0)
+	Contribution float64 `json:"contribution"` // Weight * Score
+}
+
 // Similar checks semantic similarity between two items of the same type.
 //
 // Type parameter T specifies the type of items being compared.
@@ -714,13 +852,15 @@ func Similar[T any](itemA, itemB T, opts SimilarOptions) (SimilarResult, error)
 	}
 
 	opt := opts.OpOptions
+	opt.Operation = "similar"
 
 	// Build similarity instructions
 	var instructions []string
 	instructions = append(instructions, fmt.Sprintf("Similarity threshold: %.2f", opts.SimilarityThreshold))
 
-	if len(opts.Aspects) > 0 {
-		instructions = append(instructions, fmt.Sprintf("Compare aspects: %s", strings.Join(opts.Aspects, ", ")))
+	aspects := similarAspects(opts)
+	if len(aspects) > 0 {
+		instructions = append(instructions, fmt.Sprintf("Compare aspects: %s", strings.Join(aspects, ", ")))
 	}
 
 	if len(instructions) > 0 {
@@ -744,6 +884,9 @@ func Similar[T any](itemA, itemB T, opts SimilarOptions) (SimilarResult, error)
 	itemBString := formatInput(itemB)
 
 	aspectsJSON, _ := json.Marshal(opts.Aspects)
+	if len(opts.AspectWeights) > 0 {
+		aspectsJSON, _ = json.Marshal(aspects)
+	}
 
 	systemPrompt := fmt.Sprintf(`You are a similarity analyzer. Determine if two items are semantically similar.
 
@@ -764,6 +907,7 @@ Return a JSON object with these fields:
 - "differing_aspects": array of {aspect, score, reason} for different aspects
 - "explanation": overall explanation of similarity`,
 		opts.SimilarityThreshold, string(aspectsJSON), opts.SimilarityThreshold)
+	systemPrompt += aspectWeightRules(opts.AspectWeights)
 
 	userPrompt := fmt.Sprintf("Compare these items for similarity:\n\nItem A:\n%s\n\nItem B:\n%s", itemAString, itemBString)
 
@@ -840,6 +984,11 @@ Return a JSON object with these fields:
 		})
 	}
 
+	if len(opts.AspectWeights) > 0 {
+		result.Score, result.AspectContributions = weightAspectScores(opts.AspectWeights, result)
+		result.IsSimilar = result.Score >= opts.SimilarityThreshold
+	}
+
 	log.Debug("Similar operation completed", "isSimilar", result.IsSimilar, "score", result.Score)
 	return result, nil
 }
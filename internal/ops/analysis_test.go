@@ -0,0 +1,272 @@
+package ops
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+func TestClassifyAllowOther(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	opts := NewClassifyOptions().
+		WithCategories([]string{"billing", "shipping"}).
+		WithAllowOther(true)
+
+	t.Run("OffTopicInputIsOther", func(t *testing.T) {
+		setLLMCaller(func(ctx context.Context, system, user string, o types.OpOptions) (string, error) {
+			if !strings.Contains(system, `NONE of the available categories genuinely applies, use the category "other"`) {
+				t.Errorf("expected other-label rules in system prompt")
+			}
+			return `{"category": "other", "confidence": 0.9,
+				"alternatives": [{"category": "billing", "confidence": 0.1}],
+				"reasoning": "A pasta recipe is neither a billing nor a shipping question"}`, nil
+		})
+
+		result, err := Classify[string, string]("How long should I boil penne?", opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Category != "other" || !result.Other {
+			t.Errorf("expected other, got %+v", result)
+		}
+		if result.Reasoning == "" {
+			t.Error("expected reasoning for other label")
+		}
+	})
+
+	t.Run("OtherRejectedWhenCategoryApplies", func(t *testing.T) {
+		setLLMCaller(func(ctx context.Context, system, user string, o types.OpOptions) (string, error) {
+			return `{"category": "other", "confidence": 0.5,
+				"alternatives": [{"category": "shipping", "confidence": 0.7}],
+				"reasoning": "unsure"}`, nil
+		})
+
+		result, err := Classify[string, string]("My parcel never arrived", opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Category != "shipping" || result.Other || result.Metadata["other_rejected"] != true {
+			t.Errorf("expected other to be overridden by shipping, got %+v", result)
+		}
+	})
+
+	t.Run("OtherWithoutReasoningIsInvalid", func(t *testing.T) {
+		setLLMCaller(func(ctx context.Context, system, user string, o types.OpOptions) (string, error) {
+			return `{"category": "other", "confidence": 0.8}`, nil
+		})
+
+		if _, err := Classify[string, string]("How long should I boil penne?", opts); err == nil {
+			t.Error("expected error for other label without reasoning")
+		}
+	})
+
+	t.Run("OtherNotAllowedByDefault", func(t *testing.T) {
+		setLLMCaller(func(ctx context.Context, system, user string, o types.OpOptions) (string, error) {
+			return `{"category": "other", "confidence": 0.9, "reasoning": "off-topic"}`, nil
+		})
+
+		if _, err := Classify[string, string]("How long should I boil penne?", opts.WithAllowOther(false)); err == nil {
+			t.Error("expected other to be rejected without AllowOther")
+		}
+	})
+
+	t.Run("OtherLabelMustNotDuplicateCategory", func(t *testing.T) {
+		if err := opts.WithOtherLabel("Billing").Validate(); err == nil {
+			t.Error("expected validation error for other label that duplicates a category")
+		}
+	})
+}
+
+type testSentiment string
+
+const (
+	sentimentPositive testSentiment = "positive"
+	sentimentNegative testSentiment = "negative"
+	sentimentMixed    testSentiment = "mixed"
+)
+
+func TestClassifyUsesRegisteredEnumConstants(t *testing.T) {
+	defer setupMockClient()
+	RegisterEnum(sentimentPositive, sentimentNegative, sentimentMixed)
+
+	var system string
+	response := `{"category": "Negative", "confidence": 0.9, "alternatives": [{"category": "mixed", "confidence": 0.3}, {"category": "angry", "confidence": 0.2}]}`
+	setLLMCaller(func(ctx context.Context, sys, user string, o types.OpOptions) (string, error) {
+		system = sys
+		return response, nil
+	})
+
+	result, err := Classify[string, testSentiment]("The update broke everything I relied on.", NewClassifyOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(system, `["positive","negative","mixed"]`) {
+		t.Errorf("expected the enum constants as categories, got %q", system)
+	}
+	if result.Category != sentimentNegative {
+		t.Errorf("expected the typed constant %q, got %q", sentimentNegative, result.Category)
+	}
+	if len(result.Alternatives) != 1 || result.Alternatives[0].Category != sentimentMixed {
+		t.Errorf("expected alternatives limited to the enum constants, got %+v", result.Alternatives)
+	}
+
+	response = `{"category": "angry", "confidence": 0.9}`
+	if _, err := Classify[string, testSentiment]("I am furious.", NewClassifyOptions()); err == nil {
+		t.Error("expected a category outside the enum to be rejected")
+	}
+	opts := NewClassifyOptions().WithCategories([]string{"positive", "neutral"})
+	if _, err := Classify[string, testSentiment]("Fine, I guess.", opts); err == nil {
+		t.Error("expected a listed category that is not an enum constant to be rejected")
+	}
+}
+
+func TestClassifyReviewBandFlagsBorderlineConfidence(t *testing.T) {
+	defer setupMockClient()
+
+	confidence := "0.55"
+	setLLMCaller(func(ctx context.Context, system, user string, o types.OpOptions) (string, error) {
+		return `{"category": "remove", "confidence": ` + confidence + `, "reasoning": "Possibly a veiled threat"}`, nil
+	})
+
+	opts := NewClassifyOptions().
+		WithCategories([]string{"allow", "remove"}).
+		WithReviewBand(0.4, 0.75)
+
+	result, err := Classify[string, string]("I know where you park your car.", opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Category != "remove" || !result.NeedsReview {
+		t.Errorf("expected a borderline removal flagged for review, got %+v", result)
+	}
+
+	confidence = "0.95"
+	result, err = Classify[string, string]("I know where you park your car.", opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.NeedsReview {
+		t.Errorf("expected a confident result outside the band not to need review, got %+v", result)
+	}
+
+	if err := opts.WithReviewBand(0.8, 0.6).Validate(); err == nil {
+		t.Error("expected an inverted review band to be rejected")
+	}
+}
+
+func TestScoreWithRubricWeightsCriteria(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	rubric, err := NewRubric([]Criterion{
+		{Name: "clarity", Weight: 1, Description: "Easy to follow"},
+		{Name: "accuracy", Weight: 3, Description: "Claims are correct", ScaleMin: 1, ScaleMax: 5},
+	})
+	if err != nil {
+		t.Fatalf("NewRubric() error = %v", err)
+	}
+
+	var prompts []string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		prompts = append(prompts, system)
+		return `{"scores": {"clarity": 8, "accuracy": 2}, "reasoning": "clear but often wrong"}`, nil
+	})
+
+	essay := "The moon is made of cheese, as this essay explains step by step."
+	for i := 0; i < 2; i++ {
+		result, err := ScoreWithRubric(essay, rubric, NewScoreOptions().WithScaleMax(100))
+		if err != nil {
+			t.Fatalf("ScoreWithRubric() error = %v", err)
+		}
+		// clarity 8/10 -> 0.8 at weight 0.25; accuracy 2 on 1-5 -> 0.25 at weight 0.75
+		wantNormalized := 0.25*0.8 + 0.75*0.25
+		if diff := result.NormalizedValue - wantNormalized; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("NormalizedValue = %v, want %v", result.NormalizedValue, wantNormalized)
+		}
+		if diff := result.Value - wantNormalized*100; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("Value = %v, want %v", result.Value, wantNormalized*100)
+		}
+		if result.Breakdown["clarity"] != 8 || result.Breakdown["accuracy"] != 2 {
+			t.Errorf("Breakdown = %v, want raw criterion scores", result.Breakdown)
+		}
+		weights, _ := result.Metadata["weights"].(map[string]float64)
+		if weights["clarity"] != 0.25 || weights["accuracy"] != 0.75 {
+			t.Errorf("weights = %v, want clarity 0.25 and accuracy 0.75", weights)
+		}
+	}
+	if !strings.Contains(prompts[0], `"accuracy" (1.0 to 5.0): Claims are correct`) {
+		t.Errorf("prompt missing criterion scale and description: %q", prompts[0])
+	}
+
+	if _, err := NewRubric([]Criterion{{Name: "a"}, {Name: "a", Weight: 1}}); err == nil {
+		t.Error("NewRubric() accepted duplicate criteria")
+	}
+	if _, err := NewRubric([]Criterion{{Name: "a"}}); err == nil {
+		t.Error("NewRubric() accepted all-zero weights")
+	}
+}
+
+func TestSimilarAspectWeightsFavorSharedRootCause(t *testing.T) {
+	defer setupMockClient()
+
+	var systems []string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		systems = append(systems, system)
+		// The model's own overall score is deliberately the same for both pairs
+		if strings.Contains(user, "expired certificate") {
+			return `{"is_similar":false,"score":0.5,
+				"matched_aspects":[{"aspect":"underlying issue","score":0.9,"reason":"both are the expired TLS certificate"}],
+				"differing_aspects":[{"aspect":"wording","score":0.2,"reason":"different symptoms described"}],
+				"explanation":"same outage"}`, nil
+		}
+		return `{"is_similar":false,"score":0.5,
+			"matched_aspects":[{"aspect":"Wording","score":0.95,"reason":"nearly identical text"}],
+			"differing_aspects":[{"aspect":"underlying issue","score":0.1,"reason":"DNS versus disk"}],
+			"explanation":"look alike"}`, nil
+	})
+
+	opts := NewSimilarOptions().
+		WithSimilarityThreshold(0.6).
+		WithAspectWeights(map[string]float64{"underlying issue": 4, "wording": 1})
+
+	rootCause, err := Similar("Checkout fails with TLS handshake error", "Mobile app cannot log in: expired certificate on api host", opts)
+	if err != nil {
+		t.Fatalf("Similar() error = %v", err)
+	}
+	surface, err := Similar("Checkout fails with TLS handshake error", "Checkout fails with timeout error", opts)
+	if err != nil {
+		t.Fatalf("Similar() error = %v", err)
+	}
+
+	if math.Abs(rootCause.Score-0.76) > 1e-9 || math.Abs(surface.Score-0.27) > 1e-9 {
+		t.Errorf("scores = %.3f (root cause) / %.3f (surface), want 0.76 / 0.27", rootCause.Score, surface.Score)
+	}
+	if !rootCause.IsSimilar || surface.IsSimilar {
+		t.Errorf("IsSimilar = %v / %v, want true / false", rootCause.IsSimilar, surface.IsSimilar)
+	}
+
+	if len(rootCause.AspectContributions) != 2 {
+		t.Fatalf("AspectContributions = %+v, want one per weighted aspect", rootCause.AspectContributions)
+	}
+	sum := 0.0
+	for _, contribution := range rootCause.AspectContributions {
+		sum += contribution.Contribution
+	}
+	issue := rootCause.AspectContributions[0]
+	if issue.Aspect != "underlying issue" || issue.Weight != 0.8 || math.Abs(issue.Contribution-0.72) > 1e-9 || math.Abs(sum-rootCause.Score) > 1e-9 {
+		t.Errorf("contributions = %+v, want underlying issue weight 0.8 contributing 0.72 and a total of %.2f", rootCause.AspectContributions, rootCause.Score)
+	}
+
+	if !strings.Contains(systems[0], "underlying issue (weight 0.80), wording (weight 0.20)") {
+		t.Errorf("system prompt missing aspect weights:\n%s", systems[0])
+	}
+
+	if err := NewSimilarOptions().WithAspectWeights(map[string]float64{"tone": -1}).Validate(); err == nil {
+		t.Error("expected a negative aspect weight to fail validation")
+	}
+}
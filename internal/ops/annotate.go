@@ -184,6 +184,7 @@ func Annotate[T any](input T, opts AnnotateOptions) (AnnotateResult, error) {
 	}
 
 	opt := opts.toOpOptions()
+	opt.Operation = "annotate"
 
 	ctx := opt.Context
 	if ctx == nil {
@@ -311,6 +312,7 @@ func AnnotateStruct[T any, U any](input T, opts AnnotateOptions) (U, error) {
 	}
 
 	opt := opts.toOpOptions()
+	opt.Operation = "annotate"
 
 	ctx := opt.Context
 	if ctx == nil {
@@ -179,12 +179,7 @@ func Arbitrate[T any](options []T, opts ...ArbitrateOptions) (ArbitrateResult[T]
 	}
 
 	// Apply defaults
-	opt := ArbitrateOptions{
-		IncludeReasoning: true,
-		Tiebreaker:       "most-confident",
-		Mode:             types.TransformMode,
-		Intelligence:     types.Fast,
-	}
+	opt := defaultArbitrateOptions()
 	if len(opts) > 0 {
 		opt = mergeArbitrateOptions(opt, opts[0])
 	}
@@ -285,6 +280,7 @@ Rules:
 		Context:       ctx,
 		RequestID:     opt.RequestID,
 		CorrelationID: opt.CorrelationID,
+		Operation:     "arbitrate",
 	}
 
 	response, err := callLLM(ctx, systemPrompt, userPrompt, opOpts)
@@ -347,6 +343,16 @@ Rules:
 	return result, nil
 }
 
+// defaultArbitrateOptions returns the options Arbitrate runs with when none are given
+func defaultArbitrateOptions() ArbitrateOptions {
+	return ArbitrateOptions{
+		IncludeReasoning: true,
+		Tiebreaker:       "most-confident",
+		Mode:             types.TransformMode,
+		Intelligence:     types.Fast,
+	}
+}
+
 // mergeArbitrateOptions merges user options with defaults
 func mergeArbitrateOptions(defaults, user ArbitrateOptions) ArbitrateOptions {
 	if user.Rules != nil {
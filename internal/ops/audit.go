@@ -3,6 +3,8 @@ package ops
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"reflect"
@@ -18,6 +20,11 @@ type AuditOptions struct {
 	// Policies lists audit rules/policies to check against
 	Policies []string
 
+	// PolicySet lists named policies, typically loaded with LoadPolicies.
+	// Violations inherit the policy's category and severity, and blocking
+	// policies fail the audit whatever the severity threshold.
+	PolicySet []Policy
+
 	// Categories specifies which categories of issues to check
 	// e.g., "security", "compliance", "quality", "consistency"
 	Categories []string
@@ -28,6 +35,14 @@ type AuditOptions struct {
 	// Deep enables recursive inspection of nested structures
 	Deep bool
 
+	// DatasetPolicies lists cross-record rules evaluated over the whole
+	// collection by AuditCollection (e.g., "no duplicate SSNs across records")
+	DatasetPolicies []string
+
+	// HashReport stores a SHA-256 of the input, policies and findings in the
+	// result's ReportHash so VerifyReport can detect later edits to the report
+	HashReport bool
+
 	// Common options
 	Steering      string
 	Mode          types.Mode
@@ -59,6 +74,9 @@ type AuditFinding struct {
 
 	// Policy is the policy/rule that was violated (if applicable)
 	Policy string `json:"policy,omitempty"`
+
+	// Records lists the indices of the records involved in a dataset-level finding
+	Records []int `json:"records,omitempty"`
 }
 
 // AuditSummary provides aggregate statistics
@@ -75,8 +93,12 @@ type AuditSummary struct {
 	// Critical flags if any critical (severity >= 0.9) findings exist
 	Critical bool `json:"critical"`
 
-	// PassesAudit indicates if data passes the audit (no high severity findings)
+	// PassesAudit indicates if data passes the audit (no high severity findings
+	// and no blocking policy violations)
 	PassesAudit bool `json:"passes_audit"`
+
+	// BlockingViolations lists the names of violated blocking policies
+	BlockingViolations []string `json:"blocking_violations,omitempty"`
 }
 
 // AuditResult contains the complete audit output
@@ -90,6 +112,12 @@ type AuditResult[T any] struct {
 	// Summary provides aggregate statistics
 	Summary AuditSummary `json:"summary"`
 
+	// Policies lists the policies the data was checked against (set with HashReport)
+	Policies []string `json:"policies,omitempty"`
+
+	// ReportHash is a hex SHA-256 of the input, Policies and Findings (set with HashReport)
+	ReportHash string `json:"report_hash,omitempty"`
+
 	// Metadata contains additional operation information
 	Metadata map[string]any `json:"metadata,omitempty"`
 }
@@ -152,12 +180,7 @@ func Audit[T any](data T, opts ...AuditOptions) (AuditResult[T], error) {
 	result.Metadata = make(map[string]any)
 
 	// Apply defaults
-	opt := AuditOptions{
-		Threshold:    0.0, // Report everything
-		Deep:         true,
-		Mode:         types.TransformMode,
-		Intelligence: types.Smart,
-	}
+	opt := defaultAuditOptions()
 	if len(opts) > 0 {
 		opt = mergeAuditOptions(opt, opts[0])
 	}
@@ -182,12 +205,9 @@ func Audit[T any](data T, opts ...AuditOptions) (AuditResult[T], error) {
 
 	// Build policies description
 	policiesDesc := ""
-	if len(opt.Policies) > 0 {
-		var parts []string
-		for _, p := range opt.Policies {
-			parts = append(parts, fmt.Sprintf("- %s", p))
-		}
-		policiesDesc = fmt.Sprintf("\n\nPolicies to check:\n%s", strings.Join(parts, "\n"))
+	if len(opt.Policies) > 0 || len(opt.PolicySet) > 0 {
+		parts := policyLines(opt.Policies, opt.PolicySet)
+		policiesDesc = fmt.Sprintf("\n\nPolicies to check:\n%s%s", strings.Join(parts, "\n"), policySetNote(opt.PolicySet))
 	}
 
 	// Build categories description
@@ -251,6 +271,7 @@ Be thorough but precise. Only report genuine issues.`,
 		Context:       ctx,
 		RequestID:     opt.RequestID,
 		CorrelationID: opt.CorrelationID,
+		Operation:     "audit",
 	}
 
 	response, err := callLLM(ctx, systemPrompt, userPrompt, opOpts)
@@ -283,13 +304,23 @@ Be thorough but precise. Only report genuine issues.`,
 
 	// Filter by threshold
 	for _, f := range parsed.Findings {
-		if f.Severity >= opt.Threshold {
+		f = applyPolicy(f, opt.PolicySet)
+		if keepFinding(f, opt.Threshold, opt.PolicySet) {
 			result.Findings = append(result.Findings, f)
 		}
 	}
 
 	// Build summary
 	result.Summary = buildAuditSummary(result.Findings)
+	markBlockingViolations(&result.Summary, result.Findings, opt.PolicySet)
+
+	if opt.HashReport {
+		result.Policies = policyLines(opt.Policies, opt.PolicySet)
+		result.ReportHash, err = auditReportHash(inputJSON, result.Policies, result.Findings)
+		if err != nil {
+			return result, fmt.Errorf("failed to hash audit report: %w", err)
+		}
+	}
 
 	log.Debug("Audit operation succeeded",
 		"findings", result.Summary.TotalFindings,
@@ -299,6 +330,247 @@ Be thorough but precise. Only report genuine issues.`,
 	return result, nil
 }
 
+// AuditRecordFindings groups the findings raised against a single record
+type AuditRecordFindings struct {
+	// Index is the position of the record in the audited collection
+	Index int `json:"index"`
+
+	// Findings lists issues discovered in this record
+	Findings []AuditFinding `json:"findings"`
+}
+
+// AuditCollectionResult contains record-level and dataset-level audit output
+type AuditCollectionResult[T any] struct {
+	// Records is the input collection that was audited
+	Records []T `json:"records"`
+
+	// RecordFindings lists per-record findings (only records with findings are included)
+	RecordFindings []AuditRecordFindings `json:"record_findings"`
+
+	// DatasetFindings lists issues that span multiple records or the dataset as a whole
+	DatasetFindings []AuditFinding `json:"dataset_findings"`
+
+	// Summary provides aggregate statistics over record and dataset findings
+	Summary AuditSummary `json:"summary"`
+
+	// Metadata contains additional operation information
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// AuditCollection audits a collection of records, checking both per-record policies
+// and cross-record (dataset) policies such as uniqueness or reconciliation rules.
+//
+// Policies are applied to every record individually, while DatasetPolicies are
+// evaluated across the whole collection. Dataset findings reference the indices
+// of the records involved via AuditFinding.Records.
+//
+// Example:
+//
+//	result, err := AuditCollection(customers, AuditOptions{
+//	    Policies:        []string{"SSN must be 9 digits"},
+//	    DatasetPolicies: []string{"No duplicate SSNs across records"},
+//	})
+//	for _, f := range result.DatasetFindings {
+//	    fmt.Printf("%s (records %v)\n", f.Issue, f.Records)
+//	}
+func AuditCollection[T any](records []T, opts ...AuditOptions) (AuditCollectionResult[T], error) {
+	log := logger.GetLogger()
+	log.Debug("Starting audit collection operation", "records", len(records))
+
+	var result AuditCollectionResult[T]
+	result.Records = records
+	result.Metadata = make(map[string]any)
+
+	if len(records) == 0 {
+		return result, fmt.Errorf("no records to audit")
+	}
+
+	// Apply defaults
+	opt := AuditOptions{
+		Threshold:    0.0, // Report everything
+		Deep:         true,
+		Mode:         types.TransformMode,
+		Intelligence: types.Smart,
+	}
+	if len(opts) > 0 {
+		opt = mergeAuditOptions(opt, opts[0])
+	}
+
+	// Get context
+	ctx := opt.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, config.GetTimeout())
+	defer cancel()
+
+	// Index records so the LLM can reference them
+	indexed := make([]map[string]any, len(records))
+	for i, record := range records {
+		indexed[i] = map[string]any{"index": i, "record": record}
+	}
+	inputJSON, err := json.Marshal(indexed)
+	if err != nil {
+		log.Error("Audit collection failed: marshal error", "error", err)
+		return result, fmt.Errorf("failed to marshal records: %w", err)
+	}
+
+	var elem T
+	schema := GenerateTypeSchema(reflect.TypeOf(elem))
+
+	policiesDesc := ""
+	if len(opt.Policies) > 0 || len(opt.PolicySet) > 0 {
+		parts := policyLines(opt.Policies, opt.PolicySet)
+		policiesDesc = fmt.Sprintf("\n\nRecord policies (check each record):\n%s%s", strings.Join(parts, "\n"), policySetNote(opt.PolicySet))
+	}
+
+	datasetDesc := ""
+	if len(opt.DatasetPolicies) > 0 {
+		var parts []string
+		for _, p := range opt.DatasetPolicies {
+			parts = append(parts, fmt.Sprintf("- %s", p))
+		}
+		datasetDesc = fmt.Sprintf("\n\nDataset policies (check across all records):\n%s", strings.Join(parts, "\n"))
+	}
+
+	categoriesDesc := ""
+	if len(opt.Categories) > 0 {
+		categoriesDesc = fmt.Sprintf("\n\nFocus on categories: %s", strings.Join(opt.Categories, ", "))
+	}
+
+	systemPrompt := fmt.Sprintf(`You are a data audit expert. Audit a collection of records for issues in individual records and across the dataset.
+
+Record schema: %s%s%s%s
+
+Severity threshold: %.2f (only report findings at or above this level)
+Deep inspection: %v
+
+Return a JSON object with:
+{
+  "record_findings": [
+    {
+      "index": 0,
+      "findings": [
+        {
+          "category": "security/compliance/quality/consistency/completeness",
+          "severity": 0.0-1.0,
+          "field": "path.to.field",
+          "issue": "description of the problem",
+          "evidence": "specific value or pattern",
+          "recommendation": "how to fix",
+          "policy": "violated policy if applicable"
+        }
+      ]
+    }
+  ],
+  "dataset_findings": [
+    {
+      "category": "consistency",
+      "severity": 0.0-1.0,
+      "field": "path.to.field",
+      "issue": "description of the cross-record problem",
+      "evidence": "conflicting values",
+      "recommendation": "how to fix",
+      "policy": "violated dataset policy if applicable",
+      "records": [0, 1]
+    }
+  ]
+}
+
+Rules:
+- record_findings are issues contained within a single record; use the record's index
+- dataset_findings are issues that only appear when comparing records (duplicates, totals that do not reconcile, inconsistent values)
+- Always list the indices of every involved record in "records"
+
+Severity levels:
+- 0.0-0.2: Info (observations, suggestions)
+- 0.3-0.4: Low (minor issues, style concerns)
+- 0.5-0.6: Medium (should be addressed)
+- 0.7-0.8: High (must be fixed)
+- 0.9-1.0: Critical (immediate action required)
+
+Be thorough but precise. Only report genuine issues.`,
+		schema, policiesDesc, datasetDesc, categoriesDesc, opt.Threshold, opt.Deep)
+
+	steeringNote := ""
+	if opt.Steering != "" {
+		steeringNote = fmt.Sprintf("\n\nAdditional guidance: %s", opt.Steering)
+	}
+
+	userPrompt := fmt.Sprintf(`Audit these %d records:
+
+%s%s`, len(records), string(inputJSON), steeringNote)
+
+	opOpts := types.OpOptions{
+		Mode:          opt.Mode,
+		Intelligence:  opt.Intelligence,
+		Context:       ctx,
+		RequestID:     opt.RequestID,
+		CorrelationID: opt.CorrelationID,
+		Operation:     "audit",
+	}
+
+	response, err := callLLM(ctx, systemPrompt, userPrompt, opOpts)
+	if err != nil {
+		log.Error("Audit collection LLM call failed", "error", err)
+		return result, fmt.Errorf("audit collection failed: %w", err)
+	}
+
+	var parsed struct {
+		RecordFindings  []AuditRecordFindings `json:"record_findings"`
+		DatasetFindings []AuditFinding        `json:"dataset_findings"`
+	}
+	if err := ParseJSON(response, &parsed); err != nil {
+		log.Error("Audit collection failed: parse error", "error", err)
+		return result, fmt.Errorf("failed to parse audit collection result: %w", err)
+	}
+
+	// Filter by threshold and drop references to unknown records
+	var all []AuditFinding
+	for _, rf := range parsed.RecordFindings {
+		if rf.Index < 0 || rf.Index >= len(records) {
+			continue
+		}
+		var kept []AuditFinding
+		for _, f := range rf.Findings {
+			f = applyPolicy(f, opt.PolicySet)
+			if keepFinding(f, opt.Threshold, opt.PolicySet) {
+				kept = append(kept, f)
+			}
+		}
+		if len(kept) > 0 {
+			result.RecordFindings = append(result.RecordFindings, AuditRecordFindings{Index: rf.Index, Findings: kept})
+			all = append(all, kept...)
+		}
+	}
+	for _, f := range parsed.DatasetFindings {
+		if f.Severity < opt.Threshold {
+			continue
+		}
+		var valid []int
+		for _, idx := range f.Records {
+			if idx >= 0 && idx < len(records) {
+				valid = append(valid, idx)
+			}
+		}
+		f.Records = valid
+		result.DatasetFindings = append(result.DatasetFindings, f)
+		all = append(all, f)
+	}
+
+	result.Summary = buildAuditSummary(all)
+	markBlockingViolations(&result.Summary, all, opt.PolicySet)
+	result.Metadata["records_audited"] = len(records)
+
+	log.Debug("Audit collection succeeded",
+		"records", len(records),
+		"recordFindings", len(result.RecordFindings),
+		"datasetFindings", len(result.DatasetFindings),
+		"passes", result.Summary.PassesAudit)
+
+	return result, nil
+}
+
 // buildAuditSummary creates aggregate statistics from findings
 func buildAuditSummary(findings []AuditFinding) AuditSummary {
 	summary := AuditSummary{
@@ -335,17 +607,71 @@ func buildAuditSummary(findings []AuditFinding) AuditSummary {
 	return summary
 }
 
+// auditReportHash hashes the canonical JSON of an audit's input, policies and findings
+func auditReportHash(inputJSON []byte, policies []string, findings []AuditFinding) (string, error) {
+	data, err := json.Marshal(struct {
+		Input    json.RawMessage `json:"input"`
+		Policies []string        `json:"policies"`
+		Findings []AuditFinding  `json:"findings"`
+	}{inputJSON, policies, findings})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// VerifyReport reports whether an audit result produced with HashReport still
+// matches input: it is false when the result has no hash or when the input,
+// policies or findings changed after the audit.
+//
+// Example:
+//
+//	result, _ := Audit(record, AuditOptions{Policies: policies, HashReport: true})
+//	// ... store and later reload result ...
+//	if !VerifyReport(result, record) {
+//	    // the report was modified
+//	}
+func VerifyReport[T any](result AuditResult[T], input T) bool {
+	if result.ReportHash == "" {
+		return false
+	}
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		return false
+	}
+	hash, err := auditReportHash(inputJSON, result.Policies, result.Findings)
+	return err == nil && hash == result.ReportHash
+}
+
+// defaultAuditOptions returns the options Audit runs with when none are given
+func defaultAuditOptions() AuditOptions {
+	return AuditOptions{
+		Threshold:    0.0, // Report everything
+		Deep:         true,
+		Mode:         types.TransformMode,
+		Intelligence: types.Smart,
+	}
+}
+
 // mergeAuditOptions merges user options with defaults
 func mergeAuditOptions(defaults, user AuditOptions) AuditOptions {
 	if user.Policies != nil {
 		defaults.Policies = user.Policies
 	}
+	if user.PolicySet != nil {
+		defaults.PolicySet = user.PolicySet
+	}
 	if user.Categories != nil {
 		defaults.Categories = user.Categories
 	}
+	if user.DatasetPolicies != nil {
+		defaults.DatasetPolicies = user.DatasetPolicies
+	}
 	if user.Threshold > 0 {
 		defaults.Threshold = user.Threshold
 	}
+	defaults.HashReport = user.HashReport
 	// Deep is a boolean, use explicit assignment
 	defaults.Deep = user.Deep
 	if user.Steering != "" {
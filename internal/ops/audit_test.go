@@ -0,0 +1,256 @@
+package ops
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+type auditCustomer struct {
+	Name string `json:"name"`
+	SSN  string `json:"ssn"`
+}
+
+func TestAuditCollection(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		if !strings.Contains(system, "No duplicate SSNs across records") {
+			t.Errorf("expected dataset policy in system prompt")
+		}
+		if strings.Count(user, "123-45-6789") == 2 {
+			return `{
+				"record_findings": [],
+				"dataset_findings": [{
+					"category": "consistency",
+					"severity": 0.9,
+					"field": "ssn",
+					"issue": "Duplicate SSN shared by multiple records",
+					"evidence": "123-45-6789",
+					"policy": "No duplicate SSNs across records",
+					"records": [0, 1, 7]
+				}]
+			}`, nil
+		}
+		return `{"record_findings": [], "dataset_findings": []}`, nil
+	})
+
+	records := []auditCustomer{
+		{Name: "Alice", SSN: "123-45-6789"},
+		{Name: "Bob", SSN: "123-45-6789"},
+	}
+
+	result, err := AuditCollection(records, AuditOptions{
+		DatasetPolicies: []string{"No duplicate SSNs across records"},
+	})
+	if err != nil {
+		t.Fatalf("AuditCollection failed: %v", err)
+	}
+
+	if len(result.DatasetFindings) != 1 {
+		t.Fatalf("expected 1 dataset finding, got %d", len(result.DatasetFindings))
+	}
+	finding := result.DatasetFindings[0]
+	if len(finding.Records) != 2 || finding.Records[0] != 0 || finding.Records[1] != 1 {
+		t.Errorf("expected out-of-range record indices to be dropped, got %v", finding.Records)
+	}
+	if result.Summary.PassesAudit {
+		t.Error("expected audit to fail with a critical dataset finding")
+	}
+	if result.Summary.TotalFindings != 1 {
+		t.Errorf("expected 1 total finding, got %d", result.Summary.TotalFindings)
+	}
+}
+
+func TestAuditCollectionEmpty(t *testing.T) {
+	if _, err := AuditCollection([]auditCustomer{}); err == nil {
+		t.Error("expected error for empty collection")
+	}
+}
+
+func writePolicyFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write policy file: %v", err)
+	}
+	return path
+}
+
+func TestLoadPolicies(t *testing.T) {
+	yamlPath := writePolicyFile(t, "policies.yaml", `policies:
+  - name: ssn-plaintext
+    rule: SSNs must not be stored in plain text
+    severity: 0.9
+    category: security
+    blocking: true
+  - rule: Names should be capitalized
+    severity: 0.2
+    category: quality
+`)
+	policies, err := LoadPolicies(yamlPath)
+	if err != nil {
+		t.Fatalf("LoadPolicies(yaml) failed: %v", err)
+	}
+	if len(policies) != 2 {
+		t.Fatalf("expected 2 policies, got %d", len(policies))
+	}
+	want := Policy{Name: "ssn-plaintext", Rule: "SSNs must not be stored in plain text", Severity: 0.9, Category: "security", Blocking: true}
+	if policies[0] != want {
+		t.Errorf("policies[0] = %+v, want %+v", policies[0], want)
+	}
+	if policies[1].Name != "policy-2" || policies[1].Blocking {
+		t.Errorf("expected unnamed non-blocking policy to be named policy-2, got %+v", policies[1])
+	}
+
+	jsonPath := writePolicyFile(t, "policies.json", `[{"name": "ssn-plaintext", "rule": "SSNs must not be stored in plain text", "severity": 0.9, "category": "security", "blocking": true}]`)
+	fromJSON, err := LoadPolicies(jsonPath)
+	if err != nil {
+		t.Fatalf("LoadPolicies(json) failed: %v", err)
+	}
+	if len(fromJSON) != 1 || fromJSON[0] != want {
+		t.Errorf("JSON policies = %+v, want [%+v]", fromJSON, want)
+	}
+
+	invalid := map[string]string{
+		"missing rule":   "- name: empty\n",
+		"bad severity":   "- name: x\n  rule: r\n  severity: 3\n",
+		"duplicate name": "- name: x\n  rule: a\n- name: x\n  rule: b\n",
+	}
+	for name, content := range invalid {
+		if _, err := LoadPolicies(writePolicyFile(t, "bad.yml", content)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if _, err := LoadPolicies(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestAuditWithLoadedPolicies(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	policies, err := LoadPolicies(writePolicyFile(t, "policies.yaml", `- name: ssn-plaintext
+  rule: SSNs must not be stored in plain text
+  severity: 0.4
+  category: security
+  blocking: true
+- name: name-case
+  rule: Names should be capitalized
+  severity: 0.2
+  category: quality
+`))
+	if err != nil {
+		t.Fatalf("LoadPolicies failed: %v", err)
+	}
+
+	var systemPrompt string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		systemPrompt = system
+		return `{"findings": [
+			{"issue": "SSN stored unmasked", "field": "ssn", "policy": "ssn-plaintext", "recommendation": "Tokenize the SSN"},
+			{"issue": "Name is lowercase", "field": "name", "policy": "Names should be capitalized"}
+		]}`, nil
+	})
+
+	result, err := Audit(auditCustomer{Name: "alice", SSN: "123-45-6789"}, AuditOptions{
+		PolicySet: policies,
+		Threshold: 0.5,
+	})
+	if err != nil {
+		t.Fatalf("Audit failed: %v", err)
+	}
+
+	for _, want := range []string{"[ssn-plaintext] SSNs must not be stored in plain text", "blocking", "[name-case] Names should be capitalized"} {
+		if !strings.Contains(systemPrompt, want) {
+			t.Errorf("expected system prompt to contain %q", want)
+		}
+	}
+
+	// The blocking finding survives the threshold; the non-blocking one does not
+	if len(result.Findings) != 1 {
+		t.Fatalf("expected 1 finding, got %+v", result.Findings)
+	}
+	f := result.Findings[0]
+	if f.Policy != "ssn-plaintext" || f.Category != "security" || f.Severity != 0.4 {
+		t.Errorf("expected finding to inherit policy attributes, got %+v", f)
+	}
+	if result.Summary.PassesAudit {
+		t.Error("expected blocking violation to fail the audit")
+	}
+	if len(result.Summary.BlockingViolations) != 1 || result.Summary.BlockingViolations[0] != "ssn-plaintext" {
+		t.Errorf("BlockingViolations = %v", result.Summary.BlockingViolations)
+	}
+
+	guard, err := GuardPolicies(auditCustomer{Name: "alice", SSN: "123-45-6789"}, policies)
+	if err != nil {
+		t.Fatalf("GuardPolicies failed: %v", err)
+	}
+	if guard.CanProceed || len(guard.FailedChecks) != 1 || !strings.HasPrefix(guard.FailedChecks[0], "ssn-plaintext:") {
+		t.Errorf("expected guard to block on ssn-plaintext, got %+v", guard)
+	}
+	if len(guard.Suggestions) != 1 || guard.Suggestions[0] != "Tokenize the SSN" {
+		t.Errorf("Suggestions = %v", guard.Suggestions)
+	}
+}
+
+func TestAuditReportHashDetectsModifiedFindings(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		return `{"findings": [{"category": "security", "severity": 0.8, "field": "ssn", "issue": "SSN stored in plain text", "policy": "PII must be encrypted"}]}`, nil
+	})
+
+	customer := auditCustomer{Name: "Alice", SSN: "123-45-6789"}
+	result, err := Audit(customer, AuditOptions{Policies: []string{"PII must be encrypted"}, HashReport: true})
+	if err != nil {
+		t.Fatalf("Audit failed: %v", err)
+	}
+	if len(result.ReportHash) != 64 {
+		t.Fatalf("expected a hex SHA-256 report hash, got %q", result.ReportHash)
+	}
+	if !VerifyReport(result, customer) {
+		t.Fatal("expected the unmodified report to verify")
+	}
+
+	// Editing a finding must change the hash and fail verification
+	tampered := result
+	tampered.Findings = append([]AuditFinding(nil), result.Findings...)
+	tampered.Findings[0].Severity = 0.1
+	hash, err := auditReportHash([]byte(`{"name":"Alice","ssn":"123-45-6789"}`), tampered.Policies, tampered.Findings)
+	if err != nil {
+		t.Fatalf("hashing failed: %v", err)
+	}
+	if hash == result.ReportHash {
+		t.Error("expected a modified finding to change the report hash")
+	}
+	if VerifyReport(tampered, customer) {
+		t.Error("expected VerifyReport to reject a modified finding")
+	}
+
+	// So must auditing different input or dropping a policy
+	if VerifyReport(result, auditCustomer{Name: "Alice", SSN: "000-00-0000"}) {
+		t.Error("expected VerifyReport to reject a different input")
+	}
+	tampered = result
+	tampered.Policies = nil
+	if VerifyReport(tampered, customer) {
+		t.Error("expected VerifyReport to reject removed policies")
+	}
+
+	// Reports audited without HashReport carry no hash and never verify
+	plain, err := Audit(customer, AuditOptions{Policies: []string{"PII must be encrypted"}})
+	if err != nil {
+		t.Fatalf("Audit failed: %v", err)
+	}
+	if plain.ReportHash != "" || VerifyReport(plain, customer) {
+		t.Error("expected no report hash without HashReport")
+	}
+}
@@ -3,9 +3,13 @@ package ops
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 	"sync"
 	"time"
 
@@ -30,25 +34,182 @@ type BatchProcessor struct {
 	maxConcurrent int
 	maxBatchSize  int
 	timeout       time.Duration
+
+	// Optional checkpointing so interrupted runs resume where they stopped
+	checkpointStore StateStore
+	checkpointRunID string
+
+	// Optional hard ceiling on estimated spend, in USD (0 means unlimited)
+	budgetUSD float64
+
+	// Optional AIMD bounds for ParallelMode concurrency
+	adaptive *AdaptiveConfig
+
+	// Optional key that keeps items of the same group in the same MergedMode call
+	groupBy func(item any) string
 }
 
-// BatchResult contains the results of a batch operation
+// BatchResult contains the results of a batch operation. Results and Errors
+// are aligned with the inputs: item i succeeded when Errors[i] is nil. In JSON
+// each error is stored as its message.
 type BatchResult[T any] struct {
 	Results  []T
 	Errors   []error
 	Metadata BatchMetadata
+
+	// retry reprocesses the inputs at indices for RetryFailures, returning
+	// results aligned with indices; nil when the batch cannot be rerun
+	retry func(indices []int, opts types.OpOptions) BatchResult[T]
+}
+
+// BatchError is the failure of one item of a batch
+type BatchError struct {
+	Index int // position of the item in the batch input
+	Err   error
+}
+
+func (e BatchError) Error() string {
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+func (e BatchError) Unwrap() error {
+	return e.Err
+}
+
+// All returns one result per input in input order, with the zero value for
+// failed items; use it where a plain result slice is expected
+func (r BatchResult[T]) All() []T {
+	return r.Results
+}
+
+// Successes returns the results of the items that succeeded, in input order
+func (r BatchResult[T]) Successes() []T {
+	successes := make([]T, 0, len(r.Results))
+	for i, result := range r.Results {
+		if i >= len(r.Errors) || r.Errors[i] == nil {
+			successes = append(successes, result)
+		}
+	}
+	return successes
+}
+
+// Failures returns the items that failed, in input order
+func (r BatchResult[T]) Failures() []BatchError {
+	var failures []BatchError
+	for i, err := range r.Errors {
+		if err != nil {
+			failures = append(failures, BatchError{Index: i, Err: err})
+		}
+	}
+	return failures
+}
+
+// SuccessRate returns the fraction of items that succeeded, or 0 for an
+// empty batch
+func (r BatchResult[T]) SuccessRate() float64 {
+	if len(r.Results) == 0 {
+		return 0
+	}
+	return float64(len(r.Results)-len(r.Failures())) / float64(len(r.Results))
+}
+
+// FirstError returns the failure of the earliest failed item as a
+// BatchError, or nil when every item succeeded
+func (r BatchResult[T]) FirstError() error {
+	for i, err := range r.Errors {
+		if err != nil {
+			return BatchError{Index: i, Err: err}
+		}
+	}
+	return nil
+}
+
+// RetryFailures reprocesses only the failed items with opts, which replace
+// the options of the first pass (raise Intelligence to retry with a stronger
+// model), and merges the new successes into a copy of r. Items that fail again
+// keep their new error. Metadata counts both passes; the result can itself be
+// retried. Retries spend from the same budget as the first pass, and with a
+// checkpoint store the recovered items are checkpointed like first-pass
+// successes. r is returned unchanged when nothing failed or it cannot be rerun,
+// such as a BatchResult decoded from JSON.
+//
+// Example:
+//
+//	result := ExtractBatch[Invoice](batch, inputs)
+//	if result.Metadata.Failed > 0 {
+//	    result = result.RetryFailures(types.OpOptions{Intelligence: types.Smart})
+//	}
+func (r BatchResult[T]) RetryFailures(opts types.OpOptions) BatchResult[T] {
+	failures := r.Failures()
+	if len(failures) == 0 || r.retry == nil {
+		return r
+	}
+	indices := make([]int, len(failures))
+	for i, failure := range failures {
+		indices[i] = failure.Index
+	}
+	pass := r.retry(indices, opts)
+
+	merged := BatchResult[T]{
+		Results:  append([]T(nil), r.Results...),
+		Errors:   append([]error(nil), r.Errors...),
+		Metadata: r.Metadata,
+		retry:    r.retry,
+	}
+	for k, idx := range indices {
+		if k >= len(pass.Errors) {
+			break
+		}
+		merged.Errors[idx] = pass.Errors[k]
+		if pass.Errors[k] == nil {
+			merged.Results[idx] = pass.Results[k]
+		}
+	}
+
+	meta := &merged.Metadata
+	meta.Failed = len(merged.Failures())
+	meta.Succeeded = len(merged.Results) - meta.Failed
+	meta.Duration += pass.Metadata.Duration
+	meta.TokensSaved += pass.Metadata.TokensSaved
+	meta.APICallsMade += pass.Metadata.APICallsMade
+	meta.EstimatedCost += pass.Metadata.EstimatedCost
+	meta.Remaining = nil
+	for _, idx := range pass.Metadata.Remaining {
+		if idx < len(indices) {
+			meta.Remaining = append(meta.Remaining, indices[idx])
+		}
+	}
+	meta.BudgetExhausted = len(meta.Remaining) > 0
+	if pass.Metadata.FinalConcurrency > 0 {
+		meta.FinalConcurrency = pass.Metadata.FinalConcurrency
+	}
+	return merged
 }
 
 // BatchMetadata provides metrics about the batch operation
 type BatchMetadata struct {
-	Mode          BatchMode
-	TotalItems    int
-	Succeeded     int
-	Failed        int
-	Duration      time.Duration
-	TokensSaved   int
-	APICallsMade  int
-	EstimatedCost float64
+	Mode          BatchMode     `json:"mode"`
+	TotalItems    int           `json:"total_items"`
+	Succeeded     int           `json:"succeeded"`
+	Failed        int           `json:"failed"`
+	Duration      time.Duration `json:"duration"` // nanoseconds in JSON
+	TokensSaved   int           `json:"tokens_saved"`
+	APICallsMade  int           `json:"api_calls_made"`
+	EstimatedCost float64       `json:"estimated_cost"`
+
+	// Resumed counts items restored from a checkpoint instead of reprocessed
+	Resumed int `json:"resumed,omitempty"`
+
+	// BudgetExhausted is true when items were skipped to stay within the budget
+	BudgetExhausted bool `json:"budget_exhausted,omitempty"`
+
+	// Remaining lists the indices of skipped items, in input order; their
+	// errors are types.ErrBudgetExhausted
+	Remaining []int `json:"remaining,omitempty"`
+
+	// FinalConcurrency is the ParallelMode concurrency limit when the batch
+	// ended; with adaptive concurrency it reflects the last adjustment
+	FinalConcurrency int `json:"final_concurrency,omitempty"`
 }
 
 // NewBatchProcessor creates a new batch processor for a given provider.
@@ -64,7 +225,7 @@ func NewBatchProcessor(provider llm.Provider) *BatchProcessor {
 
 // Global Batch function for backward compatibility
 func Batch() *BatchProcessor {
-	return NewBatchProcessor(defaultProvider)
+	return NewBatchProcessor(getDefaultProvider())
 }
 
 // WithMode sets the batch processing mode
@@ -79,22 +240,214 @@ func (batchProcessor *BatchProcessor) WithConcurrency(concurrency int) *BatchPro
 	return batchProcessor
 }
 
+// WithAdaptiveConcurrency lets ParallelMode adjust its concurrency between
+// cfg.Min and cfg.Max, starting from the WithConcurrency value: it grows by
+// one after each window of calls with stable latency and is cut
+// multiplicatively on rate-limit (429) errors or rising latency. The limit in
+// effect at the end is reported in Metadata.FinalConcurrency.
+func (batchProcessor *BatchProcessor) WithAdaptiveConcurrency(cfg AdaptiveConfig) *BatchProcessor {
+	batchProcessor.adaptive = &cfg
+	return batchProcessor
+}
+
 // WithBatchSize sets the maximum items per API call for MergedMode
 func (batchProcessor *BatchProcessor) WithBatchSize(size int) *BatchProcessor {
 	batchProcessor.maxBatchSize = size
 	return batchProcessor
 }
 
+// WithGroupBy makes MergedMode calls hold items of a single group, as named
+// by key, so items sharing context (such as the same customer) are extracted
+// together. Groups larger than the batch size span several calls. Parallel
+// mode makes one call per item and ignores grouping.
+func (batchProcessor *BatchProcessor) WithGroupBy(key func(item any) string) *BatchProcessor {
+	batchProcessor.groupBy = key
+	return batchProcessor
+}
+
 // WithTimeout sets the timeout for the batch operation
 func (batchProcessor *BatchProcessor) WithTimeout(timeout time.Duration) *BatchProcessor {
 	batchProcessor.timeout = timeout
 	return batchProcessor
 }
 
+// WithCheckpoint records each completed item's result in store under runID.
+// Re-running the same batch with the same runID skips items that already
+// succeeded and only processes the rest. Entries are keyed on the item's
+// position and a hash of its input, so an item whose input changed between
+// runs is processed again rather than restored.
+func (batchProcessor *BatchProcessor) WithCheckpoint(store StateStore, runID string) *BatchProcessor {
+	batchProcessor.checkpointStore = store
+	batchProcessor.checkpointRunID = runID
+	return batchProcessor
+}
+
+// WithBudgetUSD sets a hard ceiling on the batch's estimated cost. Before each
+// API call the provider's estimate is added to the running total; once the
+// next call would exceed max, no further items are started. Completed results
+// are returned with Metadata.BudgetExhausted and the skipped indices in
+// Metadata.Remaining. Zero disables the limit.
+func (batchProcessor *BatchProcessor) WithBudgetUSD(max float64) *BatchProcessor {
+	batchProcessor.budgetUSD = max
+	return batchProcessor
+}
+
+// WithOptions applies BatchOptions (mode, concurrency, batch size, budget,
+// grouping and checkpointing)
+func (batchProcessor *BatchProcessor) WithOptions(opts BatchOptions) *BatchProcessor {
+	switch opts.Mode {
+	case "merged":
+		batchProcessor.mode = MergedMode
+	case "sequential":
+		batchProcessor.mode = ParallelMode
+		opts.Concurrency = 1
+	case "parallel":
+		batchProcessor.mode = ParallelMode
+	}
+	if opts.Concurrency > 0 {
+		batchProcessor.maxConcurrent = opts.Concurrency
+	}
+	if opts.BatchSize > 0 {
+		batchProcessor.maxBatchSize = opts.BatchSize
+	}
+	batchProcessor.budgetUSD = opts.BudgetUSD
+	if opts.GroupBy != nil {
+		batchProcessor.groupBy = opts.GroupBy
+	}
+	if opts.CheckpointStore != nil {
+		batchProcessor.checkpointStore = opts.CheckpointStore
+		batchProcessor.checkpointRunID = opts.CheckpointRunID
+	}
+	return batchProcessor
+}
+
+// batchBudget tracks estimated spend against an optional USD ceiling
+type batchBudget struct {
+	limit    float64
+	spent    float64
+	provider llm.Provider
+}
+
+func (batchProcessor *BatchProcessor) newBudget() *batchBudget {
+	provider := batchProcessor.provider
+	if provider == nil {
+		provider = getDefaultProvider()
+	}
+	return &batchBudget{limit: batchProcessor.budgetUSD, provider: provider}
+}
+
+// reserve adds the estimated cost of a call to the running total, or reports
+// false without reserving when the call would exceed the limit
+func (budget *batchBudget) reserve(systemPrompt, userPrompt string, opts types.OpOptions) bool {
+	cost := 0.0
+	if budget.provider != nil {
+		cost = budget.provider.EstimateCost(buildCompletionRequest(budget.provider.Name(), systemPrompt, userPrompt, opts))
+	}
+	if budget.limit > 0 && budget.spent+cost > budget.limit {
+		return false
+	}
+	budget.spent += cost
+	return true
+}
+
 // ExtractBatch performs batch extraction based on the configured mode
 // Note: Go doesn't support type parameters on methods, so we use a function
 func ExtractBatch[T any](batchProcessor *BatchProcessor, inputs []interface{}, opts ...types.OpOptions) BatchResult[T] {
-	// Convert legacy OpOptions to ExtractOptions for compatibility
+	return extractBatch[T](batchProcessor, inputs, opts, nil)
+}
+
+// IndexedResult is one item of a batch delivered by ExtractBatchChan
+type IndexedResult[T any] struct {
+	Index  int // position of the item in the batch input
+	Result T
+	Err    error
+}
+
+// ExtractBatchChan runs ExtractBatch and delivers each item on the returned
+// channel as soon as it completes, in completion order rather than input
+// order, so callers can render early results. Every input is delivered
+// exactly once, failures included, and the channel is closed after the last.
+//
+// Example:
+//
+//	results := make([]Person, len(inputs))
+//	for item := range ExtractBatchChan[Person](batch, inputs) {
+//	    if item.Err == nil {
+//	        results[item.Index] = item.Result
+//	    }
+//	}
+func ExtractBatchChan[T any](batchProcessor *BatchProcessor, inputs []interface{}, opts ...types.OpOptions) <-chan IndexedResult[T] {
+	out := make(chan IndexedResult[T], len(inputs))
+	go func() {
+		defer close(out)
+
+		var mu sync.Mutex
+		delivered := make([]bool, len(inputs))
+		deliver := func(idx int, result T, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if !delivered[idx] {
+				delivered[idx] = true
+				out <- IndexedResult[T]{Index: idx, Result: result, Err: err}
+			}
+		}
+
+		batch := extractBatch[T](batchProcessor, inputs, opts, deliver)
+
+		// Items that never ran (budget stops, failed merged calls) complete here
+		for i := range inputs {
+			deliver(i, batch.Results[i], batch.Errors[i])
+		}
+	}()
+	return out
+}
+
+// extractBatch implements ExtractBatch; onDone, when set, is called as each
+// item completes with its index in inputs
+func extractBatch[T any](batchProcessor *BatchProcessor, inputs []interface{}, opts []types.OpOptions, onDone func(idx int, result T, err error)) BatchResult[T] {
+	extractOpts := batchExtractOptions(opts)
+
+	// Every item's provider request carries the run ID; checkpointed runs
+	// reuse theirs so resumed attempts correlate with the original
+	runCtx, runID := contextWithRunID(extractOpts.GetContext(), batchProcessor.checkpointRunID)
+	extractOpts = extractOpts.WithContext(runCtx)
+
+	// Retry passes draw on the same budget, so the ceiling covers the run
+	budget := batchProcessor.newBudget()
+
+	var result BatchResult[T]
+	if batchProcessor.checkpointStore != nil {
+		result = extractCheckpointed[T](batchProcessor, inputs, extractOpts, budget, onDone)
+	} else {
+		result = extractWithMode[T](batchProcessor, inputs, extractOpts, nil, budget, onDone)
+	}
+	result.retry = func(indices []int, retryOpts types.OpOptions) BatchResult[T] {
+		subset := make([]interface{}, len(indices))
+		for k, idx := range indices {
+			subset[k] = inputs[idx]
+		}
+		// The retry pass shares the run ID, and its recovered items are
+		// checkpointed under their positions in the full batch
+		retryExtract := batchExtractOptions([]types.OpOptions{retryOpts})
+		retryCtx, _ := contextWithRunID(retryExtract.GetContext(), runID)
+		retryExtract = retryExtract.WithContext(retryCtx)
+		if batchProcessor.checkpointStore == nil {
+			return extractWithMode[T](batchProcessor, subset, retryExtract, indices, budget, nil)
+		}
+		save, saveErr := checkpointSaver[T](batchProcessor, retryCtx, inputs, indices, nil)
+		pass := extractWithMode[T](batchProcessor, subset, retryExtract, indices, budget, save)
+		for k, idx := range indices {
+			if pass.Errors[k] == nil && saveErr(idx) != nil {
+				pass.Errors[k] = saveErr(idx)
+			}
+		}
+		return pass
+	}
+	return result
+}
+
+// batchExtractOptions converts legacy OpOptions to ExtractOptions for compatibility
+func batchExtractOptions(opts []types.OpOptions) ExtractOptions {
 	var extractOpts ExtractOptions
 	if len(opts) > 0 {
 		converted := ConvertOpOptions(opts[0], "extract")
@@ -106,45 +459,196 @@ func ExtractBatch[T any](batchProcessor *BatchProcessor, inputs []interface{}, o
 	} else {
 		extractOpts = NewExtractOptions()
 	}
+	return extractOpts
+}
 
+// extractWithMode dispatches to the configured batch mode. positions, when
+// set, maps each input to its index in the caller's batch for request
+// metadata. onDone, when set, is called as each item completes.
+func extractWithMode[T any](batchProcessor *BatchProcessor, inputs []interface{}, opts ExtractOptions, positions []int, budget *batchBudget, onDone func(idx int, result T, err error)) BatchResult[T] {
 	switch batchProcessor.mode {
 	case MergedMode:
-		return extractMerged[T](batchProcessor, inputs, extractOpts)
+		return extractMerged[T](batchProcessor, inputs, opts, budget, onDone)
 	default:
-		return extractParallel[T](batchProcessor, inputs, extractOpts)
+		return extractParallel[T](batchProcessor, inputs, opts, positions, budget, onDone)
+	}
+}
+
+// extractCheckpointed restores completed items from the checkpoint store,
+// processes only the remaining ones, and records each new success. onDone,
+// when set, is called for restored items first and then as items complete.
+func extractCheckpointed[T any](batchProcessor *BatchProcessor, inputs []interface{}, opts ExtractOptions, budget *batchBudget, onDone func(idx int, result T, err error)) BatchResult[T] {
+	startTime := time.Now()
+	store := batchProcessor.checkpointStore
+	ctx := opts.GetContext()
+
+	results := make([]T, len(inputs))
+	errors := make([]error, len(inputs))
+	var pending []int
+	resumed := 0
+
+	for i := range inputs {
+		data, found, err := store.Load(ctx, checkpointKey(batchProcessor.checkpointRunID, i, inputs[i]))
+		if err != nil {
+			errors[i] = fmt.Errorf("failed to load checkpoint: %w", err)
+			continue
+		}
+		if found && json.Unmarshal(data, &results[i]) == nil {
+			resumed++
+			if onDone != nil {
+				onDone(i, results[i], nil)
+			}
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	pendingInputs := make([]interface{}, len(pending))
+	for j, idx := range pending {
+		pendingInputs[j] = inputs[idx]
+	}
+
+	save, saveErr := checkpointSaver[T](batchProcessor, ctx, inputs, pending, onDone)
+	sub := extractWithMode[T](batchProcessor, pendingInputs, opts, pending, budget, save)
+
+	for j, idx := range pending {
+		results[idx] = sub.Results[j]
+		errors[idx] = sub.Errors[j]
+		if errors[idx] == nil && saveErr(idx) != nil {
+			errors[idx] = saveErr(idx)
+		}
+	}
+
+	succeeded := 0
+	for _, err := range errors {
+		if err == nil {
+			succeeded++
+		}
+	}
+
+	metadata := sub.Metadata
+	if len(sub.Metadata.Remaining) > 0 {
+		metadata.Remaining = make([]int, len(sub.Metadata.Remaining))
+		for k, j := range sub.Metadata.Remaining {
+			metadata.Remaining[k] = pending[j]
+		}
+	}
+	metadata.TotalItems = len(inputs)
+	metadata.Succeeded = succeeded
+	metadata.Failed = len(inputs) - succeeded
+	metadata.Duration = time.Since(startTime)
+	metadata.Resumed = resumed
+
+	return BatchResult[T]{
+		Results:  results,
+		Errors:   errors,
+		Metadata: metadata,
+	}
+}
+
+// checkpointSaver returns the onDone of a pass over the batch items at
+// positions: each success is saved under its position in the full batch
+// before onDone, when set, sees it. saveErr reports the save failure of a
+// position once the pass is done.
+func checkpointSaver[T any](batchProcessor *BatchProcessor, ctx context.Context, inputs []interface{}, positions []int, onDone func(idx int, result T, err error)) (save func(j int, result T, err error), saveErr func(idx int) error) {
+	var mu sync.Mutex
+	failed := make(map[int]error)
+	save = func(j int, result T, err error) {
+		idx := positions[j]
+		if err == nil {
+			data, marshalErr := json.Marshal(result)
+			if marshalErr == nil {
+				marshalErr = batchProcessor.checkpointStore.Save(ctx, checkpointKey(batchProcessor.checkpointRunID, idx, inputs[idx]), data)
+			}
+			if marshalErr != nil {
+				err = fmt.Errorf("failed to save checkpoint: %w", marshalErr)
+				mu.Lock()
+				failed[idx] = err
+				mu.Unlock()
+			}
+		}
+		if onDone != nil {
+			onDone(idx, result, err)
+		}
+	}
+	saveErr = func(idx int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return failed[idx]
+	}
+	return save, saveErr
+}
+
+// checkpointKey names the checkpoint of the item at idx; the input hash keeps
+// a rerun with edited inputs from restoring results of the old ones
+func checkpointKey(runID string, idx int, input interface{}) string {
+	normalized, err := NormalizeInput(input)
+	if err != nil {
+		normalized = fmt.Sprintf("%#v", input)
 	}
+	sum := sha256.Sum256([]byte(normalized))
+	return fmt.Sprintf("batch/%s/%d/%s", runID, idx, hex.EncodeToString(sum[:12]))
 }
 
 // extractParallel processes items concurrently with separate API calls
-func extractParallel[T any](batchProcessor *BatchProcessor, inputs []interface{}, opts ExtractOptions) BatchResult[T] {
+func extractParallel[T any](batchProcessor *BatchProcessor, inputs []interface{}, opts ExtractOptions, positions []int, budget *batchBudget, onDone func(idx int, result T, err error)) BatchResult[T] {
 	startTime := time.Now()
 	results := make([]T, len(inputs))
 	errors := make([]error, len(inputs))
 
-	// Semaphore for concurrency control
-	semaphore := make(chan struct{}, batchProcessor.maxConcurrent)
+	limiter := newConcurrencyLimiter(batchProcessor.maxConcurrent, batchProcessor.adaptive)
 	var wg sync.WaitGroup
 
-	ctx, cancel := context.WithTimeout(context.Background(), batchProcessor.timeout)
+	ctx := opts.CommonOptions.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, batchProcessor.timeout)
 	defer cancel()
 
 	apiCalls := 0
 	var apiCallsMu sync.Mutex
 
+	// Items are admitted in input order so a budget stop leaves a clean tail
+	spentBefore := budget.spent
+	opOptions := opts.toOpOptions()
+	var systemPrompt string
+	if budget.limit > 0 {
+		var sample T
+		systemPrompt = BuildExtractSystemPrompt(GenerateTypeSchema(reflect.TypeOf(sample)), opOptions.Mode)
+	}
+	var remaining []int
+
 	for i, input := range inputs {
+		if budget.limit > 0 {
+			inputStr, _ := NormalizeInput(input)
+			if remaining != nil || !budget.reserve(systemPrompt, "Extract structured data from this input:\n"+inputStr, opOptions) {
+				remaining = append(remaining, i)
+				errors[i] = types.ErrBudgetExhausted
+				continue
+			}
+		}
+
 		wg.Add(1)
 		go func(idx int, input interface{}) {
 			defer wg.Done()
 
-			select {
-			case semaphore <- struct{}{}:
-				defer func() { <-semaphore }()
-			case <-ctx.Done():
+			if !limiter.acquire(ctx) {
 				errors[idx] = ctx.Err()
 				return
 			}
 
-			result, err := Extract[T](input, opts)
+			position := idx
+			if positions != nil {
+				position = positions[idx]
+			}
+			itemOpts := opts.WithContext(ContextWithRequestMetadata(opts.GetContext(), map[string]string{
+				llm.MetadataBatchItem: strconv.Itoa(position),
+			}))
+
+			started := time.Now()
+			result, err := Extract[T](input, itemOpts)
+			limiter.release(time.Since(started), err)
 			if err == nil {
 				results[idx] = result
 				apiCallsMu.Lock()
@@ -153,6 +657,9 @@ func extractParallel[T any](batchProcessor *BatchProcessor, inputs []interface{}
 			} else {
 				errors[idx] = err
 			}
+			if onDone != nil {
+				onDone(idx, result, err)
+			}
 		}(i, input)
 	}
 
@@ -170,30 +677,55 @@ func extractParallel[T any](batchProcessor *BatchProcessor, inputs []interface{}
 		Results: results,
 		Errors:  errors,
 		Metadata: BatchMetadata{
-			Mode:         ParallelMode,
-			TotalItems:   len(inputs),
-			Succeeded:    succeeded,
-			Failed:       len(inputs) - succeeded,
-			Duration:     time.Since(startTime),
-			APICallsMade: apiCalls,
+			Mode:             ParallelMode,
+			TotalItems:       len(inputs),
+			Succeeded:        succeeded,
+			Failed:           len(inputs) - succeeded,
+			Duration:         time.Since(startTime),
+			APICallsMade:     apiCalls,
+			EstimatedCost:    budget.spent - spentBefore,
+			BudgetExhausted:  len(remaining) > 0,
+			Remaining:        remaining,
+			FinalConcurrency: limiter.current(),
 		},
 	}
 }
 
-// extractMerged combines multiple items into fewer API calls
-func extractMerged[T any](batchProcessor *BatchProcessor, inputs []interface{}, opts ExtractOptions) BatchResult[T] {
+// extractMerged combines multiple items into fewer API calls. With a group
+// key function, each call holds items of a single group.
+func extractMerged[T any](batchProcessor *BatchProcessor, inputs []interface{}, opts ExtractOptions, budget *batchBudget, onDone func(idx int, result T, err error)) BatchResult[T] {
 	startTime := time.Now()
-	var allResults []T
-	var allErrors []error
+	allResults := make([]T, len(inputs))
+	allErrors := make([]error, len(inputs))
 	apiCalls := 0
 	tokensSaved := 0
 
+	spentBefore := budget.spent
+	var remaining []int
+	skip := func(chunk []int) {
+		for _, idx := range chunk {
+			remaining = append(remaining, idx)
+			allErrors[idx] = types.ErrBudgetExhausted
+		}
+	}
+
 	// Process in chunks
-	chunks := batchProcessor.createChunks(inputs, batchProcessor.maxBatchSize)
+	for _, chunk := range batchProcessor.mergedChunks(inputs) {
+		if remaining != nil {
+			skip(chunk)
+			continue
+		}
+
+		items := make([]interface{}, len(chunk))
+		for i, idx := range chunk {
+			items[i] = inputs[idx]
+		}
 
-	for _, chunk := range chunks {
 		// Create merged prompt
-		mergedPrompt := batchProcessor.createMergedExtractPrompt(chunk)
+		mergedPrompt := batchProcessor.createMergedExtractPrompt(items)
+		if batchProcessor.groupBy != nil {
+			mergedPrompt = fmt.Sprintf("These items all belong to group %q and share its context.\n\n%s", batchProcessor.groupBy(items[0]), mergedPrompt)
+		}
 
 		// Get type information for response parsing
 		var sample T
@@ -207,23 +739,31 @@ Output JSON array where each element matches this schema:
 Return format: [{"index": 0, "data": {...}}, {"index": 1, "data": {...}}, ...]`, typeInfo)
 
 		opOptions := opts.toOpOptions()
-		ctx, cancel := context.WithTimeout(context.Background(), batchProcessor.timeout)
+		opOptions.Operation = "extract"
+		if budget.limit > 0 && !budget.reserve(systemPrompt, mergedPrompt, opOptions) {
+			skip(chunk)
+			continue
+		}
+		ctx := opts.CommonOptions.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		ctx, cancel := context.WithTimeout(ctx, batchProcessor.timeout)
 
 		// Use provider from batchProcessor if available, otherwise default
 		var response string
-		var err error
-		if batchProcessor.provider != nil {
-			response, err = CallLLM(ctx, batchProcessor.provider, systemPrompt, mergedPrompt, opOptions)
-		} else {
-			response, err = callLLM(ctx, systemPrompt, mergedPrompt, opOptions)
+		guardedSystem, guardedPrompt, err := guardPrompts(systemPrompt, mergedPrompt, opOptions)
+		if err == nil && batchProcessor.provider != nil {
+			response, err = CallLLM(ctx, batchProcessor.provider, guardedSystem, guardedPrompt, opOptions)
+		} else if err == nil {
+			response, err = sendLLM(ctx, guardedSystem, guardedPrompt, opOptions)
 		}
 		cancel()
 
 		if err != nil {
 			// Add error for each item in chunk
-			for range chunk {
-				allErrors = append(allErrors, err)
-				allResults = append(allResults, *new(T))
+			for _, idx := range chunk {
+				allErrors[idx] = err
 			}
 			continue
 		}
@@ -232,12 +772,18 @@ Return format: [{"index": 0, "data": {...}}, {"index": 1, "data": {...}}, ...]`,
 
 		// Parse merged response
 		results, parseErrors := parseMergedResponse[T](response, len(chunk))
-		allResults = append(allResults, results...)
-		allErrors = append(allErrors, parseErrors...)
+		for i, idx := range chunk {
+			allResults[idx] = results[i]
+			allErrors[idx] = parseErrors[i]
+			if onDone != nil {
+				onDone(idx, results[i], parseErrors[i])
+			}
+		}
 
 		// Estimate tokens saved (rough calculation)
 		tokensSaved += (len(chunk) - 1) * 100 // Approximate overhead per call
 	}
+	sort.Ints(remaining)
 
 	// Calculate metadata
 	succeeded := 0
@@ -251,18 +797,63 @@ Return format: [{"index": 0, "data": {...}}, {"index": 1, "data": {...}}, ...]`,
 		Results: allResults,
 		Errors:  allErrors,
 		Metadata: BatchMetadata{
-			Mode:          MergedMode,
-			TotalItems:    len(inputs),
-			Succeeded:     succeeded,
-			Failed:        len(inputs) - succeeded,
-			Duration:      time.Since(startTime),
-			TokensSaved:   tokensSaved,
-			APICallsMade:  apiCalls,
-			EstimatedCost: float64(apiCalls) * 0.01, // Rough estimate
+			Mode:            MergedMode,
+			TotalItems:      len(inputs),
+			Succeeded:       succeeded,
+			Failed:          len(inputs) - succeeded,
+			Duration:        time.Since(startTime),
+			TokensSaved:     tokensSaved,
+			APICallsMade:    apiCalls,
+			EstimatedCost:   mergedCostEstimate(budget.spent-spentBefore, budget.limit, apiCalls),
+			BudgetExhausted: len(remaining) > 0,
+			Remaining:       remaining,
 		},
 	}
 }
 
+// mergedChunks splits the indices of inputs into the item sets of merged
+// calls, at most maxBatchSize each. With a group key function, groups are
+// chunked separately in order of first appearance, so no call mixes groups.
+func (batchProcessor *BatchProcessor) mergedChunks(inputs []interface{}) [][]int {
+	var groups [][]int
+	if batchProcessor.groupBy == nil {
+		all := make([]int, len(inputs))
+		for i := range all {
+			all[i] = i
+		}
+		groups = [][]int{all}
+	} else {
+		position := make(map[string]int)
+		for i, input := range inputs {
+			key := batchProcessor.groupBy(input)
+			g, ok := position[key]
+			if !ok {
+				g = len(groups)
+				position[key] = g
+				groups = append(groups, nil)
+			}
+			groups[g] = append(groups[g], i)
+		}
+	}
+
+	var chunks [][]int
+	for _, group := range groups {
+		for start := 0; start < len(group); start += batchProcessor.maxBatchSize {
+			chunks = append(chunks, group[start:min(start+batchProcessor.maxBatchSize, len(group))])
+		}
+	}
+	return chunks
+}
+
+// mergedCostEstimate prefers the provider estimate of a pass's spend, tracked
+// when a budget limit is set, and falls back to a rough per-call figure
+func mergedCostEstimate(spent, limit float64, apiCalls int) float64 {
+	if limit > 0 {
+		return spent
+	}
+	return float64(apiCalls) * 0.01 // Rough estimate
+}
+
 // createChunks splits inputs into chunks of specified size
 func (batchProcessor *BatchProcessor) createChunks(inputs []interface{}, chunkSize int) [][]interface{} {
 	var chunks [][]interface{}
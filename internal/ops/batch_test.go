@@ -1,8 +1,17 @@
 package ops
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/monstercameron/schemaflow/internal/llm"
+	"github.com/monstercameron/schemaflow/internal/types"
 )
 
 func TestBatchOperations(t *testing.T) {
@@ -149,6 +158,156 @@ func TestBatchMetadata(t *testing.T) {
 	}
 }
 
+func TestExtractBatchCheckpointResume(t *testing.T) {
+	defer setupMockClient()
+
+	inputs := []interface{}{"Alice, 30", "Bob, 40", "Carol, 50", "Dave, 60"}
+	store := NewMemoryStateStore()
+
+	var mu sync.Mutex
+	var processed []string
+	failCarol := true
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, input := range inputs {
+			name := strings.Split(input.(string), ",")[0]
+			if !strings.Contains(user, name) {
+				continue
+			}
+			processed = append(processed, name)
+			if name == "Carol" && failCarol {
+				return "", errors.New("upstream unavailable")
+			}
+			return `{"name": "` + name + `", "age": 1}`, nil
+		}
+		return "", errors.New("unexpected input")
+	})
+
+	batch := NewBatchProcessor(nil).WithConcurrency(2).WithCheckpoint(store, "run-1")
+
+	first := ExtractBatch[Person](batch, inputs)
+	if first.Metadata.Succeeded != 3 || first.Errors[2] == nil {
+		t.Fatalf("first run: succeeded = %d, errors = %v; want 3 successes and Carol failing", first.Metadata.Succeeded, first.Errors)
+	}
+
+	processed = nil
+	failCarol = false
+
+	second := ExtractBatch[Person](batch, inputs)
+	if len(processed) != 1 || processed[0] != "Carol" {
+		t.Errorf("second run processed %v, want only [Carol]", processed)
+	}
+	if second.Metadata.Resumed != 3 || second.Metadata.Succeeded != 4 {
+		t.Errorf("second run: resumed = %d, succeeded = %d; want 3 and 4", second.Metadata.Resumed, second.Metadata.Succeeded)
+	}
+	for i, want := range []string{"Alice", "Bob", "Carol", "Dave"} {
+		if second.Results[i].Name != want {
+			t.Errorf("Results[%d].Name = %q, want %q", i, second.Results[i].Name, want)
+		}
+	}
+}
+
+func TestExtractBatchCheckpointReprocessesChangedInputs(t *testing.T) {
+	defer setupMockClient()
+
+	var mu sync.Mutex
+	var processed []string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, name := range []string{"Alice", "Bob", "Carol", "Erin"} {
+			if strings.Contains(user, name) {
+				processed = append(processed, name)
+				return `{"name": "` + name + `", "age": 1}`, nil
+			}
+		}
+		return "", errors.New("unexpected input")
+	})
+
+	store := NewMemoryStateStore()
+	batch := NewBatchProcessor(nil).WithOptions(NewBatchOptions().WithConcurrency(1).WithCheckpoint(store, "run-1"))
+
+	ExtractBatch[Person](batch, []interface{}{"Alice, 30", "Bob, 40", "Carol, 50"})
+	processed = nil
+
+	second := ExtractBatch[Person](batch, []interface{}{"Alice, 30", "Erin, 41", "Carol, 50"})
+	if len(processed) != 1 || processed[0] != "Erin" {
+		t.Errorf("second run processed %v, want only the edited item [Erin]", processed)
+	}
+	if second.Metadata.Resumed != 2 || second.Results[1].Name != "Erin" {
+		t.Errorf("second run: resumed = %d, Results[1] = %+v; want 2 resumed and Erin", second.Metadata.Resumed, second.Results[1])
+	}
+}
+
+func TestExtractBatchChanDeliversItemsAsTheyComplete(t *testing.T) {
+	defer setupMockClient()
+
+	inputs := []interface{}{"Alice, 30", "Bob, 40", "Carol, 50", "Dave, 60"}
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		for _, input := range inputs {
+			name := strings.Split(input.(string), ",")[0]
+			if !strings.Contains(user, name) {
+				continue
+			}
+			switch name {
+			case "Alice":
+				time.Sleep(100 * time.Millisecond)
+			case "Carol":
+				return "", errors.New("upstream unavailable")
+			}
+			return `{"name": "` + name + `", "age": 1}`, nil
+		}
+		return "", errors.New("unexpected input")
+	})
+
+	batch := NewBatchProcessor(nil).WithConcurrency(len(inputs))
+
+	var order []int
+	results := make([]Person, len(inputs))
+	errs := make([]error, len(inputs))
+	for item := range ExtractBatchChan[Person](batch, inputs) {
+		order = append(order, item.Index)
+		results[item.Index] = item.Result
+		errs[item.Index] = item.Err
+	}
+
+	if len(order) != len(inputs) {
+		t.Fatalf("received %d items, want %d: %v", len(order), len(inputs), order)
+	}
+	seen := make(map[int]bool)
+	for _, idx := range order {
+		if seen[idx] {
+			t.Errorf("index %d delivered twice", idx)
+		}
+		seen[idx] = true
+	}
+	if order[len(order)-1] != 0 {
+		t.Errorf("expected the slow first item to arrive last, got order %v", order)
+	}
+
+	for i, want := range []string{"Alice", "Bob", "", "Dave"} {
+		if results[i].Name != want {
+			t.Errorf("results[%d].Name = %q, want %q", i, results[i].Name, want)
+		}
+	}
+	if errs[2] == nil || errs[0] != nil || errs[1] != nil || errs[3] != nil {
+		t.Errorf("expected only Carol to fail, got %v", errs)
+	}
+
+	// Items skipped by the budget are still delivered, with their error
+	budgeted := NewBatchProcessor(&pricedProvider{costPerCall: 1}).WithBudgetUSD(2.5)
+	var skipped int
+	for item := range ExtractBatchChan[Person](budgeted, []interface{}{"Alice, 30", "Bob, 40", "Dave, 60"}) {
+		if errors.Is(item.Err, types.ErrBudgetExhausted) {
+			skipped++
+		}
+	}
+	if skipped != 1 {
+		t.Errorf("expected 1 budget-skipped item, got %d", skipped)
+	}
+}
+
 // Benchmark batch operations
 func BenchmarkBatchParallel(b *testing.B) {
 	setupMockClient()
@@ -179,3 +338,435 @@ func BenchmarkBatchMerged(b *testing.B) {
 		_ = ExtractBatch[Person](batch, inputs)
 	}
 }
+
+// pricedProvider reports a fixed estimated cost per call
+type pricedProvider struct {
+	captureProvider
+	costPerCall float64
+}
+
+func (p *pricedProvider) EstimateCost(req llm.CompletionRequest) float64 {
+	return p.costPerCall
+}
+
+func TestExtractBatchStopsAtBudget(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	var calls int
+	var mu sync.Mutex
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		return `{"Name": "Someone", "Age": 30}`, nil
+	})
+
+	inputs := []interface{}{"Ada, 36", "Bob, 41", "Cy, 29", "Di, 52", "Ed, 33"}
+	batch := NewBatchProcessor(&pricedProvider{costPerCall: 0.01}).
+		WithOptions(NewBatchOptions().WithBudgetUSD(0.025))
+
+	result := ExtractBatch[Person](batch, inputs)
+
+	if !result.Metadata.BudgetExhausted {
+		t.Fatal("expected budget to be exhausted")
+	}
+	if calls != 2 || result.Metadata.Succeeded != 2 {
+		t.Errorf("expected 2 items processed within budget, got %d calls and %d successes", calls, result.Metadata.Succeeded)
+	}
+	want := []int{2, 3, 4}
+	if len(result.Metadata.Remaining) != len(want) {
+		t.Fatalf("Remaining = %v, want %v", result.Metadata.Remaining, want)
+	}
+	for i, idx := range want {
+		if result.Metadata.Remaining[i] != idx {
+			t.Fatalf("Remaining = %v, want %v", result.Metadata.Remaining, want)
+		}
+		if !errors.Is(result.Errors[idx], types.ErrBudgetExhausted) {
+			t.Errorf("item %d error = %v, want ErrBudgetExhausted", idx, result.Errors[idx])
+		}
+	}
+	if result.Results[0].Name != "Someone" || result.Results[1].Name != "Someone" {
+		t.Errorf("expected completed results to be returned, got %+v", result.Results[:2])
+	}
+	if result.Metadata.EstimatedCost < 0.0199 || result.Metadata.EstimatedCost > 0.0201 {
+		t.Errorf("EstimatedCost = %v, want 0.02", result.Metadata.EstimatedCost)
+	}
+}
+
+func TestExtractBatchAdaptiveConcurrencyBacksOffOn429(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	var calls, inFlight, peak int
+	var mu sync.Mutex
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		mu.Lock()
+		calls++
+		call := calls
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+
+		time.Sleep(2 * time.Millisecond)
+		if call <= 4 {
+			return "", fmt.Errorf("rate limit exceeded: status 429")
+		}
+		return `{"Name": "Someone", "Age": 30}`, nil
+	})
+
+	inputs := make([]interface{}, 24)
+	for i := range inputs {
+		inputs[i] = fmt.Sprintf("Person %d, 30", i)
+	}
+	batch := NewBatchProcessor(nil).
+		WithConcurrency(8).
+		WithAdaptiveConcurrency(AdaptiveConfig{Min: 1, Max: 8})
+
+	result := ExtractBatch[Person](batch, inputs)
+
+	if result.Metadata.Failed != 4 {
+		t.Errorf("expected the 4 throttled items to fail, got %d failures", result.Metadata.Failed)
+	}
+	if result.Metadata.FinalConcurrency >= 8 {
+		t.Errorf("expected concurrency to drop below 8 after 429s, got %d", result.Metadata.FinalConcurrency)
+	}
+	if result.Metadata.FinalConcurrency < 1 {
+		t.Errorf("expected concurrency to stay at or above Min, got %d", result.Metadata.FinalConcurrency)
+	}
+	if peak > 8 {
+		t.Errorf("expected at most 8 concurrent calls, saw %d", peak)
+	}
+
+	fixed := ExtractBatch[Person](NewBatchProcessor(nil).WithConcurrency(3), inputs[:3])
+	if fixed.Metadata.FinalConcurrency != 3 {
+		t.Errorf("expected fixed concurrency to be reported unchanged, got %d", fixed.Metadata.FinalConcurrency)
+	}
+}
+
+func TestAdaptiveConcurrencyRecoversFromPermanentLatencyShift(t *testing.T) {
+	limiter := newConcurrencyLimiter(8, &AdaptiveConfig{Min: 1, Max: 8})
+	call := func(latency time.Duration) {
+		limiter.acquire(context.Background())
+		limiter.release(latency, nil)
+	}
+	for range 20 {
+		call(10 * time.Millisecond)
+	}
+
+	// Latency triples for good: one cut, then the baseline catches up and
+	// the limit ramps back instead of sinking to Min
+	lowest := limiter.current()
+	for range 200 {
+		call(30 * time.Millisecond)
+		lowest = min(lowest, limiter.current())
+	}
+	if lowest < 6 {
+		t.Errorf("limit fell to %d, want a single cut to 6", lowest)
+	}
+	if got := limiter.current(); got != 8 {
+		t.Errorf("limit = %d after the shift, want it back at Max 8", got)
+	}
+
+	// A burst of rate limits from calls already in flight cuts only once
+	rateLimited := fmt.Errorf("rate limit exceeded: status 429")
+	for range 4 {
+		limiter.acquire(context.Background())
+		limiter.release(30*time.Millisecond, rateLimited)
+	}
+	if got := limiter.current(); got != 4 {
+		t.Errorf("limit = %d after a burst of 429s, want one halving to 4", got)
+	}
+}
+
+func TestBatchResultHelpersOverMixedBatch(t *testing.T) {
+	defer setupMockClient()
+	errUnreadable := errors.New("unreadable input")
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		if strings.Contains(user, "smudged") {
+			return "", errUnreadable
+		}
+		for _, name := range []string{"Ada", "Grace"} {
+			if strings.Contains(user, name) {
+				return fmt.Sprintf(`{"name":%q,"age":30}`, name), nil
+			}
+		}
+		return `{}`, nil
+	})
+
+	inputs := []interface{}{"Ada is 30", "smudged card", "Grace is 30", "smudged form"}
+	result := ExtractBatch[Person](NewBatchProcessor(nil).WithConcurrency(1), inputs)
+
+	successes := result.Successes()
+	if len(successes) != 2 || successes[0].Name != "Ada" || successes[1].Name != "Grace" {
+		t.Errorf("Successes() = %+v, want Ada and Grace in input order", successes)
+	}
+
+	failures := result.Failures()
+	if len(failures) != 2 || failures[0].Index != 1 || failures[1].Index != 3 {
+		t.Fatalf("Failures() = %+v, want items 1 and 3", failures)
+	}
+	if !errors.Is(failures[0], errUnreadable) {
+		t.Errorf("Failures()[0] = %v, want it to wrap the item error", failures[0])
+	}
+
+	if rate := result.SuccessRate(); rate != 0.5 {
+		t.Errorf("SuccessRate() = %v, want 0.5", rate)
+	}
+
+	var first BatchError
+	if err := result.FirstError(); !errors.As(err, &first) || first.Index != 1 || !errors.Is(err, errUnreadable) {
+		t.Errorf("FirstError() = %v, want item 1 wrapping the item error", err)
+	}
+
+	if all := result.All(); len(all) != len(inputs) || all[2].Name != "Grace" || all[1].Name != "" {
+		t.Errorf("All() = %+v, want one entry per input with zero values for failures", all)
+	}
+
+	var empty BatchResult[Person]
+	if empty.SuccessRate() != 0 || empty.FirstError() != nil || empty.Failures() != nil {
+		t.Error("empty batch should report no failures and a zero success rate")
+	}
+}
+
+func TestExtractBatchMergedGroupsItemsByKey(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	var calls []string
+	var mu sync.Mutex
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		mu.Lock()
+		calls = append(calls, user)
+		mu.Unlock()
+		var items []string
+		for i := 0; strings.Contains(user, fmt.Sprintf("Item %d:", i)); i++ {
+			items = append(items, fmt.Sprintf(`{"index": %d, "data": {"Name": "Someone", "Age": 30}}`, i))
+		}
+		return "[" + strings.Join(items, ",") + "]", nil
+	})
+
+	inputs := []interface{}{"acme: Ada, 36", "globex: Bob, 41", "acme: Cy, 29", "initech: Di, 52", "globex: Ed, 33"}
+	customer := func(item any) string {
+		return strings.SplitN(item.(string), ":", 2)[0]
+	}
+	batch := NewBatchProcessor(nil).
+		WithOptions(NewBatchOptions().WithGroupBy(customer)).
+		WithMode(MergedMode)
+
+	result := ExtractBatch[Person](batch, inputs)
+
+	if result.Metadata.Succeeded != len(inputs) {
+		t.Fatalf("expected every item to succeed, got %+v (errors %v)", result.Metadata, result.Errors)
+	}
+	if len(calls) != 3 {
+		t.Fatalf("expected one call per customer, got %d", len(calls))
+	}
+	for _, call := range calls {
+		var groups []string
+		for _, input := range inputs {
+			if strings.Contains(call, input.(string)) {
+				groups = append(groups, customer(input))
+			}
+		}
+		if len(groups) == 0 {
+			t.Errorf("expected items in call %q", call)
+			continue
+		}
+		for _, group := range groups {
+			if group != groups[0] {
+				t.Errorf("expected a single customer per call, got %v in %q", groups, call)
+				break
+			}
+		}
+	}
+	if !strings.Contains(calls[0], "acme: Ada, 36") || !strings.Contains(calls[0], "acme: Cy, 29") {
+		t.Errorf("expected both acme items in the first call, got %q", calls[0])
+	}
+}
+
+func TestMapOpScoresSnippetsWithAlignedResultsAndPartialErrors(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	var mu sync.Mutex
+	calls := map[string]int{}
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case strings.Contains(user, "return a + b"):
+			calls["add"]++
+			return `{"value": 9, "reasoning": "clear and correct"}`, nil
+		case strings.Contains(user, "goto retry"):
+			calls["flaky"]++
+			if calls["flaky"] == 1 {
+				return "", errors.New("503 service unavailable")
+			}
+			return `{"value": 4, "reasoning": "hard to follow"}`, nil
+		default:
+			calls["broken"]++
+			return "", errors.New("invalid request: snippet rejected")
+		}
+	})
+
+	snippets := []string{
+		"func add(a, b int) int { return a + b }",
+		"func broken( {",
+		"retry: if fetch() != nil { goto retry }",
+	}
+	score := func(snippet string) (ScoreResult, error) {
+		return Score(snippet, NewScoreOptions())
+	}
+
+	var progress []int
+	result, err := MapOp(snippets, score, NewBatchOptions().
+		WithConcurrency(1).
+		WithErrorStrategy("retry").
+		WithMaxRetries(1).
+		WithOnProgress(func(completed, total int) { progress = append(progress, completed) }))
+
+	var batchErr BatchError
+	if !errors.As(err, &batchErr) || batchErr.Index != 1 {
+		t.Fatalf("expected the broken snippet's failure as the error, got %v", err)
+	}
+	if len(result.Results) != 3 || result.Results[0].Value != 9 || result.Results[2].Value != 4 {
+		t.Fatalf("expected scores aligned with the snippets, got %+v", result.Results)
+	}
+	if result.Errors[0] != nil || result.Errors[1] == nil || result.Errors[2] != nil {
+		t.Errorf("expected only the broken snippet to fail, got %v", result.Errors)
+	}
+	if calls["flaky"] != 2 || calls["broken"] != 2 {
+		t.Errorf("expected one retry per failed attempt, got %v", calls)
+	}
+	if result.Metadata.Succeeded != 2 || result.Metadata.Failed != 1 || len(progress) != 3 {
+		t.Errorf("unexpected metadata %+v or progress %v", result.Metadata, progress)
+	}
+
+	// Without retries the transient failure is reported as well
+	calls = map[string]int{}
+	result, _ = ScoreEach(snippets, NewScoreOptions(), NewBatchOptions())
+	if result.Errors[2] == nil || result.Results[0].Value != 9 || len(result.Failures()) != 2 {
+		t.Errorf("expected the flaky snippet to fail without retries, got %+v", result)
+	}
+}
+
+func TestBatchResultRetryFailuresMergesRetriedSuccesses(t *testing.T) {
+	defer setupMockClient()
+	var mu sync.Mutex
+	var retried []string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		for _, name := range []string{"Ada", "Grace", "Alan", "Edsger", "Barbara"} {
+			if !strings.Contains(user, name) {
+				continue
+			}
+			if strings.Contains(user, "faded") {
+				if opts.Intelligence != types.Smart {
+					return "", errors.New("unreadable input")
+				}
+				mu.Lock()
+				retried = append(retried, name)
+				mu.Unlock()
+			}
+			return fmt.Sprintf(`{"name":%q,"age":30}`, name), nil
+		}
+		return `{}`, nil
+	})
+
+	inputs := []interface{}{"card Ada", "faded Grace", "card Alan", "faded Edsger", "faded Barbara"}
+	batch := NewBatchProcessor(nil).WithConcurrency(2)
+	first := ExtractBatch[Person](batch, inputs, types.OpOptions{Intelligence: types.Fast})
+	if first.Metadata.Failed != 3 || len(first.Failures()) != 3 {
+		t.Fatalf("first pass failed %d items, want 3", first.Metadata.Failed)
+	}
+
+	merged := first.RetryFailures(types.OpOptions{Intelligence: types.Smart})
+	if len(retried) != 3 {
+		t.Errorf("retry pass reprocessed %v, want only the 3 failed items", retried)
+	}
+	if err := merged.FirstError(); err != nil || merged.SuccessRate() != 1 {
+		t.Fatalf("merged result still fails: %v", err)
+	}
+	want := []string{"Ada", "Grace", "Alan", "Edsger", "Barbara"}
+	for i, person := range merged.Results {
+		if person.Name != want[i] {
+			t.Errorf("Results[%d].Name = %q, want %q", i, person.Name, want[i])
+		}
+	}
+	if merged.Metadata.Succeeded != len(inputs) || merged.Metadata.Failed != 0 {
+		t.Errorf("Metadata = %+v, want %d succeeded and 0 failed", merged.Metadata, len(inputs))
+	}
+	if merged.Metadata.APICallsMade != first.Metadata.APICallsMade+3 {
+		t.Errorf("APICallsMade = %d, want both passes counted", merged.Metadata.APICallsMade)
+	}
+	if first.Metadata.Failed != 3 || first.Errors[1] == nil {
+		t.Error("RetryFailures modified the first-pass result")
+	}
+	if again := merged.RetryFailures(types.OpOptions{}); again.Metadata.APICallsMade != merged.Metadata.APICallsMade {
+		t.Error("retrying a fully successful batch should make no calls")
+	}
+}
+
+func TestBatchResultRetryFailuresStaysWithinTheBudget(t *testing.T) {
+	defer setupMockClient()
+	var calls atomic.Int32
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		calls.Add(1)
+		return `{"name": "Someone", "age": 30}`, nil
+	})
+
+	batch := NewBatchProcessor(&pricedProvider{costPerCall: 1}).WithConcurrency(1).WithBudgetUSD(2.5)
+	first := ExtractBatch[Person](batch, []interface{}{"Ada, 36", "Bob, 41", "Cy, 29", "Di, 52"})
+	if !first.Metadata.BudgetExhausted || calls.Load() != 2 {
+		t.Fatalf("first pass made %d calls, exhausted = %v; want 2 calls and the budget spent", calls.Load(), first.Metadata.BudgetExhausted)
+	}
+
+	// The retry draws on what the first pass left, which covers no call
+	retried := first.RetryFailures(types.OpOptions{Intelligence: types.Smart})
+	if calls.Load() != 2 {
+		t.Errorf("retry pass made %d calls past the budget", calls.Load()-2)
+	}
+	if retried.Metadata.EstimatedCost > 2.5 || len(retried.Metadata.Remaining) != 2 {
+		t.Errorf("EstimatedCost = %v, Remaining = %v; want at most 2.5 and both skipped items left", retried.Metadata.EstimatedCost, retried.Metadata.Remaining)
+	}
+}
+
+func TestBatchResultRetryFailuresCheckpointsRecoveredItems(t *testing.T) {
+	defer setupMockClient()
+	var mu sync.Mutex
+	var processed []string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		for _, name := range []string{"Ada", "Grace", "Alan"} {
+			if !strings.Contains(user, name) {
+				continue
+			}
+			mu.Lock()
+			processed = append(processed, name)
+			mu.Unlock()
+			if name == "Grace" && opts.Intelligence != types.Smart {
+				return "", errors.New("unreadable input")
+			}
+			return fmt.Sprintf(`{"name":%q,"age":30}`, name), nil
+		}
+		return "", errors.New("unexpected input")
+	})
+
+	inputs := []interface{}{"card Ada", "faded Grace", "card Alan"}
+	batch := NewBatchProcessor(nil).WithConcurrency(1).WithCheckpoint(NewMemoryStateStore(), "run-1")
+	first := ExtractBatch[Person](batch, inputs, types.OpOptions{Intelligence: types.Fast})
+	if merged := first.RetryFailures(types.OpOptions{Intelligence: types.Smart}); merged.Metadata.Failed != 0 {
+		t.Fatalf("retry left %d failures", merged.Metadata.Failed)
+	}
+
+	// A resumed run restores the item the retry recovered
+	processed = nil
+	resumed := ExtractBatch[Person](batch, inputs, types.OpOptions{Intelligence: types.Fast})
+	if len(processed) != 0 || resumed.Metadata.Resumed != 3 || resumed.Results[1].Name != "Grace" {
+		t.Errorf("resumed run processed %v, resumed = %d, Results[1] = %+v; want all 3 restored", processed, resumed.Metadata.Resumed, resumed.Results[1])
+	}
+}
@@ -0,0 +1,69 @@
+// package ops - Content-hash cache for the chunk results of auto-chunked inputs
+package ops
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+)
+
+// chunkCacheCapacity bounds the cached chunk results; the oldest entry is
+// evicted first
+const chunkCacheCapacity = 1024
+
+var (
+	chunkCacheMu    sync.Mutex
+	chunkCache      = map[string]string{}
+	chunkCacheOrder []string
+)
+
+// ClearChunkCache drops every chunk result stored by WithChunkCache
+func ClearChunkCache() {
+	chunkCacheMu.Lock()
+	defer chunkCacheMu.Unlock()
+	chunkCache = map[string]string{}
+	chunkCacheOrder = nil
+}
+
+// summaryChunkKey returns the cache key of a Summarize chunk, or "" when the
+// chunk must not be cached. The key hashes the chunk together with every
+// option that shapes its summary.
+func summaryChunkKey(chunk string, opts SummarizeOptions) string {
+	if !opts.ChunkCache {
+		return ""
+	}
+	opt := summarizeOpOptions(opts)
+	if opt.DryRun || IsDryRun(opt.Context) || !isDeterministic(opt) {
+		return ""
+	}
+	hash := sha256.Sum256([]byte(fmt.Sprintf("summarize\x00%d\x00%d\x00%s\x00%s\x00%s\x00%s",
+		opt.Mode, opt.Intelligence, opt.Steering, opt.Persona, opts.Query, chunk)))
+	return hex.EncodeToString(hash[:])
+}
+
+func lookupChunkCache(key string) (string, bool) {
+	if key == "" {
+		return "", false
+	}
+	chunkCacheMu.Lock()
+	defer chunkCacheMu.Unlock()
+	result, ok := chunkCache[key]
+	return result, ok
+}
+
+func storeChunkCache(key, result string) {
+	if key == "" {
+		return
+	}
+	chunkCacheMu.Lock()
+	defer chunkCacheMu.Unlock()
+	if _, ok := chunkCache[key]; !ok {
+		chunkCacheOrder = append(chunkCacheOrder, key)
+	}
+	chunkCache[key] = result
+	for len(chunkCacheOrder) > chunkCacheCapacity {
+		delete(chunkCache, chunkCacheOrder[0])
+		chunkCacheOrder = chunkCacheOrder[1:]
+	}
+}
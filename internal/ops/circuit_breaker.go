@@ -0,0 +1,159 @@
+// package ops - Circuit breaker that stops calling a failing provider
+package ops
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/monstercameron/schemaflow/internal/llm"
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+// CBConfig configures a circuit breaker around provider calls
+type CBConfig struct {
+	// FailureThreshold is the number of consecutive failed provider calls
+	// that opens the breaker (default 5)
+	FailureThreshold int
+
+	// OpenDuration is how long an open breaker fails calls fast before
+	// letting probes through (default 30s)
+	OpenDuration time.Duration
+
+	// HalfOpenProbes is the number of trial calls let through after the
+	// cooldown; the breaker closes once they all succeed (default 1)
+	HalfOpenProbes int
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker tracks provider failures across every operation sharing it
+type circuitBreaker struct {
+	mu        sync.Mutex
+	cfg       CBConfig
+	state     breakerState
+	failures  int
+	openedAt  time.Time
+	inFlight  int
+	successes int
+}
+
+// CircuitBreakerInterceptor returns an interceptor that fails provider calls
+// with types.ErrCircuitOpen, without calling the provider, once
+// cfg.FailureThreshold consecutive calls have failed. After cfg.OpenDuration
+// it lets cfg.HalfOpenProbes trial calls through: if they all succeed the
+// breaker closes, and any failure opens it again. Only provider failures
+// count (see isBreakerFailure); retries pass through interceptors, so each
+// attempt counts.
+func CircuitBreakerInterceptor(cfg CBConfig) llm.Interceptor {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.OpenDuration <= 0 {
+		cfg.OpenDuration = 30 * time.Second
+	}
+	if cfg.HalfOpenProbes <= 0 {
+		cfg.HalfOpenProbes = 1
+	}
+	breaker := &circuitBreaker{cfg: cfg}
+
+	return func(next llm.CompletionFunc) llm.CompletionFunc {
+		return func(ctx context.Context, req llm.CompletionRequest) (llm.CompletionResponse, error) {
+			probe, ok := breaker.allow()
+			if !ok {
+				return llm.CompletionResponse{}, types.ErrCircuitOpen
+			}
+			resp, err := next(ctx, req)
+			if err == nil {
+				err = validateLLMCompletion(resp)
+			}
+			breaker.record(probe, err)
+			return resp, err
+		}
+	}
+}
+
+// allow reports whether a call may reach the provider and whether it is a
+// half-open probe
+func (b *circuitBreaker) allow() (probe bool, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerOpen {
+		if time.Since(b.openedAt) < b.cfg.OpenDuration {
+			return false, false
+		}
+		b.state = breakerHalfOpen
+		b.inFlight = 0
+		b.successes = 0
+	}
+	if b.state == breakerHalfOpen {
+		if b.inFlight+b.successes >= b.cfg.HalfOpenProbes {
+			return false, false
+		}
+		b.inFlight++
+		return true, true
+	}
+	return false, true
+}
+
+// record updates the breaker with the outcome of a call it allowed
+func (b *circuitBreaker) record(probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	canceled := errors.Is(err, context.Canceled)
+	failed := isBreakerFailure(err)
+	if !probe {
+		if b.state != breakerClosed || canceled {
+			return
+		}
+		if !failed {
+			b.failures = 0
+		} else {
+			b.failures++
+			if b.failures >= b.cfg.FailureThreshold {
+				b.open()
+			}
+		}
+		return
+	}
+
+	if b.state != breakerHalfOpen {
+		return
+	}
+	b.inFlight--
+	switch {
+	case failed:
+		b.open()
+	case !canceled:
+		b.successes++
+		if b.successes >= b.cfg.HalfOpenProbes {
+			b.state = breakerClosed
+			b.failures = 0
+		}
+	}
+}
+
+func (b *circuitBreaker) open() {
+	b.state = breakerOpen
+	b.openedAt = time.Now()
+	b.failures = 0
+}
+
+// isBreakerFailure reports whether err says the provider is unhealthy:
+// timeouts and the transient errors worth retrying. Rejected requests, such
+// as a 400 for a bad prompt, show the provider is answering and do not count.
+func isBreakerFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	return errors.Is(err, context.DeadlineExceeded) || isRetryableLLMError(err)
+}
@@ -40,6 +40,14 @@ type ClusterOptions struct {
 
 	// Generate cluster descriptions
 	GenerateDescriptions bool
+
+	// Bounds for the cluster count when auto-detecting (0 for unbounded)
+	MinK int
+	MaxK int
+
+	// Depth of the cluster tree; levels above 1 split each cluster into
+	// sub-clusters (0 or 1 for flat clusters)
+	Levels int
 }
 
 // NewClusterOptions creates ClusterOptions with defaults
@@ -76,6 +84,15 @@ func (c ClusterOptions) Validate() error {
 	if c.NamingStrategy != "" && !validStrategies[c.NamingStrategy] {
 		return fmt.Errorf("invalid naming strategy: %s", c.NamingStrategy)
 	}
+	if c.MinK < 0 || c.MaxK < 0 {
+		return fmt.Errorf("k range cannot be negative, got [%d, %d]", c.MinK, c.MaxK)
+	}
+	if c.MaxK > 0 && c.MinK > c.MaxK {
+		return fmt.Errorf("min k %d exceeds max k %d", c.MinK, c.MaxK)
+	}
+	if c.Levels < 0 {
+		return fmt.Errorf("hierarchy levels cannot be negative, got %d", c.Levels)
+	}
 	return nil
 }
 
@@ -85,6 +102,20 @@ func (c ClusterOptions) WithNumClusters(n int) ClusterOptions {
 	return c
 }
 
+// WithKRange bounds the cluster count chosen by auto-detection
+func (c ClusterOptions) WithKRange(min, max int) ClusterOptions {
+	c.MinK = min
+	c.MaxK = max
+	return c
+}
+
+// WithHierarchy builds a cluster tree levels deep, splitting every cluster
+// into sub-clusters until the given depth is reached
+func (c ClusterOptions) WithHierarchy(levels int) ClusterOptions {
+	c.Levels = levels
+	return c
+}
+
 // WithMinClusterSize sets the minimum cluster size
 func (c ClusterOptions) WithMinClusterSize(size int) ClusterOptions {
 	c.MinClusterSize = size
@@ -158,6 +189,10 @@ type ClusterInfo[T any] struct {
 	Centroid    string   `json:"centroid,omitempty"`
 	Keywords    []string `json:"keywords,omitempty"`
 	Size        int      `json:"size"`
+
+	// Subclusters splits the cluster further when WithHierarchy asks for more
+	// than one level; their Indices refer to the original items
+	Subclusters []ClusterInfo[T] `json:"subclusters,omitempty"`
 }
 
 // ClusterResult contains the results of clustering
@@ -169,6 +204,15 @@ type ClusterResult[T any] struct {
 	NumClusters    int              `json:"num_clusters"`
 	Quality        float64          `json:"quality,omitempty"`
 	Metadata       map[string]any   `json:"metadata,omitempty"`
+
+	// ChosenK is the number of clusters produced and KReason explains why it
+	// was chosen when auto-detecting
+	ChosenK int    `json:"chosen_k"`
+	KReason string `json:"k_reason,omitempty"`
+
+	// CohesionScore is the model's assessment (0.0-1.0) of how tightly items
+	// fit their clusters versus neighbouring clusters, analogous to a silhouette score
+	CohesionScore float64 `json:"cohesion_score"`
 }
 
 // Cluster groups similar items semantically without predefined categories.
@@ -186,9 +230,19 @@ type ClusterResult[T any] struct {
 //	    WithNumClusters(5).
 //	    WithNamingStrategy("descriptive"))
 //
+//	// Auto-detect within bounds and inspect the choice
+//	result, err := Cluster(tickets, NewClusterOptions().WithKRange(2, 5))
+//	fmt.Println(result.ChosenK, result.KReason, result.CohesionScore)
+//
 //	// Cluster by specific criteria
 //	result, err := Cluster(customers, NewClusterOptions().
 //	    WithClusterBy("purchasing behavior and preferences"))
+//
+//	// Topic -> subtopic tree
+//	result, err := Cluster(articles, NewClusterOptions().WithHierarchy(2))
+//	for _, sub := range result.Clusters[0].Subclusters {
+//	    fmt.Println(result.Clusters[0].Name, "->", sub.Name)
+//	}
 func Cluster[T any](items []T, opts ClusterOptions) (ClusterResult[T], error) {
 	log := logger.GetLogger()
 	log.Debug("Starting cluster operation", "itemCount", len(items))
@@ -218,6 +272,7 @@ func Cluster[T any](items []T, opts ClusterOptions) (ClusterResult[T], error) {
 	}
 
 	opt := opts.toOpOptions()
+	opt.Operation = "cluster"
 
 	ctx := opt.Context
 	if ctx == nil {
@@ -244,6 +299,10 @@ func Cluster[T any](items []T, opts ClusterOptions) (ClusterResult[T], error) {
 		clusterConstraint = fmt.Sprintf("Create exactly %d clusters.", opts.NumClusters)
 	} else {
 		clusterConstraint = fmt.Sprintf("Automatically determine the optimal number of clusters (minimum cluster size: %d).", opts.MinClusterSize)
+		if kRange := describeKRange(opts.MinK, opts.MaxK); kRange != "" {
+			clusterConstraint += " The number of clusters must be " + kRange + "."
+		}
+		clusterConstraint += " Explain in k_reason why this number of clusters fits the data."
 	}
 
 	clusterCriteria := "semantic similarity"
@@ -278,8 +337,12 @@ Return a JSON object with:
     }
   ],
   "outlier_indices": [2, 5],
-  "quality": 0.85
-}`, clusterCriteria, clusterConstraint, outlierHandling, opts.NamingStrategy, opts.SimilarityThreshold)
+  "quality": 0.85,
+  "k_reason": "Why this number of clusters",
+  "cohesion": 0.8
+}
+
+"cohesion" (0.0-1.0) rates how much closer items are to their own cluster than to the nearest other cluster.`, clusterCriteria, clusterConstraint, outlierHandling, opts.NamingStrategy, opts.SimilarityThreshold)
 
 	userPrompt := fmt.Sprintf("Cluster these items:\n\n%s", strings.Join(itemsJSON, "\n"))
 
@@ -299,6 +362,8 @@ Return a JSON object with:
 		} `json:"clusters"`
 		OutlierIndices []int   `json:"outlier_indices"`
 		Quality        float64 `json:"quality"`
+		KReason        string  `json:"k_reason"`
+		Cohesion       float64 `json:"cohesion"`
 	}
 
 	if err := ParseJSON(response, &parsed); err != nil {
@@ -317,12 +382,22 @@ Return a JSON object with:
 			Size:        len(c.Indices),
 		}
 
+		var memberIndices []int
 		for _, idx := range c.Indices {
 			if idx >= 0 && idx < len(items) {
 				cluster.Items = append(cluster.Items, items[idx])
+				memberIndices = append(memberIndices, idx)
 			}
 		}
 
+		if opts.Levels > 1 && len(cluster.Items) > 1 {
+			subclusters, err := clusterSubtopics(cluster, memberIndices, opts)
+			if err != nil {
+				return result, err
+			}
+			cluster.Subclusters = subclusters
+		}
+
 		result.Clusters = append(result.Clusters, cluster)
 	}
 
@@ -336,7 +411,83 @@ Return a JSON object with:
 
 	result.NumClusters = len(result.Clusters)
 	result.Quality = parsed.Quality
+	result.ChosenK = result.NumClusters
+	result.KReason = parsed.KReason
+	result.CohesionScore = parsed.Cohesion
+
+	// Re-cluster with an explicit count if auto-detection left the requested range
+	if opts.NumClusters == 0 {
+		if k := clampK(result.ChosenK, opts.MinK, opts.MaxK); k != result.ChosenK {
+			log.Debug("Cluster count outside range, re-clustering", "chosenK", result.ChosenK, "targetK", k)
+			retry := opts
+			retry.NumClusters = k
+			retried, err := Cluster(items, retry)
+			if err != nil {
+				return result, err
+			}
+			retried.KReason = fmt.Sprintf("auto-detection chose %d clusters, outside %s; re-clustered into %d", result.ChosenK, describeKRange(opts.MinK, opts.MaxK), k)
+			return retried, nil
+		}
+	}
 
 	log.Debug("Cluster operation succeeded", "numClusters", result.NumClusters, "outlierCount", len(result.Outliers))
 	return result, nil
 }
+
+func describeKRange(minK, maxK int) string {
+	switch {
+	case minK > 0 && maxK > 0:
+		return fmt.Sprintf("between %d and %d", minK, maxK)
+	case minK > 0:
+		return fmt.Sprintf("at least %d", minK)
+	case maxK > 0:
+		return fmt.Sprintf("at most %d", maxK)
+	}
+	return ""
+}
+
+func clampK(k, minK, maxK int) int {
+	if minK > 0 && k < minK {
+		return minK
+	}
+	if maxK > 0 && k > maxK {
+		return maxK
+	}
+	return k
+}
+
+// clusterSubtopics splits one cluster into sub-clusters one level down,
+// mapping their indices back onto the original items
+func clusterSubtopics[T any](parent ClusterInfo[T], memberIndices []int, opts ClusterOptions) ([]ClusterInfo[T], error) {
+	sub := opts
+	sub.Levels = opts.Levels - 1
+	sub.NumClusters = 0
+	sub.MinK, sub.MaxK = 0, 0
+	sub.IncludeOutliers = false
+	sub.ClusterBy = fmt.Sprintf("subtopics within the %q cluster", parent.Name)
+	if opts.ClusterBy != "" {
+		sub.ClusterBy += ", using " + opts.ClusterBy
+	}
+
+	result, err := Cluster(parent.Items, sub)
+	if err != nil {
+		return nil, fmt.Errorf("sub-clustering %q: %w", parent.Name, err)
+	}
+	for i := range result.Clusters {
+		remapClusterIndices(&result.Clusters[i], memberIndices)
+	}
+	return result.Clusters, nil
+}
+
+// remapClusterIndices rewrites a cluster tree's indices, which refer to a
+// subset of items, as indices into the full item list
+func remapClusterIndices[T any](cluster *ClusterInfo[T], memberIndices []int) {
+	for i, idx := range cluster.Indices {
+		if idx >= 0 && idx < len(memberIndices) {
+			cluster.Indices[i] = memberIndices[idx]
+		}
+	}
+	for i := range cluster.Subclusters {
+		remapClusterIndices(&cluster.Subclusters[i], memberIndices)
+	}
+}
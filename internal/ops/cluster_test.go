@@ -1,7 +1,13 @@
 package ops
 
 import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/monstercameron/schemaflow/internal/types"
 )
 
 func TestClusterOptions(t *testing.T) {
@@ -84,3 +90,122 @@ func TestCluster(t *testing.T) {
 		}
 	})
 }
+
+func TestClusterChosenKWithinRange(t *testing.T) {
+	defer setupMockClient()
+
+	type Ticket struct {
+		ID    int    `json:"id"`
+		Title string `json:"title"`
+	}
+	tickets := []Ticket{
+		{1, "Cannot log in after password reset"},
+		{2, "Charged twice for March invoice"},
+		{3, "App crashes when uploading photos"},
+		{4, "2FA code never arrives"},
+		{5, "Refund not received"},
+		{6, "Export to CSV hangs"},
+	}
+
+	var prompts []string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		prompts = append(prompts, system)
+		if strings.Contains(system, "Create exactly 4 clusters") {
+			return `{"clusters": [
+				{"name": "Login", "indices": [0]}, {"name": "2FA", "indices": [3]},
+				{"name": "Billing", "indices": [1, 4]}, {"name": "Bugs", "indices": [2, 5]}
+			], "cohesion": 0.7}`, nil
+		}
+		// Auto-detection ignores the bound and proposes one cluster per ticket
+		var clusters []string
+		for i := range tickets {
+			clusters = append(clusters, fmt.Sprintf(`{"name": "T%d", "indices": [%d]}`, i, i))
+		}
+		return `{"clusters": [` + strings.Join(clusters, ",") + `], "k_reason": "every ticket is distinct", "cohesion": 0.4}`, nil
+	})
+
+	result, err := Cluster(tickets, NewClusterOptions().WithKRange(2, 4))
+	if err != nil {
+		t.Fatalf("Cluster() error = %v", err)
+	}
+	if !strings.Contains(prompts[0], "between 2 and 4") {
+		t.Errorf("auto-detect prompt missing k range: %q", prompts[0])
+	}
+	if result.ChosenK < 2 || result.ChosenK > 4 {
+		t.Errorf("ChosenK = %d, want within [2, 4]", result.ChosenK)
+	}
+	if result.ChosenK != len(result.Clusters) {
+		t.Errorf("ChosenK = %d, but %d clusters returned", result.ChosenK, len(result.Clusters))
+	}
+	if result.CohesionScore != 0.7 || result.KReason == "" {
+		t.Errorf("CohesionScore = %v, KReason = %q; want 0.7 and an explanation", result.CohesionScore, result.KReason)
+	}
+}
+
+func TestClusterOptionsRejectsInvertedKRange(t *testing.T) {
+	if err := NewClusterOptions().WithKRange(5, 2).Validate(); err == nil {
+		t.Error("expected error for min k greater than max k")
+	}
+}
+
+func TestClusterWithHierarchyBuildsSubclusters(t *testing.T) {
+	defer setupMockClient()
+
+	articles := []string{
+		"Building REST APIs with Express", // 0
+		"Sourdough starter basics",        // 1
+		"Writing a memory allocator in C", // 2
+		"CSS grid layouts explained",      // 3
+		"Rust ownership and borrowing",    // 4
+		"Perfect pan-seared steak",        // 5
+	}
+
+	var calls int
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		calls++
+		switch {
+		case strings.Contains(system, `subtopics within the "Programming" cluster`):
+			// Indices refer to the four programming articles in order
+			return `{"clusters": [{"name": "Web", "indices": [0, 2]}, {"name": "Systems", "indices": [1, 3]}]}`, nil
+		case strings.Contains(system, `subtopics within the "Cooking" cluster`):
+			return `{"clusters": [{"name": "Baking", "indices": [0]}, {"name": "Grilling", "indices": [1]}]}`, nil
+		}
+		return `{"clusters": [
+			{"name": "Programming", "indices": [0, 2, 3, 4]},
+			{"name": "Cooking", "indices": [1, 5]}
+		]}`, nil
+	})
+
+	result, err := Cluster(articles, NewClusterOptions().WithHierarchy(2))
+	if err != nil {
+		t.Fatalf("Cluster() error = %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected one top-level and two sub-cluster calls, got %d", calls)
+	}
+	if len(result.Clusters) != 2 {
+		t.Fatalf("expected 2 top-level clusters, got %d", len(result.Clusters))
+	}
+
+	programming := result.Clusters[0]
+	if len(programming.Subclusters) != 2 {
+		t.Fatalf("expected Programming to have 2 subclusters, got %+v", programming.Subclusters)
+	}
+	web, systems := programming.Subclusters[0], programming.Subclusters[1]
+	if web.Name != "Web" || !reflect.DeepEqual(web.Indices, []int{0, 3}) {
+		t.Errorf("Web = %q %v, want indices [0 3] into the original articles", web.Name, web.Indices)
+	}
+	if systems.Name != "Systems" || !reflect.DeepEqual(systems.Items, []string{articles[2], articles[4]}) {
+		t.Errorf("Systems = %q %v, want the C and Rust articles", systems.Name, systems.Items)
+	}
+	for _, cluster := range result.Clusters {
+		if len(cluster.Subclusters) == 0 {
+			t.Errorf("cluster %q has no subclusters", cluster.Name)
+		}
+		for _, sub := range cluster.Subclusters {
+			if sub.Size == 0 || len(sub.Subclusters) != 0 {
+				t.Errorf("subcluster %q should be a non-empty leaf, got %+v", sub.Name, sub)
+			}
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/monstercameron/schemaflow/internal/config"
+	"github.com/monstercameron/schemaflow/internal/logger"
 	"github.com/monstercameron/schemaflow/internal/types"
 )
 
@@ -25,7 +26,30 @@ import (
 //	    WithRequireReasoning(true).
 //	    WithTopN(3))
 func Choose[T any](options []T, opts ChooseOptions) (T, error) {
+	var stats ShortlistStats
+	return choose(options, opts, &stats)
+}
+
+// ChooseWithShortlist is Choose that also returns the size of each stage:
+// the options passed in and those sent to the LLM, which differ when
+// WithShortlistStage narrows a long list.
+//
+// Example:
+//
+//	best, stats, err := ChooseWithShortlist(catalog, NewChooseOptions().
+//	    WithCriteria([]string{"waterproof hiking boots"}).
+//	    WithShortlistStage(50))
+//	log.Printf("LLM compared %d of %d options", stats.Shortlisted, stats.Candidates)
+func ChooseWithShortlist[T any](options []T, opts ChooseOptions) (T, ShortlistStats, error) {
+	var stats ShortlistStats
+	result, err := choose(options, opts, &stats)
+	return result, stats, err
+}
+
+// choose implements Choose, recording the stage sizes in stats
+func choose[T any](options []T, opts ChooseOptions, stats *ShortlistStats) (T, error) {
 	var result T
+	*stats = ShortlistStats{Candidates: len(options), Shortlisted: len(options)}
 
 	// Validate options
 	if err := opts.Validate(); err != nil {
@@ -43,7 +67,21 @@ func Choose[T any](options []T, opts ChooseOptions) (T, error) {
 		return options[0], nil
 	}
 
+	if opts.ShortlistTopK > 0 && len(options) > opts.ShortlistTopK {
+		shortlisted, err := shortlistItems(opts.CommonOptions.GetContext(), options, strings.Join(opts.Criteria, " ")+" "+opts.CommonOptions.Steering, opts.ShortlistTopK, opts.ShortlistScorer)
+		if err != nil {
+			return result, types.ChooseError{
+				Options: interfaceSlice(options),
+				Reason:  fmt.Sprintf("failed to marshal options: %v", err),
+			}
+		}
+		logger.GetLogger().Debug("Choose shortlist stage", "candidates", len(options), "shortlisted", len(shortlisted))
+		options = shortlisted
+		stats.Shortlisted = len(shortlisted)
+	}
+
 	opOptions := opts.toOpOptions()
+	opOptions.Operation = "choose"
 
 	// Build selection instructions
 	var instructions []string
@@ -72,7 +110,7 @@ func Choose[T any](options []T, opts ChooseOptions) (T, error) {
 		opOptions.Steering = steering
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.GetTimeout())
+	ctx, cancel := context.WithTimeout(opOptions.Context, config.GetTimeout())
 	defer cancel()
 
 	optionsJSON, err := json.Marshal(options)
@@ -105,6 +143,7 @@ Rules:
 		return result, types.ChooseError{
 			Options: interfaceSlice(options),
 			Reason:  err.Error(),
+			Cause:   err,
 		}
 	}
 
@@ -131,6 +170,97 @@ Rules:
 	return result, nil
 }
 
+// ChooseBy selects the best item using caller-provided descriptions instead of
+// JSON serialization, so items may be of heterogeneous shapes behind a common
+// interface. The model picks by index and the original item is returned.
+//
+// Example:
+//
+//	best, err := ChooseBy(vendors, func(v Vendor) string {
+//	    return v.Name() + ": " + v.Terms()
+//	}, NewChooseOptions().WithCriteria([]string{"lowest total cost"}))
+func ChooseBy[T any](items []T, describe func(T) string, opts ChooseOptions) (T, error) {
+	var result T
+
+	if err := opts.Validate(); err != nil {
+		return result, fmt.Errorf("invalid options: %w", err)
+	}
+	if describe == nil {
+		return result, fmt.Errorf("describe function is required")
+	}
+
+	if len(items) == 0 {
+		return result, types.ChooseError{
+			Options: []any{},
+			Reason:  "no options provided",
+		}
+	}
+
+	if len(items) == 1 {
+		return items[0], nil
+	}
+
+	opOptions := opts.toOpOptions()
+	opOptions.Operation = "choose"
+	if len(opts.Criteria) > 0 {
+		criteria := fmt.Sprintf("Selection criteria: %s", strings.Join(opts.Criteria, ", "))
+		if opOptions.Steering != "" {
+			criteria = opOptions.Steering + ". " + criteria
+		}
+		opOptions.Steering = criteria
+	}
+
+	ctx, cancel := context.WithTimeout(opts.CommonOptions.GetContext(), config.GetTimeout())
+	defer cancel()
+
+	var list strings.Builder
+	for i, item := range items {
+		fmt.Fprintf(&list, "[%d] %s\n", i, strings.TrimSpace(describe(item)))
+	}
+
+	systemPrompt := `You are a selection expert. Choose the best option from the numbered list.
+
+Return a JSON object:
+{"index": <number of the chosen option>, "reasoning": "why it was chosen"}
+
+Rules:
+- Evaluate every option against the criteria
+- The index must be one of the numbers shown in brackets`
+
+	userPrompt := fmt.Sprintf("Choose the best option from this list:\n%s", list.String())
+	if opOptions.Steering != "" {
+		userPrompt = fmt.Sprintf("Selection Requirements: %s\n\n%s", opOptions.Steering, userPrompt)
+	}
+
+	response, err := callLLM(ctx, systemPrompt, userPrompt, opOptions)
+	if err != nil {
+		return result, types.ChooseError{
+			Options: interfaceSlice(items),
+			Reason:  err.Error(),
+			Cause:   err,
+		}
+	}
+
+	var choice struct {
+		Index     *int   `json:"index"`
+		Reasoning string `json:"reasoning"`
+	}
+	if err := ParseJSON(response, &choice); err != nil {
+		return result, types.ChooseError{
+			Options: interfaceSlice(items),
+			Reason:  fmt.Sprintf("failed to parse selection: %v", err),
+		}
+	}
+	if choice.Index == nil || *choice.Index < 0 || *choice.Index >= len(items) {
+		return result, types.ChooseError{
+			Options: interfaceSlice(items),
+			Reason:  fmt.Sprintf("selection index out of range (response: %s)", response),
+		}
+	}
+
+	return items[*choice.Index], nil
+}
+
 // Filter semantically filters items with specialized options.
 // Returns the actual matching objects instead of using index-based selection.
 //
@@ -146,6 +276,23 @@ Rules:
 //	    WithMinConfidence(0.8).
 //	    WithIncludeReasons(true))
 func Filter[T any](items []T, opts FilterOptions) ([]T, error) {
+	var stats ShortlistStats
+	return filter(items, opts, &stats)
+}
+
+// FilterWithShortlist is Filter that also returns the size of each stage:
+// the items passed in and those sent to the LLM, which differ when
+// WithShortlistStage narrows a long list.
+func FilterWithShortlist[T any](items []T, opts FilterOptions) ([]T, ShortlistStats, error) {
+	var stats ShortlistStats
+	result, err := filter(items, opts, &stats)
+	return result, stats, err
+}
+
+// filter implements Filter, recording the stage sizes in stats
+func filter[T any](items []T, opts FilterOptions, stats *ShortlistStats) ([]T, error) {
+	*stats = ShortlistStats{Candidates: len(items), Shortlisted: len(items)}
+
 	// Validate options
 	if err := opts.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid options: %w", err)
@@ -155,7 +302,35 @@ func Filter[T any](items []T, opts FilterOptions) ([]T, error) {
 		return items, nil
 	}
 
+	// Shortlist stage: items outside the shortlist are treated as not matching,
+	// so they are dropped when keeping matches and kept, in their original
+	// positions, when removing them
+	all := items
+	var shortlisted, unconsidered []int
+	var texts []string
+	if opts.ShortlistTopK > 0 && len(items) > opts.ShortlistTopK {
+		var rest []int
+		var err error
+		shortlisted, rest, texts, err = splitShortlist(opts.CommonOptions.GetContext(), items, opts.Criteria+" "+opts.CommonOptions.Steering, opts.ShortlistTopK, opts.ShortlistScorer)
+		if err != nil {
+			return nil, types.FilterError{
+				Items:  interfaceSlice(items),
+				Reason: fmt.Sprintf("failed to marshal items: %v", err),
+			}
+		}
+		logger.GetLogger().Debug("Filter shortlist stage", "candidates", len(items), "shortlisted", len(shortlisted))
+		items = make([]T, len(shortlisted))
+		for i, idx := range shortlisted {
+			items[i] = all[idx]
+		}
+		stats.Shortlisted = len(items)
+		if !opts.KeepMatching {
+			unconsidered = rest
+		}
+	}
+
 	opOptions := opts.toOpOptions()
+	opOptions.Operation = "filter"
 
 	// Build filter instructions
 	var instructions []string
@@ -182,7 +357,7 @@ func Filter[T any](items []T, opts FilterOptions) ([]T, error) {
 	}
 	opOptions.Steering = steering
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.GetTimeout())
+	ctx, cancel := context.WithTimeout(opOptions.Context, config.GetTimeout())
 	defer cancel()
 
 	itemsJSON, err := json.Marshal(items)
@@ -216,6 +391,7 @@ Examples:
 		return nil, types.FilterError{
 			Items:  interfaceSlice(items),
 			Reason: err.Error(),
+			Cause:  err,
 		}
 	}
 
@@ -236,7 +412,7 @@ Examples:
 	if err := json.Unmarshal([]byte(response), &result); err != nil {
 		var single T
 		if strings.TrimSpace(response) != "{}" && json.Unmarshal([]byte(response), &single) == nil {
-			return []T{single}, nil
+			return restoreFilterOrder([]T{single}, all, shortlisted, texts, unconsidered), nil
 		}
 		return nil, types.FilterError{
 			Items:  interfaceSlice(items),
@@ -244,7 +420,46 @@ Examples:
 		}
 	}
 
-	return result, nil
+	return restoreFilterOrder(result, all, shortlisted, texts, unconsidered), nil
+}
+
+// restoreFilterOrder merges the items the model kept from the shortlist with
+// the unconsidered items by their original index. Kept items are located by
+// their serialized form among the shortlisted texts; one the model altered
+// stays right after the kept item before it.
+func restoreFilterOrder[T any](kept []T, all []T, shortlisted []int, texts []string, unconsidered []int) []T {
+	if len(unconsidered) == 0 {
+		return kept
+	}
+	type placed struct {
+		index int
+		item  T
+	}
+	merged := make([]placed, 0, len(kept)+len(unconsidered))
+	used := make(map[int]bool, len(kept))
+	last := -1
+	for _, item := range kept {
+		if data, err := json.Marshal(item); err == nil {
+			for _, idx := range shortlisted {
+				if !used[idx] && texts[idx] == string(data) {
+					used[idx] = true
+					last = idx
+					break
+				}
+			}
+		}
+		merged = append(merged, placed{index: last, item: item})
+	}
+	for _, idx := range unconsidered {
+		merged = append(merged, placed{index: idx, item: all[idx]})
+	}
+	sort.SliceStable(merged, func(a, b int) bool { return merged[a].index < merged[b].index })
+
+	out := make([]T, len(merged))
+	for i, p := range merged {
+		out[i] = p.item
+	}
+	return out
 }
 
 // Sort orders items semantically with specialized options.
@@ -261,7 +476,36 @@ Examples:
 //	    WithCriteria("by quality").
 //	    WithSecondaryCriteria([]string{"by price", "by popularity"}).
 //	    WithDirection("descending"))
+//
+//	// Tiered keys: fuzzy impact first, then the concrete effort field
+//	sorted, err := Sort(tasks, NewSortOptions().WithKeys([]SortKey{
+//	    {Criteria: "business impact", Direction: "descending"},
+//	    {Field: "effort", Direction: "ascending"},
+//	}))
+//
+//	// Large lists: embedding shortlist, then the LLM orders the top 50
+//	sorted, err := Sort(tickets, NewSortOptions().
+//	    WithCriteria("most urgent payment issues").
+//	    WithShortlistStage(50))
 func Sort[T any](items []T, opts SortOptions) ([]T, error) {
+	var stats ShortlistStats
+	return sortItems(items, opts, &stats)
+}
+
+// SortWithShortlist is Sort that also returns the size of each stage: the
+// items passed in and those sorted by the LLM, which differ when
+// WithShortlistStage narrows a long list. Only result[:stats.Shortlisted]
+// is ordered by the criteria; the rest follow in shortlist score order.
+func SortWithShortlist[T any](items []T, opts SortOptions) ([]T, ShortlistStats, error) {
+	var stats ShortlistStats
+	result, err := sortItems(items, opts, &stats)
+	return result, stats, err
+}
+
+// sortItems implements Sort, recording the stage sizes in stats
+func sortItems[T any](items []T, opts SortOptions, stats *ShortlistStats) ([]T, error) {
+	*stats = ShortlistStats{Candidates: len(items), Shortlisted: len(items)}
+
 	// Validate options
 	if err := opts.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid options: %w", err)
@@ -271,7 +515,17 @@ func Sort[T any](items []T, opts SortOptions) ([]T, error) {
 		return items, nil
 	}
 
+	if opts.ShortlistTopK > 0 && len(items) > opts.ShortlistTopK {
+		stats.Shortlisted = opts.ShortlistTopK
+		return shortlistSort(items, opts)
+	}
+
 	opOptions := opts.toOpOptions()
+	opOptions.Operation = "sort"
+
+	if len(opts.Keys) > 0 {
+		return sortByKeys(items, opts, opOptions)
+	}
 
 	// Build sort instructions
 	var instructions []string
@@ -304,7 +558,7 @@ func Sort[T any](items []T, opts SortOptions) ([]T, error) {
 	}
 	opOptions.Steering = steering
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.GetTimeout())
+	ctx, cancel := context.WithTimeout(opOptions.Context, config.GetTimeout())
 	defer cancel()
 
 	itemsJSON, err := json.Marshal(items)
@@ -340,6 +594,7 @@ Examples:
 		return nil, types.SortError{
 			Items:  interfaceSlice(items),
 			Reason: err.Error(),
+			Cause:  err,
 		}
 	}
 
@@ -382,8 +637,48 @@ Examples:
 	return result, nil
 }
 
+// shortlistSort ranks items against the sort criteria, sorts the
+// ShortlistTopK best matches with the LLM and appends the rest in score order
+func shortlistSort[T any](items []T, opts SortOptions) ([]T, error) {
+	texts, err := shortlistTexts(items)
+	if err != nil {
+		return nil, types.SortError{
+			Items:  interfaceSlice(items),
+			Reason: fmt.Sprintf("failed to marshal items: %v", err),
+		}
+	}
+
+	queryParts := append([]string{opts.Criteria}, opts.SecondaryCriteria...)
+	for _, key := range opts.Keys {
+		queryParts = append(queryParts, key.Criteria, key.Field)
+	}
+	query := strings.Join(queryParts, " ")
+	scorer := opts.ShortlistScorer
+	if scorer == nil {
+		scorer = embeddingScorer(opts.CommonOptions.GetContext(), query, texts)
+	}
+	order := rankIndices(query, texts, scorer)
+
+	head := make([]T, opts.ShortlistTopK)
+	for i, idx := range order[:opts.ShortlistTopK] {
+		head[i] = items[idx]
+	}
+	logger.GetLogger().Debug("Sort shortlist stage", "candidates", len(items), "shortlisted", len(head))
+
+	headOpts := opts
+	headOpts.ShortlistTopK = 0
+	sorted, err := Sort(head, headOpts)
+	if err != nil {
+		return nil, err
+	}
+	for _, idx := range order[opts.ShortlistTopK:] {
+		sorted = append(sorted, items[idx])
+	}
+	return sorted, nil
+}
+
 func sortByScoringFallback[T any](items []T, opts SortOptions, opOptions types.OpOptions) ([]T, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), config.GetTimeout())
+	ctx, cancel := context.WithTimeout(opOptions.Context, config.GetTimeout())
 	defer cancel()
 
 	type scoredItem struct {
@@ -0,0 +1,308 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+type shippingOption interface {
+	Carrier() string
+}
+
+type groundShipping struct{ days int }
+
+func (g groundShipping) Carrier() string { return "ground" }
+
+type airShipping struct {
+	cost   float64
+	flight string
+}
+
+func (a airShipping) Carrier() string { return "air" }
+
+func TestChooseByUsesDescribe(t *testing.T) {
+	defer setupMockClient()
+
+	items := []shippingOption{
+		groundShipping{days: 6},
+		airShipping{cost: 42, flight: "LH400"},
+	}
+	describe := func(o shippingOption) string {
+		switch v := o.(type) {
+		case groundShipping:
+			return fmt.Sprintf("Ground, arrives in %d days", v.days)
+		case airShipping:
+			return "Air freight on " + v.flight
+		}
+		return o.Carrier()
+	}
+
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		if !strings.Contains(user, "[1] Air freight on LH400") {
+			t.Errorf("prompt missing described option: %q", user)
+		}
+		if !strings.Contains(user, "fastest delivery") {
+			t.Errorf("prompt missing criteria: %q", user)
+		}
+		return `{"index": 1, "reasoning": "air is faster"}`, nil
+	})
+
+	got, err := ChooseBy(items, describe, NewChooseOptions().WithCriteria([]string{"fastest delivery"}))
+	if err != nil {
+		t.Fatalf("ChooseBy() error = %v", err)
+	}
+	if got.Carrier() != "air" {
+		t.Errorf("ChooseBy() = %v, want air option", got)
+	}
+}
+
+func TestChooseByRejectsOutOfRangeIndex(t *testing.T) {
+	defer setupMockClient()
+
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		return `{"index": 5}`, nil
+	})
+
+	_, err := ChooseBy([]string{"a", "b"}, func(s string) string { return s }, NewChooseOptions())
+	if _, ok := err.(types.ChooseError); !ok {
+		t.Fatalf("ChooseBy() error = %v, want ChooseError", err)
+	}
+}
+
+func TestSortWithKeysAppliesTiersInOrder(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	type task struct {
+		Title  string `json:"title"`
+		Effort int    `json:"effort"`
+	}
+	tasks := []task{
+		{Title: "Fix typo in footer", Effort: 1},
+		{Title: "Fix checkout outage", Effort: 5},
+		{Title: "Restore payment retries", Effort: 3},
+		{Title: "Update team wiki", Effort: 2},
+		{Title: "Patch login crash", Effort: 3},
+	}
+
+	// Only the fuzzy impact key reaches the model; effort is compared locally
+	calls := 0
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		calls++
+		if !strings.Contains(user, "Criterion: business impact") || !strings.Contains(system, "exactly 5 scores") {
+			t.Errorf("unexpected scoring prompt: %q / %q", system, user)
+		}
+		return `{"scores": [0.1, 0.9, 0.9, 0.1, 0.9]}`, nil
+	})
+
+	sorted, err := Sort(tasks, NewSortOptions().WithKeys([]SortKey{
+		{Criteria: "business impact", Direction: "descending"},
+		{Field: "effort", Direction: "ascending"},
+	}))
+	if err != nil {
+		t.Fatalf("Sort failed: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected one scoring call, got %d", calls)
+	}
+
+	// High impact first, cheapest first within a tier, input order on full ties
+	want := []string{"Restore payment retries", "Patch login crash", "Fix checkout outage", "Fix typo in footer", "Update team wiki"}
+	for i, title := range want {
+		if sorted[i].Title != title {
+			t.Fatalf("position %d: got %q, want %q (order %v)", i, sorted[i].Title, title, sorted)
+		}
+	}
+
+	// Concrete keys alone never call the model
+	calls = 0
+	byEffort, err := Sort(tasks, NewSortOptions().WithKeys([]SortKey{{Field: "effort", Direction: "descending"}}))
+	if err != nil {
+		t.Fatalf("Sort failed: %v", err)
+	}
+	if calls != 0 || byEffort[0].Title != "Fix checkout outage" || byEffort[4].Title != "Fix typo in footer" {
+		t.Errorf("expected a local effort sort without model calls, got %v (calls %d)", byEffort, calls)
+	}
+
+	if _, err := Sort(tasks, NewSortOptions().WithKeys([]SortKey{{Field: "priority"}})); err == nil {
+		t.Error("expected an unknown field to be rejected")
+	}
+	if _, err := Sort(tasks, NewSortOptions().WithKeys([]SortKey{{Field: "effort", Criteria: "effort"}})); err == nil {
+		t.Error("expected a key with both Field and Criteria to be rejected")
+	}
+}
+
+func TestSortShortlistSendsOnlyBestMatchesToLLM(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	tickets := make([]string, 500)
+	for i := range tickets {
+		tickets[i] = fmt.Sprintf("Ticket %d: update the office plant watering rota", i)
+	}
+	urgent := map[string]bool{}
+	for _, i := range []int{37, 211, 458} {
+		tickets[i] = fmt.Sprintf("Ticket %d: payment outage blocks checkout for customers", i)
+		urgent[tickets[i]] = true
+	}
+
+	calls := 0
+	var sentToLLM []string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		calls++
+		input := user[strings.Index(user, "Sort these items:"):]
+		input = input[strings.Index(input, "["):]
+		if err := json.NewDecoder(strings.NewReader(input)).Decode(&sentToLLM); err != nil {
+			t.Fatalf("could not read items from prompt: %v", err)
+		}
+		data, _ := json.Marshal(sentToLLM)
+		return string(data), nil
+	})
+
+	sorted, stats, err := SortWithShortlist(tickets, NewSortOptions().WithCriteria("payment outage checkout urgency").WithShortlistStage(20))
+	if err != nil {
+		t.Fatalf("Sort failed: %v", err)
+	}
+	if stats.Candidates != 500 || stats.Shortlisted != 20 {
+		t.Errorf("expected stats {500 20}, got %+v", stats)
+	}
+	if calls != 1 {
+		t.Errorf("expected one LLM call, got %d", calls)
+	}
+	if len(sentToLLM) != 20 {
+		t.Errorf("expected only the 20 shortlisted items in the prompt, got %d", len(sentToLLM))
+	}
+	for _, ticket := range sorted[:3] {
+		if !urgent[ticket] {
+			t.Errorf("expected the relevant tickets to lead the order, got %q", ticket)
+		}
+	}
+	if len(sorted) != len(tickets) {
+		t.Fatalf("expected all %d items back, got %d", len(tickets), len(sorted))
+	}
+	seen := map[string]bool{}
+	for _, ticket := range sorted {
+		seen[ticket] = true
+	}
+	if len(seen) != len(tickets) {
+		t.Errorf("expected every item exactly once, got %d distinct", len(seen))
+	}
+}
+
+func TestFilterShortlistKeepsUnconsideredItemsInPlace(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	items := []string{"office chair", "refund request overdue", "desk lamp", "refund denied twice", "stapler"}
+	var sent []string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		input := user[strings.Index(user, "["):]
+		if err := json.NewDecoder(strings.NewReader(input)).Decode(&sent); err != nil {
+			t.Fatalf("could not read items from prompt: %v", err)
+		}
+		// Remove the matching refund complaint, keep the other
+		return `["refund request overdue"]`, nil
+	})
+
+	opts := NewFilterOptions().WithCriteria("refund denied").WithShortlistStage(2)
+	opts.KeepMatching = false
+	filtered, stats, err := FilterWithShortlist(items, opts)
+	if err != nil {
+		t.Fatalf("FilterWithShortlist() error = %v", err)
+	}
+
+	if len(sent) != 2 {
+		t.Errorf("LLM stage received %v, want the 2 shortlisted items", sent)
+	}
+	if stats.Candidates != 5 || stats.Shortlisted != 2 {
+		t.Errorf("stats = %+v, want 5 candidates and 2 shortlisted", stats)
+	}
+	want := []string{"office chair", "refund request overdue", "desk lamp", "stapler"}
+	if strings.Join(filtered, "|") != strings.Join(want, "|") {
+		t.Errorf("filtered = %v, want input order %v", filtered, want)
+	}
+}
+
+func TestChooseWithShortlistReportsStageSizes(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	items := make([]string, 100)
+	for i := range items {
+		items[i] = fmt.Sprintf("generic office chair %d", i)
+	}
+	items[42] = "waterproof hiking boots"
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		return `"waterproof hiking boots"`, nil
+	})
+
+	best, stats, err := ChooseWithShortlist(items, NewChooseOptions().
+		WithCriteria([]string{"waterproof hiking boots"}).
+		WithShortlistStage(5))
+	if err != nil {
+		t.Fatalf("ChooseWithShortlist() error = %v", err)
+	}
+	if best != "waterproof hiking boots" {
+		t.Errorf("best = %q", best)
+	}
+	if stats.Candidates != 100 || stats.Shortlisted != 5 {
+		t.Errorf("stats = %+v, want 100 candidates and 5 shortlisted", stats)
+	}
+}
+
+func TestWithPrefilterAliasesShortlistStage(t *testing.T) {
+	if got := NewChooseOptions().WithPrefilter(5).ShortlistTopK; got != 5 {
+		t.Errorf("ChooseOptions.WithPrefilter(5) ShortlistTopK = %d", got)
+	}
+	if got := NewFilterOptions().WithPrefilter(5).ShortlistTopK; got != 5 {
+		t.Errorf("FilterOptions.WithPrefilter(5) ShortlistTopK = %d", got)
+	}
+	if got := NewSortOptions().WithPrefilter(5).ShortlistTopK; got != 5 {
+		t.Errorf("SortOptions.WithPrefilter(5) ShortlistTopK = %d", got)
+	}
+}
+
+func TestShortlistUsesTheInjectedEmbedder(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+	previous := textEmbedder
+	defer func() { textEmbedder = previous }()
+
+	// The embedder places "rain boots" next to the query although the two
+	// share no words, so only embeddings can shortlist it
+	textEmbedder = func(ctx context.Context, texts []string) ([][]float64, error) {
+		vectors := make([][]float64, len(texts))
+		for i, text := range texts {
+			if strings.Contains(text, "rain boots") || strings.Contains(text, "waterproof footwear") {
+				vectors[i] = []float64{1, 0}
+			} else {
+				vectors[i] = []float64{0, 1}
+			}
+		}
+		return vectors, nil
+	}
+	var sent string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		sent = user
+		return `"rain boots"`, nil
+	})
+
+	items := []string{"office chair", "desk lamp", "rain boots", "stapler", "monitor"}
+	best, stats, err := ChooseWithShortlist(items, NewChooseOptions().
+		WithCriteria([]string{"waterproof footwear"}).
+		WithShortlistStage(1))
+	if err != nil {
+		t.Fatalf("ChooseWithShortlist() error = %v", err)
+	}
+	if best != "rain boots" || stats.Shortlisted != 1 {
+		t.Errorf("best = %q, stats = %+v, want rain boots shortlisted alone", best, stats)
+	}
+	if !strings.Contains(sent, "rain boots") || strings.Contains(sent, "office chair") {
+		t.Errorf("LLM stage prompt = %q, want only the embedded match", sent)
+	}
+}
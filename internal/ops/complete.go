@@ -28,7 +28,7 @@ type CompleteOptions struct {
 	Context       []string // Previous context messages/text
 	MaxLength     int      // Maximum length of completion
 	StopSequences []string // Sequences that stop generation
-	Temperature   float32  // Creativity level (0.0-2.0)
+	Temperature   float32  // Creativity level on the normalized 0.0-1.0 scale
 	TopP          float32  // Nucleus sampling (0.0-1.0)
 	TopK          int      // Top-k sampling
 }
@@ -41,7 +41,7 @@ func NewCompleteOptions() CompleteOptions {
 			Intelligence: types.Smart,
 		},
 		MaxLength:   100,
-		Temperature: 0.7,
+		Temperature: 0.35,
 		TopP:        0.9,
 		TopK:        50,
 	}
@@ -65,9 +65,23 @@ func (opts CompleteOptions) WithStopSequences(sequences []string) CompleteOption
 	return opts
 }
 
-// WithTemperature sets the creativity level
+// WithTemperature sets the creativity level on the provider-agnostic 0-1
+// scale used by every other operation. It is mapped to the provider's native
+// range when the request is sent (e.g. 0.5 -> 1.0 for OpenAI), and the
+// default 0.35 matches the Creative mode default everywhere. Use
+// WithRawTemperature for a value in the provider's native range.
 func (opts CompleteOptions) WithTemperature(temperature float32) CompleteOptions {
 	opts.Temperature = temperature
+	opts.OpOptions.Temperature = nil
+	opts.OpOptions.RawTemperature = false
+	return opts
+}
+
+// WithRawTemperature sets the temperature in the provider's native range,
+// bypassing normalization.
+func (opts CompleteOptions) WithRawTemperature(temperature float64) CompleteOptions {
+	opts.OpOptions.Temperature = &temperature
+	opts.OpOptions.RawTemperature = true
 	return opts
 }
 
@@ -100,8 +114,8 @@ func (opts CompleteOptions) Validate() error {
 	if opts.MaxLength <= 0 {
 		return fmt.Errorf("MaxLength must be positive")
 	}
-	if opts.Temperature < 0 || opts.Temperature > 2.0 {
-		return fmt.Errorf("Temperature must be between 0.0 and 2.0")
+	if opts.Temperature < 0 || opts.Temperature > 1.0 {
+		return fmt.Errorf("Temperature must be between 0.0 and 1.0")
 	}
 	if opts.TopP <= 0 || opts.TopP > 1.0 {
 		return fmt.Errorf("TopP must be between 0.0 and 1.0")
@@ -114,7 +128,12 @@ func (opts CompleteOptions) Validate() error {
 
 // toOpOptions converts CompleteOptions to types.OpOptions
 func (opts CompleteOptions) toOpOptions() types.OpOptions {
-	return opts.OpOptions
+	op := opts.OpOptions
+	if op.Temperature == nil {
+		temperature := float64(opts.Temperature)
+		op.Temperature = &temperature
+	}
+	return op
 }
 
 // Complete intelligently completes partial text using LLM intelligence.
@@ -158,12 +177,13 @@ func completeImpl(ctx context.Context, provider llm.Provider, partialText string
 
 	// Call LLM - use default provider if none provided
 	opOpts := opts.toOpOptions()
+	opOpts.Operation = "complete"
 	var response string
-	var err error
-	if provider != nil {
+	systemPrompt, userPrompt, err := guardPrompts(systemPrompt, userPrompt, opOpts)
+	if err == nil && provider != nil {
 		response, err = CallLLM(ctx, provider, systemPrompt, userPrompt, opOpts)
-	} else {
-		response, err = callLLM(ctx, systemPrompt, userPrompt, opOpts)
+	} else if err == nil {
+		response, err = sendLLM(ctx, systemPrompt, userPrompt, opOpts)
 	}
 	if err != nil {
 		logger.Error("Complete operation LLM call failed", "requestID", opts.RequestID, "error", err)
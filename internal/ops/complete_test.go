@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/monstercameron/schemaflow/internal/llm"
 	"github.com/monstercameron/schemaflow/internal/types"
 )
 
@@ -18,8 +19,8 @@ func TestComplete_Basic(t *testing.T) {
 	if opts.MaxLength != 100 {
 		t.Errorf("Expected default MaxLength 100, got %d", opts.MaxLength)
 	}
-	if opts.Temperature != 0.7 {
-		t.Errorf("Expected default Temperature 0.7, got %f", opts.Temperature)
+	if opts.Temperature != 0.35 {
+		t.Errorf("Expected default Temperature 0.35, got %f", opts.Temperature)
 	}
 }
 
@@ -52,6 +53,35 @@ func TestComplete_Options(t *testing.T) {
 	}
 }
 
+func TestComplete_DefaultTemperatureMatchesCreativeMode(t *testing.T) {
+	for _, name := range []string{"openai", "anthropic"} {
+		provider := &captureProvider{name: name, resp: llm.CompletionResponse{Content: "the end."}}
+		if _, err := Complete(context.Background(), provider, "Once upon a time", NewCompleteOptions()); err != nil {
+			t.Fatalf("Complete() error = %v", err)
+		}
+		want := resolveTemperature(name, types.OpOptions{Mode: types.Creative})
+		if provider.req.Temperature != want {
+			t.Errorf("%s: Temperature = %v, want Creative default %v", name, provider.req.Temperature, want)
+		}
+	}
+
+	provider := &captureProvider{name: "openai", resp: llm.CompletionResponse{Content: "the end."}}
+	if _, err := Complete(context.Background(), provider, "Once upon a time", NewCompleteOptions().WithTemperature(0.75)); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if provider.req.Temperature != 1.5 {
+		t.Errorf("Temperature = %v, want 0.75 mapped to OpenAI's 1.5", provider.req.Temperature)
+	}
+
+	provider = &captureProvider{name: "openai", resp: llm.CompletionResponse{Content: "the end."}}
+	if _, err := Complete(context.Background(), provider, "Once upon a time", NewCompleteOptions().WithRawTemperature(1.2)); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if provider.req.Temperature != 1.2 {
+		t.Errorf("Temperature = %v, want the raw 1.2", provider.req.Temperature)
+	}
+}
+
 func TestComplete_OptionsValidation(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -80,7 +110,7 @@ func TestComplete_OptionsValidation(t *testing.T) {
 		},
 		{
 			name:    "temperature too high",
-			opts:    NewCompleteOptions().WithTemperature(2.1),
+			opts:    NewCompleteOptions().WithTemperature(1.1),
 			wantErr: true,
 		},
 		{
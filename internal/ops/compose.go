@@ -136,13 +136,7 @@ func Assemble[T any](parts []any, opts ...ComposeOptions) (ComposeResult[T], err
 	result.Metadata = make(map[string]any)
 
 	// Apply defaults
-	opt := ComposeOptions{
-		MergeStrategy: "smart",
-		FillGaps:      false,
-		Validate:      true,
-		Mode:          types.TransformMode,
-		Intelligence:  types.Smart,
-	}
+	opt := defaultComposeOptions()
 	if len(opts) > 0 {
 		opt = mergeComposeOptions(opt, opts[0])
 	}
@@ -232,6 +226,7 @@ Rules:
 		Context:       ctx,
 		RequestID:     opt.RequestID,
 		CorrelationID: opt.CorrelationID,
+		Operation:     "compose",
 	}
 
 	response, err := callLLM(ctx, systemPrompt, userPrompt, opOpts)
@@ -289,6 +284,18 @@ Rules:
 	return result, nil
 }
 
+// defaultComposeOptions returns the options Assemble runs with when none are
+// given
+func defaultComposeOptions() ComposeOptions {
+	return ComposeOptions{
+		MergeStrategy: "smart",
+		FillGaps:      false,
+		Validate:      true,
+		Mode:          types.TransformMode,
+		Intelligence:  types.Smart,
+	}
+}
+
 // mergeComposeOptions merges user options with defaults
 func mergeComposeOptions(defaults, user ComposeOptions) ComposeOptions {
 	if user.Template != "" {
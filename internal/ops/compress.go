@@ -194,6 +194,7 @@ func Compress[T any](input T, opts CompressOptions) (CompressResult[T], error) {
 	}
 
 	opt := opts.toOpOptions()
+	opt.Operation = "compress"
 
 	ctx := opt.Context
 	if ctx == nil {
@@ -71,6 +71,14 @@ type ConformResult[T any] struct {
 	// Standard is the standard that was applied
 	Standard string `json:"standard"`
 
+	// Rules are the concrete operations applied, replayable without an LLM
+	// via ApplyTransformRules
+	Rules []TransformRule `json:"rules,omitempty"`
+
+	// RulesReproduce reports whether replaying Rules on the input yields
+	// Conformed exactly
+	RulesReproduce bool `json:"rules_reproduce"`
+
 	// Metadata contains additional operation information
 	Metadata map[string]any `json:"metadata,omitempty"`
 }
@@ -134,12 +142,7 @@ func Conform[T any](input T, standard string, opts ...ConformOptions) (ConformRe
 	}
 
 	// Apply defaults
-	opt := ConformOptions{
-		PreserveUnknown: true,
-		Validate:        true,
-		Mode:            types.TransformMode,
-		Intelligence:    types.Fast,
-	}
+	opt := defaultConformOptions()
 	if len(opts) > 0 {
 		opt = mergeConformOptions(opt, opts[0])
 	}
@@ -159,25 +162,26 @@ func Conform[T any](input T, standard string, opts ...ConformOptions) (ConformRe
 		return result, fmt.Errorf("failed to marshal input: %w", err)
 	}
 
-	// Get type schema
-	typeSchema := GenerateTypeSchema(reflect.TypeOf(input))
-
-	// Build custom rules description
-	customRulesDesc := ""
-	if len(opt.CustomRules) > 0 {
-		var parts []string
-		for field, rule := range opt.CustomRules {
-			parts = append(parts, fmt.Sprintf("- %s: %s", field, rule))
+	// The schema and rules prompt depend only on the type, standard and
+	// options, so hot paths reuse them
+	customRules := ruleLines(opt.CustomRules)
+	ruleset := append([]string{standard, fmt.Sprint(opt.Strict)}, customRules...)
+	compiled := cachedRules("conform", reflect.TypeOf(input), opt.Intelligence, ruleset, func() compiledRules {
+		// Get type schema
+		typeSchema := GenerateTypeSchema(reflect.TypeOf(input))
+
+		// Build custom rules description
+		customRulesDesc := ""
+		if len(customRules) > 0 {
+			customRulesDesc = fmt.Sprintf("\n\nCustom rules:\n%s", strings.Join(customRules, "\n"))
 		}
-		customRulesDesc = fmt.Sprintf("\n\nCustom rules:\n%s", strings.Join(parts, "\n"))
-	}
 
-	strictNote := ""
-	if opt.Strict {
-		strictNote = "\nStrict mode: fail if any field cannot be fully conformed."
-	}
+		strictNote := ""
+		if opt.Strict {
+			strictNote = "\nStrict mode: fail if any field cannot be fully conformed."
+		}
 
-	systemPrompt := fmt.Sprintf(`You are a data standards compliance expert. Transform data to conform to the %s standard.
+		systemPrompt := fmt.Sprintf(`You are a data standards compliance expert. Transform data to conform to the %s standard.
 
 Data schema: %s%s%s
 
@@ -194,9 +198,16 @@ Return a JSON object with:
     }
   ],
   "violations": ["issues that could not be fixed"],
-  "compliance": 0.0-1.0
+  "compliance": 0.0-1.0,
+  "rules": [
+    {"field": "field_name", "op": "uppercase"}
+  ]
 }
 
+"rules" must list, in order, the deterministic operations that turn the input into the conformed output.
+Allowed ops (fields are top-level JSON string fields):
+%s
+
 Standard: %s
 Known standards:
 - USPS: US Postal Service address format (uppercase, abbreviated states, ZIP+4)
@@ -211,7 +222,10 @@ Rules:
 - Document all changes in adjustments
 - List any violations that couldn't be fixed
 - Calculate compliance as ratio of conforming fields`,
-		standard, typeSchema, customRulesDesc, strictNote, typeSchema, standard)
+			standard, typeSchema, customRulesDesc, strictNote, typeSchema, transformOpsDoc, standard)
+		return compiledRules{systemPrompt: systemPrompt}
+	})
+	systemPrompt := compiled.systemPrompt
 
 	steeringNote := ""
 	if opt.Steering != "" {
@@ -229,6 +243,7 @@ Rules:
 		Context:       ctx,
 		RequestID:     opt.RequestID,
 		CorrelationID: opt.CorrelationID,
+		Operation:     "conform",
 	}
 
 	response, err := callLLM(ctx, systemPrompt, userPrompt, opOpts)
@@ -255,6 +270,7 @@ Rules:
 		Adjustments []Adjustment    `json:"adjustments"`
 		Violations  []string        `json:"violations"`
 		Compliance  float64         `json:"compliance"`
+		Rules       []TransformRule `json:"rules"`
 	}
 
 	if err := json.Unmarshal([]byte(response), &parsed); err != nil {
@@ -278,6 +294,16 @@ Rules:
 	result.Adjustments = parsed.Adjustments
 	result.Violations = parsed.Violations
 	result.Compliance = parsed.Compliance
+	result.Rules = parsed.Rules
+
+	if len(result.Rules) > 0 {
+		replayed, err := ApplyTransformRules(input, result.Rules)
+		if err != nil {
+			log.Debug("Conform rules could not be replayed", "error", err)
+		} else {
+			result.RulesReproduce = reflect.DeepEqual(replayed, result.Conformed)
+		}
+	}
 
 	log.Debug("Conform operation succeeded",
 		"standard", standard,
@@ -288,6 +314,16 @@ Rules:
 	return result, nil
 }
 
+// defaultConformOptions returns the options Conform runs with when none are given
+func defaultConformOptions() ConformOptions {
+	return ConformOptions{
+		PreserveUnknown: true,
+		Validate:        true,
+		Mode:            types.TransformMode,
+		Intelligence:    types.Fast,
+	}
+}
+
 // mergeConformOptions merges user options with defaults
 func mergeConformOptions(defaults, user ConformOptions) ConformOptions {
 	// Strict and PreserveUnknown are bools, handle explicitly
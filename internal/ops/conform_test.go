@@ -0,0 +1,68 @@
+package ops
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+type conformAddress struct {
+	Name   string `json:"name"`
+	State  string `json:"state"`
+	Street string `json:"street"`
+	Since  string `json:"since"`
+}
+
+func TestConformRulesReplayWithoutModel(t *testing.T) {
+	defer setupMockClient()
+
+	raw := conformAddress{Name: "john doe", State: "california", Street: "123 north main street", Since: "01/15/2024"}
+
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		return `{
+			"conformed": {"name": "JOHN DOE", "state": "CA", "street": "123 N MAIN ST", "since": "2024-01-15"},
+			"compliance": 1.0,
+			"rules": [
+				{"field": "name", "op": "uppercase"},
+				{"field": "state", "op": "map", "mapping": {"california": "CA"}},
+				{"field": "street", "op": "abbreviate", "mapping": {"north": "N", "street": "ST"}},
+				{"field": "street", "op": "uppercase"},
+				{"field": "since", "op": "reformat_date", "from": "MM/DD/YYYY", "to": "YYYY-MM-DD"}
+			]
+		}`, nil
+	})
+
+	result, err := Conform(raw, "USPS")
+	if err != nil {
+		t.Fatalf("Conform() error = %v", err)
+	}
+	if len(result.Rules) != 5 {
+		t.Fatalf("Rules = %v, want 5", result.Rules)
+	}
+	if !result.RulesReproduce {
+		t.Error("RulesReproduce = false, want replay to match conformed output")
+	}
+
+	// Replaying on a second identical record must not touch the model
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		t.Error("model called during replay")
+		return "", errors.New("unexpected call")
+	})
+
+	replayed, err := ApplyTransformRules(raw, result.Rules)
+	if err != nil {
+		t.Fatalf("ApplyTransformRules() error = %v", err)
+	}
+	if replayed != result.Conformed {
+		t.Errorf("replayed = %+v, want %+v", replayed, result.Conformed)
+	}
+}
+
+func TestApplyTransformRulesRejectsUnknownOp(t *testing.T) {
+	_, err := ApplyTransformRules(conformAddress{Name: "x"}, []TransformRule{{Field: "name", Op: "rot13"}})
+	if err == nil {
+		t.Error("expected error for unsupported op")
+	}
+}
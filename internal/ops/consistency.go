@@ -0,0 +1,343 @@
+// package ops - Cross-field consistency rules checked after extraction
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/monstercameron/schemaflow/internal/logger"
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+// ConsistentResult is an extraction checked against WithConsistencyRules
+type ConsistentResult[T any] struct {
+	// Value is the extracted data, after any corrective re-prompt
+	Value T `json:"value"`
+
+	// ConsistencyRepairs describes each field the corrective re-prompt
+	// changed, as "path: old -> new"
+	ConsistencyRepairs []string `json:"consistency_repairs,omitempty"`
+
+	// Violations lists the rules, and the fields not matching their `pattern`
+	// tag, that still failed after the re-prompt
+	Violations []string `json:"violations,omitempty"`
+
+	// PIIFields lists the fields of Value tagged `pii:"true"` that hold a
+	// value (see PIIFields)
+	PIIFields []string `json:"pii_fields,omitempty"`
+}
+
+// ExtractConsistent extracts T like Extract and reports how the consistency
+// rules set with WithConsistencyRules were enforced. Rules are arithmetic
+// comparisons over the dotted JSON paths of T's fields (not through slices),
+// such as "total == subtotal + tax" or "totals.tax <= totals.net * 0.25".
+// When a rule fails, the model is re-prompted once with the violations;
+// fields it changed are listed in ConsistencyRepairs, and rules still failing
+// make the call return an ExtractError along with the result.
+//
+// String fields tagged `pattern:"^\\+?[0-9-]+$"` are checked the same way: a
+// non-empty value that does not match its regular expression is re-prompted
+// once and, if still malformed, flagged in Violations.
+//
+// Example:
+//
+//	result, err := ExtractConsistent[Invoice](document, NewExtractOptions().
+//	    WithConsistencyRules([]string{"total == subtotal + tax"}))
+//	fmt.Println(result.Value.Total, result.ConsistencyRepairs)
+func ExtractConsistent[T any](input any, opts ExtractOptions) (ConsistentResult[T], error) {
+	result, err := extractConsistent[T](input, opts, nil)
+	result.PIIFields = PIIFields(result.Value)
+	return result, err
+}
+
+// extractConsistent runs extract and, when consistency rules are set or T
+// has pattern-tagged fields, checks them and re-prompts once on violation
+func extractConsistent[T any](input any, opts ExtractOptions, report *extractReport) (ConsistentResult[T], error) {
+	var result ConsistentResult[T]
+	var cancelOperation context.CancelFunc
+	opts.CommonOptions, cancelOperation = opts.CommonOptions.startOperation()
+	defer cancelOperation()
+	patterns, patternErr := fieldPatterns(reflect.TypeOf(result.Value))
+	if len(opts.ConsistencyRules) == 0 && len(patterns) == 0 && patternErr == nil {
+		value, err := extract[T](input, opts, report)
+		result.Value = value
+		return result, err
+	}
+
+	fail := func(reason string, cause error) error {
+		return types.ExtractError{
+			Input:      input,
+			TargetType: reflect.TypeOf(result.Value).String(),
+			Reason:     reason,
+			Cause:      cause,
+			RequestID:  opts.CommonOptions.RequestID,
+			Timestamp:  time.Now(),
+		}
+	}
+
+	rules := make([]consistencyRule, len(opts.ConsistencyRules))
+	for i, text := range opts.ConsistencyRules {
+		rule, err := parseConsistencyRule(text)
+		if err != nil {
+			return result, fmt.Errorf("invalid options: %w", err)
+		}
+		for _, path := range rule.fields {
+			if err := checkFieldPath(reflect.TypeOf(result.Value), path); err != nil {
+				return result, fail(fmt.Sprintf("consistency rule %q: %v", text, err), err)
+			}
+		}
+		rules[i] = rule
+	}
+	if patternErr != nil {
+		return result, fail(patternErr.Error(), patternErr)
+	}
+	check := func(value T) []string {
+		return append(checkConsistencyRules(value, rules), checkFieldPatterns(value)...)
+	}
+
+	value, err := extract[T](input, opts, report)
+	result.Value = value
+	if err != nil {
+		return result, err
+	}
+	violations := check(value)
+	if len(violations) == 0 {
+		return result, nil
+	}
+
+	logger.GetLogger().Debug("Extract result violates consistency rules or field patterns, re-prompting", "requestID", opts.CommonOptions.RequestID, "violations", violations)
+	previous, _ := json.Marshal(value)
+	repairOpts := opts
+	repairOpts.consistencyRepair = fmt.Sprintf(`
+
+Your previous answer violated these consistency rules or field formats:
+- %s
+
+Previous answer:
+%s
+
+Re-read the input and return the full corrected JSON. Fix the fields the input actually supports, so that every rule holds and every formatted field matches its pattern; keep the other values unchanged.`, strings.Join(violations, "\n- "), previous)
+
+	repaired, err := extract[T](input, repairOpts, report)
+	if err != nil {
+		return result, err
+	}
+	result.Value = repaired
+	result.ConsistencyRepairs = append(consistencyRepairs(value, repaired, rules), patternRepairs(value, repaired)...)
+	if result.Violations = check(repaired); len(result.Violations) > 0 {
+		return result, fail("consistency rules or field patterns still violated after re-prompt: "+strings.Join(result.Violations, "; "), nil)
+	}
+	return result, nil
+}
+
+// consistencyRule is a parsed comparison between two arithmetic expressions
+type consistencyRule struct {
+	text        string
+	op          token.Token
+	left, right ast.Expr
+	fields      []string
+}
+
+var consistencyOperators = map[token.Token]bool{
+	token.EQL: true, token.NEQ: true, token.LSS: true, token.LEQ: true, token.GTR: true, token.GEQ: true,
+}
+
+// parseConsistencyRule parses rules such as "total == subtotal + tax"
+func parseConsistencyRule(text string) (consistencyRule, error) {
+	rule := consistencyRule{text: text}
+	expr, err := parser.ParseExpr(text)
+	if err != nil {
+		return rule, fmt.Errorf("consistency rule %q: %v", text, err)
+	}
+	comparison, ok := expr.(*ast.BinaryExpr)
+	if !ok || !consistencyOperators[comparison.Op] {
+		return rule, fmt.Errorf("consistency rule %q must compare two expressions with ==, !=, <, <=, > or >=", text)
+	}
+	rule.op, rule.left, rule.right = comparison.Op, comparison.X, comparison.Y
+
+	var walk func(ast.Expr) error
+	walk = func(node ast.Expr) error {
+		switch n := node.(type) {
+		case *ast.BasicLit:
+			if n.Kind != token.INT && n.Kind != token.FLOAT {
+				return fmt.Errorf("consistency rule %q: unsupported literal %s", text, n.Value)
+			}
+		case *ast.Ident, *ast.SelectorExpr:
+			path, ok := rulePath(n)
+			if !ok {
+				return fmt.Errorf("consistency rule %q: unsupported field reference", text)
+			}
+			rule.fields = append(rule.fields, path)
+		case *ast.ParenExpr:
+			return walk(n.X)
+		case *ast.UnaryExpr:
+			if n.Op != token.SUB && n.Op != token.ADD {
+				return fmt.Errorf("consistency rule %q: unsupported operator %s", text, n.Op)
+			}
+			return walk(n.X)
+		case *ast.BinaryExpr:
+			if n.Op != token.ADD && n.Op != token.SUB && n.Op != token.MUL && n.Op != token.QUO {
+				return fmt.Errorf("consistency rule %q: unsupported operator %s", text, n.Op)
+			}
+			if err := walk(n.X); err != nil {
+				return err
+			}
+			return walk(n.Y)
+		default:
+			return fmt.Errorf("consistency rule %q: unsupported expression", text)
+		}
+		return nil
+	}
+	if err := walk(rule.left); err != nil {
+		return rule, err
+	}
+	return rule, walk(rule.right)
+}
+
+// rulePath turns an identifier or selector chain into a dotted JSON path
+func rulePath(node ast.Expr) (string, bool) {
+	switch n := node.(type) {
+	case *ast.Ident:
+		return n.Name, true
+	case *ast.SelectorExpr:
+		parent, ok := rulePath(n.X)
+		return parent + "." + n.Sel.Name, ok
+	}
+	return "", false
+}
+
+// checkConsistencyRules lists the rules value breaks, with the values compared
+func checkConsistencyRules(value any, rules []consistencyRule) []string {
+	decoded := decodeForRules(value)
+	var violations []string
+	for _, rule := range rules {
+		left, errLeft := evalRuleExpr(rule.left, decoded)
+		right, errRight := evalRuleExpr(rule.right, decoded)
+		if errLeft != nil || errRight != nil {
+			violations = append(violations, fmt.Sprintf("%s cannot be evaluated: %v", rule.text, firstError(errLeft, errRight)))
+			continue
+		}
+		if !ruleHolds(rule.op, left, right) {
+			violations = append(violations, fmt.Sprintf("%s fails (%g %s %g)", rule.text, left, rule.op, right))
+		}
+	}
+	return violations
+}
+
+// consistencyRepairs lists the rule fields whose values differ between the
+// original and the repaired extraction
+func consistencyRepairs(original, repaired any, rules []consistencyRule) []string {
+	before, after := decodeForRules(original), decodeForRules(repaired)
+	var repairs []string
+	seen := map[string]bool{}
+	for _, rule := range rules {
+		for _, path := range rule.fields {
+			if seen[path] {
+				continue
+			}
+			seen[path] = true
+			old, updated := fieldValue(before, path), fieldValue(after, path)
+			if !reflect.DeepEqual(old, updated) {
+				repairs = append(repairs, fmt.Sprintf("%s: %v -> %v", path, old, updated))
+			}
+		}
+	}
+	return repairs
+}
+
+func decodeForRules(value any) any {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var decoded any
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	if decoder.Decode(&decoded) != nil {
+		return nil
+	}
+	return decoded
+}
+
+func evalRuleExpr(node ast.Expr, record any) (float64, error) {
+	switch n := node.(type) {
+	case *ast.BasicLit:
+		return strconv.ParseFloat(n.Value, 64)
+	case *ast.Ident, *ast.SelectorExpr:
+		path, _ := rulePath(n)
+		number, ok := sortNumber(fieldValue(record, path))
+		if !ok {
+			return 0, fmt.Errorf("%s is missing or not a number", path)
+		}
+		return number, nil
+	case *ast.ParenExpr:
+		return evalRuleExpr(n.X, record)
+	case *ast.UnaryExpr:
+		x, err := evalRuleExpr(n.X, record)
+		if n.Op == token.SUB {
+			x = -x
+		}
+		return x, err
+	case *ast.BinaryExpr:
+		x, err := evalRuleExpr(n.X, record)
+		if err != nil {
+			return 0, err
+		}
+		y, err := evalRuleExpr(n.Y, record)
+		if err != nil {
+			return 0, err
+		}
+		switch n.Op {
+		case token.ADD:
+			return x + y, nil
+		case token.SUB:
+			return x - y, nil
+		case token.MUL:
+			return x * y, nil
+		case token.QUO:
+			if y == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			return x / y, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported expression")
+}
+
+// ruleHolds compares two values; equality tolerates floating-point rounding
+func ruleHolds(op token.Token, a, b float64) bool {
+	equal := math.Abs(a-b) <= 1e-9*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+	switch op {
+	case token.EQL:
+		return equal
+	case token.NEQ:
+		return !equal
+	case token.LSS:
+		return a < b && !equal
+	case token.LEQ:
+		return a < b || equal
+	case token.GTR:
+		return a > b && !equal
+	case token.GEQ:
+		return a > b || equal
+	}
+	return false
+}
+
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
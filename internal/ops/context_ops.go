@@ -0,0 +1,447 @@
+// package ops - Context-first variants of the core operations
+package ops
+
+import (
+	"context"
+
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+// The functions below run an operation under ctx, so a cancelled request or
+// an expired deadline stops the model call and surfaces ctx.Err(), wrapped in
+// the operation's error type. They replace any context set on the options;
+// the plain functions run with context.Background() when none is set.
+
+// ExtractCtx is Extract run under ctx
+func ExtractCtx[T any](ctx context.Context, input any, opts ExtractOptions) (T, error) {
+	opts.CommonOptions.Context = ctx
+	return Extract[T](input, opts)
+}
+
+// ExtractStreamCtx is ExtractStream run under ctx; cancelling ctx stops the
+// stream and closes its channel
+func ExtractStreamCtx[T any](ctx context.Context, input any, opts ExtractOptions) (<-chan ExtractSnapshot[T], error) {
+	opts.CommonOptions.Context = ctx
+	return ExtractStream[T](input, opts)
+}
+
+// TransformCtx is Transform run under ctx
+func TransformCtx[T any, U any](ctx context.Context, input T, opts TransformOptions) (U, error) {
+	opts.CommonOptions.Context = ctx
+	return Transform[T, U](input, opts)
+}
+
+// GenerateCtx is Generate run under ctx
+func GenerateCtx[T any](ctx context.Context, prompt string, opts GenerateOptions) (T, error) {
+	opts.CommonOptions.Context = ctx
+	return Generate[T](prompt, opts)
+}
+
+// SummarizeCtx is Summarize run under ctx
+func SummarizeCtx(ctx context.Context, input string, opts SummarizeOptions) (string, error) {
+	opts.CommonOptions.Context = ctx
+	return Summarize(input, opts)
+}
+
+// RewriteCtx is Rewrite run under ctx
+func RewriteCtx(ctx context.Context, input string, opts RewriteOptions) (string, error) {
+	opts.CommonOptions.Context = ctx
+	return Rewrite(input, opts)
+}
+
+// TranslateCtx is Translate run under ctx
+func TranslateCtx(ctx context.Context, input string, opts TranslateOptions) (string, error) {
+	opts.CommonOptions.Context = ctx
+	return Translate(input, opts)
+}
+
+// ExpandCtx is Expand run under ctx
+func ExpandCtx(ctx context.Context, input string, opts ExpandOptions) (string, error) {
+	opts.CommonOptions.Context = ctx
+	return Expand(input, opts)
+}
+
+// ClassifyCtx is Classify run under ctx
+func ClassifyCtx[T any, C any](ctx context.Context, input T, opts ClassifyOptions) (ClassifyResult[C], error) {
+	opts.CommonOptions.Context = ctx
+	return Classify[T, C](input, opts)
+}
+
+// ScoreCtx is Score run under ctx
+func ScoreCtx[T any](ctx context.Context, input T, opts ScoreOptions) (ScoreResult, error) {
+	opts.CommonOptions.Context = ctx
+	return Score(input, opts)
+}
+
+// CompareCtx is Compare run under ctx
+func CompareCtx[T any](ctx context.Context, itemA, itemB T, opts CompareOptions) (CompareResult[T], error) {
+	opts.CommonOptions.Context = ctx
+	return Compare(itemA, itemB, opts)
+}
+
+// ChooseCtx is Choose run under ctx
+func ChooseCtx[T any](ctx context.Context, options []T, opts ChooseOptions) (T, error) {
+	opts.CommonOptions.Context = ctx
+	return Choose(options, opts)
+}
+
+// FilterCtx is Filter run under ctx
+func FilterCtx[T any](ctx context.Context, items []T, opts FilterOptions) ([]T, error) {
+	opts.CommonOptions.Context = ctx
+	return Filter(items, opts)
+}
+
+// SortCtx is Sort run under ctx
+func SortCtx[T any](ctx context.Context, items []T, opts SortOptions) ([]T, error) {
+	opts.CommonOptions.Context = ctx
+	return Sort(items, opts)
+}
+
+// ValidateCtx is Validate run under ctx
+func ValidateCtx[T any](ctx context.Context, data T, opts ValidateOptions) (ValidateResult[T], error) {
+	opts.CommonOptions.Context = ctx
+	return Validate(data, opts)
+}
+
+// QuestionCtx is Question run under ctx
+func QuestionCtx[T any, A any](ctx context.Context, data T, opts QuestionOptions) (QuestionResult[A], error) {
+	opts.CommonOptions.Context = ctx
+	return Question[T, A](data, opts)
+}
+
+// AnnotateCtx is Annotate run under ctx
+func AnnotateCtx[T any](ctx context.Context, input T, opts AnnotateOptions) (AnnotateResult, error) {
+	opts.CommonOptions.Context = ctx
+	return Annotate(input, opts)
+}
+
+// ClusterCtx is Cluster run under ctx
+func ClusterCtx[T any](ctx context.Context, items []T, opts ClusterOptions) (ClusterResult[T], error) {
+	opts.CommonOptions.Context = ctx
+	return Cluster(items, opts)
+}
+
+// RankCtx is Rank run under ctx
+func RankCtx[T any](ctx context.Context, items []T, opts RankOptions) (RankResult[T], error) {
+	opts.CommonOptions.Context = ctx
+	return Rank(items, opts)
+}
+
+// EnrichCtx is Enrich run under ctx
+func EnrichCtx[T any, U any](ctx context.Context, input T, opts EnrichOptions) (EnrichResult[U], error) {
+	opts.CommonOptions.Context = ctx
+	return Enrich[T, U](input, opts)
+}
+
+// VerifyCtx is Verify run under ctx
+func VerifyCtx(ctx context.Context, input any, opts VerifyOptions) (VerifyResult, error) {
+	opts.CommonOptions.Context = ctx
+	return Verify(input, opts)
+}
+
+// PredictCtx is Predict run under ctx
+func PredictCtx[T any](ctx context.Context, historicalData any, opts PredictOptions) (PredictResult[T], error) {
+	opts.CommonOptions.Context = ctx
+	return Predict[T](historicalData, opts)
+}
+
+// NormalizeCtx is Normalize run under ctx
+func NormalizeCtx[T any](ctx context.Context, input T, opts NormalizeOptions) (NormalizeResult[T], error) {
+	opts.CommonOptions.Context = ctx
+	return Normalize(input, opts)
+}
+
+// CompressCtx is Compress run under ctx
+func CompressCtx[T any](ctx context.Context, input T, opts CompressOptions) (CompressResult[T], error) {
+	opts.CommonOptions.Context = ctx
+	return Compress(input, opts)
+}
+
+// DecomposeCtx is Decompose run under ctx
+func DecomposeCtx[T any](ctx context.Context, input T, opts DecomposeOptions) (DecomposeResult[T], error) {
+	opts.CommonOptions.Context = ctx
+	return Decompose(input, opts)
+}
+
+// CritiqueCtx is Critique run under ctx
+func CritiqueCtx[T any](ctx context.Context, input T, opts CritiqueOptions) (CritiqueResult, error) {
+	opts.CommonOptions.Context = ctx
+	return Critique(input, opts)
+}
+
+// SynthesizeCtx is Synthesize run under ctx
+func SynthesizeCtx[T any](ctx context.Context, sources []any, opts SynthesizeOptions) (SynthesizeResult[T], error) {
+	opts.CommonOptions.Context = ctx
+	return Synthesize[T](sources, opts)
+}
+
+// SemanticMatchCtx is SemanticMatch run under ctx
+func SemanticMatchCtx[S any, T any](ctx context.Context, sources []S, targets []T, opts MatchOptions) (MatchResult[S, T], error) {
+	opts.CommonOptions.Context = ctx
+	return SemanticMatch(sources, targets, opts)
+}
+
+// SuggestCtx is Suggest run under ctx
+func SuggestCtx[T any](ctx context.Context, input any, opts SuggestOptions) ([]T, error) {
+	opts.CommonOptions.Context = ctx
+	return Suggest[T](input, opts)
+}
+
+// InferCtx is Infer run under ctx
+func InferCtx[T any](ctx context.Context, partialData T, opts InferOptions) (T, error) {
+	opts.OpOptions.Context = ctx
+	return Infer(partialData, opts)
+}
+
+// DiffCtx is Diff run under ctx
+func DiffCtx[T any](ctx context.Context, oldData, newData T, opts DiffOptions) (DiffResult, error) {
+	opts.OpOptions.Context = ctx
+	return Diff(oldData, newData, opts)
+}
+
+// ExplainCtx is Explain run under ctx
+func ExplainCtx(ctx context.Context, data any, opts ExplainOptions) (ExplainResult, error) {
+	opts.OpOptions.Context = ctx
+	return Explain(data, opts)
+}
+
+// ParseCtx is Parse run under ctx
+func ParseCtx[T any](ctx context.Context, input any, opts ParseOptions) (ParseResult[T], error) {
+	opts.OpOptions.Context = ctx
+	return Parse[T](input, opts)
+}
+
+// AuditCtx is Audit run under ctx
+func AuditCtx[T any](ctx context.Context, data T, opts ...AuditOptions) (AuditResult[T], error) {
+	opt := defaultAuditOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	opt.Context = ctx
+	return Audit(data, opt)
+}
+
+// NegotiateCtx is Negotiate run under ctx
+func NegotiateCtx[T any](ctx context.Context, constraints any, opts ...NegotiateOptions) (NegotiateResult[T], error) {
+	opt := defaultNegotiateOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	opt.Context = ctx
+	return Negotiate[T](constraints, opt)
+}
+
+// ConformCtx is Conform run under ctx
+func ConformCtx[T any](ctx context.Context, input T, standard string, opts ...ConformOptions) (ConformResult[T], error) {
+	opt := defaultConformOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	opt.Context = ctx
+	return Conform(input, standard, opt)
+}
+
+// ArbitrateCtx is Arbitrate run under ctx
+func ArbitrateCtx[T any](ctx context.Context, options []T, opts ...ArbitrateOptions) (ArbitrateResult[T], error) {
+	opt := defaultArbitrateOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	opt.Context = ctx
+	return Arbitrate(options, opt)
+}
+
+// ProjectCtx is Project run under ctx
+func ProjectCtx[T any, U any](ctx context.Context, input T, opts ...ProjectOptions) (ProjectResult[U], error) {
+	opt := defaultProjectOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	opt.Context = ctx
+	return Project[T, U](input, opt)
+}
+
+// PivotCtx is Pivot run under ctx
+func PivotCtx[T any, U any](ctx context.Context, input T, opts ...PivotOptions) (PivotResult[U], error) {
+	opt := defaultPivotOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	opt.Context = ctx
+	return Pivot[T, U](input, opt)
+}
+
+// ResolveCtx is Resolve run under ctx
+func ResolveCtx[T any](ctx context.Context, sources []T, opts ...ResolveOptions) (ResolveResult[T], error) {
+	opt := defaultResolveOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	opt.Context = ctx
+	return Resolve(sources, opt)
+}
+
+// DeriveCtx is Derive run under ctx
+func DeriveCtx[T any, U any](ctx context.Context, input T, opts ...DeriveOptions) (DeriveResult[U], error) {
+	opt := defaultDeriveOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	opt.Context = ctx
+	return Derive[T, U](input, opt)
+}
+
+// InterpolateCtx is Interpolate run under ctx
+func InterpolateCtx[T any](ctx context.Context, items []T, opts ...InterpolateOptions) (InterpolateResult[T], error) {
+	opt := defaultInterpolateOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	opt.Context = ctx
+	return Interpolate(items, opt)
+}
+
+// MergeCtx is Merge run under ctx
+func MergeCtx[T any](ctx context.Context, sources []T, strategy string, opts ...types.OpOptions) (T, error) {
+	return Merge(sources, strategy, applyDefaultsUnder(ctx, opts...))
+}
+
+// FormatCtx is Format run under ctx
+func FormatCtx(ctx context.Context, data any, template string, opts ...types.OpOptions) (string, error) {
+	return Format(data, template, applyDefaultsUnder(ctx, opts...))
+}
+
+// DeduplicateCtx is Deduplicate run under ctx
+func DeduplicateCtx[T any](ctx context.Context, items []T, threshold float64, opts ...types.OpOptions) (DeduplicateResult[T], error) {
+	return Deduplicate(items, threshold, applyDefaultsUnder(ctx, opts...))
+}
+
+// DecideCtx is Decide run under ctx; input is what Decide calls its context,
+// the value the decisions are made about
+func DecideCtx[T any](ctx context.Context, input any, decisions []Decision[T], opts ...types.OpOptions) (T, DecisionResult, error) {
+	return Decide(input, decisions, applyDefaultsUnder(ctx, opts...))
+}
+
+// TransformWithMetadataCtx is TransformWithMetadata run under ctx
+func TransformWithMetadataCtx[T any, U any](ctx context.Context, input T, opts TransformOptions) (TransformResult[U], error) {
+	opts.CommonOptions.Context = ctx
+	return TransformWithMetadata[T, U](input, opts)
+}
+
+// SummarizeWithMetadataCtx is SummarizeWithMetadata run under ctx
+func SummarizeWithMetadataCtx(ctx context.Context, input string, opts SummarizeOptions) (SummarizeResult, error) {
+	opts.CommonOptions.Context = ctx
+	return SummarizeWithMetadata(input, opts)
+}
+
+// RewriteWithMetadataCtx is RewriteWithMetadata run under ctx
+func RewriteWithMetadataCtx(ctx context.Context, input string, opts RewriteOptions) (RewriteResult, error) {
+	opts.CommonOptions.Context = ctx
+	return RewriteWithMetadata(input, opts)
+}
+
+// TranslateWithMetadataCtx is TranslateWithMetadata run under ctx
+func TranslateWithMetadataCtx(ctx context.Context, input string, opts TranslateOptions) (TranslateResult, error) {
+	opts.CommonOptions.Context = ctx
+	return TranslateWithMetadata(input, opts)
+}
+
+// ExpandWithMetadataCtx is ExpandWithMetadata run under ctx
+func ExpandWithMetadataCtx(ctx context.Context, input string, opts ExpandOptions) (ExpandResult, error) {
+	opts.CommonOptions.Context = ctx
+	return ExpandWithMetadata(input, opts)
+}
+
+// MergeWithMetadataCtx is MergeWithMetadata run under ctx
+func MergeWithMetadataCtx[T any](ctx context.Context, sources []T, strategy string, opts ...types.OpOptions) (MergeResult[T], error) {
+	return MergeWithMetadata(sources, strategy, applyDefaultsUnder(ctx, opts...))
+}
+
+// FormatWithMetadataCtx is FormatWithMetadata run under ctx
+func FormatWithMetadataCtx(ctx context.Context, data any, template string, opts ...types.OpOptions) (FormatResult, error) {
+	return FormatWithMetadata(data, template, applyDefaultsUnder(ctx, opts...))
+}
+
+// SimilarCtx is Similar run under ctx
+func SimilarCtx[T any](ctx context.Context, itemA, itemB T, opts SimilarOptions) (SimilarResult, error) {
+	opts.OpOptions.Context = ctx
+	return Similar(itemA, itemB, opts)
+}
+
+// RedactCtx is Redact run under ctx. Redaction is local, so ctx is only
+// checked before it starts.
+func RedactCtx[T any](ctx context.Context, input T, opts RedactOptions) (T, error) {
+	if err := ctx.Err(); err != nil {
+		var zero T
+		return zero, err
+	}
+	opts.OpOptions.Context = ctx
+	return Redact(input, opts)
+}
+
+// GuardCtx is Guard with the suggestions for failed checks requested under ctx
+func GuardCtx[T any](ctx context.Context, state T, checks ...func(T) (bool, string)) GuardResult {
+	return guard(ctx, state, checks...)
+}
+
+// VerifyClaimCtx is VerifyClaim run under ctx
+func VerifyClaimCtx(ctx context.Context, claim string, opts VerifyOptions) (ClaimVerification, error) {
+	opts.CommonOptions.Context = ctx
+	return VerifyClaim(claim, opts)
+}
+
+// CompressTextCtx is CompressText run under ctx
+func CompressTextCtx(ctx context.Context, input string, opts CompressOptions) (string, error) {
+	opts.CommonOptions.Context = ctx
+	return CompressText(input, opts)
+}
+
+// NormalizeTextCtx is NormalizeText run under ctx
+func NormalizeTextCtx(ctx context.Context, input string, opts NormalizeOptions) (string, error) {
+	opts.CommonOptions.Context = ctx
+	return NormalizeText(input, opts)
+}
+
+// NormalizeBatchCtx is NormalizeBatch run under ctx
+func NormalizeBatchCtx[T any](ctx context.Context, items []T, opts NormalizeOptions) (BatchResult[NormalizeResult[T]], error) {
+	opts.CommonOptions.Context = ctx
+	return NormalizeBatch(items, opts)
+}
+
+// MatchOneCtx is MatchOne run under ctx
+func MatchOneCtx[S any, T any](ctx context.Context, source S, targets []T, opts MatchOptions) ([]MatchPair[S, T], error) {
+	opts.CommonOptions.Context = ctx
+	return MatchOne(source, targets, opts)
+}
+
+// AssembleCtx is Assemble run under ctx
+func AssembleCtx[T any](ctx context.Context, parts []any, opts ...ComposeOptions) (ComposeResult[T], error) {
+	opt := defaultComposeOptions()
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	opt.Context = ctx
+	return Assemble[T](parts, opt)
+}
+
+// EnrichInPlaceCtx is EnrichInPlace run under ctx
+func EnrichInPlaceCtx[T any](ctx context.Context, input T, opts EnrichOptions) (T, error) {
+	opts.CommonOptions.Context = ctx
+	return EnrichInPlace(input, opts)
+}
+
+// DecomposeToSliceCtx is DecomposeToSlice run under ctx
+func DecomposeToSliceCtx[T any, U any](ctx context.Context, input T, opts DecomposeOptions) ([]U, error) {
+	opts.CommonOptions.Context = ctx
+	return DecomposeToSlice[T, U](input, opts)
+}
+
+// NegotiateAdversarialCtx is NegotiateAdversarial run under ctx
+func NegotiateAdversarialCtx[T any](ctx context.Context, adversarial AdversarialContext[T], opts ...AdversarialOptions) (AdversarialResult[T], error) {
+	var opt AdversarialOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	opt.Context = ctx
+	return NegotiateAdversarial[T](adversarial, opt)
+}
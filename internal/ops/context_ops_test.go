@@ -0,0 +1,180 @@
+package ops
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+func TestCtxVariantsStopWhenContextIsCancelled(t *testing.T) {
+	defer setupMockClient()
+
+	type person struct {
+		Name string `json:"name"`
+	}
+	cases := map[string]func(ctx context.Context) error{
+		"Extract": func(ctx context.Context) error {
+			_, err := ExtractCtx[person](ctx, "Ada Lovelace", NewExtractOptions())
+			return err
+		},
+		"Summarize": func(ctx context.Context) error {
+			_, err := SummarizeCtx(ctx, "A long report about quarterly results.", NewSummarizeOptions())
+			return err
+		},
+		"Filter": func(ctx context.Context) error {
+			_, err := FilterCtx(ctx, []string{"a", "b"}, NewFilterOptions().WithCriteria("vowels"))
+			return err
+		},
+		"Infer": func(ctx context.Context) error {
+			_, err := InferCtx(ctx, person{Name: "Ada"}, NewInferOptions())
+			return err
+		},
+		"Merge": func(ctx context.Context) error {
+			_, err := MergeCtx(ctx, []person{{Name: "Ada"}, {Name: "Ada Lovelace"}}, "most-complete")
+			return err
+		},
+		"Audit": func(ctx context.Context) error {
+			_, err := AuditCtx(ctx, person{Name: "Ada"})
+			return err
+		},
+		"Explain": func(ctx context.Context) error {
+			_, err := ExplainCtx(ctx, person{Name: "Ada"}, NewExplainOptions())
+			return err
+		},
+		"Complete": func(ctx context.Context) error {
+			_, err := Complete(ctx, nil, "Once upon a time", NewCompleteOptions())
+			return err
+		},
+		"CompleteField": func(ctx context.Context) error {
+			_, err := CompleteField(ctx, nil, person{Name: "Ada"}, NewCompleteFieldOptions("Name"))
+			return err
+		},
+		"Similar": func(ctx context.Context) error {
+			_, err := SimilarCtx(ctx, "a cat", "a kitten", NewSimilarOptions())
+			return err
+		},
+		"RedactLLM": func(ctx context.Context) error {
+			_, err := RedactLLM(ctx, "Call Ada on 555-0100", NewRedactLLMOptions())
+			return err
+		},
+		"VerifyClaim": func(ctx context.Context) error {
+			_, err := VerifyClaimCtx(ctx, "Water boils at 100C at sea level.", NewVerifyOptions())
+			return err
+		},
+		"CompressText": func(ctx context.Context) error {
+			_, err := CompressTextCtx(ctx, "A long and repetitive report about quarterly results.", NewCompressOptions())
+			return err
+		},
+		"NormalizeText": func(ctx context.Context) error {
+			_, err := NormalizeTextCtx(ctx, "  ADA   lovelace ", NewNormalizeOptions())
+			return err
+		},
+		"NormalizeBatch": func(ctx context.Context) error {
+			_, err := NormalizeBatchCtx(ctx, []string{"ADA lovelace"}, NewNormalizeOptions())
+			return err
+		},
+		"MatchOne": func(ctx context.Context) error {
+			_, err := MatchOneCtx(ctx, "Ada", []string{"Ada Lovelace", "Alan Turing"}, NewMatchOptions())
+			return err
+		},
+		"Assemble": func(ctx context.Context) error {
+			_, err := AssembleCtx[person](ctx, []any{map[string]string{"name": "Ada"}})
+			return err
+		},
+		"EnrichInPlace": func(ctx context.Context) error {
+			_, err := EnrichInPlaceCtx(ctx, person{Name: "Ada"}, NewEnrichOptions())
+			return err
+		},
+		"DecomposeToSlice": func(ctx context.Context) error {
+			_, err := DecomposeToSliceCtx[string, string](ctx, "Plan a product launch", NewDecomposeOptions())
+			return err
+		},
+		"NegotiateAdversarial": func(ctx context.Context) error {
+			_, err := NegotiateAdversarialCtx(ctx, AdversarialContext[person]{OurLeverage: "balanced"})
+			return err
+		},
+		"SummarizeWithMetadata": func(ctx context.Context) error {
+			_, err := SummarizeWithMetadataCtx(ctx, "A long report about quarterly results.", NewSummarizeOptions())
+			return err
+		},
+		"RewriteWithMetadata": func(ctx context.Context) error {
+			_, err := RewriteWithMetadataCtx(ctx, "A draft sentence.", NewRewriteOptions())
+			return err
+		},
+		"TranslateWithMetadata": func(ctx context.Context) error {
+			_, err := TranslateWithMetadataCtx(ctx, "Good morning", NewTranslateOptions().WithTargetLanguage("French"))
+			return err
+		},
+		"ExpandWithMetadata": func(ctx context.Context) error {
+			_, err := ExpandWithMetadataCtx(ctx, "Quarterly results", NewExpandOptions())
+			return err
+		},
+		"MergeWithMetadata": func(ctx context.Context) error {
+			_, err := MergeWithMetadataCtx(ctx, []person{{Name: "Ada"}, {Name: "Ada Lovelace"}}, "most-complete")
+			return err
+		},
+		"FormatWithMetadata": func(ctx context.Context) error {
+			_, err := FormatWithMetadataCtx(ctx, person{Name: "Ada"}, "Name: {name}")
+			return err
+		},
+	}
+
+	for name, run := range cases {
+		t.Run(name, func(t *testing.T) {
+			started := make(chan struct{})
+			var once sync.Once
+			setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+				once.Do(func() { close(started) })
+				<-ctx.Done()
+				return "", ctx.Err()
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				<-started
+				cancel()
+			}()
+
+			done := make(chan error, 1)
+			go func() { done <- run(ctx) }()
+
+			select {
+			case err := <-done:
+				if !errors.Is(err, context.Canceled) {
+					t.Fatalf("expected context.Canceled, got %v", err)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("operation did not return after its context was cancelled")
+			}
+		})
+	}
+}
+
+func TestLocalCtxVariantsHonorTheirContext(t *testing.T) {
+	defer setupMockClient()
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := RedactCtx(cancelled, "ada@example.com", NewRedactOptions()); !errors.Is(err, context.Canceled) {
+		t.Errorf("RedactCtx() error = %v, want context.Canceled", err)
+	}
+	if got, err := RedactCtx(context.Background(), "ada@example.com", NewRedactOptions()); err != nil || got == "ada@example.com" {
+		t.Errorf("RedactCtx() = %q, %v, want the email redacted", got, err)
+	}
+
+	// Suggestions for failed checks are requested under the guard's context
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		return "Add a name", nil
+	})
+	failing := func(name string) (bool, string) { return name != "", "name is required" }
+	if result := GuardCtx(context.Background(), "", failing); result.CanProceed || len(result.Suggestions) != 1 {
+		t.Errorf("GuardCtx() = %+v, want a failed check with one suggestion", result)
+	}
+	if result := GuardCtx(cancelled, "", failing); result.CanProceed || len(result.Suggestions) != 0 {
+		t.Errorf("GuardCtx() under a cancelled context = %+v, want no suggestions", result)
+	}
+}
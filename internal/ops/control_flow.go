@@ -114,6 +114,7 @@ Rules:
 	opt := types.OpOptions{
 		Intelligence: types.Quick,
 		Mode:         types.TransformMode,
+		Operation:    "match",
 	}
 
 	response, err := callLLM(ctx, systemPrompt, userPrompt, opt)
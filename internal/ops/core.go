@@ -4,6 +4,7 @@ package ops
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -41,7 +42,54 @@ import (
 // The operation uses schema inference to guide the LLM in structured extraction.
 // In Strict mode, all required fields must be present. In Transform mode (default),
 // the LLM will intelligently infer missing fields.
+//
+// Fields tagged `required:"true"` must be found in the input in every mode. When
+// one is missing, Extract returns the partially extracted value together with a
+// types.ExtractError whose MissingRequired lists the absent fields. Fields tagged
+// with omitempty (or `required:"false"`) are treated as optional.
+//
+// *bool fields are tri-state: they are left nil when the input does not state
+// the value, rather than guessed or defaulted to false.
+//
+// Numeric fields tagged `unit:"meters"` receive quantities converted into that
+// unit locally ("5.7 feet" becomes 1.737...); `unit:"kg,from=lb"` also reads
+// bare numbers as pounds. Quantities in unknown units are left zero.
+//
+// time.Time fields are parsed locally from whatever format the input uses
+// ("15-MAR-2019", "Jan 10, 2024", RFC3339, ...), trying WithDateLayouts first.
+// Dates without a zone are read in WithTimezone (UTC by default).
+//
+// With WithLocale, numbers written as text are parsed into numeric fields
+// with that locale's separators, so "1.234,56 €" reads as 1234.56 under
+// "de-DE". Currency symbols and codes around the number are ignored.
+//
+// With WithGroundedExtraction, every top-level field must cite the input
+// substring it came from; fields whose source cannot be found in the input are
+// left empty. ExtractGrounded returns the cited sources and flagged fields.
+//
+// With WithFieldEscalation, the model rates each top-level field and only
+// the fields rated below the threshold are extracted again at a higher
+// intelligence level, so a Fast pass pays for Smart only where it is unsure.
+//
+// With WithConsistencyRules, cross-field rules such as "total == subtotal +
+// tax" are checked after extraction and a violation is re-prompted once;
+// ExtractConsistent reports the repairs made.
+//
+// String fields tagged `pattern:"^\\+?[0-9-]+$"` must match their regular
+// expression; a malformed value is re-prompted once and, if still malformed,
+// returned with an ExtractError naming the field.
+//
+// With WithAlternatives, top-level fields the input reads more than one way
+// get the most likely reading; ExtractAlternatives returns every candidate
+// with its confidence.
 func Extract[T any](input any, opts ExtractOptions) (T, error) {
+	result, err := extractConsistent[T](input, opts, nil)
+	return result.Value, err
+}
+
+// extract implements Extract; when report is non-nil it receives the
+// per-field grounding and alternatives the options ask for
+func extract[T any](input any, opts ExtractOptions, report *extractReport) (T, error) {
 	var result T
 	log := logger.GetLogger()
 
@@ -51,46 +99,8 @@ func Extract[T any](input any, opts ExtractOptions) (T, error) {
 	}
 
 	// Convert to legacy OpOptions for internal use
-	opt := opts.toOpOptions()
-
-	// Enhance steering with extraction-specific options
-	if opts.SchemaHints != nil || opts.Examples != nil || opts.FieldRules != nil {
-		var steeringParts []string
-		if opts.OpOptions.Steering != "" {
-			steeringParts = append(steeringParts, opts.OpOptions.Steering)
-		}
-
-		if opts.StrictSchema {
-			steeringParts = append(steeringParts, "Enforce strict schema validation. All fields must be present and valid.")
-		}
-
-		if opts.AllowPartial {
-			steeringParts = append(steeringParts, "Allow partial extraction if some fields are missing.")
-		}
-
-		if len(opts.SchemaHints) > 0 {
-			hints := "Schema hints: "
-			for field, hint := range opts.SchemaHints {
-				hints += fmt.Sprintf("%s (%s), ", field, hint)
-			}
-			steeringParts = append(steeringParts, strings.TrimSuffix(hints, ", "))
-		}
-
-		if len(opts.FieldRules) > 0 {
-			rules := "Field rules: "
-			for field, rule := range opts.FieldRules {
-				rules += fmt.Sprintf("%s: %s; ", field, rule)
-			}
-			steeringParts = append(steeringParts, strings.TrimSuffix(rules, "; "))
-		}
-
-		if len(opts.Examples) > 0 {
-			examplesJSON, _ := json.Marshal(opts.Examples)
-			steeringParts = append(steeringParts, fmt.Sprintf("Follow these examples: %s", string(examplesJSON)))
-		}
-
-		opt.Steering = strings.Join(steeringParts, ". ")
-	}
+	opt := extractOpOptions(opts)
+	opt.Operation = "extract"
 
 	// Start operation timing
 	startTime := time.Now()
@@ -134,6 +144,56 @@ func Extract[T any](input any, opts ExtractOptions) (T, error) {
 	targetType := reflect.TypeOf(result)
 	typeInfo := GenerateTypeSchema(targetType)
 
+	if opts.hasExpectedCount() && targetType.Kind() != reflect.Slice {
+		err := types.ExtractError{
+			Input:      input,
+			TargetType: targetType.String(),
+			Reason:     "expected count requires a slice target type",
+			RequestID:  opt.RequestID,
+			Timestamp:  time.Now(),
+		}
+		log.Error("Extract failed: invalid expected count", "requestID", opt.RequestID, "error", err)
+		return result, err
+	}
+
+	if opts.Alternatives && structType(targetType) == nil {
+		err := types.ExtractError{
+			Input:      input,
+			TargetType: targetType.String(),
+			Reason:     "alternatives require a struct target type",
+			RequestID:  opt.RequestID,
+			Timestamp:  time.Now(),
+		}
+		log.Error("Extract failed: invalid alternatives", "requestID", opt.RequestID, "error", err)
+		return result, err
+	}
+
+	if opts.EscalationThreshold > 0 && structType(targetType) == nil {
+		err := types.ExtractError{
+			Input:      input,
+			TargetType: targetType.String(),
+			Reason:     "field escalation requires a struct target type",
+			RequestID:  opt.RequestID,
+			Timestamp:  time.Now(),
+		}
+		log.Error("Extract failed: invalid field escalation", "requestID", opt.RequestID, "error", err)
+		return result, err
+	}
+
+	fieldSteering, err := fieldSteeringRule(targetType, opts.FieldSteering)
+	if err != nil {
+		extractErr := types.ExtractError{
+			Input:      input,
+			TargetType: targetType.String(),
+			Reason:     err.Error(),
+			Cause:      err,
+			RequestID:  opt.RequestID,
+			Timestamp:  time.Now(),
+		}
+		log.Error("Extract failed: invalid field steering", "requestID", opt.RequestID, "error", extractErr)
+		return result, extractErr
+	}
+
 	// Convert input to string format for LLM processing
 	inputStr, err := NormalizeInput(input)
 	if err != nil {
@@ -163,16 +223,91 @@ func Extract[T any](input any, opts ExtractOptions) (T, error) {
 	// Build system prompt based on mode
 	systemPrompt := BuildExtractSystemPrompt(typeInfo, opt.Mode)
 
+	// Required fields must come from the input rather than be inferred
+	requiredFields := explicitRequiredFields(targetType)
+	if len(requiredFields) > 0 {
+		systemPrompt += fmt.Sprintf(`
+- These fields are required and must be taken from the input: %s
+- If a required field cannot be found in the input, set it to null; never invent a value for it`, strings.Join(requiredFields, ", "))
+	}
+
+	// *bool fields distinguish "not stated" (nil) from false
+	if triState := triStateBoolFields(targetType); len(triState) > 0 {
+		systemPrompt += fmt.Sprintf(`
+- These yes/no fields are true or false only when the input explicitly states the answer; otherwise set them to null and never default them to false: %s`, strings.Join(triState, ", "))
+	}
+
+	// Dates are parsed locally, so the model only needs to copy them faithfully
+	hasTimeFields := containsTimeField(targetType)
+	if hasTimeFields {
+		systemPrompt += `
+- For datetime fields, copy the date exactly as written in the input (e.g. "15-MAR-2019") or give it as RFC3339; never guess a missing year or day`
+	}
+
+	// Quantities are converted locally, so the model reports them as stated
+	unitRules := unitFieldRules(targetType)
+	if len(unitRules) > 0 {
+		systemPrompt += fmt.Sprintf(`
+- For these quantity fields give the amount exactly as stated in the input as {"value": number, "unit": "unit as written"} and never convert units yourself: %s`, strings.Join(unitRules, ", "))
+	}
+
+	// Formatted fields are checked against their pattern after extraction
+	if patternRules := fieldPatternRules(targetType); len(patternRules) > 0 {
+		systemPrompt += fmt.Sprintf(`
+- These fields must match the given regular expression; copy the value from the input and normalize only its formatting: %s`, strings.Join(patternRules, ", "))
+	}
+
+	// Numbers are parsed locally with the locale's separators
+	numbers, hasLocale := lookupNumberFormat(opts.Locale)
+	if hasLocale {
+		systemPrompt += fmt.Sprintf(`
+- Numbers in the input are written for the %s locale: give amounts for numeric fields as strings copied exactly as written (e.g. "1.234,56 €") and never reinterpret their separators`, opts.Locale)
+	}
+
+	systemPrompt += fieldSteering
+
+	if opts.GroundedExtraction {
+		systemPrompt += groundingInstructions
+	}
+
+	if opts.EscalationThreshold > 0 {
+		systemPrompt += fieldConfidenceInstructions
+	}
+
+	if opts.Alternatives {
+		systemPrompt += alternativesInstructions
+	}
+
+	if opts.hasExpectedCount() {
+		systemPrompt += fmt.Sprintf(`
+- The input is expected to contain %s items: return one array element per item, without merging, splitting or duplicating items`, opts.expectedCountText())
+	}
+
+	var inputLanguage string
+	if opt.PreserveLanguage {
+		var rule string
+		inputLanguage, rule = preserveLanguageRule(inputStr)
+		systemPrompt += rule
+	}
+
 	// Build user prompt
-	userPrompt := fmt.Sprintf("Extract structured data from this input:\n%s", inputStr)
+	userPrompt := fmt.Sprintf("Extract structured data from this input:\n%s", inputStr) + opts.consistencyRepair
 
 	// Call LLM for extraction
 	response, err := callLLM(ctx, systemPrompt, userPrompt, opt)
+	if err == nil && opts.hasExpectedCount() {
+		response, err = correctItemCount(ctx, systemPrompt, userPrompt, response, opts, opt)
+	}
+	if err == nil && opts.EscalationThreshold > 0 {
+		response, err = escalateUncertainFields(ctx, typeInfo, inputStr, response, opts, opt)
+	}
 	if err != nil {
 		extractErr := types.ExtractError{
 			Input:      input,
 			TargetType: targetType.String(),
 			Reason:     err.Error(),
+			Truncated:  errors.Is(err, types.ErrTruncated),
+			Cause:      err,
 			Confidence: 0,
 			RequestID:  opt.RequestID,
 			Timestamp:  time.Now(),
@@ -184,8 +319,59 @@ func Extract[T any](input any, opts ExtractOptions) (T, error) {
 		return result, extractErr
 	}
 
+	warnIfLanguageChanged("Extract", inputLanguage, jsonText(response), opt.RequestID)
+
+	// Keep only values whose cited source appears in the input
+	if opts.GroundedExtraction {
+		data, grounding := groundResponse(response, inputStr)
+		response = data
+		if ungrounded := ungroundedFields(grounding); len(ungrounded) > 0 {
+			log.Warn("Extract cleared ungrounded fields", "requestID", opt.RequestID, "fields", ungrounded)
+		}
+		if report != nil {
+			report.grounding = grounding
+		}
+	}
+
+	// Keep the most likely reading in the data and the rest in the report
+	if opts.Alternatives {
+		data, alternatives := splitAlternatives(response)
+		response = data
+		if report != nil {
+			report.alternatives = alternatives
+		}
+	}
+
+	// Convert quantities into the units their fields are tagged with
+	decoded := response
+	if len(unitRules) > 0 {
+		var unconverted []string
+		decoded, unconverted = normalizeUnitFields(decoded, targetType)
+		if len(unconverted) > 0 {
+			log.Warn("Extract could not convert quantities", "requestID", opt.RequestID, "fields", unconverted)
+		}
+	}
+
+	// Parse numbers written as text with the locale's separators
+	if hasLocale {
+		var unparsed []string
+		decoded, unparsed = normalizeNumberFields(decoded, targetType, numbers)
+		if len(unparsed) > 0 {
+			log.Warn("Extract could not parse numbers", "requestID", opt.RequestID, "fields", unparsed)
+		}
+	}
+
+	// Normalize extracted dates into RFC3339 for time.Time fields
+	if hasTimeFields {
+		var unparsed []string
+		decoded, unparsed = normalizeTimeFields(decoded, targetType, opts.DateLayouts, opts.Timezone)
+		if len(unparsed) > 0 {
+			log.Warn("Extract could not parse dates", "requestID", opt.RequestID, "fields", unparsed)
+		}
+	}
+
 	// Parse JSON response into target type
-	if err := ParseJSON(response, &result); err != nil {
+	if err := ParseJSON(decoded, &result); err != nil {
 		// Calculate partial confidence based on parsing attempt
 		confidence := CalculateParsingConfidence(response, targetType)
 
@@ -206,6 +392,46 @@ func Extract[T any](input any, opts ExtractOptions) (T, error) {
 		return result, extractErr
 	}
 
+	if hasTimeFields && opts.Timezone != nil {
+		applyTimezone(reflect.ValueOf(&result), opts.Timezone)
+	}
+
+	// Fail when the corrective re-prompt still missed the expected count
+	if opts.hasExpectedCount() {
+		if count := reflect.ValueOf(result).Len(); !opts.countInRange(count) {
+			extractErr := types.ExtractError{
+				Input:      input,
+				TargetType: targetType.String(),
+				Reason:     fmt.Sprintf("expected %s items, got %d", opts.expectedCountText(), count),
+				RequestID:  opt.RequestID,
+				Timestamp:  time.Now(),
+			}
+			log.Error("Extract failed: item count out of range",
+				"requestID", opt.RequestID,
+				"count", count,
+			)
+			return result, extractErr
+		}
+	}
+
+	// Flag required fields the model could not find in the input
+	if missing := missingRequiredFields(response, requiredFields); len(missing) > 0 {
+		extractErr := types.ExtractError{
+			Input:           input,
+			TargetType:      targetType.String(),
+			Reason:          fmt.Sprintf("missing required fields: %s", strings.Join(missing, ", ")),
+			Confidence:      1 - float64(len(missing))/float64(len(requiredFields)),
+			RequestID:       opt.RequestID,
+			Timestamp:       time.Now(),
+			MissingRequired: missing,
+		}
+		log.Error("Extract failed: missing required fields",
+			"requestID", opt.RequestID,
+			"missing", missing,
+		)
+		return result, extractErr
+	}
+
 	// Validate extracted data if in Strict mode
 	if opt.Mode == types.Strict {
 		if err := ValidateExtractedData(result, opt.Threshold); err != nil {
@@ -233,6 +459,53 @@ func Extract[T any](input any, opts ExtractOptions) (T, error) {
 	return result, nil
 }
 
+// extractOpOptions converts ExtractOptions to OpOptions, folding the
+// extraction-specific options into the steering prompt
+func extractOpOptions(opts ExtractOptions) types.OpOptions {
+	opt := opts.toOpOptions()
+
+	// Enhance steering with extraction-specific options
+	if opts.SchemaHints != nil || opts.Examples != nil || opts.FieldRules != nil {
+		var steeringParts []string
+		if opts.OpOptions.Steering != "" {
+			steeringParts = append(steeringParts, opts.OpOptions.Steering)
+		}
+
+		if opts.StrictSchema {
+			steeringParts = append(steeringParts, "Enforce strict schema validation. All fields must be present and valid.")
+		}
+
+		if opts.AllowPartial {
+			steeringParts = append(steeringParts, "Allow partial extraction if some fields are missing.")
+		}
+
+		if len(opts.SchemaHints) > 0 {
+			hints := "Schema hints: "
+			for field, hint := range opts.SchemaHints {
+				hints += fmt.Sprintf("%s (%s), ", field, hint)
+			}
+			steeringParts = append(steeringParts, strings.TrimSuffix(hints, ", "))
+		}
+
+		if len(opts.FieldRules) > 0 {
+			rules := "Field rules: "
+			for field, rule := range opts.FieldRules {
+				rules += fmt.Sprintf("%s: %s; ", field, rule)
+			}
+			steeringParts = append(steeringParts, strings.TrimSuffix(rules, "; "))
+		}
+
+		if len(opts.Examples) > 0 {
+			examplesJSON, _ := json.Marshal(opts.Examples)
+			steeringParts = append(steeringParts, fmt.Sprintf("Follow these examples: %s", string(examplesJSON)))
+		}
+
+		opt.Steering = strings.Join(steeringParts, ". ")
+	}
+
+	return opt
+}
+
 // Transform converts data from one type to another using semantic mapping.
 // It understands relationships between different structures and maps fields intelligently.
 //
@@ -255,7 +528,20 @@ func Extract[T any](input any, opts ExtractOptions) (T, error) {
 //
 // The operation uses semantic understanding to map between related but structurally
 // different types. It can handle field renaming, type conversion, and derived fields.
+//
+// With WithCopyMatchingFields, fields that T and U share by JSON name and Go
+// type are copied verbatim and only the remaining fields go to the model.
+//
+// The result is checked against U's `validate` and `enum` tags, as Validate
+// does; on a violation the model is re-prompted once with the list of
+// violations.
 func Transform[T any, U any](input T, opts TransformOptions) (U, error) {
+	return transform[T, U](input, opts, nil)
+}
+
+// transform implements Transform; when copied is non-nil it receives the
+// target fields copied from the input
+func transform[T any, U any](input T, opts TransformOptions, copied *[]string) (U, error) {
 	var result U
 	log := logger.GetLogger()
 
@@ -266,6 +552,7 @@ func Transform[T any, U any](input T, opts TransformOptions) (U, error) {
 
 	// Convert to legacy OpOptions
 	opt := opts.toOpOptions()
+	opt.Operation = "transform"
 
 	// Enhance steering with transformation-specific options
 	var steeringParts []string
@@ -352,6 +639,44 @@ func Transform[T any, U any](input T, opts TransformOptions) (U, error) {
 		return result, transformErr
 	}
 
+	// Fields shared by name and type are copied rather than regenerated;
+	// explicitly mapped target fields are always left to the model
+	var copiedJSON []byte
+	var copiedFields []string
+	if opts.CopyMatchingFields {
+		if fields := matchingFields(fromType, toType, opts.MappingRules); len(fields) > 0 {
+			remaining, values, present, err := splitCopiedFields(inputJSON, fields)
+			if err == nil && len(present) > 0 {
+				inputJSON, copiedJSON, copiedFields = remaining, values, present
+			}
+		}
+		if copied != nil {
+			*copied = copiedFields
+		}
+		log.Debug("Transform copied matching fields", "requestID", opt.RequestID, "fields", copiedFields)
+	}
+
+	// Nothing is left for the model when every target field was copied
+	if copiedJSON != nil && len(copiedFields) == len(matchingFields(toType, toType, nil)) {
+		if err := json.Unmarshal(copiedJSON, &result); err != nil {
+			return result, types.TransformError{
+				Input:     input,
+				FromType:  fromType.String(),
+				ToType:    toType.String(),
+				Reason:    fmt.Sprintf("failed to copy fields: %v", err),
+				Cause:     err,
+				RequestID: opt.RequestID,
+				Timestamp: time.Now(),
+			}
+		}
+		log.Info("Transform operation completed",
+			"requestID", opt.RequestID,
+			"duration", time.Since(startTime),
+			"copiedFields", len(copiedFields),
+		)
+		return result, nil
+	}
+
 	// Build transformation prompt
 	systemPrompt := fmt.Sprintf(`You are a data transformation expert. Transform data from one type to another using semantic mapping.
 
@@ -370,6 +695,11 @@ Transformation rules:
 - Preserve data integrity and meaning
 - Return ONLY valid JSON matching the target schema`, fromSchema, toSchema)
 
+	if len(copiedFields) > 0 {
+		systemPrompt += fmt.Sprintf(`
+- These target fields are copied from the source separately; omit them: %s`, strings.Join(copiedFields, ", "))
+	}
+
 	userPrompt := fmt.Sprintf("Transform this data:\n%s", string(inputJSON))
 
 	// Log transformation details in debug mode
@@ -389,6 +719,7 @@ Transformation rules:
 			FromType:  fromType.String(),
 			ToType:    toType.String(),
 			Reason:    err.Error(),
+			Cause:     err,
 			RequestID: opt.RequestID,
 			Timestamp: time.Now(),
 		}
@@ -417,6 +748,50 @@ Transformation rules:
 		return result, transformErr
 	}
 
+	// Copied values win over anything the model returned for those fields
+	if copiedJSON != nil {
+		if err := json.Unmarshal(copiedJSON, &result); err != nil {
+			return result, types.TransformError{
+				Input:     input,
+				FromType:  fromType.String(),
+				ToType:    toType.String(),
+				Reason:    fmt.Sprintf("failed to copy fields: %v", err),
+				Cause:     err,
+				RequestID: opt.RequestID,
+				Timestamp: time.Now(),
+			}
+		}
+	}
+
+	// Output that breaks the target's validate or enum tags gets one
+	// corrective re-prompt listing the violations
+	if issues := checkTagConstraints(result); len(issues) > 0 {
+		corrected, err := correctTagViolations(ctx, systemPrompt, userPrompt, response, issues, opt)
+		if err != nil {
+			return result, types.TransformError{
+				Input:     input,
+				FromType:  fromType.String(),
+				ToType:    toType.String(),
+				Reason:    err.Error(),
+				Cause:     err,
+				RequestID: opt.RequestID,
+				Timestamp: time.Now(),
+			}
+		}
+		var retried U
+		if err := ParseJSON(corrected, &retried); err == nil {
+			if copiedJSON == nil || json.Unmarshal(copiedJSON, &retried) == nil {
+				result = retried
+			}
+		}
+		if remaining := checkTagConstraints(result); len(remaining) > 0 {
+			log.Warn("Transform output still violates target tags",
+				"requestID", opt.RequestID,
+				"violations", tagViolationList(remaining),
+			)
+		}
+	}
+
 	log.Info("Transform operation completed",
 		"requestID", opt.RequestID,
 		"duration", time.Since(startTime),
@@ -444,6 +819,14 @@ Transformation rules:
 //	    }).
 //	    WithEnsureUnique(true))
 //
+//	// Generation restricted to known values
+//	users, err := Generate[[]User]("Generate test users", NewGenerateOptions().
+//	    WithAllowedValues(map[string][]string{"country": {"US", "Canada", "Mexico"}}))
+//
+//	// Generation checked against a JSON Schema
+//	user, err := Generate[User]("Generate a test user", NewGenerateOptions().
+//	    WithSchemaConstraints(userSchema))
+//
 //	// Generation with template and examples
 //	content, err := Generate[BlogPost]("Tech article", NewGenerateOptions().
 //	    WithTemplate(articleTemplate).
@@ -469,6 +852,7 @@ func Generate[T any](prompt string, opts GenerateOptions) (T, error) {
 
 	// Convert to legacy OpOptions
 	opt := opts.toOpOptions()
+	opt.Operation = "generate"
 
 	// Build enhanced prompt
 	var promptParts []string
@@ -556,6 +940,12 @@ func Generate[T any](prompt string, opts GenerateOptions) (T, error) {
 
 	targetType := reflect.TypeOf(result)
 
+	for path := range opts.AllowedValues {
+		if err := checkFieldPath(targetType, path); err != nil {
+			return result, fmt.Errorf("invalid options: allowed values: %w", err)
+		}
+	}
+
 	// Handle string generation differently (simpler)
 	if targetType.Kind() == reflect.String {
 		systemPrompt := BuildGenerateStringPrompt(opt.Mode)
@@ -566,6 +956,7 @@ func Generate[T any](prompt string, opts GenerateOptions) (T, error) {
 				Prompt:     prompt,
 				TargetType: targetType.String(),
 				Reason:     err.Error(),
+				Cause:      err,
 				RequestID:  opt.RequestID,
 				Timestamp:  time.Now(),
 			}
@@ -603,6 +994,13 @@ Generation rules:
 - Maintain internal consistency (e.g., related fields should make sense together)
 - Return ONLY valid JSON matching the schema, no explanations`, typeSchema)
 
+	if len(opts.AllowedValues) > 0 {
+		systemPrompt += allowedValuesRule(opts.AllowedValues)
+	}
+	if opts.SchemaConstraints != nil {
+		systemPrompt += schemaRule(opts.SchemaConstraints)
+	}
+
 	// Log generation details in debug mode
 	if config.GetDebugMode() {
 		log.Debug("Generate schema",
@@ -618,6 +1016,7 @@ Generation rules:
 			Prompt:     prompt,
 			TargetType: targetType.String(),
 			Reason:     err.Error(),
+			Cause:      err,
 			RequestID:  opt.RequestID,
 			Timestamp:  time.Now(),
 		}
@@ -628,6 +1027,65 @@ Generation rules:
 		return result, genErr
 	}
 
+	// Values outside the allowed sets or the schema get one corrective re-prompt
+	if len(opts.AllowedValues) > 0 || opts.SchemaConstraints != nil {
+		allowedViolations := allowedValueViolations(response, opts.AllowedValues)
+		schemaProblems := schemaViolations(response, opts.SchemaConstraints)
+		if len(allowedViolations) > 0 || len(schemaProblems) > 0 {
+			log.Warn("Generate broke its value constraints, re-prompting",
+				"requestID", opt.RequestID,
+				"violations", allowedViolations,
+				"schemaViolations", schemaProblems,
+			)
+			var problems []string
+			if len(allowedViolations) > 0 {
+				problems = append(problems, fmt.Sprintf(`A previous answer used values that are not allowed:
+%s`, strings.Join(allowedViolations, "\n")))
+			}
+			if len(schemaProblems) > 0 {
+				problems = append(problems, fmt.Sprintf(`A previous answer broke the JSON Schema:
+%s`, strings.Join(schemaProblems, "\n")))
+			}
+			correction := fmt.Sprintf(`%s
+
+%s
+
+Previous answer:
+%s
+
+Return the complete corrected JSON, replacing each of these with a value that satisfies the constraints.`,
+				prompt, strings.Join(problems, "\n\n"), response)
+			response, err = callLLM(ctx, systemPrompt, correction, opt)
+			if err == nil {
+				var failures []string
+				if violations := allowedValueViolations(response, opts.AllowedValues); len(violations) > 0 {
+					failures = append(failures, fmt.Sprintf("values outside the allowed sets: %s", strings.Join(violations, ", ")))
+				}
+				if violations := schemaViolations(response, opts.SchemaConstraints); len(violations) > 0 {
+					failures = append(failures, fmt.Sprintf("schema violations: %s", strings.Join(violations, ", ")))
+				}
+				if len(failures) > 0 {
+					err = errors.New(strings.Join(failures, "; "))
+				}
+			}
+			if err != nil {
+				genErr := types.GenerateError{
+					Prompt:     prompt,
+					TargetType: targetType.String(),
+					Reason:     err.Error(),
+					Cause:      err,
+					RequestID:  opt.RequestID,
+					Timestamp:  time.Now(),
+				}
+				log.Error("Generate failed: value constraints",
+					"requestID", opt.RequestID,
+					"error", genErr,
+				)
+				return result, genErr
+			}
+		}
+	}
+
 	// Parse generated data
 	if err := ParseJSON(response, &result); err != nil {
 		genErr := types.GenerateError{
@@ -0,0 +1,1270 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/monstercameron/schemaflow/internal/config"
+	"github.com/monstercameron/schemaflow/internal/llm"
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+type requiredContact struct {
+	Name  string `json:"name" required:"true"`
+	Email string `json:"email" required:"true"`
+	Phone string `json:"phone,omitempty"`
+}
+
+func TestExtractMissingRequired(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		if !strings.Contains(system, "must be taken from the input: name, email") {
+			t.Errorf("expected required fields in system prompt, got %q", system)
+		}
+		return `{"name": "Jane Doe", "email": null, "phone": "555-0100"}`, nil
+	})
+
+	result, err := Extract[requiredContact]("Jane Doe can be reached at 555-0100", NewExtractOptions())
+	if err == nil {
+		t.Fatal("expected error for missing required field")
+	}
+
+	var extractErr types.ExtractError
+	if !errors.As(err, &extractErr) {
+		t.Fatalf("expected ExtractError, got %T", err)
+	}
+	if len(extractErr.MissingRequired) != 1 || extractErr.MissingRequired[0] != "email" {
+		t.Errorf("expected MissingRequired [email], got %v", extractErr.MissingRequired)
+	}
+	if result.Name != "Jane Doe" {
+		t.Errorf("expected partial result to be returned, got %+v", result)
+	}
+}
+
+func TestExtractRequiredPresent(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		return `{"name": "Jane Doe", "email": "jane@example.com"}`, nil
+	})
+
+	result, err := Extract[requiredContact]("Jane Doe, jane@example.com", NewExtractOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Email != "jane@example.com" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestIsRequiredField(t *testing.T) {
+	type sample struct {
+		A string `json:"a"`
+		B string `json:"b,omitempty"`
+		C string `json:"c,omitempty" required:"true"`
+		D string `json:"d" required:"false"`
+	}
+
+	expected := map[string]bool{"A": true, "B": false, "C": true, "D": false}
+	typ := reflect.TypeOf(sample{})
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if got := isRequiredField(field); got != expected[field.Name] {
+			t.Errorf("field %s: expected required=%v, got %v", field.Name, expected[field.Name], got)
+		}
+	}
+}
+
+func TestExtractDryRun(t *testing.T) {
+	calls := 0
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		calls++
+		return `{"name":"Ada","age":36}`, nil
+	})
+	defer setupMockClient()
+
+	provider := &captureProvider{name: "openai"}
+	previous := getDefaultProvider()
+	SetDefaultProvider(provider)
+	defer SetDefaultProvider(previous)
+
+	_, err := Extract[Person]("Ada Lovelace, 36 years old", NewExtractOptions().WithDryRun(true))
+	if !errors.Is(err, types.ErrDryRun) {
+		t.Fatalf("Extract() error = %v, want ErrDryRun", err)
+	}
+	if calls != 0 || provider.attempts != 0 {
+		t.Fatalf("provider called during dry run: caller=%d provider=%d", calls, provider.attempts)
+	}
+
+	plan, ok := AsDryRun(err)
+	if !ok {
+		t.Fatalf("AsDryRun(%v) = false", err)
+	}
+	if !strings.Contains(plan.RenderedPrompt, "Ada Lovelace, 36 years old") {
+		t.Errorf("RenderedPrompt missing input: %q", plan.RenderedPrompt)
+	}
+	if !strings.Contains(plan.RenderedPrompt, "return only the final JSON answer") {
+		t.Errorf("RenderedPrompt missing system prompt: %q", plan.RenderedPrompt)
+	}
+	if plan.Provider != "openai" || plan.Model == "" || plan.EstimatedTokens <= 0 {
+		t.Errorf("plan = %+v, want provider, model and token estimate", plan)
+	}
+
+	var extractErr types.ExtractError
+	if !errors.As(err, &extractErr) {
+		t.Errorf("dry-run error should still be wrapped in ExtractError, got %T", err)
+	}
+}
+
+func TestExtractRerunsTruncatedJSONWithMoreTokens(t *testing.T) {
+	setLLMCaller(nil)
+	defer setupMockClient()
+	previous := getDefaultProvider()
+	defer SetDefaultProvider(previous)
+
+	truncated := llm.CompletionResponse{Content: `{"name":"Ada","ag`, FinishReason: llm.FinishReasonLength}
+	provider := &captureProvider{responses: []llm.CompletionResponse{
+		truncated,
+		{Content: `{"name":"Ada","age":36}`, FinishReason: "stop"},
+	}}
+	SetDefaultProvider(provider)
+
+	opts := NewExtractOptions()
+	person, err := Extract[Person]("Ada Lovelace, 36 years old", opts)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if person.Name != "Ada" || person.Age != 36 {
+		t.Errorf("person = %+v, want Ada, 36", person)
+	}
+	base := config.GetMaxTokens(opts.CommonOptions.Intelligence)
+	if provider.attempts != 2 || provider.req.MaxTokens != base*2 {
+		t.Errorf("attempts = %d, max tokens = %d; want a second call with %d", provider.attempts, provider.req.MaxTokens, base*2)
+	}
+
+	// Turning off provider-enforced JSON does not stop the re-run: Extract
+	// still parses the answer as JSON
+	provider = &captureProvider{responses: []llm.CompletionResponse{
+		truncated,
+		{Content: `{"name":"Ada","age":36}`, FinishReason: "stop"},
+	}}
+	SetDefaultProvider(provider)
+	person, err = Extract[Person]("Ada Lovelace, 36 years old", opts.WithJSONMode(types.JSONModeOff))
+	if err != nil || person.Age != 36 {
+		t.Fatalf("Extract() with JSON mode off = %+v, %v; want Ada, 36", person, err)
+	}
+	if provider.req.ResponseFormat != "text" || provider.attempts != 2 {
+		t.Errorf("response format = %q, attempts = %d; want a text request re-run once", provider.req.ResponseFormat, provider.attempts)
+	}
+
+	// Output that stays truncated gives up at the cap and reports it
+	provider = &captureProvider{responses: []llm.CompletionResponse{truncated, truncated, truncated}}
+	SetDefaultProvider(provider)
+	_, err = Extract[Person]("Ada Lovelace, 36 years old", opts)
+	var extractErr types.ExtractError
+	if !errors.As(err, &extractErr) || !extractErr.Truncated || !errors.Is(err, types.ErrTruncated) {
+		t.Fatalf("Extract() error = %v, want a truncated ExtractError", err)
+	}
+	if provider.attempts != 3 || provider.req.MaxTokens != base*truncationGrowth {
+		t.Errorf("attempts = %d, max tokens = %d; want 3 calls ending at %d", provider.attempts, provider.req.MaxTokens, base*truncationGrowth)
+	}
+}
+
+type datedRecord struct {
+	Title     string     `json:"title"`
+	Published time.Time  `json:"published"`
+	Updated   *time.Time `json:"updated,omitempty"`
+}
+
+func TestExtractNormalizesDates(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     time.Time
+	}{
+		{"day-month-year", `{"title":"Annual report","published":"15-MAR-2019"}`, time.Date(2019, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		{"month-day-year", `{"title":"Launch","published":"Jan 10, 2024"}`, time.Date(2024, time.January, 10, 0, 0, 0, 0, time.UTC)},
+		{"rfc3339", `{"title":"Post","published":"2024-01-10T09:30:00Z"}`, time.Date(2024, time.January, 10, 9, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+				return tt.response, nil
+			})
+			defer setupMockClient()
+
+			got, err := Extract[datedRecord]("some document", NewExtractOptions())
+			if err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			}
+			if !got.Published.Equal(tt.want) {
+				t.Errorf("Published = %v, want %v", got.Published, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractDatesHonorTimezoneAndLayouts(t *testing.T) {
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		return `{"title":"Memo","published":"10.01.2024 14:00","updated":"2024-01-11T08:00:00Z"}`, nil
+	})
+	defer setupMockClient()
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	got, err := Extract[datedRecord]("memo", NewExtractOptions().
+		WithTimezone(berlin).
+		WithDateLayouts("DD.MM.YYYY HH:mm"))
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	want := time.Date(2024, time.January, 10, 14, 0, 0, 0, berlin)
+	if !got.Published.Equal(want) || got.Published.Location().String() != "Europe/Berlin" {
+		t.Errorf("Published = %v, want %v", got.Published, want)
+	}
+	if got.Updated == nil || !got.Updated.Equal(time.Date(2024, time.January, 11, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Updated = %v, want 2024-01-11T08:00:00Z", got.Updated)
+	}
+}
+
+func TestExtractRecordsIsolatesMalformedRecords(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		if !strings.Contains(system, `{"records": [...]}`) {
+			t.Errorf("expected record envelope instructions in system prompt")
+		}
+		return `{"records": [
+			{"Name": "Ada", "Age": 36},
+			{"Name": "Bob", "Age": "thirty-ish"},
+			{"Name": "Cy", "Age": 41}
+		]}`, nil
+	})
+
+	input := "Ada, 36\nBob, thirty-ish\nCy, 41"
+	people, recordErrs, err := ExtractRecords[Person](input, NewExtractOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(people) != 2 || people[0].Name != "Ada" || people[1].Name != "Cy" {
+		t.Errorf("expected Ada and Cy to survive, got %+v", people)
+	}
+	if len(recordErrs) != 1 {
+		t.Fatalf("expected 1 record error, got %v", recordErrs)
+	}
+	if recordErrs[0].Index != 1 || !strings.Contains(recordErrs[0].Raw, "Bob") {
+		t.Errorf("expected record 1 (Bob) to fail, got %+v", recordErrs[0])
+	}
+	var extractErr types.ExtractError
+	if !errors.As(recordErrs[0], &extractErr) {
+		t.Errorf("expected record error to wrap ExtractError, got %T", recordErrs[0].Err)
+	}
+}
+
+func TestExtractRecordsRejectsResponseWithoutRecords(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		return `not json`, nil
+	})
+
+	if _, _, err := ExtractRecords[Person]("Ada, 36", NewExtractOptions()); err == nil {
+		t.Fatal("expected error when response has no record list")
+	}
+}
+
+type groundedContact struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Phone string `json:"phone,omitempty"`
+}
+
+func TestExtractAlternativesReturnsCandidatesForAmbiguousTitle(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	type employee struct {
+		Name  string `json:"name"`
+		Title string `json:"title"`
+	}
+
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		if !strings.Contains(system, `"alternatives"`) {
+			t.Errorf("expected alternatives instructions in system prompt")
+		}
+		return `{
+			"data": {"name": "Priya Raman", "title": "Head of Platform"},
+			"alternatives": {
+				"title": [
+					{"value": "Senior Engineering Manager", "confidence": 0.35},
+					{"value": "Head of Platform", "confidence": 0.6}
+				],
+				"name": [{"value": "Priya Raman", "confidence": 0.99}]
+			}
+		}`, nil
+	})
+
+	input := "Priya Raman leads our platform group.\n--\nPriya Raman | Senior Engineering Manager"
+	result, err := ExtractAlternatives[employee](input, NewExtractOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Value.Title != "Head of Platform" || result.Value.Name != "Priya Raman" {
+		t.Errorf("expected the most likely reading in the value, got %+v", result.Value)
+	}
+	title, ok := result.Fields["title"]
+	if !ok || len(title.Candidates) != 2 {
+		t.Fatalf("expected two title candidates, got %+v", result.Fields)
+	}
+	if title.Value != "Head of Platform" || title.Candidates[0].Value != "Head of Platform" || title.Candidates[1].Value != "Senior Engineering Manager" {
+		t.Errorf("expected candidates ordered by confidence, got %+v", title)
+	}
+	if _, ok := result.Fields["name"]; ok {
+		t.Errorf("expected a single-candidate field to be left out, got %+v", result.Fields["name"])
+	}
+
+	if _, err := Extract[employee](input, NewExtractOptions().WithAlternatives(true).WithGroundedExtraction(true)); err == nil {
+		t.Error("expected alternatives combined with grounded extraction to be rejected")
+	}
+}
+
+func TestExtractGroundedFlagsFabricatedField(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		if !strings.Contains(system, `"sources"`) {
+			t.Errorf("expected grounding instructions in system prompt")
+		}
+		return `{
+			"data": {"name": "Jane Doe", "email": "jane.doe@acme-corp.com", "phone": "555-0100"},
+			"sources": {"name": "Jane  Doe", "email": "jane.doe@acme-corp.com", "phone": "reach her at 555-0100"}
+		}`, nil
+	})
+
+	input := "Contact: jane doe.\nYou can reach her at 555-0100 after 5pm."
+	result, err := ExtractGrounded[groundedContact](input, NewExtractOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Value.Name != "Jane Doe" || result.Value.Phone != "555-0100" {
+		t.Errorf("expected grounded fields to be kept, got %+v", result.Value)
+	}
+	if result.Value.Email != "" {
+		t.Errorf("expected fabricated email to be cleared, got %q", result.Value.Email)
+	}
+	if len(result.Ungrounded) != 1 || result.Ungrounded[0] != "email" {
+		t.Errorf("expected email to be flagged as ungrounded, got %v", result.Ungrounded)
+	}
+	if g := result.Fields["phone"]; !g.Grounded || g.Source != "reach her at 555-0100" {
+		t.Errorf("expected phone source to be reported, got %+v", g)
+	}
+
+	// Plain Extract with the option enabled clears the same field
+	contact, err := Extract[groundedContact](input, NewExtractOptions().WithGroundedExtraction(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if contact.Email != "" || contact.Name != "Jane Doe" {
+		t.Errorf("expected Extract to drop the ungrounded email, got %+v", contact)
+	}
+}
+
+type streamedOrder struct {
+	ID       string   `json:"id"`
+	Customer string   `json:"customer"`
+	Items    []string `json:"items"`
+	Total    float64  `json:"total"`
+}
+
+func TestExtractStreamSnapshotsGrowMonotonically(t *testing.T) {
+	local, _ := llm.NewLocalProvider(llm.ProviderConfig{})
+	local.
+		WithOpHandler("extract", func(ctx context.Context, req llm.CompletionRequest) (string, error) {
+			return `{"id": "A-17", "customer": "Ada, Ltd.", "items": ["lamp", "desk"], "total": 129.5}`, nil
+		}).
+		WithStreamChunkSize(5)
+
+	previous := getDefaultProvider()
+	SetDefaultProvider(local)
+	setLLMCaller(nil)
+	defer func() {
+		SetDefaultProvider(previous)
+		setupMockClient()
+	}()
+
+	snapshots, err := ExtractStream[streamedOrder]("Order A-17 for Ada, Ltd.: lamp, desk; total 129.50", NewExtractOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var received []ExtractSnapshot[streamedOrder]
+	for snap := range snapshots {
+		received = append(received, snap)
+	}
+	if len(received) < 3 {
+		t.Fatalf("expected several partial snapshots, got %d", len(received))
+	}
+
+	for i := 1; i < len(received); i++ {
+		prev, cur := received[i-1].Fields, received[i].Fields
+		if len(cur) < len(prev) {
+			t.Fatalf("snapshot %d has fewer fields than its predecessor: %v then %v", i, prev, cur)
+		}
+		for _, field := range prev {
+			if !containsFold(cur, field) {
+				t.Errorf("snapshot %d dropped field %q", i, field)
+			}
+		}
+		if received[i-1].Final {
+			t.Errorf("snapshot %d was emitted after the final snapshot", i)
+		}
+	}
+	if first := received[0]; first.Value.ID != "A-17" || first.Value.Total != 0 {
+		t.Errorf("expected the first snapshot to hold only the leading field, got %+v", first.Value)
+	}
+
+	final := received[len(received)-1]
+	if !final.Final || final.Err != nil {
+		t.Fatalf("expected a successful final snapshot, got final=%v err=%v", final.Final, final.Err)
+	}
+	want := streamedOrder{ID: "A-17", Customer: "Ada, Ltd.", Items: []string{"lamp", "desk"}, Total: 129.5}
+	if !reflect.DeepEqual(final.Value, want) {
+		t.Errorf("expected complete final value %+v, got %+v", want, final.Value)
+	}
+}
+
+// endlessStreamProvider streams an extraction whose last field never
+// completes, until its context is cancelled
+type endlessStreamProvider struct {
+	*llm.LocalProvider
+	stopped chan struct{}
+}
+
+func (p endlessStreamProvider) CompleteStream(ctx context.Context, req llm.CompletionRequest, onDelta func(string)) (llm.CompletionResponse, error) {
+	defer close(p.stopped)
+	onDelta(`{"id": "A-17", "customer": "Ada, Ltd.", "items": [`)
+	for {
+		select {
+		case <-ctx.Done():
+			return llm.CompletionResponse{}, ctx.Err()
+		case <-time.After(time.Millisecond):
+			onDelta(`"lamp", `)
+		}
+	}
+}
+
+func TestExtractStreamCancelStopsUpstreamAndLeaksNoGoroutines(t *testing.T) {
+	local, _ := llm.NewLocalProvider(llm.ProviderConfig{})
+	provider := endlessStreamProvider{LocalProvider: local, stopped: make(chan struct{})}
+	previous := getDefaultProvider()
+	SetDefaultProvider(provider)
+	setLLMCaller(nil)
+	defer func() {
+		SetDefaultProvider(previous)
+		setupMockClient()
+	}()
+
+	baseline := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	snapshots, err := ExtractStreamCtx[streamedOrder](ctx, "Order A-17 for Ada, Ltd.", NewExtractOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first := <-snapshots; first.Value.Customer != "Ada, Ltd." {
+		t.Fatalf("expected a partial snapshot before cancelling, got %+v", first)
+	}
+
+	// The consumer stops reading and cancels
+	cancel()
+	select {
+	case <-provider.stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected cancellation to stop the upstream stream")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > baseline && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if leaked := runtime.NumGoroutine() - baseline; leaked > 0 {
+		t.Errorf("expected no goroutines left after cancelling, %d leaked", leaked)
+	}
+	for snap := range snapshots {
+		if snap.Final {
+			t.Errorf("expected no final snapshot after cancelling, got %+v", snap)
+		}
+	}
+}
+
+type copyLead struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+	Notes string `json:"notes"`
+	Score int    `json:"score"`
+}
+
+type copyCustomer struct {
+	ID      string  `json:"id"`
+	Email   string  `json:"email"`
+	Score   float64 `json:"score"`
+	Segment string  `json:"segment"`
+}
+
+func TestTransformCopiesSharedFieldsVerbatim(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	lead := copyLead{ID: "lead-0042", Email: "ada@example.com", Notes: "Runs a 40-person analytics team", Score: 87}
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		if strings.Contains(user, lead.ID) || strings.Contains(user, lead.Email) {
+			t.Errorf("expected copied fields to be withheld from the model, got %q", user)
+		}
+		if !strings.Contains(user, "analytics team") {
+			t.Errorf("expected the remaining fields to reach the model, got %q", user)
+		}
+		return `{"id": "LEAD-42", "email": "ADA@EXAMPLE.COM", "score": 0.87, "segment": "mid-market"}`, nil
+	})
+
+	result, err := TransformWithMetadata[copyLead, copyCustomer](lead, NewTransformOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Value.ID != lead.ID || result.Value.Email != lead.Email {
+		t.Errorf("expected id and email to be copied verbatim, got %+v", result.Value)
+	}
+	if result.Value.Score != 0.87 || result.Value.Segment != "mid-market" {
+		t.Errorf("expected the model to fill the remaining fields, got %+v", result.Value)
+	}
+	if want := []string{"email", "id"}; !reflect.DeepEqual(result.CopiedFields, want) {
+		t.Errorf("CopiedFields = %v, want %v", result.CopiedFields, want)
+	}
+
+	// Without the option the model sees and produces every field
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		return `{"id": "LEAD-42", "email": "ada@example.com", "score": 0.87, "segment": "mid-market"}`, nil
+	})
+	plain, err := Transform[copyLead, copyCustomer](lead, NewTransformOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plain.ID != "LEAD-42" {
+		t.Errorf("expected plain Transform to use the model output, got %+v", plain)
+	}
+}
+
+func TestTransformCorrectsEnumViolationOnRetry(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	type order struct {
+		ID    string `json:"id"`
+		State string `json:"state"`
+	}
+	type shipment struct {
+		OrderID string `json:"order_id" validate:"required"`
+		Status  string `json:"status" enum:"pending,shipped,delivered"`
+	}
+
+	var prompts []string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		prompts = append(prompts, user)
+		if len(prompts) == 1 {
+			return `{"order_id": "ORD-7", "status": "in transit"}`, nil
+		}
+		return `{"order_id": "ORD-7", "status": "shipped"}`, nil
+	})
+
+	got, err := Transform[order, shipment](order{ID: "ORD-7", State: "left the warehouse"}, NewTransformOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prompts) != 2 {
+		t.Fatalf("expected one corrective re-prompt, got %d calls", len(prompts))
+	}
+	if !strings.Contains(prompts[1], "status: in transit is not one of the allowed values") ||
+		!strings.Contains(prompts[1], "pending, shipped, delivered") {
+		t.Errorf("re-prompt should list the enum violation, got %q", prompts[1])
+	}
+	if got.Status != "shipped" || got.OrderID != "ORD-7" {
+		t.Errorf("expected the corrected output, got %+v", got)
+	}
+
+	// Output that already satisfies the tags is not re-prompted
+	prompts = nil
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		prompts = append(prompts, user)
+		return `{"order_id": "ORD-8", "status": "pending"}`, nil
+	})
+	if _, err := Transform[order, shipment](order{ID: "ORD-8"}, NewTransformOptions()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prompts) != 1 {
+		t.Errorf("expected a single call for valid output, got %d", len(prompts))
+	}
+}
+
+func TestExtractExpectedCountRepromptsOnMismatch(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	planets := []string{"Mercury", "Venus", "Earth", "Mars", "Jupiter", "Saturn", "Uranus", "Neptune", "Ceres", "Pluto"}
+	input := "Bodies visited by probes: " + strings.Join(planets, ", ")
+	quoted := func(names []string) string {
+		return `["` + strings.Join(names, `", "`) + `"]`
+	}
+
+	var prompts []string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		prompts = append(prompts, user)
+		if !strings.Contains(system, "expected to contain exactly 10 items") {
+			t.Errorf("system prompt missing the expected count: %q", system)
+		}
+		if len(prompts) == 1 {
+			return quoted(planets[:8]), nil
+		}
+		return quoted(planets), nil
+	})
+
+	names, err := Extract[[]string](input, NewExtractOptions().WithExpectedCount(10, 10))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prompts) != 2 {
+		t.Fatalf("expected one corrective re-prompt, got %d calls", len(prompts))
+	}
+	if !strings.Contains(prompts[1], "contained 8 items") || !strings.Contains(prompts[1], input) {
+		t.Errorf("corrective prompt should cite the bad count and the input, got %q", prompts[1])
+	}
+	if !reflect.DeepEqual(names, planets) {
+		t.Errorf("got %v, want %v", names, planets)
+	}
+
+	// A count still out of range after the correction is an error
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		return quoted(planets[:8]), nil
+	})
+	names, err = Extract[[]string](input, NewExtractOptions().WithExpectedCount(10, 10))
+	var extractErr types.ExtractError
+	if !errors.As(err, &extractErr) || !strings.Contains(extractErr.Reason, "got 8") {
+		t.Fatalf("expected an item count ExtractError, got %v", err)
+	}
+	if len(names) != 8 {
+		t.Errorf("expected the partial result alongside the error, got %v", names)
+	}
+
+	if _, err := Extract[requiredContact](input, NewExtractOptions().WithExpectedCount(1, 2)); err == nil {
+		t.Error("expected an error for a non-slice target")
+	}
+}
+
+type measuredPatient struct {
+	Name         string  `json:"name"`
+	HeightMeters float64 `json:"height_meters" unit:"meters"`
+	WeightKg     float64 `json:"weight_kg" unit:"kg,from=lb"`
+}
+
+func TestExtractConvertsTaggedUnits(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	near := func(got, want float64) bool {
+		return got-want < 1e-9 && want-got < 1e-9
+	}
+	responses := map[string]string{
+		"objects": `{"name": "Ada", "height_meters": {"value": 5.7, "unit": "feet"}, "weight_kg": {"value": 150, "unit": "pounds"}}`,
+		"strings": `{"name": "Ada", "height_meters": "5.7 ft", "weight_kg": "150 lbs"}`,
+		"bare":    `{"name": "Ada", "height_meters": 1.73736, "weight_kg": 150}`,
+	}
+	for name, response := range responses {
+		t.Run(name, func(t *testing.T) {
+			setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+				if !strings.Contains(system, "height_meters (stored in meters)") || !strings.Contains(system, "weight_kg (stored in kg, usually stated in lb)") {
+					t.Errorf("system prompt missing unit rules: %q", system)
+				}
+				return response, nil
+			})
+
+			patient, err := Extract[measuredPatient]("Ada is 5.7 feet tall and weighs 150 pounds.", NewExtractOptions())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !near(patient.HeightMeters, 5.7*0.3048) {
+				t.Errorf("HeightMeters = %v, want %v", patient.HeightMeters, 5.7*0.3048)
+			}
+			if !near(patient.WeightKg, 150*0.45359237) {
+				t.Errorf("WeightKg = %v, want %v", patient.WeightKg, 150*0.45359237)
+			}
+		})
+	}
+
+	// Quantities that cannot be converted are cleared without failing the rest
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		return `{"name": "Ada", "height_meters": {"value": 5.7, "unit": "cubits"}, "weight_kg": {"value": 68, "unit": "kg"}}`, nil
+	})
+	patient, err := Extract[measuredPatient]("Ada is 5.7 cubits tall and weighs 68 kg.", NewExtractOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if patient.HeightMeters != 0 || patient.WeightKg != 68 || patient.Name != "Ada" {
+		t.Errorf("expected only the unknown unit to be cleared, got %+v", patient)
+	}
+}
+
+func TestExtractWithLocaleParsesNumberSeparators(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	type invoice struct {
+		Total    float64 `json:"total"`
+		Quantity int     `json:"quantity"`
+	}
+	cases := []struct {
+		locale, response string
+		total            float64
+		quantity         int
+	}{
+		{"de-DE", `{"total": "1.234,56 €", "quantity": "1.200"}`, 1234.56, 1200},
+		{"en-US", `{"total": "$1,234.56", "quantity": "1,200"}`, 1234.56, 1200},
+		{"fr-FR", `{"total": "1 234,56 €", "quantity": 3}`, 1234.56, 3},
+		{"de-CH", `{"total": "CHF 1'234.56", "quantity": "-2"}`, 1234.56, -2},
+	}
+	for _, tc := range cases {
+		t.Run(tc.locale, func(t *testing.T) {
+			setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+				if !strings.Contains(system, tc.locale+" locale") {
+					t.Errorf("system prompt missing locale rule: %q", system)
+				}
+				return tc.response, nil
+			})
+
+			got, err := Extract[invoice]("Rechnung", NewExtractOptions().WithLocale(tc.locale))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Total != tc.total || got.Quantity != tc.quantity {
+				t.Errorf("got %+v, want total %v quantity %d", got, tc.total, tc.quantity)
+			}
+		})
+	}
+
+	if err := NewExtractOptions().WithLocale("xx-YY").Validate(); err == nil {
+		t.Error("expected an unsupported locale to fail validation")
+	}
+}
+
+func TestExtractLeavesUnstatedTriStateBoolNil(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	type account struct {
+		Name    string `json:"name"`
+		Active  *bool  `json:"active"`
+		Billing struct {
+			AutoPay *bool `json:"auto_pay"`
+		} `json:"billing"`
+	}
+
+	var system string
+	responses := map[string]string{
+		"Acme Corp signed up last week.":                     `{"name": "Acme Corp", "active": null, "billing": {}}`,
+		"Acme Corp's account is active and pays by invoice.": `{"name": "Acme Corp", "active": true, "billing": {"auto_pay": false}}`,
+	}
+	setLLMCaller(func(ctx context.Context, sys, user string, opts types.OpOptions) (string, error) {
+		system = sys
+		for input, response := range responses {
+			if strings.Contains(user, input) {
+				return response, nil
+			}
+		}
+		return "", errors.New("unexpected input")
+	})
+
+	silent, err := Extract[account]("Acme Corp signed up last week.", NewExtractOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(system, "otherwise set them to null and never default them to false: active, billing.auto_pay") {
+		t.Errorf("system prompt missing tri-state rule: %q", system)
+	}
+	if silent.Active != nil || silent.Billing.AutoPay != nil {
+		t.Errorf("expected unstated booleans to stay nil, got active=%v auto_pay=%v", silent.Active, silent.Billing.AutoPay)
+	}
+
+	stated, err := Extract[account]("Acme Corp's account is active and pays by invoice.", NewExtractOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stated.Active == nil || !*stated.Active || stated.Billing.AutoPay == nil || *stated.Billing.AutoPay {
+		t.Errorf("expected active=true auto_pay=false, got active=%v auto_pay=%v", stated.Active, stated.Billing.AutoPay)
+	}
+}
+
+func TestExtractFieldSteeringTargetsNestedPath(t *testing.T) {
+	type Address struct {
+		City   string `json:"city"`
+		Method string `json:"method"`
+	}
+	type LineItem struct {
+		SKU string `json:"sku"`
+		Qty int    `json:"qty"`
+	}
+	type Order struct {
+		ID       string     `json:"id"`
+		Billing  Address    `json:"billing"`
+		Shipping Address    `json:"shipping"`
+		Items    []LineItem `json:"items"`
+	}
+
+	var system string
+	setLLMCaller(func(ctx context.Context, s, user string, opts types.OpOptions) (string, error) {
+		system = s
+		return `{"id":"A-1","billing":{"city":"Austin","method":"card"},"shipping":{"city":"Denver","method":"2-day"},"items":[{"sku":"KB-1","qty":1}]}`, nil
+	})
+	defer setupMockClient()
+
+	opts := NewExtractOptions().WithFieldSteering(map[string]string{
+		"shipping.method": "Use the carrier service level, e.g. 2-day",
+		"items.sku":       "Uppercase the SKU",
+	})
+	order, err := Extract[Order]("Order A-1 paid by card in Austin, ships 2-day to Denver: 1x kb-1", opts)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if order.Shipping.Method != "2-day" {
+		t.Errorf("Shipping.Method = %q, want 2-day", order.Shipping.Method)
+	}
+
+	for _, want := range []string{
+		"\n  - items.sku: Uppercase the SKU",
+		"\n  - shipping.method: Use the carrier service level, e.g. 2-day",
+	} {
+		if !strings.Contains(system, want) {
+			t.Errorf("system prompt missing %q:\n%s", want, system)
+		}
+	}
+	if strings.Contains(system, "billing.method") {
+		t.Errorf("steering rendered against the wrong field:\n%s", system)
+	}
+	if strings.Index(system, "items.sku") > strings.Index(system, "shipping.method") {
+		t.Errorf("field instructions not in path order:\n%s", system)
+	}
+
+	_, err = Extract[Order]("Order A-1", NewExtractOptions().WithFieldSteering(map[string]string{"shipping.carrier": "x"}))
+	var extractErr types.ExtractError
+	if !errors.As(err, &extractErr) || !strings.Contains(extractErr.Reason, `no field "carrier"`) {
+		t.Errorf("Extract() with unknown path error = %v, want ExtractError naming the field", err)
+	}
+}
+
+func TestExtractFieldEscalationReextractsOnlyUncertainFields(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	type invoice struct {
+		Vendor string  `json:"vendor"`
+		Total  float64 `json:"total"`
+		Due    string  `json:"due"`
+	}
+
+	type call struct {
+		system       string
+		intelligence types.Speed
+	}
+	var calls []call
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		calls = append(calls, call{system, opts.Intelligence})
+		if len(calls) == 1 {
+			return `{"data": {"vendor": "Acme Corp", "total": 1250, "due": "next Friday"},
+				"confidence": {"vendor": 0.95, "total": 0.9, "due": 0.3}}`, nil
+		}
+		return `{"due": "2024-03-15"}`, nil
+	})
+
+	input := "Acme Corp invoice, total $1,250.00, payment due Friday the 15th of March 2024"
+	got, err := Extract[invoice](input, NewExtractOptions().WithFieldEscalation(0.6, types.Smart))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(calls) != 2 {
+		t.Fatalf("expected a first pass and one escalation, got %d calls", len(calls))
+	}
+	if calls[0].intelligence != types.Fast || !strings.Contains(calls[0].system, `"confidence"`) {
+		t.Errorf("first pass should run Fast and ask for confidence, got %v", calls[0].intelligence)
+	}
+	if calls[1].intelligence != types.Smart {
+		t.Errorf("escalation should run Smart, got %v", calls[1].intelligence)
+	}
+	if !strings.Contains(calls[1].system, "extract only these fields: due\n") {
+		t.Errorf("escalation should ask only for the uncertain field, got %q", calls[1].system)
+	}
+	want := invoice{Vendor: "Acme Corp", Total: 1250, Due: "2024-03-15"}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// A confident first pass makes no second call
+	calls = nil
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		calls = append(calls, call{system, opts.Intelligence})
+		return `{"data": {"vendor": "Acme Corp", "total": 1250, "due": "2024-03-15"},
+			"confidence": {"vendor": 0.95, "total": 0.9, "due": 0.8}}`, nil
+	})
+	if _, err := Extract[invoice](input, NewExtractOptions().WithFieldEscalation(0.6, types.Smart)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(calls) != 1 {
+		t.Errorf("expected no escalation for confident fields, got %d calls", len(calls))
+	}
+}
+
+func TestGenerateRelatedKeepsForeignKeysValid(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	type order struct {
+		ID     int     `json:"id"`
+		UserID int     `json:"user_id"`
+		Total  float64 `json:"total"`
+	}
+
+	var prompts []string
+	var system string
+	setLLMCaller(func(ctx context.Context, sys, user string, opts types.OpOptions) (string, error) {
+		system = sys
+		prompts = append(prompts, user)
+		users := `"users": [{"id": 1, "name": "Ada"}, {"id": 2, "name": "Grace"}]`
+		if len(prompts) == 1 {
+			// Order 12 references a user that was never generated
+			return `{` + users + `, "orders": [{"id": 11, "user_id": 1, "total": 20}, {"id": 12, "user_id": 7, "total": 35}, {"id": 13, "user_id": 2, "total": 12.5}]}`, nil
+		}
+		return `{` + users + `, "orders": [{"id": 11, "user_id": 1, "total": 20}, {"id": 12, "user_id": 2, "total": 35}, {"id": 13, "user_id": 2, "total": 12.5}]}`, nil
+	})
+
+	var users []user
+	var orders []order
+	err := GenerateRelated("Customers of a bookshop and their orders",
+		[]RelatedEntity{Entity("users", 2, &users), Entity("orders", 3, &orders)},
+		[]Reference{{Child: "orders", ForeignKey: "user_id", Parent: "users", Key: "id"}},
+		NewGenerateOptions())
+	if err != nil {
+		t.Fatalf("GenerateRelated() error = %v", err)
+	}
+	if !strings.Contains(system, "Every orders.user_id must equal the id of one of the generated users") {
+		t.Errorf("system prompt missing the relationship rule: %q", system)
+	}
+	if len(prompts) != 2 || !strings.Contains(prompts[1], "orders[1].user_id 7 matches no users.id") {
+		t.Fatalf("expected one corrective re-prompt naming the dangling key, got %q", prompts)
+	}
+
+	if len(users) != 2 || len(orders) != 3 {
+		t.Fatalf("got %d users and %d orders, want 2 and 3", len(users), len(orders))
+	}
+	userIDs := map[int]bool{}
+	for _, u := range users {
+		userIDs[u.ID] = true
+	}
+	for _, o := range orders {
+		if !userIDs[o.UserID] {
+			t.Errorf("order %d references missing user %d", o.ID, o.UserID)
+		}
+	}
+
+	// A relation naming a field the child does not have is rejected up front
+	err = GenerateRelated("bookshop", []RelatedEntity{Entity("users", 2, &users), Entity("orders", 3, &orders)},
+		[]Reference{{Child: "orders", ForeignKey: "customer_id", Parent: "users", Key: "id"}}, NewGenerateOptions())
+	var genErr types.GenerateError
+	if !errors.As(err, &genErr) || !strings.Contains(genErr.Reason, "customer_id") {
+		t.Errorf("expected a GenerateError naming customer_id, got %v", err)
+	}
+}
+
+func TestGenerateWithAllowedValuesRepromptsOnViolation(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	type user struct {
+		Name    string `json:"name"`
+		Country string `json:"country"`
+	}
+	countries := []string{"Canada", "Mexico", "United States"}
+
+	// The first answer invents a country; the correction stays within the list
+	var prompts []string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		prompts = append(prompts, user)
+		if !strings.Contains(system, `- country: "Canada", "Mexico", "United States"`) {
+			t.Errorf("system prompt missing allowed values: %q", system)
+		}
+		if len(prompts) == 1 {
+			return `[{"name": "Ana", "country": "Mexico"}, {"name": "Bo", "country": "Narnia"}]`, nil
+		}
+		return `[{"name": "Ana", "country": "Mexico"}, {"name": "Bo", "country": "Canada"}]`, nil
+	})
+
+	users, err := Generate[[]user]("Generate two test users", NewGenerateOptions().
+		WithAllowedValues(map[string][]string{"country": countries}))
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if len(prompts) != 2 || !strings.Contains(prompts[1], `1.country: "Narnia"`) {
+		t.Fatalf("expected one re-prompt naming the violation, got %q", prompts)
+	}
+	for _, u := range users {
+		if !slices.Contains(countries, u.Country) {
+			t.Errorf("user %s has country %q outside the allowed list", u.Name, u.Country)
+		}
+	}
+
+	// A model that keeps violating the list fails the operation
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		return `[{"name": "Bo", "country": "Atlantis"}]`, nil
+	})
+	if _, err := Generate[[]user]("Generate a test user", NewGenerateOptions().
+		WithAllowedValues(map[string][]string{"country": countries})); err == nil || !strings.Contains(err.Error(), "Atlantis") {
+		t.Errorf("expected an allowed-values error, got %v", err)
+	}
+
+	if _, err := Generate[[]user]("Generate a test user", NewGenerateOptions().
+		WithAllowedValues(map[string][]string{"region": countries})); err == nil {
+		t.Error("expected an unknown path to be rejected")
+	}
+}
+
+func TestGenerateWithSchemaConstraintsRepromptsOnViolation(t *testing.T) {
+	defer setupMockClient()
+
+	type applicant struct {
+		Name  string `json:"name"`
+		Age   int    `json:"age"`
+		Email string `json:"email"`
+	}
+	var schema JSONSchema
+	if err := json.Unmarshal([]byte(`{
+		"type": "object",
+		"required": ["name", "age", "email"],
+		"properties": {
+			"age":   {"type": "integer", "minimum": 18, "maximum": 65},
+			"email": {"type": "string", "pattern": "^[a-z.]+@example\\.com$"}
+		}
+	}`), &schema); err != nil {
+		t.Fatalf("failed to decode schema: %v", err)
+	}
+
+	// The first answer is too young; the correction is within range
+	var prompts []string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		prompts = append(prompts, user)
+		if !strings.Contains(system, `"maximum": 65`) {
+			t.Errorf("system prompt missing the schema: %q", system)
+		}
+		if len(prompts) == 1 {
+			return `{"name": "Ana", "age": 16, "email": "ana@example.com"}`, nil
+		}
+		return `{"name": "Ana", "age": 34, "email": "ana@example.com"}`, nil
+	})
+
+	person, err := Generate[applicant]("Generate a job applicant", NewGenerateOptions().WithSchemaConstraints(schema))
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if len(prompts) != 2 || !strings.Contains(prompts[1], "age: 16 is below the minimum 18") {
+		t.Fatalf("expected one re-prompt naming the violation, got %q", prompts)
+	}
+	if person.Age < 18 || person.Age > 65 {
+		t.Errorf("expected an age within 18-65, got %d", person.Age)
+	}
+
+	// A model that keeps violating the schema fails the operation
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		return `{"name": "Bo", "age": 70, "email": "bo@elsewhere.org"}`, nil
+	})
+	_, err = Generate[applicant]("Generate a job applicant", NewGenerateOptions().WithSchemaConstraints(schema))
+	if err == nil || !strings.Contains(err.Error(), "age: 70 is above the maximum 65") || !strings.Contains(err.Error(), "does not match pattern") {
+		t.Errorf("expected schema violations, got %v", err)
+	}
+
+	if err := NewGenerateOptions().WithSchemaConstraints(JSONSchema{Pattern: "("}).Validate(); err == nil {
+		t.Error("expected an invalid pattern to be rejected")
+	}
+}
+
+func TestExtractLikeMatchesExampleShape(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	var systemPrompt string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		systemPrompt = system
+		// The model adds a stray key and leaves out contact.phone
+		return `{"name": "Ada Lovelace", "contact": {"email": "ada@example.com"}, "orders": [{"sku": "A-1", "qty": 2}, {"sku": "B-7", "qty": 1}], "notes": "extra"}`, nil
+	})
+
+	example := map[string]any{
+		"name":    "Jane Doe",
+		"contact": map[string]any{"email": "jane@example.com", "phone": "555-0100"},
+		"orders":  []any{map[string]any{"sku": "X-0", "qty": 1}},
+	}
+	result, err := ExtractLike("Ada Lovelace (ada@example.com) ordered 2x A-1 and 1x B-7", example, NewExtractOptions().WithMode(types.TransformMode))
+	if err != nil {
+		t.Fatalf("ExtractLike failed: %v", err)
+	}
+
+	for _, want := range []string{"contact: {", "email: string", "orders: []{", "qty: number"} {
+		if !strings.Contains(systemPrompt, want) {
+			t.Errorf("expected the inferred schema to contain %q, got:\n%s", want, systemPrompt)
+		}
+	}
+
+	want := map[string]any{
+		"name":    "Ada Lovelace",
+		"contact": map[string]any{"email": "ada@example.com", "phone": nil},
+		"orders": []any{
+			map[string]any{"sku": "A-1", "qty": float64(2)},
+			map[string]any{"sku": "B-7", "qty": float64(1)},
+		},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("expected a result shaped like the example\n got: %#v\nwant: %#v", result, want)
+	}
+
+	// A struct example yields a value of the same struct type
+	type order struct {
+		SKU string `json:"sku"`
+		Qty int    `json:"qty"`
+	}
+	typed, err := ExtractLike("2x A-1", order{SKU: "X-0", Qty: 1}, NewExtractOptions().WithMode(types.TransformMode))
+	if err != nil {
+		t.Fatalf("ExtractLike with a struct example failed: %v", err)
+	}
+	if _, ok := typed.(order); !ok {
+		t.Errorf("expected an order result, got %T", typed)
+	}
+}
+
+func TestExtractConsistencyRulesRepairWrongTotal(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	type invoice struct {
+		Subtotal float64 `json:"subtotal"`
+		Tax      float64 `json:"tax"`
+		Total    float64 `json:"total"`
+	}
+
+	var prompts []string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		prompts = append(prompts, user)
+		if len(prompts) == 1 {
+			return `{"subtotal": 100.00, "tax": 8.25, "total": 118.25}`, nil
+		}
+		return `{"subtotal": 100.00, "tax": 8.25, "total": 108.25}`, nil
+	})
+
+	opts := NewExtractOptions().WithConsistencyRules([]string{"total == subtotal + tax"})
+	result, err := ExtractConsistent[invoice]("Subtotal $100.00, tax $8.25, total due $108.25", opts)
+	if err != nil {
+		t.Fatalf("ExtractConsistent failed: %v", err)
+	}
+	if len(prompts) != 2 {
+		t.Fatalf("expected one corrective re-prompt, got %d calls", len(prompts))
+	}
+	if !strings.Contains(prompts[1], "total == subtotal + tax fails (118.25 == 108.25)") {
+		t.Errorf("expected the re-prompt to name the violation, got %q", prompts[1])
+	}
+	if result.Value.Total != 108.25 {
+		t.Errorf("expected the total to be corrected to 108.25, got %v", result.Value.Total)
+	}
+	if want := []string{"total: 118.25 -> 108.25"}; !slices.Equal(result.ConsistencyRepairs, want) {
+		t.Errorf("ConsistencyRepairs = %v, want %v", result.ConsistencyRepairs, want)
+	}
+
+	// Extract applies the same repair without reporting it
+	prompts = nil
+	if _, err := Extract[invoice]("Subtotal $100.00, tax $8.25, total due $108.25", opts); err != nil || len(prompts) != 2 {
+		t.Fatalf("expected Extract to repair too, got err=%v calls=%d", err, len(prompts))
+	}
+
+	if err := NewExtractOptions().WithConsistencyRules([]string{"total"}).Validate(); err == nil {
+		t.Error("expected a rule without a comparison to fail validation")
+	}
+	if _, err := Extract[invoice]("x", NewExtractOptions().WithConsistencyRules([]string{"grand_total == subtotal"})); err == nil {
+		t.Error("expected a rule naming an unknown field to fail")
+	}
+}
+
+func TestExtractPatternTagRepromptsMalformedPhone(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	type contact struct {
+		Name  string `json:"name"`
+		Phone string `json:"phone" pattern:"^\\+?[0-9-]+$"`
+	}
+
+	var systems, prompts []string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		systems = append(systems, system)
+		prompts = append(prompts, user)
+		if len(prompts) == 1 {
+			return `{"name": "Ada", "phone": "(555) 010-0199"}`, nil
+		}
+		return `{"name": "Ada", "phone": "555-010-0199"}`, nil
+	})
+
+	result, err := ExtractConsistent[contact]("Call Ada at (555) 010-0199", NewExtractOptions())
+	if err != nil {
+		t.Fatalf("ExtractConsistent failed: %v", err)
+	}
+	if len(prompts) != 2 {
+		t.Fatalf("expected one corrective re-prompt, got %d calls", len(prompts))
+	}
+	if !strings.Contains(systems[0], `phone (^\+?[0-9-]+$)`) {
+		t.Errorf("expected the system prompt to state the phone format, got %q", systems[0])
+	}
+	if !strings.Contains(prompts[1], `phone: "(555) 010-0199" does not match pattern ^\+?[0-9-]+$`) {
+		t.Errorf("expected the re-prompt to name the malformed field, got %q", prompts[1])
+	}
+	if result.Value.Phone != "555-010-0199" {
+		t.Errorf("expected the phone to be corrected, got %q", result.Value.Phone)
+	}
+	if want := []string{"phone: (555) 010-0199 -> 555-010-0199"}; !slices.Equal(result.ConsistencyRepairs, want) {
+		t.Errorf("ConsistencyRepairs = %v, want %v", result.ConsistencyRepairs, want)
+	}
+
+	// A value still malformed after the re-prompt is flagged
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		return `{"name": "Ada", "phone": "ext. 12"}`, nil
+	})
+	result, err = ExtractConsistent[contact]("Call Ada, ext. 12", NewExtractOptions())
+	var extractErr types.ExtractError
+	if !errors.As(err, &extractErr) {
+		t.Fatalf("expected an ExtractError, got %v", err)
+	}
+	if len(result.Violations) != 1 || !strings.HasPrefix(result.Violations[0], "phone:") {
+		t.Errorf("expected the phone to be flagged, got %v", result.Violations)
+	}
+}
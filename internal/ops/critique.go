@@ -231,6 +231,7 @@ func Critique[T any](input T, opts CritiqueOptions) (CritiqueResult, error) {
 	}
 
 	opt := opts.toOpOptions()
+	opt.Operation = "critique"
 
 	ctx := opt.Context
 	if ctx == nil {
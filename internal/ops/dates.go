@@ -0,0 +1,208 @@
+// package ops - Date/time normalization for time.Time fields in extracted data
+package ops
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// defaultDateLayouts are tried, in order, after any caller-supplied layouts
+var defaultDateLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+	"02-Jan-2006",
+	"02-Jan-06",
+	"2 Jan 2006",
+	"2 January 2006",
+	"Jan 2, 2006",
+	"Jan 2 2006",
+	"January 2, 2006",
+	"January 2 2006",
+	"Mon, Jan 2, 2006",
+	"Monday, January 2, 2006",
+	"Jan 2, 2006 3:04 PM",
+	"January 2, 2006 3:04 PM",
+	"01/02/2006",
+	"01/02/2006 15:04",
+	"1/2/2006",
+	"01/02/06",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC822Z,
+	time.RFC822,
+	"January 2006",
+	"Jan 2006",
+}
+
+// parseDateValue parses value with the given layouts followed by the defaults.
+// Values without a zone are interpreted in loc; zoned values are converted to it.
+// Layouts may be Go layouts or patterns such as "DD-MMM-YYYY".
+func parseDateValue(value string, layouts []string, loc *time.Location) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if loc == nil {
+		loc = time.UTC
+	}
+	candidates := make([]string, 0, len(layouts)+len(defaultDateLayouts))
+	for _, layout := range layouts {
+		candidates = append(candidates, datePatternLayout(layout))
+	}
+	for _, layout := range append(candidates, defaultDateLayouts...) {
+		if parsed, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return parsed.In(loc), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// containsTimeField reports whether t has a time.Time anywhere in its structure
+func containsTimeField(t reflect.Type) bool {
+	return containsTimeFieldSeen(t, make(map[reflect.Type]bool))
+}
+
+func containsTimeFieldSeen(t reflect.Type, seen map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return true
+	}
+	if t.Kind() != reflect.Struct || seen[t] {
+		return false
+	}
+	seen[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() && containsTimeFieldSeen(t.Field(i).Type, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeTimeFields rewrites date strings at time.Time positions of a JSON
+// response into RFC3339 so they unmarshal into time.Time. It returns the
+// rewritten JSON and the paths of values that could not be parsed; those are
+// left unchanged so decoding reports them.
+func normalizeTimeFields(response string, target reflect.Type, layouts []string, loc *time.Location) (string, []string) {
+	var raw any
+	decoder := json.NewDecoder(strings.NewReader(cleanJSON(response)))
+	decoder.UseNumber() // keep large integers exact when re-encoding
+	if err := decoder.Decode(&raw); err != nil {
+		return response, nil
+	}
+	var unparsed []string
+	normalized := normalizeTimeValue(raw, target, "", layouts, loc, &unparsed)
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return response, nil
+	}
+	return string(data), unparsed
+}
+
+func normalizeTimeValue(value any, t reflect.Type, path string, layouts []string, loc *time.Location, unparsed *[]string) any {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		str, ok := value.(string)
+		if !ok {
+			return value
+		}
+		if strings.TrimSpace(str) == "" {
+			return nil
+		}
+		if parsed, ok := parseDateValue(str, layouts, loc); ok {
+			return parsed.Format(time.RFC3339Nano)
+		}
+		*unparsed = append(*unparsed, path)
+		return value
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return value
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() || field.Tag.Get("json") == "-" {
+				continue
+			}
+			name := jsonFieldName(field)
+			if fieldValue, ok := obj[name]; ok {
+				obj[name] = normalizeTimeValue(fieldValue, field.Type, joinPath(path, name), layouts, loc, unparsed)
+			}
+		}
+		return obj
+	case reflect.Slice, reflect.Array:
+		items, ok := value.([]any)
+		if !ok {
+			return value
+		}
+		for i, item := range items {
+			items[i] = normalizeTimeValue(item, t.Elem(), joinPath(path, strconv.Itoa(i)), layouts, loc, unparsed)
+		}
+		return items
+	case reflect.Map:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return value
+		}
+		for key, item := range obj {
+			obj[key] = normalizeTimeValue(item, t.Elem(), joinPath(path, key), layouts, loc, unparsed)
+		}
+		return obj
+	}
+	return value
+}
+
+func joinPath(parent, child string) string {
+	if parent == "" {
+		return child
+	}
+	return parent + "." + child
+}
+
+// applyTimezone converts every time.Time reachable from v (a pointer) to loc,
+// restoring the named location lost in the RFC3339 round trip
+func applyTimezone(v reflect.Value, loc *time.Location) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			applyTimezone(v.Elem(), loc)
+		}
+	case reflect.Struct:
+		if v.Type() == timeType {
+			if v.CanSet() && !v.Interface().(time.Time).IsZero() {
+				v.Set(reflect.ValueOf(v.Interface().(time.Time).In(loc)))
+			}
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				applyTimezone(v.Field(i), loc)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			applyTimezone(v.Index(i), loc)
+		}
+	case reflect.Map:
+		if v.Type().Elem() == timeType {
+			for _, key := range v.MapKeys() {
+				v.SetMapIndex(key, reflect.ValueOf(v.MapIndex(key).Interface().(time.Time).In(loc)))
+			}
+		}
+	}
+}
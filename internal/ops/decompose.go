@@ -208,6 +208,7 @@ func Decompose[T any](input T, opts DecomposeOptions) (DecomposeResult[T], error
 	}
 
 	opt := opts.toOpOptions()
+	opt.Operation = "decompose"
 
 	ctx := opt.Context
 	if ctx == nil {
@@ -344,6 +345,7 @@ func DecomposeToSlice[T any, U any](input T, opts DecomposeOptions) ([]U, error)
 	}
 
 	opt := opts.toOpOptions()
+	opt.Operation = "decompose"
 
 	ctx := opt.Context
 	if ctx == nil {
@@ -0,0 +1,430 @@
+// package ops - Streaming near-duplicate detection with an LSH prefilter
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/monstercameron/schemaflow/internal/config"
+	"github.com/monstercameron/schemaflow/internal/logger"
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+// DeduplicateStreamOptions configures the DeduplicateStream operation
+type DeduplicateStreamOptions struct {
+	// Threshold is the semantic similarity (0.0-1.0) at which the LLM treats a
+	// borderline pair as duplicates
+	Threshold float64
+
+	// DuplicateSimilarity is the estimated Jaccard similarity at or above which
+	// an item is dropped without asking the LLM
+	DuplicateSimilarity float64
+
+	// BorderlineSimilarity is the estimated Jaccard similarity below which a
+	// candidate is treated as distinct without asking the LLM
+	BorderlineSimilarity float64
+
+	// Window is the number of recent unique items remembered; older ones are
+	// evicted so memory stays bounded
+	Window int
+
+	// NumHashes is the MinHash signature length; it must be divisible by Bands
+	NumHashes int
+
+	// Bands is the number of LSH bands; more bands find less similar candidates
+	Bands int
+
+	// OnError is called when an LLM comparison fails; the item is kept
+	OnError func(error)
+
+	// Common options
+	Steering      string
+	Mode          types.Mode
+	Intelligence  types.Speed
+	Context       context.Context
+	RequestID     string
+	CorrelationID string
+}
+
+// DeduplicateStream drops near-duplicates from a live stream without holding
+// the whole stream in memory. Each item is MinHashed and looked up in an LSH
+// index over the last Window unique items: obvious duplicates are dropped and
+// obvious non-duplicates pass without a model call; only borderline pairs are
+// compared by the LLM. The output channel closes when in closes or the
+// context is cancelled.
+//
+// Examples:
+//
+//	unique := DeduplicateStream(events, DeduplicateStreamOptions{
+//	    Threshold: 0.85,
+//	    Window:    5000,
+//	})
+//	for event := range unique {
+//	    handle(event)
+//	}
+func DeduplicateStream[T any](in <-chan T, opts ...DeduplicateStreamOptions) <-chan T {
+	opt := DeduplicateStreamOptions{
+		Threshold:            0.85,
+		DuplicateSimilarity:  0.9,
+		BorderlineSimilarity: 0.5,
+		Window:               1000,
+		NumHashes:            64,
+		Bands:                16,
+		Mode:                 types.Strict,
+		Intelligence:         types.Fast,
+	}
+	if len(opts) > 0 {
+		opt = mergeDeduplicateStreamOptions(opt, opts[0])
+	}
+
+	ctx := opt.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		dedup := newStreamDeduper(opt)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case item, ok := <-in:
+				if !ok {
+					return
+				}
+				if dedup.isDuplicate(ctx, item) {
+					continue
+				}
+				select {
+				case out <- item:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// streamEntry is a remembered unique item
+type streamEntry struct {
+	id        int
+	text      string
+	signature []uint64
+	buckets   []string
+}
+
+// streamDeduper holds the bounded LSH index used by DeduplicateStream
+type streamDeduper struct {
+	opt     DeduplicateStreamOptions
+	seeds   []uint64
+	nextID  int
+	entries []*streamEntry       // oldest first, at most opt.Window
+	byID    map[int]*streamEntry // live entries
+	buckets map[string][]int     // LSH bucket -> entry ids
+}
+
+func newStreamDeduper(opt DeduplicateStreamOptions) *streamDeduper {
+	seeds := make([]uint64, opt.NumHashes)
+	state := uint64(0x9E3779B97F4A7C15)
+	for i := range seeds {
+		state = splitMix64(state)
+		seeds[i] = state
+	}
+	return &streamDeduper{
+		opt:     opt,
+		seeds:   seeds,
+		byID:    make(map[int]*streamEntry),
+		buckets: make(map[string][]int),
+	}
+}
+
+// isDuplicate reports whether item duplicates a remembered one; unique items
+// are added to the index
+func (d *streamDeduper) isDuplicate(ctx context.Context, item any) bool {
+	log := logger.GetLogger()
+
+	data, err := json.Marshal(item)
+	if err != nil {
+		d.reportError(fmt.Errorf("failed to marshal item: %w", err))
+		return false
+	}
+	text := streamItemText(data)
+	signature := d.minHash(text)
+	buckets := d.bandKeys(signature)
+
+	// Estimate similarity against every LSH candidate
+	type candidate struct {
+		entry      *streamEntry
+		similarity float64
+	}
+	seen := make(map[int]bool)
+	var borderline []candidate
+	for _, key := range buckets {
+		for _, id := range d.buckets[key] {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			entry := d.byID[id]
+			similarity := signatureSimilarity(signature, entry.signature)
+			if similarity >= d.opt.DuplicateSimilarity {
+				log.Debug("DeduplicateStream dropped duplicate", "similarity", similarity)
+				return true
+			}
+			if similarity >= d.opt.BorderlineSimilarity {
+				borderline = append(borderline, candidate{entry, similarity})
+			}
+		}
+	}
+
+	if len(borderline) > 0 {
+		sort.SliceStable(borderline, func(i, j int) bool {
+			return borderline[i].similarity > borderline[j].similarity
+		})
+		texts := make([]string, len(borderline))
+		for i, c := range borderline {
+			texts[i] = c.entry.text
+		}
+		duplicate, err := d.compare(ctx, string(data), texts)
+		if err != nil {
+			d.reportError(err)
+		} else if duplicate {
+			log.Debug("DeduplicateStream dropped semantic duplicate", "candidates", len(borderline))
+			return true
+		}
+	}
+
+	d.remember(&streamEntry{text: string(data), signature: signature, buckets: buckets})
+	return false
+}
+
+// remember indexes a unique entry, evicting the oldest beyond the window
+func (d *streamDeduper) remember(entry *streamEntry) {
+	entry.id = d.nextID
+	d.nextID++
+	d.entries = append(d.entries, entry)
+	d.byID[entry.id] = entry
+	for _, key := range entry.buckets {
+		d.buckets[key] = append(d.buckets[key], entry.id)
+	}
+
+	for d.opt.Window > 0 && len(d.entries) > d.opt.Window {
+		oldest := d.entries[0]
+		d.entries[0] = nil
+		d.entries = d.entries[1:]
+		delete(d.byID, oldest.id)
+		for _, key := range oldest.buckets {
+			ids := d.buckets[key]
+			for i, id := range ids {
+				if id == oldest.id {
+					ids = append(ids[:i], ids[i+1:]...)
+					break
+				}
+			}
+			if len(ids) == 0 {
+				delete(d.buckets, key)
+			} else {
+				d.buckets[key] = ids
+			}
+		}
+	}
+}
+
+// compare asks the LLM whether item duplicates any of the candidates
+func (d *streamDeduper) compare(ctx context.Context, item string, candidates []string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.GetTimeout())
+	defer cancel()
+
+	lines := make([]string, len(candidates))
+	for i, c := range candidates {
+		lines[i] = fmt.Sprintf("[%d] %s", i, c)
+	}
+
+	systemPrompt := fmt.Sprintf(`You are a deduplication expert. Decide whether a new item is a duplicate of any earlier item.
+Items with semantic similarity >= %.2f are duplicates (same entity or event, possibly worded differently).
+
+Return a JSON object with:
+{
+  "duplicate_of": 0,
+  "similarity": 0.9
+}
+Use -1 for "duplicate_of" when the new item is not a duplicate.`, d.opt.Threshold)
+
+	userPrompt := fmt.Sprintf("New item:\n%s\n\nEarlier items:\n%s", item, strings.Join(lines, "\n"))
+
+	response, err := callLLM(ctx, systemPrompt, userPrompt, types.OpOptions{
+		Steering:      d.opt.Steering,
+		Mode:          d.opt.Mode,
+		Intelligence:  d.opt.Intelligence,
+		Context:       ctx,
+		RequestID:     d.opt.RequestID,
+		CorrelationID: d.opt.CorrelationID,
+		Operation:     "deduplicate",
+	})
+	if err != nil {
+		return false, fmt.Errorf("duplicate comparison failed: %w", err)
+	}
+
+	var verdict struct {
+		DuplicateOf int     `json:"duplicate_of"`
+		Similarity  float64 `json:"similarity"`
+	}
+	if err := ParseJSON(response, &verdict); err != nil {
+		return false, fmt.Errorf("failed to parse duplicate comparison: %w", err)
+	}
+	return verdict.DuplicateOf >= 0 && verdict.DuplicateOf < len(candidates), nil
+}
+
+func (d *streamDeduper) reportError(err error) {
+	logger.GetLogger().Error("DeduplicateStream comparison failed", "error", err)
+	if d.opt.OnError != nil {
+		d.opt.OnError(err)
+	}
+}
+
+// minHash computes the MinHash signature of text's character 4-gram shingles
+func (d *streamDeduper) minHash(text string) []uint64 {
+	signature := make([]uint64, len(d.seeds))
+	for i := range signature {
+		signature[i] = math.MaxUint64
+	}
+	for _, shingle := range streamShingles(text) {
+		h := fnv.New64a()
+		h.Write([]byte(shingle))
+		base := h.Sum64()
+		for i, seed := range d.seeds {
+			if v := splitMix64(base ^ seed); v < signature[i] {
+				signature[i] = v
+			}
+		}
+	}
+	return signature
+}
+
+// bandKeys splits a signature into LSH bucket keys, one per band
+func (d *streamDeduper) bandKeys(signature []uint64) []string {
+	rows := len(signature) / d.opt.Bands
+	keys := make([]string, d.opt.Bands)
+	for b := 0; b < d.opt.Bands; b++ {
+		h := fnv.New64a()
+		for _, v := range signature[b*rows : (b+1)*rows] {
+			var buf [8]byte
+			for i := range buf {
+				buf[i] = byte(v >> (8 * i))
+			}
+			h.Write(buf[:])
+		}
+		keys[b] = fmt.Sprintf("%d:%x", b, h.Sum64())
+	}
+	return keys
+}
+
+// signatureSimilarity estimates Jaccard similarity from two MinHash signatures
+func signatureSimilarity(a, b []uint64) float64 {
+	matches := 0
+	for i := range a {
+		if a[i] == b[i] {
+			matches++
+		}
+	}
+	return float64(matches) / float64(len(a))
+}
+
+// streamItemText flattens an item's JSON into its lowercased values so field
+// names shared by every item do not inflate similarity
+func streamItemText(data []byte) string {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return strings.ToLower(string(data))
+	}
+	var parts []string
+	var walk func(v any)
+	walk = func(v any) {
+		switch t := v.(type) {
+		case map[string]any:
+			for _, key := range sortedKeys(t) {
+				walk(t[key])
+			}
+		case []any:
+			for _, item := range t {
+				walk(item)
+			}
+		case nil:
+		default:
+			parts = append(parts, fmt.Sprint(t))
+		}
+	}
+	walk(value)
+	return strings.ToLower(strings.Join(strings.Fields(strings.Join(parts, " ")), " "))
+}
+
+// streamShingles returns the character 4-grams of text (or text itself if shorter)
+func streamShingles(text string) []string {
+	runes := []rune(text)
+	if len(runes) <= 4 {
+		return []string{text}
+	}
+	shingles := make([]string, 0, len(runes)-3)
+	for i := 0; i+4 <= len(runes); i++ {
+		shingles = append(shingles, string(runes[i:i+4]))
+	}
+	return shingles
+}
+
+// splitMix64 is a fast 64-bit mixer used to derive independent hash functions
+func splitMix64(x uint64) uint64 {
+	x += 0x9E3779B97F4A7C15
+	x = (x ^ (x >> 30)) * 0xBF58476D1CE4E5B9
+	x = (x ^ (x >> 27)) * 0x94D049BB133111EB
+	return x ^ (x >> 31)
+}
+
+// mergeDeduplicateStreamOptions merges user options with defaults
+func mergeDeduplicateStreamOptions(defaults, user DeduplicateStreamOptions) DeduplicateStreamOptions {
+	if user.Threshold > 0 {
+		defaults.Threshold = user.Threshold
+	}
+	if user.DuplicateSimilarity > 0 {
+		defaults.DuplicateSimilarity = user.DuplicateSimilarity
+	}
+	if user.BorderlineSimilarity > 0 {
+		defaults.BorderlineSimilarity = user.BorderlineSimilarity
+	}
+	if user.Window > 0 {
+		defaults.Window = user.Window
+	}
+	if user.NumHashes > 0 && user.Bands > 0 && user.NumHashes%user.Bands == 0 {
+		defaults.NumHashes = user.NumHashes
+		defaults.Bands = user.Bands
+	}
+	if user.OnError != nil {
+		defaults.OnError = user.OnError
+	}
+	if user.Steering != "" {
+		defaults.Steering = user.Steering
+	}
+	if user.Mode != 0 {
+		defaults.Mode = user.Mode
+	}
+	if user.Intelligence != 0 {
+		defaults.Intelligence = user.Intelligence
+	}
+	if user.Context != nil {
+		defaults.Context = user.Context
+	}
+	if user.RequestID != "" {
+		defaults.RequestID = user.RequestID
+	}
+	if user.CorrelationID != "" {
+		defaults.CorrelationID = user.CorrelationID
+	}
+	return defaults
+}
@@ -0,0 +1,112 @@
+package ops
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+type streamEvent struct {
+	Source  string `json:"source"`
+	Message string `json:"message"`
+}
+
+func distinctStreamEvent(i int) streamEvent {
+	sources := []string{"billing", "auth", "search", "checkout", "inventory", "shipping", "profile"}
+	verbs := []string{"timed out", "returned 503", "exceeded quota", "lost its lease", "rejected a token", "dropped packets"}
+	return streamEvent{
+		Source:  fmt.Sprintf("%s-%d", sources[i%len(sources)], i*7919%1000),
+		Message: fmt.Sprintf("node %d %s after %d retries in zone %c", i*31%997, verbs[i%len(verbs)], i%9, 'a'+rune(i%26)),
+	}
+}
+
+func TestDeduplicateStreamDropsInterleavedDuplicates(t *testing.T) {
+	llmCalls := 0
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		llmCalls++
+		newItem := strings.SplitN(user, "Earlier items:", 2)[0]
+		if strings.Contains(newItem, "(retransmitted)") {
+			return `{"duplicate_of": 0, "similarity": 0.95}`, nil
+		}
+		return `{"duplicate_of": -1, "similarity": 0.2}`, nil
+	})
+	defer setupMockClient()
+
+	in := make(chan streamEvent)
+	var want []streamEvent
+	go func() {
+		defer close(in)
+		for i := 0; i < 60; i++ {
+			event := distinctStreamEvent(i)
+			want = append(want, event)
+			in <- event
+			if i%5 == 4 {
+				in <- distinctStreamEvent(i - 2) // exact duplicate of a recent event
+			}
+			if i%10 == 9 {
+				near := distinctStreamEvent(i - 1)
+				near.Message += " (retransmitted)"
+				in <- near // near duplicate only the LLM can confirm
+			}
+		}
+	}()
+
+	var got []streamEvent
+	for event := range DeduplicateStream(in, DeduplicateStreamOptions{Window: 20}) {
+		got = append(got, event)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d events, want %d unique events", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if llmCalls == 0 {
+		t.Error("expected borderline near-duplicates to be confirmed by the LLM")
+	}
+	if llmCalls >= 60 {
+		t.Errorf("LLM called %d times; the LSH prefilter should skip obvious pairs", llmCalls)
+	}
+}
+
+func TestStreamDeduperMemoryIsBounded(t *testing.T) {
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		return `{"duplicate_of": -1}`, nil
+	})
+	defer setupMockClient()
+
+	opt := mergeDeduplicateStreamOptions(DeduplicateStreamOptions{
+		Threshold: 0.85, DuplicateSimilarity: 0.9, BorderlineSimilarity: 0.5,
+		NumHashes: 64, Bands: 16,
+	}, DeduplicateStreamOptions{Window: 25})
+	dedup := newStreamDeduper(opt)
+
+	for i := 0; i < 500; i++ {
+		dedup.isDuplicate(context.Background(), distinctStreamEvent(i))
+	}
+
+	if len(dedup.entries) > 25 || len(dedup.byID) > 25 {
+		t.Errorf("remembered %d entries (%d by id), want at most 25", len(dedup.entries), len(dedup.byID))
+	}
+	indexed := 0
+	for _, ids := range dedup.buckets {
+		indexed += len(ids)
+	}
+	if indexed > 25*opt.Bands {
+		t.Errorf("LSH index holds %d ids, want at most %d", indexed, 25*opt.Bands)
+	}
+
+	// An evicted item is no longer remembered; a recent one still is
+	if dedup.isDuplicate(context.Background(), distinctStreamEvent(0)) {
+		t.Error("evicted item should not be reported as a duplicate")
+	}
+	if !dedup.isDuplicate(context.Background(), distinctStreamEvent(499)) {
+		t.Error("recent item should be reported as a duplicate")
+	}
+}
@@ -0,0 +1,143 @@
+// package ops - Process-wide default operation options
+package ops
+
+import (
+	"maps"
+	"sync"
+
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+// optionField identifies a CommonOptions field whose zero value is a valid
+// per-call choice, so setting it must be recorded to win over defaults
+type optionField uint8
+
+const (
+	fieldThreshold optionField = 1 << iota
+	fieldMode
+	fieldIntelligence
+	fieldDryRun
+	fieldPreserveLanguage
+	fieldInjectionGuard
+)
+
+var (
+	defaultOptionsMu sync.RWMutex
+	defaultOptions   *types.OpOptions
+	defaultPersona   string
+)
+
+// SetDefaultOptions registers options applied to every operation unless the
+// call overrides them. Precedence is call > default > built-in: a field set
+// through one of the call options' With methods wins, then the registered
+// default, then the operation's own default. Fields assigned directly win
+// when non-zero, except Mode and Intelligence, whose constructor values
+// cannot be told apart from a choice.
+//
+// Mode and Intelligence are always taken from defaults, since their zero
+// values (Strict, Smart) are meaningful; other fields apply when non-zero.
+// Request metadata is merged, with per-call entries winning. Context and
+// request IDs are never defaulted. Legacy operations taking variadic
+// OpOptions start from the defaults, with Mode and Intelligence always
+// replaced by the options passed.
+func SetDefaultOptions(opts types.OpOptions) {
+	defaultOptionsMu.Lock()
+	defer defaultOptionsMu.Unlock()
+	defaultOptions = &opts
+}
+
+// ClearDefaultOptions removes options registered with SetDefaultOptions
+func ClearDefaultOptions() {
+	defaultOptionsMu.Lock()
+	defer defaultOptionsMu.Unlock()
+	defaultOptions = nil
+}
+
+// SetDefaultPersona sets system text placed before the system prompt of every
+// operation whose options and registered defaults set no Persona; an empty
+// persona removes it
+func SetDefaultPersona(persona string) {
+	defaultOptionsMu.Lock()
+	defer defaultOptionsMu.Unlock()
+	defaultPersona = persona
+}
+
+func getDefaultPersona() string {
+	defaultOptionsMu.RLock()
+	defer defaultOptionsMu.RUnlock()
+	return defaultPersona
+}
+
+func getDefaultOptions() (types.OpOptions, bool) {
+	defaultOptionsMu.RLock()
+	defer defaultOptionsMu.RUnlock()
+	if defaultOptions == nil {
+		return types.OpOptions{}, false
+	}
+	return *defaultOptions, true
+}
+
+// withDefaults fills the fields the call did not set from the default options
+// of the call's client scope, or the registered ones
+func (c CommonOptions) withDefaults() CommonOptions {
+	defaults, ok := defaultOptionsFor(c.GetContext())
+	if c.Persona == "" && defaults.Persona == "" {
+		c.Persona = defaultPersonaFor(c.GetContext())
+	}
+	if !ok {
+		return c
+	}
+	set := func(field optionField) bool { return c.explicit&field != 0 }
+
+	if c.Steering == "" {
+		c.Steering = defaults.Steering
+	}
+	if c.Persona == "" {
+		c.Persona = defaults.Persona
+	}
+	if c.Threshold == 0 && !set(fieldThreshold) {
+		c.Threshold = defaults.Threshold
+	}
+	if !set(fieldMode) {
+		c.Mode = defaults.Mode
+	}
+	if !set(fieldIntelligence) {
+		c.Intelligence = defaults.Intelligence
+	}
+	if c.Temperature == nil {
+		c.Temperature, c.RawTemperature = defaults.Temperature, defaults.RawTemperature
+	}
+	if !c.DryRun && !set(fieldDryRun) {
+		c.DryRun = defaults.DryRun
+	}
+	if !c.PreserveLanguage && !set(fieldPreserveLanguage) {
+		c.PreserveLanguage = defaults.PreserveLanguage
+	}
+	if !c.InjectionGuard && !set(fieldInjectionGuard) {
+		c.InjectionGuard = defaults.InjectionGuard
+	}
+	if c.SemanticCacheThreshold == 0 {
+		c.SemanticCacheThreshold = defaults.SemanticCacheThreshold
+	}
+	if c.ReasoningEffort == "" {
+		c.ReasoningEffort = defaults.ReasoningEffort
+	}
+	if c.JSONMode == "" {
+		c.JSONMode = defaults.JSONMode
+	}
+	if c.MaxInputBytes == 0 {
+		c.MaxInputBytes = defaults.MaxInputBytes
+	}
+	if c.MaxPromptTokens == 0 {
+		c.MaxPromptTokens = defaults.MaxPromptTokens
+	}
+	if c.TruncationPolicy == "" {
+		c.TruncationPolicy = defaults.TruncationPolicy
+	}
+	if len(defaults.RequestMetadata) > 0 {
+		metadata := maps.Clone(defaults.RequestMetadata)
+		maps.Copy(metadata, c.RequestMetadata)
+		c.RequestMetadata = metadata
+	}
+	return c
+}
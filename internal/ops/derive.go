@@ -132,12 +132,7 @@ func Derive[T any, U any](input T, opts ...DeriveOptions) (DeriveResult[U], erro
 	result.Metadata = make(map[string]any)
 
 	// Apply defaults
-	opt := DeriveOptions{
-		IncludeReasoning: true,
-		MinConfidence:    0.6,
-		Mode:             types.TransformMode,
-		Intelligence:     types.Fast,
-	}
+	opt := defaultDeriveOptions()
 	if len(opts) > 0 {
 		opt = mergeDeriveOptions(opt, opts[0])
 	}
@@ -226,6 +221,7 @@ Rules:
 		Context:       ctx,
 		RequestID:     opt.RequestID,
 		CorrelationID: opt.CorrelationID,
+		Operation:     "derive",
 	}
 
 	response, err := callLLM(ctx, systemPrompt, userPrompt, opOpts)
@@ -278,6 +274,16 @@ Rules:
 	return result, nil
 }
 
+// defaultDeriveOptions returns the options Derive runs with when none are given
+func defaultDeriveOptions() DeriveOptions {
+	return DeriveOptions{
+		IncludeReasoning: true,
+		MinConfidence:    0.6,
+		Mode:             types.TransformMode,
+		Intelligence:     types.Fast,
+	}
+}
+
 // mergeDeriveOptions merges user options with defaults
 func mergeDeriveOptions(defaults, user DeriveOptions) DeriveOptions {
 	if user.Fields != nil {
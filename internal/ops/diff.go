@@ -341,7 +341,11 @@ func formatValue(v reflect.Value) any {
 
 // generateDiffSummary uses LLM to create an intelligent summary of changes
 func generateDiffSummary(oldData, newData any, changes comparisonResult, opts DiffOptions) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), config.GetTimeout())
+	ctx := opts.OpOptions.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, config.GetTimeout())
 	defer cancel()
 
 	// Marshal data for prompt (only when needed)
@@ -387,6 +391,7 @@ Keep the summary under 200 words and focus on actionable insights.`
 
 	// Call LLM for summary
 	opt := opts.toOpOptions()
+	opt.Operation = "diff"
 
 	response, err := callLLM(ctx, systemPrompt, userPrompt, opt)
 	if err != nil {
@@ -395,3 +400,161 @@ Keep the summary under 200 words and focus on actionable insights.`
 
 	return strings.TrimSpace(response), nil
 }
+
+// CompareAndMerge diffs two records and proposes a merged record in one call,
+// for reconciliation views that show both. The diff is computed locally as in
+// Diff; the model resolves only the fields that differ, and each of them is
+// reported as a MergeConflict naming both values, the chosen value and why.
+// Fields the records agree on are copied into the merged record unchanged.
+//
+// Examples:
+//
+//	diff, merged, err := CompareAndMerge(crmCustomer, billingCustomer,
+//	    NewDiffOptions().WithContext("Prefer the most recently verified contact details"))
+//	for _, conflict := range merged.Conflicts {
+//	    fmt.Printf("%s: %v (%s)\n", conflict.Field, conflict.ChosenValue, conflict.Resolution)
+//	}
+func CompareAndMerge[T any](a, b T, opts DiffOptions) (DiffResult, MergeResult[T], error) {
+	log := logger.GetLogger()
+	log.Debug("Starting compare and merge operation", "requestID", opts.RequestID)
+
+	merged := MergeResult[T]{Merged: a, SourcesUsed: []int{0}, Confidence: 1.0, Strategy: "compare and merge"}
+
+	if err := opts.Validate(); err != nil {
+		return DiffResult{}, merged, fmt.Errorf("invalid options: %w", err)
+	}
+
+	if reflect.DeepEqual(a, b) {
+		return DiffResult{Summary: "No changes detected - data is identical"}, merged, nil
+	}
+
+	changes, err := compareData(a, b, opts)
+	if err != nil {
+		return DiffResult{}, merged, fmt.Errorf("comparison failed: %w", err)
+	}
+	diff := DiffResult{Added: changes.Added, Removed: changes.Removed, Modified: changes.Modified}
+	if len(changes.Modified) == 0 {
+		diff.Summary = "No changes detected between the data instances"
+		return diff, merged, nil
+	}
+
+	aJSON, err := json.Marshal(a)
+	if err != nil {
+		return diff, merged, fmt.Errorf("failed to marshal first record: %w", err)
+	}
+	bJSON, err := json.Marshal(b)
+	if err != nil {
+		return diff, merged, fmt.Errorf("failed to marshal second record: %w", err)
+	}
+	changesJSON, err := json.Marshal(changes.Modified)
+	if err != nil {
+		return diff, merged, fmt.Errorf("failed to marshal changes: %w", err)
+	}
+
+	systemPrompt := fmt.Sprintf(`You are a data reconciliation expert. Two records describe the same entity; merge them into one.
+
+Respond ONLY with valid JSON in this exact format:
+{
+  "merged": { the merged record matching this schema: %s },
+  "conflicts": [
+    {"field": "FieldName", "resolution": "why this value was chosen"}
+  ],
+  "summary": "one or two sentences on how the records differ",
+  "confidence": 0.9
+}
+
+Rules:
+- Only the listed differing fields need a decision; copy every other field unchanged
+- For each differing field choose the better value or combine them, and add one conflict entry explaining the choice
+- "confidence": A value from 0.0 to 1.0 indicating merge quality`, GenerateTypeSchema(reflect.TypeOf(a)))
+
+	userPrompt := fmt.Sprintf("Record A (old_value):\n%s\n\nRecord B (new_value):\n%s\n\nDiffering fields:\n%s", aJSON, bJSON, changesJSON)
+	if opts.Context != "" {
+		userPrompt += "\n\nContext: " + opts.Context
+	}
+
+	ctx := opts.OpOptions.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, config.GetTimeout())
+	defer cancel()
+
+	opt := opts.toOpOptions()
+	opt.Operation = "diff"
+	response, err := callLLM(ctx, systemPrompt, userPrompt, opt)
+	if err != nil {
+		log.Error("CompareAndMerge operation LLM call failed", "requestID", opts.RequestID, "error", err)
+		return diff, merged, fmt.Errorf("merge failed: %w", err)
+	}
+
+	var parsed struct {
+		Merged    json.RawMessage `json:"merged"`
+		Conflicts []struct {
+			Field      string `json:"field"`
+			Resolution string `json:"resolution"`
+		} `json:"conflicts"`
+		Summary    string  `json:"summary"`
+		Confidence float64 `json:"confidence"`
+	}
+	if err := ParseJSON(response, &parsed); err != nil {
+		return diff, merged, fmt.Errorf("failed to parse merge result: %w", err)
+	}
+	var proposed T
+	if err := json.Unmarshal(parsed.Merged, &proposed); err != nil {
+		return diff, merged, fmt.Errorf("failed to parse merged record: %w", err)
+	}
+	diff.Summary = strings.TrimSpace(parsed.Summary)
+
+	// Take only the differing fields from the proposal and everything else
+	// from the first record, so the merged record never contradicts the diff
+	proposedValue := reflect.ValueOf(&proposed).Elem()
+	firstValue := reflect.ValueOf(a)
+	for proposedValue.Kind() == reflect.Ptr {
+		if proposedValue.IsNil() {
+			return diff, merged, fmt.Errorf("merged record is empty")
+		}
+		proposedValue = proposedValue.Elem()
+		firstValue = firstValue.Elem()
+	}
+	differing := make(map[string]DiffChange, len(changes.Modified))
+	for _, change := range changes.Modified {
+		differing[change.Field] = change
+	}
+	resolutions := make(map[string]string, len(parsed.Conflicts))
+	for _, conflict := range parsed.Conflicts {
+		resolutions[strings.ToLower(conflict.Field)] = conflict.Resolution
+	}
+	recordType := proposedValue.Type()
+	for i := 0; i < recordType.NumField(); i++ {
+		field := recordType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		change, ok := differing[field.Name]
+		if !ok {
+			proposedValue.Field(i).Set(firstValue.Field(i))
+			continue
+		}
+
+		resolution := resolutions[strings.ToLower(field.Name)]
+		if resolution == "" {
+			resolution = resolutions[strings.ToLower(jsonFieldName(field))]
+		}
+		if resolution == "" {
+			resolution = "chosen by the model without explanation"
+		}
+		merged.Conflicts = append(merged.Conflicts, MergeConflict{
+			Field:       change.Field,
+			Values:      []any{change.OldValue, change.NewValue},
+			Resolution:  resolution,
+			ChosenValue: proposedValue.Field(i).Interface(),
+		})
+	}
+	merged.Merged = proposed
+	merged.Confidence = parsed.Confidence
+	merged.SourcesUsed = []int{0, 1}
+
+	log.Debug("CompareAndMerge operation succeeded", "requestID", opts.RequestID, "conflicts", len(merged.Conflicts))
+	return diff, merged, nil
+}
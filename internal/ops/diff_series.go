@@ -0,0 +1,122 @@
+// package ops - DiffSeries for changelogs across a version history
+package ops
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/monstercameron/schemaflow/internal/config"
+	"github.com/monstercameron/schemaflow/internal/logger"
+)
+
+// DiffSeriesResult contains the consecutive diffs of a version history and
+// a changelog combining them
+type DiffSeriesResult struct {
+	Diffs     []DiffResult `json:"diffs"`     // Diffs[i] compares versions i and i+1
+	Changelog string       `json:"changelog"` // Release notes covering every step
+}
+
+// DiffSeries diffs each pair of consecutive versions, oldest first, and
+// summarizes the whole history as one changelog. When the changelog cannot
+// be generated it falls back to a plain list of the detected changes.
+//
+// Examples:
+//
+//	// Release notes for a config history
+//	series, err := DiffSeries([]Config{v1, v2, v3},
+//	    NewDiffOptions().WithContext("Service configuration"))
+//	fmt.Println(series.Changelog)
+func DiffSeries[T any](versions []T, opts DiffOptions) (DiffSeriesResult, error) {
+	log := logger.GetLogger()
+	log.Debug("Starting diff series operation", "requestID", opts.RequestID, "versions", len(versions))
+
+	result := DiffSeriesResult{}
+
+	if len(versions) < 2 {
+		return result, fmt.Errorf("diff series requires at least 2 versions, got %d", len(versions))
+	}
+
+	result.Diffs = make([]DiffResult, 0, len(versions)-1)
+	for i := 1; i < len(versions); i++ {
+		diff, err := diffImpl(versions[i-1], versions[i], opts)
+		if err != nil {
+			return result, fmt.Errorf("versions %d -> %d: %w", i, i+1, err)
+		}
+		result.Diffs = append(result.Diffs, diff)
+	}
+
+	entries := changelogEntries(result.Diffs)
+	if entries == "" {
+		result.Changelog = "No changes detected across the versions"
+		return result, nil
+	}
+
+	changelog, err := generateChangelog(entries, opts)
+	if err != nil {
+		log.Warn("Diff series changelog generation failed", "requestID", opts.RequestID, "error", err)
+		result.Changelog = entries
+	} else {
+		result.Changelog = changelog
+	}
+
+	log.Debug("Diff series operation succeeded", "requestID", opts.RequestID, "diffs", len(result.Diffs))
+	return result, nil
+}
+
+// changelogEntries lists the changes of each step that has any, one
+// "Version i -> i+1" section per step
+func changelogEntries(diffs []DiffResult) string {
+	var sections []string
+	for i, diff := range diffs {
+		var lines []string
+		for _, field := range diff.Added {
+			lines = append(lines, "- added "+field)
+		}
+		for _, field := range diff.Removed {
+			lines = append(lines, "- removed "+field)
+		}
+		for _, change := range diff.Modified {
+			lines = append(lines, fmt.Sprintf("- %s: %v -> %v", change.Field, change.OldValue, change.NewValue))
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		section := fmt.Sprintf("Version %d -> %d:\n%s", i+1, i+2, strings.Join(lines, "\n"))
+		if diff.Summary != "" {
+			section += "\nSummary: " + diff.Summary
+		}
+		sections = append(sections, section)
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// generateChangelog uses the LLM to turn per-step changes into one changelog
+func generateChangelog(entries string, opts DiffOptions) (string, error) {
+	opt := opts.toOpOptions()
+	opt.Operation = "diff"
+	ctx := opt.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, config.GetTimeout())
+	defer cancel()
+
+	systemPrompt := `You are a technical writer producing a changelog from a version history.
+Given the changes between each pair of consecutive versions, write concise release notes that:
+1. Cover every version step in order, oldest first
+2. Describe what changed in practical terms
+3. Point out changes that were later reverted or changed again
+Keep the changelog under 300 words.`
+
+	userPrompt := "Write a changelog for these version changes:\n\n" + entries
+	if opts.Context != "" {
+		userPrompt += "\n\nCONTEXT: " + opts.Context
+	}
+
+	response, err := callLLM(ctx, systemPrompt, userPrompt, opt)
+	if err != nil {
+		return "", fmt.Errorf("changelog generation failed: %w", err)
+	}
+	return strings.TrimSpace(response), nil
+}
@@ -219,3 +219,131 @@ func TestDiff(t *testing.T) {
 		}
 	})
 }
+
+func TestDiffSeriesBuildsChangelogAcrossVersions(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	type Config struct {
+		Endpoint string `json:"endpoint"`
+		Timeout  int    `json:"timeout"`
+		Retries  int    `json:"retries"`
+	}
+	versions := []Config{
+		{Endpoint: "https://api.example.com", Timeout: 30, Retries: 3},
+		{Endpoint: "https://api.example.com", Timeout: 60, Retries: 3},
+		{Endpoint: "https://api-v2.example.com", Timeout: 60, Retries: 5},
+	}
+
+	var changelogPrompt string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		if strings.Contains(system, "producing a changelog") {
+			changelogPrompt = user
+			return "v2 doubled the timeout to 60s. v3 moved to the api-v2 endpoint and raised retries to 5.", nil
+		}
+		return "step summary", nil
+	})
+
+	series, err := DiffSeries(versions, NewDiffOptions())
+	if err != nil {
+		t.Fatalf("DiffSeries() error = %v", err)
+	}
+	if len(series.Diffs) != 2 {
+		t.Fatalf("expected 2 consecutive diffs, got %d", len(series.Diffs))
+	}
+	if first := series.Diffs[0].Modified; len(first) != 1 || first[0].Field != "Timeout" {
+		t.Errorf("first diff should only change Timeout, got %+v", first)
+	}
+	if second := series.Diffs[1].Modified; len(second) != 2 {
+		t.Errorf("second diff should change Endpoint and Retries, got %+v", second)
+	}
+
+	v12 := strings.Index(changelogPrompt, "Version 1 -> 2:\n- Timeout: 30 -> 60")
+	v23 := strings.Index(changelogPrompt, "Version 2 -> 3:")
+	if v12 < 0 || v23 < v12 || !strings.Contains(changelogPrompt, "- Retries: 3 -> 5") {
+		t.Errorf("changelog prompt should list both steps in order, got %q", changelogPrompt)
+	}
+	if !strings.Contains(series.Changelog, "timeout") || !strings.Contains(series.Changelog, "api-v2") {
+		t.Errorf("changelog should cover both steps, got %q", series.Changelog)
+	}
+
+	if _, err := DiffSeries(versions[:1], NewDiffOptions()); err == nil {
+		t.Error("expected an error for a single version")
+	}
+}
+
+func TestCompareAndMergeKeepsMergeConsistentWithDiff(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	type customer struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Email string `json:"email"`
+		Phone string `json:"phone"`
+		Tier  string `json:"tier"`
+	}
+	crm := customer{ID: "C-17", Name: "Dana Whitfield", Email: "dana@old-mail.com", Phone: "555-0100", Tier: "gold"}
+	billing := customer{ID: "C-17", Name: "Dana Whitfield", Email: "dana@whitfield.io", Phone: "555-0100", Tier: "silver"}
+
+	// The model also rewrites the phone number, which both records agree on
+	calls := 0
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		calls++
+		if !strings.Contains(user, "dana@old-mail.com") || !strings.Contains(user, "dana@whitfield.io") {
+			t.Errorf("expected both records in the prompt: %q", user)
+		}
+		return `{
+			"merged": {"id": "C-17", "name": "Dana Whitfield", "email": "dana@whitfield.io", "phone": "555-9999", "tier": "gold"},
+			"conflicts": [
+				{"field": "email", "resolution": "billing address was verified more recently"},
+				{"field": "Tier", "resolution": "CRM tier reflects the current contract"}
+			],
+			"summary": "The records disagree on email and tier.",
+			"confidence": 0.85
+		}`, nil
+	})
+
+	diff, merged, err := CompareAndMerge(crm, billing, NewDiffOptions())
+	if err != nil {
+		t.Fatalf("CompareAndMerge failed: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected a single model call, got %d", calls)
+	}
+
+	if len(diff.Modified) != 2 || diff.Modified[0].Field != "Email" || diff.Modified[1].Field != "Tier" {
+		t.Fatalf("expected Email and Tier to differ, got %+v", diff.Modified)
+	}
+	want := customer{ID: "C-17", Name: "Dana Whitfield", Email: "dana@whitfield.io", Phone: "555-0100", Tier: "gold"}
+	if merged.Merged != want {
+		t.Errorf("merged = %+v, want %+v", merged.Merged, want)
+	}
+
+	// Every differing field is annotated with its values and the chosen one
+	if len(merged.Conflicts) != len(diff.Modified) {
+		t.Fatalf("expected one conflict per differing field, got %+v", merged.Conflicts)
+	}
+	for i, conflict := range merged.Conflicts {
+		change := diff.Modified[i]
+		if conflict.Field != change.Field || conflict.Values[0] != change.OldValue || conflict.Values[1] != change.NewValue {
+			t.Errorf("conflict %+v does not match change %+v", conflict, change)
+		}
+		if conflict.ChosenValue != change.OldValue && conflict.ChosenValue != change.NewValue {
+			t.Errorf("conflict %s chose %v, which neither record has", conflict.Field, conflict.ChosenValue)
+		}
+		if conflict.Resolution == "" {
+			t.Errorf("conflict %s has no resolution", conflict.Field)
+		}
+	}
+	if diff.Summary != "The records disagree on email and tier." || merged.Confidence != 0.85 {
+		t.Errorf("unexpected summary %q or confidence %v", diff.Summary, merged.Confidence)
+	}
+
+	// Identical records merge without a model call
+	calls = 0
+	_, same, err := CompareAndMerge(crm, crm, NewDiffOptions())
+	if err != nil || calls != 0 || same.Merged != crm || len(same.Conflicts) != 0 {
+		t.Errorf("expected identical records to merge locally, got %+v (calls %d, err %v)", same, calls, err)
+	}
+}
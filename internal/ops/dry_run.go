@@ -0,0 +1,107 @@
+// package ops - Dry-run mode: render requests without calling the provider
+package ops
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/monstercameron/schemaflow/internal/llm"
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+type dryRunKey struct{}
+
+// ContextWithDryRun marks ctx so every operation using it renders its request
+// and returns a *types.DryRunError instead of calling the provider
+func ContextWithDryRun(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// IsDryRun reports whether ctx was marked with ContextWithDryRun
+func IsDryRun(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	enabled, _ := ctx.Value(dryRunKey{}).(bool)
+	return enabled
+}
+
+// AsDryRun extracts the DryRunResult from an error returned in dry-run mode
+func AsDryRun(err error) (types.DryRunResult, bool) {
+	var dryErr *types.DryRunError
+	if errors.As(err, &dryErr) {
+		return dryErr.Result, true
+	}
+	return types.DryRunResult{}, false
+}
+
+// dryRunRecorderKey carries a *dryRunRecorder on a context
+type dryRunRecorderKey struct{}
+
+// dryRunRecorder collects the DryRunResult of every request rendered under
+// one context
+type dryRunRecorder struct {
+	mu      sync.Mutex
+	results []types.DryRunResult
+}
+
+// withDryRunRecorder attaches a recorder to ctx
+func withDryRunRecorder(ctx context.Context) (context.Context, *dryRunRecorder) {
+	recorder := &dryRunRecorder{}
+	return context.WithValue(ctx, dryRunRecorderKey{}, recorder), recorder
+}
+
+// take returns the results recorded since the last take
+func (r *dryRunRecorder) take() []types.DryRunResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	results := r.results
+	r.results = nil
+	return results
+}
+
+// dryRun describes the request the provider of ctx would receive
+func dryRun(ctx context.Context, systemPrompt, userPrompt string, opts types.OpOptions) error {
+	return dryRunFor(ctx, providerFor(ctx), systemPrompt, userPrompt, opts)
+}
+
+func dryRunFor(ctx context.Context, provider llm.Provider, systemPrompt, userPrompt string, opts types.OpOptions) error {
+	providerName := ""
+	if provider != nil {
+		providerName = provider.Name()
+	}
+	req := buildCompletionRequest(providerName, systemPrompt, userPrompt, opts)
+	rendered := req.SystemPrompt + "\n\n" + req.UserPrompt
+	result := types.DryRunResult{
+		RenderedPrompt:  rendered,
+		EstimatedTokens: estimateTokens(rendered),
+		Provider:        providerName,
+		Model:           req.Model,
+	}
+	if provider != nil {
+		result.EstimatedCost = provider.EstimateCost(req)
+	}
+	if recorder := findDryRunRecorder(ctx, opts.Context); recorder != nil {
+		recorder.mu.Lock()
+		recorder.results = append(recorder.results, result)
+		recorder.mu.Unlock()
+	}
+	return &types.DryRunError{Result: result}
+}
+
+// findDryRunRecorder returns the recorder on the first context carrying one
+func findDryRunRecorder(contexts ...context.Context) *dryRunRecorder {
+	for _, ctx := range contexts {
+		if ctx == nil {
+			continue
+		}
+		if recorder, ok := ctx.Value(dryRunRecorderKey{}).(*dryRunRecorder); ok {
+			return recorder
+		}
+	}
+	return nil
+}
@@ -185,6 +185,7 @@ func Enrich[T any, U any](input T, opts EnrichOptions) (EnrichResult[U], error)
 	}
 
 	opt := opts.toOpOptions()
+	opt.Operation = "enrich"
 
 	ctx := opt.Context
 	if ctx == nil {
@@ -310,6 +311,7 @@ func EnrichInPlace[T any](input T, opts EnrichOptions) (T, error) {
 	}
 
 	opt := opts.toOpOptions()
+	opt.Operation = "enrich"
 
 	ctx := opt.Context
 	if ctx == nil {
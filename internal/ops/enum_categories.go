@@ -0,0 +1,66 @@
+// package ops - Classify categories derived from registered string enum types
+package ops
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	enumRegistryMu sync.RWMutex
+	enumRegistry   = map[reflect.Type][]string{}
+)
+
+// RegisterEnum declares the constants of a string enum type so Classify can
+// use it as the category type C without listing the categories again. With a
+// registered C, Classify defaults its categories to values, rejects
+// WithCategories entries that are not among them, and only returns
+// alternatives that are. Registering a type again replaces its values.
+//
+// Example:
+//
+//	type Sentiment string
+//	const (
+//	    Positive Sentiment = "positive"
+//	    Negative Sentiment = "negative"
+//	)
+//	RegisterEnum(Positive, Negative)
+//	result, err := Classify[string, Sentiment](review, NewClassifyOptions())
+//	// result.Category is Positive or Negative
+func RegisterEnum[C ~string](values ...C) {
+	names := make([]string, len(values))
+	for i, value := range values {
+		names[i] = string(value)
+	}
+	enumRegistryMu.Lock()
+	defer enumRegistryMu.Unlock()
+	enumRegistry[reflect.TypeFor[C]()] = names
+}
+
+// enumValues returns the registered constants of C, if any
+func enumValues[C any]() ([]string, bool) {
+	enumRegistryMu.RLock()
+	defer enumRegistryMu.RUnlock()
+	values, ok := enumRegistry[reflect.TypeFor[C]()]
+	return values, ok
+}
+
+// withEnumCategories fills the categories of opts from C's registered
+// constants, or checks the listed categories against them
+func withEnumCategories[C any](opts ClassifyOptions) (ClassifyOptions, []string, error) {
+	values, ok := enumValues[C]()
+	if !ok {
+		return opts, nil, nil
+	}
+	if len(opts.Categories) == 0 {
+		opts.Categories = append([]string(nil), values...)
+		return opts, values, nil
+	}
+	for _, category := range opts.Categories {
+		if !containsFold(values, category) {
+			return opts, nil, fmt.Errorf("category %q is not a constant of %s (registered: %v)", category, reflect.TypeFor[C](), values)
+		}
+	}
+	return opts, values, nil
+}
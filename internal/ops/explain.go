@@ -251,7 +251,11 @@ type explanationResponse struct {
 
 // generateExplanation uses LLM to create a human explanation
 func generateExplanation(data any, analysis dataAnalysis, opts ExplainOptions) (explanationResponse, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), config.GetTimeout())
+	ctx := opts.OpOptions.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, config.GetTimeout())
 	defer cancel()
 
 	// Marshal data for prompt
@@ -268,6 +272,7 @@ func generateExplanation(data any, analysis dataAnalysis, opts ExplainOptions) (
 
 	// Call LLM for explanation
 	opt := opts.toOpOptions()
+	opt.Operation = "explain"
 
 	response, err := callLLM(ctx, systemPrompt, userPrompt, opt)
 	if err != nil {
@@ -127,6 +127,32 @@ type ValidationIssue struct {
 	Message     string `json:"message"`
 	Suggestion  string `json:"suggestion,omitempty"`
 	Explanation string `json:"explanation,omitempty"`
+
+	// ErrorCode is one of the ErrorCode constants, stable across messages
+	// and languages; empty when the issue fits none of them
+	ErrorCode string `json:"error_code,omitempty"`
+}
+
+// Machine-readable ValidationIssue codes
+const (
+	ErrorCodeInvalidFormat = "INVALID_FORMAT" // malformed value, e.g. a bad email
+	ErrorCodeOutOfRange    = "OUT_OF_RANGE"   // number or length outside its bounds
+	ErrorCodeRequired      = "REQUIRED"       // missing or empty value
+	ErrorCodeEnum          = "ENUM"           // value not among the allowed ones
+)
+
+// normalizeErrorCodes upper-cases the codes the model assigned and clears
+// any that are not ErrorCode constants
+func normalizeErrorCodes(issues []ValidationIssue) {
+	for i := range issues {
+		code := strings.ToUpper(strings.TrimSpace(issues[i].ErrorCode))
+		switch code {
+		case ErrorCodeInvalidFormat, ErrorCodeOutOfRange, ErrorCodeRequired, ErrorCodeEnum:
+		default:
+			code = ""
+		}
+		issues[i].ErrorCode = code
+	}
 }
 
 // ValidationResult contains the results of a validation operation (legacy)
@@ -166,6 +192,8 @@ type ValidateResult[T any] struct {
 }
 
 // Validate checks if data meets specified criteria using LLM interpretation.
+// Values that break a field's `validate` or `enum` tag are also checked
+// deterministically and always reported as errors.
 //
 // Type parameter T specifies the type being validated.
 //
@@ -208,6 +236,7 @@ func Validate[T any](data T, opts ValidateOptions) (ValidateResult[T], error) {
 	}
 
 	opt := opts.toOpOptions()
+	opt.Operation = "validate"
 
 	ctx := opt.Context
 	if ctx == nil {
@@ -225,56 +254,67 @@ func Validate[T any](data T, opts ValidateOptions) (ValidateResult[T], error) {
 		return result, fmt.Errorf("failed to marshal data: %w", err)
 	}
 
-	// Build rules description
-	rulesDesc := opts.Rules
-	if len(opts.FieldRules) > 0 {
-		var fieldRulesStr []string
-		for field, rule := range opts.FieldRules {
-			fieldRulesStr = append(fieldRulesStr, fmt.Sprintf("- %s: %s", field, rule))
-		}
-		if rulesDesc != "" {
-			rulesDesc += "\n\nField-specific rules:\n" + strings.Join(fieldRulesStr, "\n")
-		} else {
-			rulesDesc = "Field-specific rules:\n" + strings.Join(fieldRulesStr, "\n")
+	// Violations of validate and enum tags are found deterministically and
+	// always reported as errors, whatever the model concludes
+	tagIssues := checkTagConstraints(data)
+
+	// The rules prompt depends only on the type, rules and options, so hot
+	// paths reuse it; violations of validate and enum tags stay per call
+	fieldRules, schemaHints := ruleLines(opts.FieldRules), ruleLines(opts.SchemaHints)
+	ruleset := []string{opts.Rules, fmt.Sprint(opts.AutoCorrect, opts.IncludeExplanations, len(fieldRules))}
+	ruleset = append(append(ruleset, fieldRules...), schemaHints...)
+	compiled := cachedRules("validate", reflect.TypeOf(data), opt.Intelligence, ruleset, func() compiledRules {
+		// Build rules description
+		rulesDesc := opts.Rules
+		if len(fieldRules) > 0 {
+			if rulesDesc != "" {
+				rulesDesc += "\n\nField-specific rules:\n" + strings.Join(fieldRules, "\n")
+			} else {
+				rulesDesc = "Field-specific rules:\n" + strings.Join(fieldRules, "\n")
+			}
 		}
-	}
 
-	if len(opts.SchemaHints) > 0 {
-		var hintsStr []string
-		for field, hint := range opts.SchemaHints {
-			hintsStr = append(hintsStr, fmt.Sprintf("- %s: %s", field, hint))
+		if len(schemaHints) > 0 {
+			rulesDesc += "\n\nSchema hints:\n" + strings.Join(schemaHints, "\n")
 		}
-		rulesDesc += "\n\nSchema hints:\n" + strings.Join(hintsStr, "\n")
-	}
 
-	correctionNote := ""
-	if opts.AutoCorrect {
-		correctionNote = `
+		correctionNote := ""
+		if opts.AutoCorrect {
+			correctionNote = `
 If any issues are found and correction is possible, include a "corrected" field with the fixed version of the input data.`
-	}
+		}
 
-	explanationNote := ""
-	if opts.IncludeExplanations {
-		explanationNote = "\nInclude explanations for each issue."
-	}
+		explanationNote := ""
+		if opts.IncludeExplanations {
+			explanationNote = "\nInclude explanations for each issue."
+		}
 
-	systemPrompt := fmt.Sprintf(`You are a data validation expert. Validate the provided data against the given rules.%s%s
+		systemPrompt := fmt.Sprintf(`You are a data validation expert. Validate the provided data against the given rules.%s%s
 
 Severity levels:
 - "error": Critical issues that must be fixed
 - "warning": Issues that should be addressed
 - "info": Minor suggestions for improvement
 
+Error codes, one per error and warning:
+- "INVALID_FORMAT": the value is malformed (bad email, phone, date, pattern)
+- "OUT_OF_RANGE": a number or length is outside its allowed bounds
+- "REQUIRED": a required value is missing or empty
+- "ENUM": the value is not one of the allowed values
+
 Return a JSON object with:
 {
   "valid": boolean (true if no errors),
-  "errors": [{"field": "fieldName", "severity": "error", "message": "Issue description", "suggestion": "How to fix", "explanation": "Why this is wrong"}],
-  "warnings": [{"field": "fieldName", "severity": "warning", "message": "Issue", "suggestion": "How to fix"}],
+  "errors": [{"field": "fieldName", "severity": "error", "error_code": "OUT_OF_RANGE", "message": "Issue description", "suggestion": "How to fix", "explanation": "Why this is wrong"}],
+  "warnings": [{"field": "fieldName", "severity": "warning", "error_code": "INVALID_FORMAT", "message": "Issue", "suggestion": "How to fix"}],
   "info": [{"severity": "info", "message": "Suggestion"}],
   "corrected": { corrected data if applicable },
   "confidence": 0.0-1.0,
   "summary": "Overall assessment"
 }`, correctionNote, explanationNote)
+		return compiledRules{systemPrompt: systemPrompt, rulesDesc: rulesDesc}
+	})
+	systemPrompt, rulesDesc := compiled.systemPrompt, compiled.rulesDesc
 
 	userPrompt := fmt.Sprintf(`Validate this data:
 %s
@@ -314,19 +354,24 @@ Against these rules:
 	if err := json.Unmarshal([]byte(response), &llmResult); err != nil {
 		log.Error("Validate operation failed: parse error", "error", err, "response", response)
 		// Try to infer from plain text
-		result.Valid = strings.Contains(strings.ToLower(response), "valid")
+		result.Valid = strings.Contains(strings.ToLower(response), "valid") && len(tagIssues) == 0
 		result.Confidence = 0.5
-		if !result.Valid {
-			result.Errors = []ValidationIssue{{
+		result.Errors = tagIssues
+		if !strings.Contains(strings.ToLower(response), "valid") {
+			result.Errors = append(result.Errors, ValidationIssue{
 				Severity: "error",
 				Message:  response,
-			}}
+			})
 		}
 		return result, nil
 	}
 
-	result.Valid = llmResult.Valid
-	result.Errors = llmResult.Errors
+	normalizeErrorCodes(llmResult.Errors)
+	normalizeErrorCodes(llmResult.Warnings)
+	normalizeErrorCodes(llmResult.Info)
+
+	result.Valid = llmResult.Valid && len(tagIssues) == 0
+	result.Errors = append(tagIssues, llmResult.Errors...)
 	result.Warnings = llmResult.Warnings
 	result.Info = llmResult.Info
 	result.Confidence = llmResult.Confidence
@@ -360,8 +405,13 @@ func ValidateLegacy[T any](data T, rules string, opts ...types.OpOptions) (Valid
 	log.Debug("Starting legacy validate operation")
 
 	opt := applyDefaults(opts...)
+	opt.Operation = "validate"
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.GetTimeout())
+	ctx := opt.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, config.GetTimeout())
 	defer cancel()
 
 	// Convert data to JSON for validation
@@ -440,7 +490,12 @@ func Format(data any, template string, opts ...types.OpOptions) (string, error)
 	log.Debug("Starting format operation")
 
 	opt := applyDefaults(opts...)
-	ctx, cancel := context.WithTimeout(context.Background(), config.GetTimeout())
+	opt.Operation = "format"
+	ctx := opt.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, config.GetTimeout())
 	defer cancel()
 
 	// Convert data to string representation
@@ -483,7 +538,12 @@ func FormatWithMetadata(data any, template string, opts ...types.OpOptions) (For
 	log.Debug("Starting format with metadata operation")
 
 	opt := applyDefaults(opts...)
-	ctx, cancel := context.WithTimeout(context.Background(), config.GetTimeout())
+	opt.Operation = "format"
+	ctx := opt.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, config.GetTimeout())
 	defer cancel()
 
 	// Convert data to string representation
@@ -621,7 +681,12 @@ func Merge[T any](sources []T, strategy string, opts ...types.OpOptions) (T, err
 	}
 
 	opt := applyDefaults(opts...)
-	ctx, cancel := context.WithTimeout(context.Background(), config.GetTimeout())
+	opt.Operation = "merge"
+	ctx := opt.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, config.GetTimeout())
 	defer cancel()
 
 	// Convert sources to JSON
@@ -688,7 +753,12 @@ func MergeWithMetadata[T any](sources []T, strategy string, opts ...types.OpOpti
 	}
 
 	opt := applyDefaults(opts...)
-	ctx, cancel := context.WithTimeout(context.Background(), config.GetTimeout())
+	opt.Operation = "merge"
+	ctx := opt.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, config.GetTimeout())
 	defer cancel()
 
 	// Convert sources to JSON
@@ -938,6 +1008,7 @@ func Question[T any, A any](data T, opts QuestionOptions) (QuestionResult[A], er
 	}
 
 	opt := opts.toOpOptions()
+	opt.Operation = "question"
 
 	ctx := opt.Context
 	if ctx == nil {
@@ -1045,6 +1116,144 @@ Question: %s`, string(dataJSON), opts.Question)
 	return result, nil
 }
 
+// QuestionBatch answers several questions about the same input in a single
+// LLM call, sending the input once. Results are returned in question order;
+// opts.Question is ignored.
+//
+// Example:
+//
+//	results, err := QuestionBatch(report, []string{
+//	    "What was total revenue?",
+//	    "Which region grew fastest?",
+//	}, NewQuestionOptions(""))
+func QuestionBatch[I any](input I, questions []string, opts QuestionOptions) ([]QuestionResult[string], error) {
+	log := logger.GetLogger()
+	log.Debug("Starting question batch operation", "questionCount", len(questions))
+
+	if err := opts.CommonOptions.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+	if len(questions) == 0 {
+		return nil, fmt.Errorf("no questions provided")
+	}
+	for i, question := range questions {
+		if strings.TrimSpace(question) == "" {
+			return nil, fmt.Errorf("question %d cannot be empty", i)
+		}
+	}
+
+	opt := opts.toOpOptions()
+	opt.Operation = "question"
+
+	ctx := opt.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var cancel context.CancelFunc
+	ctx, cancel = context.WithTimeout(ctx, config.GetTimeout())
+	defer cancel()
+
+	var dataStr string
+	if s, ok := any(input).(string); ok {
+		dataStr = s
+	} else {
+		dataJSON, err := json.Marshal(input)
+		if err != nil {
+			log.Error("QuestionBatch operation failed: marshal error", "error", err)
+			return nil, fmt.Errorf("failed to marshal data: %w", err)
+		}
+		dataStr = string(dataJSON)
+	}
+
+	formatParts := []string{`"index": (question number)`, `"answer": "answer text"`}
+	if opts.IncludeConfidence {
+		formatParts = append(formatParts, `"confidence": 0.0-1.0`)
+	}
+	if opts.IncludeReasoning {
+		formatParts = append(formatParts, `"reasoning": "explanation of how you derived the answer"`)
+	}
+	if opts.IncludeEvidence {
+		formatParts = append(formatParts, `"evidence": ["supporting quotes or facts from the data"]`)
+	}
+
+	systemPrompt := fmt.Sprintf(`You are a data analysis expert. Answer every question about the provided data accurately and concisely.
+Base your answers only on the information provided. Answer each question independently.
+
+Return a JSON object with one entry per question:
+{
+  "answers": [
+    {%s}
+  ]
+}`, strings.Join(formatParts, ", "))
+
+	var questionList strings.Builder
+	for i, question := range questions {
+		fmt.Fprintf(&questionList, "[%d] %s\n", i, question)
+	}
+
+	userPrompt := fmt.Sprintf(`Data:
+%s
+
+Questions:
+%s`, dataStr, questionList.String())
+
+	response, err := callLLM(ctx, systemPrompt, userPrompt, opt)
+	if err != nil {
+		log.Error("QuestionBatch operation LLM call failed", "error", err)
+		return nil, fmt.Errorf("question answering failed: %w", err)
+	}
+
+	var parsed struct {
+		Answers []struct {
+			Index      int             `json:"index"`
+			Answer     json.RawMessage `json:"answer"`
+			Confidence float64         `json:"confidence"`
+			Reasoning  string          `json:"reasoning"`
+			Evidence   []string        `json:"evidence"`
+		} `json:"answers"`
+	}
+	if err := ParseJSON(response, &parsed); err != nil {
+		log.Error("QuestionBatch operation failed: parse error", "error", err)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	results := make([]QuestionResult[string], len(questions))
+	answered := make([]bool, len(questions))
+	for _, a := range parsed.Answers {
+		if a.Index < 0 || a.Index >= len(questions) || answered[a.Index] {
+			continue
+		}
+		var answer string
+		if err := json.Unmarshal(a.Answer, &answer); err != nil {
+			answer = string(a.Answer)
+		}
+		results[a.Index] = QuestionResult[string]{
+			Answer:     answer,
+			Confidence: a.Confidence,
+			Reasoning:  a.Reasoning,
+			Evidence:   a.Evidence,
+			Metadata:   map[string]any{"question": questions[a.Index]},
+		}
+		answered[a.Index] = true
+	}
+
+	var missing []int
+	for i, ok := range answered {
+		if !ok {
+			results[i].Metadata = map[string]any{"question": questions[i]}
+			missing = append(missing, i)
+		}
+	}
+	if len(missing) > 0 {
+		log.Error("QuestionBatch operation incomplete", "missing", missing)
+		return results, fmt.Errorf("no answer returned for questions %v", missing)
+	}
+
+	log.Debug("QuestionBatch operation succeeded", "questionCount", len(questions))
+	return results, nil
+}
+
 // QuestionLegacy answers questions about data (legacy interface)
 //
 // Examples:
@@ -1056,7 +1265,12 @@ func QuestionLegacy(data any, question string, opts ...types.OpOptions) (string,
 	log.Debug("Starting legacy question operation")
 
 	opt := applyDefaults(opts...)
-	ctx, cancel := context.WithTimeout(context.Background(), config.GetTimeout())
+	opt.Operation = "question"
+	ctx := opt.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, config.GetTimeout())
 	defer cancel()
 
 	// Convert data to string representation
@@ -1093,9 +1307,9 @@ Question: %s`, dataStr, question)
 
 // DeduplicateResult contains the results of deduplication
 type DeduplicateResult[T any] struct {
-	Unique       []T
-	Duplicates   [][]T // Groups of duplicates
-	TotalRemoved int
+	Unique       []T   `json:"unique"`
+	Duplicates   [][]T `json:"duplicates,omitempty"` // Groups of duplicates
+	TotalRemoved int   `json:"total_removed"`
 }
 
 // Deduplicate removes duplicates using semantic similarity
@@ -1119,7 +1333,12 @@ func Deduplicate[T any](items []T, threshold float64, opts ...types.OpOptions) (
 	}
 
 	opt := applyDefaults(opts...)
-	ctx, cancel := context.WithTimeout(context.Background(), config.GetTimeout())
+	opt.Operation = "deduplicate"
+	ctx := opt.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, config.GetTimeout())
 	defer cancel()
 
 	// Convert items to JSON for comparison
@@ -2,6 +2,7 @@ package ops
 
 import (
 	"context"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -284,6 +285,55 @@ func TestQuestion(t *testing.T) {
 	})
 }
 
+func TestQuestionBatch(t *testing.T) {
+	defer setupMockClient()
+
+	type SalesReport struct {
+		Quarter string         `json:"quarter"`
+		Revenue map[string]int `json:"revenue"`
+	}
+	report := SalesReport{Quarter: "Q3", Revenue: map[string]int{"north": 120, "south": 95, "west": 140}}
+	questions := []string{
+		"What was total revenue?",
+		"Which region sold the most?",
+		"Which region sold the least?",
+	}
+
+	calls := 0
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		calls++
+		if strings.Count(user, `"quarter"`) != 1 {
+			t.Errorf("input should be sent exactly once: %q", user)
+		}
+		// Answers deliberately returned out of order
+		return `{"answers": [
+			{"index": 2, "answer": "south", "confidence": 0.9},
+			{"index": 0, "answer": "355", "confidence": 0.95},
+			{"index": 1, "answer": "west", "confidence": 0.9}
+		]}`, nil
+	})
+
+	results, err := QuestionBatch(report, questions, NewQuestionOptions(""))
+	if err != nil {
+		t.Fatalf("QuestionBatch() error = %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("provider called %d times, want 1", calls)
+	}
+	want := []string{"355", "west", "south"}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(results), len(want))
+	}
+	for i, w := range want {
+		if results[i].Answer != w {
+			t.Errorf("results[%d].Answer = %q, want %q", i, results[i].Answer, w)
+		}
+		if results[i].Metadata["question"] != questions[i] {
+			t.Errorf("results[%d] question = %v, want %q", i, results[i].Metadata["question"], questions[i])
+		}
+	}
+}
+
 func TestDeduplicate(t *testing.T) {
 	setupMockClient()
 
@@ -381,3 +431,73 @@ func TestDeduplicate(t *testing.T) {
 		}
 	})
 }
+
+func TestValidateAssignsErrorCodes(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	type signup struct {
+		Name  string `json:"name"`
+		Age   int    `json:"age"`
+		Email string `json:"email"`
+	}
+
+	var system string
+	setLLMCaller(func(ctx context.Context, sys, user string, opts types.OpOptions) (string, error) {
+		system = sys
+		return `{
+			"valid": false,
+			"errors": [
+				{"field": "age", "severity": "error", "error_code": "OUT_OF_RANGE", "message": "Must be at least 18"},
+				{"field": "email", "severity": "error", "error_code": "invalid_format", "message": "Not a valid email address"},
+				{"field": "name", "severity": "error", "error_code": "TOO_SHORT", "message": "Name looks truncated"}
+			],
+			"confidence": 0.9
+		}`, nil
+	})
+
+	result, err := Validate(signup{Name: "Al", Age: 15, Email: "al-at-example"}, NewValidateOptions().
+		WithRules("age must be at least 18, email must be valid"))
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if !strings.Contains(system, `"OUT_OF_RANGE"`) || !strings.Contains(system, `"INVALID_FORMAT"`) {
+		t.Errorf("system prompt should define the error codes, got %q", system)
+	}
+	codes := map[string]string{}
+	for _, issue := range result.Errors {
+		codes[issue.Field] = issue.ErrorCode
+	}
+	if codes["age"] != ErrorCodeOutOfRange || codes["email"] != ErrorCodeInvalidFormat {
+		t.Errorf("codes = %v, want age OUT_OF_RANGE and email INVALID_FORMAT", codes)
+	}
+	if codes["name"] != "" {
+		t.Errorf("unknown code should be cleared, got %q", codes["name"])
+	}
+
+	// Tag violations carry codes without relying on the model
+	type taggedSignup struct {
+		Age   int    `json:"age" validate:"min=18"`
+		Email string `json:"email" validate:"email"`
+		Plan  string `json:"plan" enum:"free,pro"`
+		Name  string `json:"name" validate:"required"`
+	}
+	setLLMCaller(func(ctx context.Context, sys, user string, opts types.OpOptions) (string, error) {
+		return `{"valid": true, "errors": [], "confidence": 0.9}`, nil
+	})
+	tagged, err := Validate(taggedSignup{Age: 15, Email: "al-at-example", Plan: "gold"}, NewValidateOptions())
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	codes = map[string]string{}
+	for _, issue := range tagged.Errors {
+		codes[issue.Field] = issue.ErrorCode
+	}
+	want := map[string]string{"age": ErrorCodeOutOfRange, "email": ErrorCodeInvalidFormat, "plan": ErrorCodeEnum, "name": ErrorCodeRequired}
+	if !reflect.DeepEqual(codes, want) {
+		t.Errorf("tag codes = %v, want %v", codes, want)
+	}
+	if tagged.Valid {
+		t.Error("expected tag violations to make the result invalid")
+	}
+}
@@ -0,0 +1,111 @@
+// package ops - Candidate values for ambiguous extracted fields
+package ops
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// Candidate is one reading of an ambiguous field
+type Candidate struct {
+	// Value is the candidate field value
+	Value any `json:"value"`
+
+	// Confidence is the model's confidence in the candidate (0.0-1.0)
+	Confidence float64 `json:"confidence"`
+}
+
+// FieldAlternatives holds the candidates for a field the input supports more
+// than one reading of
+type FieldAlternatives struct {
+	// Value is the value placed in the extracted data, the most likely reading
+	Value any `json:"value"`
+
+	// Candidates lists every reading, most confident first
+	Candidates []Candidate `json:"candidates"`
+}
+
+// AlternativesResult is an extracted value with the candidates of each
+// ambiguous field
+type AlternativesResult[T any] struct {
+	// Value is the extracted data, holding the most likely reading of each field
+	Value T `json:"value"`
+
+	// Fields maps the top-level JSON names of ambiguous fields to their
+	// candidates; unambiguous fields are absent
+	Fields map[string]FieldAlternatives `json:"fields,omitempty"`
+
+	// PIIFields lists the fields of Value tagged `pii:"true"` that hold a
+	// value (see PIIFields)
+	PIIFields []string `json:"pii_fields,omitempty"`
+}
+
+const alternativesInstructions = `
+- Where the input supports more than one reading of a top-level field (e.g. a role given one title in the signature and another in the text), list the readings: return {"data": {...}, "alternatives": {...}} where "data" follows the schema with the most likely value and "alternatives" maps each ambiguous field name to [{"value": ..., "confidence": 0.0-1.0}, ...], most likely first
+- Omit fields with a single clear reading from "alternatives"`
+
+// extractReport collects the per-field reports of one extraction
+type extractReport struct {
+	grounding    map[string]FieldGrounding
+	alternatives map[string]FieldAlternatives
+}
+
+// ExtractAlternatives extracts T like Extract with alternatives enabled and
+// returns, for each top-level field the input reads more than one way, the
+// candidate values with their confidence instead of a single guess. The
+// extracted value holds the most likely candidate of each field.
+//
+// Example:
+//
+//	result, err := ExtractAlternatives[Contact](signature, NewExtractOptions())
+//	if title, ok := result.Fields["title"]; ok {
+//	    for _, candidate := range title.Candidates {
+//	        fmt.Printf("%v (%.0f%%)\n", candidate.Value, candidate.Confidence*100)
+//	    }
+//	}
+func ExtractAlternatives[T any](input any, opts ExtractOptions) (AlternativesResult[T], error) {
+	opts.Alternatives = true
+
+	var report extractReport
+	consistent, err := extractConsistent[T](input, opts, &report)
+	return AlternativesResult[T]{
+		Value:     consistent.Value,
+		Fields:    report.alternatives,
+		PIIFields: PIIFields(consistent.Value),
+	}, err
+}
+
+// splitAlternatives unwraps a {"data", "alternatives"} response into the data
+// object and the fields with at least two candidates. Responses that are not
+// an envelope are returned unchanged so parsing reports the error.
+func splitAlternatives(response string) (string, map[string]FieldAlternatives) {
+	var envelope struct {
+		Data         map[string]json.RawMessage `json:"data"`
+		Alternatives map[string][]Candidate     `json:"alternatives"`
+	}
+	if err := json.Unmarshal([]byte(cleanJSON(response)), &envelope); err != nil || envelope.Data == nil {
+		return response, nil
+	}
+
+	fields := make(map[string]FieldAlternatives)
+	for field, candidates := range envelope.Alternatives {
+		raw, ok := envelope.Data[field]
+		if !ok || len(candidates) < 2 {
+			continue
+		}
+		var value any
+		if err := json.Unmarshal(raw, &value); err != nil {
+			continue
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].Confidence > candidates[j].Confidence
+		})
+		fields[field] = FieldAlternatives{Value: value, Candidates: candidates}
+	}
+
+	data, err := json.Marshal(envelope.Data)
+	if err != nil {
+		return response, fields
+	}
+	return string(data), fields
+}
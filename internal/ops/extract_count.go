@@ -0,0 +1,66 @@
+// package ops - Item count expectations for slice extraction
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/monstercameron/schemaflow/internal/logger"
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+// hasExpectedCount reports whether WithExpectedCount bounds the item count
+func (e ExtractOptions) hasExpectedCount() bool {
+	return e.ExpectedCountMin > 0 || e.ExpectedCountMax > 0
+}
+
+// countInRange reports whether n satisfies the expected count bounds
+func (e ExtractOptions) countInRange(n int) bool {
+	return n >= e.ExpectedCountMin && (e.ExpectedCountMax == 0 || n <= e.ExpectedCountMax)
+}
+
+// expectedCountText describes the expected count bounds, e.g. "between 3 and 5"
+func (e ExtractOptions) expectedCountText() string {
+	switch {
+	case e.ExpectedCountMax == 0:
+		return fmt.Sprintf("at least %d", e.ExpectedCountMin)
+	case e.ExpectedCountMin == e.ExpectedCountMax:
+		return fmt.Sprintf("exactly %d", e.ExpectedCountMin)
+	default:
+		return fmt.Sprintf("between %d and %d", e.ExpectedCountMin, e.ExpectedCountMax)
+	}
+}
+
+// jsonArrayLength returns the number of elements in a JSON array response
+func jsonArrayLength(response string) (int, bool) {
+	var items []json.RawMessage
+	if err := ParseJSON(response, &items); err != nil {
+		return 0, false
+	}
+	return len(items), true
+}
+
+// correctItemCount re-prompts once when response holds an array whose length
+// is outside the expected count, returning the corrected response. Responses
+// that are not arrays, or already in range, are returned unchanged.
+func correctItemCount(ctx context.Context, systemPrompt, userPrompt, response string, opts ExtractOptions, opt types.OpOptions) (string, error) {
+	count, ok := jsonArrayLength(response)
+	if !ok || opts.countInRange(count) {
+		return response, nil
+	}
+
+	logger.GetLogger().Warn("Extract item count out of range, re-prompting",
+		"requestID", opt.RequestID,
+		"count", count,
+		"expected", opts.expectedCountText(),
+	)
+	correction := fmt.Sprintf(`%s
+
+A previous answer contained %d items:
+%s
+
+The input is expected to yield %s items. Re-read the input, add any items that were missed or merge any that were split or duplicated, and return the complete corrected JSON array.`,
+		userPrompt, count, response, opts.expectedCountText())
+	return callLLM(ctx, systemPrompt, correction, opt)
+}
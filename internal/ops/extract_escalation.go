@@ -0,0 +1,92 @@
+// package ops - Re-extracting low-confidence fields at a higher intelligence level
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/monstercameron/schemaflow/internal/logger"
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+const fieldConfidenceInstructions = `
+- Rate your confidence in each value: return {"data": {...}, "confidence": {...}} where "data" follows the schema and "confidence" maps each top-level field name to a score from 0.0 (guessed) to 1.0 (stated plainly in the input)`
+
+// escalateUncertainFields unwraps a {"data", "confidence"} response and
+// re-extracts the fields rated below the escalation threshold at the
+// escalation intelligence level, merging their values into the data. Fields
+// without a rating are kept as they are; responses that are not an envelope
+// are returned unchanged so parsing reports the error.
+func escalateUncertainFields(ctx context.Context, typeInfo, input, response string, opts ExtractOptions, opt types.OpOptions) (string, error) {
+	var envelope struct {
+		Data       map[string]json.RawMessage `json:"data"`
+		Confidence map[string]float64         `json:"confidence"`
+	}
+	if err := json.Unmarshal([]byte(cleanJSON(response)), &envelope); err != nil || envelope.Data == nil {
+		return response, nil
+	}
+
+	var uncertain []string
+	for field, score := range envelope.Confidence {
+		if _, ok := envelope.Data[field]; ok && score < opts.EscalationThreshold {
+			uncertain = append(uncertain, field)
+		}
+	}
+	sort.Strings(uncertain)
+
+	if len(uncertain) > 0 {
+		logger.GetLogger().Info("Extract escalating uncertain fields",
+			"requestID", opt.RequestID,
+			"fields", uncertain,
+			"intelligence", opts.EscalationIntelligence.String(),
+		)
+		escalated, err := reextractFields(ctx, typeInfo, input, envelope.Data, uncertain, opts.EscalationIntelligence, opt)
+		if err != nil {
+			return response, err
+		}
+		for _, field := range uncertain {
+			if value, ok := escalated[field]; ok {
+				envelope.Data[field] = value
+			}
+		}
+	}
+
+	data, err := json.Marshal(envelope.Data)
+	if err != nil {
+		return response, err
+	}
+	return string(data), nil
+}
+
+// reextractFields asks the model, at the given intelligence level, for just
+// the named top-level fields of an earlier extraction
+func reextractFields(ctx context.Context, typeInfo, input string, previous map[string]json.RawMessage, fields []string, intelligence types.Speed, opt types.OpOptions) (map[string]json.RawMessage, error) {
+	var earlier []string
+	for _, field := range fields {
+		earlier = append(earlier, fmt.Sprintf("- %s: %s", field, previous[field]))
+	}
+
+	systemPrompt := fmt.Sprintf(`You are a data extraction expert. A first pass extracted data matching this schema but was unsure of some fields:
+%s
+
+Re-read the input and extract only these fields: %s
+Return ONLY a JSON object with exactly those fields, using null for values the input does not contain.`, typeInfo, strings.Join(fields, ", "))
+
+	userPrompt := fmt.Sprintf("Input:\n%s\n\nUncertain first-pass values:\n%s", input, strings.Join(earlier, "\n"))
+
+	escalated := opt
+	escalated.Intelligence = intelligence
+	response, err := callLLM(ctx, systemPrompt, userPrompt, escalated)
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]json.RawMessage
+	if err := ParseJSON(response, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse escalated fields: %w", err)
+	}
+	return values, nil
+}
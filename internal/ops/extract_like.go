@@ -0,0 +1,213 @@
+// package ops - Extracting into the shape of an example value
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/monstercameron/schemaflow/internal/logger"
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+// ExtractLike extracts structured data shaped like example, so no target
+// type has to be declared up front. The schema is inferred from the example's
+// JSON form, including nested objects and the element shape of non-empty
+// arrays; the example's values only illustrate the shape and are never
+// copied. The result has the example's type: a struct example yields the same
+// struct, a map example a map[string]any with exactly the example's keys
+// (missing values are nil).
+//
+// Options that depend on a declared target type (grounded extraction,
+// expected counts, field steering, field escalation and Locale) are not
+// supported.
+//
+// Example:
+//
+//	result, err := ExtractLike("Ada Lovelace, London, ada@example.com", map[string]any{
+//	    "name":    "Jane Doe",
+//	    "contact": map[string]any{"email": "jane@example.com", "city": "Paris"},
+//	}, NewExtractOptions())
+func ExtractLike(input, example any, opts ExtractOptions) (any, error) {
+	log := logger.GetLogger()
+
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+	opt := extractOpOptions(opts)
+	opt.Operation = "extract"
+
+	fail := func(targetType, reason string, cause error) (any, error) {
+		err := types.ExtractError{
+			Input:      input,
+			TargetType: targetType,
+			Reason:     reason,
+			Truncated:  errors.Is(cause, types.ErrTruncated),
+			Cause:      cause,
+			RequestID:  opt.RequestID,
+			Timestamp:  time.Now(),
+		}
+		log.Error("ExtractLike failed", "requestID", opt.RequestID, "error", err)
+		return nil, err
+	}
+
+	if example == nil {
+		return fail("", "example cannot be nil", nil)
+	}
+	exampleType := reflect.TypeOf(example)
+	if input == nil {
+		return fail(exampleType.String(), "input cannot be nil", nil)
+	}
+	if unsupported := unsupportedLikeOption(opts); unsupported != "" {
+		return fail(exampleType.String(), unsupported+" is not supported by ExtractLike", nil)
+	}
+
+	shape, err := exampleShape(example)
+	if err != nil {
+		return fail(exampleType.String(), fmt.Sprintf("failed to read example: %v", err), err)
+	}
+
+	inputStr, err := NormalizeInput(input)
+	if err != nil {
+		return fail(exampleType.String(), fmt.Sprintf("failed to normalize input: %v", err), err)
+	}
+
+	ctx := opt.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	exampleJSON, _ := json.Marshal(shape)
+	systemPrompt := BuildExtractSystemPrompt(describeShape(shape, ""), opt.Mode) + fmt.Sprintf(`
+- Return JSON with exactly the structure of this example: the same keys and nesting, and arrays holding elements shaped like the example's
+- The example's values only show the shape; never copy them, and use null for values the input does not contain
+Example: %s`, exampleJSON)
+
+	userPrompt := fmt.Sprintf("Extract structured data from this input:\n%s", inputStr)
+
+	response, err := callLLM(ctx, systemPrompt, userPrompt, opt)
+	if err != nil {
+		return fail(exampleType.String(), err.Error(), err)
+	}
+
+	var raw any
+	if err := ParseJSON(response, &raw); err != nil {
+		return fail(exampleType.String(), fmt.Sprintf("failed to parse response: %v", err), err)
+	}
+	conformed, err := json.Marshal(conformToShape(raw, shape))
+	if err != nil {
+		return fail(exampleType.String(), fmt.Sprintf("failed to conform response: %v", err), err)
+	}
+
+	result := reflect.New(exampleType)
+	if err := json.Unmarshal(conformed, result.Interface()); err != nil {
+		return fail(exampleType.String(), fmt.Sprintf("failed to parse response: %v", err), err)
+	}
+
+	log.Info("ExtractLike operation completed", "requestID", opt.RequestID, "targetType", exampleType.String())
+	return result.Elem().Interface(), nil
+}
+
+// unsupportedLikeOption names the first option set that needs a declared
+// target type, or returns ""
+func unsupportedLikeOption(opts ExtractOptions) string {
+	switch {
+	case opts.GroundedExtraction:
+		return "grounded extraction"
+	case opts.hasExpectedCount():
+		return "expected count"
+	case len(opts.FieldSteering) > 0:
+		return "field steering"
+	case opts.EscalationThreshold > 0:
+		return "field escalation"
+	case opts.Alternatives:
+		return "alternatives"
+	case opts.Locale != "":
+		return "locale"
+	}
+	return ""
+}
+
+// exampleShape decodes the JSON form of example into generic values
+func exampleShape(example any) (any, error) {
+	data, err := json.Marshal(example)
+	if err != nil {
+		return nil, err
+	}
+	var shape any
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&shape); err != nil {
+		return nil, err
+	}
+	return shape, nil
+}
+
+// describeShape renders a decoded example in the notation GenerateTypeSchema
+// uses for declared types
+func describeShape(shape any, indent string) string {
+	switch v := shape.(type) {
+	case map[string]any:
+		keys := slices.Sorted(maps.Keys(v))
+		fields := make([]string, len(keys))
+		for i, key := range keys {
+			fields[i] = fmt.Sprintf("%s  %s: %s", indent, key, describeShape(v[key], indent+"  "))
+		}
+		return fmt.Sprintf("{\n%s\n%s}", strings.Join(fields, "\n"), indent)
+	case []any:
+		if len(v) == 0 {
+			return "[]any"
+		}
+		return "[]" + describeShape(v[0], indent)
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return "any"
+}
+
+// conformToShape keeps the keys of value that the shape has, sets the missing
+// ones to nil and conforms every array element to the shape's first element
+func conformToShape(value, shape any) any {
+	switch s := shape.(type) {
+	case map[string]any:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		conformed := make(map[string]any, len(s))
+		for key, fieldShape := range s {
+			conformed[key] = conformToShape(obj[key], fieldShape)
+		}
+		return conformed
+	case []any:
+		items, ok := value.([]any)
+		if !ok {
+			return nil
+		}
+		if len(s) == 0 {
+			return items
+		}
+		conformed := make([]any, len(items))
+		for i, item := range items {
+			conformed[i] = conformToShape(item, s[0])
+		}
+		return conformed
+	}
+	if _, ok := value.(map[string]any); ok {
+		return nil
+	}
+	if _, ok := value.([]any); ok {
+		return nil
+	}
+	return value
+}
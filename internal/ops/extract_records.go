@@ -0,0 +1,236 @@
+// package ops - Multi-record extraction with per-record error isolation
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/monstercameron/schemaflow/internal/logger"
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+// RecordError reports a record that could not be extracted by ExtractRecords
+type RecordError struct {
+	// Index is the position of the record in the input (0-based)
+	Index int `json:"index"`
+
+	// Raw is the record as returned by the model
+	Raw string `json:"raw"`
+
+	// Err describes why the record was rejected
+	Err error `json:"-"`
+}
+
+// Error implements the error interface
+func (e RecordError) Error() string {
+	return fmt.Sprintf("record %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e RecordError) Unwrap() error {
+	return e.Err
+}
+
+// ExtractRecords extracts every record in multi-record input into T, decoding
+// each record independently so one malformed record does not fail the rest.
+// Records that cannot be decoded, miss required fields, or fail Strict-mode
+// validation are reported as RecordErrors with their input position.
+//
+// The error is non-nil only when the extraction as a whole fails (invalid
+// options, nil input, LLM failure, or a response with no record list).
+//
+// Example:
+//
+//	people, bad, err := ExtractRecords[Person](csvLikeText, NewExtractOptions())
+//	for _, rec := range bad {
+//	    log.Printf("skipped record %d: %v", rec.Index, rec.Err)
+//	}
+func ExtractRecords[T any](input any, opts ExtractOptions) ([]T, []RecordError, error) {
+	log := logger.GetLogger()
+
+	if err := opts.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid options: %w", err)
+	}
+	opt := extractOpOptions(opts)
+	opt.Operation = "extract"
+
+	var zero T
+	targetType := reflect.TypeOf(zero)
+	if input == nil {
+		return nil, nil, types.ExtractError{
+			Input:      input,
+			TargetType: "[]" + targetType.String(),
+			Reason:     "input cannot be nil",
+			RequestID:  opt.RequestID,
+			Timestamp:  time.Now(),
+		}
+	}
+
+	log.Info("ExtractRecords operation started",
+		"requestID", opt.RequestID,
+		"targetType", targetType.String(),
+		"mode", opt.Mode.String(),
+	)
+
+	ctx := opt.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	inputStr, err := NormalizeInput(input)
+	if err != nil {
+		return nil, nil, types.ExtractError{
+			Input:      input,
+			TargetType: "[]" + targetType.String(),
+			Reason:     fmt.Sprintf("failed to normalize input: %v", err),
+			Cause:      err,
+			RequestID:  opt.RequestID,
+			Timestamp:  time.Now(),
+		}
+	}
+
+	systemPrompt := BuildExtractSystemPrompt(GenerateTypeSchema(targetType), opt.Mode) + `
+- The input contains multiple records; the schema describes ONE record
+- Return {"records": [...]} with one entry per record, in input order
+- Extract each record independently; never merge, drop, or reorder records
+- If a record is unreadable, still include your best attempt for it`
+
+	requiredFields := explicitRequiredFields(targetType)
+	if len(requiredFields) > 0 {
+		systemPrompt += fmt.Sprintf(`
+- These fields are required and must be taken from each record: %s
+- If a required field cannot be found in a record, set it to null; never invent a value for it`, strings.Join(requiredFields, ", "))
+	}
+
+	hasTimeFields := containsTimeField(targetType)
+	if hasTimeFields {
+		systemPrompt += `
+- For datetime fields, copy the date exactly as written in the input (e.g. "15-MAR-2019") or give it as RFC3339; never guess a missing year or day`
+	}
+
+	userPrompt := fmt.Sprintf("Extract every record from this input:\n%s", inputStr)
+
+	response, err := callLLM(ctx, systemPrompt, userPrompt, opt)
+	if err != nil {
+		log.Error("ExtractRecords failed: LLM error", "requestID", opt.RequestID, "error", err)
+		return nil, nil, types.ExtractError{
+			Input:      input,
+			TargetType: "[]" + targetType.String(),
+			Reason:     err.Error(),
+			Cause:      err,
+			RequestID:  opt.RequestID,
+			Timestamp:  time.Now(),
+		}
+	}
+
+	rawRecords, err := splitRecords(response)
+	if err != nil {
+		log.Error("ExtractRecords failed: no record list in response", "requestID", opt.RequestID, "error", err)
+		return nil, nil, types.ExtractError{
+			Input:      input,
+			TargetType: "[]" + targetType.String(),
+			Reason:     fmt.Sprintf("failed to parse records: %v", err),
+			Cause:      err,
+			RequestID:  opt.RequestID,
+			Timestamp:  time.Now(),
+		}
+	}
+
+	results := make([]T, 0, len(rawRecords))
+	var recordErrors []RecordError
+	for i, raw := range rawRecords {
+		record, err := decodeRecord[T](string(raw), targetType, requiredFields, hasTimeFields, opts, opt)
+		if err != nil {
+			recordErrors = append(recordErrors, RecordError{Index: i, Raw: string(raw), Err: err})
+			continue
+		}
+		results = append(results, record)
+	}
+
+	if len(recordErrors) > 0 {
+		log.Warn("ExtractRecords skipped malformed records",
+			"requestID", opt.RequestID,
+			"extracted", len(results),
+			"failed", len(recordErrors),
+		)
+	}
+	log.Info("ExtractRecords operation completed",
+		"requestID", opt.RequestID,
+		"records", len(rawRecords),
+		"extracted", len(results),
+	)
+
+	return results, recordErrors, nil
+}
+
+// splitRecords returns the raw entries of a {"records": [...]} or bare array response
+func splitRecords(response string) ([]json.RawMessage, error) {
+	cleaned := cleanJSON(response)
+
+	var envelope struct {
+		Records []json.RawMessage `json:"records"`
+	}
+	if err := json.Unmarshal([]byte(cleaned), &envelope); err == nil && envelope.Records != nil {
+		return envelope.Records, nil
+	}
+
+	var list []json.RawMessage
+	if err := json.Unmarshal([]byte(cleaned), &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// decodeRecord decodes and validates a single record the way Extract does
+func decodeRecord[T any](raw string, targetType reflect.Type, requiredFields []string, hasTimeFields bool, opts ExtractOptions, opt types.OpOptions) (T, error) {
+	var record T
+
+	decoded := raw
+	if hasTimeFields {
+		decoded, _ = normalizeTimeFields(raw, targetType, opts.DateLayouts, opts.Timezone)
+	}
+	if err := json.Unmarshal([]byte(decoded), &record); err != nil {
+		return record, types.ExtractError{
+			Input:      raw,
+			TargetType: targetType.String(),
+			Reason:     fmt.Sprintf("failed to parse record: %v", err),
+			Cause:      err,
+			RequestID:  opt.RequestID,
+			Timestamp:  time.Now(),
+		}
+	}
+	if hasTimeFields && opts.Timezone != nil {
+		applyTimezone(reflect.ValueOf(&record), opts.Timezone)
+	}
+
+	if missing := missingRequiredFields(raw, requiredFields); len(missing) > 0 {
+		return record, types.ExtractError{
+			Input:           raw,
+			TargetType:      targetType.String(),
+			Reason:          fmt.Sprintf("missing required fields: %s", strings.Join(missing, ", ")),
+			Confidence:      1 - float64(len(missing))/float64(len(requiredFields)),
+			RequestID:       opt.RequestID,
+			Timestamp:       time.Now(),
+			MissingRequired: missing,
+		}
+	}
+
+	if opt.Mode == types.Strict {
+		if err := ValidateExtractedData(record, opt.Threshold); err != nil {
+			return record, types.ExtractError{
+				Input:      raw,
+				TargetType: targetType.String(),
+				Reason:     fmt.Sprintf("validation failed: %v", err),
+				Cause:      err,
+				RequestID:  opt.RequestID,
+				Timestamp:  time.Now(),
+			}
+		}
+	}
+
+	return record, nil
+}
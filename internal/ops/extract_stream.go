@@ -0,0 +1,340 @@
+// package ops - Streaming extraction with partially populated snapshots
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/monstercameron/schemaflow/internal/llm"
+	"github.com/monstercameron/schemaflow/internal/logger"
+	"github.com/monstercameron/schemaflow/internal/requesttracking"
+	"github.com/monstercameron/schemaflow/internal/types"
+	"github.com/monstercameron/schemaflow/pricing"
+)
+
+// ExtractSnapshot is one emission of ExtractStream
+type ExtractSnapshot[T any] struct {
+	// Value holds every top-level field parsed so far
+	Value T
+
+	// Fields lists the top-level JSON fields present in Value, sorted
+	Fields []string
+
+	// Final marks the last snapshot, which holds the complete result
+	Final bool
+
+	// Err is set on the final snapshot when the extraction failed
+	Err error
+}
+
+// ExtractStream extracts T like Extract but emits successive snapshots as the
+// model's JSON is streamed: each snapshot adds at least one newly completed
+// top-level field. The last snapshot has Final set and carries the complete
+// value, or Err if the extraction failed. The channel is closed afterwards.
+//
+// Providers implementing llm.StreamingProvider are streamed; others produce a
+// single final snapshot. Option errors are returned immediately.
+//
+// The stream lives as long as the options' context (see ExtractStreamCtx).
+// Cancelling it aborts the upstream request, ends the stream's goroutine and
+// closes the channel without a final snapshot, so a consumer that stops
+// reading early should cancel; otherwise the goroutine waits for it to read.
+//
+// Example:
+//
+//	snapshots, err := ExtractStream[Invoice](document, NewExtractOptions())
+//	for snap := range snapshots {
+//	    render(snap.Value) // fields appear as the model produces them
+//	}
+func ExtractStream[T any](input any, opts ExtractOptions) (<-chan ExtractSnapshot[T], error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+	var zero T
+	targetType := reflect.TypeOf(zero)
+	if input == nil {
+		return nil, types.ExtractError{
+			Input:      input,
+			TargetType: targetType.String(),
+			Reason:     "input cannot be nil",
+			Timestamp:  time.Now(),
+		}
+	}
+	opt := extractOpOptions(opts)
+	opt.Operation = "extract"
+
+	inputStr, err := NormalizeInput(input)
+	if err != nil {
+		return nil, types.ExtractError{
+			Input:      input,
+			TargetType: targetType.String(),
+			Reason:     fmt.Sprintf("failed to normalize input: %v", err),
+			Cause:      err,
+			RequestID:  opt.RequestID,
+			Timestamp:  time.Now(),
+		}
+	}
+
+	systemPrompt := BuildExtractSystemPrompt(GenerateTypeSchema(targetType), opt.Mode) + `
+- Return a single JSON object and emit its fields in schema order`
+	requiredFields := explicitRequiredFields(targetType)
+	if len(requiredFields) > 0 {
+		systemPrompt += fmt.Sprintf(`
+- These fields are required and must be taken from the input: %s
+- If a required field cannot be found in the input, set it to null; never invent a value for it`, strings.Join(requiredFields, ", "))
+	}
+	unitRules := unitFieldRules(targetType)
+	if len(unitRules) > 0 {
+		systemPrompt += fmt.Sprintf(`
+- For these quantity fields give the amount exactly as stated in the input as {"value": number, "unit": "unit as written"} and never convert units yourself: %s`, strings.Join(unitRules, ", "))
+	}
+	hasTimeFields := containsTimeField(targetType)
+	if hasTimeFields {
+		systemPrompt += `
+- For datetime fields, copy the date exactly as written in the input (e.g. "15-MAR-2019") or give it as RFC3339; never guess a missing year or day`
+	}
+	fieldSteering, err := fieldSteeringRule(targetType, opts.FieldSteering)
+	if err != nil {
+		return nil, types.ExtractError{
+			Input:      input,
+			TargetType: targetType.String(),
+			Reason:     err.Error(),
+			Cause:      err,
+			RequestID:  opt.RequestID,
+			Timestamp:  time.Now(),
+		}
+	}
+	systemPrompt += fieldSteering
+	userPrompt := fmt.Sprintf("Extract structured data from this input:\n%s", inputStr)
+
+	ctx := opt.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	decode := func(raw string) (T, error) {
+		var value T
+		if len(unitRules) > 0 {
+			raw, _ = normalizeUnitFields(raw, targetType)
+		}
+		if hasTimeFields {
+			raw, _ = normalizeTimeFields(raw, targetType, opts.DateLayouts, opts.Timezone)
+		}
+		if err := ParseJSON(raw, &value); err != nil {
+			return value, err
+		}
+		if hasTimeFields && opts.Timezone != nil {
+			applyTimezone(reflect.ValueOf(&value), opts.Timezone)
+		}
+		return value, nil
+	}
+
+	out := make(chan ExtractSnapshot[T])
+	go func() {
+		defer close(out)
+		log := logger.GetLogger()
+
+		// Released when the stream ends so the upstream request never outlives it
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var buffer strings.Builder
+		emitted := 0
+		response, err := streamLLM(ctx, systemPrompt, userPrompt, opt, func(delta string) {
+			if ctx.Err() != nil {
+				return
+			}
+			buffer.WriteString(delta)
+			partial, fields := closePartialObject(buffer.String())
+			if len(fields) <= emitted {
+				return
+			}
+			value, err := decode(partial)
+			if err != nil {
+				return // a field may still be incomplete; wait for more content
+			}
+			emitted = len(fields)
+			select {
+			case out <- ExtractSnapshot[T]{Value: value, Fields: fields}:
+			case <-ctx.Done():
+			}
+		})
+
+		if ctx.Err() != nil {
+			log.Debug("ExtractStream cancelled", "requestID", opt.RequestID, "error", ctx.Err())
+			return
+		}
+
+		final := ExtractSnapshot[T]{Final: true}
+		if err != nil {
+			log.Error("ExtractStream failed: LLM error", "requestID", opt.RequestID, "error", err)
+			final.Err = types.ExtractError{
+				Input:      input,
+				TargetType: targetType.String(),
+				Reason:     err.Error(),
+				Cause:      err,
+				RequestID:  opt.RequestID,
+				Timestamp:  time.Now(),
+			}
+		} else if final.Value, err = decode(response); err != nil {
+			final.Err = types.ExtractError{
+				Input:      input,
+				TargetType: targetType.String(),
+				Reason:     fmt.Sprintf("failed to parse response: %v", err),
+				Cause:      err,
+				RequestID:  opt.RequestID,
+				Timestamp:  time.Now(),
+			}
+		} else if missing := missingRequiredFields(response, requiredFields); len(missing) > 0 {
+			final.Err = types.ExtractError{
+				Input:           input,
+				TargetType:      targetType.String(),
+				Reason:          fmt.Sprintf("missing required fields: %s", strings.Join(missing, ", ")),
+				RequestID:       opt.RequestID,
+				Timestamp:       time.Now(),
+				MissingRequired: missing,
+			}
+		}
+		_, final.Fields = closePartialObject(response)
+
+		select {
+		case out <- final:
+		case <-ctx.Done():
+		}
+	}()
+
+	return out, nil
+}
+
+// streamLLM is callLLM for streaming consumers: content fragments are passed
+// to onDelta as they arrive and the full content is returned. Providers that
+// cannot stream (and test callers) deliver their content as one fragment.
+// Streams are not retried, since fragments may already have been consumed.
+func streamLLM(ctx context.Context, systemPrompt, userPrompt string, opts types.OpOptions, onDelta func(string)) (string, error) {
+	userPrompt, err := fitPromptBudget(ctx, systemPrompt, userPrompt, opts)
+	if err != nil {
+		return "", err
+	}
+	systemPrompt, userPrompt, err = guardPrompts(systemPrompt, userPrompt, opts)
+	if err != nil {
+		return "", err
+	}
+	if opts.DryRun || IsDryRun(ctx) || IsDryRun(opts.Context) {
+		return "", dryRun(ctx, systemPrompt, userPrompt, opts)
+	}
+
+	provider, streaming := providerFor(ctx).(llm.StreamingProvider)
+	if customLLMCaller != nil || !streaming {
+		response, err := sendLLM(ctx, systemPrompt, userPrompt, opts)
+		if err == nil {
+			onDelta(response)
+		}
+		return response, err
+	}
+
+	if !opts.Deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, opts.Deadline)
+		defer cancel()
+	}
+	if threshold := getSlowThreshold(); threshold > 0 {
+		defer startSlowCheck(ctx, opts, threshold)()
+	}
+
+	if err := checkJSONMode(provider, opts); err != nil {
+		return "", err
+	}
+	ctx, tracking := requesttracking.Ensure(ctx, opts.RequestID, opts.CorrelationID)
+	opts.RequestID = tracking.RequestID
+	req := buildCompletionRequest(provider.Name(), systemPrompt, userPrompt, opts)
+	req.Metadata = requestMetadata(ctx, opts)
+	req.ReasoningEffort = reasoningEffortFor(provider, opts)
+	start := time.Now()
+	resp, err := provider.CompleteStream(ctx, scrubRequest(ctx, req), onDelta)
+	if err == nil {
+		resp = restoreResponse(ctx, resp)
+		err = validateLLMCompletion(resp)
+	}
+	model := req.Model
+	if resp.Model != "" {
+		model = resp.Model
+	}
+	emitOperationLog(userPrompt, opts, provider.Name(), model, start, resp.Usage, err)
+	if err != nil {
+		return "", err
+	}
+	recordUsage(ctx, resp.Usage, pricing.CalculateCost(&resp.Usage, model, provider.Name()).TotalCost)
+	recordResultMeta(ctx, resp, model)
+	return resp.Content, nil
+}
+
+// closePartialObject returns the prefix of a streamed JSON object made of its
+// complete top-level members, closed with "}", and the sorted names of those
+// members. Content that is not (yet) an object yields no fields.
+func closePartialObject(buffer string) (string, []string) {
+	cleaned := strings.TrimSpace(buffer)
+	cleaned = strings.TrimPrefix(cleaned, "```json")
+	cleaned = strings.TrimPrefix(cleaned, "```")
+	start := strings.IndexByte(cleaned, '{')
+	if start < 0 {
+		return "", nil
+	}
+	cleaned = cleaned[start:]
+
+	cut := -1
+	depth := 0
+	inString, escaped := false, false
+scan:
+	for i := 0; i < len(cleaned); i++ {
+		c := cleaned[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth == 0 {
+				cut = i
+				break scan
+			}
+		case ',':
+			if depth == 1 {
+				cut = i
+			}
+		}
+	}
+	if cut < 0 {
+		return "", nil
+	}
+
+	partial := cleaned[:cut] + "}"
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(partial), &members); err != nil {
+		return "", nil
+	}
+	fields := make([]string, 0, len(members))
+	for name, raw := range members {
+		if string(raw) != "null" {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return partial, fields
+}
@@ -0,0 +1,110 @@
+// package ops - Regular expression formats for extracted string fields
+package ops
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+)
+
+// fieldPattern is a `pattern` tag found on a field of the target type
+type fieldPattern struct {
+	path string
+	re   *regexp.Regexp
+}
+
+// fieldPatterns compiles the `pattern:"..."` tags on string fields of t,
+// keyed by the path used in prompts (slice elements are not indexed)
+func fieldPatterns(t reflect.Type) ([]fieldPattern, error) {
+	var patterns []fieldPattern
+	var firstErr error
+	var walk func(t reflect.Type, path string, seen map[reflect.Type]bool)
+	walk = func(t reflect.Type, path string, seen map[reflect.Type]bool) {
+		for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct || t == timeType || seen[t] {
+			return
+		}
+		seen[t] = true
+		defer delete(seen, t)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() || field.Tag.Get("json") == "-" {
+				continue
+			}
+			name := joinPath(path, jsonFieldName(field))
+			if expr, ok := field.Tag.Lookup("pattern"); ok {
+				re, err := regexp.Compile(expr)
+				if err != nil && firstErr == nil {
+					firstErr = fmt.Errorf("field %s: invalid pattern %q: %w", name, expr, err)
+				}
+				if err == nil {
+					patterns = append(patterns, fieldPattern{path: name, re: re})
+				}
+				continue
+			}
+			walk(field.Type, name, seen)
+		}
+	}
+	walk(t, "", map[reflect.Type]bool{})
+	return patterns, firstErr
+}
+
+// fieldPatternRules describes the tagged formats for the system prompt
+func fieldPatternRules(t reflect.Type) []string {
+	patterns, _ := fieldPatterns(t)
+	rules := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		rules[i] = fmt.Sprintf("%s (%s)", pattern.path, pattern.re)
+	}
+	return rules
+}
+
+// checkFieldPatterns lists the non-empty string fields of value tagged
+// `pattern` whose value does not match it, with the offending value
+func checkFieldPatterns(value any) []string {
+	var violations []string
+	walkTaggedFields(reflect.ValueOf(value), "", "pattern", func(path, expr string, field reflect.Value) {
+		for field.Kind() == reflect.Ptr && !field.IsNil() {
+			field = field.Elem()
+		}
+		if field.Kind() != reflect.String || field.Len() == 0 {
+			return
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil || re.MatchString(field.String()) {
+			return
+		}
+		violations = append(violations, fmt.Sprintf("%s: %q does not match pattern %s", path, field.String(), expr))
+	})
+	return violations
+}
+
+// patternRepairs lists the pattern-tagged fields whose values differ between
+// the original and the repaired extraction
+func patternRepairs(original, repaired any) []string {
+	before := map[string]any{}
+	walkTaggedFields(reflect.ValueOf(original), "", "pattern", func(path, _ string, field reflect.Value) {
+		before[path] = patternFieldValue(field)
+	})
+	var repairs []string
+	walkTaggedFields(reflect.ValueOf(repaired), "", "pattern", func(path, _ string, field reflect.Value) {
+		updated := patternFieldValue(field)
+		if old, ok := before[path]; !ok || !reflect.DeepEqual(old, updated) {
+			repairs = append(repairs, fmt.Sprintf("%s: %v -> %v", path, old, updated))
+		}
+	})
+	return repairs
+}
+
+// patternFieldValue is the value of a pattern-tagged field, dereferenced
+func patternFieldValue(field reflect.Value) any {
+	for field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return nil
+		}
+		field = field.Elem()
+	}
+	return field.Interface()
+}
@@ -0,0 +1,54 @@
+// package ops - Steering instructions scoped to nested fields by JSON path
+package ops
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// fieldSteeringRule renders WithFieldSteering instructions as a system prompt
+// rule, one line per path in path order. Every path must name a field of t;
+// array and map values are traversed implicitly, so "items.sku" addresses the
+// sku of every item.
+func fieldSteeringRule(t reflect.Type, steering map[string]string) (string, error) {
+	if len(steering) == 0 {
+		return "", nil
+	}
+	var lines []string
+	for _, path := range slices.Sorted(maps.Keys(steering)) {
+		if err := checkFieldPath(t, path); err != nil {
+			return "", fmt.Errorf("field steering: %w", err)
+		}
+		lines = append(lines, fmt.Sprintf("\n  - %s: %s", path, strings.TrimSpace(steering[path])))
+	}
+	return "\n- Field instructions, each applying only to the field at that JSON path (for every element of arrays along the path):" + strings.Join(lines, ""), nil
+}
+
+// checkFieldPath reports an error unless a dotted JSON path names a field of t
+func checkFieldPath(t reflect.Type, path string) error {
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct || t == timeType {
+			return fmt.Errorf("field path %q: %q has no fields", path, strings.Join(segments[:i], "."))
+		}
+		var next reflect.Type
+		for j := 0; j < t.NumField(); j++ {
+			field := t.Field(j)
+			if field.IsExported() && field.Tag.Get("json") != "-" && jsonFieldName(field) == segment {
+				next = field.Type
+				break
+			}
+		}
+		if next == nil {
+			return fmt.Errorf("field path %q: no field %q in %s", path, segment, t)
+		}
+		t = next
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+// package ops - Generating related records with referential integrity
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/monstercameron/schemaflow/internal/logger"
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+// RelatedEntity is one kind of record generated by GenerateRelated; create
+// it with Entity
+type RelatedEntity struct {
+	// Name keys the records in the model's response, e.g. "users"
+	Name string
+
+	// Count is the number of records to generate (0 lets the model choose)
+	Count int
+
+	target reflect.Value // *[]T receiving the records
+}
+
+// Entity declares count records of type T, called name, that GenerateRelated
+// generates into *into
+func Entity[T any](name string, count int, into *[]T) RelatedEntity {
+	return RelatedEntity{Name: name, Count: count, target: reflect.ValueOf(into)}
+}
+
+// Reference declares that every Child record's ForeignKey field holds the Key
+// field of one Parent record. Entities are named as in Entity and fields by
+// their JSON names, e.g. {Child: "orders", ForeignKey: "user_id", Parent: "users", Key: "id"}.
+type Reference struct {
+	Child      string
+	ForeignKey string
+	Parent     string
+	Key        string
+}
+
+// GenerateRelated generates several related kinds of records in one call,
+// keeping foreign keys consistent: every Reference's child records reference
+// a generated parent and parent keys are unique. Dangling or duplicate keys
+// are sent back to the model once for correction; if any remain,
+// GenerateRelated returns a GenerateError and leaves the targets unchanged.
+//
+// Example:
+//
+//	var users []User
+//	var orders []Order
+//	err := GenerateRelated("Customers of an online bookshop and their orders",
+//	    []RelatedEntity{Entity("users", 3, &users), Entity("orders", 8, &orders)},
+//	    []Reference{{Child: "orders", ForeignKey: "user_id", Parent: "users", Key: "id"}},
+//	    NewGenerateOptions())
+func GenerateRelated(prompt string, entities []RelatedEntity, references []Reference, opts GenerateOptions) error {
+	log := logger.GetLogger()
+
+	if err := opts.Validate(); err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+	opt := opts.toOpOptions()
+	opt.Operation = "generate"
+
+	names := make([]string, len(entities))
+	for i, entity := range entities {
+		names[i] = entity.Name
+	}
+	generateErr := func(reason string, cause error) error {
+		return types.GenerateError{
+			Prompt:     prompt,
+			TargetType: strings.Join(names, ", "),
+			Reason:     reason,
+			Cause:      cause,
+			RequestID:  opt.RequestID,
+			Timestamp:  time.Now(),
+		}
+	}
+
+	if strings.TrimSpace(prompt) == "" {
+		return generateErr("prompt cannot be empty", nil)
+	}
+	if err := checkRelatedEntities(entities, references); err != nil {
+		return generateErr(err.Error(), err)
+	}
+
+	log.Info("GenerateRelated operation started", "requestID", opt.RequestID, "entities", names)
+
+	var schemas, rules []string
+	for _, entity := range entities {
+		count := "as many records as the prompt calls for"
+		if entity.Count > 0 {
+			count = fmt.Sprintf("exactly %d records", entity.Count)
+		}
+		schemas = append(schemas, fmt.Sprintf("%q (%s), each:\n%s", entity.Name, count, GenerateTypeSchema(entity.target.Type().Elem().Elem())))
+	}
+	for _, reference := range references {
+		rules = append(rules,
+			fmt.Sprintf("- %s.%s values must be unique", reference.Parent, reference.Key),
+			fmt.Sprintf("- Every %s.%s must equal the %s of one of the generated %s", reference.Child, reference.ForeignKey, reference.Key, reference.Parent))
+	}
+
+	systemPrompt := fmt.Sprintf(`You are a data generation expert. Generate sets of related records based on the prompt.
+
+Return ONLY a JSON object with one array per record kind:
+%s
+
+Relationship rules:
+%s
+
+Generation rules:
+- Generate realistic and coherent data
+- Follow the prompt requirements precisely
+- Return ONLY valid JSON, no explanations`, strings.Join(schemas, "\n\n"), strings.Join(rules, "\n"))
+
+	ctx := opt.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	response, err := callLLM(ctx, systemPrompt, prompt, opt)
+	if err != nil {
+		return generateErr(err.Error(), err)
+	}
+	records, violations, err := checkRelatedResponse(response, entities, references)
+	if err == nil && len(violations) > 0 {
+		log.Warn("GenerateRelated output breaks relationships, re-prompting", "requestID", opt.RequestID, "violations", len(violations))
+		correction := fmt.Sprintf(`%s
+
+A previous answer was:
+%s
+
+It breaks these relationship rules:
+- %s
+
+Fix the keys so every rule holds and return the complete corrected JSON object.`, prompt, response, strings.Join(violations, "\n- "))
+		if response, err = callLLM(ctx, systemPrompt, correction, opt); err != nil {
+			return generateErr(err.Error(), err)
+		}
+		records, violations, err = checkRelatedResponse(response, entities, references)
+	}
+	if err != nil {
+		return generateErr(fmt.Sprintf("failed to parse response: %v", err), err)
+	}
+	if len(violations) > 0 {
+		return generateErr("broken relationships: "+strings.Join(violations, "; "), nil)
+	}
+
+	decoded := make([]reflect.Value, len(entities))
+	for i, entity := range entities {
+		decoded[i] = reflect.New(entity.target.Type().Elem())
+		if err := json.Unmarshal(records[entity.Name], decoded[i].Interface()); err != nil {
+			return generateErr(fmt.Sprintf("failed to decode %s: %v", entity.Name, err), err)
+		}
+	}
+	for i, entity := range entities {
+		entity.target.Elem().Set(decoded[i].Elem())
+	}
+
+	log.Info("GenerateRelated operation completed", "requestID", opt.RequestID)
+	return nil
+}
+
+// checkRelatedEntities reports entities without a target or with a repeated
+// name, and references naming an unknown entity or field
+func checkRelatedEntities(entities []RelatedEntity, references []Reference) error {
+	if len(entities) == 0 {
+		return fmt.Errorf("at least one entity is required")
+	}
+	entityTypes := make(map[string]reflect.Type, len(entities))
+	for _, entity := range entities {
+		if !entity.target.IsValid() || entity.target.IsNil() {
+			return fmt.Errorf("entity %q has no target; create it with Entity", entity.Name)
+		}
+		if _, ok := entityTypes[entity.Name]; ok || entity.Name == "" {
+			return fmt.Errorf("entity names must be unique and non-empty, got %q", entity.Name)
+		}
+		entityTypes[entity.Name] = entity.target.Type().Elem().Elem()
+	}
+
+	hasField := func(entity, field string) error {
+		t, ok := entityTypes[entity]
+		if !ok {
+			return fmt.Errorf("reference names unknown entity %q", entity)
+		}
+		if t = structType(t); t != nil {
+			for i := 0; i < t.NumField(); i++ {
+				if t.Field(i).IsExported() && jsonFieldName(t.Field(i)) == field {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("reference names unknown field %q of %q", field, entity)
+	}
+	for _, reference := range references {
+		if err := hasField(reference.Parent, reference.Key); err != nil {
+			return err
+		}
+		if err := hasField(reference.Child, reference.ForeignKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRelatedResponse splits a response into each entity's records and
+// lists the relationship rules they break
+func checkRelatedResponse(response string, entities []RelatedEntity, references []Reference) (map[string]json.RawMessage, []string, error) {
+	var records map[string]json.RawMessage
+	if err := ParseJSON(response, &records); err != nil {
+		return nil, nil, err
+	}
+
+	rows := make(map[string][]map[string]any, len(entities))
+	for _, entity := range entities {
+		raw, ok := records[entity.Name]
+		if !ok {
+			return nil, nil, fmt.Errorf("response has no %q records", entity.Name)
+		}
+		var decoded []map[string]any
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", entity.Name, err)
+		}
+		rows[entity.Name] = decoded
+	}
+
+	var violations []string
+	for _, reference := range references {
+		keys := make(map[string]bool)
+		for i, parent := range rows[reference.Parent] {
+			key := fmt.Sprint(parent[reference.Key])
+			if keys[key] {
+				violations = append(violations, fmt.Sprintf("%s[%d].%s %s is a duplicate", reference.Parent, i, reference.Key, key))
+			}
+			keys[key] = true
+		}
+		for i, child := range rows[reference.Child] {
+			if ref := fmt.Sprint(child[reference.ForeignKey]); !keys[ref] {
+				violations = append(violations, fmt.Sprintf("%s[%d].%s %s matches no %s.%s", reference.Child, i, reference.ForeignKey, ref, reference.Parent, reference.Key))
+			}
+		}
+	}
+	return records, violations, nil
+}
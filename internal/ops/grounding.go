@@ -0,0 +1,121 @@
+// package ops - Source grounding for extracted fields
+package ops
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// FieldGrounding records where an extracted field came from in the input
+type FieldGrounding struct {
+	// Source is the input substring the model cited for the value
+	Source string `json:"source,omitempty"`
+
+	// Grounded is true when Source was found in the input
+	Grounded bool `json:"grounded"`
+}
+
+// GroundedResult is an extracted value with the source of each field
+type GroundedResult[T any] struct {
+	// Value is the extracted data; ungrounded fields are left empty
+	Value T `json:"value"`
+
+	// Fields maps top-level JSON field names to their grounding
+	Fields map[string]FieldGrounding `json:"fields"`
+
+	// Ungrounded lists fields whose value could not be traced to the input
+	Ungrounded []string `json:"ungrounded,omitempty"`
+
+	// PIIFields lists the fields of Value tagged `pii:"true"` that hold a
+	// value (see PIIFields)
+	PIIFields []string `json:"pii_fields,omitempty"`
+}
+
+const groundingInstructions = `
+- Ground every value in the input: return {"data": {...}, "sources": {...}} where "data" follows the schema and "sources" maps each top-level field name to the exact substring of the input the value was taken from
+- Copy sources verbatim from the input; do not paraphrase
+- If a field has no supporting text in the input, set it to null in "data" and omit it from "sources"`
+
+// ExtractGrounded extracts T like Extract with grounded extraction enabled and
+// reports, per top-level field, the input substring the value was drawn from.
+// Fields whose cited source does not appear in the input are left empty and
+// listed in Ungrounded.
+//
+// Example:
+//
+//	result, err := ExtractGrounded[Invoice](document, NewExtractOptions())
+//	for _, field := range result.Ungrounded {
+//	    log.Printf("%s could not be traced to the document", field)
+//	}
+//	fmt.Println(result.Fields["total"].Source) // e.g. "Total due: $1,250.00"
+func ExtractGrounded[T any](input any, opts ExtractOptions) (GroundedResult[T], error) {
+	opts.GroundedExtraction = true
+
+	var report extractReport
+	consistent, err := extractConsistent[T](input, opts, &report)
+	value := consistent.Value
+	if report.grounding == nil {
+		report.grounding = map[string]FieldGrounding{}
+	}
+	return GroundedResult[T]{
+		Value:      value,
+		Fields:     report.grounding,
+		Ungrounded: ungroundedFields(report.grounding),
+		PIIFields:  PIIFields(value),
+	}, err
+}
+
+// groundResponse verifies the cited source of each field in a grounded
+// {"data", "sources"} response against the input. It returns the data object
+// with ungrounded values set to null, plus the per-field report. Responses that
+// cannot be decoded are returned unchanged so parsing reports the error.
+func groundResponse(response, input string) (string, map[string]FieldGrounding) {
+	var envelope struct {
+		Data    map[string]any    `json:"data"`
+		Sources map[string]string `json:"sources"`
+	}
+	decoder := json.NewDecoder(strings.NewReader(cleanJSON(response)))
+	decoder.UseNumber() // keep large integers exact when re-encoding
+	if err := decoder.Decode(&envelope); err != nil || envelope.Data == nil {
+		return response, map[string]FieldGrounding{}
+	}
+
+	normalizedInput := normalizeForGrounding(input)
+	report := make(map[string]FieldGrounding, len(envelope.Data))
+	for field, value := range envelope.Data {
+		if value == nil {
+			continue
+		}
+		source := strings.TrimSpace(envelope.Sources[field])
+		grounded := source != "" && strings.Contains(normalizedInput, normalizeForGrounding(source))
+		report[field] = FieldGrounding{Source: source, Grounded: grounded}
+		if !grounded {
+			envelope.Data[field] = nil
+		}
+	}
+
+	data, err := json.Marshal(envelope.Data)
+	if err != nil {
+		return response, report
+	}
+	return string(data), report
+}
+
+// normalizeForGrounding lowercases and collapses whitespace so sources match
+// across line breaks and spacing differences
+func normalizeForGrounding(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// ungroundedFields returns the sorted names of fields that failed grounding
+func ungroundedFields(report map[string]FieldGrounding) []string {
+	var fields []string
+	for field, g := range report {
+		if !g.Grounded {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
@@ -78,8 +78,13 @@ func inferImpl[T any](partialData T, opts InferOptions) (T, error) {
 	}
 
 	opt := opts.toOpOptions()
+	opt.Operation = "infer"
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.GetTimeout())
+	ctx := opt.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, config.GetTimeout())
 	defer cancel()
 
 	// Get type information
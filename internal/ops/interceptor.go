@@ -0,0 +1,73 @@
+// package ops - Interceptor chain around provider completions
+package ops
+
+import (
+	"context"
+	"sync"
+
+	"github.com/monstercameron/schemaflow/internal/llm"
+)
+
+// registeredInterceptor pairs an interceptor with the ID its remove
+// function looks for, since functions cannot be compared
+type registeredInterceptor struct {
+	id          uint64
+	interceptor llm.Interceptor
+}
+
+var (
+	interceptorsMu    sync.RWMutex
+	interceptors      []registeredInterceptor
+	nextInterceptorID uint64
+)
+
+// AddInterceptor appends interceptor to the process-wide chain wrapping every
+// provider completion and returns a function that removes it again.
+// Interceptors run in the order they were added, the first one outermost,
+// and wrap each attempt so retries pass through them again. A client scope's
+// interceptors run inside this chain. Streaming calls are not intercepted.
+func AddInterceptor(interceptor llm.Interceptor) (remove func()) {
+	interceptorsMu.Lock()
+	defer interceptorsMu.Unlock()
+	nextInterceptorID++
+	id := nextInterceptorID
+	interceptors = append(interceptors, registeredInterceptor{id: id, interceptor: interceptor})
+	return func() {
+		interceptorsMu.Lock()
+		defer interceptorsMu.Unlock()
+		for i, registered := range interceptors {
+			if registered.id == id {
+				interceptors = append(interceptors[:i:i], interceptors[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// SetInterceptors replaces the process-wide interceptor chain; with no
+// arguments it removes every interceptor
+func SetInterceptors(chain ...llm.Interceptor) {
+	interceptorsMu.Lock()
+	defer interceptorsMu.Unlock()
+	interceptors = nil
+	for _, interceptor := range chain {
+		nextInterceptorID++
+		interceptors = append(interceptors, registeredInterceptor{id: nextInterceptorID, interceptor: interceptor})
+	}
+}
+
+// interceptedComplete returns provider.Complete wrapped in the process-wide
+// chain and then the chain of ctx's scope, with the outbound scrubbers and
+// inbound restorers innermost
+func interceptedComplete(ctx context.Context, provider llm.Provider) llm.CompletionFunc {
+	interceptorsMu.RLock()
+	chain := make([]llm.Interceptor, 0, len(interceptors))
+	for _, registered := range interceptors {
+		chain = append(chain, registered.interceptor)
+	}
+	interceptorsMu.RUnlock()
+	if scope := scopeFrom(ctx); scope != nil {
+		chain = append(chain, scope.Interceptors...)
+	}
+	return llm.ChainInterceptors(scrubbedComplete(provider.Complete), chain...)
+}
@@ -30,6 +30,13 @@ type InterpolateOptions struct {
 	// Constraints are rules that interpolated values must satisfy
 	Constraints []string
 
+	// Rules are structured constraints checked deterministically after the
+	// gaps are filled. Violations trigger a corrective re-prompt.
+	Rules []SequenceConstraint
+
+	// MaxRepairs limits corrective re-prompts when Rules are violated (default 2)
+	MaxRepairs int
+
 	// Common options
 	Steering      string
 	Mode          types.Mode
@@ -74,6 +81,9 @@ type InterpolateResult[T any] struct {
 	// AverageConfidence across all interpolated values
 	AverageConfidence float64 `json:"average_confidence"`
 
+	// Violations lists Rules still broken after all repair attempts
+	Violations []string `json:"violations,omitempty"`
+
 	// Metadata contains additional operation information
 	Metadata map[string]any `json:"metadata,omitempty"`
 }
@@ -124,6 +134,15 @@ type InterpolateResult[T any] struct {
 //	})
 //	fmt.Printf("Filled %d gaps with %.0f%% avg confidence\n",
 //	    result.GapCount, result.AverageConfidence*100)
+//
+//	// Example 4: Enforce structured constraints after filling
+//	result, err := Interpolate(prices, InterpolateOptions{
+//	    Rules: []SequenceConstraint{
+//	        SequenceMonotonic("close", "non_decreasing"),
+//	        SequenceRange("volume", 0, 1e9),
+//	        SequenceRelation("high>=low"),
+//	    },
+//	})
 func Interpolate[T any](items []T, opts ...InterpolateOptions) (InterpolateResult[T], error) {
 	log := logger.GetLogger()
 	log.Debug("Starting interpolate operation", "itemCount", len(items))
@@ -142,15 +161,16 @@ func Interpolate[T any](items []T, opts ...InterpolateOptions) (InterpolateResul
 	}
 
 	// Apply defaults
-	opt := InterpolateOptions{
-		Method:        "auto",
-		ContextWindow: 3,
-		Mode:          types.TransformMode,
-		Intelligence:  types.Fast,
-	}
+	opt := defaultInterpolateOptions()
 	if len(opts) > 0 {
 		opt = mergeInterpolateOptions(opt, opts[0])
 	}
+	for i, rule := range opt.Rules {
+		if err := rule.Validate(); err != nil {
+			log.Error("Interpolate operation validation failed", "rule", i, "error", err)
+			return result, fmt.Errorf("invalid rule %d: %w", i, err)
+		}
+	}
 
 	result.Method = opt.Method
 
@@ -185,8 +205,12 @@ func Interpolate[T any](items []T, opts ...InterpolateOptions) (InterpolateResul
 
 	// Build constraints description
 	constraintsDesc := ""
-	if len(opt.Constraints) > 0 {
-		constraintsDesc = fmt.Sprintf("\n\nConstraints:\n- %s", strings.Join(opt.Constraints, "\n- "))
+	allConstraints := append([]string(nil), opt.Constraints...)
+	for _, rule := range opt.Rules {
+		allConstraints = append(allConstraints, rule.String())
+	}
+	if len(allConstraints) > 0 {
+		constraintsDesc = fmt.Sprintf("\n\nConstraints:\n- %s", strings.Join(allConstraints, "\n- "))
 	}
 
 	sequenceFieldNote := ""
@@ -247,49 +271,43 @@ Rules:
 		Context:       ctx,
 		RequestID:     opt.RequestID,
 		CorrelationID: opt.CorrelationID,
+		Operation:     "interpolate",
 	}
 
-	response, err := callLLM(ctx, systemPrompt, userPrompt, opOpts)
+	parsed, err := requestInterpolation[T](ctx, systemPrompt, userPrompt, opOpts)
 	if err != nil {
-		log.Error("Interpolate operation LLM call failed", "error", err)
-		return result, fmt.Errorf("interpolation failed: %w", err)
+		return result, err
 	}
 
-	// Clean up response
-	response = strings.TrimSpace(response)
-	if strings.HasPrefix(response, "```json") {
-		response = strings.TrimPrefix(response, "```json")
-		response = strings.TrimSuffix(response, "```")
-		response = strings.TrimSpace(response)
-	} else if strings.HasPrefix(response, "```") {
-		response = strings.TrimPrefix(response, "```")
-		response = strings.TrimSuffix(response, "```")
-		response = strings.TrimSpace(response)
+	violations, err := checkSequenceConstraints(parsed.complete, opt.Rules)
+	if err != nil {
+		return result, err
 	}
+	for attempt := 1; len(violations) > 0 && attempt <= opt.MaxRepairs; attempt++ {
+		log.Debug("Interpolate result violates constraints, re-prompting", "attempt", attempt, "violations", violations)
 
-	// Parse response
-	var parsed struct {
-		Complete          []json.RawMessage `json:"complete"`
-		Filled            []FilledItem      `json:"filled"`
-		GapCount          int               `json:"gap_count"`
-		Method            string            `json:"method"`
-		AverageConfidence float64           `json:"average_confidence"`
-	}
+		previousJSON, _ := json.Marshal(parsed.complete)
+		repairPrompt := fmt.Sprintf(`%s
 
-	if err := json.Unmarshal([]byte(response), &parsed); err != nil {
-		log.Error("Interpolate operation failed: parse error", "error", err, "response", response)
-		return result, fmt.Errorf("failed to parse interpolation result: %w", err)
-	}
+Your previous answer violated these constraints:
+- %s
 
-	// Parse complete sequence
-	result.Complete = make([]T, len(parsed.Complete))
-	for i, itemJSON := range parsed.Complete {
-		if err := json.Unmarshal(itemJSON, &result.Complete[i]); err != nil {
-			log.Error("Interpolate operation failed: item parse error", "index", i, "error", err)
-			return result, fmt.Errorf("failed to parse item %d: %w", i, err)
+Previous sequence:
+%s
+
+Return the full corrected JSON object. Only change filled gap values; keep original values unchanged.`,
+			userPrompt, strings.Join(violations, "\n- "), string(previousJSON))
+
+		parsed, err = requestInterpolation[T](ctx, systemPrompt, repairPrompt, opOpts)
+		if err != nil {
+			return result, err
+		}
+		if violations, err = checkSequenceConstraints(parsed.complete, opt.Rules); err != nil {
+			return result, err
 		}
 	}
 
+	result.Complete = parsed.complete
 	result.Filled = parsed.Filled
 	result.GapCount = parsed.GapCount
 	if parsed.Method != "" {
@@ -297,6 +315,12 @@ Rules:
 	}
 	result.AverageConfidence = parsed.AverageConfidence
 
+	if len(violations) > 0 {
+		result.Violations = violations
+		log.Error("Interpolate operation failed: constraints still violated", "violations", violations)
+		return result, fmt.Errorf("interpolated sequence violates constraints after %d repair attempts: %s", opt.MaxRepairs, strings.Join(violations, "; "))
+	}
+
 	log.Debug("Interpolate operation succeeded",
 		"gapCount", result.GapCount,
 		"method", result.Method,
@@ -305,6 +329,17 @@ Rules:
 	return result, nil
 }
 
+// defaultInterpolateOptions returns the options Interpolate runs with when none are given
+func defaultInterpolateOptions() InterpolateOptions {
+	return InterpolateOptions{
+		Method:        "auto",
+		ContextWindow: 3,
+		MaxRepairs:    2,
+		Mode:          types.TransformMode,
+		Intelligence:  types.Fast,
+	}
+}
+
 // mergeInterpolateOptions merges user options with defaults
 func mergeInterpolateOptions(defaults, user InterpolateOptions) InterpolateOptions {
 	if user.Method != "" {
@@ -322,6 +357,12 @@ func mergeInterpolateOptions(defaults, user InterpolateOptions) InterpolateOptio
 	if user.Constraints != nil {
 		defaults.Constraints = user.Constraints
 	}
+	if user.Rules != nil {
+		defaults.Rules = user.Rules
+	}
+	if user.MaxRepairs > 0 {
+		defaults.MaxRepairs = user.MaxRepairs
+	}
 	if user.Steering != "" {
 		defaults.Steering = user.Steering
 	}
@@ -336,3 +377,40 @@ func mergeInterpolateOptions(defaults, user InterpolateOptions) InterpolateOptio
 	}
 	return defaults
 }
+
+// interpolationResponse is the raw LLM answer plus its decoded sequence
+type interpolationResponse[T any] struct {
+	Complete          []json.RawMessage `json:"complete"`
+	Filled            []FilledItem      `json:"filled"`
+	GapCount          int               `json:"gap_count"`
+	Method            string            `json:"method"`
+	AverageConfidence float64           `json:"average_confidence"`
+
+	complete []T
+}
+
+// requestInterpolation calls the LLM and decodes the interpolated sequence
+func requestInterpolation[T any](ctx context.Context, systemPrompt, userPrompt string, opOpts types.OpOptions) (interpolationResponse[T], error) {
+	log := logger.GetLogger()
+	var parsed interpolationResponse[T]
+
+	response, err := callLLM(ctx, systemPrompt, userPrompt, opOpts)
+	if err != nil {
+		log.Error("Interpolate operation LLM call failed", "error", err)
+		return parsed, fmt.Errorf("interpolation failed: %w", err)
+	}
+
+	if err := ParseJSON(response, &parsed); err != nil {
+		log.Error("Interpolate operation failed: parse error", "error", err, "response", response)
+		return parsed, fmt.Errorf("failed to parse interpolation result: %w", err)
+	}
+
+	parsed.complete = make([]T, len(parsed.Complete))
+	for i, itemJSON := range parsed.Complete {
+		if err := json.Unmarshal(itemJSON, &parsed.complete[i]); err != nil {
+			log.Error("Interpolate operation failed: item parse error", "index", i, "error", err)
+			return parsed, fmt.Errorf("failed to parse item %d: %w", i, err)
+		}
+	}
+	return parsed, nil
+}
@@ -0,0 +1,122 @@
+package ops
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+type surveyResponse struct {
+	Respondent int `json:"respondent"`
+	Score      int `json:"score"`
+}
+
+func TestInterpolateRepairsOutOfRangeFill(t *testing.T) {
+	defer setupMockClient()
+
+	responses := []surveyResponse{{1, 4}, {2, 0}, {3, 5}}
+
+	calls := 0
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		calls++
+		if !strings.Contains(system, `"score" must be between 1 and 5`) {
+			t.Errorf("prompt missing range constraint: %q", system)
+		}
+		if strings.Contains(user, "violated these constraints") {
+			if !strings.Contains(user, `item 1: "score" = 7 is outside [1, 5]`) {
+				t.Errorf("repair prompt missing violation detail: %q", user)
+			}
+			return `{"complete": [{"respondent": 1, "score": 4}, {"respondent": 2, "score": 5}, {"respondent": 3, "score": 5}],
+				"filled": [{"index": 1, "method": "semantic", "confidence": 0.7}], "gap_count": 1}`, nil
+		}
+		return `{"complete": [{"respondent": 1, "score": 4}, {"respondent": 2, "score": 7}, {"respondent": 3, "score": 5}],
+			"filled": [{"index": 1, "method": "trend", "confidence": 0.6}], "gap_count": 1}`, nil
+	})
+
+	result, err := Interpolate(responses, InterpolateOptions{
+		GapIndices: []int{1},
+		Rules:      []SequenceConstraint{SequenceRange("score", 1, 5)},
+	})
+	if err != nil {
+		t.Fatalf("Interpolate() error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("LLM called %d times, want 2 (fill + repair)", calls)
+	}
+	if got := result.Complete[1].Score; got != 5 {
+		t.Errorf("repaired score = %d, want 5", got)
+	}
+	if len(result.Violations) != 0 {
+		t.Errorf("Violations = %v, want none", result.Violations)
+	}
+}
+
+func TestInterpolateReportsUnrepairedViolations(t *testing.T) {
+	defer setupMockClient()
+
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		return `{"complete": [{"respondent": 1, "score": 4}, {"respondent": 2, "score": 9}]}`, nil
+	})
+
+	result, err := Interpolate([]surveyResponse{{1, 4}, {2, 0}}, InterpolateOptions{
+		Rules:      []SequenceConstraint{SequenceRange("score", 1, 5)},
+		MaxRepairs: 1,
+	})
+	if err == nil {
+		t.Fatal("expected error when constraint cannot be satisfied")
+	}
+	if len(result.Violations) != 1 {
+		t.Errorf("Violations = %v, want one", result.Violations)
+	}
+}
+
+func TestCheckSequenceConstraints(t *testing.T) {
+	type bar struct {
+		Open  float64 `json:"open"`
+		High  float64 `json:"high"`
+		Close float64 `json:"close"`
+	}
+	bars := []bar{{10, 12, 11}, {11, 10.5, 12}, {12, 13, 11.5}}
+
+	violations, err := checkSequenceConstraints(bars, []SequenceConstraint{
+		SequenceMonotonic("close", "increasing"),
+		SequenceRelation("high>=open"),
+	})
+	if err != nil {
+		t.Fatalf("checkSequenceConstraints() error = %v", err)
+	}
+	if len(violations) != 2 {
+		t.Fatalf("violations = %v, want 2 (close drop at item 2, high<open at item 1)", violations)
+	}
+	if !strings.HasPrefix(violations[0], "item 2") || !strings.HasPrefix(violations[1], "item 1") {
+		t.Errorf("unexpected violations: %v", violations)
+	}
+
+	if _, err := checkSequenceConstraints(bars, []SequenceConstraint{SequenceRelation("high")}); err == nil {
+		t.Error("expected error for malformed relation")
+	}
+}
+
+func TestInterpolateRejectsInvalidRules(t *testing.T) {
+	defer setupMockClient()
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		t.Fatal("provider called with an invalid rule")
+		return "", nil
+	})
+
+	type point struct {
+		Close float64 `json:"close"`
+	}
+	for _, rule := range []SequenceConstraint{
+		SequenceMonotonic("close", "upward"),
+		SequenceRange("close", 5, 1),
+		SequenceRelation("close"),
+	} {
+		_, err := Interpolate([]point{{1}, {2}}, InterpolateOptions{Rules: []SequenceConstraint{rule}})
+		if err == nil {
+			t.Errorf("Interpolate() with %+v: expected validation error", rule)
+		}
+	}
+}
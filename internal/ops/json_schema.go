@@ -0,0 +1,245 @@
+// package ops - JSON Schema constraints for generated data
+package ops
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// JSONSchema is the subset of JSON Schema that Generate enforces: types,
+// required properties, numeric ranges, string lengths and patterns, enums and
+// array sizes. It decodes from a standard JSON Schema document; keywords
+// outside the subset are ignored.
+//
+// Example:
+//
+//	var schema JSONSchema
+//	json.Unmarshal([]byte(`{
+//	    "type": "object",
+//	    "required": ["name", "age"],
+//	    "properties": {
+//	        "age":   {"type": "integer", "minimum": 18, "maximum": 65},
+//	        "email": {"type": "string", "pattern": "^[^@]+@example\\.com$"}
+//	    }
+//	}`), &schema)
+type JSONSchema struct {
+	Type             string                 `json:"type,omitempty"`
+	Properties       map[string]*JSONSchema `json:"properties,omitempty"`
+	Required         []string               `json:"required,omitempty"`
+	Items            *JSONSchema            `json:"items,omitempty"`
+	Enum             []any                  `json:"enum,omitempty"`
+	Minimum          *float64               `json:"minimum,omitempty"`
+	Maximum          *float64               `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64               `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64               `json:"exclusiveMaximum,omitempty"`
+	MinLength        *int                   `json:"minLength,omitempty"`
+	MaxLength        *int                   `json:"maxLength,omitempty"`
+	Pattern          string                 `json:"pattern,omitempty"`
+	MinItems         *int                   `json:"minItems,omitempty"`
+	MaxItems         *int                   `json:"maxItems,omitempty"`
+}
+
+// Validate checks that the schema's types are known, its patterns compile and
+// its bounds are ordered
+func (s *JSONSchema) Validate() error {
+	return s.validate("")
+}
+
+func (s *JSONSchema) validate(path string) error {
+	if s == nil {
+		return nil
+	}
+	at := func(format string, args ...any) error {
+		if path == "" {
+			return fmt.Errorf("schema: "+format, args...)
+		}
+		return fmt.Errorf("schema at %s: "+format, append([]any{path}, args...)...)
+	}
+	switch s.Type {
+	case "", "object", "array", "string", "number", "integer", "boolean", "null":
+	default:
+		return at("unknown type %q", s.Type)
+	}
+	if s.Pattern != "" {
+		if _, err := regexp.Compile(s.Pattern); err != nil {
+			return at("invalid pattern: %v", err)
+		}
+	}
+	if s.Minimum != nil && s.Maximum != nil && *s.Minimum > *s.Maximum {
+		return at("minimum %g exceeds maximum %g", *s.Minimum, *s.Maximum)
+	}
+	if s.MinLength != nil && s.MaxLength != nil && *s.MinLength > *s.MaxLength {
+		return at("minLength %d exceeds maxLength %d", *s.MinLength, *s.MaxLength)
+	}
+	if s.MinItems != nil && s.MaxItems != nil && *s.MinItems > *s.MaxItems {
+		return at("minItems %d exceeds maxItems %d", *s.MinItems, *s.MaxItems)
+	}
+	for _, name := range slices.Sorted(maps.Keys(s.Properties)) {
+		if err := s.Properties[name].validate(joinPath(path, name)); err != nil {
+			return err
+		}
+	}
+	return s.Items.validate(joinPath(path, "items"))
+}
+
+// schemaRule renders the schema as a system prompt rule
+func schemaRule(schema *JSONSchema) string {
+	data, _ := json.MarshalIndent(schema, "", "  ")
+	return fmt.Sprintf(`
+- The JSON must satisfy this JSON Schema; ranges, lengths, patterns and required fields are checked:
+%s`, data)
+}
+
+// schemaViolations lists where a JSON response breaks the schema, as
+// "path: problem"
+func schemaViolations(response string, schema *JSONSchema) []string {
+	var raw any
+	decoder := json.NewDecoder(strings.NewReader(cleanJSON(response)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil
+	}
+	var violations []string
+	collectSchemaViolations(raw, schema, "", &violations)
+	return violations
+}
+
+func collectSchemaViolations(value any, schema *JSONSchema, path string, violations *[]string) {
+	if schema == nil {
+		return
+	}
+	label := path
+	if label == "" {
+		label = "value"
+	}
+	report := func(format string, args ...any) {
+		*violations = append(*violations, label+": "+fmt.Sprintf(format, args...))
+	}
+
+	if !schemaTypeMatches(schema.Type, value) {
+		report("expected %s, got %s", schema.Type, jsonKind(value))
+		return
+	}
+	if len(schema.Enum) > 0 && !enumContains(schema.Enum, value) {
+		report("%s is not one of the allowed values", jsonLiteral(value))
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for _, name := range schema.Required {
+			if v[name] == nil {
+				*violations = append(*violations, joinPath(path, name)+": required")
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(schema.Properties)) {
+			if field, ok := v[name]; ok && field != nil {
+				collectSchemaViolations(field, schema.Properties[name], joinPath(path, name), violations)
+			}
+		}
+	case []any:
+		if schema.MinItems != nil && len(v) < *schema.MinItems {
+			report("has %d items, fewer than %d", len(v), *schema.MinItems)
+		}
+		if schema.MaxItems != nil && len(v) > *schema.MaxItems {
+			report("has %d items, more than %d", len(v), *schema.MaxItems)
+		}
+		for i, item := range v {
+			collectSchemaViolations(item, schema.Items, joinPath(path, strconv.Itoa(i)), violations)
+		}
+	case string:
+		length := utf8.RuneCountInString(v)
+		if schema.MinLength != nil && length < *schema.MinLength {
+			report("%q is shorter than %d characters", v, *schema.MinLength)
+		}
+		if schema.MaxLength != nil && length > *schema.MaxLength {
+			report("%q is longer than %d characters", v, *schema.MaxLength)
+		}
+		if schema.Pattern != "" {
+			if pattern, err := regexp.Compile(schema.Pattern); err == nil && !pattern.MatchString(v) {
+				report("%q does not match pattern %s", v, schema.Pattern)
+			}
+		}
+	case json.Number:
+		n, err := v.Float64()
+		if err != nil {
+			return
+		}
+		if schema.Minimum != nil && n < *schema.Minimum {
+			report("%s is below the minimum %g", v, *schema.Minimum)
+		}
+		if schema.Maximum != nil && n > *schema.Maximum {
+			report("%s is above the maximum %g", v, *schema.Maximum)
+		}
+		if schema.ExclusiveMinimum != nil && n <= *schema.ExclusiveMinimum {
+			report("%s must be greater than %g", v, *schema.ExclusiveMinimum)
+		}
+		if schema.ExclusiveMaximum != nil && n >= *schema.ExclusiveMaximum {
+			report("%s must be less than %g", v, *schema.ExclusiveMaximum)
+		}
+	}
+}
+
+// schemaTypeMatches reports whether value has the JSON Schema type; an empty
+// type matches anything
+func schemaTypeMatches(schemaType string, value any) bool {
+	switch schemaType {
+	case "":
+		return true
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		f, err := n.Float64()
+		return err == nil && f == float64(int64(f))
+	}
+	return jsonKind(value) == schemaType
+}
+
+// jsonKind names the JSON Schema type of a decoded value
+func jsonKind(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case json.Number, float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func enumContains(enum []any, value any) bool {
+	literal := jsonLiteral(value)
+	for _, allowed := range enum {
+		if jsonLiteral(allowed) == literal {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonLiteral(value any) string {
+	if n, ok := value.(json.Number); ok {
+		if f, err := n.Float64(); err == nil {
+			return strconv.FormatFloat(f, 'g', -1, 64)
+		}
+	}
+	if f, ok := value.(float64); ok {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
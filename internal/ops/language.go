@@ -0,0 +1,156 @@
+// package ops - Input language detection for language-preserving operations
+package ops
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/monstercameron/schemaflow/internal/logger"
+)
+
+// languageStopwords holds frequent function words of Latin-script languages.
+// Words shared between languages count for each; the distinctive ones decide.
+var languageStopwords = map[string][]string{
+	"English":    {"the", "and", "of", "to", "is", "in", "that", "it", "was", "for", "with", "are", "this", "be", "have", "on", "not", "they", "from", "by", "at", "were", "has", "which", "we"},
+	"Spanish":    {"el", "la", "los", "las", "de", "que", "y", "en", "un", "una", "es", "por", "con", "para", "del", "se", "no", "al", "lo", "como", "más", "pero", "sus", "fue", "está", "son", "muy"},
+	"French":     {"le", "la", "les", "de", "des", "et", "est", "un", "une", "du", "que", "en", "dans", "pour", "pas", "sur", "au", "avec", "ce", "il", "elle", "qui", "sont", "ne", "à", "nous"},
+	"German":     {"der", "die", "das", "und", "ist", "nicht", "ein", "eine", "zu", "den", "mit", "von", "sich", "auf", "für", "dem", "im", "auch", "es", "wird", "sind", "wir", "ich", "wurde"},
+	"Portuguese": {"o", "a", "os", "as", "de", "que", "e", "do", "da", "em", "um", "uma", "para", "com", "não", "no", "na", "por", "mais", "dos", "das", "se", "foi", "são", "é"},
+	"Italian":    {"il", "lo", "la", "gli", "le", "di", "che", "e", "è", "un", "una", "per", "con", "non", "del", "della", "sono", "da", "nel", "anche", "al", "ma", "si"},
+	"Dutch":      {"de", "het", "een", "en", "van", "is", "dat", "niet", "op", "te", "in", "voor", "met", "zijn", "er", "die", "maar", "ook", "aan", "bij", "wordt"},
+}
+
+// minLanguageEvidence is the number of stopword hits needed to name a
+// Latin-script language; shorter texts are left undetected
+const minLanguageEvidence = 3
+
+// detectLanguage returns the English name of text's dominant language, or ""
+// when it cannot be determined with reasonable confidence. Non-Latin scripts
+// are identified by script; Latin-script text by stopword frequency.
+func detectLanguage(text string) string {
+	scripts := map[string]int{}
+	letters := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.Is(unicode.Hangul, r):
+			scripts["Korean"]++
+		case unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+			scripts["Japanese"]++
+		case unicode.Is(unicode.Han, r):
+			scripts["Chinese"]++
+		case unicode.Is(unicode.Cyrillic, r):
+			scripts["Russian"]++
+		case unicode.Is(unicode.Arabic, r):
+			scripts["Arabic"]++
+		case unicode.Is(unicode.Hebrew, r):
+			scripts["Hebrew"]++
+		case unicode.Is(unicode.Greek, r):
+			scripts["Greek"]++
+		case unicode.Is(unicode.Devanagari, r):
+			scripts["Hindi"]++
+		case unicode.Is(unicode.Thai, r):
+			scripts["Thai"]++
+		}
+	}
+	if letters == 0 {
+		return ""
+	}
+	// Kana alongside Han characters is Japanese
+	if scripts["Japanese"] > 0 {
+		scripts["Japanese"] += scripts["Chinese"]
+		delete(scripts, "Chinese")
+	}
+	for language, count := range scripts {
+		if count*2 > letters {
+			return language
+		}
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	counts := map[string]int{}
+	for _, word := range words {
+		counts[word]++
+	}
+
+	best, bestScore, runnerUp := "", 0, 0
+	for language, stopwords := range languageStopwords {
+		score := 0
+		for _, word := range stopwords {
+			score += counts[word]
+		}
+		switch {
+		case score > bestScore:
+			best, bestScore, runnerUp = language, score, bestScore
+		case score > runnerUp:
+			runnerUp = score
+		}
+	}
+	if bestScore < minLanguageEvidence || bestScore == runnerUp {
+		return ""
+	}
+	return best
+}
+
+// preserveLanguageRule returns the detected language of input and the system
+// prompt rule keeping the output in it; both are empty when undetected
+func preserveLanguageRule(input string) (string, string) {
+	language := detectLanguage(input)
+	if language == "" {
+		return "", ""
+	}
+	return language, fmt.Sprintf(`
+- The input is written in %s: write all natural-language output in %s and never translate it`, language, language)
+}
+
+// warnIfLanguageChanged logs a warning when output is detectably written in a
+// language other than expected, and reports whether it was
+func warnIfLanguageChanged(operation, expected, output, requestID string) bool {
+	if expected == "" {
+		return false
+	}
+	actual := detectLanguage(output)
+	if actual == "" || actual == expected {
+		return false
+	}
+	logger.GetLogger().Warn(operation+" output language differs from input",
+		"requestID", requestID,
+		"inputLanguage", expected,
+		"outputLanguage", actual,
+	)
+	return true
+}
+
+// jsonText joins the string values of a JSON document, for checking the
+// language of structured output; non-JSON content is returned unchanged
+func jsonText(document string) string {
+	var value any
+	if err := json.Unmarshal([]byte(cleanJSON(document)), &value); err != nil {
+		return document
+	}
+	var parts []string
+	var walk func(any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case string:
+			parts = append(parts, v)
+		case []any:
+			for _, item := range v {
+				walk(item)
+			}
+		case map[string]any:
+			for _, item := range v {
+				walk(item)
+			}
+		}
+	}
+	walk(value)
+	return strings.Join(parts, " ")
+}
@@ -2,9 +2,11 @@ package ops
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/monstercameron/schemaflow/internal/config"
@@ -16,7 +18,10 @@ import (
 	"github.com/monstercameron/schemaflow/telemetry"
 )
 
-var defaultProvider llm.Provider
+var (
+	defaultProvider   llm.Provider
+	defaultProviderMu sync.RWMutex
+)
 
 // LLMCaller is the function type for calling the LLM
 type LLMCaller func(ctx context.Context, system, user string, opts types.OpOptions) (string, error)
@@ -31,42 +36,92 @@ func setLLMCaller(caller LLMCaller) {
 
 // SetDefaultProvider sets the default LLM provider for operations
 func SetDefaultProvider(p llm.Provider) {
+	defaultProviderMu.Lock()
+	defer defaultProviderMu.Unlock()
 	defaultProvider = p
 }
 
-// callLLM executes an LLM request using the default provider
+// DefaultProvider returns the provider installed by SetDefaultProvider
+func DefaultProvider() llm.Provider {
+	return getDefaultProvider()
+}
+
+// getDefaultProvider returns the provider installed by SetDefaultProvider
+func getDefaultProvider() llm.Provider {
+	defaultProviderMu.RLock()
+	defer defaultProviderMu.RUnlock()
+	return defaultProvider
+}
+
+// callLLM executes an LLM request using the default provider, with the user
+// input fenced as untrusted data (see guardPrompts)
 func callLLM(ctx context.Context, systemPrompt, userPrompt string, opts types.OpOptions) (string, error) {
+	scope := semanticCacheScope(ctx, systemPrompt, opts)
+	var vector []float64
+	if scope != "" {
+		vector = embedForCache(ctx, userPrompt)
+		if response, similarity, ok := lookupSemanticCache(scope, vector, opts.SemanticCacheThreshold); ok {
+			logger.GetLogger().Debug("Semantic cache hit", "requestID", opts.RequestID, "similarity", similarity)
+			return response, nil
+		}
+	}
+
+	userPrompt, err := fitPromptBudget(ctx, systemPrompt, userPrompt, opts)
+	if err != nil {
+		return "", err
+	}
+	systemPrompt, userPrompt, err = guardPrompts(systemPrompt, userPrompt, opts)
+	if err != nil {
+		return "", err
+	}
+	response, err := sendLLM(ctx, systemPrompt, userPrompt, opts)
+	if err == nil && scope != "" {
+		storeSemanticCache(scope, vector, response)
+	}
+	return response, err
+}
+
+// sendLLM is callLLM for prompts whose untrusted input is already fenced
+func sendLLM(ctx context.Context, systemPrompt, userPrompt string, opts types.OpOptions) (string, error) {
+	if opts.DryRun || IsDryRun(ctx) || IsDryRun(opts.Context) {
+		return "", dryRun(ctx, systemPrompt, userPrompt, opts)
+	}
+
+	if threshold := getSlowThreshold(); threshold > 0 {
+		defer startSlowCheck(ctx, opts, threshold)()
+	}
+
+	if !opts.Deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, opts.Deadline)
+		defer cancel()
+	}
+
+	// An operation deadline shared across calls may already have passed
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
 	// Use custom caller if set (for testing)
 	if customLLMCaller != nil {
 		return customLLMCaller(ctx, systemPrompt, userPrompt, opts)
 	}
 
-	if defaultProvider == nil {
+	provider := providerFor(ctx)
+	if provider == nil {
 		// Try to initialize a default provider (e.g. OpenAI from env)
 		// For now, just return error if not set
 		return "", fmt.Errorf("no LLM provider configured")
 	}
-	return CallLLM(ctx, defaultProvider, systemPrompt, userPrompt, opts)
+	return CallLLM(ctx, provider, systemPrompt, userPrompt, opts)
 }
 
 // CallLLM executes an LLM request using the provided provider
 func CallLLM(ctx context.Context, provider llm.Provider, systemPrompt, userPrompt string, opts types.OpOptions) (string, error) {
 	log := logger.GetLogger()
 
-	// Determine model
-	model := config.GetModel(opts.Intelligence, provider.Name())
-	maxTokens := config.GetMaxTokens(opts.Intelligence)
-	temperature := config.GetTemperature(opts.Mode)
-	effectiveSystemPrompt := applySteering(systemPrompt, opts.Steering)
-	responseFormat := inferResponseFormat(effectiveSystemPrompt, userPrompt)
-
-	req := llm.CompletionRequest{
-		Model:          model,
-		SystemPrompt:   strengthenSystemPrompt(effectiveSystemPrompt, responseFormat),
-		UserPrompt:     userPrompt,
-		Temperature:    float64(temperature),
-		MaxTokens:      maxTokens,
-		ResponseFormat: responseFormat,
+	if opts.DryRun || IsDryRun(ctx) {
+		return "", dryRunFor(ctx, provider, systemPrompt, userPrompt, opts)
 	}
 
 	start := time.Now()
@@ -76,6 +131,16 @@ func CallLLM(ctx context.Context, provider llm.Provider, systemPrompt, userPromp
 	opts.RequestID = requestID
 	opts.CorrelationID = correlationID
 
+	if err := checkJSONMode(provider, opts); err != nil {
+		return "", err
+	}
+	req := buildCompletionRequest(provider.Name(), systemPrompt, userPrompt, opts)
+	req.Metadata = requestMetadata(ctx, opts)
+	req.ReasoningEffort = reasoningEffortFor(provider, opts)
+	model := req.Model
+	maxTokens := req.MaxTokens
+	responseFormat := req.ResponseFormat
+
 	log.Debug("LLM request started",
 		"requestID", requestID,
 		"correlationID", correlationID,
@@ -96,13 +161,14 @@ func CallLLM(ctx context.Context, provider llm.Provider, systemPrompt, userPromp
 	}
 
 	attempts := maxRetries + 1
+	complete := interceptedComplete(ctx, provider)
 	var (
 		resp llm.CompletionResponse
 		err  error
 	)
 
 	for attempt := 1; attempt <= attempts; attempt++ {
-		resp, err = provider.Complete(ctx, req)
+		resp, err = complete(ctx, req)
 		if err == nil {
 			if validationErr := validateLLMCompletion(resp); validationErr != nil {
 				err = validationErr
@@ -125,6 +191,7 @@ func CallLLM(ctx context.Context, provider llm.Provider, systemPrompt, userPromp
 				"duration_ms", time.Since(start).Milliseconds(),
 				"error", err,
 			)
+			emitOperationLog(userPrompt, opts, provider.Name(), model, start, types.TokenUsage{}, err)
 			return "", err
 		}
 
@@ -142,10 +209,24 @@ func CallLLM(ctx context.Context, provider llm.Provider, systemPrompt, userPromp
 		)
 
 		if sleepErr := waitForRetry(ctx, delay); sleepErr != nil {
+			emitOperationLog(userPrompt, opts, provider.Name(), model, start, types.TokenUsage{}, sleepErr)
 			return "", sleepErr
 		}
 	}
 
+	if resp, err = completeTruncated(ctx, complete, req, resp, expectsJSON(systemPrompt, userPrompt, opts)); err != nil {
+		log.Error("LLM response truncated",
+			"requestID", requestID,
+			"correlationID", correlationID,
+			"provider", provider.Name(),
+			"model", model,
+			"duration_ms", time.Since(start).Milliseconds(),
+			"error", err,
+		)
+		emitOperationLog(userPrompt, opts, provider.Name(), model, start, types.TokenUsage{}, err)
+		return "", err
+	}
+
 	actualModel := resp.Model
 	if actualModel == "" {
 		actualModel = model
@@ -177,6 +258,8 @@ func CallLLM(ctx context.Context, provider llm.Provider, systemPrompt, userPromp
 		},
 	}
 
+	recordUsage(ctx, usage, cost.TotalCost)
+	recordResultMeta(ctx, resp, actualModel)
 	pricing.TrackCost(cost, metadata)
 	telemetry.RecordLLMMetrics(metadata)
 
@@ -191,10 +274,120 @@ func CallLLM(ctx context.Context, provider llm.Provider, systemPrompt, userPromp
 		"cost_usd", cost.TotalCost,
 		"finishReason", resp.FinishReason,
 	)
+	emitOperationLog(userPrompt, opts, actualProvider, actualModel, start, usage, nil)
 
 	return resp.Content, nil
 }
 
+// truncationGrowth caps how far completeTruncated raises MaxTokens, as a
+// multiple of the request's original limit
+const truncationGrowth = 4
+
+// completeTruncated re-runs a completion whose JSON answer (wantJSON) the
+// max-token limit cut off mid-document, doubling MaxTokens each time up to
+// truncationGrowth times the original limit. Token usage of the discarded
+// attempts is added to the returned response; a response still truncated at
+// the cap yields types.ErrTruncated.
+func completeTruncated(ctx context.Context, complete llm.CompletionFunc, req llm.CompletionRequest, resp llm.CompletionResponse, wantJSON bool) (llm.CompletionResponse, error) {
+	truncated := func(resp llm.CompletionResponse) bool {
+		return resp.FinishReason == llm.FinishReasonLength && wantJSON &&
+			!json.Valid([]byte(cleanJSON(resp.Content)))
+	}
+	if !truncated(resp) {
+		return resp, nil
+	}
+
+	limit := req.MaxTokens * truncationGrowth
+	usage := resp.Usage
+	for truncated(resp) {
+		if req.MaxTokens <= 0 || req.MaxTokens >= limit {
+			return resp, fmt.Errorf("%w (max tokens %d)", types.ErrTruncated, req.MaxTokens)
+		}
+		req.MaxTokens = min(req.MaxTokens*2, limit)
+		logger.GetLogger().Warn("LLM response truncated, re-running with more tokens",
+			"requestID", req.Metadata[llm.MetadataRequestID],
+			"maxTokens", req.MaxTokens,
+		)
+
+		var err error
+		if resp, err = complete(ctx, req); err != nil {
+			return resp, err
+		}
+		usage.PromptTokens += resp.Usage.PromptTokens
+		usage.CompletionTokens += resp.Usage.CompletionTokens
+		usage.TotalTokens += resp.Usage.TotalTokens
+	}
+	resp.Usage = usage
+	return resp, nil
+}
+
+// buildCompletionRequest renders the provider request for an operation
+func buildCompletionRequest(providerName, systemPrompt, userPrompt string, opts types.OpOptions) llm.CompletionRequest {
+	effectiveSystemPrompt := applySteering(systemPrompt, opts.Steering)
+	responseFormat := inferResponseFormat(effectiveSystemPrompt, userPrompt)
+	switch opts.JSONMode {
+	case types.JSONModeOn:
+		responseFormat = "json"
+	case types.JSONModeOff:
+		responseFormat = "text"
+	}
+
+	return llm.CompletionRequest{
+		Model:          config.GetModel(opts.Intelligence, providerName),
+		SystemPrompt:   applyPersona(strengthenSystemPrompt(effectiveSystemPrompt, responseFormat), opts.Persona),
+		UserPrompt:     userPrompt,
+		Temperature:    resolveTemperature(providerName, opts),
+		Deterministic:  isDeterministic(opts),
+		MaxTokens:      config.GetMaxTokens(opts.Intelligence),
+		ResponseFormat: responseFormat,
+	}
+}
+
+// checkJSONMode fails JSONModeOn requests to providers that cannot enforce
+// JSON output natively
+func checkJSONMode(provider llm.Provider, opts types.OpOptions) error {
+	if opts.JSONMode != types.JSONModeOn || llm.CapabilitiesOf(provider).JSONMode {
+		return nil
+	}
+	return fmt.Errorf("%w: JSON mode is on but provider %q has no native JSON output", types.ErrCapabilityUnsupported, provider.Name())
+}
+
+// reasoningEffortFor returns the reasoning effort to send to provider, or ""
+// with a debug log when the provider has no reasoning controls
+func reasoningEffortFor(provider llm.Provider, opts types.OpOptions) string {
+	if opts.ReasoningEffort == "" || llm.CapabilitiesOf(provider).Reasoning {
+		return opts.ReasoningEffort
+	}
+	logger.GetLogger().Debug("Reasoning effort ignored by provider without reasoning controls",
+		"requestID", opts.RequestID,
+		"provider", provider.Name(),
+		"reasoningEffort", opts.ReasoningEffort,
+	)
+	return ""
+}
+
+// isDeterministic reports whether opts ask for a reproducible answer: any
+// mode but Creative, with no explicit temperature above zero. Responses to
+// such requests may be served from cache.
+func isDeterministic(opts types.OpOptions) bool {
+	return opts.Mode != types.Creative && (opts.Temperature == nil || *opts.Temperature <= 0)
+}
+
+// resolveTemperature returns the native temperature for a provider. Explicit
+// temperatures are normalized (0-1) unless RawTemperature is set. Mode
+// defaults are tuned on the OpenAI range and normalized the same way, so a
+// mode and the equivalent WithTemperature value send the same native value.
+func resolveTemperature(providerName string, opts types.OpOptions) float64 {
+	if opts.Temperature == nil {
+		modeDefault := llm.NormalizeTemperature(float64(config.GetTemperature(opts.Mode)))
+		return llm.NativeTemperature(providerName, modeDefault)
+	}
+	if opts.RawTemperature {
+		return llm.ClampTemperature(providerName, *opts.Temperature)
+	}
+	return llm.NativeTemperature(providerName, *opts.Temperature)
+}
+
 func validateLLMCompletion(resp llm.CompletionResponse) error {
 	if strings.TrimSpace(resp.Content) == "" {
 		return fmt.Errorf("provider returned empty completion content")
@@ -206,7 +399,7 @@ func isRetryableLLMError(err error) bool {
 	if err == nil {
 		return false
 	}
-	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, types.ErrCircuitOpen) {
 		return false
 	}
 
@@ -262,6 +455,20 @@ func isRetryableLLMError(err error) bool {
 	return false
 }
 
+// isRateLimitError reports whether err signals provider throttling
+func isRateLimitError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, needle := range []string{"status 429", "rate limit", "too many requests", "throttled"} {
+		if strings.Contains(msg, needle) {
+			return true
+		}
+	}
+	return false
+}
+
 func retryDelay(base time.Duration, attempt int) time.Duration {
 	if attempt <= 1 {
 		return base
@@ -296,6 +503,23 @@ func applySteering(systemPrompt, steering string) string {
 	return strings.TrimSpace(systemPrompt + "\n\nAdditional instructions:\n" + steering)
 }
 
+// applyPersona places the persona text before the system prompt
+func applyPersona(systemPrompt, persona string) string {
+	persona = strings.TrimSpace(persona)
+	if persona == "" {
+		return systemPrompt
+	}
+	return persona + "\n\n" + systemPrompt
+}
+
+// expectsJSON reports whether the operation parses its answer as JSON. It
+// does not depend on the request's response format: JSONModeOff only stops
+// the provider from enforcing JSON, the operation still expects it.
+func expectsJSON(systemPrompt, userPrompt string, opts types.OpOptions) bool {
+	return opts.JSONMode == types.JSONModeOn ||
+		inferResponseFormat(applySteering(systemPrompt, opts.Steering), userPrompt) == "json"
+}
+
 func inferResponseFormat(systemPrompt, userPrompt string) string {
 	combined := strings.ToLower(systemPrompt + "\n" + userPrompt)
 	jsonSignals := []string{
@@ -0,0 +1,146 @@
+// package ops - Applying an operation to every element of a slice as a batch
+package ops
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/monstercameron/schemaflow/internal/logger"
+)
+
+// MapOp applies op to every item with the batch engine's concurrency and
+// error handling. Results and Errors of the returned BatchResult are aligned
+// with items, so a failed item never shifts the others; err is the first
+// item failure (BatchResult.FirstError).
+//
+// opts controls the run: Concurrency bounds the items in flight ("sequential"
+// mode runs one at a time), ErrorStrategy "retry" retries a failed item up
+// to MaxRetries more times, "fail-fast" starts no new items after the first
+// failure (their errors are context.Canceled), and OnProgress is called after
+// each item, never concurrently. Items not started when opts' context ends
+// fail with its error.
+//
+// Example:
+//
+//	scores, err := MapOp(snippets, func(snippet string) (ScoreResult, error) {
+//	    return Score(snippet, NewScoreOptions().WithCriteria([]string{"readability"}))
+//	}, NewBatchOptions().WithConcurrency(4))
+func MapOp[T any, U any](items []T, op func(T) (U, error), opts BatchOptions) (BatchResult[U], error) {
+	log := logger.GetLogger()
+	if err := opts.Validate(); err != nil {
+		return BatchResult[U]{}, err
+	}
+
+	startTime := time.Now()
+	batch := BatchResult[U]{
+		Results: make([]U, len(items)),
+		Errors:  make([]error, len(items)),
+	}
+
+	concurrency := opts.Concurrency
+	if opts.Mode == "sequential" {
+		concurrency = 1
+	}
+	attempts := 1
+	if opts.ErrorStrategy == "retry" {
+		attempts += opts.MaxRetries
+	}
+
+	ctx, cancel := context.WithCancel(opts.GetContext())
+	defer cancel()
+	limiter := newConcurrencyLimiter(concurrency, nil)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	completed, calls := 0, 0
+	for i, item := range items {
+		wg.Add(1)
+		go func(idx int, item T) {
+			defer wg.Done()
+			if !limiter.acquire(ctx) {
+				batch.Errors[idx] = ctx.Err()
+				return
+			}
+
+			var result U
+			var err error
+			for attempt := 1; attempt <= attempts; attempt++ {
+				if ctx.Err() != nil {
+					err = ctx.Err()
+					break
+				}
+				started := time.Now()
+				result, err = op(item)
+				limiter.release(time.Since(started), err)
+				mu.Lock()
+				calls++
+				mu.Unlock()
+				if err == nil {
+					break
+				}
+				if attempt < attempts {
+					log.Debug("MapOp item failed, retrying", "index", idx, "attempt", attempt, "error", err)
+					if !limiter.acquire(ctx) {
+						err = ctx.Err()
+						break
+					}
+				}
+			}
+
+			if err != nil {
+				batch.Errors[idx] = err
+				if opts.ErrorStrategy == "fail-fast" {
+					cancel()
+				}
+			} else {
+				batch.Results[idx] = result
+			}
+
+			mu.Lock()
+			completed++
+			if opts.OnProgress != nil {
+				opts.OnProgress(completed, len(items))
+			}
+			mu.Unlock()
+		}(i, item)
+	}
+	wg.Wait()
+
+	failed := len(batch.Failures())
+	batch.Metadata = BatchMetadata{
+		Mode:             ParallelMode,
+		TotalItems:       len(items),
+		Succeeded:        len(items) - failed,
+		Failed:           failed,
+		Duration:         time.Since(startTime),
+		APICallsMade:     calls,
+		FinalConcurrency: limiter.current(),
+	}
+	log.Debug("MapOp finished", "itemCount", len(items), "failed", failed)
+	return batch, batch.FirstError()
+}
+
+// ClassifyEach classifies every item with MapOp, returning one result per
+// item in input order
+func ClassifyEach[T any, C any](items []T, opts ClassifyOptions, batch BatchOptions) (BatchResult[ClassifyResult[C]], error) {
+	return MapOp(items, func(item T) (ClassifyResult[C], error) {
+		return Classify[T, C](item, opts)
+	}, batch)
+}
+
+// ScoreEach scores every item with MapOp, returning one result per item in
+// input order
+func ScoreEach[T any](items []T, opts ScoreOptions, batch BatchOptions) (BatchResult[ScoreResult], error) {
+	return MapOp(items, func(item T) (ScoreResult, error) {
+		return Score(item, opts)
+	}, batch)
+}
+
+// SummarizeEach summarizes every input with MapOp, returning one summary per
+// input in input order
+func SummarizeEach(inputs []string, opts SummarizeOptions, batch BatchOptions) (BatchResult[string], error) {
+	return MapOp(inputs, func(input string) (string, error) {
+		return Summarize(input, opts)
+	}, batch)
+}
@@ -224,6 +224,7 @@ func SemanticMatch[S any, T any](sources []S, targets []T, opts MatchOptions) (M
 	}
 
 	opt := opts.toOpOptions()
+	opt.Operation = "semantic_match"
 
 	ctx := opt.Context
 	if ctx == nil {
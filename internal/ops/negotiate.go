@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -141,13 +142,7 @@ func Negotiate[T any](constraints any, opts ...NegotiateOptions) (NegotiateResul
 	result.Metadata = make(map[string]any)
 
 	// Apply defaults
-	opt := NegotiateOptions{
-		MinSatisfaction: 0.6,
-		MaxAlternatives: 3,
-		Strategy:        "balanced",
-		Mode:            types.TransformMode,
-		Intelligence:    types.Fast,
-	}
+	opt := defaultNegotiateOptions()
 	if len(opts) > 0 {
 		opt = mergeNegotiateOptions(opt, opts[0])
 	}
@@ -227,6 +222,7 @@ Rules:
 		Context:       ctx,
 		RequestID:     opt.RequestID,
 		CorrelationID: opt.CorrelationID,
+		Operation:     "negotiate",
 	}
 
 	response, err := callLLM(ctx, systemPrompt, userPrompt, opOpts)
@@ -297,6 +293,17 @@ Rules:
 	return result, nil
 }
 
+// defaultNegotiateOptions returns the options Negotiate runs with when none are given
+func defaultNegotiateOptions() NegotiateOptions {
+	return NegotiateOptions{
+		MinSatisfaction: 0.6,
+		MaxAlternatives: 3,
+		Strategy:        "balanced",
+		Mode:            types.TransformMode,
+		Intelligence:    types.Fast,
+	}
+}
+
 // mergeNegotiateOptions merges user options with defaults
 func mergeNegotiateOptions(defaults, user NegotiateOptions) NegotiateOptions {
 	if user.Priorities != nil {
@@ -426,6 +433,32 @@ type AdversarialResult[T any] struct {
 
 	// Confidence in the result quality (0.0-1.0)
 	Confidence float64 `json:"confidence"`
+
+	// ConstraintViolations lists hard constraints the proposed deal broke.
+	// When non-empty, DealReached is false.
+	ConstraintViolations []string `json:"constraint_violations,omitempty"`
+}
+
+// TermConstraint is a non-negotiable numeric limit on a deal term.
+// A nil bound is unconstrained.
+type TermConstraint struct {
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+}
+
+// TermAtLeast returns a constraint with only a floor
+func TermAtLeast(min float64) TermConstraint {
+	return TermConstraint{Min: &min}
+}
+
+// TermAtMost returns a constraint with only a ceiling
+func TermAtMost(max float64) TermConstraint {
+	return TermConstraint{Max: &max}
+}
+
+// TermBetween returns a constraint with both a floor and a ceiling
+func TermBetween(min, max float64) TermConstraint {
+	return TermConstraint{Min: &min, Max: &max}
 }
 
 // AdversarialOptions configures the adversarial negotiation
@@ -433,6 +466,11 @@ type AdversarialOptions struct {
 	// Strategy guides the approach ("aggressive", "balanced", "accommodating")
 	Strategy string
 
+	// Constraints are walk-away limits keyed by the deal's top-level JSON
+	// field names. They are checked deterministically against the final deal;
+	// a deal that violates any of them is reported with DealReached=false.
+	Constraints map[string]TermConstraint
+
 	// Common options
 	Steering      string
 	Intelligence  types.Speed
@@ -466,6 +504,11 @@ type AdversarialOptions struct {
 //	// result.Deal has the final terms
 //	// result.TermMovements shows who moved on each term
 //	// result.WhoConcededMore indicates "they" since we had strong leverage
+//
+//	// Never accept a base salary below 150k
+//	result, err = NegotiateAdversarial(ctx, AdversarialOptions{
+//	    Constraints: map[string]TermConstraint{"base_salary": TermAtLeast(150000)},
+//	})
 func NegotiateAdversarial[T any](context AdversarialContext[T], opts ...AdversarialOptions) (AdversarialResult[T], error) {
 	log := logger.GetLogger()
 	log.Debug("Starting adversarial negotiation")
@@ -490,6 +533,9 @@ func NegotiateAdversarial[T any](context AdversarialContext[T], opts ...Adversar
 		if opts[0].Context != nil {
 			opt.Context = opts[0].Context
 		}
+		if len(opts[0].Constraints) > 0 {
+			opt.Constraints = opts[0].Constraints
+		}
 	}
 
 	// Get context
@@ -549,6 +595,10 @@ Rules:
 	if opt.Steering != "" {
 		steeringNote = fmt.Sprintf("\n\nAdditional guidance: %s", opt.Steering)
 	}
+	if len(opt.Constraints) > 0 {
+		constraintsJSON, _ := json.Marshal(opt.Constraints)
+		steeringNote += fmt.Sprintf("\n\nHard constraints (never cross; if no deal can satisfy them, set deal_reached to false):\n%s", string(constraintsJSON))
+	}
 
 	userPrompt := fmt.Sprintf(`Analyze this adversarial negotiation and determine the final deal:
 
@@ -561,6 +611,7 @@ Rules:
 		Context:       ctx,
 		RequestID:     opt.RequestID,
 		CorrelationID: opt.CorrelationID,
+		Operation:     "negotiate",
 	}
 
 	response, err := callLLM(ctx, systemPrompt, userPrompt, opOpts)
@@ -614,6 +665,18 @@ Rules:
 	result.Reasoning = parsed.Reasoning
 	result.Confidence = parsed.Confidence
 
+	if len(opt.Constraints) > 0 {
+		violations, err := checkTermConstraints(result.Deal, opt.Constraints)
+		if err != nil {
+			return result, err
+		}
+		if len(violations) > 0 {
+			log.Debug("Adversarial negotiation deal violates constraints", "violations", violations)
+			result.ConstraintViolations = violations
+			result.DealReached = false
+		}
+	}
+
 	log.Debug("Adversarial negotiation succeeded",
 		"dealReached", result.DealReached,
 		"whoConceded", result.WhoConcededMore,
@@ -621,3 +684,321 @@ Rules:
 
 	return result, nil
 }
+
+// checkTermConstraints reports each constraint the deal violates. A missing or
+// non-numeric term counts as a violation since it cannot be shown to comply.
+func checkTermConstraints(deal any, constraints map[string]TermConstraint) ([]string, error) {
+	dealJSON, err := json.Marshal(deal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal deal: %w", err)
+	}
+	var terms map[string]any
+	if err := json.Unmarshal(dealJSON, &terms); err != nil {
+		return nil, fmt.Errorf("constraints require an object deal: %w", err)
+	}
+
+	names := make([]string, 0, len(constraints))
+	for name := range constraints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var violations []string
+	for _, name := range names {
+		constraint := constraints[name]
+		value, ok := normalizeFloat(terms[name])
+		switch {
+		case !ok:
+			violations = append(violations, fmt.Sprintf("%s: missing or non-numeric in deal", name))
+		case constraint.Min != nil && value < *constraint.Min:
+			violations = append(violations, fmt.Sprintf("%s: %g is below minimum %g", name, value, *constraint.Min))
+		case constraint.Max != nil && value > *constraint.Max:
+			violations = append(violations, fmt.Sprintf("%s: %g is above maximum %g", name, value, *constraint.Max))
+		}
+	}
+	return violations, nil
+}
+
+// =============================================================================
+// COLLABORATIVE NEGOTIATION API
+// =============================================================================
+
+// Position is one party's stake in a collaborative negotiation
+type Position[T any] struct {
+	// Party names the party; names must be unique
+	Party string `json:"party"`
+
+	// Ideal is the party's preferred outcome
+	Ideal T `json:"ideal"`
+
+	// Ranges are the values the party accepts for numeric terms, keyed by the
+	// deal's top-level JSON field names, and checked against the final deal
+	Ranges map[string]TermConstraint `json:"ranges,omitempty"`
+
+	// Interests are the party's underlying needs in natural language
+	Interests []string `json:"interests,omitempty"`
+
+	// Weight is the party's share of the joint satisfaction (default 1)
+	Weight float64 `json:"weight,omitempty"`
+}
+
+// CollaborativeResult contains the outcome of a collaborative negotiation
+type CollaborativeResult[T any] struct {
+	// Deal is the agreement proposed to every party
+	Deal T `json:"deal"`
+
+	// DealReached is true when the deal lies within every party's ranges
+	DealReached bool `json:"deal_reached"`
+
+	// Satisfaction maps each party to how well the deal serves it (0.0-1.0)
+	Satisfaction map[string]float64 `json:"satisfaction"`
+
+	// JointSatisfaction is the weighted mean of the parties' satisfaction
+	JointSatisfaction float64 `json:"joint_satisfaction"`
+
+	// Rationale explains why the deal is acceptable to each party
+	Rationale string `json:"rationale,omitempty"`
+
+	// Confidence in the result quality (0.0-1.0)
+	Confidence float64 `json:"confidence"`
+
+	// ConstraintViolations lists the party ranges the deal breaks, as
+	// "party: term: problem"; when non-empty, DealReached is false
+	ConstraintViolations []string `json:"constraint_violations,omitempty"`
+}
+
+// NegotiateCollaborative looks for a deal every party can accept, maximizing
+// their joint satisfaction rather than one side's advantage. Ranges are
+// intersected locally first: when no value of a term fits every party, no
+// deal is possible and the provider is not called. A proposed deal outside a
+// party's range is re-prompted once; ranges still broken are reported in
+// ConstraintViolations with DealReached false.
+//
+// Example:
+//
+//	type Contract struct {
+//	    Price        int `json:"price"`
+//	    DeliveryDays int `json:"delivery_days"`
+//	}
+//	result, err := NegotiateCollaborative([]Position[Contract]{
+//	    {Party: "buyer", Ideal: Contract{Price: 90, DeliveryDays: 7},
+//	        Ranges: map[string]TermConstraint{"price": TermAtMost(110)}},
+//	    {Party: "supplier", Ideal: Contract{Price: 120, DeliveryDays: 21},
+//	        Ranges: map[string]TermConstraint{"price": TermAtLeast(100), "delivery_days": TermAtLeast(10)}},
+//	})
+//	fmt.Println(result.Deal, result.Satisfaction)
+func NegotiateCollaborative[T any](parties []Position[T], opts ...NegotiateOptions) (CollaborativeResult[T], error) {
+	log := logger.GetLogger()
+	log.Debug("Starting collaborative negotiation", "parties", len(parties))
+
+	var result CollaborativeResult[T]
+	if len(parties) < 2 {
+		return result, fmt.Errorf("collaborative negotiation needs at least two parties, got %d", len(parties))
+	}
+	seen := make(map[string]bool, len(parties))
+	for _, party := range parties {
+		if strings.TrimSpace(party.Party) == "" {
+			return result, fmt.Errorf("every position needs a party name")
+		}
+		if seen[party.Party] {
+			return result, fmt.Errorf("duplicate party %q", party.Party)
+		}
+		if party.Weight < 0 {
+			return result, fmt.Errorf("party %q has a negative weight", party.Party)
+		}
+		seen[party.Party] = true
+	}
+
+	if conflicts := rangeConflicts(parties); len(conflicts) > 0 {
+		log.Debug("Collaborative negotiation has no feasible deal", "conflicts", conflicts)
+		result.ConstraintViolations = conflicts
+		return result, nil
+	}
+
+	opt := NegotiateOptions{
+		Mode:         types.TransformMode,
+		Intelligence: types.Fast,
+	}
+	if len(opts) > 0 {
+		opt = mergeNegotiateOptions(opt, opts[0])
+	}
+
+	ctx := opt.Context
+	if ctx == nil {
+		ctx = gocontext.Background()
+	}
+	ctx, cancel := gocontext.WithTimeout(ctx, config.GetTimeout())
+	defer cancel()
+
+	partiesJSON, err := json.Marshal(parties)
+	if err != nil {
+		return result, fmt.Errorf("failed to marshal positions: %w", err)
+	}
+
+	var zero T
+	typeSchema := GenerateTypeSchema(reflect.TypeOf(zero))
+
+	systemPrompt := fmt.Sprintf(`You are a mediator seeking a win-win agreement between several parties. Find the deal every party can accept that maximizes their joint satisfaction; no party should be sacrificed for another.
+
+The input lists each party's position:
+- "ideal": the party's preferred outcome
+- "ranges": the values the party accepts for numeric terms ("min" and/or "max"); the deal must lie inside every party's ranges
+- "interests": the party's underlying needs; look for terms that serve several parties at once
+- "weight": the party's relative importance (default 1)
+
+Return a JSON object:
+{
+  "deal": %s,
+  "satisfaction": {"party name": 0.0-1.0, ...},
+  "rationale": "why each party can accept the deal",
+  "confidence": 0.0-1.0
+}
+
+Rules:
+- "deal" must match the schema and respect every party's ranges
+- "satisfaction" must score every party by name (1.0 = as good as its ideal)`, typeSchema)
+
+	if len(opt.Constraints) > 0 {
+		systemPrompt += fmt.Sprintf("\n\nAdditional constraints:\n- %s", strings.Join(opt.Constraints, "\n- "))
+	}
+
+	steeringNote := ""
+	if opt.Steering != "" {
+		steeringNote = fmt.Sprintf("\n\nAdditional guidance: %s", opt.Steering)
+	}
+	userPrompt := fmt.Sprintf("Find the deal that works for every party:\n\n%s%s", string(partiesJSON), steeringNote)
+
+	opOpts := types.OpOptions{
+		Mode:          opt.Mode,
+		Intelligence:  opt.Intelligence,
+		Context:       ctx,
+		RequestID:     opt.RequestID,
+		CorrelationID: opt.CorrelationID,
+		Operation:     "negotiate",
+	}
+
+	propose := func(userPrompt string) error {
+		response, err := callLLM(ctx, systemPrompt, userPrompt, opOpts)
+		if err != nil {
+			return fmt.Errorf("collaborative negotiation failed: %w", err)
+		}
+		var parsed struct {
+			Deal         json.RawMessage `json:"deal"`
+			Satisfaction map[string]any  `json:"satisfaction"`
+			Rationale    string          `json:"rationale"`
+			Confidence   float64         `json:"confidence"`
+		}
+		if err := json.Unmarshal([]byte(cleanJSON(response)), &parsed); err != nil {
+			return fmt.Errorf("failed to parse negotiation result: %w", err)
+		}
+		var deal T
+		if len(parsed.Deal) > 0 {
+			if err := json.Unmarshal(parsed.Deal, &deal); err != nil {
+				return fmt.Errorf("failed to parse deal: %w", err)
+			}
+		}
+		result.Deal = deal
+		result.Satisfaction = make(map[string]float64, len(parties))
+		for _, party := range parties {
+			if score, ok := normalizeFloat(parsed.Satisfaction[party.Party]); ok {
+				result.Satisfaction[party.Party] = max(0, min(1, score))
+			}
+		}
+		result.Rationale = parsed.Rationale
+		result.Confidence = parsed.Confidence
+		result.ConstraintViolations, err = partyRangeViolations(deal, parties)
+		return err
+	}
+
+	if err := propose(userPrompt); err != nil {
+		log.Error("Collaborative negotiation failed", "error", err)
+		return result, err
+	}
+	if len(result.ConstraintViolations) > 0 {
+		log.Debug("Collaborative deal breaks party ranges, re-prompting", "violations", result.ConstraintViolations)
+		previous, _ := json.Marshal(result.Deal)
+		repair := fmt.Sprintf("%s\n\nYour previous deal %s broke these ranges:\n- %s\n\nPropose a deal inside every party's ranges.", userPrompt, previous, strings.Join(result.ConstraintViolations, "\n- "))
+		if err := propose(repair); err != nil {
+			log.Error("Collaborative negotiation failed", "error", err)
+			return result, err
+		}
+	}
+
+	result.DealReached = len(result.ConstraintViolations) == 0
+	result.JointSatisfaction = jointSatisfaction(parties, result.Satisfaction)
+
+	log.Debug("Collaborative negotiation succeeded",
+		"dealReached", result.DealReached,
+		"jointSatisfaction", result.JointSatisfaction)
+
+	return result, nil
+}
+
+// rangeConflicts reports the terms no value of which fits every party's range
+func rangeConflicts[T any](parties []Position[T]) []string {
+	type bound struct {
+		value float64
+		party string
+	}
+	floors := map[string]bound{}
+	ceilings := map[string]bound{}
+	for _, party := range parties {
+		for term, constraint := range party.Ranges {
+			if constraint.Min != nil {
+				if floor, ok := floors[term]; !ok || *constraint.Min > floor.value {
+					floors[term] = bound{*constraint.Min, party.Party}
+				}
+			}
+			if constraint.Max != nil {
+				if ceiling, ok := ceilings[term]; !ok || *constraint.Max < ceiling.value {
+					ceilings[term] = bound{*constraint.Max, party.Party}
+				}
+			}
+		}
+	}
+
+	var conflicts []string
+	for term, floor := range floors {
+		if ceiling, ok := ceilings[term]; ok && floor.value > ceiling.value {
+			conflicts = append(conflicts, fmt.Sprintf("%s: %s needs at least %g but %s accepts at most %g", term, floor.party, floor.value, ceiling.party, ceiling.value))
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+// partyRangeViolations checks the deal against every party's ranges
+func partyRangeViolations[T any](deal T, parties []Position[T]) ([]string, error) {
+	var violations []string
+	for _, party := range parties {
+		if len(party.Ranges) == 0 {
+			continue
+		}
+		broken, err := checkTermConstraints(deal, party.Ranges)
+		if err != nil {
+			return nil, err
+		}
+		for _, violation := range broken {
+			violations = append(violations, party.Party+": "+violation)
+		}
+	}
+	return violations, nil
+}
+
+// jointSatisfaction is the weighted mean satisfaction; parties the model did
+// not score count as 0
+func jointSatisfaction[T any](parties []Position[T], satisfaction map[string]float64) float64 {
+	var total, weights float64
+	for _, party := range parties {
+		weight := party.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		total += weight * satisfaction[party.Party]
+		weights += weight
+	}
+	if weights == 0 {
+		return 0
+	}
+	return total / weights
+}
@@ -0,0 +1,145 @@
+package ops
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+type salaryTerms struct {
+	BaseSalary int `json:"base_salary"`
+	RemoteDays int `json:"remote_days"`
+}
+
+func salaryNegotiation() AdversarialContext[salaryTerms] {
+	return AdversarialContext[salaryTerms]{
+		Ours:        AdversarialPosition[salaryTerms]{Position: salaryTerms{BaseSalary: 160000, RemoteDays: 5}},
+		Theirs:      AdversarialPosition[salaryTerms]{Position: salaryTerms{BaseSalary: 130000, RemoteDays: 2}},
+		OurLeverage: "weak",
+	}
+}
+
+func TestNegotiateAdversarialEnforcesFloor(t *testing.T) {
+	defer setupMockClient()
+
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		if !strings.Contains(user, `"base_salary":{"min":150000}`) {
+			t.Errorf("prompt missing hard constraint: %q", user)
+		}
+		// Weak leverage pulls the proposed salary below our floor
+		return `{"deal": {"base_salary": 142000, "remote_days": 3}, "deal_reached": true, "confidence": 0.8}`, nil
+	})
+
+	opts := AdversarialOptions{
+		Constraints: map[string]TermConstraint{"base_salary": TermAtLeast(150000)},
+	}
+	result, err := NegotiateAdversarial(salaryNegotiation(), opts)
+	if err != nil {
+		t.Fatalf("NegotiateAdversarial() error = %v", err)
+	}
+	if result.DealReached {
+		t.Errorf("DealReached = true for deal with base_salary %d below floor", result.Deal.BaseSalary)
+	}
+	if len(result.ConstraintViolations) != 1 || !strings.Contains(result.ConstraintViolations[0], "base_salary") {
+		t.Errorf("ConstraintViolations = %v, want one base_salary violation", result.ConstraintViolations)
+	}
+}
+
+func TestNegotiateAdversarialAcceptsCompliantDeal(t *testing.T) {
+	defer setupMockClient()
+
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		return `{"deal": {"base_salary": 151000, "remote_days": 3}, "deal_reached": true}`, nil
+	})
+
+	opts := AdversarialOptions{
+		Constraints: map[string]TermConstraint{
+			"base_salary": TermAtLeast(150000),
+			"remote_days": TermBetween(2, 5),
+		},
+	}
+	result, err := NegotiateAdversarial(salaryNegotiation(), opts)
+	if err != nil {
+		t.Fatalf("NegotiateAdversarial() error = %v", err)
+	}
+	if !result.DealReached || len(result.ConstraintViolations) != 0 {
+		t.Errorf("DealReached = %v, violations = %v; want compliant deal", result.DealReached, result.ConstraintViolations)
+	}
+}
+
+type supplyContract struct {
+	Price        int `json:"price"`
+	DeliveryDays int `json:"delivery_days"`
+}
+
+func supplyParties() []Position[supplyContract] {
+	return []Position[supplyContract]{
+		{Party: "buyer", Ideal: supplyContract{Price: 90, DeliveryDays: 7},
+			Ranges: map[string]TermConstraint{"price": TermAtMost(115), "delivery_days": TermAtMost(21)}},
+		{Party: "supplier", Ideal: supplyContract{Price: 125, DeliveryDays: 28},
+			Ranges: map[string]TermConstraint{"price": TermAtLeast(100), "delivery_days": TermAtLeast(10)}},
+		{Party: "carrier", Ideal: supplyContract{DeliveryDays: 14}, Weight: 2,
+			Ranges: map[string]TermConstraint{"delivery_days": TermBetween(12, 20)}},
+	}
+}
+
+func TestNegotiateCollaborativeFindsDealWithinEveryRange(t *testing.T) {
+	defer setupMockClient()
+
+	calls := 0
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		calls++
+		if !strings.Contains(user, `"party":"carrier"`) {
+			t.Errorf("prompt missing a party: %q", user)
+		}
+		if calls == 1 {
+			// Below the supplier's floor and the carrier's minimum
+			return `{"deal": {"price": 95, "delivery_days": 10}, "satisfaction": {"buyer": 0.9, "supplier": 0.3, "carrier": 0.4}}`, nil
+		}
+		if !strings.Contains(user, "supplier: price: 95 is below minimum 100") {
+			t.Errorf("expected the broken ranges in the re-prompt: %q", user)
+		}
+		return `{"deal": {"price": 108, "delivery_days": 14}, "satisfaction": {"buyer": 0.7, "supplier": 0.7, "carrier": 1.0}, "rationale": "splits price, matches the carrier schedule", "confidence": 0.8}`, nil
+	})
+
+	result, err := NegotiateCollaborative(supplyParties())
+	if err != nil {
+		t.Fatalf("NegotiateCollaborative() error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected one corrective re-prompt, got %d calls", calls)
+	}
+	if !result.DealReached || len(result.ConstraintViolations) != 0 {
+		t.Fatalf("expected a deal within every range, got %+v", result)
+	}
+	for _, party := range supplyParties() {
+		if violations, _ := checkTermConstraints(result.Deal, party.Ranges); len(violations) > 0 {
+			t.Errorf("deal %+v is outside %s's ranges: %v", result.Deal, party.Party, violations)
+		}
+	}
+	// Weighted mean: (0.7 + 0.7 + 2*1.0) / 4
+	if result.JointSatisfaction < 0.849 || result.JointSatisfaction > 0.851 {
+		t.Errorf("JointSatisfaction = %f, want 0.85", result.JointSatisfaction)
+	}
+}
+
+func TestNegotiateCollaborativeReportsInfeasibleRangesWithoutCalling(t *testing.T) {
+	defer setupMockClient()
+
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		t.Error("expected no provider call for ranges with no overlap")
+		return "", nil
+	})
+
+	parties := supplyParties()
+	parties[0].Ranges["price"] = TermAtMost(95)
+	result, err := NegotiateCollaborative(parties)
+	if err != nil {
+		t.Fatalf("NegotiateCollaborative() error = %v", err)
+	}
+	if result.DealReached || len(result.ConstraintViolations) != 1 || !strings.Contains(result.ConstraintViolations[0], "supplier needs at least 100 but buyer accepts at most 95") {
+		t.Errorf("expected the price conflict to be reported, got %+v", result)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/monstercameron/schemaflow/internal/config"
 	"github.com/monstercameron/schemaflow/internal/logger"
@@ -210,6 +211,7 @@ func Normalize[T any](input T, opts NormalizeOptions) (NormalizeResult[T], error
 	}
 
 	opt := opts.toOpOptions()
+	opt.Operation = "normalize"
 
 	ctx := opt.Context
 	if ctx == nil {
@@ -344,22 +346,37 @@ func NormalizeText(input string, opts NormalizeOptions) (string, error) {
 	return fmt.Sprintf("%v", result.Normalized), nil
 }
 
-// NormalizeBatch normalizes a slice of items
-func NormalizeBatch[T any](items []T, opts NormalizeOptions) ([]NormalizeResult[T], error) {
+// NormalizeBatch normalizes a slice of items. Every item is attempted; the
+// error is the first item failure (BatchResult.FirstError), and the result
+// holds the outcome of every item, with All giving one entry per input.
+func NormalizeBatch[T any](items []T, opts NormalizeOptions) (BatchResult[NormalizeResult[T]], error) {
 	log := logger.GetLogger()
 	log.Debug("Starting normalize batch operation", "itemCount", len(items))
 
-	results := make([]NormalizeResult[T], len(items))
+	startTime := time.Now()
+	batch := BatchResult[NormalizeResult[T]]{
+		Results: make([]NormalizeResult[T], len(items)),
+		Errors:  make([]error, len(items)),
+	}
 
 	for i, item := range items {
 		result, err := Normalize(item, opts)
 		if err != nil {
 			log.Error("NormalizeBatch failed for item", "index", i, "error", err)
-			return nil, fmt.Errorf("failed to normalize item %d: %w", i, err)
+			batch.Errors[i] = err
+			continue
 		}
-		results[i] = result
+		batch.Results[i] = result
+	}
+
+	failed := len(batch.Failures())
+	batch.Metadata = BatchMetadata{
+		TotalItems: len(items),
+		Succeeded:  len(items) - failed,
+		Failed:     failed,
+		Duration:   time.Since(startTime),
 	}
 
-	log.Debug("NormalizeBatch succeeded", "itemCount", len(items))
-	return results, nil
+	log.Debug("NormalizeBatch finished", "itemCount", len(items), "failed", failed)
+	return batch, batch.FirstError()
 }
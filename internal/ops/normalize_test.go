@@ -137,8 +137,8 @@ func TestNormalizeBatch(t *testing.T) {
 			t.Fatalf("NormalizeBatch failed: %v", err)
 		}
 
-		if len(results) != 3 {
-			t.Errorf("expected 3 results, got %d", len(results))
+		if len(results.All()) != 3 {
+			t.Errorf("expected 3 results, got %d", len(results.All()))
 		}
 	})
 }
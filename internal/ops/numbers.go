@@ -0,0 +1,180 @@
+// package ops - Locale-aware parsing of numbers written as text in extracted data
+package ops
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// numberFormat holds a locale's decimal and digit grouping separators
+type numberFormat struct {
+	decimal rune
+	group   rune
+}
+
+// languageNumberFormats maps language subtags to their number formats.
+// Whitespace is accepted as a grouping separator in every locale.
+var languageNumberFormats = map[string]numberFormat{}
+
+// regionNumberFormats overrides the language format for specific locales
+var regionNumberFormats = map[string]numberFormat{
+	"de-ch": {decimal: '.', group: '\''},
+	"de-li": {decimal: '.', group: '\''},
+	"it-ch": {decimal: '.', group: '\''},
+	"es-mx": {decimal: '.', group: ','},
+	"es-us": {decimal: '.', group: ','},
+}
+
+func init() {
+	formats := map[numberFormat][]string{
+		{decimal: '.', group: ','}: {"en", "ja", "zh", "ko", "he", "th", "hi", "ms"},
+		{decimal: ',', group: '.'}: {"de", "es", "it", "nl", "pt", "id", "tr", "da", "el", "ro", "hr", "sl", "sr", "vi"},
+		{decimal: ',', group: ' '}: {"fr", "ru", "pl", "cs", "sk", "sv", "fi", "nb", "no", "uk", "hu", "bg", "lt", "lv", "et"},
+	}
+	for format, languages := range formats {
+		for _, language := range languages {
+			languageNumberFormats[language] = format
+		}
+	}
+}
+
+// lookupNumberFormat finds the number format for a locale such as "de-DE" or "fr_FR"
+func lookupNumberFormat(locale string) (numberFormat, bool) {
+	tag := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	if format, ok := regionNumberFormats[tag]; ok {
+		return format, true
+	}
+	language, _, _ := strings.Cut(tag, "-")
+	format, ok := languageNumberFormats[language]
+	return format, ok
+}
+
+// parseLocaleNumber reads text such as "1.234,56 €" or "-$1,234.56" with the
+// separators of format. Currency symbols and codes around the number are
+// ignored; a leading minus or surrounding parentheses make it negative.
+func parseLocaleNumber(text string, format numberFormat) (float64, error) {
+	runes := []rune(strings.TrimSpace(text))
+	start, end := -1, -1
+	for i, r := range runes {
+		if unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			end = i
+		}
+	}
+	if start < 0 {
+		return 0, fmt.Errorf("no number in %q", text)
+	}
+	if start > 0 && runes[start-1] == format.decimal {
+		start--
+	}
+
+	prefix, suffix := string(runes[:start]), string(runes[end+1:])
+	negative := strings.ContainsAny(prefix, "-−") ||
+		(strings.Contains(prefix, "(") && strings.Contains(suffix, ")"))
+
+	var digits strings.Builder
+	if negative {
+		digits.WriteByte('-')
+	}
+	seenDecimal := false
+	for _, r := range runes[start : end+1] {
+		switch {
+		case unicode.IsDigit(r):
+			digits.WriteRune(r)
+		case r == format.decimal:
+			if seenDecimal {
+				return 0, fmt.Errorf("more than one decimal separator in %q", text)
+			}
+			seenDecimal = true
+			digits.WriteByte('.')
+		case r == format.group, unicode.IsSpace(r):
+		default:
+			return 0, fmt.Errorf("unexpected %q in number %q", r, text)
+		}
+	}
+	return strconv.ParseFloat(digits.String(), 64)
+}
+
+// normalizeNumberFields parses strings at numeric positions of a JSON
+// response with the separators of format. It returns the rewritten JSON and
+// the paths of strings that could not be parsed; those are cleared so the
+// rest of the response still decodes.
+func normalizeNumberFields(response string, target reflect.Type, format numberFormat) (string, []string) {
+	var raw any
+	decoder := json.NewDecoder(strings.NewReader(cleanJSON(response)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return response, nil
+	}
+	var failed []string
+	normalized := normalizeNumberValue(raw, target, "", format, &failed)
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return response, nil
+	}
+	return string(data), failed
+}
+
+func normalizeNumberValue(value any, t reflect.Type, path string, format numberFormat, failed *[]string) any {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		text, ok := value.(string)
+		if !ok {
+			return value
+		}
+		number, err := parseLocaleNumber(text, format)
+		if err != nil {
+			*failed = append(*failed, path)
+			return nil
+		}
+		return json.Number(strconv.FormatFloat(number, 'f', -1, 64))
+	case reflect.Struct:
+		obj, ok := value.(map[string]any)
+		if !ok || t == timeType {
+			return value
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("json")
+			if !field.IsExported() || tag == "-" || strings.Contains(tag, ",string") {
+				continue
+			}
+			name := jsonFieldName(field)
+			if fieldValue, present := obj[name]; present {
+				obj[name] = normalizeNumberValue(fieldValue, field.Type, joinPath(path, name), format, failed)
+			}
+		}
+		return obj
+	case reflect.Slice, reflect.Array:
+		items, ok := value.([]any)
+		if !ok {
+			return value
+		}
+		for i, item := range items {
+			items[i] = normalizeNumberValue(item, t.Elem(), joinPath(path, strconv.Itoa(i)), format, failed)
+		}
+		return items
+	case reflect.Map:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return value
+		}
+		for key, item := range obj {
+			obj[key] = normalizeNumberValue(item, t.Elem(), joinPath(path, key), format, failed)
+		}
+		return obj
+	}
+	return value
+}
@@ -0,0 +1,112 @@
+// package ops - Structured per-operation log records with redacted prompt previews
+package ops
+
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+// OperationLog is the record passed to the operation log sink after every
+// LLM call an operation makes
+type OperationLog struct {
+	Operation     string           `json:"operation"`
+	Provider      string           `json:"provider"`
+	Model         string           `json:"model"`
+	RequestID     string           `json:"request_id,omitempty"`
+	CorrelationID string           `json:"correlation_id,omitempty"`
+	Time          time.Time        `json:"time"`
+	LatencyMS     int64            `json:"latency_ms"`
+	Tokens        types.TokenUsage `json:"tokens"`
+	Status        string           `json:"status"` // "ok" or "error"
+	Error         string           `json:"error,omitempty"`
+	PromptPreview string           `json:"prompt_preview"`
+}
+
+// operationLogPreviewLength caps the prompt preview, in runes
+const operationLogPreviewLength = 200
+
+// fenceMarker matches the untrusted-data markers added by fenceUntrustedInput
+var fenceMarker = regexp.MustCompile(`<<<(END_)?UNTRUSTED_DATA [^>]*>>>\n?`)
+
+var (
+	operationLogMu     sync.RWMutex
+	operationLogSink   func(OperationLog)
+	operationLogRedact RedactOptions
+)
+
+// SetOperationLog installs sink to receive one OperationLog per LLM call,
+// after the call completes or fails. Prompt previews are masked with the
+// default RedactOptions extended to the "secrets" category; pass redact to
+// choose the categories, patterns and mask instead. A nil sink disables
+// operation logs.
+func SetOperationLog(sink func(OperationLog), redact ...RedactOptions) {
+	opts := NewRedactOptions().WithCategories([]string{"PII", "secrets"})
+	if len(redact) > 0 {
+		opts = redact[0]
+	}
+	operationLogMu.Lock()
+	defer operationLogMu.Unlock()
+	operationLogSink = sink
+	operationLogRedact = opts
+}
+
+// OperationLogWriter returns a sink that writes each record to w as one line
+// of JSON
+func OperationLogWriter(w io.Writer) func(OperationLog) {
+	var mu sync.Mutex
+	encoder := json.NewEncoder(w)
+	return func(record OperationLog) {
+		mu.Lock()
+		defer mu.Unlock()
+		_ = encoder.Encode(record)
+	}
+}
+
+// emitOperationLog sends the record for one LLM call to the installed sink
+func emitOperationLog(userPrompt string, opts types.OpOptions, provider, model string, start time.Time, usage types.TokenUsage, err error) {
+	operationLogMu.RLock()
+	sink, redact := operationLogSink, operationLogRedact
+	operationLogMu.RUnlock()
+	if sink == nil {
+		return
+	}
+
+	operation := opts.Operation
+	if operation == "" {
+		operation = "unknown"
+	}
+	record := OperationLog{
+		Operation:     operation,
+		Provider:      provider,
+		Model:         model,
+		RequestID:     opts.RequestID,
+		CorrelationID: opts.CorrelationID,
+		Time:          start,
+		LatencyMS:     time.Since(start).Milliseconds(),
+		Tokens:        usage,
+		Status:        "ok",
+		PromptPreview: promptPreview(userPrompt, redact),
+	}
+	if err != nil {
+		record.Status = "error"
+		record.Error = err.Error()
+	}
+	sink(record)
+}
+
+// promptPreview masks sensitive values in a user prompt, then shortens it.
+// Masking runs first so truncation cannot leave part of a value unmatched.
+func promptPreview(userPrompt string, redact RedactOptions) string {
+	preview := strings.TrimSpace(fenceMarker.ReplaceAllString(userPrompt, ""))
+	preview = redactString(preview, redact)
+	if runes := []rune(preview); len(runes) > operationLogPreviewLength {
+		preview = string(runes[:operationLogPreviewLength]) + "..."
+	}
+	return preview
+}
@@ -5,6 +5,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/monstercameron/schemaflow/internal/requesttracking"
 	"github.com/monstercameron/schemaflow/internal/types"
@@ -28,6 +30,9 @@ type CommonOptions struct {
 	// Natural language guidance for the operation
 	Steering string
 
+	// Assistant persona placed before the operation's system prompt
+	Persona string
+
 	// Minimum confidence threshold (0.0-1.0)
 	Threshold float64
 
@@ -40,9 +45,59 @@ type CommonOptions struct {
 	// Context for cancellation
 	Context context.Context
 
+	// Sampling temperature override (0-1 normalized unless RawTemperature)
+	Temperature    *float64
+	RawTemperature bool
+
+	// Render the request without calling the provider
+	DryRun bool
+
+	// Metadata attached to outgoing provider requests
+	RequestMetadata map[string]string
+
+	// Respond in the detected language of the input
+	PreserveLanguage bool
+
+	// Reject input that appears to carry instructions for the model
+	InjectionGuard bool
+
+	// Reuse responses for near-duplicate inputs at this cosine similarity
+	SemanticCacheThreshold float64
+
+	// Reasoning effort for reasoning-capable models: "low", "medium" or "high"
+	ReasoningEffort string
+
+	// Provider-native JSON output: auto (default), on or off
+	JSONMode types.JSONMode
+
+	// Reject inputs larger than this many bytes before calling the provider
+	// (0 means unlimited)
+	MaxInputBytes int
+
+	// Bound the estimated tokens of rendered prompts (0 means unlimited) and
+	// choose what happens to prompts above the bound
+	MaxPromptTokens  int
+	TruncationPolicy types.TruncationPolicy
+
+	// Split oversize inputs into chunks within MaxInputBytes instead of
+	// rejecting them, for operations that can combine chunk results
+	AutoChunk bool
+
+	// Reuse the partial result of a chunk already processed with the same
+	// options, keyed on the chunk's content hash
+	ChunkCache bool
+
+	// Bound the whole operation, including every chunk and retry, to this
+	// duration (0 means only the per-call timeout applies)
+	Deadline time.Duration
+
 	// Internal fields
 	RequestID     string
 	CorrelationID string
+
+	// explicit records fields set through With methods, which take
+	// precedence over registered default options
+	explicit optionField
 }
 
 // NewCommonOptions creates reusable default common options.
@@ -96,20 +151,70 @@ func (c CommonOptions) Validate() error {
 	if c.Threshold < 0 || c.Threshold > 1 {
 		return fmt.Errorf("threshold must be between 0 and 1, got %f", c.Threshold)
 	}
+	if c.SemanticCacheThreshold < 0 || c.SemanticCacheThreshold > 1 {
+		return fmt.Errorf("semantic cache threshold must be between 0 and 1, got %f", c.SemanticCacheThreshold)
+	}
+	if c.MaxInputBytes < 0 {
+		return fmt.Errorf("max input bytes cannot be negative, got %d", c.MaxInputBytes)
+	}
+	if c.Deadline < 0 {
+		return fmt.Errorf("deadline cannot be negative, got %s", c.Deadline)
+	}
+	switch c.ReasoningEffort {
+	case "", "low", "medium", "high":
+	default:
+		return fmt.Errorf("reasoning effort must be low, medium or high, got %q", c.ReasoningEffort)
+	}
+	if c.MaxPromptTokens < 0 {
+		return fmt.Errorf("max prompt tokens cannot be negative, got %d", c.MaxPromptTokens)
+	}
+	switch c.TruncationPolicy {
+	case "", types.TruncationError, types.TruncateInput, types.SummarizeInput:
+	default:
+		return fmt.Errorf("truncation policy must be error, truncate-input or summarize-input, got %q", c.TruncationPolicy)
+	}
+	switch c.JSONMode {
+	case "", types.JSONModeAuto, types.JSONModeOn, types.JSONModeOff:
+	default:
+		return fmt.Errorf("JSON mode must be auto, on or off, got %q", c.JSONMode)
+	}
+	if c.Temperature != nil {
+		if *c.Temperature < 0 {
+			return fmt.Errorf("temperature must not be negative, got %f", *c.Temperature)
+		}
+		if !c.RawTemperature && *c.Temperature > 1 {
+			return fmt.Errorf("temperature must be between 0 and 1, got %f (use WithRawTemperature for native values)", *c.Temperature)
+		}
+	}
 	return nil
 }
 
 // toOpOptions converts to legacy OpOptions for backward compatibility
 func (c CommonOptions) toOpOptions() types.OpOptions {
-	ctx, tracking := requesttracking.Ensure(c.GetContext(), c.RequestID, c.CorrelationID)
+	c = c.withDefaults()
+	ctx, tracking := requesttracking.Ensure(withOperationClock(c.GetContext()), c.RequestID, c.CorrelationID)
 	return types.OpOptions{
-		Steering:      c.Steering,
-		Threshold:     c.Threshold,
-		Mode:          c.Mode,
-		Intelligence:  c.Intelligence,
-		Context:       ctx,
-		RequestID:     tracking.RequestID,
-		CorrelationID: tracking.CorrelationID,
+		Steering:               c.Steering,
+		Persona:                c.Persona,
+		Threshold:              c.Threshold,
+		Mode:                   c.Mode,
+		Intelligence:           c.Intelligence,
+		Context:                ctx,
+		RequestID:              tracking.RequestID,
+		CorrelationID:          tracking.CorrelationID,
+		Temperature:            c.Temperature,
+		RawTemperature:         c.RawTemperature,
+		DryRun:                 c.DryRun,
+		RequestMetadata:        c.RequestMetadata,
+		PreserveLanguage:       c.PreserveLanguage,
+		InjectionGuard:         c.InjectionGuard,
+		SemanticCacheThreshold: c.SemanticCacheThreshold,
+		ReasoningEffort:        c.ReasoningEffort,
+		JSONMode:               c.JSONMode,
+		MaxInputBytes:          c.MaxInputBytes,
+		MaxPromptTokens:        c.MaxPromptTokens,
+		TruncationPolicy:       c.TruncationPolicy,
+		Deadline:               c.deadlineAt(),
 	}
 }
 
@@ -119,21 +224,32 @@ func (c CommonOptions) WithSteering(steering string) CommonOptions {
 	return c
 }
 
+// WithPersona sets system text, such as "You are a strict financial
+// auditor.", placed before the operation's own system prompt. It replaces
+// the default persona (see SetDefaultPersona) for this call.
+func (c CommonOptions) WithPersona(persona string) CommonOptions {
+	c.Persona = persona
+	return c
+}
+
 // WithThreshold sets the confidence threshold
 func (c CommonOptions) WithThreshold(threshold float64) CommonOptions {
 	c.Threshold = threshold
+	c.explicit |= fieldThreshold
 	return c
 }
 
 // WithMode sets the reasoning mode
 func (c CommonOptions) WithMode(mode types.Mode) CommonOptions {
 	c.Mode = mode
+	c.explicit |= fieldMode
 	return c
 }
 
 // WithIntelligence sets the intelligence speed
 func (c CommonOptions) WithIntelligence(intelligence types.Speed) CommonOptions {
 	c.Intelligence = intelligence
+	c.explicit |= fieldIntelligence
 	return c
 }
 
@@ -143,6 +259,164 @@ func (c CommonOptions) WithContext(ctx context.Context) CommonOptions {
 	return c
 }
 
+// WithTemperature sets the sampling temperature on a provider-agnostic 0-1 scale.
+// The value is mapped to each provider's native range (e.g. 1.0 -> 2.0 for OpenAI).
+func (c CommonOptions) WithTemperature(temperature float64) CommonOptions {
+	c.Temperature = &temperature
+	c.RawTemperature = false
+	return c
+}
+
+// WithRawTemperature sets the sampling temperature in the provider's native range,
+// bypassing normalization. The value is still clamped to the supported range.
+func (c CommonOptions) WithRawTemperature(temperature float64) CommonOptions {
+	c.Temperature = &temperature
+	c.RawTemperature = true
+	return c
+}
+
+// WithDryRun renders the request instead of calling the provider. The
+// operation fails with a *types.DryRunError carrying the rendered prompt.
+func (c CommonOptions) WithDryRun(enabled bool) CommonOptions {
+	c.DryRun = enabled
+	c.explicit |= fieldDryRun
+	return c
+}
+
+// WithRequestMetadata attaches metadata to every provider request the
+// operation makes, for correlating provider-side logs. Entries are merged over
+// metadata carried by the context (see ContextWithRequestMetadata).
+func (c CommonOptions) WithRequestMetadata(metadata map[string]string) CommonOptions {
+	c.RequestMetadata = metadata
+	return c
+}
+
+// WithPreserveLanguage instructs the model to answer in the detected language
+// of the input and logs a warning when the output language differs.
+func (c CommonOptions) WithPreserveLanguage(enabled bool) CommonOptions {
+	c.PreserveLanguage = enabled
+	c.explicit |= fieldPreserveLanguage
+	return c
+}
+
+// WithInjectionGuard scans user input for instructions aimed at the model
+// (e.g. "ignore previous instructions") before calling the provider, failing
+// with a *types.InjectionError when any are found. Input is always fenced as
+// data in the prompt; the guard adds this detection pass.
+func (c CommonOptions) WithInjectionGuard(enabled bool) CommonOptions {
+	c.InjectionGuard = enabled
+	c.explicit |= fieldInjectionGuard
+	return c
+}
+
+// WithSemanticCache reuses the response of an earlier request whose input has
+// a cosine similarity of at least threshold (e.g. 0.95) to this one, so
+// paraphrased inputs skip the provider. Only read-style operations whose
+// result survives rewording (Summarize, Classify, Score) are cached, and
+// never in Creative mode or with a sampling temperature. Inputs are embedded
+// by the provider when it implements llm.Embedder, lexically otherwise.
+func (c CommonOptions) WithSemanticCache(threshold float64) CommonOptions {
+	c.SemanticCacheThreshold = threshold
+	return c
+}
+
+// WithReasoningEffort sets how much reasoning ("low", "medium" or "high") a
+// reasoning-capable model spends before answering, independent of
+// temperature. Providers without reasoning controls ignore it.
+func (c CommonOptions) WithReasoningEffort(effort string) CommonOptions {
+	c.ReasoningEffort = effort
+	return c
+}
+
+// WithJSONMode controls provider-native JSON output. Auto, the default,
+// requests it for structured operations when the provider supports it; on
+// requests it for every call and fails with ErrCapabilityUnsupported on
+// providers that cannot enforce it; off relies on prompt instructions alone.
+func (c CommonOptions) WithJSONMode(mode types.JSONMode) CommonOptions {
+	c.JSONMode = mode
+	return c
+}
+
+// WithMaxInputBytes rejects inputs larger than n bytes with
+// types.ErrInputTooLarge before any provider call, so an accidentally huge
+// input cannot run up cost. Zero removes the limit.
+func (c CommonOptions) WithMaxInputBytes(n int) CommonOptions {
+	c.MaxInputBytes = n
+	return c
+}
+
+// WithMaxPromptTokens bounds the estimated size of every rendered prompt
+// (system prompt, instructions and input, at about four bytes per token).
+// What happens to a larger prompt is set by WithTruncationPolicy; by default
+// the request fails with types.ErrInputTooLarge before any provider call.
+func (c CommonOptions) WithMaxPromptTokens(n int) CommonOptions {
+	c.MaxPromptTokens = n
+	return c
+}
+
+// WithTruncationPolicy sets how a prompt above WithMaxPromptTokens is
+// handled: types.TruncationError fails it, types.TruncateInput cuts the end
+// of the input, and types.SummarizeInput first summarizes the input to fit,
+// which costs extra calls but keeps content from the whole input.
+func (c CommonOptions) WithTruncationPolicy(policy types.TruncationPolicy) CommonOptions {
+	c.TruncationPolicy = policy
+	return c
+}
+
+// WithAutoChunk lets operations that can combine partial results (currently
+// Summarize) split an input larger than WithMaxInputBytes into chunks within
+// the limit instead of rejecting it. Other operations still reject it.
+func (c CommonOptions) WithAutoChunk(enabled bool) CommonOptions {
+	c.AutoChunk = enabled
+	return c
+}
+
+// WithChunkCache serves chunks of an auto-chunked input that were already
+// processed with the same options from an in-memory cache keyed on their
+// content hash, so re-processing an edited or overlapping document only pays
+// for the chunks that changed. Chunk boundaries fall on paragraphs where
+// possible, so unchanged paragraphs usually produce the same chunks.
+func (c CommonOptions) WithChunkCache(enabled bool) CommonOptions {
+	c.ChunkCache = enabled
+	return c
+}
+
+// WithDeadline bounds the whole operation to d. Unlike the per-call timeout,
+// the budget is shared by every provider call the operation makes, including
+// auto-chunk passes, retries and corrective re-prompts, and every operation
+// fails with an error wrapping context.DeadlineExceeded once it passes.
+// Operations that can combine partial results (currently Summarize) return
+// what they finished along with that error. A deadline on WithContext's
+// context is honored the same way.
+func (c CommonOptions) WithDeadline(d time.Duration) CommonOptions {
+	c.Deadline = d
+	return c
+}
+
+// deadlineAt returns the time at which a deadline started now expires, or
+// the zero time when none is set
+func (c CommonOptions) deadlineAt() time.Time {
+	if c.Deadline <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(c.Deadline)
+}
+
+// startOperation marks the start of an operation that runs nested ones: it
+// returns options whose Context carries the operation's clock and expires
+// after Deadline, with Deadline cleared so nested calls share the budget and
+// the slow-operation timing instead of restarting them
+func (c CommonOptions) startOperation() (CommonOptions, context.CancelFunc) {
+	c.Context = withOperationClock(c.GetContext())
+	if c.Deadline <= 0 {
+		return c, func() {}
+	}
+	ctx, cancel := context.WithTimeout(c.Context, c.Deadline)
+	c.Context = ctx
+	c.Deadline = 0
+	return c, cancel
+}
+
 // WithRequestID sets the request ID for tracing.
 func (c CommonOptions) WithRequestID(requestID string) CommonOptions {
 	c.RequestID = requestID
@@ -178,6 +452,49 @@ type ExtractOptions struct {
 
 	// Field-specific extraction rules
 	FieldRules map[string]string
+
+	// Timezone for dates extracted into time.Time fields that carry no zone
+	// (nil means UTC); zoned dates are converted to it
+	Timezone *time.Location
+
+	// Extra layouts tried first when parsing dates into time.Time fields; Go
+	// layouts ("02-Jan-2006") or patterns ("DD-MMM-YYYY")
+	DateLayouts []string
+
+	// Locale (e.g. "de-DE") whose decimal and grouping separators are used
+	// to parse numbers written as text into numeric fields ("1.234,56 €")
+	Locale string
+
+	// Require every extracted top-level field to cite the input substring it
+	// was drawn from; fields without a verifiable source are left empty
+	GroundedExtraction bool
+
+	// Bounds on the number of items extracted into a slice target; a
+	// response outside them is re-prompted once (0 max means no upper bound)
+	ExpectedCountMin int
+	ExpectedCountMax int
+
+	// Instructions for individual fields keyed by dotted JSON path (e.g.
+	// "shipping.method"); every path must name a field of the target type
+	FieldSteering map[string]string
+
+	// Top-level fields the model rates below EscalationThreshold (0.0-1.0)
+	// are re-extracted at EscalationIntelligence (0 threshold disables)
+	EscalationThreshold    float64
+	EscalationIntelligence types.Speed
+
+	// Return candidate values for top-level fields the input reads more than
+	// one way; ExtractAlternatives reports them
+	Alternatives bool
+
+	// Arithmetic comparisons between fields (e.g. "total == subtotal + tax")
+	// that must hold after extraction; a violation triggers one corrective
+	// re-prompt
+	ConsistencyRules []string
+
+	// consistencyRepair is appended to the user prompt of the corrective
+	// re-prompt for ConsistencyRules
+	consistencyRepair string
 }
 
 // NewExtractOptions creates ExtractOptions with defaults
@@ -199,6 +516,31 @@ func (e ExtractOptions) Validate() error {
 	if e.StrictSchema && e.AllowPartial {
 		return errors.New("cannot have both StrictSchema and AllowPartial")
 	}
+	if e.ExpectedCountMin < 0 || e.ExpectedCountMax < 0 {
+		return errors.New("expected count bounds cannot be negative")
+	}
+	if e.ExpectedCountMax > 0 && e.ExpectedCountMin > e.ExpectedCountMax {
+		return fmt.Errorf("expected count min (%d) cannot exceed max (%d)", e.ExpectedCountMin, e.ExpectedCountMax)
+	}
+	if e.EscalationThreshold < 0 || e.EscalationThreshold > 1 {
+		return fmt.Errorf("escalation threshold must be between 0 and 1, got %f", e.EscalationThreshold)
+	}
+	if e.EscalationThreshold > 0 && e.GroundedExtraction {
+		return errors.New("field escalation cannot be combined with grounded extraction")
+	}
+	if e.Alternatives && (e.GroundedExtraction || e.EscalationThreshold > 0) {
+		return errors.New("alternatives cannot be combined with grounded extraction or field escalation")
+	}
+	if e.Locale != "" {
+		if _, ok := lookupNumberFormat(e.Locale); !ok {
+			return fmt.Errorf("unsupported locale %q", e.Locale)
+		}
+	}
+	for _, rule := range e.ConsistencyRules {
+		if _, err := parseConsistencyRule(rule); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -226,18 +568,95 @@ func (e ExtractOptions) WithExamples(examples ...interface{}) ExtractOptions {
 	return e
 }
 
+// WithFieldSteering scopes instructions to fields addressed by dotted JSON
+// path, e.g. {"shipping.method": "use the carrier's service level"}. Paths
+// pass through arrays, so "items.sku" applies to every item. Extract fails
+// before calling the provider when a path names no field.
+func (e ExtractOptions) WithFieldSteering(steering map[string]string) ExtractOptions {
+	e.FieldSteering = steering
+	return e
+}
+
 // WithFieldRules sets field-specific extraction rules
 func (e ExtractOptions) WithFieldRules(rules map[string]string) ExtractOptions {
 	e.FieldRules = rules
 	return e
 }
 
+// WithTimezone sets the timezone for extracted time.Time fields
+func (e ExtractOptions) WithTimezone(loc *time.Location) ExtractOptions {
+	e.Timezone = loc
+	return e
+}
+
+// WithDateLayouts adds layouts tried before the built-in ones when parsing
+// dates into time.Time fields
+func (e ExtractOptions) WithDateLayouts(layouts ...string) ExtractOptions {
+	e.DateLayouts = append(e.DateLayouts, layouts...)
+	return e
+}
+
+// WithLocale parses numbers written as text into numeric fields with the
+// separators of locale, so "1.234,56 €" reads as 1234.56 under "de-DE"
+func (e ExtractOptions) WithLocale(locale string) ExtractOptions {
+	e.Locale = locale
+	return e
+}
+
+// WithGroundedExtraction requires each extracted field to be traceable to
+// a substring of the input. Use ExtractGrounded to read the cited sources.
+func (e ExtractOptions) WithGroundedExtraction(grounded bool) ExtractOptions {
+	e.GroundedExtraction = grounded
+	return e
+}
+
+// WithExpectedCount bounds the number of items extracted into a slice target.
+// When the model returns a count outside [min, max] it is re-prompted once;
+// a count still out of range fails with an ExtractError. A max of 0 leaves
+// the count unbounded above.
+func (e ExtractOptions) WithExpectedCount(min, max int) ExtractOptions {
+	e.ExpectedCountMin = min
+	e.ExpectedCountMax = max
+	return e
+}
+
+// WithFieldEscalation asks the model to rate its confidence in each
+// top-level field and re-extracts only the fields rated below threshold at
+// the given intelligence level, e.g. a Fast pass escalating to Smart. The
+// escalated values replace the first-pass values; the rest are kept.
+func (e ExtractOptions) WithFieldEscalation(threshold float64, intelligence types.Speed) ExtractOptions {
+	e.EscalationThreshold = threshold
+	e.EscalationIntelligence = intelligence
+	return e
+}
+
+// WithAlternatives asks the model for every plausible reading of ambiguous
+// top-level fields, with a confidence each, instead of a single guess. The
+// extracted value keeps the most likely reading; use ExtractAlternatives to
+// read the candidates.
+func (e ExtractOptions) WithAlternatives(enabled bool) ExtractOptions {
+	e.Alternatives = enabled
+	return e
+}
+
+// WithConsistencyRules sets cross-field rules checked after extraction, such
+// as "total == subtotal + tax"; see ExtractConsistent for the rule syntax
+func (e ExtractOptions) WithConsistencyRules(rules []string) ExtractOptions {
+	e.ConsistencyRules = rules
+	return e
+}
+
 // Builder methods for ExtractOptions that chain CommonOptions methods
 func (e ExtractOptions) WithSteering(steering string) ExtractOptions {
 	e.CommonOptions = e.CommonOptions.WithSteering(steering)
 	return e
 }
 
+func (e ExtractOptions) WithPersona(persona string) ExtractOptions {
+	e.CommonOptions = e.CommonOptions.WithPersona(persona)
+	return e
+}
+
 func (e ExtractOptions) WithThreshold(threshold float64) ExtractOptions {
 	e.CommonOptions = e.CommonOptions.WithThreshold(threshold)
 	return e
@@ -253,6 +672,58 @@ func (e ExtractOptions) WithIntelligence(intelligence types.Speed) ExtractOption
 	return e
 }
 
+func (e ExtractOptions) WithContext(ctx context.Context) ExtractOptions {
+	e.CommonOptions = e.CommonOptions.WithContext(ctx)
+	return e
+}
+
+func (e ExtractOptions) WithDryRun(enabled bool) ExtractOptions {
+	e.CommonOptions = e.CommonOptions.WithDryRun(enabled)
+	return e
+}
+
+func (e ExtractOptions) WithRequestMetadata(metadata map[string]string) ExtractOptions {
+	e.CommonOptions = e.CommonOptions.WithRequestMetadata(metadata)
+	return e
+}
+
+func (e ExtractOptions) WithPreserveLanguage(enabled bool) ExtractOptions {
+	e.CommonOptions = e.CommonOptions.WithPreserveLanguage(enabled)
+	return e
+}
+
+func (e ExtractOptions) WithInjectionGuard(enabled bool) ExtractOptions {
+	e.CommonOptions = e.CommonOptions.WithInjectionGuard(enabled)
+	return e
+}
+
+func (e ExtractOptions) WithReasoningEffort(effort string) ExtractOptions {
+	e.CommonOptions = e.CommonOptions.WithReasoningEffort(effort)
+	return e
+}
+
+func (e ExtractOptions) WithJSONMode(mode types.JSONMode) ExtractOptions {
+	e.CommonOptions = e.CommonOptions.WithJSONMode(mode)
+	return e
+}
+
+func (e ExtractOptions) WithMaxInputBytes(n int) ExtractOptions {
+	e.CommonOptions = e.CommonOptions.WithMaxInputBytes(n)
+	return e
+}
+
+// WithMaxPromptTokens bounds the estimated size of the rendered prompt
+func (e ExtractOptions) WithMaxPromptTokens(n int) ExtractOptions {
+	e.CommonOptions = e.CommonOptions.WithMaxPromptTokens(n)
+	return e
+}
+
+// WithTruncationPolicy sets how a prompt above WithMaxPromptTokens is handled
+func (e ExtractOptions) WithTruncationPolicy(policy types.TruncationPolicy) ExtractOptions {
+	e.CommonOptions = e.CommonOptions.WithTruncationPolicy(policy)
+	return e
+}
+
 func (e ExtractOptions) toOpOptions() types.OpOptions {
 	return e.CommonOptions.toOpOptions()
 }
@@ -279,6 +750,9 @@ type TransformOptions struct {
 		From interface{}
 		To   interface{}
 	}
+
+	// Copy fields shared by JSON name and Go type instead of asking the model
+	CopyMatchingFields bool
 }
 
 // WithMergeStrategy sets the merge strategy
@@ -299,6 +773,14 @@ func (t TransformOptions) WithPreserveFields(fields []string) TransformOptions {
 	return t
 }
 
+// WithCopyMatchingFields copies top-level fields that the source and target
+// share by JSON name and Go type verbatim, sending only the remaining fields
+// to the model. Target fields named in MappingRules are never copied.
+func (t TransformOptions) WithCopyMatchingFields(enabled bool) TransformOptions {
+	t.CopyMatchingFields = enabled
+	return t
+}
+
 // WithTransformLogic sets custom transformation logic
 func (t TransformOptions) WithTransformLogic(logic string) TransformOptions {
 	t.TransformLogic = logic
@@ -375,6 +857,14 @@ type GenerateOptions struct {
 
 	// Style or format preferences
 	Style string
+
+	// Allowed values keyed by dotted JSON path (e.g. "country"); generated
+	// values outside a set are re-prompted once, then reported as an error
+	AllowedValues map[string][]string
+
+	// JSON Schema the generated data must satisfy; violations are
+	// re-prompted once, then reported as an error
+	SchemaConstraints *JSONSchema
 }
 
 // NewGenerateOptions creates GenerateOptions with defaults
@@ -396,7 +886,12 @@ func (g GenerateOptions) Validate() error {
 	if g.Count < 1 {
 		return fmt.Errorf("count must be at least 1, got %d", g.Count)
 	}
-	return nil
+	for path, values := range g.AllowedValues {
+		if len(values) == 0 {
+			return fmt.Errorf("allowed values for %q cannot be empty", path)
+		}
+	}
+	return g.SchemaConstraints.Validate()
 }
 
 // WithTemplate sets the template for generation
@@ -411,6 +906,15 @@ func (g GenerateOptions) WithConstraints(constraints map[string]interface{}) Gen
 	return g
 }
 
+// WithSchemaConstraints constrains structured generation with a JSON Schema:
+// numeric ranges, string lengths and patterns, enums and required fields are
+// stated in the prompt and checked on the result, with one corrective
+// re-prompt before a violation is reported as an error
+func (g GenerateOptions) WithSchemaConstraints(schema JSONSchema) GenerateOptions {
+	g.SchemaConstraints = &schema
+	return g
+}
+
 // WithSeedData sets seed data for generation
 func (g GenerateOptions) WithSeedData(seed interface{}) GenerateOptions {
 	g.SeedData = seed
@@ -429,6 +933,14 @@ func (g GenerateOptions) WithEnsureUnique(unique bool) GenerateOptions {
 	return g
 }
 
+// WithAllowedValues restricts fields, keyed by dotted JSON path, to the given
+// values, such as valid SKUs or country names. Paths reach through arrays, so
+// "country" applies to every element of a []User target.
+func (g GenerateOptions) WithAllowedValues(allowed map[string][]string) GenerateOptions {
+	g.AllowedValues = allowed
+	return g
+}
+
 // WithStyle sets the generation style
 func (g GenerateOptions) WithStyle(style string) GenerateOptions {
 	g.Style = style
@@ -492,6 +1004,16 @@ type SummarizeOptions struct {
 
 	// Maximum compression ratio
 	MaxCompression float64
+
+	// Field selects the struct field (Go or JSON name) to summarize in SummarizeAll
+	Field string
+
+	// ChunkSize is the number of items summarized per map step in SummarizeAll;
+	// at least 2, so each round combines items
+	ChunkSize int
+
+	// Query focuses the summary on answering one question
+	Query string
 }
 
 // NewSummarizeOptions creates SummarizeOptions with defaults
@@ -504,6 +1026,7 @@ func NewSummarizeOptions() SummarizeOptions {
 		LengthUnit:     "sentences",
 		Style:          "paragraph",
 		MaxCompression: 0.1, // 10% of original
+		ChunkSize:      20,
 	}
 }
 
@@ -519,6 +1042,12 @@ func (s SummarizeOptions) Validate() error {
 	if s.MaxCompression < 0 || s.MaxCompression > 1 {
 		return fmt.Errorf("max compression must be between 0 and 1, got %f", s.MaxCompression)
 	}
+	if s.ChunkSize < 0 {
+		return fmt.Errorf("chunk size cannot be negative, got %d", s.ChunkSize)
+	}
+	if s.ChunkSize == 1 {
+		return fmt.Errorf("chunk size must be at least 2 to combine items, got 1")
+	}
 	return nil
 }
 
@@ -534,6 +1063,46 @@ func (s SummarizeOptions) WithMode(mode types.Mode) SummarizeOptions {
 	return s
 }
 
+// WithPreserveLanguage keeps the summary in the language of the input
+func (s SummarizeOptions) WithPreserveLanguage(enabled bool) SummarizeOptions {
+	s.CommonOptions = s.CommonOptions.WithPreserveLanguage(enabled)
+	return s
+}
+
+// WithSemanticCache reuses summaries of near-duplicate inputs
+func (s SummarizeOptions) WithSemanticCache(threshold float64) SummarizeOptions {
+	s.CommonOptions = s.CommonOptions.WithSemanticCache(threshold)
+	return s
+}
+
+// WithTargetLength sets the target summary length and its unit
+func (s SummarizeOptions) WithTargetLength(length int, unit string) SummarizeOptions {
+	s.TargetLength = length
+	s.LengthUnit = unit
+	return s
+}
+
+// WithQuery focuses the summary on what the text says about query, e.g.
+// "what were the Q4 security incidents?". SummarizeWithMetadata reports
+// Relevant false when the text does not address it.
+func (s SummarizeOptions) WithQuery(query string) SummarizeOptions {
+	s.Query = query
+	return s
+}
+
+// WithField sets the struct field summarized by SummarizeAll
+func (s SummarizeOptions) WithField(field string) SummarizeOptions {
+	s.Field = field
+	return s
+}
+
+// WithChunkSize sets how many items SummarizeAll summarizes per chunk (at
+// least 2)
+func (s SummarizeOptions) WithChunkSize(size int) SummarizeOptions {
+	s.ChunkSize = size
+	return s
+}
+
 func (s SummarizeOptions) toOpOptions() types.OpOptions {
 	return s.CommonOptions.toOpOptions()
 }
@@ -618,13 +1187,14 @@ type TranslateOptions struct {
 	// Cultural adaptation level (0=literal, 10=full adaptation)
 	CulturalAdaptation int
 
-	// Formality level for target language
+	// Formality level for target language: "formal", "informal" or
+	// "neutral" (empty means neutral)
 	Formality string
 
 	// Domain-specific terminology
 	Glossary map[string]string
 
-	// Regional dialect
+	// Regional dialect or variant of the target language (e.g. "pt-BR")
 	Dialect string
 }
 
@@ -652,6 +1222,11 @@ func (t TranslateOptions) Validate() error {
 	if t.CulturalAdaptation < 0 || t.CulturalAdaptation > 10 {
 		return fmt.Errorf("cultural adaptation must be between 0 and 10, got %d", t.CulturalAdaptation)
 	}
+	switch t.Formality {
+	case "", "neutral", "formal", "informal":
+	default:
+		return fmt.Errorf("formality must be formal, informal or neutral, got %q", t.Formality)
+	}
 	return nil
 }
 
@@ -661,6 +1236,20 @@ func (t TranslateOptions) WithTargetLanguage(lang string) TranslateOptions {
 	return t
 }
 
+// WithFormality sets the register of the translation: "formal" (e.g. German
+// "Sie"), "informal" (e.g. "du") or "neutral"
+func (t TranslateOptions) WithFormality(formality string) TranslateOptions {
+	t.Formality = formality
+	return t
+}
+
+// WithDialect sets the regional variant of the target language, such as
+// "pt-BR" for Brazilian rather than European Portuguese
+func (t TranslateOptions) WithDialect(dialect string) TranslateOptions {
+	t.Dialect = dialect
+	return t
+}
+
 // WithMode sets the mode
 func (t TranslateOptions) WithMode(mode types.Mode) TranslateOptions {
 	t.CommonOptions = t.CommonOptions.WithMode(mode)
@@ -761,6 +1350,17 @@ type ClassifyOptions struct {
 
 	// Examples per category
 	CategoryExamples map[string][]string
+
+	// Allow an escape label for inputs that fit none of the categories
+	AllowOther bool
+
+	// Label returned when no category applies (defaults to "other")
+	OtherLabel string
+
+	// Confidence band, inclusive, within which results are flagged for human
+	// review (unset when both bounds are zero)
+	ReviewBandLow  float64
+	ReviewBandHigh float64
 }
 
 // NewClassifyOptions creates ClassifyOptions with defaults
@@ -786,9 +1386,30 @@ func (c ClassifyOptions) Validate() error {
 	if c.MinConfidence < 0 || c.MinConfidence > 1 {
 		return fmt.Errorf("min confidence must be between 0 and 1, got %f", c.MinConfidence)
 	}
+	if c.ReviewBandLow != 0 || c.ReviewBandHigh != 0 {
+		if c.ReviewBandLow < 0 || c.ReviewBandHigh > 1 || c.ReviewBandLow > c.ReviewBandHigh {
+			return fmt.Errorf("review band must satisfy 0 <= low <= high <= 1, got [%g, %g]", c.ReviewBandLow, c.ReviewBandHigh)
+		}
+	}
+	if c.AllowOther {
+		other := c.otherLabel()
+		for _, category := range c.Categories {
+			if strings.EqualFold(category, other) {
+				return fmt.Errorf("other label %q duplicates a category", other)
+			}
+		}
+	}
 	return nil
 }
 
+// otherLabel returns the escape label used when AllowOther is set
+func (c ClassifyOptions) otherLabel() string {
+	if c.OtherLabel != "" {
+		return c.OtherLabel
+	}
+	return "other"
+}
+
 // WithCategories sets the categories for classification
 func (c ClassifyOptions) WithCategories(categories []string) ClassifyOptions {
 	c.Categories = categories
@@ -807,6 +1428,36 @@ func (c ClassifyOptions) WithMaxCategories(max int) ClassifyOptions {
 	return c
 }
 
+// WithAllowOther lets the model return the "other" label, with its reasoning,
+// when the input genuinely fits none of the categories
+func (c ClassifyOptions) WithAllowOther(allow bool) ClassifyOptions {
+	c.AllowOther = allow
+	return c
+}
+
+// WithOtherLabel sets the escape label used by WithAllowOther
+func (c ClassifyOptions) WithOtherLabel(label string) ClassifyOptions {
+	c.OtherLabel = label
+	return c
+}
+
+// WithReviewBand flags results whose confidence lies between low and high,
+// inclusive, with NeedsReview, whatever category was chosen, so borderline
+// classifications can be routed to a human
+func (c ClassifyOptions) WithReviewBand(low, high float64) ClassifyOptions {
+	c.ReviewBandLow = low
+	c.ReviewBandHigh = high
+	return c
+}
+
+// needsReview reports whether confidence falls in the review band
+func (c ClassifyOptions) needsReview(confidence float64) bool {
+	if c.ReviewBandLow == 0 && c.ReviewBandHigh == 0 {
+		return false
+	}
+	return confidence >= c.ReviewBandLow && confidence <= c.ReviewBandHigh
+}
+
 // WithSteering sets the steering prompt
 func (c ClassifyOptions) WithSteering(steering string) ClassifyOptions {
 	c.CommonOptions = c.CommonOptions.WithSteering(steering)
@@ -819,6 +1470,12 @@ func (c ClassifyOptions) WithMode(mode types.Mode) ClassifyOptions {
 	return c
 }
 
+// WithSemanticCache reuses classifications of near-duplicate inputs
+func (c ClassifyOptions) WithSemanticCache(threshold float64) ClassifyOptions {
+	c.CommonOptions = c.CommonOptions.WithSemanticCache(threshold)
+	return c
+}
+
 func (c ClassifyOptions) toOpOptions() types.OpOptions {
 	return c.CommonOptions.toOpOptions()
 }
@@ -1018,6 +1675,12 @@ type ChooseOptions struct {
 
 	// Elimination strategy (sequential, tournament, scoring)
 	Strategy string
+
+	// Narrow the list to this many candidates before the LLM stage (0 disables)
+	ShortlistTopK int
+
+	// Scores candidates for the shortlist stage (defaults to embedding similarity)
+	ShortlistScorer ShortlistScorer
 }
 
 // NewChooseOptions creates ChooseOptions with defaults
@@ -1041,6 +1704,9 @@ func (c ChooseOptions) Validate() error {
 	if c.TopN < 1 {
 		return fmt.Errorf("topN must be at least 1, got %d", c.TopN)
 	}
+	if c.ShortlistTopK < 0 {
+		return fmt.Errorf("shortlist topK cannot be negative, got %d", c.ShortlistTopK)
+	}
 	validStrategies := map[string]bool{"sequential": true, "tournament": true, "scoring": true}
 	if c.Strategy != "" && !validStrategies[c.Strategy] {
 		return fmt.Errorf("invalid strategy: %s", c.Strategy)
@@ -1066,6 +1732,27 @@ func (c ChooseOptions) WithTopN(n int) ChooseOptions {
 	return c
 }
 
+// WithShortlistStage considers only the embeddingTopK candidates that best
+// match the criteria in the LLM stage. Candidates are scored by
+// ShortlistScorer, or by embedding similarity (one embedding call, lexical
+// when the provider has no embeddings) when none is set.
+func (c ChooseOptions) WithShortlistStage(embeddingTopK int) ChooseOptions {
+	c.ShortlistTopK = embeddingTopK
+	return c
+}
+
+// WithShortlistScorer sets the scorer used by the shortlist stage
+func (c ChooseOptions) WithShortlistScorer(scorer ShortlistScorer) ChooseOptions {
+	c.ShortlistScorer = scorer
+	return c
+}
+
+// WithPrefilter is WithShortlistStage under its earlier name: only the topK
+// options most similar to the criteria reach the LLM stage
+func (c ChooseOptions) WithPrefilter(topK int) ChooseOptions {
+	return c.WithShortlistStage(topK)
+}
+
 // WithSteering sets the steering prompt.
 func (c ChooseOptions) WithSteering(steering string) ChooseOptions {
 	c.CommonOptions = c.CommonOptions.WithSteering(steering)
@@ -1125,6 +1812,13 @@ type FilterOptions struct {
 
 	// Batch size for processing
 	BatchSize int
+
+	// Evaluate only this many candidates in the LLM stage (0 disables);
+	// items outside the shortlist are treated as not matching
+	ShortlistTopK int
+
+	// Scores candidates for the shortlist stage (defaults to embedding similarity)
+	ShortlistScorer ShortlistScorer
 }
 
 // NewFilterOptions creates FilterOptions with defaults
@@ -1148,6 +1842,9 @@ func (f FilterOptions) Validate() error {
 	if f.Criteria == "" {
 		return errors.New("filter criteria is required")
 	}
+	if f.ShortlistTopK < 0 {
+		return fmt.Errorf("shortlist topK cannot be negative, got %d", f.ShortlistTopK)
+	}
 	if f.MinConfidence < 0 || f.MinConfidence > 1 {
 		return fmt.Errorf("min confidence must be between 0 and 1, got %f", f.MinConfidence)
 	}
@@ -1160,6 +1857,27 @@ func (f FilterOptions) WithCriteria(criteria string) FilterOptions {
 	return f
 }
 
+// WithShortlistStage evaluates only the embeddingTopK candidates that best
+// match the criteria; the rest are treated as not matching. Candidates are
+// scored by ShortlistScorer, or by embedding similarity when none is set.
+func (f FilterOptions) WithShortlistStage(embeddingTopK int) FilterOptions {
+	f.ShortlistTopK = embeddingTopK
+	return f
+}
+
+// WithShortlistScorer sets the scorer used by the shortlist stage
+func (f FilterOptions) WithShortlistScorer(scorer ShortlistScorer) FilterOptions {
+	f.ShortlistScorer = scorer
+	return f
+}
+
+// WithPrefilter is WithShortlistStage under its earlier name: only the topK
+// items most similar to the criteria are evaluated, and the rest are treated
+// as not matching
+func (f FilterOptions) WithPrefilter(topK int) FilterOptions {
+	return f.WithShortlistStage(topK)
+}
+
 // WithMinConfidence sets the minimum confidence for filtering
 func (f FilterOptions) WithMinConfidence(confidence float64) FilterOptions {
 	f.MinConfidence = confidence
@@ -1234,6 +1952,17 @@ type SortOptions struct {
 
 	// Multi-level sort criteria
 	SecondaryCriteria []string
+
+	// Tiered sort keys applied in order, each breaking the ties of the ones
+	// before it; when set, they replace Criteria and Direction
+	Keys []SortKey
+
+	// Sort only this many best-matching candidates with the LLM (0 disables);
+	// the rest follow them unsorted, in shortlist score order
+	ShortlistTopK int
+
+	// Scores candidates for the shortlist stage (defaults to embedding similarity)
+	ShortlistScorer ShortlistScorer
 }
 
 // NewSortOptions creates SortOptions with defaults
@@ -1253,13 +1982,21 @@ func (s SortOptions) Validate() error {
 	if err := s.CommonOptions.Validate(); err != nil {
 		return err
 	}
-	if s.Criteria == "" {
+	if s.Criteria == "" && len(s.Keys) == 0 {
 		return errors.New("sort criteria is required")
 	}
+	if s.ShortlistTopK < 0 {
+		return fmt.Errorf("shortlist topK cannot be negative, got %d", s.ShortlistTopK)
+	}
 	validDirections := map[string]bool{"ascending": true, "descending": true}
 	if s.Direction != "" && !validDirections[s.Direction] {
 		return fmt.Errorf("invalid direction: %s", s.Direction)
 	}
+	for _, key := range s.Keys {
+		if err := key.validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -1275,12 +2012,45 @@ func (s SortOptions) WithDirection(direction string) SortOptions {
 	return s
 }
 
+// WithShortlistStage sorts only the embeddingTopK candidates that best match
+// the criteria with the LLM and appends the rest after them in shortlist
+// score order. The tail is NOT sorted by the criteria, so this suits
+// relevance-style sorts on large lists where only the head of the order
+// needs the model's judgement; SortWithShortlist reports where the sorted
+// head ends. Candidates are scored by ShortlistScorer, or by embedding
+// similarity when none is set.
+func (s SortOptions) WithShortlistStage(embeddingTopK int) SortOptions {
+	s.ShortlistTopK = embeddingTopK
+	return s
+}
+
+// WithShortlistScorer sets the scorer used by the shortlist stage
+func (s SortOptions) WithShortlistScorer(scorer ShortlistScorer) SortOptions {
+	s.ShortlistScorer = scorer
+	return s
+}
+
+// WithPrefilter is WithShortlistStage under its earlier name: only the topK
+// items most similar to the criteria are sorted by the LLM, and the rest
+// follow them unsorted
+func (s SortOptions) WithPrefilter(topK int) SortOptions {
+	return s.WithShortlistStage(topK)
+}
+
 // WithSecondaryCriteria sets secondary sort criteria
 func (s SortOptions) WithSecondaryCriteria(criteria []string) SortOptions {
 	s.SecondaryCriteria = criteria
 	return s
 }
 
+// WithKeys sorts by tiered keys, such as priority descending then effort
+// ascending. Field keys are compared deterministically; Criteria keys are
+// scored by the LLM.
+func (s SortOptions) WithKeys(keys []SortKey) SortOptions {
+	s.Keys = keys
+	return s
+}
+
 // WithSteering sets the steering prompt.
 func (s SortOptions) WithSteering(steering string) SortOptions {
 	s.CommonOptions = s.CommonOptions.WithSteering(steering)
@@ -1353,6 +2123,16 @@ type BatchOptions struct {
 
 	// Result postprocessor
 	PostProcess func(result interface{}) interface{}
+
+	// Hard ceiling on the batch's estimated cost in USD (0 means unlimited)
+	BudgetUSD float64
+
+	// Group key; merged mode never mixes items of different groups in a call
+	GroupBy func(item any) string
+
+	// Store and run ID recording completed items so a rerun resumes
+	CheckpointStore StateStore
+	CheckpointRunID string
 }
 
 // NewBatchOptions creates BatchOptions with defaults
@@ -1389,9 +2169,64 @@ func (b BatchOptions) Validate() error {
 	if b.BatchSize < 1 {
 		return fmt.Errorf("batch size must be at least 1, got %d", b.BatchSize)
 	}
+	if b.BudgetUSD < 0 {
+		return fmt.Errorf("budget must be non-negative, got %.2f", b.BudgetUSD)
+	}
+	if b.MaxRetries < 0 {
+		return fmt.Errorf("max retries cannot be negative, got %d", b.MaxRetries)
+	}
 	return nil
 }
 
+// WithBudgetUSD caps the batch's estimated cost; items that would exceed it
+// are not started (see BatchProcessor.WithBudgetUSD)
+func (b BatchOptions) WithBudgetUSD(max float64) BatchOptions {
+	b.BudgetUSD = max
+	return b
+}
+
+// WithCheckpoint records each completed item's result in store under runID
+// so a rerun skips finished items (see BatchProcessor.WithCheckpoint)
+func (b BatchOptions) WithCheckpoint(store StateStore, runID string) BatchOptions {
+	b.CheckpointStore = store
+	b.CheckpointRunID = runID
+	return b
+}
+
+// WithGroupBy keeps items with the same key together in merged-mode calls
+// (see BatchProcessor.WithGroupBy)
+func (b BatchOptions) WithGroupBy(key func(item any) string) BatchOptions {
+	b.GroupBy = key
+	return b
+}
+
+// WithConcurrency sets the maximum number of items processed at once
+func (b BatchOptions) WithConcurrency(n int) BatchOptions {
+	b.Concurrency = n
+	return b
+}
+
+// WithErrorStrategy sets how item failures are handled: "continue",
+// "fail-fast" or "retry"
+func (b BatchOptions) WithErrorStrategy(strategy string) BatchOptions {
+	b.ErrorStrategy = strategy
+	return b
+}
+
+// WithMaxRetries sets how many more times the "retry" strategy attempts a
+// failed item
+func (b BatchOptions) WithMaxRetries(n int) BatchOptions {
+	b.MaxRetries = n
+	return b
+}
+
+// WithOnProgress sets a callback run after each item with the number of
+// items completed so far
+func (b BatchOptions) WithOnProgress(fn func(completed, total int)) BatchOptions {
+	b.OnProgress = fn
+	return b
+}
+
 func (b BatchOptions) toOpOptions() types.OpOptions {
 	return b.CommonOptions.toOpOptions()
 }
@@ -7,7 +7,9 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -27,10 +29,11 @@ type ParseResult[T any] struct {
 // ParseOptions configures the Parse operation
 type ParseOptions struct {
 	types.OpOptions
-	AllowLLMFallback bool     // Allow LLM fallback for malformed/custom formats
-	AutoFix          bool     // Attempt to fix malformed data
-	FormatHints      []string // Hints about expected formats
-	CustomDelimiters []string // Custom delimiters for parsing
+	AllowLLMFallback bool              // Allow LLM fallback for malformed/custom formats
+	AutoFix          bool              // Attempt to fix malformed data
+	FormatHints      []string          // Hints about expected formats
+	CustomDelimiters []string          // Custom delimiters for parsing
+	HeaderAliases    map[string]string // CSV header -> struct field (Go or JSON name)
 }
 
 // NewParseOptions creates ParseOptions with defaults
@@ -69,6 +72,15 @@ func (opts ParseOptions) WithCustomDelimiters(delimiters []string) ParseOptions
 	return opts
 }
 
+// WithHeaderAliases maps CSV headers to struct fields, given by Go or JSON
+// name, e.g. {"fname": "first_name"}. Headers are compared ignoring case.
+// Aliased headers are mapped before any LLM fallback; an alias naming no
+// field fails the algorithmic parse.
+func (opts ParseOptions) WithHeaderAliases(aliases map[string]string) ParseOptions {
+	opts.HeaderAliases = aliases
+	return opts
+}
+
 // WithIntelligence sets the intelligence level for LLM fallback
 func (opts ParseOptions) WithIntelligence(intelligence types.Speed) ParseOptions {
 	opts.OpOptions.Intelligence = intelligence
@@ -247,7 +259,7 @@ func parseWithAlgorithm[T any](input string, format string, opts ParseOptions) (
 	case "xml":
 		return parseXML[T](input)
 	case "csv":
-		return parseCSV[T](input)
+		return parseCSV[T](input, opts)
 	case "yaml", "yml":
 		return parseYAML[T](input)
 	case "pipe-delimited":
@@ -280,8 +292,8 @@ func parseYAML[T any](input string) (T, error) {
 	return result, err
 }
 
-// parseCSV parses CSV data
-func parseCSV[T any](input string) (T, error) {
+// parseCSV parses CSV data, mapping headers to fields with resolveCSVHeaders
+func parseCSV[T any](input string, opts ParseOptions) (T, error) {
 	var result T
 
 	reader := csv.NewReader(strings.NewReader(input))
@@ -297,15 +309,24 @@ func parseCSV[T any](input string) (T, error) {
 	headers := records[0]
 	data := records[1:]
 
-	// Handle slice types
 	resultType := reflect.TypeOf(result)
+	structType := resultType
+	if resultType.Kind() == reflect.Slice {
+		structType = resultType.Elem()
+	}
+	fields, err := resolveCSVHeaders(headers, structType, opts.HeaderAliases)
+	if err != nil {
+		return result, err
+	}
+
+	// Handle slice types
 	if resultType.Kind() == reflect.Slice {
 		elemType := resultType.Elem()
 		slice := reflect.MakeSlice(resultType, len(data), len(data))
 
 		for i, row := range data {
 			item := reflect.New(elemType).Elem()
-			if err := mapCSVRowToStruct(row, headers, item); err != nil {
+			if err := mapCSVRowToStruct(row, headers, fields, item); err != nil {
 				return result, err
 			}
 			slice.Index(i).Set(item)
@@ -318,7 +339,7 @@ func parseCSV[T any](input string) (T, error) {
 	// Handle single struct
 	if len(data) > 0 {
 		item := reflect.ValueOf(&result).Elem()
-		if err := mapCSVRowToStruct(data[0], headers, item); err != nil {
+		if err := mapCSVRowToStruct(data[0], headers, fields, item); err != nil {
 			return result, err
 		}
 	}
@@ -370,19 +391,84 @@ func parseDelimited[T any](input string, delimiter string, opts ParseOptions) (T
 	return result, nil
 }
 
-// mapCSVRowToStruct maps CSV row to struct fields
-func mapCSVRowToStruct(row []string, headers []string, target reflect.Value) error {
+// resolveCSVHeaders returns, for each header, the index of the struct field
+// it fills, or -1 when it matches none. A header found in aliases is looked up
+// under its alias. Names match a field's JSON name or Go name, exactly first
+// and then ignoring case. It fails when no header matches a field, so the
+// caller can fall back to the LLM instead of returning empty records.
+func resolveCSVHeaders(headers []string, target reflect.Type, aliases map[string]string) ([]int, error) {
+	if target.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("target must be a struct")
+	}
+
+	fields := make([]int, len(headers))
+	matched := 0
+	for i, header := range headers {
+		name := strings.TrimSpace(header)
+		alias, aliased := lookupHeaderAlias(aliases, name)
+		if aliased {
+			name = alias
+		}
+		fields[i] = csvFieldIndex(target, name)
+		if fields[i] < 0 && aliased {
+			return nil, fmt.Errorf("header alias %q -> %q matches no field of %s", header, alias, target)
+		}
+		if fields[i] >= 0 {
+			matched++
+		}
+	}
+	if matched == 0 {
+		return nil, fmt.Errorf("no CSV header matches a field of %s", target)
+	}
+	return fields, nil
+}
+
+// lookupHeaderAlias finds header in aliases, preferring an exact key over
+// case-insensitive ones, which are tried in sorted order
+func lookupHeaderAlias(aliases map[string]string, header string) (string, bool) {
+	if alias, ok := aliases[header]; ok {
+		return alias, true
+	}
+	for _, key := range slices.Sorted(maps.Keys(aliases)) {
+		if strings.EqualFold(key, header) {
+			return aliases[key], true
+		}
+	}
+	return "", false
+}
+
+// csvFieldIndex returns the index of the exported field of target named name
+func csvFieldIndex(target reflect.Type, name string) int {
+	for _, fold := range []bool{false, true} {
+		for i := 0; i < target.NumField(); i++ {
+			field := target.Field(i)
+			if !field.IsExported() || field.Tag.Get("json") == "-" {
+				continue
+			}
+			for _, candidate := range []string{jsonFieldName(field), field.Name} {
+				if candidate == name || (fold && strings.EqualFold(candidate, name)) {
+					return i
+				}
+			}
+		}
+	}
+	return -1
+}
+
+// mapCSVRowToStruct maps CSV row to struct fields, using the field indexes
+// from resolveCSVHeaders
+func mapCSVRowToStruct(row []string, headers []string, fields []int, target reflect.Value) error {
 	if target.Kind() != reflect.Struct {
 		return fmt.Errorf("target must be a struct")
 	}
 
 	for i, header := range headers {
-		if i >= len(row) {
+		if i >= len(row) || fields[i] < 0 {
 			continue
 		}
 
-		field := target.FieldByName(capitalizeFirst(header))
-		if !field.IsValid() || !field.CanSet() {
+		field := target.Field(fields[i])
+		if !field.CanSet() {
 			continue
 		}
 
@@ -490,7 +576,11 @@ func setFieldValue(field reflect.Value, value string) error {
 func parseWithLLM[T any](input string, detectedFormat string, opts ParseOptions) (ParseResult[T], error) {
 	var result ParseResult[T]
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.GetTimeout())
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, config.GetTimeout())
 	defer cancel()
 
 	// Generate type schema
@@ -504,6 +594,7 @@ func parseWithLLM[T any](input string, detectedFormat string, opts ParseOptions)
 
 	// Call LLM
 	opt := opts.toOpOptions()
+	opt.Operation = "parse"
 	response, err := callLLM(ctx, systemPrompt, userPrompt, opt)
 	if err != nil {
 		return result, fmt.Errorf("LLM parsing failed: %w", err)
@@ -554,6 +645,14 @@ func buildParseUserPrompt(input, typeSchema, format string, opts ParseOptions) s
 		prompt += fmt.Sprintf("Custom delimiters: %s\n\n", strings.Join(opts.CustomDelimiters, ", "))
 	}
 
+	if len(opts.HeaderAliases) > 0 {
+		var aliases []string
+		for _, header := range slices.Sorted(maps.Keys(opts.HeaderAliases)) {
+			aliases = append(aliases, fmt.Sprintf("%s -> %s", header, opts.HeaderAliases[header]))
+		}
+		prompt += fmt.Sprintf("Header aliases (source header -> field): %s\n\n", strings.Join(aliases, ", "))
+	}
+
 	return prompt
 }
 
@@ -1,7 +1,10 @@
 package ops
 
 import (
+	"context"
 	"testing"
+
+	"github.com/monstercameron/schemaflow/internal/types"
 )
 
 func TestParse_JSON(t *testing.T) {
@@ -334,3 +337,45 @@ func TestParse_MixedFormat(t *testing.T) {
 		t.Errorf("Expected format 'json', got '%s'", result.Format)
 	}
 }
+
+func TestParse_CSVHeaderAliases(t *testing.T) {
+	defer setupMockClient()
+	calls := 0
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		calls++
+		return `[]`, nil
+	})
+
+	type Contact struct {
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+		Age       int    `json:"age"`
+	}
+
+	input := `FName,Surname,age
+Ada,Lovelace,36
+Alan,Turing,41`
+	opts := NewParseOptions().
+		WithAllowLLMFallback(true).
+		WithHeaderAliases(map[string]string{"fname": "first_name", "surname": "LastName"})
+
+	result, err := Parse[[]Contact](input, opts)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("LLM called %d times, want aliases to resolve headers without it", calls)
+	}
+	want := []Contact{{"Ada", "Lovelace", 36}, {"Alan", "Turing", 41}}
+	if len(result.Data) != len(want) || result.Data[0] != want[0] || result.Data[1] != want[1] {
+		t.Errorf("Data = %+v, want %+v", result.Data, want)
+	}
+	if result.Format != "csv" {
+		t.Errorf("Expected format 'csv', got '%s'", result.Format)
+	}
+
+	_, err = Parse[[]Contact](input, NewParseOptions().WithHeaderAliases(map[string]string{"fname": "given_name"}))
+	if err == nil {
+		t.Error("expected an alias naming no field to fail the parse")
+	}
+}
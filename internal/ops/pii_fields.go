@@ -0,0 +1,103 @@
+// package ops - Struct fields tagged as personal data
+package ops
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// piiMask replaces string values of PII fields in RedactStruct
+const piiMask = "***"
+
+// PIIFields lists the fields of value tagged `pii:"true"` that hold a
+// non-zero value, as dotted JSON paths such as "email" or "contacts.0.phone",
+// so logging and storage layers can apply their data policy. Extract and
+// Transform results report it as PIIFields.
+//
+// Example:
+//
+//	type Customer struct {
+//	    ID    string `json:"id"`
+//	    Email string `json:"email" pii:"true"`
+//	}
+//	PIIFields(Customer{ID: "C1", Email: "ada@example.com"}) // [email]
+func PIIFields(value any) []string {
+	var fields []string
+	walkTaggedFields(reflect.ValueOf(value), "", "pii", func(path, tag string, field reflect.Value) {
+		if tag == "true" && !field.IsZero() {
+			fields = append(fields, path)
+		}
+	})
+	return fields
+}
+
+// RedactStruct returns a copy of value with every field tagged `pii:"true"`
+// masked: strings become "***" and other values their zero value. The copy is
+// made through JSON, so only exported fields carry over; use it for logging,
+// not for data that is written back.
+//
+// Example:
+//
+//	log.Printf("extracted %+v", RedactStruct(customer)) // Email: ***
+func RedactStruct[T any](value T) T {
+	data, err := json.Marshal(value)
+	if err != nil {
+		var zero T
+		return zero
+	}
+	var redacted T
+	if err := json.Unmarshal(data, &redacted); err != nil {
+		var zero T
+		return zero
+	}
+	walkTaggedFields(reflect.ValueOf(&redacted), "", "pii", func(_, tag string, field reflect.Value) {
+		if tag != "true" || !field.CanSet() {
+			return
+		}
+		if field.Kind() == reflect.String {
+			if field.Len() > 0 {
+				field.SetString(piiMask)
+			}
+			return
+		}
+		field.SetZero()
+	})
+	return redacted
+}
+
+// walkTaggedFields calls visit, with the tag's value, for every field
+// carrying tag that is reachable from v through structs, pointers, slices and
+// arrays; tagged fields are not descended into
+func walkTaggedFields(v reflect.Value, path, tag string, visit func(path, value string, field reflect.Value)) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			walkTaggedFields(v.Elem(), path, tag, visit)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+			if field.Anonymous && name == "" {
+				walkTaggedFields(v.Field(i), path, tag, visit)
+				continue
+			}
+			fieldPath := joinPath(path, jsonFieldName(field))
+			if value, ok := field.Tag.Lookup(tag); ok {
+				visit(fieldPath, value, v.Field(i))
+				continue
+			}
+			walkTaggedFields(v.Field(i), fieldPath, tag, visit)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walkTaggedFields(v.Index(i), joinPath(path, strconv.Itoa(i)), tag, visit)
+		}
+	}
+}
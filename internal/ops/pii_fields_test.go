@@ -0,0 +1,57 @@
+package ops
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+type piiContact struct {
+	Name  string `json:"name" pii:"true"`
+	Phone string `json:"phone,omitempty" pii:"true"`
+	Role  string `json:"role"`
+}
+
+type piiAccount struct {
+	ID       string       `json:"id"`
+	Email    string       `json:"email" pii:"true"`
+	Balance  float64      `json:"balance"`
+	Contacts []piiContact `json:"contacts"`
+}
+
+func TestExtractReportsPIIFieldsAndRedactStructMasksThem(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		return `{
+			"id": "ACC-7",
+			"email": "ada@example.com",
+			"balance": 120.5,
+			"contacts": [{"name": "Grace", "phone": "555-0100", "role": "billing"}, {"name": "Alan", "role": "support"}]
+		}`, nil
+	})
+
+	result, err := ExtractConsistent[piiAccount]("Account ACC-7 (ada@example.com) ...", NewExtractOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"email", "contacts.0.name", "contacts.0.phone", "contacts.1.name"}
+	if !reflect.DeepEqual(result.PIIFields, want) {
+		t.Errorf("expected PII fields %v, got %v", want, result.PIIFields)
+	}
+
+	masked := RedactStruct(result.Value)
+	if masked.Email != "***" || masked.Contacts[0].Phone != "***" || masked.Contacts[1].Phone != "" {
+		t.Errorf("expected tagged fields masked, got %+v", masked)
+	}
+	if masked.ID != "ACC-7" || masked.Balance != 120.5 || masked.Contacts[0].Role != "billing" {
+		t.Errorf("expected untagged fields kept, got %+v", masked)
+	}
+	if result.Value.Email != "ada@example.com" || result.Value.Contacts[0].Name != "Grace" {
+		t.Errorf("expected the original to be unchanged, got %+v", result.Value)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/monstercameron/schemaflow/internal/llm"
 	"github.com/monstercameron/schemaflow/internal/logger"
 )
 
@@ -40,6 +41,10 @@ type PipelineResult struct {
 	StepsFailed   int
 	Duration      time.Duration
 	Errors        []error
+
+	// RunID identifies this execution in provider request metadata; nested
+	// pipelines share the run ID of the pipeline that executes them
+	RunID string
 }
 
 // NewPipeline creates a new pipeline
@@ -83,14 +88,21 @@ func (p *Pipeline) AddOptional(name string, operation func(context.Context, any)
 	return p
 }
 
-// Execute runs the pipeline with the given input
+// Execute runs the pipeline with the given input.
+//
+// Each step's context carries request metadata naming the run, pipeline and
+// step (see ContextWithRequestMetadata); operations given that context attach
+// it to their provider requests.
 func (p *Pipeline) Execute(ctx context.Context, input any) PipelineResult {
 	startTime := time.Now()
 	log := logger.GetLogger()
+	ctx, runID := contextWithRunID(ctx, "")
 	result := PipelineResult{
 		Output: input,
 		Errors: []error{},
+		RunID:  runID,
 	}
+	parentStep := RequestMetadataFromContext(ctx)[llm.MetadataPipelineStep]
 
 	// Apply timeout if specified
 	if p.opts.Timeout > 0 {
@@ -114,8 +126,18 @@ func (p *Pipeline) Execute(ctx context.Context, input any) PipelineResult {
 			"pipeline", p.name,
 			"step", step.Name,
 			"index", i,
+			"runID", runID,
 		)
 
+		stepPath := step.Name
+		if parentStep != "" {
+			stepPath = parentStep + "/" + step.Name
+		}
+		stepCtx := ContextWithRequestMetadata(ctx, map[string]string{
+			llm.MetadataPipeline:     p.name,
+			llm.MetadataPipelineStep: stepPath,
+		})
+
 		// Execute with retry if configured
 		var stepErr error
 		attempts := 1
@@ -124,7 +146,7 @@ func (p *Pipeline) Execute(ctx context.Context, input any) PipelineResult {
 		}
 
 		for attempt := 0; attempt < attempts; attempt++ {
-			output, err := step.Operation(ctx, current)
+			output, err := step.Operation(stepCtx, current)
 			if err == nil {
 				current = output
 				result.StepsExecuted++
@@ -204,6 +226,92 @@ func Then[T any, U any, V any](
 	}
 }
 
+// Compose2 composes two typed operations into one. The second operation
+// only runs when the first succeeds.
+func Compose2[A any, B any, C any](
+	first func(A) (B, error),
+	second func(B) (C, error),
+) func(A) (C, error) {
+	return func(input A) (C, error) {
+		var zero C
+
+		intermediate, err := first(input)
+		if err != nil {
+			return zero, fmt.Errorf("compose stage 1 failed: %w", err)
+		}
+
+		result, err := second(intermediate)
+		if err != nil {
+			return zero, fmt.Errorf("compose stage 2 failed: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+// Stage is a typed, context-aware operation that can be composed with Chain
+type Stage[In any, Out any] func(context.Context, In) (Out, error)
+
+// Lift adapts a context-free operation into a Stage
+func Lift[In any, Out any](operation func(In) (Out, error)) Stage[In, Out] {
+	return func(_ context.Context, input In) (Out, error) {
+		return operation(input)
+	}
+}
+
+// Chain composes two stages, passing the caller's context to each. The next
+// stage is skipped when the first fails or the context is done in between.
+// Chains nest, so longer typed chains are built left to right:
+//
+//	flow := Chain(Chain(extract, enrich), transform)
+//	out, err := flow(ctx, input)
+func Chain[A any, B any, C any](first Stage[A, B], next Stage[B, C]) Stage[A, C] {
+	return func(ctx context.Context, input A) (C, error) {
+		var zero C
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if err := ctx.Err(); err != nil {
+			return zero, err
+		}
+
+		intermediate, err := first(ctx, input)
+		if err != nil {
+			return zero, err
+		}
+		if err := ctx.Err(); err != nil {
+			return zero, err
+		}
+
+		return next(ctx, intermediate)
+	}
+}
+
+// ComposeStages chains any number of same-typed stages left to right,
+// stopping at the first error or when the context is done
+func ComposeStages[T any](stages ...Stage[T, T]) Stage[T, T] {
+	return func(ctx context.Context, input T) (T, error) {
+		var zero T
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		current := input
+		for i, stage := range stages {
+			if err := ctx.Err(); err != nil {
+				return zero, err
+			}
+			next, err := stage(ctx, current)
+			if err != nil {
+				return zero, fmt.Errorf("compose stage %d failed: %w", i+1, err)
+			}
+			current = next
+		}
+
+		return current, nil
+	}
+}
+
 // Map applies an operation to each element in a slice
 func Map[T any, U any](items []T, operation func(T) (U, error)) ([]U, error) {
 	results := make([]U, len(items))
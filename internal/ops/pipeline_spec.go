@@ -0,0 +1,115 @@
+// package ops - Storing pipeline definitions as data
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// pipelineSpecVersion is the spec format written by MarshalSpec
+const pipelineSpecVersion = 1
+
+// StepFactory builds the operation for a pipeline step loaded from a spec
+type StepFactory func() func(context.Context, any) (any, error)
+
+// StepRegistry maps step names to the factories LoadPipeline builds them with.
+// Services that load the same specs register the same names.
+type StepRegistry map[string]StepFactory
+
+// pipelineSpec is the serialized form of a Pipeline
+type pipelineSpec struct {
+	Version int                 `json:"version"`
+	Name    string              `json:"name"`
+	Options pipelineSpecOptions `json:"options"`
+	Steps   []pipelineSpecStep  `json:"steps"`
+}
+
+type pipelineSpecOptions struct {
+	FailFast     bool   `json:"fail_fast"`
+	Timeout      string `json:"timeout,omitempty"`
+	RetryFailed  bool   `json:"retry_failed"`
+	MaxRetries   int    `json:"max_retries"`
+	SaveProgress bool   `json:"save_progress"`
+}
+
+type pipelineSpecStep struct {
+	Name     string `json:"name"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+// MarshalSpec serializes the pipeline's name, options and step list as JSON.
+// Steps are recorded by name only; LoadPipeline rebuilds their operations
+// from a StepRegistry, so every step name must be registered where the spec
+// is loaded.
+//
+// Example:
+//
+//	spec, err := pipeline.MarshalSpec()
+//	// ... store or send spec ...
+//	loaded, err := LoadPipeline(spec, registry)
+func (p *Pipeline) MarshalSpec() ([]byte, error) {
+	spec := pipelineSpec{
+		Version: pipelineSpecVersion,
+		Name:    p.name,
+		Options: pipelineSpecOptions{
+			FailFast:     p.opts.FailFast,
+			RetryFailed:  p.opts.RetryFailed,
+			MaxRetries:   p.opts.MaxRetries,
+			SaveProgress: p.opts.SaveProgress,
+		},
+		Steps: make([]pipelineSpecStep, len(p.steps)),
+	}
+	if p.opts.Timeout > 0 {
+		spec.Options.Timeout = p.opts.Timeout.String()
+	}
+	for i, step := range p.steps {
+		if step.Name == "" {
+			return nil, fmt.Errorf("step %d has no name and cannot be loaded from a spec", i)
+		}
+		spec.Steps[i] = pipelineSpecStep{Name: step.Name, Optional: step.Optional}
+	}
+	return json.Marshal(spec)
+}
+
+// LoadPipeline rebuilds a pipeline from a spec written by MarshalSpec, taking
+// each step's operation from registry. It fails if a step name is not
+// registered.
+func LoadPipeline(spec []byte, registry StepRegistry) (*Pipeline, error) {
+	var decoded pipelineSpec
+	if err := json.Unmarshal(spec, &decoded); err != nil {
+		return nil, fmt.Errorf("invalid pipeline spec: %w", err)
+	}
+	if decoded.Version != pipelineSpecVersion {
+		return nil, fmt.Errorf("unsupported pipeline spec version %d", decoded.Version)
+	}
+
+	opts := PipelineOptions{
+		FailFast:     decoded.Options.FailFast,
+		RetryFailed:  decoded.Options.RetryFailed,
+		MaxRetries:   decoded.Options.MaxRetries,
+		SaveProgress: decoded.Options.SaveProgress,
+	}
+	if decoded.Options.Timeout != "" {
+		timeout, err := time.ParseDuration(decoded.Options.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pipeline timeout %q: %w", decoded.Options.Timeout, err)
+		}
+		opts.Timeout = timeout
+	}
+
+	p := NewPipeline(decoded.Name, opts)
+	for _, step := range decoded.Steps {
+		factory, ok := registry[step.Name]
+		if !ok || factory == nil {
+			return nil, fmt.Errorf("pipeline %q step %q is not registered", decoded.Name, step.Name)
+		}
+		p.steps = append(p.steps, PipelineStep{
+			Name:      step.Name,
+			Operation: factory(),
+			Optional:  step.Optional,
+		})
+	}
+	return p, nil
+}
@@ -6,6 +6,9 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/monstercameron/schemaflow/internal/llm"
+	"github.com/monstercameron/schemaflow/internal/types"
 )
 
 func TestPipeline(t *testing.T) {
@@ -513,3 +516,255 @@ func TestCachedOperation(t *testing.T) {
 		}
 	})
 }
+
+type pipelineTicket struct {
+	Customer string `json:"customer"`
+	Summary  string `json:"summary"`
+}
+
+func TestPipelineWithLocalOpHandlers(t *testing.T) {
+	calls := map[string]int{}
+	local, _ := llm.NewLocalProvider(llm.ProviderConfig{})
+	local.
+		WithOpHandler("extract", func(ctx context.Context, req llm.CompletionRequest) (string, error) {
+			calls["extract"]++
+			return `{"customer": "Ada", "summary": "Invoice charged twice"}`, nil
+		}).
+		WithOpHandler("classify", func(ctx context.Context, req llm.CompletionRequest) (string, error) {
+			calls["classify"]++
+			if !strings.Contains(req.UserPrompt, "Invoice charged twice") {
+				t.Errorf("expected classify to receive the extracted summary, got %q", req.UserPrompt)
+			}
+			return `{"category": "billing", "confidence": 0.9}`, nil
+		})
+
+	previous := getDefaultProvider()
+	SetDefaultProvider(local)
+	setLLMCaller(nil)
+	defer func() {
+		SetDefaultProvider(previous)
+		setupMockClient()
+	}()
+
+	p := NewPipeline("ticket-triage").
+		Add("extract", func(ctx context.Context, input any) (any, error) {
+			return Extract[pipelineTicket](input, NewExtractOptions())
+		}).
+		Add("classify", func(ctx context.Context, input any) (any, error) {
+			ticket := input.(pipelineTicket)
+			return Classify[string, string](ticket.Summary, NewClassifyOptions().WithCategories([]string{"billing", "technical"}))
+		})
+
+	result := p.Execute(context.Background(), "Ada says her invoice was charged twice")
+	if len(result.Errors) > 0 {
+		t.Fatalf("pipeline failed: %v", result.Errors)
+	}
+	if calls["extract"] != 1 || calls["classify"] != 1 {
+		t.Errorf("expected each op handler to be used once, got %v", calls)
+	}
+	classified, ok := result.Output.(ClassifyResult[string])
+	if !ok || classified.Category != "billing" {
+		t.Errorf("expected billing classification, got %#v", result.Output)
+	}
+}
+
+func TestNestedPipelineStepSendsRequestMetadata(t *testing.T) {
+	var sent map[string]string
+	local, _ := llm.NewLocalProvider(llm.ProviderConfig{})
+	local.WithOpHandler("extract", func(ctx context.Context, req llm.CompletionRequest) (string, error) {
+		sent = req.Metadata
+		return `{"customer": "Ada", "summary": "Invoice charged twice"}`, nil
+	})
+
+	previous := getDefaultProvider()
+	SetDefaultProvider(local)
+	setLLMCaller(nil)
+	defer func() {
+		SetDefaultProvider(previous)
+		setupMockClient()
+	}()
+
+	inner := NewPipeline("ticket-intake").
+		Add("extract", func(ctx context.Context, input any) (any, error) {
+			opts := NewExtractOptions().
+				WithContext(ctx).
+				WithRequestMetadata(map[string]string{"tenant": "acme"})
+			return Extract[pipelineTicket](input, opts)
+		})
+	outer := NewPipeline("support").
+		Add("intake", func(ctx context.Context, input any) (any, error) {
+			result := inner.Execute(ctx, input)
+			if len(result.Errors) > 0 {
+				return nil, result.Errors[0]
+			}
+			return result.Output, nil
+		})
+
+	ctx := ContextWithRequestMetadata(context.Background(), map[string]string{llm.MetadataRunID: "run-42"})
+	result := outer.Execute(ctx, "Ada says her invoice was charged twice")
+	if len(result.Errors) > 0 {
+		t.Fatalf("pipeline failed: %v", result.Errors)
+	}
+	if result.RunID != "run-42" {
+		t.Errorf("expected the caller's run ID to be kept, got %q", result.RunID)
+	}
+
+	want := map[string]string{
+		llm.MetadataRunID:        "run-42",
+		llm.MetadataPipeline:     "ticket-intake",
+		llm.MetadataPipelineStep: "intake/extract",
+		"tenant":                 "acme",
+	}
+	for key, value := range want {
+		if sent[key] != value {
+			t.Errorf("expected metadata %s=%q on the outgoing request, got %q (all: %v)", key, value, sent[key], sent)
+		}
+	}
+	if sent[llm.MetadataRequestID] == "" {
+		t.Errorf("expected the request ID in metadata, got %v", sent)
+	}
+}
+
+type composedEmployee struct {
+	FullName string `json:"full_name"`
+	Years    int    `json:"years"`
+}
+
+func TestCompose2ExtractThenTransform(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	var transformCalls int
+	failExtract := false
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		switch {
+		case strings.Contains(system, "extraction expert"):
+			if failExtract {
+				return "", fmt.Errorf("provider unavailable")
+			}
+			return `{"Name": "Ada", "Age": 36}`, nil
+		case strings.Contains(system, "transformation expert"):
+			transformCalls++
+			return `{"full_name": "Ada", "years": 36}`, nil
+		}
+		return "", fmt.Errorf("unexpected prompt: %s", system)
+	})
+
+	extract := func(text string) (Person, error) {
+		return Extract[Person](text, NewExtractOptions())
+	}
+	transform := func(p Person) (composedEmployee, error) {
+		return Transform[Person, composedEmployee](p, NewTransformOptions())
+	}
+	flow := Compose2(extract, transform)
+
+	employee, err := flow("Ada, 36")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if employee.FullName != "Ada" || employee.Years != 36 || transformCalls != 1 {
+		t.Errorf("got %+v after %d transform calls", employee, transformCalls)
+	}
+
+	failExtract = true
+	if _, err := flow("Ada, 36"); err == nil || !strings.Contains(err.Error(), "stage 1") {
+		t.Fatalf("expected stage 1 error, got %v", err)
+	}
+	if transformCalls != 1 {
+		t.Errorf("expected transform to be skipped after extract failed, got %d calls", transformCalls)
+	}
+}
+
+func TestChainPropagatesContext(t *testing.T) {
+	type ctxKey struct{}
+	var seen []any
+	record := func(ctx context.Context, n int) (int, error) {
+		seen = append(seen, ctx.Value(ctxKey{}))
+		return n + 1, nil
+	}
+
+	flow := Chain(Chain(Stage[int, int](record), Stage[int, int](record)), Lift(func(n int) (string, error) {
+		return fmt.Sprintf("n=%d", n), nil
+	}))
+	ctx := context.WithValue(context.Background(), ctxKey{}, "req-1")
+	out, err := flow(ctx, 1)
+	if err != nil || out != "n=3" {
+		t.Fatalf("got %q, %v", out, err)
+	}
+	if len(seen) != 2 || seen[0] != "req-1" || seen[1] != "req-1" {
+		t.Errorf("expected context to reach every stage, got %v", seen)
+	}
+
+	// A failing stage short-circuits the rest
+	calls := 0
+	failing := func(ctx context.Context, n int) (int, error) { return 0, fmt.Errorf("boom") }
+	counting := func(ctx context.Context, n int) (int, error) { calls++; return n, nil }
+	if _, err := ComposeStages[int](failing, counting)(ctx, 1); err == nil || !strings.Contains(err.Error(), "stage 1") {
+		t.Errorf("expected stage 1 error, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected later stages to be skipped, got %d calls", calls)
+	}
+
+	// A cancelled context stops the chain before the next stage
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := Chain(Stage[int, int](counting), Stage[int, int](counting))(cancelled, 1); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected no stages to run on a cancelled context, got %d calls", calls)
+	}
+}
+
+func TestPipelineSpecRoundTrip(t *testing.T) {
+	registry := StepRegistry{
+		"trim": func() func(context.Context, any) (any, error) {
+			return func(ctx context.Context, input any) (any, error) {
+				return strings.TrimSpace(fmt.Sprint(input)), nil
+			}
+		},
+		"upper": func() func(context.Context, any) (any, error) {
+			return func(ctx context.Context, input any) (any, error) {
+				return strings.ToUpper(fmt.Sprint(input)), nil
+			}
+		},
+		"fail": func() func(context.Context, any) (any, error) {
+			return func(ctx context.Context, input any) (any, error) {
+				return nil, fmt.Errorf("boom")
+			}
+		},
+	}
+
+	original := NewPipeline("normalize", PipelineOptions{FailFast: true, Timeout: 30 * time.Second, MaxRetries: 2})
+	for _, name := range []string{"trim", "upper"} {
+		original.Add(name, registry[name]())
+	}
+	original.AddOptional("fail", registry["fail"]())
+
+	spec, err := original.MarshalSpec()
+	if err != nil {
+		t.Fatalf("MarshalSpec failed: %v", err)
+	}
+	loaded, err := LoadPipeline(spec, registry)
+	if err != nil {
+		t.Fatalf("LoadPipeline failed: %v", err)
+	}
+	if loaded.name != original.name || loaded.opts != original.opts {
+		t.Errorf("expected %q %+v, got %q %+v", original.name, original.opts, loaded.name, loaded.opts)
+	}
+
+	want := original.Execute(context.Background(), "  hello  ")
+	got := loaded.Execute(context.Background(), "  hello  ")
+	if got.Output != want.Output || got.Output != "HELLO" {
+		t.Errorf("expected output %v, got %v", want.Output, got.Output)
+	}
+	if got.StepsExecuted != want.StepsExecuted || got.StepsFailed != want.StepsFailed {
+		t.Errorf("expected %d executed/%d failed, got %d/%d", want.StepsExecuted, want.StepsFailed, got.StepsExecuted, got.StepsFailed)
+	}
+
+	delete(registry, "upper")
+	if _, err := LoadPipeline(spec, registry); err == nil || !strings.Contains(err.Error(), `"upper" is not registered`) {
+		t.Errorf("expected unregistered step error, got %v", err)
+	}
+}
@@ -160,12 +160,7 @@ func Pivot[T any, U any](input T, opts ...PivotOptions) (PivotResult[U], error)
 	result.Metadata = make(map[string]any)
 
 	// Apply defaults
-	opt := PivotOptions{
-		Aggregate:    "first",
-		Flatten:      false,
-		Mode:         types.TransformMode,
-		Intelligence: types.Smart,
-	}
+	opt := defaultPivotOptions()
 	if len(opts) > 0 {
 		opt = mergePivotOptions(opt, opts[0])
 	}
@@ -267,6 +262,7 @@ Preserve data semantics. Document any data loss.`,
 		Context:       ctx,
 		RequestID:     opt.RequestID,
 		CorrelationID: opt.CorrelationID,
+		Operation:     "pivot",
 	}
 
 	response, err := callLLM(ctx, systemPrompt, userPrompt, opOpts)
@@ -320,6 +316,16 @@ Preserve data semantics. Document any data loss.`,
 	return result, nil
 }
 
+// defaultPivotOptions returns the options Pivot runs with when none are given
+func defaultPivotOptions() PivotOptions {
+	return PivotOptions{
+		Aggregate:    "first",
+		Flatten:      false,
+		Mode:         types.TransformMode,
+		Intelligence: types.Smart,
+	}
+}
+
 // mergePivotOptions merges user options with defaults
 func mergePivotOptions(defaults, user PivotOptions) PivotOptions {
 	if user.PivotOn != nil {
@@ -0,0 +1,200 @@
+// package ops - Policy file loading for Audit and GuardPolicies
+package ops
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Policy is a named compliance rule, typically maintained in a policy file
+// and loaded with LoadPolicies
+type Policy struct {
+	// Name identifies the policy in findings (e.g., "pii-plaintext")
+	Name string `json:"name" yaml:"name"`
+
+	// Rule is the natural-language rule the data must satisfy
+	Rule string `json:"rule" yaml:"rule"`
+
+	// Severity (0.0-1.0) assigned to violations the LLM does not score
+	Severity float64 `json:"severity" yaml:"severity"`
+
+	// Category of violations (security, compliance, quality, etc.)
+	Category string `json:"category,omitempty" yaml:"category,omitempty"`
+
+	// Blocking marks violations as failing the audit regardless of severity
+	Blocking bool `json:"blocking,omitempty" yaml:"blocking,omitempty"`
+}
+
+// LoadPolicies reads a policy file in YAML (.yaml, .yml) or JSON (.json).
+// The file holds either a list of policies or an object with a "policies" list:
+//
+//	policies:
+//	  - name: pii-plaintext
+//	    rule: PII must not be stored in plain text
+//	    severity: 0.9
+//	    category: security
+//	    blocking: true
+//
+// Files with other extensions are parsed as JSON, then YAML.
+func LoadPolicies(path string) ([]Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+
+	var policies []Policy
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		policies, err = decodePolicies(data, json.Unmarshal)
+	case ".yaml", ".yml":
+		policies, err = decodePolicies(data, yaml.Unmarshal)
+	default:
+		policies, err = decodePolicies(data, json.Unmarshal)
+		if err != nil {
+			policies, err = decodePolicies(data, yaml.Unmarshal)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse policy file %s: %w", path, err)
+	}
+
+	if err := validatePolicies(policies); err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", path, err)
+	}
+	return policies, nil
+}
+
+// decodePolicies accepts a bare list or a {"policies": [...]} document
+func decodePolicies(data []byte, unmarshal func([]byte, any) error) ([]Policy, error) {
+	var list []Policy
+	if err := unmarshal(data, &list); err == nil {
+		return list, nil
+	}
+	var doc struct {
+		Policies []Policy `json:"policies" yaml:"policies"`
+	}
+	if err := unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc.Policies, nil
+}
+
+// validatePolicies requires a rule per policy, severities in range, and
+// unique names; unnamed policies are named after their position
+func validatePolicies(policies []Policy) error {
+	seen := make(map[string]bool, len(policies))
+	for i := range policies {
+		p := &policies[i]
+		p.Name = strings.TrimSpace(p.Name)
+		p.Rule = strings.TrimSpace(p.Rule)
+		if p.Rule == "" {
+			return fmt.Errorf("policy %d (%q) has no rule", i, p.Name)
+		}
+		if p.Severity < 0 || p.Severity > 1 {
+			return fmt.Errorf("policy %q severity %.2f must be between 0 and 1", p.Name, p.Severity)
+		}
+		if p.Name == "" {
+			p.Name = fmt.Sprintf("policy-%d", i+1)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("duplicate policy name %q", p.Name)
+		}
+		seen[p.Name] = true
+	}
+	return nil
+}
+
+// policyLines renders plain policy strings and structured policies as prompt bullets
+func policyLines(plain []string, set []Policy) []string {
+	lines := make([]string, 0, len(plain)+len(set))
+	for _, p := range plain {
+		lines = append(lines, fmt.Sprintf("- %s", p))
+	}
+	for _, p := range set {
+		var attrs []string
+		if p.Category != "" {
+			attrs = append(attrs, "category: "+p.Category)
+		}
+		if p.Severity > 0 {
+			attrs = append(attrs, fmt.Sprintf("severity: %.2f", p.Severity))
+		}
+		if p.Blocking {
+			attrs = append(attrs, "blocking")
+		}
+		line := fmt.Sprintf("- [%s] %s", p.Name, p.Rule)
+		if len(attrs) > 0 {
+			line += " (" + strings.Join(attrs, ", ") + ")"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// policySetNote tells the LLM how to attribute findings to named policies
+func policySetNote(set []Policy) string {
+	if len(set) == 0 {
+		return ""
+	}
+	return "\n\nFor violations of a bracketed policy, set \"policy\" to its name exactly (e.g., \"" + set[0].Name + "\")."
+}
+
+// findPolicy matches a finding's policy reference by name or rule text
+func findPolicy(set []Policy, ref string) (Policy, bool) {
+	ref = strings.Trim(strings.TrimSpace(ref), "[]")
+	if ref == "" {
+		return Policy{}, false
+	}
+	for _, p := range set {
+		if strings.EqualFold(p.Name, ref) || strings.EqualFold(p.Rule, ref) {
+			return p, true
+		}
+	}
+	return Policy{}, false
+}
+
+// applyPolicy fills a finding's category and severity from the policy it
+// violates and normalizes its policy reference to the policy name
+func applyPolicy(f AuditFinding, set []Policy) AuditFinding {
+	p, ok := findPolicy(set, f.Policy)
+	if !ok {
+		return f
+	}
+	f.Policy = p.Name
+	if f.Category == "" {
+		f.Category = p.Category
+	}
+	if f.Severity == 0 {
+		f.Severity = p.Severity
+	}
+	return f
+}
+
+// keepFinding reports whether a finding survives the severity threshold;
+// blocking policy violations are always kept
+func keepFinding(f AuditFinding, threshold float64, set []Policy) bool {
+	if f.Severity >= threshold {
+		return true
+	}
+	p, ok := findPolicy(set, f.Policy)
+	return ok && p.Blocking
+}
+
+// markBlockingViolations records violated blocking policies on the summary
+// and fails the audit when any are present
+func markBlockingViolations(summary *AuditSummary, findings []AuditFinding, set []Policy) {
+	seen := make(map[string]bool)
+	for _, f := range findings {
+		p, ok := findPolicy(set, f.Policy)
+		if !ok || !p.Blocking || seen[p.Name] {
+			continue
+		}
+		seen[p.Name] = true
+		summary.BlockingViolations = append(summary.BlockingViolations, p.Name)
+		summary.PassesAudit = false
+	}
+}
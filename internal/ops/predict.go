@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/monstercameron/schemaflow/internal/config"
@@ -192,6 +193,22 @@ type PredictionFactor struct {
 	Reasoning string  `json:"reasoning,omitempty"`
 }
 
+// PredictionDriver explains how much a factor moved the forecast
+type PredictionDriver struct {
+	// Factor is the driver, e.g. "holiday seasonality"
+	Factor string `json:"factor"`
+
+	// Impact is the signed share of the forecast change attributed to the
+	// factor, from -1 (pulls it down) to 1 (pushes it up)
+	Impact float64 `json:"impact"`
+
+	// Component is the forecast field the driver affects (structured predictions only)
+	Component string `json:"component,omitempty"`
+
+	// Explanation describes the mechanism
+	Explanation string `json:"explanation,omitempty"`
+}
+
 // PredictResult contains the results of prediction
 type PredictResult[T any] struct {
 	Prediction  T                    `json:"prediction"`
@@ -199,10 +216,14 @@ type PredictResult[T any] struct {
 	Interval    *PredictionInterval  `json:"interval,omitempty"`
 	Scenarios   []PredictionScenario `json:"scenarios,omitempty"`
 	Factors     []PredictionFactor   `json:"factors,omitempty"`
+	Drivers     []PredictionDriver   `json:"drivers,omitempty"`
 	Reasoning   string               `json:"reasoning,omitempty"`
 	Assumptions []string             `json:"assumptions,omitempty"`
 	Risks       []string             `json:"risks,omitempty"`
 	Metadata    map[string]any       `json:"metadata,omitempty"`
+
+	// MissingComponents lists struct fields of T the model left unforecast
+	MissingComponents []string `json:"missing_components,omitempty"`
 }
 
 // Predict forecasts or extrapolates based on patterns in historical data.
@@ -227,6 +248,10 @@ type PredictResult[T any] struct {
 //	result, err := Predict[RiskAssessment](data, NewPredictOptions().
 //	    WithFactors([]string{"market_trends", "seasonality", "competition"}).
 //	    WithAssumptions([]string{"no major policy changes"}))
+//
+// When T is a struct, each exported field is a forecast component (e.g.
+// {Revenue, Units, Confidence}); the model must forecast every one, and
+// Drivers attribute the forecast to factors per component.
 func Predict[T any](historicalData any, opts PredictOptions) (PredictResult[T], error) {
 	log := logger.GetLogger()
 	log.Debug("Starting predict operation")
@@ -240,6 +265,7 @@ func Predict[T any](historicalData any, opts PredictOptions) (PredictResult[T],
 	}
 
 	opt := opts.toOpOptions()
+	opt.Operation = "predict"
 
 	ctx := opt.Context
 	if ctx == nil {
@@ -300,10 +326,20 @@ func Predict[T any](historicalData any, opts PredictOptions) (PredictResult[T],
 		historyNote = fmt.Sprintf("\nFocus on the %s of historical data.", opts.HistoryWindow)
 	}
 
+	// Structured predictions forecast each field of T as a component
+	components := predictionComponents(reflect.TypeOf((*T)(nil)).Elem())
+	componentsNote := ""
+	if len(components) > 0 {
+		componentsNote = fmt.Sprintf("\n\n\"prediction\" must be a JSON object matching this schema, with a forecast for every component (%s):\n%s",
+			strings.Join(components, ", "), GenerateTypeSchema(reflect.TypeOf((*T)(nil)).Elem()))
+	}
+
 	systemPrompt := fmt.Sprintf(`You are an expert at forecasting and prediction. Analyze the data and make predictions.
 
 Prediction horizon: %s
-Method: %s%s%s%s%s%s%s
+Method: %s%s%s%s%s%s%s%s
+
+List at least one driver explaining the forecast. "impact" is the signed share of the forecast change attributed to the factor (-1 to 1); "component" names the forecast field it affects, if any.
 
 Return a JSON object with:
 {
@@ -331,10 +367,18 @@ Return a JSON object with:
       "reasoning": "Why this factor matters"
     }
   ],
+  "drivers": [
+    {
+      "factor": "Factor name",
+      "impact": 0.4,
+      "component": "field name",
+      "explanation": "How this factor moves the forecast"
+    }
+  ],
   "reasoning": "Explanation of prediction logic",
   "assumptions": ["assumptions made"],
   "risks": ["potential risks to prediction accuracy"]
-}`, opts.Horizon, methodDesc, factorsDesc, assumptionsDesc, intervalNote, scenariosNote, reasoningNote, historyNote)
+}`, opts.Horizon, methodDesc, factorsDesc, assumptionsDesc, intervalNote, scenariosNote, reasoningNote, historyNote, componentsNote)
 
 	userPrompt := fmt.Sprintf("Based on this historical data, predict %s:\n\n%s", opts.Horizon, string(dataJSON))
 
@@ -350,9 +394,42 @@ Return a JSON object with:
 		return result, fmt.Errorf("failed to parse prediction result: %w", err)
 	}
 
+	if len(components) > 0 {
+		var raw struct {
+			Prediction json.RawMessage `json:"prediction"`
+		}
+		if err := ParseJSON(response, &raw); err == nil {
+			result.MissingComponents = missingRequiredFields(string(raw.Prediction), components)
+		}
+		if len(result.MissingComponents) > 0 {
+			log.Warn("Predict operation left components unforecast", "missing", result.MissingComponents)
+		}
+	}
+
 	log.Debug("Predict operation succeeded",
 		"confidence", result.Confidence,
 		"scenarioCount", len(result.Scenarios),
-		"factorCount", len(result.Factors))
+		"factorCount", len(result.Factors),
+		"driverCount", len(result.Drivers))
 	return result, nil
 }
+
+// predictionComponents returns the JSON names of a struct prediction's fields
+func predictionComponents(targetType reflect.Type) []string {
+	if targetType.Kind() == reflect.Ptr {
+		targetType = targetType.Elem()
+	}
+	if targetType.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var names []string
+	for i := 0; i < targetType.NumField(); i++ {
+		field := targetType.Field(i)
+		if !field.IsExported() || field.Tag.Get("json") == "-" {
+			continue
+		}
+		names = append(names, jsonFieldName(field))
+	}
+	return names
+}
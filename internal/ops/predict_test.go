@@ -1,7 +1,11 @@
 package ops
 
 import (
+	"context"
+	"strings"
 	"testing"
+
+	"github.com/monstercameron/schemaflow/internal/types"
 )
 
 func TestPredictOptions(t *testing.T) {
@@ -145,3 +149,69 @@ func TestPredict(t *testing.T) {
 		}
 	})
 }
+
+func TestPredictStructuredComponents(t *testing.T) {
+	type Forecast struct {
+		Revenue    float64 `json:"revenue"`
+		Units      int     `json:"units"`
+		Confidence float64 `json:"confidence"`
+	}
+
+	var systemPrompt string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		systemPrompt = system
+		return `{
+			"prediction": {"revenue": 125000, "units": 5100, "confidence": 0.7},
+			"confidence": 0.7,
+			"drivers": [
+				{"factor": "holiday seasonality", "impact": 0.6, "component": "units", "explanation": "Q4 demand spike"},
+				{"factor": "price increase", "impact": -0.2, "component": "revenue"}
+			]
+		}`, nil
+	})
+	defer setupMockClient()
+
+	history := []map[string]any{
+		{"quarter": "Q2", "revenue": 98000, "units": 4000},
+		{"quarter": "Q3", "revenue": 104000, "units": 4300},
+	}
+	result, err := Predict[Forecast](history, NewPredictOptions().WithHorizon("next_quarter"))
+	if err != nil {
+		t.Fatalf("Predict() error = %v", err)
+	}
+
+	for _, component := range []string{"revenue", "units", "confidence"} {
+		if !strings.Contains(systemPrompt, component) {
+			t.Errorf("prompt missing component %q", component)
+		}
+	}
+	if result.Prediction.Revenue != 125000 || result.Prediction.Units != 5100 || result.Prediction.Confidence != 0.7 {
+		t.Errorf("Prediction = %+v, want all components populated", result.Prediction)
+	}
+	if len(result.MissingComponents) != 0 {
+		t.Errorf("MissingComponents = %v, want none", result.MissingComponents)
+	}
+	if len(result.Drivers) == 0 || result.Drivers[0].Factor == "" || result.Drivers[0].Impact == 0 {
+		t.Fatalf("Drivers = %+v, want at least one populated driver", result.Drivers)
+	}
+}
+
+func TestPredictReportsMissingComponents(t *testing.T) {
+	type Forecast struct {
+		Revenue float64 `json:"revenue"`
+		Units   int     `json:"units"`
+	}
+
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		return `{"prediction": {"revenue": 125000}, "confidence": 0.5, "drivers": [{"factor": "trend", "impact": 0.3}]}`, nil
+	})
+	defer setupMockClient()
+
+	result, err := Predict[Forecast]([]int{1, 2, 3}, NewPredictOptions())
+	if err != nil {
+		t.Fatalf("Predict() error = %v", err)
+	}
+	if len(result.MissingComponents) != 1 || result.MissingComponents[0] != "units" {
+		t.Errorf("MissingComponents = %v, want [units]", result.MissingComponents)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"reflect"
 	"strings"
 	"sync"
@@ -25,16 +26,136 @@ type Decision[T any] struct {
 
 // DecisionResult contains the result of a decision operation
 type DecisionResult struct {
-	SelectedIndex int
-	Explanation   string
-	Confidence    float64
-	Alternatives  []int
+	SelectedIndex int     `json:"selected_index"`
+	Explanation   string  `json:"explanation"`
+	Confidence    float64 `json:"confidence"`
+	Alternatives  []int   `json:"alternatives,omitempty"`
+
+	// Scores holds the model's 0.0-1.0 score for each decision, when reported
+	Scores []float64 `json:"scores,omitempty"`
+
+	// Tied lists the decisions scoring within the tie epsilon of the best
+	// one, including it; empty when there was no tie
+	Tied []int `json:"tied,omitempty"`
+}
+
+// TiePolicy decides the outcome when several decisions score within the tie
+// epsilon of each other
+type TiePolicy string
+
+const (
+	// TiePolicyFirst picks the tied decision that comes first in the list
+	TiePolicyFirst TiePolicy = "first"
+
+	// TiePolicyError returns a TieError naming the tied decisions
+	TiePolicyError TiePolicy = "error"
+
+	// TiePolicyEscalate asks the model again, at a higher intelligence level, to
+	// choose between the tied decisions only
+	TiePolicyEscalate TiePolicy = "escalate"
+)
+
+// DecideOptions configures DecideWithOptions
+type DecideOptions struct {
+	CommonOptions
+	types.OpOptions
+
+	// TiePolicy controls the outcome of a near-tie
+	TiePolicy TiePolicy
+
+	// TieEpsilon is the score difference (0.0-1.0) within which decisions tie
+	TieEpsilon float64
+}
+
+// NewDecideOptions creates DecideOptions with defaults
+func NewDecideOptions() DecideOptions {
+	return DecideOptions{
+		CommonOptions: CommonOptions{
+			Mode:         types.TransformMode,
+			Intelligence: types.Smart,
+		},
+		TiePolicy:  TiePolicyFirst,
+		TieEpsilon: 0.05,
+	}
+}
+
+// Validate validates DecideOptions
+func (d DecideOptions) Validate() error {
+	if err := d.CommonOptions.Validate(); err != nil {
+		return err
+	}
+	switch d.TiePolicy {
+	case TiePolicyFirst, TiePolicyError, TiePolicyEscalate:
+	default:
+		return fmt.Errorf("invalid tie policy: %q", d.TiePolicy)
+	}
+	if d.TieEpsilon < 0 || d.TieEpsilon > 1 {
+		return fmt.Errorf("tie epsilon must be between 0 and 1, got %f", d.TieEpsilon)
+	}
+	return nil
+}
+
+// WithTiePolicy sets how near-ties within epsilon are resolved
+func (d DecideOptions) WithTiePolicy(policy TiePolicy, epsilon float64) DecideOptions {
+	d.TiePolicy = policy
+	d.TieEpsilon = epsilon
+	return d
+}
+
+// WithSteering sets the steering prompt
+func (d DecideOptions) WithSteering(steering string) DecideOptions {
+	d.CommonOptions = d.CommonOptions.WithSteering(steering)
+	return d
 }
 
-// Decide makes a decision based on conditions and context
+// WithIntelligence sets the intelligence level
+func (d DecideOptions) WithIntelligence(intelligence types.Speed) DecideOptions {
+	d.CommonOptions = d.CommonOptions.WithIntelligence(intelligence)
+	return d
+}
+
+// WithContext sets the context
+func (d DecideOptions) WithContext(ctx context.Context) DecideOptions {
+	d.CommonOptions = d.CommonOptions.WithContext(ctx)
+	return d
+}
+
+func (d DecideOptions) toOpOptions() types.OpOptions {
+	return d.CommonOptions.toOpOptions()
+}
+
+// Decide makes a decision based on conditions and context. Decisions the
+// model scores equally go to the first of them; use DecideWithOptions to
+// choose another tie policy.
 func Decide[T any](ctx any, decisions []Decision[T], opts ...types.OpOptions) (T, DecisionResult, error) {
+	return decide(ctx, decisions, applyDefaults(opts...), TiePolicyFirst, 0)
+}
+
+// DecideWithOptions is Decide with a configurable tie policy: when decisions
+// score within opts.TieEpsilon of the best one, opts.TiePolicy picks the
+// first of them, returns a TieError, or escalates to a closer look.
+//
+// Example:
+//
+//	department, result, err := DecideWithOptions(ticket, departments,
+//	    NewDecideOptions().WithTiePolicy(TiePolicyError, 0.05))
+//	var tie types.TieError
+//	if errors.As(err, &tie) {
+//	    // route to a human: tie.Tied lists the candidate departments
+//	}
+func DecideWithOptions[T any](ctx any, decisions []Decision[T], opts DecideOptions) (T, DecisionResult, error) {
+	if err := opts.Validate(); err != nil {
+		var zero T
+		return zero, DecisionResult{SelectedIndex: -1}, fmt.Errorf("invalid options: %w", err)
+	}
+	return decide(ctx, decisions, opts.toOpOptions(), opts.TiePolicy, opts.TieEpsilon)
+}
+
+// decide runs a decision, resolving near-ties within epsilon by policy
+func decide[T any](ctx any, decisions []Decision[T], opt types.OpOptions, policy TiePolicy, epsilon float64) (T, DecisionResult, error) {
 	log := logger.GetLogger()
 	log.Debug("Starting decide operation", "decisionsCount", len(decisions))
+	opt.Operation = "decide"
 
 	var zero T
 	result := DecisionResult{SelectedIndex: -1}
@@ -55,8 +176,11 @@ func Decide[T any](ctx any, decisions []Decision[T], opts ...types.OpOptions) (T
 	}
 
 	// If no programmatic condition matches, use LLM for decision
-	opt := applyDefaults(opts...)
-	llmCtx, cancel := context.WithTimeout(context.Background(), config.GetTimeout())
+	parent := opt.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+	llmCtx, cancel := context.WithTimeout(parent, config.GetTimeout())
 	defer cancel()
 
 	// Prepare decision options for LLM
@@ -71,7 +195,8 @@ Return a JSON object with:
   "selected": <index>,
   "explanation": "reason for selection",
   "confidence": 0.0-1.0,
-  "alternatives": [other viable option indices]
+  "alternatives": [other viable option indices],
+  "scores": [0.0-1.0 fit of each option, in option order]
 }`
 
 	userPrompt := fmt.Sprintf(`Context:
@@ -92,34 +217,32 @@ Choose the best option based on the context.`, ctx, strings.Join(options, "\n"))
 		return decisions[0].Value, result, nil
 	}
 
-	// Clean up response - remove markdown code blocks if present
-	response = strings.TrimSpace(response)
-	if strings.HasPrefix(response, "```json") {
-		response = strings.TrimPrefix(response, "```json")
-		response = strings.TrimSuffix(response, "```")
-		response = strings.TrimSpace(response)
-	} else if strings.HasPrefix(response, "```") {
-		response = strings.TrimPrefix(response, "```")
-		response = strings.TrimSuffix(response, "```")
-		response = strings.TrimSpace(response)
-	}
-
 	// Parse LLM response
 	var llmResult struct {
-		Selected     int     `json:"selected"`
-		Explanation  string  `json:"explanation"`
-		Confidence   float64 `json:"confidence"`
-		Alternatives []int   `json:"alternatives"`
+		Selected     int       `json:"selected"`
+		Explanation  string    `json:"explanation"`
+		Confidence   float64   `json:"confidence"`
+		Alternatives []int     `json:"alternatives"`
+		Scores       []float64 `json:"scores"`
 	}
 
-	if err := json.Unmarshal([]byte(response), &llmResult); err == nil {
+	if err := json.Unmarshal([]byte(cleanJSON(response)), &llmResult); err == nil {
 		if llmResult.Selected >= 0 && llmResult.Selected < len(decisions) {
 			result.SelectedIndex = llmResult.Selected
 			result.Explanation = llmResult.Explanation
 			result.Confidence = llmResult.Confidence
 			result.Alternatives = llmResult.Alternatives
-			log.Debug("Decide operation succeeded", "selectedIndex", llmResult.Selected, "confidence", llmResult.Confidence)
-			return decisions[llmResult.Selected].Value, result, nil
+			if len(llmResult.Scores) == len(decisions) {
+				result.Scores = llmResult.Scores
+				result.Tied = tiedDecisions(llmResult.Scores, epsilon)
+			}
+			if len(result.Tied) > 1 {
+				if err := resolveTie(llmCtx, ctx, decisions, &result, opt, policy); err != nil {
+					return zero, result, err
+				}
+			}
+			log.Debug("Decide operation succeeded", "selectedIndex", result.SelectedIndex, "confidence", result.Confidence)
+			return decisions[result.SelectedIndex].Value, result, nil
 		}
 	}
 
@@ -131,16 +254,100 @@ Choose the best option based on the context.`, ctx, strings.Join(options, "\n"))
 	return decisions[0].Value, result, nil
 }
 
+// tiedDecisions returns the indices of the scores within epsilon of the
+// highest, or nil when only one decision is that close
+func tiedDecisions(scores []float64, epsilon float64) []int {
+	best := scores[0]
+	for _, score := range scores[1:] {
+		best = max(best, score)
+	}
+	var tied []int
+	for i, score := range scores {
+		if best-score <= epsilon {
+			tied = append(tied, i)
+		}
+	}
+	if len(tied) < 2 {
+		return nil
+	}
+	return tied
+}
+
+// resolveTie applies policy to a result whose Tied decisions scored alike
+func resolveTie[T any](llmCtx context.Context, input any, decisions []Decision[T], result *DecisionResult, opt types.OpOptions, policy TiePolicy) error {
+	log := logger.GetLogger()
+	log.Info("Decide operation found a tie", "tied", result.Tied, "policy", string(policy))
+
+	switch policy {
+	case TiePolicyError:
+		return types.TieError{Tied: result.Tied, Scores: result.Scores}
+	case TiePolicyEscalate:
+		if index, explanation, ok := escalateTie(llmCtx, input, decisions, result.Tied, opt); ok {
+			result.SelectedIndex = index
+			result.Explanation = explanation
+			return nil
+		}
+		log.Warn("Decide tie escalation failed, using the first tied decision")
+	}
+	result.SelectedIndex = result.Tied[0]
+	result.Explanation = fmt.Sprintf("Tie between options %v; chose the first listed. %s", result.Tied, result.Explanation)
+	return nil
+}
+
+// escalateTie asks the model, one intelligence level up, to choose between
+// the tied decisions only
+func escalateTie[T any](llmCtx context.Context, input any, decisions []Decision[T], tied []int, opt types.OpOptions) (int, string, bool) {
+	var options []string
+	for _, i := range tied {
+		options = append(options, fmt.Sprintf("%d. %s", i, decisions[i].Description))
+	}
+
+	systemPrompt := `You are a decision-making expert. A first pass could not separate these options; look closely at the context and choose the single best one.
+Return a JSON object with:
+{
+  "selected": <index>,
+  "explanation": "what separates the selected option from the others"
+}`
+	userPrompt := fmt.Sprintf("Context:\n%v\n\nTied options:\n%s", input, strings.Join(options, "\n"))
+
+	escalated := opt
+	if escalated.Intelligence > types.Smart {
+		escalated.Intelligence--
+	}
+	response, err := callLLM(llmCtx, systemPrompt, userPrompt, escalated)
+	if err != nil {
+		return 0, "", false
+	}
+	var parsed struct {
+		Selected    int    `json:"selected"`
+		Explanation string `json:"explanation"`
+	}
+	if err := ParseJSON(response, &parsed); err != nil {
+		return 0, "", false
+	}
+	for _, i := range tied {
+		if i == parsed.Selected {
+			return i, parsed.Explanation, true
+		}
+	}
+	return 0, "", false
+}
+
 // GuardResult represents the result of a guard check
 type GuardResult struct {
-	CanProceed   bool
-	FailedChecks []string
-	Suggestions  []string
-	RetryAfter   *time.Duration
+	CanProceed   bool           `json:"can_proceed"`
+	FailedChecks []string       `json:"failed_checks,omitempty"`
+	Suggestions  []string       `json:"suggestions,omitempty"`
+	RetryAfter   *time.Duration `json:"retry_after,omitempty"` // nanoseconds in JSON
 }
 
 // Guard checks if conditions are met before proceeding
 func Guard[T any](state T, checks ...func(T) (bool, string)) GuardResult {
+	return guard(context.Background(), state, checks...)
+}
+
+// guard is Guard with the suggestion call made under ctx
+func guard[T any](ctx context.Context, state T, checks ...func(T) (bool, string)) GuardResult {
 	log := logger.GetLogger()
 	log.Debug("Starting guard operation", "checksCount", len(checks))
 
@@ -160,13 +367,13 @@ func Guard[T any](state T, checks ...func(T) (bool, string)) GuardResult {
 
 	// Generate suggestions for failed checks using LLM if available
 	if !result.CanProceed && len(result.FailedChecks) > 0 {
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 		defer cancel()
 
 		systemPrompt := "You are a helpful assistant. Suggest how to fix these issues."
 		userPrompt := fmt.Sprintf("Issues:\n%s", strings.Join(result.FailedChecks, "\n"))
 
-		opt := types.OpOptions{Intelligence: types.Quick}
+		opt := types.OpOptions{Intelligence: types.Quick, Operation: "guard"}
 		response, err := callLLM(ctx, systemPrompt, userPrompt, opt)
 		if err != nil {
 			log.Warn("Guard operation LLM call failed, proceeding without suggestions", "error", err)
@@ -179,6 +386,49 @@ func Guard[T any](state T, checks ...func(T) (bool, string)) GuardResult {
 	return result
 }
 
+// GuardPolicies audits state against policies and blocks when a blocking
+// policy is violated. Findings for non-blocking policies become suggestions.
+func GuardPolicies[T any](state T, policies []Policy, opts ...AuditOptions) (GuardResult, error) {
+	log := logger.GetLogger()
+	log.Debug("Starting policy guard operation", "policiesCount", len(policies))
+
+	result := GuardResult{
+		CanProceed:   true,
+		FailedChecks: []string{},
+		Suggestions:  []string{},
+	}
+	if len(policies) == 0 {
+		return result, nil
+	}
+
+	var opt AuditOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	opt.PolicySet = policies
+	opt.Deep = true
+
+	audit, err := Audit(state, opt)
+	if err != nil {
+		log.Error("Policy guard operation failed", "error", err)
+		return result, fmt.Errorf("policy guard failed: %w", err)
+	}
+
+	for _, f := range audit.Findings {
+		p, ok := findPolicy(policies, f.Policy)
+		if ok && p.Blocking {
+			result.CanProceed = false
+			result.FailedChecks = append(result.FailedChecks, fmt.Sprintf("%s: %s", p.Name, f.Issue))
+		}
+		if f.Recommendation != "" {
+			result.Suggestions = append(result.Suggestions, f.Recommendation)
+		}
+	}
+
+	log.Debug("Policy guard operation succeeded", "canProceed", result.CanProceed, "failedChecks", len(result.FailedChecks))
+	return result, nil
+}
+
 // StateMachine represents a finite state machine
 type StateMachine[S comparable, E any] struct {
 	Current     S
@@ -388,12 +638,19 @@ func Try[T any](operation func() (T, error)) (result T, err error) {
 	return operation()
 }
 
-// Workflow represents a complex multi-step workflow
+// Workflow represents a complex multi-step workflow. Steps form a dependency
+// graph: a step starts once every step it depends on has completed, and up
+// to MaxParallel ready steps run at a time.
 type Workflow struct {
 	Name  string
 	Steps []WorkflowStep
 	State map[string]any
-	mu    sync.RWMutex
+
+	// MaxParallel bounds how many ready steps run concurrently (default 1,
+	// which runs steps one at a time in the order they were added)
+	MaxParallel int
+
+	mu sync.RWMutex
 }
 
 // WorkflowStep represents a step in a workflow
@@ -406,63 +663,227 @@ type WorkflowStep struct {
 	Dependencies []string // Names of steps that must complete first
 }
 
+// StepOption configures a step added with Workflow.AddStep
+type StepOption func(*WorkflowStep)
+
+// DependsOn declares the steps that must complete before a step starts
+func DependsOn(names ...string) StepOption {
+	return func(step *WorkflowStep) {
+		step.Dependencies = append(step.Dependencies, names...)
+	}
+}
+
 // NewWorkflow creates a new workflow
 func NewWorkflow(name string) *Workflow {
 	return &Workflow{
-		Name:  name,
-		Steps: []WorkflowStep{},
-		State: make(map[string]any),
+		Name:        name,
+		Steps:       []WorkflowStep{},
+		State:       make(map[string]any),
+		MaxParallel: 1,
+	}
+}
+
+// AddStep adds a named step running op once its dependencies complete.
+//
+// Example:
+//
+//	wf := NewWorkflow("enrich").WithMaxParallel(4)
+//	wf.AddStep("company", lookupCompany)
+//	wf.AddStep("contacts", lookupContacts)
+//	wf.AddStep("report", buildReport, DependsOn("company", "contacts"))
+func (w *Workflow) AddStep(name string, op func(context.Context, map[string]any) error, opts ...StepOption) *Workflow {
+	step := WorkflowStep{Name: name, Execute: op}
+	for _, opt := range opts {
+		opt(&step)
 	}
+	return w.Add(step)
 }
 
-// AddStep adds a step to the workflow
-func (w *Workflow) AddStep(step WorkflowStep) *Workflow {
+// Add adds a fully specified step, e.g. one with compensation or retries
+func (w *Workflow) Add(step WorkflowStep) *Workflow {
 	w.Steps = append(w.Steps, step)
 	return w
 }
 
-// Execute runs the workflow
+// WithMaxParallel sets how many independent steps may run at once
+func (w *Workflow) WithMaxParallel(n int) *Workflow {
+	w.MaxParallel = n
+	return w
+}
+
+// Execute runs the workflow's steps in dependency order, running independent
+// steps concurrently up to MaxParallel. Each step works on a copy of the
+// state whose changes are merged back when it completes, so concurrent steps
+// should write distinct keys. When a step fails no further steps start and,
+// once running steps finish, completed steps are compensated in reverse
+// order of completion.
 func (w *Workflow) Execute(ctx context.Context) error {
-	completed := make(map[string]bool)
+	order, err := w.plan()
+	if err != nil {
+		return err
+	}
 
-	for _, step := range w.Steps {
-		// Check dependencies
+	limit := max(w.MaxParallel, 1)
+	remaining := make(map[string]int, len(w.Steps))
+	dependents := make(map[string][]int, len(w.Steps))
+	for _, i := range order {
+		step := w.Steps[i]
+		remaining[step.Name] = len(step.Dependencies)
 		for _, dep := range step.Dependencies {
-			if !completed[dep] {
-				return fmt.Errorf("dependency %s not met for step %s", dep, step.Name)
-			}
+			dependents[dep] = append(dependents[dep], i)
+		}
+	}
+
+	type outcome struct {
+		index int
+		err   error
+	}
+	done := make(chan outcome)
+	var (
+		ready     []int
+		completed []int
+		failed    error
+		running   int
+	)
+	for _, i := range order {
+		if remaining[w.Steps[i].Name] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	for {
+		if failed != nil {
+			ready = nil
+		}
+		for running < limit && len(ready) > 0 {
+			index := ready[0]
+			ready = ready[1:]
+			running++
+			go func() {
+				done <- outcome{index: index, err: w.runStep(ctx, w.Steps[index])}
+			}()
+		}
+		if running == 0 {
+			break
 		}
 
-		// Execute step with retry
-		attempts := 1
-		if step.CanRetry && step.MaxRetries > 0 {
-			attempts = step.MaxRetries
+		result := <-done
+		running--
+		step := w.Steps[result.index]
+		if result.err != nil {
+			if failed == nil {
+				failed = fmt.Errorf("step %s failed: %w", step.Name, result.err)
+			}
+			continue
 		}
+		completed = append(completed, result.index)
+		for _, next := range dependents[step.Name] {
+			name := w.Steps[next].Name
+			if remaining[name]--; remaining[name] == 0 {
+				ready = append(ready, next)
+			}
+		}
+	}
 
-		var stepErr error
-		for attempt := 0; attempt < attempts; attempt++ {
-			stepErr = step.Execute(ctx, w.State)
-			if stepErr == nil {
-				completed[step.Name] = true
-				break
+	if failed != nil {
+		for i := len(completed) - 1; i >= 0; i-- {
+			if compensate := w.Steps[completed[i]].Compensate; compensate != nil {
+				_ = compensate(w.State)
 			}
+		}
+		return failed
+	}
+	return nil
+}
 
-			if attempt < attempts-1 {
-				time.Sleep(time.Duration(attempt+1) * time.Second)
+// plan validates the step graph and returns step indexes in a valid
+// execution order, preferring the order in which steps were added
+func (w *Workflow) plan() ([]int, error) {
+	index := make(map[string]int, len(w.Steps))
+	for i, step := range w.Steps {
+		if _, exists := index[step.Name]; exists {
+			return nil, fmt.Errorf("duplicate step name %s", step.Name)
+		}
+		index[step.Name] = i
+	}
+	for _, step := range w.Steps {
+		for _, dep := range step.Dependencies {
+			if _, ok := index[dep]; !ok {
+				return nil, fmt.Errorf("dependency %s not met for step %s", dep, step.Name)
 			}
 		}
+	}
 
-		if stepErr != nil {
-			// Execute compensation for completed steps in reverse order
-			for i := len(w.Steps) - 1; i >= 0; i-- {
-				if completed[w.Steps[i].Name] && w.Steps[i].Compensate != nil {
-					_ = w.Steps[i].Compensate(w.State)
+	order := make([]int, 0, len(w.Steps))
+	placed := make(map[string]bool, len(w.Steps))
+	for len(order) < len(w.Steps) {
+		progressed := false
+		for i, step := range w.Steps {
+			if placed[step.Name] {
+				continue
+			}
+			satisfied := true
+			for _, dep := range step.Dependencies {
+				satisfied = satisfied && placed[dep]
+			}
+			if satisfied {
+				placed[step.Name] = true
+				order = append(order, i)
+				progressed = true
+			}
+		}
+		if !progressed {
+			var cycle []string
+			for _, step := range w.Steps {
+				if !placed[step.Name] {
+					cycle = append(cycle, step.Name)
 				}
 			}
-			return fmt.Errorf("step %s failed: %w", step.Name, stepErr)
+			return nil, fmt.Errorf("dependency cycle between steps %s", strings.Join(cycle, ", "))
 		}
 	}
+	return order, nil
+}
+
+// runStep executes step with retries against a copy of the state, merging
+// the keys it added, changed or removed back into the workflow state
+func (w *Workflow) runStep(ctx context.Context, step WorkflowStep) error {
+	w.mu.RLock()
+	before := maps.Clone(w.State)
+	w.mu.RUnlock()
+
+	attempts := 1
+	if step.CanRetry && step.MaxRetries > 0 {
+		attempts = step.MaxRetries
+	}
 
+	var state map[string]any
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		state = maps.Clone(before)
+		if err = step.Execute(ctx, state); err == nil {
+			break
+		}
+		if attempt < attempts-1 {
+			time.Sleep(time.Duration(attempt+1) * time.Second)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for key, value := range state {
+		if old, ok := before[key]; !ok || !reflect.DeepEqual(old, value) {
+			w.State[key] = value
+		}
+	}
+	for key := range before {
+		if _, ok := state[key]; !ok {
+			delete(w.State, key)
+		}
+	}
 	return nil
 }
 
@@ -2,10 +2,15 @@ package ops
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/monstercameron/schemaflow/internal/llm"
+	"github.com/monstercameron/schemaflow/internal/types"
 )
 
 func TestDecide(t *testing.T) {
@@ -536,7 +541,7 @@ func TestWorkflow(t *testing.T) {
 		step1Executed := false
 		step2Executed := false
 
-		wf.AddStep(WorkflowStep{
+		wf.Add(WorkflowStep{
 			Name: "step1",
 			Execute: func(ctx context.Context, state map[string]any) error {
 				step1Executed = true
@@ -545,7 +550,7 @@ func TestWorkflow(t *testing.T) {
 			},
 		})
 
-		wf.AddStep(WorkflowStep{
+		wf.Add(WorkflowStep{
 			Name:         "step2",
 			Dependencies: []string{"step1"},
 			Execute: func(ctx context.Context, state map[string]any) error {
@@ -586,7 +591,7 @@ func TestWorkflow(t *testing.T) {
 
 		step1Compensated := false
 
-		wf.AddStep(WorkflowStep{
+		wf.Add(WorkflowStep{
 			Name: "step1",
 			Execute: func(ctx context.Context, state map[string]any) error {
 				return nil
@@ -597,7 +602,7 @@ func TestWorkflow(t *testing.T) {
 			},
 		})
 
-		wf.AddStep(WorkflowStep{
+		wf.Add(WorkflowStep{
 			Name: "step2",
 			Execute: func(ctx context.Context, state map[string]any) error {
 				return fmt.Errorf("step2 failed")
@@ -620,7 +625,7 @@ func TestWorkflow(t *testing.T) {
 
 		attempts := 0
 
-		wf.AddStep(WorkflowStep{
+		wf.Add(WorkflowStep{
 			Name:       "retry-step",
 			CanRetry:   true,
 			MaxRetries: 3,
@@ -644,10 +649,58 @@ func TestWorkflow(t *testing.T) {
 		}
 	})
 
+	t.Run("IndependentStepsRunConcurrently", func(t *testing.T) {
+		wf := NewWorkflow("test-workflow").WithMaxParallel(2)
+
+		// Each fetch waits for the other to start, so a sequential run times out
+		var started sync.WaitGroup
+		started.Add(2)
+		fetch := func(key string) func(context.Context, map[string]any) error {
+			return func(ctx context.Context, state map[string]any) error {
+				started.Done()
+				waited := make(chan struct{})
+				go func() { started.Wait(); close(waited) }()
+				select {
+				case <-waited:
+				case <-time.After(2 * time.Second):
+					return fmt.Errorf("%s ran alone", key)
+				}
+				state[key] = key + "-data"
+				return nil
+			}
+		}
+
+		var report string
+		wf.AddStep("report", func(ctx context.Context, state map[string]any) error {
+			report = fmt.Sprintf("%v+%v", state["company"], state["contacts"])
+			return nil
+		}, DependsOn("company", "contacts"))
+		wf.AddStep("company", fetch("company"))
+		wf.AddStep("contacts", fetch("contacts"))
+
+		if err := wf.Execute(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if report != "company-data+contacts-data" {
+			t.Errorf("Expected report to see both inputs, got %q", report)
+		}
+	})
+
+	t.Run("WorkflowDependencyCycle", func(t *testing.T) {
+		noop := func(ctx context.Context, state map[string]any) error { return nil }
+		wf := NewWorkflow("test-workflow").
+			AddStep("a", noop, DependsOn("b")).
+			AddStep("b", noop, DependsOn("a"))
+
+		if err := wf.Execute(context.Background()); err == nil || !strings.Contains(err.Error(), "cycle") {
+			t.Errorf("Expected cycle error, got %v", err)
+		}
+	})
+
 	t.Run("WorkflowDependencyCheck", func(t *testing.T) {
 		wf := NewWorkflow("test-workflow")
 
-		wf.AddStep(WorkflowStep{
+		wf.Add(WorkflowStep{
 			Name:         "dependent",
 			Dependencies: []string{"missing"},
 			Execute: func(ctx context.Context, state map[string]any) error {
@@ -666,3 +719,112 @@ func TestWorkflow(t *testing.T) {
 		}
 	})
 }
+
+func TestDecideWithOptionsHonorsTiePolicy(t *testing.T) {
+	departments := []Decision[string]{
+		{Value: "billing", Description: "Handles invoices and refunds"},
+		{Value: "accounts", Description: "Handles invoices and account changes"},
+		{Value: "sales", Description: "Handles upgrades and new plans"},
+	}
+	const firstPass = `{"selected": 1, "explanation": "invoice question", "confidence": 0.9, "scores": [0.9, 0.88, 0.2]}`
+
+	var prompts []string
+	var intelligence []types.Speed
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		prompts = append(prompts, user)
+		intelligence = append(intelligence, opts.Intelligence)
+		if strings.Contains(system, "could not separate") {
+			return `{"selected": 1, "explanation": "account changes are involved"}`, nil
+		}
+		return firstPass, nil
+	})
+	defer setupMockClient()
+
+	ticket := "My invoice shows the wrong company name"
+
+	value, result, err := DecideWithOptions(ticket, departments, NewDecideOptions().WithTiePolicy(TiePolicyFirst, 0.05))
+	if err != nil || value != "billing" || result.SelectedIndex != 0 {
+		t.Fatalf("first policy: got %q (index %d), %v; want billing", value, result.SelectedIndex, err)
+	}
+	if len(result.Tied) != 2 || result.Tied[0] != 0 || result.Tied[1] != 1 {
+		t.Errorf("Tied = %v, want [0 1]", result.Tied)
+	}
+
+	_, _, err = DecideWithOptions(ticket, departments, NewDecideOptions().WithTiePolicy(TiePolicyError, 0.05))
+	var tie types.TieError
+	if !errors.As(err, &tie) || len(tie.Tied) != 2 {
+		t.Fatalf("error policy: got %v, want a TieError for two departments", err)
+	}
+
+	prompts, intelligence = nil, nil
+	opts := NewDecideOptions().WithIntelligence(types.Fast).WithTiePolicy(TiePolicyEscalate, 0.05)
+	value, result, err = DecideWithOptions(ticket, departments, opts)
+	if err != nil || value != "accounts" || result.SelectedIndex != 1 {
+		t.Fatalf("escalate policy: got %q (index %d), %v; want accounts", value, result.SelectedIndex, err)
+	}
+	if len(prompts) != 2 || strings.Contains(prompts[1], "upgrades") || intelligence[1] != types.Smart {
+		t.Errorf("escalation should ask about the tied departments only at Smart, got %v at %v", prompts, intelligence)
+	}
+
+	// Scores further apart than epsilon are not a tie
+	_, result, err = DecideWithOptions(ticket, departments, NewDecideOptions().WithTiePolicy(TiePolicyError, 0.01))
+	if err != nil || result.SelectedIndex != 1 || len(result.Tied) != 0 {
+		t.Errorf("narrow epsilon: got index %d, tied %v, %v; want index 1 without a tie", result.SelectedIndex, result.Tied, err)
+	}
+}
+
+func TestWorkflowEstimateCostDryRunsSteps(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	previous := getDefaultProvider()
+	defer SetDefaultProvider(previous)
+	provider, err := llm.NewOpenAIProvider(llm.ProviderConfig{APIKey: "sk-test"})
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	SetDefaultProvider(provider)
+
+	calls := 0
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		calls++
+		return `{}`, nil
+	})
+
+	type ticket struct {
+		Subject  string `json:"subject"`
+		Priority string `json:"priority"`
+	}
+	wf := NewWorkflow("triage")
+	wf.AddStep("extract", func(ctx context.Context, state map[string]any) error {
+		parsed, err := ExtractCtx[ticket](ctx, state["email"], NewExtractOptions())
+		state["ticket"] = parsed
+		return err
+	})
+	wf.AddStep("summarize", func(ctx context.Context, state map[string]any) error {
+		_, err := SummarizeCtx(ctx, fmt.Sprint(state["email"]), NewSummarizeOptions())
+		return err
+	}, DependsOn("extract"))
+
+	estimate, err := wf.EstimateCost(context.Background(), map[string]any{"email": "Checkout is down for every customer since 9am, please escalate."})
+	if err != nil {
+		t.Fatalf("EstimateCost failed: %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected no provider calls, got %d", calls)
+	}
+	if estimate.Calls != 2 || len(estimate.Steps) != 2 {
+		t.Fatalf("expected one request per step, got %+v", estimate)
+	}
+	for _, step := range estimate.Steps {
+		if step.Calls != 1 || step.EstimatedTokens == 0 || step.Error != "" {
+			t.Errorf("expected step %s to render one request, got %+v", step.Step, step)
+		}
+	}
+	if estimate.EstimatedTokens == 0 || estimate.EstimatedCost <= 0 {
+		t.Errorf("expected a nonzero estimate, got %+v", estimate)
+	}
+	if len(wf.State) != 0 {
+		t.Errorf("expected the workflow state to be left unchanged, got %v", wf.State)
+	}
+}
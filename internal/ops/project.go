@@ -27,6 +27,15 @@ type ProjectOptions struct {
 	// PreserveNulls keeps null values instead of omitting them
 	PreserveNulls bool
 
+	// Transforms computes target fields (by JSON name) in Go instead of the
+	// LLM. Each function receives the mapped source value (via Mappings, or
+	// the same-named source field), or the whole source object as
+	// map[string]any when no source field matches. Functions run before the
+	// LLM pass, and a source field consumed only by them is removed from the
+	// input the model sees. Results overwrite whatever the LLM produced for
+	// that field.
+	Transforms map[string]func(any) any
+
 	// Common options
 	Steering      string
 	Mode          types.Mode
@@ -65,6 +74,9 @@ type ProjectResult[U any] struct {
 	// Inferred lists target fields that were inferred (not from source)
 	Inferred []string `json:"inferred,omitempty"`
 
+	// Computed lists target fields produced by a Transforms function
+	Computed []string `json:"computed,omitempty"`
+
 	// Confidence in the projection quality (0.0-1.0)
 	Confidence float64 `json:"confidence"`
 
@@ -127,6 +139,12 @@ type ProjectResult[U any] struct {
 //	    Steering: "Convert date formats from MM/DD/YYYY to ISO8601",
 //	})
 //	fmt.Printf("Lost fields: %v, Inferred: %v\n", result.Lost, result.Inferred)
+//
+//	// Example 4: Compute a field in Go rather than trusting the model
+//	result, err := Project[InternalUser, PublicProfile](user, ProjectOptions{
+//	    Mappings:   map[string]string{"id": "user_id"},
+//	    Transforms: map[string]func(any) any{"user_id": hashID},
+//	})
 func Project[T any, U any](input T, opts ...ProjectOptions) (ProjectResult[U], error) {
 	log := logger.GetLogger()
 	log.Debug("Starting project operation")
@@ -135,11 +153,7 @@ func Project[T any, U any](input T, opts ...ProjectOptions) (ProjectResult[U], e
 	result.Metadata = make(map[string]any)
 
 	// Apply defaults
-	opt := ProjectOptions{
-		InferMissing: false,
-		Mode:         types.TransformMode,
-		Intelligence: types.Fast,
-	}
+	opt := defaultProjectOptions()
 	if len(opts) > 0 {
 		opt = mergeProjectOptions(opt, opts[0])
 	}
@@ -159,6 +173,15 @@ func Project[T any, U any](input T, opts ...ProjectOptions) (ProjectResult[U], e
 		return result, fmt.Errorf("failed to marshal input: %w", err)
 	}
 
+	// Run transforms first so their source values never reach the model
+	transformed := computeProjectTransforms(inputJSON, opt)
+	if len(transformed.consumed) > 0 {
+		if inputJSON, err = json.Marshal(transformed.stripped); err != nil {
+			log.Error("Project operation failed: marshal error", "error", err)
+			return result, fmt.Errorf("failed to marshal input: %w", err)
+		}
+	}
+
 	// Get schemas
 	inputSchema := GenerateTypeSchema(reflect.TypeOf(input))
 	var zero U
@@ -166,12 +189,14 @@ func Project[T any, U any](input T, opts ...ProjectOptions) (ProjectResult[U], e
 
 	// Build mappings description
 	mappingsDesc := ""
-	if len(opt.Mappings) > 0 {
-		var parts []string
-		for src, dst := range opt.Mappings {
-			parts = append(parts, fmt.Sprintf("- %s → %s", src, dst))
+	var mappingParts []string
+	for src, dst := range opt.Mappings {
+		if !transformed.consumed[src] {
+			mappingParts = append(mappingParts, fmt.Sprintf("- %s → %s", src, dst))
 		}
-		mappingsDesc = fmt.Sprintf("\n\nExplicit mappings:\n%s", strings.Join(parts, "\n"))
+	}
+	if len(mappingParts) > 0 {
+		mappingsDesc = fmt.Sprintf("\n\nExplicit mappings:\n%s", strings.Join(mappingParts, "\n"))
 	}
 
 	// Build exclude description
@@ -185,6 +210,11 @@ func Project[T any, U any](input T, opts ...ProjectOptions) (ProjectResult[U], e
 		inferNote = "\nInferMissing: true - derive target fields not present in source"
 	}
 
+	computedFields := sortedKeys(opt.Transforms)
+	if len(computedFields) > 0 {
+		inferNote += fmt.Sprintf("\nComputed separately (set to null and omit from mappings): %s", strings.Join(computedFields, ", "))
+	}
+
 	systemPrompt := fmt.Sprintf(`You are a data projection expert. Transform data from one structure to another while preserving semantics.
 
 Source schema: %s
@@ -235,6 +265,7 @@ Rules:
 		Context:       ctx,
 		RequestID:     opt.RequestID,
 		CorrelationID: opt.CorrelationID,
+		Operation:     "project",
 	}
 
 	response, err := callLLM(ctx, systemPrompt, userPrompt, opOpts)
@@ -282,6 +313,13 @@ Rules:
 	result.Inferred = parsed.Inferred
 	result.Confidence = parsed.Confidence
 
+	if len(computedFields) > 0 {
+		if err := applyProjectTransforms(&result, transformed); err != nil {
+			log.Error("Project operation failed: transform error", "error", err)
+			return result, err
+		}
+	}
+
 	log.Debug("Project operation succeeded",
 		"mappings", len(result.Mappings),
 		"lost", len(result.Lost),
@@ -291,6 +329,15 @@ Rules:
 	return result, nil
 }
 
+// defaultProjectOptions returns the options Project runs with when none are given
+func defaultProjectOptions() ProjectOptions {
+	return ProjectOptions{
+		InferMissing: false,
+		Mode:         types.TransformMode,
+		Intelligence: types.Fast,
+	}
+}
+
 // mergeProjectOptions merges user options with defaults
 func mergeProjectOptions(defaults, user ProjectOptions) ProjectOptions {
 	if user.Mappings != nil {
@@ -301,6 +348,9 @@ func mergeProjectOptions(defaults, user ProjectOptions) ProjectOptions {
 	}
 	defaults.InferMissing = user.InferMissing
 	defaults.PreserveNulls = user.PreserveNulls
+	if user.Transforms != nil {
+		defaults.Transforms = user.Transforms
+	}
 	if user.Steering != "" {
 		defaults.Steering = user.Steering
 	}
@@ -315,3 +365,113 @@ func mergeProjectOptions(defaults, user ProjectOptions) ProjectOptions {
 	}
 	return defaults
 }
+
+// projectTransforms holds the values of Transforms computed before the LLM
+// pass and the source input with the fields only they consumed removed
+type projectTransforms struct {
+	values   map[string]any
+	mappings []FieldMapping
+	consumed map[string]bool
+	stripped map[string]any
+}
+
+// computeProjectTransforms runs each Transforms function on its source value.
+// A source field is consumed, and left out of stripped, when every target it
+// maps to is computed in Go.
+func computeProjectTransforms(inputJSON []byte, opt ProjectOptions) projectTransforms {
+	transformed := projectTransforms{values: make(map[string]any), consumed: make(map[string]bool)}
+	if len(opt.Transforms) == 0 {
+		return transformed
+	}
+	var source map[string]any
+	_ = json.Unmarshal(inputJSON, &source)
+
+	for _, target := range sortedKeys(opt.Transforms) {
+		sourceField := ""
+		for src, dst := range opt.Mappings {
+			if dst == target {
+				sourceField = src
+				break
+			}
+		}
+		if sourceField == "" {
+			if _, ok := source[target]; ok {
+				sourceField = target
+			}
+		}
+
+		var arg any = source
+		if sourceField != "" {
+			arg = source[sourceField]
+			transformed.consumed[sourceField] = true
+		}
+		transformed.values[target] = opt.Transforms[target](arg)
+		transformed.mappings = append(transformed.mappings, FieldMapping{SourceField: sourceField, TargetField: target, Method: "function"})
+	}
+
+	// A field also mapped to a target the model fills is still needed
+	for src, dst := range opt.Mappings {
+		if _, computed := opt.Transforms[dst]; !computed {
+			delete(transformed.consumed, src)
+		}
+	}
+	if source != nil {
+		transformed.stripped = make(map[string]any, len(source))
+		for field, value := range source {
+			if !transformed.consumed[field] {
+				transformed.stripped[field] = value
+			}
+		}
+	}
+	return transformed
+}
+
+// applyProjectTransforms overwrites computed target fields with the values
+// of their Go functions and records them as "function" mappings
+func applyProjectTransforms[U any](result *ProjectResult[U], transformed projectTransforms) error {
+	projectedJSON, err := json.Marshal(result.Projected)
+	if err != nil {
+		return fmt.Errorf("failed to marshal projected data: %w", err)
+	}
+	var projected map[string]any
+	if err := json.Unmarshal(projectedJSON, &projected); err != nil {
+		return fmt.Errorf("transforms require an object target: %w", err)
+	}
+
+	computed := make(map[string]bool)
+	for target, value := range transformed.values {
+		projected[target] = value
+		computed[target] = true
+	}
+
+	data, err := json.Marshal(projected)
+	if err != nil {
+		return fmt.Errorf("failed to marshal transformed data: %w", err)
+	}
+	var out U
+	if err := json.Unmarshal(data, &out); err != nil {
+		return fmt.Errorf("transform output does not fit target schema: %w", err)
+	}
+	result.Projected = out
+
+	mappings := append([]FieldMapping(nil), transformed.mappings...)
+	for _, m := range result.Mappings {
+		if !computed[m.TargetField] {
+			mappings = append(mappings, m)
+		}
+	}
+	result.Mappings = mappings
+	result.Inferred = removeStrings(result.Inferred, computed)
+	result.Computed = sortedKeys(transformed.values)
+	return nil
+}
+
+func removeStrings(values []string, drop map[string]bool) []string {
+	var kept []string
+	for _, v := range values {
+		if !drop[v] {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,81 @@
+package ops
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+func TestProjectTransformsComputedInGo(t *testing.T) {
+	defer setupMockClient()
+
+	type InternalUser struct {
+		ID        string `json:"id"`
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+	}
+	type PublicProfile struct {
+		UserID      string `json:"user_id"`
+		DisplayName string `json:"display_name"`
+	}
+
+	hashID := func(v any) any {
+		sum := sha256.Sum256([]byte(v.(string)))
+		return hex.EncodeToString(sum[:8])
+	}
+
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		if !strings.Contains(system, "Computed separately (set to null and omit from mappings): user_id") {
+			t.Errorf("prompt does not exclude computed field: %q", system)
+		}
+		if strings.Contains(user, "u-123") || strings.Contains(system, "id → user_id") {
+			t.Errorf("raw user_id reached the model: %q", user)
+		}
+		if !strings.Contains(user, "Ada") {
+			t.Errorf("untransformed fields missing from prompt: %q", user)
+		}
+		// The model tries to fill user_id anyway; it must be overwritten
+		return `{
+			"projected": {"user_id": "u-123", "display_name": "Ada Lovelace"},
+			"mappings": [
+				{"source_field": "id", "target_field": "user_id", "method": "rename"},
+				{"target_field": "display_name", "method": "infer"}
+			],
+			"inferred": ["display_name", "user_id"],
+			"confidence": 0.9
+		}`, nil
+	})
+
+	user := InternalUser{ID: "u-123", FirstName: "Ada", LastName: "Lovelace"}
+	result, err := Project[InternalUser, PublicProfile](user, ProjectOptions{
+		Mappings:   map[string]string{"id": "user_id"},
+		Transforms: map[string]func(any) any{"user_id": hashID},
+	})
+	if err != nil {
+		t.Fatalf("Project() error = %v", err)
+	}
+
+	if want := hashID("u-123").(string); result.Projected.UserID != want {
+		t.Errorf("UserID = %q, want hash %q", result.Projected.UserID, want)
+	}
+	if result.Projected.DisplayName != "Ada Lovelace" {
+		t.Errorf("DisplayName = %q, want LLM value preserved", result.Projected.DisplayName)
+	}
+	if len(result.Computed) != 1 || result.Computed[0] != "user_id" {
+		t.Errorf("Computed = %v, want [user_id]", result.Computed)
+	}
+	for _, m := range result.Mappings {
+		if m.TargetField == "user_id" && (m.Method != "function" || m.SourceField != "id") {
+			t.Errorf("user_id mapping = %+v, want function from id", m)
+		}
+	}
+	for _, f := range result.Inferred {
+		if f == "user_id" {
+			t.Error("user_id should not be reported as inferred")
+		}
+	}
+}
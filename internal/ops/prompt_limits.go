@@ -0,0 +1,79 @@
+// package ops - Prompt token limits and the truncation policies applied above them
+package ops
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/monstercameron/schemaflow/internal/logger"
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+// bytesPerToken is the rough prompt size ratio used for token estimates
+const bytesPerToken = 4
+
+// estimateTokens approximates the token count of text
+func estimateTokens(text string) int {
+	return (len(text) + bytesPerToken - 1) / bytesPerToken
+}
+
+// fitPromptBudget applies opts.TruncationPolicy when the rendered prompt,
+// including the untrusted-input fence, is estimated above opts.MaxPromptTokens.
+// It returns the user prompt to send, with its input truncated or summarized
+// to fit, or types.ErrInputTooLarge.
+func fitPromptBudget(ctx context.Context, systemPrompt, userPrompt string, opts types.OpOptions) (string, error) {
+	if opts.MaxPromptTokens <= 0 {
+		return userPrompt, nil
+	}
+	renderedSystem, renderedUser, data := fenceUntrustedInput(systemPrompt, userPrompt)
+	tokens := estimateTokens(renderedSystem) + estimateTokens(renderedUser)
+	if tokens <= opts.MaxPromptTokens {
+		return userPrompt, nil
+	}
+
+	log := logger.GetLogger()
+	tooLarge := fmt.Errorf("%w: prompt is about %d tokens, limit %d", types.ErrInputTooLarge, tokens, opts.MaxPromptTokens)
+	budget := (opts.MaxPromptTokens - (tokens - estimateTokens(data))) * bytesPerToken
+	if opts.TruncationPolicy == "" || opts.TruncationPolicy == types.TruncationError || budget <= 0 {
+		log.Warn("Prompt exceeds the token limit", "requestID", opts.RequestID, "tokens", tokens, "maxTokens", opts.MaxPromptTokens)
+		return "", tooLarge
+	}
+
+	directive, input := splitDirective(userPrompt)
+	switch opts.TruncationPolicy {
+	case types.TruncateInput:
+		log.Debug("Truncating input to fit the prompt token limit", "requestID", opts.RequestID, "bytes", len(input), "budgetBytes", budget)
+		return directive + truncateUTF8(input, budget), nil
+
+	case types.SummarizeInput:
+		if opts.DryRun || IsDryRun(ctx) || IsDryRun(opts.Context) {
+			return userPrompt, nil // a dry run reports the prompt as rendered
+		}
+		log.Debug("Summarizing input to fit the prompt token limit", "requestID", opts.RequestID, "bytes", len(input), "budgetBytes", budget)
+		summaryOpts := NewSummarizeOptions()
+		summaryOpts.CommonOptions = summaryOpts.CommonOptions.
+			WithContext(ctx).
+			WithIntelligence(opts.Intelligence).
+			WithRequestID(opts.RequestID).
+			WithMaxInputBytes(max(budget, 1024)).
+			WithAutoChunk(true)
+		summary, err := Summarize(input, summaryOpts)
+		if err != nil {
+			return "", fmt.Errorf("%w (summarizing the input failed: %v)", tooLarge, err)
+		}
+		return directive + truncateUTF8(summary, budget), nil
+	}
+	return "", tooLarge
+}
+
+// truncateUTF8 cuts s to at most n bytes without splitting a UTF-8 sequence
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
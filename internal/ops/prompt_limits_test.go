@@ -0,0 +1,71 @@
+package ops
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+func TestMaxPromptTokensSummarizeInputCompressesBeforeExtract(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	type contract struct {
+		Parties []string `json:"parties"`
+		Term    string   `json:"term"`
+	}
+
+	var calls []string
+	var extractPrompt string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		if strings.Contains(system, "summarization expert") {
+			calls = append(calls, "summarize")
+			return "Acme and Globex agree to a 24-month term.", nil
+		}
+		calls = append(calls, "extract")
+		extractPrompt = system + user
+		return `{"parties": ["Acme", "Globex"], "term": "24 months"}`, nil
+	})
+
+	input := "This agreement between Acme and Globex runs for 24 months. " + strings.Repeat("Boilerplate clause text. ", 800)
+	opts := NewExtractOptions().WithMaxPromptTokens(2500)
+
+	if _, err := Extract[contract](input, opts); !errors.Is(err, types.ErrInputTooLarge) {
+		t.Fatalf("expected the default policy to reject the oversized prompt, got %v", err)
+	}
+	if len(calls) != 0 {
+		t.Fatalf("expected no provider call for a rejected prompt, got %v", calls)
+	}
+
+	result, err := Extract[contract](input, opts.WithTruncationPolicy(types.SummarizeInput))
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(calls) < 2 || calls[0] != "summarize" || calls[len(calls)-1] != "extract" {
+		t.Fatalf("expected the input to be summarized before extraction, got %v", calls)
+	}
+	if strings.Contains(extractPrompt, "Boilerplate clause") || !strings.Contains(extractPrompt, "24-month term") {
+		t.Errorf("expected extraction to see the summary instead of the input, got %q", extractPrompt)
+	}
+	if tokens := estimateTokens(extractPrompt); tokens > 2500 {
+		t.Errorf("expected the extraction prompt within 2500 tokens, got about %d", tokens)
+	}
+	if result.Term != "24 months" {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	calls = nil
+	if _, err := Extract[contract](input, opts.WithTruncationPolicy(types.TruncateInput)); err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(calls) != 1 || !strings.Contains(extractPrompt, "between Acme and Globex") || estimateTokens(extractPrompt) > 2500 {
+		t.Errorf("expected one call with the input's head within the limit, got %v calls and %d tokens", calls, estimateTokens(extractPrompt))
+	}
+
+	if err := opts.WithTruncationPolicy("drop").Validate(); err == nil {
+		t.Error("expected an unknown truncation policy to be rejected")
+	}
+}
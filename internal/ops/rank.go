@@ -43,6 +43,16 @@ type RankOptions struct {
 
 	// Include explanation for ranking
 	IncludeExplanation bool
+
+	// Narrow the list to this many candidates before the LLM stage (0 disables)
+	ShortlistTopK int
+
+	// Scores candidates for the shortlist stage (defaults to lexical overlap)
+	ShortlistScorer ShortlistScorer
+
+	// Weight of novelty against relevance when ordering results (0.0-1.0,
+	// 0 disables diversity re-ranking)
+	Diversity float64
 }
 
 // NewRankOptions creates RankOptions with defaults
@@ -71,9 +81,15 @@ func (r RankOptions) Validate() error {
 	if r.TopK < 0 {
 		return fmt.Errorf("topK cannot be negative, got %d", r.TopK)
 	}
+	if r.ShortlistTopK < 0 {
+		return fmt.Errorf("shortlist topK cannot be negative, got %d", r.ShortlistTopK)
+	}
 	if r.MinScore < 0 || r.MinScore > 1 {
 		return fmt.Errorf("min score must be between 0 and 1, got %f", r.MinScore)
 	}
+	if r.Diversity < 0 || r.Diversity > 1 {
+		return fmt.Errorf("diversity must be between 0 and 1, got %f", r.Diversity)
+	}
 	return nil
 }
 
@@ -131,6 +147,29 @@ func (r RankOptions) WithIncludeExplanation(include bool) RankOptions {
 	return r
 }
 
+// WithShortlistStage narrows huge lists to the embeddingTopK best candidates
+// (by ShortlistScorer) before the LLM ranks them
+func (r RankOptions) WithShortlistStage(embeddingTopK int) RankOptions {
+	r.ShortlistTopK = embeddingTopK
+	return r
+}
+
+// WithShortlistScorer sets the scorer used by the shortlist stage, e.g. an
+// embedding cosine similarity
+func (r RankOptions) WithShortlistScorer(scorer ShortlistScorer) RankOptions {
+	r.ShortlistScorer = scorer
+	return r
+}
+
+// WithDiversity re-ranks results by maximal marginal relevance, so
+// near-duplicates of higher-ranked items fall back in the order. lambda
+// weighs novelty against relevance: 0 keeps the relevance order and 1 orders
+// by novelty alone. Similarity between items is their term overlap.
+func (r RankOptions) WithDiversity(lambda float64) RankOptions {
+	r.Diversity = lambda
+	return r
+}
+
 // WithSteering sets the steering prompt
 func (r RankOptions) WithSteering(steering string) RankOptions {
 	r.CommonOptions = r.CommonOptions.WithSteering(steering)
@@ -169,6 +208,7 @@ type RankResult[T any] struct {
 	Query         string          `json:"query"`
 	TotalItems    int             `json:"total_items"`
 	ReturnedItems int             `json:"returned_items"`
+	Shortlist     *ShortlistStats `json:"shortlist,omitempty"`
 	Metadata      map[string]any  `json:"metadata,omitempty"`
 }
 
@@ -195,6 +235,11 @@ type RankResult[T any] struct {
 //	    WithQuery("climate change").
 //	    WithBoostFields(map[string]float64{"recent": 1.5}).
 //	    WithIncludeExplanation(true))
+//
+//	// Ranking a huge catalog: shortlist 50 candidates, then rank with the LLM
+//	result, err := Rank(catalog, NewRankOptions().
+//	    WithQuery("waterproof hiking boots").
+//	    WithShortlistStage(50))
 func Rank[T any](items []T, opts RankOptions) (RankResult[T], error) {
 	log := logger.GetLogger()
 	log.Debug("Starting rank operation", "itemCount", len(items), "query", opts.Query)
@@ -214,6 +259,7 @@ func Rank[T any](items []T, opts RankOptions) (RankResult[T], error) {
 	}
 
 	opt := opts.toOpOptions()
+	opt.Operation = "rank"
 
 	ctx := opt.Context
 	if ctx == nil {
@@ -225,14 +271,29 @@ func Rank[T any](items []T, opts RankOptions) (RankResult[T], error) {
 	defer cancel()
 
 	// Convert items to JSON
-	itemsJSON := make([]string, len(items))
+	rawJSON := make([]string, len(items))
 	for i, item := range items {
 		itemJSON, err := json.Marshal(item)
 		if err != nil {
 			log.Error("Rank operation failed: marshal error", "itemIndex", i, "error", err)
 			return result, fmt.Errorf("failed to marshal item %d: %w", i, err)
 		}
-		itemsJSON[i] = fmt.Sprintf("[%d] %s", i, string(itemJSON))
+		rawJSON[i] = string(itemJSON)
+	}
+
+	// Shortlist stage: only the best candidates reach the LLM. Prompt indices
+	// are positions in the shortlist and are mapped back to the original items.
+	candidates := shortlistIndices(opts.Query, rawJSON, opts.ShortlistTopK, opts.ShortlistScorer)
+	if opts.ShortlistTopK > 0 {
+		result.Shortlist = &ShortlistStats{Candidates: len(items), Shortlisted: len(candidates)}
+		result.Metadata["shortlist_candidates"] = len(items)
+		result.Metadata["shortlist_size"] = len(candidates)
+		log.Debug("Rank shortlist stage", "candidates", len(items), "shortlisted", len(candidates))
+	}
+
+	itemsJSON := make([]string, len(candidates))
+	for i, idx := range candidates {
+		itemsJSON[i] = fmt.Sprintf("[%d] %s", i, rawJSON[idx])
 	}
 
 	// Build ranking factors description
@@ -314,35 +375,88 @@ Order the rankings from highest to lowest score.`, opts.Query, factorsDesc, boos
 		return result, fmt.Errorf("failed to parse ranking result: %w", err)
 	}
 
-	// Build ranked items
-	rank := 1
+	// Build ranked items, skipping items below the minimum score
+	var ranked []RankedItem[T]
 	for _, r := range parsed.Rankings {
-		// Skip items below minimum score
-		if r.Score < opts.MinScore {
+		if r.Score < opts.MinScore || r.Index < 0 || r.Index >= len(candidates) {
 			continue
 		}
+		original := candidates[r.Index]
+		ranked = append(ranked, RankedItem[T]{
+			Item:         items[original],
+			Index:        original,
+			Score:        r.Score,
+			Explanation:  r.Explanation,
+			FactorScores: r.FactorScores,
+		})
+	}
+	if opts.Diversity > 0 {
+		ranked = diversify(ranked, rawJSON, opts.Diversity)
+		result.Metadata["diversity"] = opts.Diversity
+	}
+	if opts.TopK > 0 && len(ranked) > opts.TopK {
+		ranked = ranked[:opts.TopK]
+	}
+	for i := range ranked {
+		ranked[i].Rank = i + 1
+	}
+	result.Items = ranked
 
-		// Check if we've reached topK
-		if opts.TopK > 0 && rank > opts.TopK {
-			break
-		}
+	result.ReturnedItems = len(result.Items)
+
+	log.Debug("Rank operation succeeded", "returnedItems", result.ReturnedItems)
+	return result, nil
+}
 
-		if r.Index >= 0 && r.Index < len(items) {
-			rankedItem := RankedItem[T]{
-				Item:         items[r.Index],
-				Index:        r.Index,
-				Rank:         rank,
-				Score:        r.Score,
-				Explanation:  r.Explanation,
-				FactorScores: r.FactorScores,
+// diversify reorders relevance-ranked items by maximal marginal relevance:
+// each position goes to the item maximizing
+// (1-lambda)*score - lambda*(highest similarity to an item already placed),
+// where similarity is the Jaccard overlap of the items' serialized terms
+func diversify[T any](ranked []RankedItem[T], texts []string, lambda float64) []RankedItem[T] {
+	terms := make([]map[string]bool, len(ranked))
+	for i, item := range ranked {
+		terms[i] = make(map[string]bool)
+		for _, term := range shortlistTerms(texts[item.Index]) {
+			terms[i][term] = true
+		}
+	}
+	similarity := func(a, b int) float64 {
+		shared := 0
+		for term := range terms[a] {
+			if terms[b][term] {
+				shared++
 			}
-			result.Items = append(result.Items, rankedItem)
-			rank++
 		}
+		union := len(terms[a]) + len(terms[b]) - shared
+		if union == 0 {
+			return 1
+		}
+		return float64(shared) / float64(union)
 	}
 
-	result.ReturnedItems = len(result.Items)
+	placed := make([]int, 0, len(ranked))
+	used := make([]bool, len(ranked))
+	for len(placed) < len(ranked) {
+		best, bestValue := -1, 0.0
+		for i := range ranked {
+			if used[i] {
+				continue
+			}
+			redundancy := 0.0
+			for _, j := range placed {
+				redundancy = max(redundancy, similarity(i, j))
+			}
+			if value := (1-lambda)*ranked[i].Score - lambda*redundancy; best < 0 || value > bestValue {
+				best, bestValue = i, value
+			}
+		}
+		used[best] = true
+		placed = append(placed, best)
+	}
 
-	log.Debug("Rank operation succeeded", "returnedItems", result.ReturnedItems)
-	return result, nil
+	ordered := make([]RankedItem[T], len(placed))
+	for i, idx := range placed {
+		ordered[i] = ranked[idx]
+	}
+	return ordered
 }
@@ -1,7 +1,12 @@
 package ops
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"testing"
+
+	"github.com/monstercameron/schemaflow/internal/types"
 )
 
 func TestRankOptions(t *testing.T) {
@@ -92,3 +97,94 @@ func TestRank(t *testing.T) {
 		}
 	})
 }
+
+func TestRankShortlistStage(t *testing.T) {
+	type Product struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	items := make([]Product, 1000)
+	for i := range items {
+		items[i] = Product{ID: i, Name: fmt.Sprintf("generic office chair %d", i)}
+	}
+	items[137].Name = "waterproof hiking boots"
+	items[802].Name = "hiking boots, leather"
+	items[555].Name = "waterproof jacket"
+
+	var userPrompt string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		userPrompt = user
+		return `{"rankings":[{"index":0,"score":0.95},{"index":2,"score":0.6},{"index":1,"score":0.4}]}`, nil
+	})
+	defer setupMockClient()
+
+	result, err := Rank(items, NewRankOptions().
+		WithQuery("waterproof hiking boots").
+		WithShortlistStage(3))
+	if err != nil {
+		t.Fatalf("Rank() error = %v", err)
+	}
+
+	if result.Shortlist == nil || result.Shortlist.Candidates != 1000 || result.Shortlist.Shortlisted != 3 {
+		t.Fatalf("Shortlist = %+v, want 1000 candidates and 3 shortlisted", result.Shortlist)
+	}
+	if lines := strings.Count(userPrompt, "\n["); lines != 3 {
+		t.Fatalf("LLM stage received %d items, want 3:\n%s", lines, userPrompt)
+	}
+	if strings.Contains(userPrompt, "office chair") {
+		t.Errorf("LLM stage received non-shortlisted items:\n%s", userPrompt)
+	}
+
+	// Shortlist positions map back to original indices (137, 555, 802 in order)
+	wantIndices := []int{137, 802, 555}
+	if len(result.Items) != len(wantIndices) {
+		t.Fatalf("got %d ranked items, want %d", len(result.Items), len(wantIndices))
+	}
+	for i, want := range wantIndices {
+		if result.Items[i].Index != want || result.Items[i].Item.ID != want {
+			t.Errorf("Items[%d] = index %d (id %d), want %d", i, result.Items[i].Index, result.Items[i].Item.ID, want)
+		}
+	}
+}
+
+func TestRankWithDiversitySkipsNearDuplicates(t *testing.T) {
+	type Product struct {
+		Name string `json:"name"`
+	}
+	items := []Product{
+		{Name: "wireless noise cancelling headphones black"},
+		{Name: "wireless noise cancelling headphones black edition"},
+		{Name: "portable bluetooth speaker"},
+		{Name: "usb charging cable"},
+	}
+
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		return `{"rankings":[{"index":0,"score":0.95},{"index":1,"score":0.94},{"index":2,"score":0.7},{"index":3,"score":0.3}]}`, nil
+	})
+	defer setupMockClient()
+
+	opts := NewRankOptions().WithQuery("headphones for travel").WithTopK(2)
+	plain, err := Rank(items, opts)
+	if err != nil {
+		t.Fatalf("Rank() error = %v", err)
+	}
+	if plain.Items[0].Index != 0 || plain.Items[1].Index != 1 {
+		t.Fatalf("without diversity want items 0, 1; got %d, %d", plain.Items[0].Index, plain.Items[1].Index)
+	}
+
+	diverse, err := Rank(items, opts.WithDiversity(0.7))
+	if err != nil {
+		t.Fatalf("Rank() error = %v", err)
+	}
+	if len(diverse.Items) != 2 || diverse.Items[0].Index != 0 || diverse.Items[1].Index != 2 {
+		t.Fatalf("with diversity want items 0, 2; got %+v", diverse.Items)
+	}
+	if diverse.Items[1].Rank != 2 || diverse.Items[1].Score != 0.7 {
+		t.Errorf("second item rank/score = %d/%v, want 2/0.7", diverse.Items[1].Rank, diverse.Items[1].Score)
+	}
+
+	if err := opts.WithDiversity(1.5).Validate(); err == nil {
+		t.Error("expected error for diversity above 1")
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/monstercameron/schemaflow/internal/telemetry"
 	"github.com/monstercameron/schemaflow/internal/types"
@@ -37,6 +39,11 @@ type RedactLLMOptions struct {
 	ShowFirst  int      // Number of characters to show at start (default: 0)
 	ShowLast   int      // Number of characters to show at end (default: 0)
 	MinMask    int      // Minimum mask length (default: 3)
+
+	// NeverRedact lists text that must stay visible even when it looks
+	// sensitive, such as internal identifiers. Each entry is matched
+	// literally and, when it compiles, as a regular expression.
+	NeverRedact []string
 }
 
 // NewRedactLLMOptions creates RedactLLMOptions with defaults
@@ -84,6 +91,13 @@ func (opts RedactLLMOptions) WithMinMask(n int) RedactLLMOptions {
 	return opts
 }
 
+// WithNeverRedact sets literals or regular expressions whose matches are
+// never redacted
+func (opts RedactLLMOptions) WithNeverRedact(patterns []string) RedactLLMOptions {
+	opts.NeverRedact = patterns
+	return opts
+}
+
 // WithIntelligence sets the intelligence level
 func (opts RedactLLMOptions) WithIntelligence(intelligence types.Speed) RedactLLMOptions {
 	opts.OpOptions.Intelligence = intelligence
@@ -98,6 +112,11 @@ func (opts RedactLLMOptions) Validate() error {
 	if opts.MinMask < 1 {
 		return fmt.Errorf("MinMask must be at least 1")
 	}
+	for _, pattern := range opts.NeverRedact {
+		if pattern == "" {
+			return fmt.Errorf("never-redact patterns cannot be empty")
+		}
+	}
 	return nil
 }
 
@@ -147,6 +166,7 @@ func RedactLLM(ctx context.Context, text string, opts RedactLLMOptions) (RedactL
 
 	// Call LLM
 	opOpts := opts.OpOptions
+	opOpts.Operation = "redact"
 	response, err := callLLM(ctx, systemPrompt, userPrompt, opOpts)
 	if err != nil {
 		logger.Error("RedactLLM LLM call failed", "requestID", opts.RequestID, "error", err)
@@ -160,6 +180,9 @@ func RedactLLM(ctx context.Context, text string, opts RedactLLMOptions) (RedactL
 		return result, fmt.Errorf("failed to parse LLM response: %w", err)
 	}
 
+	// Drop detections covering text that must never be redacted
+	spans = excludeNeverRedact(text, spans, opts.NeverRedact)
+
 	// Apply redactions
 	result.Spans = spans
 	result.Text = applyRedactions(text, spans, opts)
@@ -301,6 +324,80 @@ func mergeOverlappingSpans(spans []RedactSpan) []RedactSpan {
 	return merged
 }
 
+// excludeNeverRedact removes the parts of spans that fall on a never-redact
+// match. A span entirely inside a match is dropped; one that only overlaps a
+// match keeps its remaining pieces, so sensitive data next to an allowed
+// identifier is still masked. Cut pieces are trimmed to their letters and
+// digits, and dropped when only separators such as ", " remain.
+func excludeNeverRedact(text string, spans []RedactSpan, patterns []string) []RedactSpan {
+	if len(patterns) == 0 || len(spans) == 0 {
+		return spans
+	}
+
+	var protected [][]int
+	for _, pattern := range patterns {
+		for offset := 0; ; {
+			idx := strings.Index(text[offset:], pattern)
+			if idx < 0 {
+				break
+			}
+			protected = append(protected, []int{offset + idx, offset + idx + len(pattern)})
+			offset += idx + len(pattern)
+		}
+		if re, err := regexp.Compile(pattern); err == nil {
+			for _, loc := range re.FindAllStringIndex(text, -1) {
+				if loc[0] < loc[1] {
+					protected = append(protected, loc)
+				}
+			}
+		}
+	}
+	if len(protected) == 0 {
+		return spans
+	}
+
+	kept := make([]RedactSpan, 0, len(spans))
+	for _, span := range spans {
+		pieces := [][]int{{span.Start, span.End}}
+		for _, p := range protected {
+			var next [][]int
+			for _, piece := range pieces {
+				if p[1] <= piece[0] || p[0] >= piece[1] {
+					next = append(next, piece)
+					continue
+				}
+				if p[0] > piece[0] {
+					next = append(next, []int{piece[0], p[0]})
+				}
+				if p[1] < piece[1] {
+					next = append(next, []int{p[1], piece[1]})
+				}
+			}
+			pieces = next
+		}
+		if len(pieces) == 1 && pieces[0][0] == span.Start && pieces[0][1] == span.End {
+			kept = append(kept, span)
+			continue
+		}
+		for _, piece := range pieces {
+			trimmed := strings.TrimFunc(text[piece[0]:piece[1]], func(r rune) bool {
+				return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+			})
+			if trimmed == "" {
+				continue
+			}
+			start := piece[0] + strings.Index(text[piece[0]:piece[1]], trimmed)
+			kept = append(kept, RedactSpan{
+				Start:    start,
+				End:      start + len(trimmed),
+				Category: span.Category,
+				Original: trimmed,
+			})
+		}
+	}
+	return kept
+}
+
 // applyRedactions applies the redaction spans to the text
 func applyRedactions(text string, spans []RedactSpan, opts RedactLLMOptions) string {
 	if len(spans) == 0 {
@@ -1,8 +1,12 @@
 package ops
 
 import (
+	"context"
+	"fmt"
 	"strings"
 	"testing"
+
+	"github.com/monstercameron/schemaflow/internal/types"
 )
 
 func TestRedactOptions(t *testing.T) {
@@ -394,3 +398,42 @@ func TestRedact_MaskConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestRedactLLMNeverRedactPreservesAllowedIdentifiers(t *testing.T) {
+	defer setupMockClient()
+
+	text := "Ticket EMP-555-0142 was filed by a caller at 555-867-5309."
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		// The detector flags the internal ID as a phone number alongside the real one
+		idStart := strings.Index(text, "555-0142")
+		phoneStart := strings.Index(text, "555-867-5309")
+		return fmt.Sprintf(`{"spans": [{"start": %d, "end": %d, "category": "phone"}, {"start": %d, "end": %d, "category": "phone"}]}`,
+			idStart, idStart+len("555-0142"), phoneStart, phoneStart+len("555-867-5309")), nil
+	})
+
+	opts := NewRedactLLMOptions().WithNeverRedact([]string{`EMP-\d{3}-\d{4}`})
+	result, err := RedactLLM(context.Background(), text, opts)
+	if err != nil {
+		t.Fatalf("RedactLLM failed: %v", err)
+	}
+
+	if !strings.Contains(result.Text, "EMP-555-0142") {
+		t.Errorf("expected the internal ID to be preserved, got %q", result.Text)
+	}
+	if strings.Contains(result.Text, "555-867-5309") {
+		t.Errorf("expected the phone number to be masked, got %q", result.Text)
+	}
+	if len(result.Spans) != 1 || result.Spans[0].Original != "555-867-5309" || result.Categories["phone"] != 1 {
+		t.Errorf("expected only the phone number span, got %+v", result.Spans)
+	}
+
+	// Literal entries work too, including ones that are not valid regexes
+	literal := NewRedactLLMOptions().WithNeverRedact([]string{"EMP-555-0142", "C++("})
+	result, err = RedactLLM(context.Background(), text, literal)
+	if err != nil {
+		t.Fatalf("RedactLLM failed: %v", err)
+	}
+	if !strings.Contains(result.Text, "EMP-555-0142") || strings.Contains(result.Text, "555-867-5309") {
+		t.Errorf("expected literal never-redact to keep only the ID, got %q", result.Text)
+	}
+}
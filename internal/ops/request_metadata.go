@@ -0,0 +1,80 @@
+// package ops - Request metadata forwarded to providers
+package ops
+
+import (
+	"context"
+	"maps"
+
+	"github.com/monstercameron/schemaflow/internal/llm"
+	"github.com/monstercameron/schemaflow/internal/requesttracking"
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+type requestMetadataKey struct{}
+
+// ContextWithRequestMetadata returns ctx carrying metadata that every
+// operation using it attaches to its provider requests. Entries are merged
+// over metadata already on ctx.
+func ContextWithRequestMetadata(ctx context.Context, metadata map[string]string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if len(metadata) == 0 {
+		return ctx
+	}
+	merged := RequestMetadataFromContext(ctx)
+	if merged == nil {
+		merged = make(map[string]string, len(metadata))
+	}
+	maps.Copy(merged, metadata)
+	return context.WithValue(ctx, requestMetadataKey{}, merged)
+}
+
+// RequestMetadataFromContext returns a copy of the metadata carried by ctx
+func RequestMetadataFromContext(ctx context.Context) map[string]string {
+	if ctx == nil {
+		return nil
+	}
+	metadata, _ := ctx.Value(requestMetadataKey{}).(map[string]string)
+	return maps.Clone(metadata)
+}
+
+// contextWithRunID returns ctx carrying a run ID, generating one when ctx has
+// none so every call made under it (pipeline steps, batch items) shares it
+func contextWithRunID(ctx context.Context, fallback string) (context.Context, string) {
+	if runID := RequestMetadataFromContext(ctx)[llm.MetadataRunID]; runID != "" {
+		return ctx, runID
+	}
+	runID := fallback
+	if runID == "" {
+		runID = requesttracking.NewID("run")
+	}
+	return ContextWithRequestMetadata(ctx, map[string]string{llm.MetadataRunID: runID}), runID
+}
+
+// requestMetadata assembles the metadata for an outgoing request: entries
+// from the operation's context and the call context, the operation's own
+// RequestMetadata, then the resolved request and correlation IDs
+func requestMetadata(ctx context.Context, opts types.OpOptions) map[string]string {
+	metadata := RequestMetadataFromContext(opts.Context)
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	maps.Copy(metadata, RequestMetadataFromContext(ctx))
+	maps.Copy(metadata, opts.RequestMetadata)
+
+	tracking := requesttracking.FromContext(ctx)
+	if tracking.RequestID != "" {
+		metadata[llm.MetadataRequestID] = tracking.RequestID
+	}
+	if tracking.CorrelationID != "" {
+		metadata[llm.MetadataCorrelationID] = tracking.CorrelationID
+	}
+	if opts.Operation != "" {
+		metadata[llm.MetadataOperation] = opts.Operation
+	}
+	if len(metadata) == 0 {
+		return nil
+	}
+	return metadata
+}
@@ -140,12 +140,7 @@ func Resolve[T any](sources []T, opts ...ResolveOptions) (ResolveResult[T], erro
 	}
 
 	// Apply defaults
-	opt := ResolveOptions{
-		Strategy:          "most-complete",
-		ConflictThreshold: 0.8,
-		Mode:              types.TransformMode,
-		Intelligence:      types.Fast,
-	}
+	opt := defaultResolveOptions()
 	if len(opts) > 0 {
 		opt = mergeResolveOptions(opt, opts[0])
 	}
@@ -231,6 +226,7 @@ Strategy explanations:
 		Context:       ctx,
 		RequestID:     opt.RequestID,
 		CorrelationID: opt.CorrelationID,
+		Operation:     "reconcile",
 	}
 
 	response, err := callLLM(ctx, systemPrompt, userPrompt, opOpts)
@@ -289,6 +285,16 @@ Strategy explanations:
 	return result, nil
 }
 
+// defaultResolveOptions returns the options Resolve runs with when none are given
+func defaultResolveOptions() ResolveOptions {
+	return ResolveOptions{
+		Strategy:          "most-complete",
+		ConflictThreshold: 0.8,
+		Mode:              types.TransformMode,
+		Intelligence:      types.Fast,
+	}
+}
+
 // mergeResolveOptions merges user options with defaults
 func mergeResolveOptions(defaults, user ResolveOptions) ResolveOptions {
 	if user.Strategy != "" {
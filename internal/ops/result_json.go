@@ -0,0 +1,232 @@
+// package ops - Storing operation results as JSON and replaying them
+package ops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+// resultFormatVersion is the version of the MarshalResult envelope
+const resultFormatVersion = 1
+
+// resultEnvelope wraps a marshalled result with its format version and the Go
+// type it was marshalled from
+type resultEnvelope struct {
+	Version int             `json:"schemaflow_result"`
+	Type    string          `json:"type"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// MarshalResult serializes an operation result, such as an AuditResult or a
+// ResolveResult, into a versioned JSON document that UnmarshalResult reads
+// back. Every result type's JSON field names are stable; errors held by
+// batch, pipeline and stream results are stored as their messages.
+//
+// Example:
+//
+//	data, err := MarshalResult(audit)
+//	// ... store data, then later:
+//	replayed, err := UnmarshalResult[AuditResult[Customer]](data)
+func MarshalResult(result any) ([]byte, error) {
+	if result == nil {
+		return nil, errors.New("cannot marshal a nil result")
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %T: %w", result, err)
+	}
+	return json.Marshal(resultEnvelope{
+		Version: resultFormatVersion,
+		Type:    reflect.TypeOf(result).String(),
+		Result:  data,
+	})
+}
+
+// UnmarshalResult reads a document written by MarshalResult into R, which
+// should be the result type that was marshalled. Values held in fields of
+// type any decode as JSON values (numbers as float64), and stored errors come
+// back as errors with the original message; messages of the package's
+// sentinel errors, such as ErrBudgetExhausted, restore the sentinel.
+func UnmarshalResult[R any](data []byte) (R, error) {
+	var result R
+	var envelope resultEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return result, fmt.Errorf("failed to read result document: %w", err)
+	}
+	switch {
+	case envelope.Version == 0 || envelope.Result == nil:
+		return result, errors.New("not a result document written by MarshalResult")
+	case envelope.Version > resultFormatVersion:
+		return result, fmt.Errorf("result document version %d is newer than the supported version %d", envelope.Version, resultFormatVersion)
+	}
+	if err := json.Unmarshal(envelope.Result, &result); err != nil {
+		return result, fmt.Errorf("failed to unmarshal %s into %T: %w", envelope.Type, result, err)
+	}
+	return result, nil
+}
+
+// sentinelErrors are restored by identity when their message is read back
+var sentinelErrors = []error{
+	types.ErrBudgetExhausted,
+	types.ErrDryRun,
+	types.ErrTruncated,
+	types.ErrCapabilityUnsupported,
+	types.ErrCircuitOpen,
+	types.ErrInputTooLarge,
+	types.ErrInjectionDetected,
+}
+
+// errorMessage returns err's message, or nil for a nil error
+func errorMessage(err error) *string {
+	if err == nil {
+		return nil
+	}
+	message := err.Error()
+	return &message
+}
+
+// messageError turns a stored message back into an error
+func messageError(message *string) error {
+	if message == nil {
+		return nil
+	}
+	for _, sentinel := range sentinelErrors {
+		if sentinel.Error() == *message {
+			return sentinel
+		}
+	}
+	return errors.New(*message)
+}
+
+func errorMessages(errs []error) []*string {
+	if errs == nil {
+		return nil
+	}
+	messages := make([]*string, len(errs))
+	for i, err := range errs {
+		messages[i] = errorMessage(err)
+	}
+	return messages
+}
+
+func messageErrors(messages []*string) []error {
+	if messages == nil {
+		return nil
+	}
+	errs := make([]error, len(messages))
+	for i, message := range messages {
+		errs[i] = messageError(message)
+	}
+	return errs
+}
+
+// batchResultJSON is the JSON form of BatchResult
+type batchResultJSON[T any] struct {
+	Results  []T           `json:"results"`
+	Errors   []*string     `json:"errors"`
+	Metadata BatchMetadata `json:"metadata"`
+}
+
+// MarshalJSON stores each error as its message, null for successful items
+func (r BatchResult[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(batchResultJSON[T]{Results: r.Results, Errors: errorMessages(r.Errors), Metadata: r.Metadata})
+}
+
+// UnmarshalJSON reads the form written by MarshalJSON
+func (r *BatchResult[T]) UnmarshalJSON(data []byte) error {
+	var decoded batchResultJSON[T]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*r = BatchResult[T]{Results: decoded.Results, Errors: messageErrors(decoded.Errors), Metadata: decoded.Metadata}
+	return nil
+}
+
+// indexedResultJSON is the JSON form of IndexedResult
+type indexedResultJSON[T any] struct {
+	Index  int     `json:"index"`
+	Result T       `json:"result"`
+	Err    *string `json:"error,omitempty"`
+}
+
+// MarshalJSON stores the error as its message
+func (r IndexedResult[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(indexedResultJSON[T]{Index: r.Index, Result: r.Result, Err: errorMessage(r.Err)})
+}
+
+// UnmarshalJSON reads the form written by MarshalJSON
+func (r *IndexedResult[T]) UnmarshalJSON(data []byte) error {
+	var decoded indexedResultJSON[T]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*r = IndexedResult[T]{Index: decoded.Index, Result: decoded.Result, Err: messageError(decoded.Err)}
+	return nil
+}
+
+// extractSnapshotJSON is the JSON form of ExtractSnapshot
+type extractSnapshotJSON[T any] struct {
+	Value  T        `json:"value"`
+	Fields []string `json:"fields"`
+	Final  bool     `json:"final,omitempty"`
+	Err    *string  `json:"error,omitempty"`
+}
+
+// MarshalJSON stores the error as its message
+func (s ExtractSnapshot[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(extractSnapshotJSON[T]{Value: s.Value, Fields: s.Fields, Final: s.Final, Err: errorMessage(s.Err)})
+}
+
+// UnmarshalJSON reads the form written by MarshalJSON
+func (s *ExtractSnapshot[T]) UnmarshalJSON(data []byte) error {
+	var decoded extractSnapshotJSON[T]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*s = ExtractSnapshot[T]{Value: decoded.Value, Fields: decoded.Fields, Final: decoded.Final, Err: messageError(decoded.Err)}
+	return nil
+}
+
+// pipelineResultJSON is the JSON form of PipelineResult
+type pipelineResultJSON struct {
+	Output        any           `json:"output"`
+	StepsExecuted int           `json:"steps_executed"`
+	StepsFailed   int           `json:"steps_failed"`
+	Duration      time.Duration `json:"duration"`
+	Errors        []*string     `json:"errors,omitempty"`
+	RunID         string        `json:"run_id,omitempty"`
+}
+
+// MarshalJSON stores each error as its message
+func (r PipelineResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(pipelineResultJSON{
+		Output:        r.Output,
+		StepsExecuted: r.StepsExecuted,
+		StepsFailed:   r.StepsFailed,
+		Duration:      r.Duration,
+		Errors:        errorMessages(r.Errors),
+		RunID:         r.RunID,
+	})
+}
+
+// UnmarshalJSON reads the form written by MarshalJSON
+func (r *PipelineResult) UnmarshalJSON(data []byte) error {
+	var decoded pipelineResultJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*r = PipelineResult{
+		Output:        decoded.Output,
+		StepsExecuted: decoded.StepsExecuted,
+		StepsFailed:   decoded.StepsFailed,
+		Duration:      decoded.Duration,
+		Errors:        messageErrors(decoded.Errors),
+		RunID:         decoded.RunID,
+	}
+	return nil
+}
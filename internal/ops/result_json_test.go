@@ -0,0 +1,130 @@
+package ops
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+type storedCustomer struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+	Phone string `json:"phone,omitempty"`
+}
+
+// assertRoundTrip marshals result, reads it back as R and checks that the
+// replayed result marshals to the same JSON
+func assertRoundTrip[R any](t *testing.T, result R) R {
+	t.Helper()
+	data, err := MarshalResult(result)
+	if err != nil {
+		t.Fatalf("MarshalResult failed: %v", err)
+	}
+	replayed, err := UnmarshalResult[R](data)
+	if err != nil {
+		t.Fatalf("UnmarshalResult failed: %v", err)
+	}
+	again, err := MarshalResult(replayed)
+	if err != nil {
+		t.Fatalf("MarshalResult of the replayed result failed: %v", err)
+	}
+	if !bytes.Equal(data, again) {
+		t.Errorf("round trip changed the result:\nbefore: %s\nafter:  %s", data, again)
+	}
+	return replayed
+}
+
+func TestAuditResultRoundTripsThroughJSON(t *testing.T) {
+	result := AuditResult[storedCustomer]{
+		Original: storedCustomer{ID: "C1", Email: "ada@example.com"},
+		Findings: []AuditFinding{{
+			Category:       "security",
+			Severity:       0.9,
+			Field:          "email",
+			Issue:          "Email stored in plain text",
+			Evidence:       "ada@example.com",
+			Recommendation: "Encrypt contact fields at rest",
+			Policy:         "PII must be encrypted",
+			Records:        []int{0, 3},
+		}},
+		Summary: AuditSummary{
+			TotalFindings:      1,
+			BySeverity:         map[string]int{"critical": 1},
+			ByCategory:         map[string]int{"security": 1},
+			Critical:           true,
+			BlockingViolations: []string{"PII must be encrypted"},
+		},
+		Policies:   []string{"PII must be encrypted"},
+		ReportHash: "9f86d081884c7d65",
+		Metadata:   map[string]any{"auditor": "nightly"},
+	}
+
+	replayed := assertRoundTrip(t, result)
+	if !reflect.DeepEqual(replayed, result) {
+		t.Errorf("expected %+v, got %+v", result, replayed)
+	}
+}
+
+func TestResolveResultRoundTripsThroughJSON(t *testing.T) {
+	result := ResolveResult[storedCustomer]{
+		Resolved: storedCustomer{ID: "C1", Email: "john@new.com", Phone: "555-1234"},
+		Conflicts: []Conflict{{
+			Field:        "email",
+			Values:       map[int]any{0: "john@old.com", 1: "john@new.com"},
+			Resolution:   "most recent source",
+			ChosenSource: 1,
+			ChosenValue:  "john@new.com",
+			Reasoning:    "Source 1 was updated last",
+		}},
+		SourceContributions: map[int][]string{0: {"id"}, 1: {"email", "phone"}},
+		Strategy:            "most-recent",
+		Confidence:          0.85,
+	}
+
+	replayed := assertRoundTrip(t, result)
+	if !reflect.DeepEqual(replayed, result) {
+		t.Errorf("expected %+v, got %+v", result, replayed)
+	}
+
+	if _, err := UnmarshalResult[ResolveResult[storedCustomer]]([]byte(`{"resolved": {}}`)); err == nil {
+		t.Error("expected a document not written by MarshalResult to be rejected")
+	}
+	if _, err := UnmarshalResult[ResolveResult[storedCustomer]]([]byte(`{"schemaflow_result": 99, "result": {}}`)); err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("expected a newer format version to be rejected, got %v", err)
+	}
+}
+
+func TestBatchResultStoresErrorsAsMessages(t *testing.T) {
+	result := BatchResult[storedCustomer]{
+		Results: []storedCustomer{{ID: "C1"}, {}, {}},
+		Errors:  []error{nil, errors.New("item 1: invalid email"), types.ErrBudgetExhausted},
+		Metadata: BatchMetadata{
+			Mode:            ParallelMode,
+			TotalItems:      3,
+			Succeeded:       1,
+			Failed:          2,
+			Duration:        1500 * time.Millisecond,
+			BudgetExhausted: true,
+			Remaining:       []int{2},
+		},
+	}
+
+	replayed := assertRoundTrip(t, result)
+	if replayed.Errors[0] != nil || replayed.Errors[1].Error() != "item 1: invalid email" {
+		t.Errorf("expected errors restored by message, got %v", replayed.Errors)
+	}
+	if !errors.Is(replayed.Errors[2], types.ErrBudgetExhausted) {
+		t.Errorf("expected the budget sentinel to be restored, got %v", replayed.Errors[2])
+	}
+
+	data, _ := json.Marshal(result)
+	if !strings.Contains(string(data), `"errors":[null,"item 1: invalid email"`) {
+		t.Errorf("expected errors stored as messages, got %s", data)
+	}
+}
@@ -0,0 +1,82 @@
+// package ops - Provider response metadata recorded per LLM call
+package ops
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/monstercameron/schemaflow/internal/llm"
+)
+
+// ResultMeta is the provider metadata of one LLM call, for debugging what
+// actually served a result
+type ResultMeta struct {
+	// FinishReason is the provider's reason for ending the completion, such
+	// as "stop", "length" or "content_filter"
+	FinishReason string `json:"finish_reason"`
+
+	// Model is the model that actually served the call
+	Model string `json:"model"`
+
+	// SystemFingerprint identifies the provider's backend configuration,
+	// when reported
+	SystemFingerprint string `json:"system_fingerprint,omitempty"`
+
+	// SafetyFlags lists the provider's safety signals for the completion
+	SafetyFlags []string `json:"safety_flags,omitempty"`
+}
+
+type resultMetaKey struct{}
+
+// ResultMetaRecorder collects the ResultMeta of every LLM call made under one
+// context
+type ResultMetaRecorder struct {
+	mu    sync.Mutex
+	metas []ResultMeta
+}
+
+// WithResultMeta attaches a ResultMetaRecorder that every LLM call made under
+// the returned context records its provider metadata to
+func WithResultMeta(ctx context.Context) (context.Context, *ResultMetaRecorder) {
+	recorder := &ResultMetaRecorder{}
+	return context.WithValue(ctx, resultMetaKey{}, recorder), recorder
+}
+
+// Last returns the metadata of the most recent call, and false when no call
+// has been recorded
+func (r *ResultMetaRecorder) Last() (ResultMeta, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.metas) == 0 {
+		return ResultMeta{}, false
+	}
+	return r.metas[len(r.metas)-1], true
+}
+
+// All returns the metadata of every recorded call, in completion order
+func (r *ResultMetaRecorder) All() []ResultMeta {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.metas)
+}
+
+// recordResultMeta adds a completion's metadata to the recorder on ctx, if
+// any; model is used when the provider did not report one
+func recordResultMeta(ctx context.Context, resp llm.CompletionResponse, model string) {
+	recorder, ok := ctx.Value(resultMetaKey{}).(*ResultMetaRecorder)
+	if !ok {
+		return
+	}
+	if resp.Model != "" {
+		model = resp.Model
+	}
+	recorder.mu.Lock()
+	recorder.metas = append(recorder.metas, ResultMeta{
+		FinishReason:      resp.FinishReason,
+		Model:             model,
+		SystemFingerprint: resp.SystemFingerprint,
+		SafetyFlags:       slices.Clone(resp.SafetyFlags),
+	})
+	recorder.mu.Unlock()
+}
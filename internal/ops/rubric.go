@@ -0,0 +1,201 @@
+// package ops - Reusable weighted rubrics for the Score operation
+package ops
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/monstercameron/schemaflow/internal/config"
+	"github.com/monstercameron/schemaflow/internal/logger"
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+// Criterion is one weighted dimension of a Rubric
+type Criterion struct {
+	// Name identifies the criterion in the breakdown
+	Name string `json:"name"`
+
+	// Weight is the criterion's relative importance; weights are normalized
+	// to sum to 1 by NewRubric
+	Weight float64 `json:"weight"`
+
+	// Description tells the model what the criterion measures
+	Description string `json:"description,omitempty"`
+
+	// ScaleMin and ScaleMax bound the criterion's score (default 0-10)
+	ScaleMin float64 `json:"scale_min"`
+	ScaleMax float64 `json:"scale_max"`
+}
+
+// Rubric is a validated, immutable set of weighted criteria that can be
+// reused across Score calls
+type Rubric struct {
+	criteria []Criterion
+}
+
+// NewRubric validates criteria and normalizes their weights to sum to 1.
+// Criteria without a scale default to 0-10.
+//
+// Example:
+//
+//	rubric, err := NewRubric([]Criterion{
+//	    {Name: "clarity", Weight: 2, Description: "Easy to follow"},
+//	    {Name: "accuracy", Weight: 3, Description: "Claims are correct", ScaleMin: 1, ScaleMax: 5},
+//	})
+func NewRubric(criteria []Criterion) (Rubric, error) {
+	if len(criteria) == 0 {
+		return Rubric{}, fmt.Errorf("rubric requires at least one criterion")
+	}
+
+	seen := make(map[string]bool, len(criteria))
+	total := 0.0
+	normalized := make([]Criterion, len(criteria))
+	for i, criterion := range criteria {
+		criterion.Name = strings.TrimSpace(criterion.Name)
+		switch {
+		case criterion.Name == "":
+			return Rubric{}, fmt.Errorf("criterion %d has no name", i)
+		case seen[criterion.Name]:
+			return Rubric{}, fmt.Errorf("duplicate criterion %q", criterion.Name)
+		case criterion.Weight < 0 || math.IsNaN(criterion.Weight) || math.IsInf(criterion.Weight, 0):
+			return Rubric{}, fmt.Errorf("criterion %q has invalid weight %v", criterion.Name, criterion.Weight)
+		}
+		if criterion.ScaleMin == 0 && criterion.ScaleMax == 0 {
+			criterion.ScaleMax = 10
+		}
+		if criterion.ScaleMin >= criterion.ScaleMax {
+			return Rubric{}, fmt.Errorf("criterion %q: scale min (%f) must be less than scale max (%f)",
+				criterion.Name, criterion.ScaleMin, criterion.ScaleMax)
+		}
+		seen[criterion.Name] = true
+		total += criterion.Weight
+		normalized[i] = criterion
+	}
+	if total == 0 {
+		return Rubric{}, fmt.Errorf("rubric weights must not all be zero")
+	}
+
+	for i := range normalized {
+		normalized[i].Weight /= total
+	}
+	return Rubric{criteria: normalized}, nil
+}
+
+// Criteria returns a copy of the rubric's criteria with normalized weights
+func (r Rubric) Criteria() []Criterion {
+	return append([]Criterion(nil), r.criteria...)
+}
+
+// ScoreWithRubric scores item against each criterion of rubric and combines
+// the results deterministically: every criterion score is normalized to its
+// own scale and weighted, giving NormalizedValue, which is then mapped onto
+// the options' scale as Value. Breakdown holds the raw per-criterion scores
+// and Metadata["weights"] the normalized weights. Criteria, Rubric and
+// Weights set on opts are ignored in favour of rubric.
+//
+// Example:
+//
+//	result, err := ScoreWithRubric[Essay](essay, rubric, NewScoreOptions())
+//	fmt.Printf("%.1f/10 (clarity %.1f)\n", result.Value, result.Breakdown["clarity"])
+func ScoreWithRubric[T any](item T, rubric Rubric, opts ScoreOptions) (ScoreResult, error) {
+	log := logger.GetLogger()
+	log.Debug("Starting rubric score operation", "criteria", len(rubric.criteria))
+
+	var result ScoreResult
+	if len(rubric.criteria) == 0 {
+		return result, fmt.Errorf("rubric has no criteria; create it with NewRubric")
+	}
+	if err := opts.Validate(); err != nil {
+		return result, fmt.Errorf("invalid options: %w", err)
+	}
+
+	opt := opts.toOpOptions()
+	opt.Operation = "score"
+	ctx := opt.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, config.GetTimeout())
+	defer cancel()
+
+	var lines []string
+	for _, criterion := range rubric.criteria {
+		line := fmt.Sprintf("- %q (%.1f to %.1f)", criterion.Name, criterion.ScaleMin, criterion.ScaleMax)
+		if criterion.Description != "" {
+			line += ": " + criterion.Description
+		}
+		lines = append(lines, line)
+	}
+
+	systemPrompt := fmt.Sprintf(`You are a scoring expert. Score the input against each rubric criterion independently.
+
+Criteria:
+%s
+
+Rules:
+- Score every criterion within its own range
+- Do not compute an overall score; it is derived from the criterion scores
+- Identify strengths and weaknesses
+- Explain your reasoning
+
+Return a JSON object with these fields:
+- "scores": object with each criterion name as key and its score as value
+- "reasoning": explanation of the scoring
+- "strengths": array of identified strengths
+- "weaknesses": array of identified weaknesses`, strings.Join(lines, "\n"))
+
+	userPrompt := fmt.Sprintf("Score this input:\n%s", formatInput(item))
+
+	response, err := callLLM(ctx, systemPrompt, userPrompt, opt)
+	if err != nil {
+		log.Error("Rubric score operation failed", "error", err)
+		return result, types.ScoreError{
+			Input:  item,
+			Reason: err.Error(),
+			Cause:  err,
+		}
+	}
+
+	var llmResult struct {
+		Scores     map[string]float64 `json:"scores"`
+		Reasoning  string             `json:"reasoning,omitempty"`
+		Strengths  []string           `json:"strengths,omitempty"`
+		Weaknesses []string           `json:"weaknesses,omitempty"`
+	}
+	if err := ParseJSON(response, &llmResult); err != nil {
+		log.Error("Rubric score failed to parse response", "error", err, "response", response)
+		return result, fmt.Errorf("failed to parse score response: %w", err)
+	}
+
+	result.Breakdown = make(map[string]float64, len(rubric.criteria))
+	weights := make(map[string]float64, len(rubric.criteria))
+	var missing []string
+	for _, criterion := range rubric.criteria {
+		score, ok := llmResult.Scores[criterion.Name]
+		if !ok {
+			missing = append(missing, criterion.Name)
+			continue
+		}
+		score = math.Min(math.Max(score, criterion.ScaleMin), criterion.ScaleMax)
+		result.Breakdown[criterion.Name] = score
+		weights[criterion.Name] = criterion.Weight
+		result.NormalizedValue += criterion.Weight * (score - criterion.ScaleMin) / (criterion.ScaleMax - criterion.ScaleMin)
+	}
+	if len(missing) > 0 {
+		return result, types.ScoreError{
+			Input:  item,
+			Reason: fmt.Sprintf("response has no score for criteria: %s", strings.Join(missing, ", ")),
+		}
+	}
+
+	result.Value = opts.ScaleMin + result.NormalizedValue*(opts.ScaleMax-opts.ScaleMin)
+	result.Reasoning = llmResult.Reasoning
+	result.Strengths = llmResult.Strengths
+	result.Weaknesses = llmResult.Weaknesses
+	result.Metadata = map[string]any{"weights": weights}
+
+	log.Debug("Rubric score operation completed", "value", result.Value, "normalized", result.NormalizedValue)
+	return result, nil
+}
@@ -0,0 +1,90 @@
+// package ops - Cache of compiled rule prompts for Validate and Conform
+package ops
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+// ruleCacheCapacity bounds the cached rulesets; the oldest entry is evicted
+// first
+const ruleCacheCapacity = 256
+
+// ruleCacheKey identifies a compiled ruleset: the operation's target type,
+// a hash of the rules and the options that shape the prompt, and the
+// intelligence level
+type ruleCacheKey struct {
+	typ          reflect.Type
+	rulesHash    string
+	intelligence types.Speed
+}
+
+// compiledRules is the data-independent prompt skeleton of an operation
+type compiledRules struct {
+	systemPrompt string
+	rulesDesc    string
+}
+
+var (
+	ruleCacheMu      sync.Mutex
+	ruleCache        = map[ruleCacheKey]compiledRules{}
+	ruleCacheOrder   []ruleCacheKey
+	ruleCompilations int // rulesets compiled since the last ClearRuleCache
+)
+
+// ClearRuleCache drops every compiled Validate and Conform ruleset, so the
+// next call over each type recompiles its prompt
+func ClearRuleCache() {
+	ruleCacheMu.Lock()
+	defer ruleCacheMu.Unlock()
+	ruleCache = map[ruleCacheKey]compiledRules{}
+	ruleCacheOrder = nil
+	ruleCompilations = 0
+}
+
+// cachedRules returns the ruleset compiled for t, rules and intelligence,
+// calling compile only on a cache miss. rules must list everything that
+// compile reads, in a stable order.
+func cachedRules(op string, t reflect.Type, intelligence types.Speed, rules []string, compile func() compiledRules) compiledRules {
+	hash := sha256.Sum256([]byte(op + "\x00" + strings.Join(rules, "\x00")))
+	key := ruleCacheKey{typ: t, rulesHash: hex.EncodeToString(hash[:]), intelligence: intelligence}
+
+	ruleCacheMu.Lock()
+	compiled, ok := ruleCache[key]
+	ruleCacheMu.Unlock()
+	if ok {
+		return compiled
+	}
+
+	compiled = compile()
+	ruleCacheMu.Lock()
+	defer ruleCacheMu.Unlock()
+	ruleCompilations++
+	if _, ok := ruleCache[key]; !ok {
+		ruleCacheOrder = append(ruleCacheOrder, key)
+	}
+	ruleCache[key] = compiled
+	for len(ruleCacheOrder) > ruleCacheCapacity {
+		delete(ruleCache, ruleCacheOrder[0])
+		ruleCacheOrder = ruleCacheOrder[1:]
+	}
+	return compiled
+}
+
+// ruleLines formats field -> rule pairs as "- field: rule" lines sorted by
+// field, so equal maps compile to the same prompt
+func ruleLines(rules map[string]string) []string {
+	lines := make([]string, 0, len(rules))
+	for field, rule := range rules {
+		lines = append(lines, fmt.Sprintf("- %s: %s", field, rule))
+	}
+	sort.Strings(lines)
+	return lines
+}
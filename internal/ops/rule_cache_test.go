@@ -0,0 +1,77 @@
+package ops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+func TestRuleCacheReusesCompiledValidateAndConformPrompts(t *testing.T) {
+	defer setupMockClient()
+	ClearRuleCache()
+	defer ClearRuleCache()
+
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		if opts.Mode == types.TransformMode {
+			return `{"conformed": {"name": "ADA", "state": "CA", "street": "", "since": ""}, "compliance": 1.0}`, nil
+		}
+		return `{"valid": true, "confidence": 0.9}`, nil
+	})
+
+	validateOpts := NewValidateOptions().WithRules("name is required")
+	validateOpts.FieldRules = map[string]string{"state": "two letters", "name": "uppercase", "since": "ISO date"}
+	for _, person := range []conformAddress{{Name: "ADA"}, {Name: "GRACE"}} {
+		if _, err := Validate(person, validateOpts); err != nil {
+			t.Fatalf("Validate() error = %v", err)
+		}
+	}
+	if ruleCompilations != 1 {
+		t.Fatalf("rulesets compiled = %d after two Validate calls, want 1", ruleCompilations)
+	}
+
+	if _, err := Validate(conformAddress{Name: "ADA"}, validateOpts.WithIntelligence(types.Smart)); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if ruleCompilations != 2 {
+		t.Errorf("rulesets compiled = %d, want a new ruleset for another intelligence level", ruleCompilations)
+	}
+
+	for range 2 {
+		if _, err := Conform(conformAddress{Name: "ada", State: "california"}, "USPS"); err != nil {
+			t.Fatalf("Conform() error = %v", err)
+		}
+	}
+	if ruleCompilations != 3 {
+		t.Errorf("rulesets compiled = %d after two Conform calls, want one more", ruleCompilations)
+	}
+
+	ClearRuleCache()
+	if _, err := Conform(conformAddress{Name: "ada"}, "USPS"); err != nil {
+		t.Fatalf("Conform() error = %v", err)
+	}
+	if ruleCompilations != 1 {
+		t.Errorf("rulesets compiled = %d after ClearRuleCache, want the ruleset recompiled", ruleCompilations)
+	}
+}
+
+func BenchmarkValidateCachedRules(b *testing.B) {
+	defer setupMockClient()
+	ClearRuleCache()
+	defer ClearRuleCache()
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		return `{"valid": true, "confidence": 0.9}`, nil
+	})
+	opts := NewValidateOptions().WithRules("name is required")
+	opts.FieldRules = map[string]string{"state": "two letters", "name": "uppercase"}
+
+	b.ResetTimer()
+	for b.Loop() {
+		if _, err := Validate(conformAddress{Name: "ADA"}, opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if ruleCompilations != 1 {
+		b.Errorf("rulesets compiled = %d, want 1", ruleCompilations)
+	}
+}
@@ -0,0 +1,76 @@
+// package ops - Per-client settings carried by a run's context
+package ops
+
+import (
+	"context"
+
+	"github.com/monstercameron/schemaflow/internal/llm"
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+// Scope holds the settings of one client. Operations whose context carries a
+// scope use it in place of the process-wide settings; unset fields fall back
+// to them.
+type Scope struct {
+	// Provider answers the operations' LLM calls
+	Provider llm.Provider
+
+	// Defaults are applied like SetDefaultOptions
+	Defaults *types.OpOptions
+
+	// Persona is applied like SetDefaultPersona
+	Persona string
+
+	// Interceptors run inside the process-wide chain, in order
+	Interceptors []llm.Interceptor
+
+	// Scrubbers run after the process-wide outbound scrubbers
+	Scrubbers []OutboundScrubber
+
+	// Restorers run before the process-wide inbound restorers
+	Restorers []InboundRestorer
+}
+
+type scopeKey struct{}
+
+// WithScope returns a context whose operations use scope. The scope must not
+// be modified afterwards.
+func WithScope(ctx context.Context, scope *Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// scopeFrom returns the scope attached to ctx, or nil
+func scopeFrom(ctx context.Context) *Scope {
+	if ctx == nil {
+		return nil
+	}
+	scope, _ := ctx.Value(scopeKey{}).(*Scope)
+	return scope
+}
+
+// providerFor returns the provider of ctx's scope, falling back to the
+// process-wide default provider
+func providerFor(ctx context.Context) llm.Provider {
+	if scope := scopeFrom(ctx); scope != nil && scope.Provider != nil {
+		return scope.Provider
+	}
+	return getDefaultProvider()
+}
+
+// defaultOptionsFor returns the default options of ctx's scope, falling back
+// to the process-wide defaults
+func defaultOptionsFor(ctx context.Context) (types.OpOptions, bool) {
+	if scope := scopeFrom(ctx); scope != nil && scope.Defaults != nil {
+		return *scope.Defaults, true
+	}
+	return getDefaultOptions()
+}
+
+// defaultPersonaFor returns the persona of ctx's scope, falling back to the
+// process-wide default persona
+func defaultPersonaFor(ctx context.Context) string {
+	if scope := scopeFrom(ctx); scope != nil && scope.Persona != "" {
+		return scope.Persona
+	}
+	return getDefaultPersona()
+}
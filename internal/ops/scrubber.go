@@ -0,0 +1,93 @@
+// package ops - Scrubbing requests before they leave and restoring responses
+package ops
+
+import (
+	"context"
+	"sync"
+
+	"github.com/monstercameron/schemaflow/internal/llm"
+)
+
+// OutboundScrubber rewrites a completion request just before it is sent to
+// the provider, for example to replace PII with tokens
+type OutboundScrubber func(req llm.CompletionRequest) llm.CompletionRequest
+
+// InboundRestorer rewrites a provider response as soon as it arrives, for
+// example to put back the PII an OutboundScrubber tokenized
+type InboundRestorer func(resp llm.CompletionResponse) llm.CompletionResponse
+
+var (
+	scrubbersMu       sync.RWMutex
+	outboundScrubbers []OutboundScrubber
+	inboundRestorers  []InboundRestorer
+)
+
+// AddOutboundScrubber appends scrubber to the hooks run on every provider
+// request, streaming included. Scrubbers run in the order they were added,
+// after every interceptor, so nothing they remove reaches the provider.
+func AddOutboundScrubber(scrubber OutboundScrubber) {
+	scrubbersMu.Lock()
+	defer scrubbersMu.Unlock()
+	outboundScrubbers = append(outboundScrubbers, scrubber)
+}
+
+// AddInboundRestorer appends restorer to the hooks run on every successful
+// provider response, before any interceptor sees it. Restorers run in the
+// reverse of the order they were added, so each undoes the scrubber added
+// alongside it. Streamed fragments are delivered as the provider sent them;
+// only the assembled response is restored.
+func AddInboundRestorer(restorer InboundRestorer) {
+	scrubbersMu.Lock()
+	defer scrubbersMu.Unlock()
+	inboundRestorers = append(inboundRestorers, restorer)
+}
+
+// ClearScrubbers removes every outbound scrubber and inbound restorer
+func ClearScrubbers() {
+	scrubbersMu.Lock()
+	defer scrubbersMu.Unlock()
+	outboundScrubbers = nil
+	inboundRestorers = nil
+}
+
+// scrubRequest runs the process-wide outbound scrubbers over req, then those
+// of ctx's scope
+func scrubRequest(ctx context.Context, req llm.CompletionRequest) llm.CompletionRequest {
+	scrubbersMu.RLock()
+	scrubbers := outboundScrubbers
+	scrubbersMu.RUnlock()
+	if scope := scopeFrom(ctx); scope != nil {
+		scrubbers = append(scrubbers[:len(scrubbers):len(scrubbers)], scope.Scrubbers...)
+	}
+	for _, scrub := range scrubbers {
+		req = scrub(req)
+	}
+	return req
+}
+
+// restoreResponse runs the inbound restorers over resp, last added first, so
+// the restorers of ctx's scope run before the process-wide ones
+func restoreResponse(ctx context.Context, resp llm.CompletionResponse) llm.CompletionResponse {
+	scrubbersMu.RLock()
+	restorers := inboundRestorers
+	scrubbersMu.RUnlock()
+	if scope := scopeFrom(ctx); scope != nil {
+		restorers = append(restorers[:len(restorers):len(restorers)], scope.Restorers...)
+	}
+	for i := len(restorers) - 1; i >= 0; i-- {
+		resp = restorers[i](resp)
+	}
+	return resp
+}
+
+// scrubbedComplete wraps complete so requests are scrubbed on the way out
+// and successful responses restored on the way in
+func scrubbedComplete(complete llm.CompletionFunc) llm.CompletionFunc {
+	return func(ctx context.Context, req llm.CompletionRequest) (llm.CompletionResponse, error) {
+		resp, err := complete(ctx, scrubRequest(ctx, req))
+		if err == nil {
+			resp = restoreResponse(ctx, resp)
+		}
+		return resp, err
+	}
+}
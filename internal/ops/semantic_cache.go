@@ -0,0 +1,101 @@
+// package ops - Similarity-keyed response cache for read-style operations
+package ops
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/monstercameron/schemaflow/internal/config"
+	"github.com/monstercameron/schemaflow/internal/llm"
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+// semanticCacheOperations are the operations whose result stays valid for a
+// paraphrased input. Operations that copy details out of the input (extract,
+// translate) or produce new content (generate, rewrite) are never cached,
+// since a near-duplicate input may differ in exactly the detail that matters.
+var semanticCacheOperations = map[string]bool{
+	"summarize": true,
+	"classify":  true,
+	"score":     true,
+}
+
+// semanticCacheCapacity bounds the entries kept per prompt scope; the oldest
+// entry is evicted first
+const semanticCacheCapacity = 256
+
+type semanticEntry struct {
+	vector   []float64
+	response string
+}
+
+var (
+	semanticCacheMu sync.Mutex
+	semanticCache   = map[string][]semanticEntry{}
+)
+
+// ClearSemanticCache drops every response stored by WithSemanticCache
+func ClearSemanticCache() {
+	semanticCacheMu.Lock()
+	defer semanticCacheMu.Unlock()
+	semanticCache = map[string][]semanticEntry{}
+}
+
+// semanticCacheScope returns the key under which a request's responses are
+// cached, or "" when the request must not be cached. Everything but the user
+// prompt must match exactly, including the persona, provider and model a
+// client's run resolves to; only the input is compared by similarity.
+func semanticCacheScope(ctx context.Context, systemPrompt string, opts types.OpOptions) string {
+	if opts.SemanticCacheThreshold <= 0 || opts.DryRun || IsDryRun(ctx) || IsDryRun(opts.Context) {
+		return ""
+	}
+	if !isDeterministic(opts) {
+		return ""
+	}
+	if !semanticCacheOperations[opts.Operation] {
+		return ""
+	}
+	providerName := ""
+	if provider := providerFor(ctx); provider != nil {
+		providerName = provider.Name()
+	}
+	return fmt.Sprintf("%s\x00%d\x00%d\x00%s\x00%s\x00%s\x00%s\x00%s\x00%s\x00%s",
+		opts.Operation, opts.Mode, opts.Intelligence, opts.Steering, opts.Persona,
+		providerName, config.GetModel(opts.Intelligence, providerName), opts.ReasoningEffort, opts.JSONMode, systemPrompt)
+}
+
+// embedForCache embeds text with the default provider when it supports
+// embeddings, falling back to llm.LexicalEmbedding
+func embedForCache(ctx context.Context, text string) []float64 {
+	return embedTexts(ctx, []string{text})[0]
+}
+
+// lookupSemanticCache returns the cached response whose input is most similar
+// to vector, if any reaches threshold
+func lookupSemanticCache(scope string, vector []float64, threshold float64) (string, float64, bool) {
+	semanticCacheMu.Lock()
+	defer semanticCacheMu.Unlock()
+
+	best, bestScore := -1, 0.0
+	for i, entry := range semanticCache[scope] {
+		if score := llm.CosineSimilarity(vector, entry.vector); score >= threshold && score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 {
+		return "", 0, false
+	}
+	return semanticCache[scope][best].response, bestScore, true
+}
+
+func storeSemanticCache(scope string, vector []float64, response string) {
+	semanticCacheMu.Lock()
+	defer semanticCacheMu.Unlock()
+
+	entries := append(semanticCache[scope], semanticEntry{vector: vector, response: response})
+	if len(entries) > semanticCacheCapacity {
+		entries = entries[len(entries)-semanticCacheCapacity:]
+	}
+	semanticCache[scope] = entries
+}
@@ -0,0 +1,222 @@
+// package ops - Structured constraints checked deterministically against sequences
+package ops
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SequenceConstraint is a structured rule over the fields of a sequence of
+// records. Build one with SequenceMonotonic, SequenceRange, or
+// SequenceRelation.
+type SequenceConstraint struct {
+	// Kind is "monotonic", "range", or "relation"
+	Kind string `json:"kind"`
+
+	// Field is the JSON field checked by monotonic and range constraints
+	Field string `json:"field,omitempty"`
+
+	// Direction for monotonic constraints ("increasing", "decreasing",
+	// "non_decreasing", "non_increasing")
+	Direction string `json:"direction,omitempty"`
+
+	// Min and Max bound range constraints (inclusive)
+	Min float64 `json:"min,omitempty"`
+	Max float64 `json:"max,omitempty"`
+
+	// Expr is a per-record comparison such as "high>=open" or "score<=5"
+	Expr string `json:"expr,omitempty"`
+}
+
+// SequenceMonotonic requires field to move in direction across the sequence
+func SequenceMonotonic(field, direction string) SequenceConstraint {
+	return SequenceConstraint{Kind: "monotonic", Field: field, Direction: direction}
+}
+
+// SequenceRange requires field to lie within [min, max] for every record
+func SequenceRange(field string, min, max float64) SequenceConstraint {
+	return SequenceConstraint{Kind: "range", Field: field, Min: min, Max: max}
+}
+
+// SequenceRelation requires a comparison between fields (or a field and a
+// number) to hold for every record, e.g. SequenceRelation("high>=open")
+func SequenceRelation(expr string) SequenceConstraint {
+	return SequenceConstraint{Kind: "relation", Expr: expr}
+}
+
+// Validate reports a constraint that cannot be checked: an unknown kind or
+// monotonic direction, a missing field, an inverted range, or a relation
+// without a comparison operator
+func (c SequenceConstraint) Validate() error {
+	switch c.Kind {
+	case "monotonic":
+		switch c.Direction {
+		case "increasing", "decreasing", "non_decreasing", "non_increasing":
+		default:
+			return fmt.Errorf("monotonic direction must be increasing, decreasing, non_decreasing or non_increasing, got %q", c.Direction)
+		}
+	case "range":
+		if c.Min > c.Max {
+			return fmt.Errorf("range min %g is greater than max %g", c.Min, c.Max)
+		}
+	case "relation":
+		_, _, _, err := parseRelation(c.Expr)
+		return err
+	default:
+		return fmt.Errorf("unknown constraint kind %q", c.Kind)
+	}
+	if strings.TrimSpace(c.Field) == "" {
+		return fmt.Errorf("%s constraint requires a field", c.Kind)
+	}
+	return nil
+}
+
+// String renders the constraint as a prompt instruction
+func (c SequenceConstraint) String() string {
+	switch c.Kind {
+	case "monotonic":
+		return fmt.Sprintf("%q must be %s across the sequence", c.Field, strings.ReplaceAll(c.Direction, "_", "-"))
+	case "range":
+		return fmt.Sprintf("%q must be between %g and %g inclusive", c.Field, c.Min, c.Max)
+	case "relation":
+		return fmt.Sprintf("every item must satisfy %s", c.Expr)
+	}
+	return c.Kind
+}
+
+var relationOperators = []string{">=", "<=", "!=", "==", ">", "<"}
+
+// checkSequenceConstraints returns a description of every violation
+func checkSequenceConstraints[T any](items []T, rules []SequenceConstraint) ([]string, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal sequence: %w", err)
+	}
+	var records []map[string]any
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("structured constraints require object elements: %w", err)
+	}
+
+	var violations []string
+	for _, rule := range rules {
+		switch rule.Kind {
+		case "monotonic":
+			violations = append(violations, checkMonotonic(records, rule)...)
+		case "range":
+			for i, record := range records {
+				value, ok := normalizeFloat(record[rule.Field])
+				if !ok {
+					violations = append(violations, fmt.Sprintf("item %d: %q is missing or non-numeric", i, rule.Field))
+				} else if value < rule.Min || value > rule.Max {
+					violations = append(violations, fmt.Sprintf("item %d: %q = %g is outside [%g, %g]", i, rule.Field, value, rule.Min, rule.Max))
+				}
+			}
+		case "relation":
+			relationViolations, err := checkRelation(records, rule.Expr)
+			if err != nil {
+				return nil, err
+			}
+			violations = append(violations, relationViolations...)
+		default:
+			return nil, fmt.Errorf("unknown constraint kind %q", rule.Kind)
+		}
+	}
+	return violations, nil
+}
+
+func checkMonotonic(records []map[string]any, rule SequenceConstraint) []string {
+	var violations []string
+	prev, havePrev := 0.0, false
+	for i, record := range records {
+		value, ok := normalizeFloat(record[rule.Field])
+		if !ok {
+			violations = append(violations, fmt.Sprintf("item %d: %q is missing or non-numeric", i, rule.Field))
+			havePrev = false
+			continue
+		}
+		if havePrev {
+			var holds bool
+			switch rule.Direction {
+			case "increasing":
+				holds = value > prev
+			case "decreasing":
+				holds = value < prev
+			case "non_increasing":
+				holds = value <= prev
+			case "non_decreasing":
+				holds = value >= prev
+			}
+			if !holds {
+				violations = append(violations, fmt.Sprintf("item %d: %q = %g breaks %s order after %g", i, rule.Field, value, rule.Direction, prev))
+			}
+		}
+		prev, havePrev = value, true
+	}
+	return violations
+}
+
+// parseRelation splits a relation such as "high>=open" into its operands
+// and operator
+func parseRelation(expr string) (left, op, right string, err error) {
+	for _, candidate := range relationOperators {
+		if idx := strings.Index(expr, candidate); idx > 0 {
+			op = candidate
+			left = strings.TrimSpace(expr[:idx])
+			right = strings.TrimSpace(expr[idx+len(candidate):])
+			break
+		}
+	}
+	if op == "" || left == "" || right == "" {
+		return "", "", "", fmt.Errorf("invalid relation %q", expr)
+	}
+	return left, op, right, nil
+}
+
+func checkRelation(records []map[string]any, expr string) ([]string, error) {
+	left, op, right, err := parseRelation(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	operand := func(record map[string]any, token string) (float64, bool) {
+		if number, err := strconv.ParseFloat(token, 64); err == nil {
+			return number, true
+		}
+		return normalizeFloat(record[token])
+	}
+
+	var violations []string
+	for i, record := range records {
+		a, okA := operand(record, left)
+		b, okB := operand(record, right)
+		if !okA || !okB {
+			violations = append(violations, fmt.Sprintf("item %d: cannot evaluate %s", i, expr))
+			continue
+		}
+		var holds bool
+		switch op {
+		case ">=":
+			holds = a >= b
+		case "<=":
+			holds = a <= b
+		case ">":
+			holds = a > b
+		case "<":
+			holds = a < b
+		case "==":
+			holds = a == b
+		case "!=":
+			holds = a != b
+		}
+		if !holds {
+			violations = append(violations, fmt.Sprintf("item %d: %s fails (%g %s %g)", i, expr, a, op, b))
+		}
+	}
+	return violations, nil
+}
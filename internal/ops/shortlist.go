@@ -0,0 +1,200 @@
+// package ops - Cheap pre-ranking that narrows huge lists before the LLM stage
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/monstercameron/schemaflow/internal/llm"
+	"github.com/monstercameron/schemaflow/internal/logger"
+)
+
+// ShortlistScorer scores how relevant an item's serialized text is to a query;
+// higher is better. Choose, Filter and Sort default to embedding similarity
+// (one embedding call, lexical when the provider has no embeddings); Rank
+// defaults to a lexical term-overlap score that needs no model calls.
+type ShortlistScorer func(query, item string) float64
+
+// ShortlistStats reports the size of each stage of a shortlisted operation
+type ShortlistStats struct {
+	// Candidates is the number of items passed in
+	Candidates int `json:"candidates"`
+
+	// Shortlisted is the number of items sent to the LLM stage
+	Shortlisted int `json:"shortlisted"`
+}
+
+// shortlistIndices returns the indices of the k items scoring highest against
+// query, in their original order. Ties keep the earlier item.
+func shortlistIndices(query string, texts []string, k int, scorer ShortlistScorer) []int {
+	if k <= 0 || k >= len(texts) {
+		indices := make([]int, len(texts))
+		for i := range indices {
+			indices[i] = i
+		}
+		return indices
+	}
+
+	indices := append([]int(nil), rankIndices(query, texts, scorer)[:k]...)
+	sort.Ints(indices)
+	return indices
+}
+
+// rankIndices returns the indices of texts from the highest score against
+// query to the lowest. Ties keep the earlier item.
+func rankIndices(query string, texts []string, scorer ShortlistScorer) []int {
+	if scorer == nil {
+		scorer = lexicalScorer(texts)
+	}
+	scores := make([]float64, len(texts))
+	order := make([]int, len(texts))
+	for i, text := range texts {
+		scores[i] = scorer(query, text)
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[order[a]] > scores[order[b]]
+	})
+	return order
+}
+
+// shortlistItems returns the k items scoring highest against query
+func shortlistItems[T any](ctx context.Context, items []T, query string, k int, scorer ShortlistScorer) ([]T, error) {
+	selected, _, _, err := splitShortlist(ctx, items, query, k, scorer)
+	if err != nil {
+		return nil, err
+	}
+	shortlisted := make([]T, len(selected))
+	for i, idx := range selected {
+		shortlisted[i] = items[idx]
+	}
+	return shortlisted, nil
+}
+
+// splitShortlist partitions the indices of items into the k best candidates
+// and the rest, both in ascending order, and returns the serialized items it
+// scored. Without a scorer, candidates are scored by embedding similarity.
+func splitShortlist[T any](ctx context.Context, items []T, query string, k int, scorer ShortlistScorer) (shortlisted, rest []int, texts []string, err error) {
+	texts, err = shortlistTexts(items)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if scorer == nil && k > 0 && k < len(texts) {
+		scorer = embeddingScorer(ctx, query, texts)
+	}
+
+	shortlisted = shortlistIndices(query, texts, k, scorer)
+	selected := make(map[int]bool, len(shortlisted))
+	for _, idx := range shortlisted {
+		selected[idx] = true
+	}
+	for i := range items {
+		if !selected[i] {
+			rest = append(rest, i)
+		}
+	}
+	return shortlisted, rest, texts, nil
+}
+
+// shortlistTexts serializes items for scoring
+func shortlistTexts[T any](items []T) ([]string, error) {
+	texts := make([]string, len(items))
+	for i, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		texts[i] = string(data)
+	}
+	return texts, nil
+}
+
+// embeddingScorer embeds query and texts in a single call and scores by
+// cosine similarity, so a shortlist over hundreds of items costs one
+// embedding request instead of LLM tokens for every item
+func embeddingScorer(ctx context.Context, query string, texts []string) ShortlistScorer {
+	all := append([]string{query}, texts...)
+	vectors := embedTexts(ctx, all)
+	byText := make(map[string][]float64, len(all))
+	for i, text := range all {
+		byText[text] = vectors[i]
+	}
+	return func(query, item string) float64 {
+		return llm.CosineSimilarity(byText[query], byText[item])
+	}
+}
+
+// textEmbedder embeds texts for shortlists and the semantic cache, one vector
+// per text. It uses the provider of ctx; tests replace it to stub embeddings.
+var textEmbedder = providerEmbed
+
+// providerEmbed embeds texts with the provider of ctx, failing when the
+// provider has no embeddings
+func providerEmbed(ctx context.Context, texts []string) ([][]float64, error) {
+	embedder, ok := providerFor(ctx).(llm.Embedder)
+	if !ok {
+		return nil, errNoEmbeddings
+	}
+	return embedder.Embed(ctx, texts)
+}
+
+var errNoEmbeddings = errors.New("provider does not support embeddings")
+
+// embedTexts embeds texts with textEmbedder, falling back to
+// llm.LexicalEmbedding when it fails
+func embedTexts(ctx context.Context, texts []string) [][]float64 {
+	vectors, err := textEmbedder(ctx, texts)
+	if err == nil && len(vectors) == len(texts) {
+		return vectors
+	}
+	if !errors.Is(err, errNoEmbeddings) {
+		logger.GetLogger().Debug("Embedding failed, using lexical embeddings", "texts", len(texts), "error", err)
+	}
+	vectors = make([][]float64, len(texts))
+	for i, text := range texts {
+		vectors[i] = llm.LexicalEmbedding(text)
+	}
+	return vectors
+}
+
+// lexicalScorer builds an IDF-weighted term-overlap scorer over the corpus, so
+// rare query terms outweigh ones that appear in every item
+func lexicalScorer(corpus []string) ShortlistScorer {
+	docFreq := make(map[string]int)
+	for _, text := range corpus {
+		seen := make(map[string]bool)
+		for _, term := range shortlistTerms(text) {
+			if !seen[term] {
+				seen[term] = true
+				docFreq[term]++
+			}
+		}
+	}
+	total := float64(len(corpus))
+
+	return func(query, item string) float64 {
+		counts := make(map[string]int)
+		for _, term := range shortlistTerms(item) {
+			counts[term]++
+		}
+		score := 0.0
+		for _, term := range shortlistTerms(query) {
+			if n := counts[term]; n > 0 {
+				idf := math.Log(1 + total/float64(1+docFreq[term]))
+				score += idf * (1 + math.Log(float64(n)))
+			}
+		}
+		return score
+	}
+}
+
+func shortlistTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,82 @@
+// package ops - Weighted aspect scoring for the Similar operation
+package ops
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/monstercameron/schemaflow/internal/logger"
+)
+
+// similarAspects returns the aspects to compare: WithAspects entries followed
+// by any weighted aspects not already listed, in name order
+func similarAspects(opts SimilarOptions) []string {
+	aspects := append([]string(nil), opts.Aspects...)
+	for _, aspect := range slices.Sorted(maps.Keys(opts.AspectWeights)) {
+		if !slices.Contains(aspects, aspect) {
+			aspects = append(aspects, aspect)
+		}
+	}
+	return aspects
+}
+
+// aspectWeightRules tells the model which aspects must be scored and how
+// much each one counts
+func aspectWeightRules(weights map[string]float64) string {
+	if len(weights) == 0 {
+		return ""
+	}
+	total := 0.0
+	for _, weight := range weights {
+		total += weight
+	}
+	var parts []string
+	for _, aspect := range slices.Sorted(maps.Keys(weights)) {
+		parts = append(parts, fmt.Sprintf("%s (weight %.2f)", aspect, weights[aspect]/total))
+	}
+	return fmt.Sprintf(`
+
+Weighted aspects:
+- Score every one of these aspects, listing each in "matched_aspects" or "differing_aspects" with its exact name: %s
+- Judge each aspect on its own; the overall score is computed from the aspect scores and weights`, strings.Join(parts, ", "))
+}
+
+// weightAspectScores computes the weighted mean of the aspect scores in
+// result, in aspect name order. Weighted aspects the model did not score
+// count as 0.
+func weightAspectScores(weights map[string]float64, result SimilarResult) (float64, []AspectContribution) {
+	scores := make(map[string]float64)
+	for _, aspect := range append(append([]AspectMatch(nil), result.MatchedAspects...), result.DifferingAspects...) {
+		scores[strings.ToLower(strings.TrimSpace(aspect.Aspect))] = math.Max(0, math.Min(1, aspect.Score))
+	}
+
+	total := 0.0
+	for _, weight := range weights {
+		total += weight
+	}
+
+	score := 0.0
+	var contributions []AspectContribution
+	var unscored []string
+	for _, aspect := range slices.Sorted(maps.Keys(weights)) {
+		aspectScore, ok := scores[strings.ToLower(strings.TrimSpace(aspect))]
+		if !ok {
+			unscored = append(unscored, aspect)
+		}
+		weight := weights[aspect] / total
+		contributions = append(contributions, AspectContribution{
+			Aspect:       aspect,
+			Weight:       weight,
+			Score:        aspectScore,
+			Contribution: weight * aspectScore,
+		})
+		score += weight * aspectScore
+	}
+	if len(unscored) > 0 {
+		logger.GetLogger().Warn("Similar response did not score weighted aspects; counting them as 0", "aspects", unscored)
+	}
+	return score, contributions
+}
@@ -0,0 +1,101 @@
+// package ops - Slow-operation warnings
+package ops
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/monstercameron/schemaflow/internal/logger"
+	"github.com/monstercameron/schemaflow/internal/types"
+	"github.com/monstercameron/schemaflow/telemetry"
+)
+
+var (
+	slowThresholdMu sync.RWMutex
+	slowThreshold   time.Duration
+)
+
+// SetSlowThreshold sets the duration after which an operation is reported as
+// slow: a warning is logged and the "slow_operation" metric is recorded with
+// the operation name. The operation is timed from its start across all of its
+// LLM calls and reported once, when the first call to end past the threshold
+// returns. Zero or negative disables the check.
+func SetSlowThreshold(d time.Duration) {
+	slowThresholdMu.Lock()
+	defer slowThresholdMu.Unlock()
+	if d < 0 {
+		d = 0
+	}
+	slowThreshold = d
+}
+
+func getSlowThreshold() time.Duration {
+	slowThresholdMu.RLock()
+	defer slowThresholdMu.RUnlock()
+	return slowThreshold
+}
+
+// operationClock times one operation across all of its LLM calls
+type operationClock struct {
+	start    time.Time
+	reported atomic.Bool
+}
+
+type operationClockKey struct{}
+
+// withOperationClock stamps the start of an operation into ctx, unless ctx
+// already carries the clock of an enclosing operation
+func withOperationClock(ctx context.Context) context.Context {
+	if operationClockFrom(ctx) != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, operationClockKey{}, &operationClock{start: time.Now()})
+}
+
+func operationClockFrom(ctx context.Context) *operationClock {
+	if ctx == nil {
+		return nil
+	}
+	clock, _ := ctx.Value(operationClockKey{}).(*operationClock)
+	return clock
+}
+
+// startSlowCheck returns a func to defer around one LLM call that reports
+// the operation once it has run longer than threshold. Calls made outside a
+// timed operation are timed on their own.
+func startSlowCheck(ctx context.Context, opts types.OpOptions, threshold time.Duration) func() {
+	clock := operationClockFrom(ctx)
+	if clock == nil {
+		clock = operationClockFrom(opts.Context)
+	}
+	if clock == nil {
+		clock = &operationClock{start: time.Now()}
+	}
+	return func() {
+		elapsed := time.Since(clock.start)
+		if elapsed > threshold && clock.reported.CompareAndSwap(false, true) {
+			reportSlow(opts, elapsed, threshold)
+		}
+	}
+}
+
+// reportSlow warns that an operation has run for elapsed, past threshold,
+// naming the operation that issued the call
+func reportSlow(opts types.OpOptions, elapsed, threshold time.Duration) {
+	operation := opts.Operation
+	if operation == "" {
+		operation = "unknown"
+	}
+
+	logger.GetLogger().Warn("Slow operation",
+		"operation", operation,
+		"elapsed", elapsed,
+		"threshold", threshold,
+		"requestID", opts.RequestID,
+	)
+	telemetry.RecordMetric("slow_operation", elapsed.Milliseconds(), map[string]string{
+		"operation": operation,
+	})
+}
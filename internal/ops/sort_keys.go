@@ -0,0 +1,217 @@
+// package ops - Tiered multi-key sorting for the Sort operation
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/monstercameron/schemaflow/internal/config"
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+// SortKey is one tier of a multi-key sort. Set Field to compare a concrete
+// field deterministically, or Criteria for a fuzzy criterion the LLM scores.
+type SortKey struct {
+	// Field is the dotted JSON path of a field of the item type
+	Field string
+
+	// Criteria describes a fuzzy sort criterion, such as "business impact"
+	Criteria string
+
+	// Direction is "ascending" (default) or "descending"
+	Direction string
+}
+
+// validate checks that a key names exactly one of Field or Criteria
+func (k SortKey) validate() error {
+	if (k.Field == "") == (k.Criteria == "") {
+		return fmt.Errorf("sort key must set exactly one of Field or Criteria, got %+v", k)
+	}
+	if k.Direction != "" && k.Direction != "ascending" && k.Direction != "descending" {
+		return fmt.Errorf("invalid sort key direction: %s", k.Direction)
+	}
+	return nil
+}
+
+// sortByKeys orders items by opts.Keys in turn, each key breaking the ties of
+// the ones before it; items equal on every key keep their input order. Field
+// keys are compared locally and each Criteria key costs one LLM call scoring
+// every item.
+func sortByKeys[T any](items []T, opts SortOptions, opOptions types.OpOptions) ([]T, error) {
+	itemType := reflect.TypeOf(items).Elem()
+	for _, key := range opts.Keys {
+		if key.Field == "" {
+			continue
+		}
+		if err := checkFieldPath(itemType, key.Field); err != nil {
+			return nil, types.SortError{Items: interfaceSlice(items), Reason: fmt.Sprintf("sort key: %v", err)}
+		}
+	}
+
+	decoded := make([]any, len(items))
+	for i, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, types.SortError{Items: interfaceSlice(items), Reason: fmt.Sprintf("failed to marshal item %d: %v", i, err)}
+		}
+		decoder := json.NewDecoder(strings.NewReader(string(data)))
+		decoder.UseNumber()
+		if err := decoder.Decode(&decoded[i]); err != nil {
+			return nil, types.SortError{Items: interfaceSlice(items), Reason: fmt.Sprintf("failed to decode item %d: %v", i, err)}
+		}
+	}
+
+	// values[k][i] is item i's value for key k; nil sorts last
+	values := make([][]any, len(opts.Keys))
+	for k, key := range opts.Keys {
+		values[k] = make([]any, len(items))
+		if key.Field != "" {
+			for i := range items {
+				values[k][i] = fieldValue(decoded[i], key.Field)
+			}
+			continue
+		}
+		scores, err := scoreSortCriteria(items, key.Criteria, opOptions)
+		if err != nil {
+			return nil, types.SortError{Items: interfaceSlice(items), Reason: fmt.Sprintf("failed to score %q: %v", key.Criteria, err), Cause: err}
+		}
+		for i, score := range scores {
+			values[k][i] = score
+		}
+	}
+
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		for k, key := range opts.Keys {
+			cmp := compareSortValues(values[k][order[a]], values[k][order[b]])
+			if cmp == 0 {
+				continue
+			}
+			// Missing values sort last whatever the direction
+			if values[k][order[a]] == nil || values[k][order[b]] == nil {
+				return values[k][order[b]] == nil
+			}
+			if key.Direction == "descending" {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+
+	result := make([]T, len(items))
+	for i, idx := range order {
+		result[i] = items[idx]
+	}
+	return result, nil
+}
+
+// fieldValue returns the value at a dotted JSON path of a decoded item, or
+// nil when the path is absent
+func fieldValue(item any, path string) any {
+	for _, segment := range strings.Split(path, ".") {
+		obj, ok := item.(map[string]any)
+		if !ok {
+			return nil
+		}
+		item = obj[segment]
+	}
+	return item
+}
+
+// compareSortValues orders two decoded JSON values: numbers numerically,
+// strings case-insensitively, false before true and nil after everything
+func compareSortValues(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	if x, ok := sortNumber(a); ok {
+		if y, ok := sortNumber(b); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	if x, ok := a.(bool); ok {
+		if y, ok := b.(bool); ok {
+			switch {
+			case x == y:
+				return 0
+			case !x:
+				return -1
+			}
+			return 1
+		}
+	}
+	return strings.Compare(strings.ToLower(fmt.Sprint(a)), strings.ToLower(fmt.Sprint(b)))
+}
+
+func sortNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// scoreSortCriteria asks the LLM to score every item for a fuzzy criterion
+// in one call, returning one score per item in input order
+func scoreSortCriteria[T any](items []T, criteria string, opOptions types.OpOptions) ([]float64, error) {
+	ctx, cancel := context.WithTimeout(opOptions.Context, config.GetTimeout())
+	defer cancel()
+
+	itemsJSON, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+
+	systemPrompt := fmt.Sprintf(`You are a sorting scorer. Score every item for one criterion.
+
+Return a JSON object with:
+{
+  "scores": [0.0-1.0, ...]
+}
+
+Rules:
+- Give exactly %d scores, one per item, in the order the items are listed
+- A higher score means the item has more of the criterion
+- Give items that are equal on the criterion the same score
+- Return only valid JSON`, len(items))
+
+	userPrompt := fmt.Sprintf("Criterion: %s\n\nItems:\n%s", criteria, string(itemsJSON))
+
+	response, err := callLLM(ctx, systemPrompt, userPrompt, opOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed struct {
+		Scores []float64 `json:"scores"`
+	}
+	if err := ParseJSON(response, &parsed); err != nil {
+		return nil, err
+	}
+	if len(parsed.Scores) != len(items) {
+		return nil, fmt.Errorf("received %d scores for %d items", len(parsed.Scores), len(items))
+	}
+	return parsed.Scores, nil
+}
@@ -0,0 +1,59 @@
+// package ops - Pluggable state persistence for resumable operations
+package ops
+
+import (
+	"context"
+	"sync"
+)
+
+// StateStore persists opaque state between runs so long-running operations
+// can resume after a failure. Implementations must be safe for concurrent use.
+type StateStore interface {
+	// Load returns the value stored under key and whether it was found
+	Load(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Save stores value under key, replacing any previous value
+	Save(ctx context.Context, key string, value []byte) error
+
+	// Delete removes key; deleting a missing key is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// MemoryStateStore is an in-process StateStore, useful for tests and for
+// resuming within a single process
+type MemoryStateStore struct {
+	mu     sync.RWMutex
+	values map[string][]byte
+}
+
+// NewMemoryStateStore creates an empty in-memory state store
+func NewMemoryStateStore() *MemoryStateStore {
+	return &MemoryStateStore{values: make(map[string][]byte)}
+}
+
+// Load returns a copy of the value stored under key
+func (s *MemoryStateStore) Load(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.values[key]
+	if !ok {
+		return nil, false, nil
+	}
+	return append([]byte(nil), value...), true, nil
+}
+
+// Save stores a copy of value under key
+func (s *MemoryStateStore) Save(ctx context.Context, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = append([]byte(nil), value...)
+	return nil
+}
+
+// Delete removes key from the store
+func (s *MemoryStateStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+	return nil
+}
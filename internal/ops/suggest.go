@@ -172,6 +172,7 @@ func Suggest[T any](input any, opts SuggestOptions) ([]T, error) {
 	}
 
 	opOptions := opts.toOpOptions()
+	opOptions.Operation = "suggest"
 
 	// Build suggestion instructions
 	var instructions []string
@@ -210,7 +211,11 @@ func Suggest[T any](input any, opts SuggestOptions) ([]T, error) {
 	}
 	opOptions.Steering = steering
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.GetTimeout())
+	ctx := opOptions.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, config.GetTimeout())
 	defer cancel()
 
 	// Marshal input for LLM
@@ -0,0 +1,150 @@
+// package ops - Summaries filled into caller-defined structs
+package ops
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/monstercameron/schemaflow/internal/config"
+	"github.com/monstercameron/schemaflow/internal/logger"
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+// StructuredSummary is a ready-made target for SummarizeStructured: a
+// headline, the key points as bullets and a breakdown by section
+type StructuredSummary struct {
+	// Headline states the main takeaway in one line
+	Headline string `json:"headline"`
+
+	// Bullets are the key points of the whole text
+	Bullets []string `json:"bullets"`
+
+	// Sections summarize the text's main topics in order
+	Sections []SummarySection `json:"sections"`
+}
+
+// SummarySection is one topic of a StructuredSummary
+type SummarySection struct {
+	// Heading names the topic
+	Heading string `json:"heading"`
+
+	// Summary is a short prose summary of the topic
+	Summary string `json:"summary"`
+
+	// Bullets are the topic's key points
+	Bullets []string `json:"bullets,omitempty"`
+}
+
+// SummarizeStructured summarizes input into the fields of T, a struct such as
+// StructuredSummary, instead of a prose string. The model fills every field
+// of T from the text: string fields with short summaries, string slices with
+// bullet points and slices of structs with one element per section or topic.
+// Length, style, focus, preservation and query options apply as they do to
+// Summarize.
+func SummarizeStructured[T any](input string, opts SummarizeOptions) (T, error) {
+	var result T
+	log := logger.GetLogger()
+	log.Debug("Starting summarize structured operation", "requestID", opts.CommonOptions.RequestID, "inputLength", len(input))
+
+	if err := opts.Validate(); err != nil {
+		log.Error("SummarizeStructured operation validation failed", "requestID", opts.CommonOptions.RequestID, "error", err)
+		return result, fmt.Errorf("invalid options: %w", err)
+	}
+
+	targetType := reflect.TypeOf(result)
+	if structType(targetType) == nil {
+		return result, types.SummarizeError{
+			Input:  input,
+			Length: len(input),
+			Reason: fmt.Sprintf("structured summary requires a struct target type, got %v", targetType),
+		}
+	}
+
+	opt := summarizeOpOptions(opts)
+	opt.Operation = "summarize"
+
+	ctx, cancel := context.WithTimeout(opt.Context, config.GetTimeout())
+	defer cancel()
+
+	systemPrompt := fmt.Sprintf(`You are a text summarization expert. Summarize the text into a structured report.
+
+Respond ONLY with valid JSON matching this schema:
+%s%s
+
+Rules:
+- Fill every field from the text; leave a field empty only when the text has nothing for it
+- Headline and title fields are a single line stating the main takeaway
+- Lists of strings are bullet points: one concise point per element, without bullet characters
+- Lists of objects are sections: one element per main topic of the text, in the order the text covers them
+- Maintain the most important points and preserve critical details
+- Never add information that is not in the text`, GenerateTypeSchema(targetType), nestedTypeSchemas(targetType))
+	systemPrompt += summarizeQueryRule(opts.Query)
+
+	var inputLanguage string
+	if opt.PreserveLanguage {
+		var rule string
+		inputLanguage, rule = preserveLanguageRule(input)
+		systemPrompt += rule
+	}
+
+	userPrompt := fmt.Sprintf("Summarize this text:\n%s", input)
+
+	response, err := callLLM(ctx, systemPrompt, userPrompt, opt)
+	if err != nil {
+		log.Error("SummarizeStructured operation LLM call failed", "requestID", opts.CommonOptions.RequestID, "error", err)
+		return result, types.SummarizeError{
+			Input:  input,
+			Length: len(input),
+			Reason: err.Error(),
+			Cause:  err,
+		}
+	}
+
+	if err := ParseJSON(response, &result); err != nil {
+		log.Error("SummarizeStructured operation parse failed", "requestID", opts.CommonOptions.RequestID, "error", err)
+		return result, types.SummarizeError{
+			Input:  input,
+			Length: len(input),
+			Reason: fmt.Sprintf("failed to parse structured summary: %v", err),
+			Cause:  err,
+		}
+	}
+
+	warnIfLanguageChanged("SummarizeStructured", inputLanguage, jsonText(response), opts.CommonOptions.RequestID)
+	log.Debug("SummarizeStructured operation succeeded", "requestID", opts.CommonOptions.RequestID, "targetType", targetType.String())
+
+	return result, nil
+}
+
+// nestedTypeSchemas renders the schema of every struct type nested in
+// targetType's fields, which GenerateTypeSchema only names
+func nestedTypeSchemas(targetType reflect.Type) string {
+	var schemas string
+	seen := map[reflect.Type]bool{}
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		t = structType(t)
+		if t == nil {
+			return
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() || field.Tag.Get("json") == "-" {
+				continue
+			}
+			nested := field.Type
+			for nested.Kind() == reflect.Ptr || nested.Kind() == reflect.Slice || nested.Kind() == reflect.Array || nested.Kind() == reflect.Map {
+				nested = nested.Elem()
+			}
+			if nested.Kind() != reflect.Struct || nested == timeType || seen[nested] {
+				continue
+			}
+			seen[nested] = true
+			schemas += fmt.Sprintf("\n\nwhere %s is:\n%s", nested.String(), GenerateTypeSchema(nested))
+			walk(nested)
+		}
+	}
+	walk(targetType)
+	return schemas
+}
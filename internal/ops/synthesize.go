@@ -228,6 +228,7 @@ func Synthesize[T any](sources []any, opts SynthesizeOptions) (SynthesizeResult[
 	}
 
 	opt := opts.toOpOptions()
+	opt.Operation = "synthesize"
 
 	ctx := opt.Context
 	if ctx == nil {
@@ -0,0 +1,204 @@
+// package ops - Deterministic checks for validate and enum struct tags
+package ops
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/monstercameron/schemaflow/internal/logger"
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+// checkTagConstraints reports every value in data that breaks an `enum` or
+// `validate` struct tag, walking nested structs, slices and maps. Supported
+// tags are `enum:"a,b,c"` and comma-separated `validate` rules: required,
+// oneof=a b c, min=N, max=N, len=N and email. min, max and len bound numbers
+// by value and strings, slices and maps by length. Zero values are only
+// checked by required.
+func checkTagConstraints(data any) []ValidationIssue {
+	var issues []ValidationIssue
+	collectTagViolations(reflect.ValueOf(data), "", &issues)
+	return issues
+}
+
+// collectTagViolations appends the tag violations found under v, addressing
+// them by JSON path from path
+func collectTagViolations(v reflect.Value, path string, issues *[]ValidationIssue) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() || field.Tag.Get("json") == "-" {
+				continue
+			}
+			fieldPath := joinPath(path, jsonFieldName(field))
+			checkFieldTags(v.Field(i), field, fieldPath, issues)
+			collectTagViolations(v.Field(i), fieldPath, issues)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			collectTagViolations(v.Index(i), fmt.Sprintf("%s[%d]", path, i), issues)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			collectTagViolations(iter.Value(), joinPath(path, fmt.Sprint(iter.Key().Interface())), issues)
+		}
+	}
+}
+
+// checkFieldTags checks one field value against its enum and validate tags
+func checkFieldTags(v reflect.Value, field reflect.StructField, path string, issues *[]ValidationIssue) {
+	enum, hasEnum := field.Tag.Lookup("enum")
+	rules := field.Tag.Get("validate")
+	if !hasEnum && rules == "" {
+		return
+	}
+
+	add := func(code, message, suggestion string) {
+		*issues = append(*issues, ValidationIssue{
+			Field:      path,
+			Severity:   "error",
+			Message:    message,
+			Suggestion: suggestion,
+			ErrorCode:  code,
+		})
+	}
+
+	if v.IsZero() {
+		for _, rule := range strings.Split(rules, ",") {
+			if strings.TrimSpace(rule) == "required" {
+				add(ErrorCodeRequired, "value is required", "provide a non-empty value")
+			}
+		}
+		return
+	}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+
+	if hasEnum {
+		if allowed := splitTrimmed(enum, ","); !containsValue(allowed, v) {
+			add(ErrorCodeEnum, fmt.Sprintf("%v is not one of the allowed values", v.Interface()),
+				"use one of: "+strings.Join(allowed, ", "))
+		}
+	}
+
+	for _, rule := range strings.Split(rules, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch name {
+		case "oneof":
+			if allowed := strings.Fields(arg); !containsValue(allowed, v) {
+				add(ErrorCodeEnum, fmt.Sprintf("%v is not one of the allowed values", v.Interface()),
+					"use one of: "+strings.Join(allowed, ", "))
+			}
+		case "min", "max", "len":
+			bound, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				continue
+			}
+			measure, unit, ok := tagMeasure(v)
+			if !ok {
+				continue
+			}
+			switch {
+			case name == "min" && measure < bound:
+				add(ErrorCodeOutOfRange, fmt.Sprintf("%s %v is below the minimum of %s", unit, measure, arg), "use a "+unit+" of at least "+arg)
+			case name == "max" && measure > bound:
+				add(ErrorCodeOutOfRange, fmt.Sprintf("%s %v is above the maximum of %s", unit, measure, arg), "use a "+unit+" of at most "+arg)
+			case name == "len" && measure != bound:
+				add(ErrorCodeOutOfRange, fmt.Sprintf("%s %v is not exactly %s", unit, measure, arg), "use a "+unit+" of exactly "+arg)
+			}
+		case "email":
+			if v.Kind() != reflect.String {
+				continue
+			}
+			if addr, err := mail.ParseAddress(v.String()); err != nil || addr.Address != v.String() {
+				add(ErrorCodeInvalidFormat, fmt.Sprintf("%q is not a valid email address", v.String()), "use an address like name@example.com")
+			}
+		}
+	}
+}
+
+// tagMeasure returns the quantity min, max and len bound for v: the value of
+// a number, or the length of a string, slice or map
+func tagMeasure(v reflect.Value) (float64, string, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), "value", true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), "value", true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), "value", true
+	case reflect.String:
+		return float64(len([]rune(v.String()))), "length", true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), "length", true
+	}
+	return 0, "", false
+}
+
+// containsValue reports whether v, formatted as text, is one of allowed
+func containsValue(allowed []string, v reflect.Value) bool {
+	text := fmt.Sprint(v.Interface())
+	for _, candidate := range allowed {
+		if candidate == text {
+			return true
+		}
+	}
+	return false
+}
+
+// splitTrimmed splits s on sep and trims each part, dropping empty ones
+func splitTrimmed(s, sep string) []string {
+	var parts []string
+	for _, part := range strings.Split(s, sep) {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// tagViolationList renders issues as one "- path: message (suggestion)" line each
+func tagViolationList(issues []ValidationIssue) string {
+	lines := make([]string, len(issues))
+	for i, issue := range issues {
+		lines[i] = fmt.Sprintf("- %s: %s (%s)", issue.Field, issue.Message, issue.Suggestion)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// correctTagViolations re-prompts once with the tag violations found in a
+// previous response, returning the corrected response
+func correctTagViolations(ctx context.Context, systemPrompt, userPrompt, response string, issues []ValidationIssue, opt types.OpOptions) (string, error) {
+	logger.GetLogger().Warn("Output violates target tags, re-prompting",
+		"requestID", opt.RequestID,
+		"violations", len(issues),
+	)
+	correction := fmt.Sprintf(`%s
+
+A previous answer was:
+%s
+
+It breaks these constraints of the target type:
+%s
+
+Fix these fields, keep every other field as it was, and return the complete corrected JSON.`,
+		userPrompt, response, tagViolationList(issues))
+	return callLLM(ctx, systemPrompt, correction, opt)
+}
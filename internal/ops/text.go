@@ -4,17 +4,35 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/monstercameron/schemaflow/internal/config"
 	"github.com/monstercameron/schemaflow/internal/logger"
 	"github.com/monstercameron/schemaflow/internal/types"
 )
 
+// TextResult is the metadata every text operation's WithMetadata variant
+// reports, embedded in the operation-specific result
+type TextResult struct {
+	// Text is the produced content
+	Text string `json:"text"`
+
+	// Confidence score for the output quality (0.0-1.0)
+	Confidence float64 `json:"confidence"`
+
+	// TokensUsed is the provider-reported token usage, estimated from prompt
+	// and response length when the provider reports none
+	TokensUsed int `json:"tokens_used"`
+
+	// Language of the produced text
+	Language string `json:"language,omitempty"`
+}
+
 // SummarizeResult contains the summary with metadata
 type SummarizeResult struct {
-	// Text is the summarized content
-	Text string `json:"text"`
+	TextResult
 
 	// CompressionRatio is output length / input length
 	CompressionRatio float64 `json:"compression_ratio"`
@@ -22,8 +40,9 @@ type SummarizeResult struct {
 	// KeyPoints are the main points extracted
 	KeyPoints []string `json:"key_points,omitempty"`
 
-	// Confidence score for the summary quality (0.0-1.0)
-	Confidence float64 `json:"confidence"`
+	// Relevant is false when a WithQuery summary found the text does not
+	// address the query; it is always true without a query
+	Relevant bool `json:"relevant"`
 
 	// Metadata contains additional operation information
 	Metadata map[string]any `json:"metadata,omitempty"`
@@ -31,15 +50,11 @@ type SummarizeResult struct {
 
 // RewriteResult contains the rewritten text with metadata
 type RewriteResult struct {
-	// Text is the rewritten content
-	Text string `json:"text"`
+	TextResult
 
 	// ChangesMade describes what was changed
 	ChangesMade []string `json:"changes_made,omitempty"`
 
-	// Confidence score for the rewrite quality (0.0-1.0)
-	Confidence float64 `json:"confidence"`
-
 	// ToneAchieved describes the tone of the output
 	ToneAchieved string `json:"tone_achieved,omitempty"`
 
@@ -49,15 +64,11 @@ type RewriteResult struct {
 
 // TranslateResult contains the translation with metadata
 type TranslateResult struct {
-	// Text is the translated content
-	Text string `json:"text"`
+	TextResult
 
 	// SourceLanguageDetected is the detected source language (if not specified)
 	SourceLanguageDetected string `json:"source_language_detected,omitempty"`
 
-	// Confidence score for the translation quality (0.0-1.0)
-	Confidence float64 `json:"confidence"`
-
 	// Alternatives are alternative translations for ambiguous phrases
 	Alternatives []TranslationAlternative `json:"alternatives,omitempty"`
 
@@ -74,8 +85,7 @@ type TranslationAlternative struct {
 
 // ExpandResult contains the expanded text with metadata
 type ExpandResult struct {
-	// Text is the expanded content
-	Text string `json:"text"`
+	TextResult
 
 	// ExpansionRatio is output length / input length
 	ExpansionRatio float64 `json:"expansion_ratio"`
@@ -83,15 +93,13 @@ type ExpandResult struct {
 	// AddedContent describes what was added
 	AddedContent []string `json:"added_content,omitempty"`
 
-	// Confidence score for the expansion quality (0.0-1.0)
-	Confidence float64 `json:"confidence"`
-
 	// Metadata contains additional operation information
 	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
 // Summarize creates a concise summary of the input text.
 // For metadata including key points and confidence, use SummarizeWithMetadata.
+// With WithQuery the summary covers only what the text says about the query.
 func Summarize(input string, opts SummarizeOptions) (string, error) {
 	log := logger.GetLogger()
 	log.Debug("Starting summarize operation", "requestID", opts.CommonOptions.RequestID, "inputLength", len(input))
@@ -102,37 +110,17 @@ func Summarize(input string, opts SummarizeOptions) (string, error) {
 		return "", fmt.Errorf("invalid options: %w", err)
 	}
 
-	// Build summarization instructions
-	var instructions []string
-
-	if opts.TargetLength > 0 {
-		instructions = append(instructions, fmt.Sprintf("Target length: %d %s", opts.TargetLength, opts.LengthUnit))
-	}
-
-	if opts.BulletPoints {
-		instructions = append(instructions, "Format as bullet points")
-	} else if opts.Style != "" {
-		instructions = append(instructions, fmt.Sprintf("Style: %s", opts.Style))
-	}
+	var cancelOperation context.CancelFunc
+	opts.CommonOptions, cancelOperation = opts.CommonOptions.startOperation()
+	defer cancelOperation()
 
-	if len(opts.FocusAreas) > 0 {
-		instructions = append(instructions, fmt.Sprintf("Focus on: %s", strings.Join(opts.FocusAreas, ", ")))
+	opt := summarizeOpOptions(opts)
+	opt.Operation = "summarize"
+	if opts.AutoChunk && opt.MaxInputBytes > 0 && len(input) > opt.MaxInputBytes {
+		return summarizeChunked(input, opts, opt.MaxInputBytes)
 	}
 
-	if len(opts.PreserveInfo) > 0 {
-		instructions = append(instructions, fmt.Sprintf("Must preserve: %s", strings.Join(opts.PreserveInfo, ", ")))
-	}
-
-	opt := opts.toOpOptions()
-	if len(instructions) > 0 {
-		steering := strings.Join(instructions, ". ")
-		if opts.OpOptions.Steering != "" {
-			steering = opts.OpOptions.Steering + ". " + steering
-		}
-		opt.Steering = steering
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), config.GetTimeout())
+	ctx, cancel := context.WithTimeout(opt.Context, config.GetTimeout())
 	defer cancel()
 
 	systemPrompt := `You are a text summarization expert. Create concise summaries that preserve key information.
@@ -142,6 +130,14 @@ Rules:
 - Use clear, concise language
 - Preserve critical details and context
 - Keep the original tone when appropriate`
+	systemPrompt += summarizeQueryRule(opts.Query)
+
+	var inputLanguage string
+	if opt.PreserveLanguage {
+		var rule string
+		inputLanguage, rule = preserveLanguageRule(input)
+		systemPrompt += rule
+	}
 
 	userPrompt := fmt.Sprintf("Summarize this text:\n%s", input)
 
@@ -152,10 +148,12 @@ Rules:
 			Input:  input,
 			Length: len(input),
 			Reason: err.Error(),
+			Cause:  err,
 		}
 	}
 
 	result := strings.TrimSpace(response)
+	warnIfLanguageChanged("Summarize", inputLanguage, result, opts.CommonOptions.RequestID)
 	log.Debug("Summarize operation succeeded", "requestID", opts.CommonOptions.RequestID, "outputLength", len(result))
 
 	return result, nil
@@ -173,38 +171,12 @@ func SummarizeWithMetadata(input string, opts SummarizeOptions) (SummarizeResult
 		return SummarizeResult{}, fmt.Errorf("invalid options: %w", err)
 	}
 
-	// Build summarization instructions
-	var instructions []string
-
-	if opts.TargetLength > 0 {
-		instructions = append(instructions, fmt.Sprintf("Target length: %d %s", opts.TargetLength, opts.LengthUnit))
-	}
-
-	if opts.BulletPoints {
-		instructions = append(instructions, "Format as bullet points")
-	} else if opts.Style != "" {
-		instructions = append(instructions, fmt.Sprintf("Style: %s", opts.Style))
-	}
+	opt := summarizeOpOptions(opts)
+	opt.Operation = "summarize"
 
-	if len(opts.FocusAreas) > 0 {
-		instructions = append(instructions, fmt.Sprintf("Focus on: %s", strings.Join(opts.FocusAreas, ", ")))
-	}
-
-	if len(opts.PreserveInfo) > 0 {
-		instructions = append(instructions, fmt.Sprintf("Must preserve: %s", strings.Join(opts.PreserveInfo, ", ")))
-	}
-
-	opt := opts.toOpOptions()
-	if len(instructions) > 0 {
-		steering := strings.Join(instructions, ". ")
-		if opts.OpOptions.Steering != "" {
-			steering = opts.OpOptions.Steering + ". " + steering
-		}
-		opt.Steering = steering
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), config.GetTimeout())
+	ctx, cancel := context.WithTimeout(opt.Context, config.GetTimeout())
 	defer cancel()
+	ctx, usage := withUsageRecorder(ctx)
 
 	systemPrompt := `You are a text summarization expert. Create concise summaries that preserve key information.
 
@@ -212,13 +184,26 @@ Respond ONLY with valid JSON in this exact format:
 {
   "text": "The summarized text here",
   "key_points": ["Main point 1", "Main point 2", "Main point 3"],
-  "confidence": 0.85
+  "confidence": 0.85,
+  "language": "English"
 }
 
 Rules:
 - "text": The complete summary
 - "key_points": 3-7 main points extracted from the text
-- "confidence": A value from 0.0 to 1.0 indicating summary quality (1.0 = excellent)`
+- "confidence": A value from 0.0 to 1.0 indicating summary quality (1.0 = excellent)
+- "language": The language of the output text`
+	if opts.Query != "" {
+		systemPrompt += summarizeQueryRule(opts.Query) + `
+- Also include "relevant": true if the text addresses the query, or false if it does not; when false, "text" says briefly that the text does not cover it and "key_points" is empty`
+	}
+
+	var inputLanguage string
+	if opt.PreserveLanguage {
+		var rule string
+		inputLanguage, rule = preserveLanguageRule(input)
+		systemPrompt += rule
+	}
 
 	userPrompt := fmt.Sprintf("Summarize this text and provide metadata:\n%s", input)
 
@@ -229,6 +214,7 @@ Rules:
 			Input:  input,
 			Length: len(input),
 			Reason: err.Error(),
+			Cause:  err,
 		}
 	}
 
@@ -237,6 +223,8 @@ Rules:
 		Text       string   `json:"text"`
 		KeyPoints  []string `json:"key_points"`
 		Confidence float64  `json:"confidence"`
+		Language   string   `json:"language"`
+		Relevant   *bool    `json:"relevant"`
 	}
 	if err := json.Unmarshal([]byte(response), &parsed); err != nil {
 		// Fallback: treat entire response as summary text
@@ -244,19 +232,29 @@ Rules:
 		summaryText := strings.TrimSpace(response)
 		compressionRatio := float64(len(summaryText)) / float64(len(input))
 		return SummarizeResult{
-			Text:             summaryText,
+			TextResult: TextResult{
+				Text:       summaryText,
+				Confidence: 0.7, // Default confidence for fallback
+				TokensUsed: usage.total(systemPrompt, userPrompt, response),
+			},
 			CompressionRatio: compressionRatio,
-			Confidence:       0.7, // Default confidence for fallback
+			Relevant:         true,
 		}, nil
 	}
 
 	compressionRatio := float64(len(parsed.Text)) / float64(len(input))
+	warnIfLanguageChanged("SummarizeWithMetadata", inputLanguage, parsed.Text, opts.CommonOptions.RequestID)
 
 	result := SummarizeResult{
-		Text:             parsed.Text,
+		TextResult: TextResult{
+			Text:       parsed.Text,
+			Confidence: parsed.Confidence,
+			TokensUsed: usage.total(systemPrompt, userPrompt, response),
+			Language:   parsed.Language,
+		},
 		CompressionRatio: compressionRatio,
 		KeyPoints:        parsed.KeyPoints,
-		Confidence:       parsed.Confidence,
+		Relevant:         parsed.Relevant == nil || *parsed.Relevant,
 	}
 
 	log.Debug("SummarizeWithMetadata operation succeeded", "requestID", opts.CommonOptions.RequestID, "outputLength", len(result.Text), "keyPoints", len(result.KeyPoints))
@@ -264,6 +262,298 @@ Rules:
 	return result, nil
 }
 
+// summarizeOpOptions converts SummarizeOptions to OpOptions, folding the
+// length, style, focus and preservation options into the steering prompt
+func summarizeOpOptions(opts SummarizeOptions) types.OpOptions {
+	var instructions []string
+
+	if opts.TargetLength > 0 {
+		instructions = append(instructions, fmt.Sprintf("Target length: %d %s", opts.TargetLength, opts.LengthUnit))
+	}
+
+	if opts.BulletPoints {
+		instructions = append(instructions, "Format as bullet points")
+	} else if opts.Style != "" {
+		instructions = append(instructions, fmt.Sprintf("Style: %s", opts.Style))
+	}
+
+	if len(opts.FocusAreas) > 0 {
+		instructions = append(instructions, fmt.Sprintf("Focus on: %s", strings.Join(opts.FocusAreas, ", ")))
+	}
+
+	if len(opts.PreserveInfo) > 0 {
+		instructions = append(instructions, fmt.Sprintf("Must preserve: %s", strings.Join(opts.PreserveInfo, ", ")))
+	}
+
+	opt := opts.toOpOptions()
+	if len(instructions) > 0 {
+		steering := strings.Join(instructions, ". ")
+		if opts.OpOptions.Steering != "" {
+			steering = opts.OpOptions.Steering + ". " + steering
+		}
+		opt.Steering = steering
+	}
+	return opt
+}
+
+// summarizeQueryRule renders the system prompt rule for a WithQuery summary
+func summarizeQueryRule(query string) string {
+	if strings.TrimSpace(query) == "" {
+		return ""
+	}
+	return fmt.Sprintf(`
+- Focus the summary on answering this query: %q
+- Include only information relevant to the query and leave out unrelated content
+- If the text does not address the query, say so plainly instead of summarizing other content`, strings.TrimSpace(query))
+}
+
+// SummarizeAll reduces a collection into a single summary. Items are rendered
+// (using opts.Field when set), summarized in chunks of opts.ChunkSize, and the
+// chunk summaries are combined until one summary remains. TargetLength applies
+// to the final summary only; a word-based target is enforced as a hard cap.
+func SummarizeAll[T any](items []T, opts SummarizeOptions) (string, error) {
+	log := logger.GetLogger()
+	log.Debug("Starting summarize all operation", "requestID", opts.CommonOptions.RequestID, "itemCount", len(items))
+
+	if err := opts.Validate(); err != nil {
+		log.Error("SummarizeAll operation validation failed", "requestID", opts.CommonOptions.RequestID, "error", err)
+		return "", fmt.Errorf("invalid options: %w", err)
+	}
+	if len(items) == 0 {
+		return "", fmt.Errorf("no items to summarize")
+	}
+
+	texts := make([]string, 0, len(items))
+	for i, item := range items {
+		text, err := summarizeItemText(item, opts.Field)
+		if err != nil {
+			return "", fmt.Errorf("item %d: %w", i, err)
+		}
+		if strings.TrimSpace(text) != "" {
+			texts = append(texts, text)
+		}
+	}
+	if len(texts) == 0 {
+		return "", fmt.Errorf("no non-empty items to summarize")
+	}
+
+	var cancelOperation context.CancelFunc
+	opts.CommonOptions, cancelOperation = opts.CommonOptions.startOperation()
+	defer cancelOperation()
+
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = 20
+	}
+
+	// Intermediate summaries are unbounded; only the final pass honors TargetLength
+	mapOpts := opts
+	mapOpts.TargetLength = 0
+
+	for round := 1; len(texts) > chunkSize; round++ {
+		var partials []string
+		for start := 0; start < len(texts); start += chunkSize {
+			end := min(start+chunkSize, len(texts))
+			partial, err := Summarize(joinSummaryItems(texts[start:end]), mapOpts)
+			if err != nil {
+				log.Error("SummarizeAll chunk failed", "requestID", opts.CommonOptions.RequestID, "round", round, "chunkStart", start, "error", err)
+				return "", err
+			}
+			partials = append(partials, partial)
+		}
+		log.Debug("SummarizeAll round completed", "requestID", opts.CommonOptions.RequestID, "round", round, "summaries", len(partials))
+		if len(partials) >= len(texts) {
+			return "", fmt.Errorf("summarize all round %d did not reduce %d summaries", round, len(texts))
+		}
+		texts = partials
+	}
+
+	summary, err := Summarize(joinSummaryItems(texts), opts)
+	if err != nil {
+		return "", err
+	}
+	if opts.TargetLength > 0 && opts.LengthUnit == "words" {
+		summary = truncateWords(summary, opts.TargetLength)
+	}
+
+	log.Debug("SummarizeAll operation succeeded", "requestID", opts.CommonOptions.RequestID, "outputLength", len(summary))
+	return summary, nil
+}
+
+// summarizeItemText renders a collection item as text, selecting a single
+// struct field when field is set
+func summarizeItemText(item any, field string) (string, error) {
+	value := reflect.ValueOf(item)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return "", nil
+		}
+		value = value.Elem()
+	}
+
+	if field != "" {
+		if value.Kind() != reflect.Struct {
+			return "", fmt.Errorf("field %q selected but item is %s, not a struct", field, value.Kind())
+		}
+		found := false
+		for i := 0; i < value.NumField(); i++ {
+			sf := value.Type().Field(i)
+			if sf.IsExported() && (sf.Name == field || jsonFieldName(sf) == field) {
+				value = value.Field(i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "", fmt.Errorf("field %q not found on %s", field, value.Type())
+		}
+	}
+
+	if value.Kind() == reflect.String {
+		return value.String(), nil
+	}
+	data, err := json.Marshal(value.Interface())
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal item: %w", err)
+	}
+	return string(data), nil
+}
+
+// maxSummarizeRounds bounds the chunking rounds of an auto-chunked Summarize;
+// each round normally shrinks the text many times over
+const maxSummarizeRounds = 8
+
+// summarizeChunked summarizes an input larger than maxBytes by summarizing
+// chunks within the limit and then the combined chunk summaries, repeating
+// while the combined text is still too large. A round that does not shrink
+// the text, or running out of rounds, fails with types.ErrInputTooLarge.
+// When the context ends first, the chunk summaries finished so far are
+// returned with the error.
+func summarizeChunked(input string, opts SummarizeOptions, maxBytes int) (string, error) {
+	log := logger.GetLogger()
+	ctx := opts.GetContext()
+	partialResult := func(partials []string, round, done, total int) (string, error) {
+		return strings.Join(partials, "\n\n"), types.SummarizeError{
+			Input:  input,
+			Length: len(input),
+			Reason: fmt.Sprintf("stopped after %d of %d chunks in round %d", done, total, round),
+			Cause:  ctx.Err(),
+		}
+	}
+
+	// Intermediate summaries are unbounded; only the final pass honors TargetLength
+	mapOpts := opts
+	mapOpts.AutoChunk = false
+	mapOpts.TargetLength = 0
+
+	text := input
+	for round := 1; len(text) > maxBytes; round++ {
+		if round > maxSummarizeRounds {
+			return "", types.SummarizeError{
+				Input:  input,
+				Length: len(input),
+				Reason: fmt.Sprintf("chunk summaries (%d bytes) still exceed the %d-byte limit after %d rounds", len(text), maxBytes, maxSummarizeRounds),
+				Cause:  types.ErrInputTooLarge,
+			}
+		}
+		chunks := chunkText(text, maxBytes)
+		partials := make([]string, len(chunks))
+		for i, chunk := range chunks {
+			if ctx.Err() != nil {
+				return partialResult(partials[:i], round, i, len(chunks))
+			}
+			key := summaryChunkKey(chunk, mapOpts)
+			if partial, ok := lookupChunkCache(key); ok {
+				log.Debug("Summarize chunk served from cache", "requestID", opts.CommonOptions.RequestID, "round", round, "chunk", i)
+				partials[i] = partial
+				continue
+			}
+			partial, err := Summarize(chunk, mapOpts)
+			if err != nil && ctx.Err() != nil {
+				return partialResult(partials[:i], round, i, len(chunks))
+			}
+			if err != nil {
+				log.Error("Summarize chunk failed", "requestID", opts.CommonOptions.RequestID, "round", round, "chunk", i, "error", err)
+				return "", err
+			}
+			storeChunkCache(key, partial)
+			partials[i] = partial
+		}
+		combined := strings.Join(partials, "\n\n")
+		if len(combined) >= len(text) {
+			return "", types.SummarizeError{
+				Input:  input,
+				Length: len(input),
+				Reason: fmt.Sprintf("chunk summaries (%d bytes) did not shrink the input below the %d-byte limit", len(combined), maxBytes),
+				Cause:  types.ErrInputTooLarge,
+			}
+		}
+		log.Debug("Summarize chunking round completed", "requestID", opts.CommonOptions.RequestID, "round", round, "chunks", len(chunks), "outputLength", len(combined))
+		text = combined
+	}
+
+	finalOpts := opts
+	finalOpts.AutoChunk = false
+	summary, err := Summarize(text, finalOpts)
+	if err != nil && ctx.Err() != nil {
+		return text, types.SummarizeError{
+			Input:  input,
+			Length: len(input),
+			Reason: "stopped before the final pass; returning the combined chunk summaries",
+			Cause:  ctx.Err(),
+		}
+	}
+	return summary, err
+}
+
+// chunkText splits text into pieces of at most maxBytes, preferring paragraph,
+// line, sentence and word boundaries and never splitting a UTF-8 sequence
+func chunkText(text string, maxBytes int) []string {
+	var chunks []string
+	for len(text) > maxBytes {
+		cut := 0
+		for _, sep := range []string{"\n\n", "\n", ". ", " "} {
+			if i := strings.LastIndex(text[:maxBytes], sep); i > 0 {
+				cut = i + len(sep)
+				break
+			}
+		}
+		if cut == 0 {
+			cut = maxBytes
+			for cut > 0 && !utf8.RuneStart(text[cut]) {
+				cut--
+			}
+			if cut == 0 {
+				_, cut = utf8.DecodeRuneInString(text)
+			}
+		}
+		if chunk := strings.TrimSpace(text[:cut]); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+		text = text[cut:]
+	}
+	if chunk := strings.TrimSpace(text); chunk != "" {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+func joinSummaryItems(texts []string) string {
+	var b strings.Builder
+	for i, text := range texts {
+		fmt.Fprintf(&b, "[%d] %s\n", i+1, strings.TrimSpace(text))
+	}
+	return b.String()
+}
+
+func truncateWords(text string, limit int) string {
+	words := strings.Fields(text)
+	if len(words) <= limit {
+		return text
+	}
+	return strings.Join(words[:limit], " ")
+}
+
 // Rewrite transforms text according to specified parameters.
 // For metadata including changes made and confidence, use RewriteWithMetadata.
 func Rewrite(input string, opts RewriteOptions) (string, error) {
@@ -312,6 +602,7 @@ func Rewrite(input string, opts RewriteOptions) (string, error) {
 	}
 
 	opt := opts.toOpOptions()
+	opt.Operation = "rewrite"
 	if len(instructions) > 0 {
 		steering := strings.Join(instructions, ". ")
 		if opts.OpOptions.Steering != "" {
@@ -320,7 +611,7 @@ func Rewrite(input string, opts RewriteOptions) (string, error) {
 		opt.Steering = steering
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.GetTimeout())
+	ctx, cancel := context.WithTimeout(opt.Context, config.GetTimeout())
 	defer cancel()
 
 	systemPrompt := `You are a text rewriting expert. Modify text while preserving its core meaning.
@@ -339,6 +630,7 @@ Rules:
 		return "", types.RewriteError{
 			Input:  input,
 			Reason: err.Error(),
+			Cause:  err,
 		}
 	}
 
@@ -396,6 +688,7 @@ func RewriteWithMetadata(input string, opts RewriteOptions) (RewriteResult, erro
 	}
 
 	opt := opts.toOpOptions()
+	opt.Operation = "rewrite"
 	if len(instructions) > 0 {
 		steering := strings.Join(instructions, ". ")
 		if opts.OpOptions.Steering != "" {
@@ -404,8 +697,9 @@ func RewriteWithMetadata(input string, opts RewriteOptions) (RewriteResult, erro
 		opt.Steering = steering
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.GetTimeout())
+	ctx, cancel := context.WithTimeout(opt.Context, config.GetTimeout())
 	defer cancel()
+	ctx, usage := withUsageRecorder(ctx)
 
 	systemPrompt := `You are a text rewriting expert. Modify text while preserving its core meaning.
 
@@ -414,14 +708,16 @@ Respond ONLY with valid JSON in this exact format:
   "text": "The rewritten text here",
   "changes_made": ["Changed tone to professional", "Simplified complex sentences"],
   "tone_achieved": "professional",
-  "confidence": 0.9
+  "confidence": 0.9,
+  "language": "English"
 }
 
 Rules:
 - "text": The complete rewritten text
 - "changes_made": List of specific changes made to the original
 - "tone_achieved": The resulting tone of the rewritten text
-- "confidence": A value from 0.0 to 1.0 indicating rewrite quality`
+- "confidence": A value from 0.0 to 1.0 indicating rewrite quality
+- "language": The language of the output text`
 
 	userPrompt := fmt.Sprintf("Rewrite this text and provide metadata about the changes:\n%s", input)
 
@@ -431,6 +727,7 @@ Rules:
 		return RewriteResult{}, types.RewriteError{
 			Input:  input,
 			Reason: err.Error(),
+			Cause:  err,
 		}
 	}
 
@@ -440,21 +737,29 @@ Rules:
 		ChangesMade  []string `json:"changes_made"`
 		ToneAchieved string   `json:"tone_achieved"`
 		Confidence   float64  `json:"confidence"`
+		Language     string   `json:"language"`
 	}
 	if err := json.Unmarshal([]byte(response), &parsed); err != nil {
 		// Fallback: treat entire response as rewritten text
 		log.Debug("RewriteWithMetadata JSON parse failed, using fallback", "requestID", opts.CommonOptions.RequestID)
 		return RewriteResult{
-			Text:       strings.TrimSpace(response),
-			Confidence: 0.7,
+			TextResult: TextResult{
+				Text:       strings.TrimSpace(response),
+				Confidence: 0.7,
+				TokensUsed: usage.total(systemPrompt, userPrompt, response),
+			},
 		}, nil
 	}
 
 	result := RewriteResult{
-		Text:         parsed.Text,
+		TextResult: TextResult{
+			Text:       parsed.Text,
+			Confidence: parsed.Confidence,
+			TokensUsed: usage.total(systemPrompt, userPrompt, response),
+			Language:   parsed.Language,
+		},
 		ChangesMade:  parsed.ChangesMade,
 		ToneAchieved: parsed.ToneAchieved,
-		Confidence:   parsed.Confidence,
 	}
 
 	log.Debug("RewriteWithMetadata operation succeeded", "requestID", opts.CommonOptions.RequestID, "outputLength", len(result.Text), "changesMade", len(result.ChangesMade))
@@ -463,6 +768,9 @@ Rules:
 }
 
 // Translate converts text to a target language.
+// Placeholders such as {username}, printf verbs and HTML tags are masked before
+// translation and restored afterwards; a translation that drops or repeats one
+// fails with a TranslateError.
 // For metadata including detected source language and alternatives, use TranslateWithMetadata.
 func Translate(input string, opts TranslateOptions) (string, error) {
 	log := logger.GetLogger()
@@ -474,47 +782,11 @@ func Translate(input string, opts TranslateOptions) (string, error) {
 		return "", fmt.Errorf("invalid options: %w", err)
 	}
 
-	// Build translation instructions
-	var instructions []string
-
-	instructions = append(instructions, fmt.Sprintf("Translate to %s", opts.TargetLanguage))
-
-	if opts.SourceLanguage != "" {
-		instructions = append(instructions, fmt.Sprintf("From %s", opts.SourceLanguage))
-	}
-
-	if opts.Dialect != "" {
-		instructions = append(instructions, fmt.Sprintf("Use %s dialect", opts.Dialect))
-	}
-
-	if opts.Formality != "neutral" {
-		instructions = append(instructions, fmt.Sprintf("Formality: %s", opts.Formality))
-	}
-
-	if opts.CulturalAdaptation != 5 {
-		instructions = append(instructions, fmt.Sprintf("Cultural adaptation level: %d/10", opts.CulturalAdaptation))
-	}
-
-	if opts.PreserveFormatting {
-		instructions = append(instructions, "Preserve formatting")
-	}
-
-	if len(opts.Glossary) > 0 {
-		glossary := "Use glossary: "
-		for term, translation := range opts.Glossary {
-			glossary += fmt.Sprintf("%s=%s, ", term, translation)
-		}
-		instructions = append(instructions, strings.TrimSuffix(glossary, ", "))
-	}
-
 	opt := opts.toOpOptions()
-	steering := strings.Join(instructions, ". ")
-	if opts.OpOptions.Steering != "" {
-		steering = opts.OpOptions.Steering + ". " + steering
-	}
-	opt.Steering = steering
+	opt.Operation = "translate"
+	opt.Steering = translateSteering(opts)
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.GetTimeout())
+	ctx, cancel := context.WithTimeout(opt.Context, config.GetTimeout())
 	defer cancel()
 
 	systemPrompt := `You are a translation expert. Translate text accurately between languages.
@@ -525,7 +797,10 @@ Rules:
 - Handle idioms and cultural references appropriately
 - Keep technical terms accurate`
 
-	userPrompt := fmt.Sprintf("Translate this text:\n%s", input)
+	masked, spans := protectPlaceholders(input)
+	systemPrompt += spans.rule()
+
+	userPrompt := fmt.Sprintf("Translate this text:\n%s", masked)
 
 	response, err := callLLM(ctx, systemPrompt, userPrompt, opt)
 	if err != nil {
@@ -533,10 +808,19 @@ Rules:
 		return "", types.TranslateError{
 			Input:  input,
 			Reason: err.Error(),
+			Cause:  err,
 		}
 	}
 
-	result := strings.TrimSpace(response)
+	result, err := spans.restore(strings.TrimSpace(response))
+	if err != nil {
+		log.Error("Translate operation lost placeholders", "requestID", opts.CommonOptions.RequestID, "error", err)
+		return "", types.TranslateError{
+			Input:  input,
+			Reason: err.Error(),
+			Cause:  err,
+		}
+	}
 	log.Debug("Translate operation succeeded", "requestID", opts.CommonOptions.RequestID, "outputLength", len(result))
 
 	return result, nil
@@ -554,48 +838,13 @@ func TranslateWithMetadata(input string, opts TranslateOptions) (TranslateResult
 		return TranslateResult{}, fmt.Errorf("invalid options: %w", err)
 	}
 
-	// Build translation instructions
-	var instructions []string
-
-	instructions = append(instructions, fmt.Sprintf("Translate to %s", opts.TargetLanguage))
-
-	if opts.SourceLanguage != "" {
-		instructions = append(instructions, fmt.Sprintf("From %s", opts.SourceLanguage))
-	}
-
-	if opts.Dialect != "" {
-		instructions = append(instructions, fmt.Sprintf("Use %s dialect", opts.Dialect))
-	}
-
-	if opts.Formality != "neutral" {
-		instructions = append(instructions, fmt.Sprintf("Formality: %s", opts.Formality))
-	}
-
-	if opts.CulturalAdaptation != 5 {
-		instructions = append(instructions, fmt.Sprintf("Cultural adaptation level: %d/10", opts.CulturalAdaptation))
-	}
-
-	if opts.PreserveFormatting {
-		instructions = append(instructions, "Preserve formatting")
-	}
-
-	if len(opts.Glossary) > 0 {
-		glossary := "Use glossary: "
-		for term, translation := range opts.Glossary {
-			glossary += fmt.Sprintf("%s=%s, ", term, translation)
-		}
-		instructions = append(instructions, strings.TrimSuffix(glossary, ", "))
-	}
-
 	opt := opts.toOpOptions()
-	steering := strings.Join(instructions, ". ")
-	if opts.OpOptions.Steering != "" {
-		steering = opts.OpOptions.Steering + ". " + steering
-	}
-	opt.Steering = steering
+	opt.Operation = "translate"
+	opt.Steering = translateSteering(opts)
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.GetTimeout())
+	ctx, cancel := context.WithTimeout(opt.Context, config.GetTimeout())
 	defer cancel()
+	ctx, usage := withUsageRecorder(ctx)
 
 	systemPrompt := `You are a translation expert. Translate text accurately between languages.
 
@@ -615,7 +864,10 @@ Rules:
 - "confidence": A value from 0.0 to 1.0 indicating translation accuracy
 - "alternatives": Alternate translations for ambiguous phrases (optional, can be empty array)`
 
-	userPrompt := fmt.Sprintf("Translate this text and provide metadata:\n%s", input)
+	masked, spans := protectPlaceholders(input)
+	systemPrompt += spans.rule()
+
+	userPrompt := fmt.Sprintf("Translate this text and provide metadata:\n%s", masked)
 
 	response, err := callLLM(ctx, systemPrompt, userPrompt, opt)
 	if err != nil {
@@ -623,6 +875,7 @@ Rules:
 		return TranslateResult{}, types.TranslateError{
 			Input:  input,
 			Reason: err.Error(),
+			Cause:  err,
 		}
 	}
 
@@ -633,19 +886,46 @@ Rules:
 		Confidence             float64                  `json:"confidence"`
 		Alternatives           []TranslationAlternative `json:"alternatives"`
 	}
-	if err := json.Unmarshal([]byte(response), &parsed); err != nil {
+	fallback := json.Unmarshal([]byte(response), &parsed) != nil
+	if fallback {
 		// Fallback: treat entire response as translation
 		log.Debug("TranslateWithMetadata JSON parse failed, using fallback", "requestID", opts.CommonOptions.RequestID)
+		parsed.Text = strings.TrimSpace(response)
+	}
+
+	text, err := spans.restore(parsed.Text)
+	if err != nil {
+		log.Error("TranslateWithMetadata operation lost placeholders", "requestID", opts.CommonOptions.RequestID, "error", err)
+		return TranslateResult{}, types.TranslateError{
+			Input:  input,
+			Reason: err.Error(),
+			Cause:  err,
+		}
+	}
+	for i, alternative := range parsed.Alternatives {
+		parsed.Alternatives[i].Phrase = spans.restoreLenient(alternative.Phrase)
+		parsed.Alternatives[i].Alternative = spans.restoreLenient(alternative.Alternative)
+	}
+
+	if fallback {
 		return TranslateResult{
-			Text:       strings.TrimSpace(response),
-			Confidence: 0.7,
+			TextResult: TextResult{
+				Text:       text,
+				Confidence: 0.7,
+				TokensUsed: usage.total(systemPrompt, userPrompt, response),
+				Language:   opts.TargetLanguage,
+			},
 		}, nil
 	}
 
 	result := TranslateResult{
-		Text:                   parsed.Text,
+		TextResult: TextResult{
+			Text:       text,
+			Confidence: parsed.Confidence,
+			TokensUsed: usage.total(systemPrompt, userPrompt, response),
+			Language:   opts.TargetLanguage,
+		},
 		SourceLanguageDetected: parsed.SourceLanguageDetected,
-		Confidence:             parsed.Confidence,
 		Alternatives:           parsed.Alternatives,
 	}
 
@@ -654,6 +934,51 @@ Rules:
 	return result, nil
 }
 
+// translateSteering builds the steering instructions shared by Translate and
+// TranslateWithMetadata, after any steering set on the options
+func translateSteering(opts TranslateOptions) string {
+	var instructions []string
+
+	instructions = append(instructions, fmt.Sprintf("Translate to %s", opts.TargetLanguage))
+
+	if opts.SourceLanguage != "" {
+		instructions = append(instructions, fmt.Sprintf("From %s", opts.SourceLanguage))
+	}
+
+	if opts.Dialect != "" {
+		instructions = append(instructions, fmt.Sprintf("Write in the %s regional variant of the target language, using its vocabulary, spelling and grammar", opts.Dialect))
+	}
+
+	switch opts.Formality {
+	case "formal":
+		instructions = append(instructions, `Use the formal register throughout, including formal pronouns and forms of address (e.g. "Sie" in German, "vous" in French, "usted" in Spanish)`)
+	case "informal":
+		instructions = append(instructions, `Use the informal register throughout, including informal pronouns and forms of address (e.g. "du" in German, "tu" in French, "tú" in Spanish)`)
+	}
+
+	if opts.CulturalAdaptation != 5 {
+		instructions = append(instructions, fmt.Sprintf("Cultural adaptation level: %d/10", opts.CulturalAdaptation))
+	}
+
+	if opts.PreserveFormatting {
+		instructions = append(instructions, "Preserve formatting")
+	}
+
+	if len(opts.Glossary) > 0 {
+		glossary := "Use glossary: "
+		for term, translation := range opts.Glossary {
+			glossary += fmt.Sprintf("%s=%s, ", term, translation)
+		}
+		instructions = append(instructions, strings.TrimSuffix(glossary, ", "))
+	}
+
+	steering := strings.Join(instructions, ". ")
+	if opts.OpOptions.Steering != "" {
+		steering = opts.OpOptions.Steering + ". " + steering
+	}
+	return steering
+}
+
 // Expand elaborates on text with additional detail.
 // For metadata including expansion ratio and what was added, use ExpandWithMetadata.
 func Expand(input string, opts ExpandOptions) (string, error) {
@@ -692,6 +1017,7 @@ func Expand(input string, opts ExpandOptions) (string, error) {
 	}
 
 	opt := opts.toOpOptions()
+	opt.Operation = "expand"
 	if len(instructions) > 0 {
 		steering := strings.Join(instructions, ". ")
 		if opts.OpOptions.Steering != "" {
@@ -700,7 +1026,7 @@ func Expand(input string, opts ExpandOptions) (string, error) {
 		opt.Steering = steering
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.GetTimeout())
+	ctx, cancel := context.WithTimeout(opt.Context, config.GetTimeout())
 	defer cancel()
 
 	systemPrompt := `You are a content expansion expert. Elaborate on text with additional detail and context.
@@ -719,6 +1045,7 @@ Rules:
 		return "", types.ExpandError{
 			Input:  input,
 			Reason: err.Error(),
+			Cause:  err,
 		}
 	}
 
@@ -766,6 +1093,7 @@ func ExpandWithMetadata(input string, opts ExpandOptions) (ExpandResult, error)
 	}
 
 	opt := opts.toOpOptions()
+	opt.Operation = "expand"
 	if len(instructions) > 0 {
 		steering := strings.Join(instructions, ". ")
 		if opts.OpOptions.Steering != "" {
@@ -774,8 +1102,9 @@ func ExpandWithMetadata(input string, opts ExpandOptions) (ExpandResult, error)
 		opt.Steering = steering
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.GetTimeout())
+	ctx, cancel := context.WithTimeout(opt.Context, config.GetTimeout())
 	defer cancel()
+	ctx, usage := withUsageRecorder(ctx)
 
 	systemPrompt := `You are a content expansion expert. Elaborate on text with additional detail and context.
 
@@ -783,13 +1112,15 @@ Respond ONLY with valid JSON in this exact format:
 {
   "text": "The expanded text here",
   "added_content": ["Added background context", "Included example of X", "Elaborated on Y"],
-  "confidence": 0.9
+  "confidence": 0.9,
+  "language": "English"
 }
 
 Rules:
 - "text": The complete expanded text
 - "added_content": List of what was added or elaborated upon
-- "confidence": A value from 0.0 to 1.0 indicating expansion quality`
+- "confidence": A value from 0.0 to 1.0 indicating expansion quality
+- "language": The language of the output text`
 
 	userPrompt := fmt.Sprintf("Expand on this text and provide metadata about what you added:\n%s", input)
 
@@ -799,6 +1130,7 @@ Rules:
 		return ExpandResult{}, types.ExpandError{
 			Input:  input,
 			Reason: err.Error(),
+			Cause:  err,
 		}
 	}
 
@@ -807,6 +1139,7 @@ Rules:
 		Text         string   `json:"text"`
 		AddedContent []string `json:"added_content"`
 		Confidence   float64  `json:"confidence"`
+		Language     string   `json:"language"`
 	}
 	if err := json.Unmarshal([]byte(response), &parsed); err != nil {
 		// Fallback: treat entire response as expanded text
@@ -814,19 +1147,26 @@ Rules:
 		expandedText := strings.TrimSpace(response)
 		expansionRatio := float64(len(expandedText)) / float64(len(input))
 		return ExpandResult{
-			Text:           expandedText,
+			TextResult: TextResult{
+				Text:       expandedText,
+				Confidence: 0.7,
+				TokensUsed: usage.total(systemPrompt, userPrompt, response),
+			},
 			ExpansionRatio: expansionRatio,
-			Confidence:     0.7,
 		}, nil
 	}
 
 	expansionRatio := float64(len(parsed.Text)) / float64(len(input))
 
 	result := ExpandResult{
-		Text:           parsed.Text,
+		TextResult: TextResult{
+			Text:       parsed.Text,
+			Confidence: parsed.Confidence,
+			TokensUsed: usage.total(systemPrompt, userPrompt, response),
+			Language:   parsed.Language,
+		},
 		ExpansionRatio: expansionRatio,
 		AddedContent:   parsed.AddedContent,
-		Confidence:     parsed.Confidence,
 	}
 
 	log.Debug("ExpandWithMetadata operation succeeded", "requestID", opts.CommonOptions.RequestID, "outputLength", len(result.Text), "expansionRatio", result.ExpansionRatio)
@@ -0,0 +1,608 @@
+package ops
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/monstercameron/schemaflow/internal/llm"
+	"github.com/monstercameron/schemaflow/internal/logger"
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+func TestSummarizeAllReducesReviews(t *testing.T) {
+	defer setupMockClient()
+
+	type Review struct {
+		Author string `json:"author"`
+		Body   string `json:"body"`
+		Stars  int    `json:"stars"`
+	}
+
+	reviews := make([]Review, 7)
+	for i := range reviews {
+		reviews[i] = Review{Author: "user", Body: "Battery life is great but the strap broke", Stars: 4}
+	}
+
+	var mapCalls, reduceCalls int
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		if strings.Contains(user, "user") {
+			t.Errorf("field selector leaked other fields into prompt: %q", user)
+		}
+		if strings.Contains(opts.Steering, "Target length") {
+			reduceCalls++
+			if !strings.Contains(opts.Steering, "Target length: 8 words") {
+				t.Errorf("final pass missing length target, steering = %q", opts.Steering)
+			}
+			return "Customers praise the battery life but report that straps break too easily under normal use.", nil
+		}
+		mapCalls++
+		if n := strings.Count(user, "\n["); n > 3 {
+			t.Errorf("chunk has %d items, want at most 3", n)
+		}
+		return "Battery praised, strap durability criticized.", nil
+	})
+
+	opts := NewSummarizeOptions().
+		WithField("body").
+		WithChunkSize(3).
+		WithTargetLength(8, "words")
+
+	summary, err := SummarizeAll(reviews, opts)
+	if err != nil {
+		t.Fatalf("SummarizeAll() error = %v", err)
+	}
+	if mapCalls != 3 || reduceCalls != 1 {
+		t.Errorf("calls = %d map / %d reduce, want 3 / 1", mapCalls, reduceCalls)
+	}
+	if words := len(strings.Fields(summary)); words > 8 {
+		t.Errorf("summary has %d words, want at most 8: %q", words, summary)
+	}
+}
+
+func TestSummarizeAllErrors(t *testing.T) {
+	defer setupMockClient()
+
+	if _, err := SummarizeAll([]string{}, NewSummarizeOptions()); err == nil {
+		t.Error("expected error for empty collection")
+	}
+
+	type Review struct{ Body string }
+	if _, err := SummarizeAll([]Review{{Body: "ok"}}, NewSummarizeOptions().WithField("missing")); err == nil {
+		t.Error("expected error for unknown field")
+	}
+
+	// One item per chunk never combines anything, so it is rejected up front
+	calls := 0
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		calls++
+		return "summary", nil
+	})
+	if _, err := SummarizeAll([]string{"first", "second"}, NewSummarizeOptions().WithChunkSize(1)); err == nil || calls != 0 {
+		t.Errorf("SummarizeAll() with chunk size 1 = %v after %d calls, want a validation error and no calls", err, calls)
+	}
+}
+
+func TestTextWithMetadataVariantsReportTextResult(t *testing.T) {
+	provider := &captureProvider{}
+	previous := getDefaultProvider()
+	SetDefaultProvider(provider)
+	setLLMCaller(nil)
+	defer func() {
+		SetDefaultProvider(previous)
+		setupMockClient()
+	}()
+
+	respond := func(content string) {
+		provider.resp = llm.CompletionResponse{
+			Content: content,
+			Usage:   types.TokenUsage{PromptTokens: 90, CompletionTokens: 30, TotalTokens: 120},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		response string
+		run      func() (TextResult, error)
+		language string
+	}{
+		{
+			name:     "translate",
+			response: `{"text":"Bonjour le monde","source_language_detected":"English","confidence":0.95}`,
+			run: func() (TextResult, error) {
+				result, err := TranslateWithMetadata("Hello world", NewTranslateOptions().WithTargetLanguage("French"))
+				return result.TextResult, err
+			},
+			language: "French",
+		},
+		{
+			name:     "rewrite",
+			response: `{"text":"Kindly review the attached report.","changes_made":["more formal"],"tone_achieved":"formal","confidence":0.9,"language":"English"}`,
+			run: func() (TextResult, error) {
+				opts := NewRewriteOptions()
+				opts.TargetTone = "formal"
+				result, err := RewriteWithMetadata("pls look at the report", opts)
+				return result.TextResult, err
+			},
+			language: "English",
+		},
+		{
+			name:     "expand",
+			response: `{"text":"Go is a statically typed, compiled language designed at Google.","added_content":["origin"],"confidence":0.8,"language":"English"}`,
+			run: func() (TextResult, error) {
+				result, err := ExpandWithMetadata("Go is a language.", NewExpandOptions())
+				return result.TextResult, err
+			},
+			language: "English",
+		},
+		{
+			name:     "summarize",
+			response: `{"text":"Go is a compiled language.","key_points":["compiled"],"confidence":0.85,"language":"English"}`,
+			run: func() (TextResult, error) {
+				result, err := SummarizeWithMetadata("Go is a statically typed, compiled language designed at Google.", NewSummarizeOptions())
+				return result.TextResult, err
+			},
+			language: "English",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			respond(tt.response)
+			got, err := tt.run()
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if got.Text == "" || got.Confidence <= 0 {
+				t.Errorf("TextResult = %+v, want text and confidence", got)
+			}
+			if got.TokensUsed != 120 {
+				t.Errorf("TokensUsed = %d, want provider-reported 120", got.TokensUsed)
+			}
+			if got.Language != tt.language {
+				t.Errorf("Language = %q, want %q", got.Language, tt.language)
+			}
+		})
+	}
+}
+
+func TestSummarizePreservesSpanish(t *testing.T) {
+	previousLogger := logger.GetLogger()
+	capture := logger.ConfigureLogger(logger.LoggerConfig{
+		Level:         logger.WarnLevel,
+		Capture:       true,
+		BufferSize:    10,
+		DisableStderr: true,
+	})
+	t.Cleanup(func() { logger.SetLogger(previousLogger) })
+
+	setupMockClient()
+	defer setupMockClient()
+
+	// The mock answers in Spanish only when asked to, otherwise in English
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		if strings.Contains(system, "written in Spanish") {
+			return "El cliente pide un reembolso porque el pedido llegó tarde y la caja estaba dañada.", nil
+		}
+		return "The customer wants a refund because the order arrived late and the box was damaged.", nil
+	})
+
+	input := "Hola, les escribo porque mi pedido llegó con una semana de retraso y la caja estaba dañada. " +
+		"Quiero que me devuelvan el dinero, ya que no es la primera vez que pasa con esta tienda."
+
+	summary, err := Summarize(input, NewSummarizeOptions().WithPreserveLanguage(true))
+	if err != nil {
+		t.Fatalf("Summarize failed: %v", err)
+	}
+	if got := detectLanguage(summary); got != "Spanish" {
+		t.Errorf("expected a Spanish summary, got %s: %q", got, summary)
+	}
+	if len(capture.Entries()) != 0 {
+		t.Errorf("expected no language warning, got %#v", capture.Entries())
+	}
+
+	// A model that ignores the instruction is flagged
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		return "The customer wants a refund because the order arrived late and the box was damaged.", nil
+	})
+	if _, err := Summarize(input, NewSummarizeOptions().WithPreserveLanguage(true)); err != nil {
+		t.Fatalf("Summarize failed: %v", err)
+	}
+	entries := capture.Entries()
+	if len(entries) != 1 || entries[0].Attributes["inputLanguage"] != "Spanish" || entries[0].Attributes["outputLanguage"] != "English" {
+		t.Errorf("expected a Spanish-to-English language warning, got %#v", entries)
+	}
+}
+
+func TestSummarizeSemanticCacheHitsParaphrase(t *testing.T) {
+	defer setupMockClient()
+	ClearSemanticCache()
+	defer ClearSemanticCache()
+
+	calls := 0
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		calls++
+		return "Cloud sales and lower costs lifted Q3 revenue 4%.", nil
+	})
+
+	opts := NewSummarizeOptions().WithSemanticCache(0.8)
+	first, err := Summarize("Revenue grew 4% in the third quarter, driven by strong cloud sales and lower costs.", opts)
+	if err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+	second, err := Summarize("In the third quarter revenue grew 4%, driven by lower costs and strong cloud sales.", opts)
+	if err != nil {
+		t.Fatalf("Summarize() paraphrase error = %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("LLM calls = %d after paraphrase, want 1 (cache hit)", calls)
+	}
+	if second != first {
+		t.Errorf("paraphrase summary = %q, want cached %q", second, first)
+	}
+
+	if _, err := Summarize("The hiking trail closes at dusk and dogs must stay on a leash.", opts); err != nil {
+		t.Fatalf("Summarize() unrelated error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("LLM calls = %d after unrelated input, want 2", calls)
+	}
+
+	rewrite := NewRewriteOptions()
+	rewrite.CommonOptions = rewrite.CommonOptions.WithSemanticCache(0.8)
+	for i := 0; i < 2; i++ {
+		if _, err := Rewrite("Revenue grew 4% in the third quarter.", rewrite); err != nil {
+			t.Fatalf("Rewrite() error = %v", err)
+		}
+	}
+	if calls != 4 {
+		t.Errorf("LLM calls = %d after rewrites, want 4 (rewrite is never cached)", calls)
+	}
+
+	// A summary written under one persona is not served to another
+	auditor := opts
+	auditor.CommonOptions = auditor.CommonOptions.WithPersona("You are a strict financial auditor.")
+	if _, err := Summarize("Revenue grew 4% in the third quarter, driven by strong cloud sales and lower costs.", auditor); err != nil {
+		t.Fatalf("Summarize() with persona error = %v", err)
+	}
+	if calls != 5 {
+		t.Errorf("LLM calls = %d after a persona change, want 5 (personas do not share entries)", calls)
+	}
+}
+
+func TestSummarizeWithQueryFocusesAndFlagsIrrelevance(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	query := "what were the Q4 security incidents?"
+	var systems []string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		systems = append(systems, system)
+		if !strings.Contains(user, "breach") {
+			return `{"text": "The report does not cover Q4 security incidents.", "key_points": [], "confidence": 0.9, "relevant": false}`, nil
+		}
+		return `{"text": "Q4 had two security incidents: a phishing-led credential breach in October and a DDoS on the API in December.", "key_points": ["October credential breach", "December API DDoS"], "confidence": 0.9, "relevant": true}`, nil
+	})
+
+	report := "Revenue grew 12% in Q4 and the new office opened in Austin. Security: in October a phishing email led to a credential breach; in December the public API suffered a DDoS. Headcount rose to 140."
+	result, err := SummarizeWithMetadata(report, NewSummarizeOptions().WithQuery(query))
+	if err != nil {
+		t.Fatalf("SummarizeWithMetadata() error = %v", err)
+	}
+	if !strings.Contains(systems[0], `Focus the summary on answering this query: "what were the Q4 security incidents?"`) ||
+		!strings.Contains(systems[0], `"relevant"`) {
+		t.Errorf("system prompt should focus on the query and ask for relevance, got %q", systems[0])
+	}
+	if !result.Relevant {
+		t.Error("expected the report to be relevant to the query")
+	}
+	if !strings.Contains(result.Text, "security incidents") || strings.Contains(result.Text, "Austin") {
+		t.Errorf("summary should cover the incidents only, got %q", result.Text)
+	}
+
+	offTopic, err := SummarizeWithMetadata("Revenue grew 12% in Q4 and the new office opened in Austin.", NewSummarizeOptions().WithQuery(query))
+	if err != nil {
+		t.Fatalf("SummarizeWithMetadata() error = %v", err)
+	}
+	if offTopic.Relevant {
+		t.Errorf("expected Relevant=false when the text does not address the query, got %+v", offTopic)
+	}
+
+	// Without a query the summary is general and always relevant
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		systems = append(systems, system)
+		return `{"text": "Revenue grew and an office opened.", "key_points": [], "confidence": 0.9}`, nil
+	})
+	general, err := SummarizeWithMetadata("Revenue grew 12% in Q4 and the new office opened in Austin.", NewSummarizeOptions())
+	if err != nil {
+		t.Fatalf("SummarizeWithMetadata() error = %v", err)
+	}
+	if !general.Relevant || strings.Contains(systems[len(systems)-1], "query") {
+		t.Errorf("expected an unfocused, relevant summary, got %+v", general)
+	}
+}
+
+func TestSummarizeStructuredFillsBulletsAndSections(t *testing.T) {
+	defer setupMockClient()
+
+	var systemPrompt string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		systemPrompt = system
+		return `{
+  "headline": "Checkout outage cut Tuesday revenue by 8%",
+  "bullets": ["Payments failed for 42 minutes", "A config rollback restored service"],
+  "sections": [
+    {"heading": "Impact", "summary": "Checkout was down for 42 minutes.", "bullets": ["8% revenue loss"]},
+    {"heading": "Follow-up", "summary": "Config changes now require a canary."}
+  ]
+}`, nil
+	})
+
+	report, err := SummarizeStructured[StructuredSummary]("Incident log: at 14:02 payments began failing...", NewSummarizeOptions())
+	if err != nil {
+		t.Fatalf("SummarizeStructured failed: %v", err)
+	}
+	if report.Headline == "" || len(report.Bullets) != 2 {
+		t.Errorf("expected a headline and bullets, got %+v", report)
+	}
+	if len(report.Sections) == 0 || report.Sections[0].Heading != "Impact" || report.Sections[0].Summary == "" {
+		t.Errorf("expected populated sections, got %+v", report.Sections)
+	}
+	for _, want := range []string{"headline: string", "bullets: []string", "where ops.SummarySection is:", "heading: string"} {
+		if !strings.Contains(systemPrompt, want) {
+			t.Errorf("expected the prompt schema to contain %q, got:\n%s", want, systemPrompt)
+		}
+	}
+
+	if _, err := SummarizeStructured[string]("text", NewSummarizeOptions()); err == nil {
+		t.Error("expected a non-struct target type to fail")
+	}
+}
+
+func TestSummarizeRejectsOversizeInputPreFlight(t *testing.T) {
+	defer setupMockClient()
+
+	calls := 0
+	var inputs []string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		calls++
+		// The guard measures the input before it is fenced
+		fenced := promptInput(user)
+		inputs = append(inputs, fenced[strings.Index(fenced, ">>>\n")+4:strings.LastIndex(fenced, "\n<<<END")])
+		return "short summary", nil
+	})
+
+	input := strings.Repeat("The quarterly report covers revenue, churn and hiring. ", 40)
+	opts := NewSummarizeOptions()
+	opts.CommonOptions = opts.CommonOptions.WithMaxInputBytes(500)
+
+	_, err := Summarize(input, opts)
+	if !errors.Is(err, types.ErrInputTooLarge) {
+		t.Fatalf("expected ErrInputTooLarge, got %v", err)
+	}
+	if calls != 0 {
+		t.Fatalf("expected no provider call for an oversize input, got %d", calls)
+	}
+
+	opts.CommonOptions = opts.CommonOptions.WithAutoChunk(true)
+	summary, err := Summarize(input, opts)
+	if err != nil {
+		t.Fatalf("expected auto-chunked Summarize to succeed, got %v", err)
+	}
+	if summary != "short summary" || calls < 3 {
+		t.Errorf("expected chunk summaries and a final pass, got %q after %d calls", summary, calls)
+	}
+	for i, sent := range inputs {
+		if len(sent) > 500 {
+			t.Errorf("call %d sent %d bytes, over the 500-byte limit", i, len(sent))
+		}
+	}
+}
+
+func TestSummarizeAutoChunkStopsWhenSummariesDoNotShrink(t *testing.T) {
+	defer setupMockClient()
+	ClearChunkCache()
+	defer ClearChunkCache()
+
+	input := strings.Repeat("The quarterly report covers revenue, churn and hiring. ", 40)
+	opts := NewSummarizeOptions()
+	opts.CommonOptions = opts.CommonOptions.WithMaxInputBytes(500).WithAutoChunk(true)
+
+	// Summaries longer than their chunks fail the first round
+	calls := 0
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		calls++
+		return strings.Repeat("An expansive summary. ", 40), nil
+	})
+	if _, err := Summarize(input, opts); !errors.Is(err, types.ErrInputTooLarge) {
+		t.Fatalf("expected ErrInputTooLarge for growing summaries, got %v", err)
+	}
+	if chunks := len(chunkText(input, 500)); calls != chunks {
+		t.Errorf("made %d calls, want one round of %d chunks", calls, chunks)
+	}
+
+	// Summaries that shrink too little run out of rounds
+	calls = 0
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		calls++
+		fenced := promptInput(user)
+		data := fenced[strings.Index(fenced, ">>>\n")+4 : strings.LastIndex(fenced, "\n<<<END")]
+		return strings.Repeat("x", len(data)*9/10), nil
+	})
+	ClearChunkCache()
+	if _, err := Summarize(strings.Repeat("word ", 2000), opts); !errors.Is(err, types.ErrInputTooLarge) || !strings.Contains(err.Error(), "rounds") {
+		t.Fatalf("expected ErrInputTooLarge after the round limit, got %v", err)
+	}
+	if calls > 250 {
+		t.Errorf("made %d calls, want the round limit to stop the operation", calls)
+	}
+}
+
+func TestSummarizeChunkCacheReusesSharedChunk(t *testing.T) {
+	defer setupMockClient()
+	ClearChunkCache()
+	defer ClearChunkCache()
+
+	var inputs []string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		fenced := promptInput(user)
+		input := fenced[strings.Index(fenced, ">>>\n")+4 : strings.LastIndex(fenced, "\n<<<END")]
+		inputs = append(inputs, input)
+		return "summary " + strconv.Itoa(len(inputs)), nil
+	})
+
+	shared := strings.Repeat("The shared introduction describes the product line. ", 3)
+	first := shared + "\n\n" + strings.Repeat("The first appendix lists regional revenue. ", 3)
+	second := shared + "\n\n" + strings.Repeat("The second appendix lists hiring plans. ", 3)
+
+	opts := NewSummarizeOptions()
+	opts.CommonOptions = opts.CommonOptions.WithMaxInputBytes(200).WithAutoChunk(true).WithChunkCache(true)
+	if _, err := Summarize(first, opts); err != nil {
+		t.Fatalf("Summarize failed: %v", err)
+	}
+	if len(inputs) != 3 {
+		t.Fatalf("expected two chunk calls and a final pass, got %d calls", len(inputs))
+	}
+
+	inputs = nil
+	if _, err := Summarize(second, opts); err != nil {
+		t.Fatalf("Summarize failed: %v", err)
+	}
+	if len(inputs) != 2 {
+		t.Fatalf("expected the shared chunk to be served from cache, got %d calls: %q", len(inputs), inputs)
+	}
+	if strings.Contains(inputs[0], "shared introduction") || !strings.Contains(inputs[0], "second appendix") {
+		t.Errorf("expected only the new chunk to be summarized, got %q", inputs[0])
+	}
+	if !strings.Contains(inputs[1], "summary 1") {
+		t.Errorf("expected the final pass to use the cached chunk summary, got %q", inputs[1])
+	}
+
+	// Without the option every chunk is summarized again
+	inputs = nil
+	opts.CommonOptions = opts.CommonOptions.WithChunkCache(false)
+	if _, err := Summarize(second, opts); err != nil || len(inputs) != 3 {
+		t.Fatalf("expected uncached chunks, got err=%v calls=%d", err, len(inputs))
+	}
+}
+
+func TestSummarizeDeadlineBoundsAllChunks(t *testing.T) {
+	defer setupMockClient()
+
+	calls := 0
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		calls++
+		select {
+		case <-time.After(40 * time.Millisecond):
+			return "chunk summary " + strconv.Itoa(calls), nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	})
+
+	input := strings.Repeat("The quarterly report covers revenue, churn and hiring. ", 40)
+	opts := NewSummarizeOptions()
+	opts.CommonOptions = opts.CommonOptions.WithMaxInputBytes(500).WithAutoChunk(true).WithDeadline(100 * time.Millisecond)
+
+	start := time.Now()
+	partial, err := Summarize(input, opts)
+	elapsed := time.Since(start)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline to stop the operation, got %v", err)
+	}
+	if elapsed > 300*time.Millisecond {
+		t.Errorf("expected the whole operation to stop near its 100ms deadline, took %s", elapsed)
+	}
+	if calls >= 5 {
+		t.Errorf("expected the deadline to cut the chunk passes short, got %d calls", calls)
+	}
+	if !strings.Contains(partial, "chunk summary 1") {
+		t.Errorf("expected the finished chunk summaries as a partial result, got %q", partial)
+	}
+
+	opts.CommonOptions = opts.CommonOptions.WithDeadline(-time.Second)
+	if _, err := Summarize(input, opts); err == nil {
+		t.Error("expected a negative deadline to fail validation")
+	}
+}
+
+func TestTranslatePreservesPlaceholdersAndMarkup(t *testing.T) {
+	defer setupMockClient()
+
+	// The mock translates the words around the tokens and moves the markup
+	// tokens together with the words they wrap
+	var sent string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		sent = user
+		return "Hola ⟦1⟧, tienes ⟦2⟧3 mensajes nuevos⟦3⟧ de ⟦4⟧: ⟦5⟧ver⟦6⟧", nil
+	})
+
+	input := `Hello {username}, you have <b>3 new messages</b> from %s: <a href="/inbox">view</a>`
+	translated, err := Translate(input, NewTranslateOptions().WithTargetLanguage("Spanish"))
+	if err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	if want := `Hola {username}, tienes <b>3 mensajes nuevos</b> de %s: <a href="/inbox">ver</a>`; translated != want {
+		t.Errorf("expected %q, got %q", want, translated)
+	}
+	for _, protected := range []string{"{username}", "<b>", "</b>", "%s", `<a href="/inbox">`} {
+		if strings.Contains(sent, protected) {
+			t.Errorf("expected %q to be masked before translation, got %q", protected, sent)
+		}
+	}
+
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		return "Hola, tienes ⟦2⟧3 mensajes nuevos⟦3⟧ de ⟦4⟧: ⟦5⟧ver⟦6⟧", nil
+	})
+	if _, err := Translate(input, NewTranslateOptions().WithTargetLanguage("Spanish")); err == nil || !strings.Contains(err.Error(), `missing "{username}"`) {
+		t.Errorf("expected a dropped placeholder to fail the translation, got %v", err)
+	}
+}
+
+func TestTranslateFormalityAndDialect(t *testing.T) {
+	setupMockClient()
+	defer setupMockClient()
+
+	// The mock uses the formal German register only when asked to
+	var steering string
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		steering = opts.Steering
+		if strings.Contains(opts.Steering, "formal register") && !strings.Contains(opts.Steering, "informal register") {
+			return "Können Sie mir bitte helfen?", nil
+		}
+		return "Kannst du mir bitte helfen?", nil
+	})
+
+	formal, err := Translate("Can you please help me?", NewTranslateOptions().WithTargetLanguage("German").WithFormality("formal"))
+	if err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	if !strings.Contains(steering, `Use the formal register throughout`) || !strings.Contains(steering, `"Sie" in German`) {
+		t.Errorf("expected the formal register instruction, got %q", steering)
+	}
+	if !strings.Contains(formal, "Sie") || strings.Contains(formal, "du") {
+		t.Errorf("expected a formal German translation, got %q", formal)
+	}
+
+	informal, err := Translate("Can you please help me?", NewTranslateOptions().WithTargetLanguage("German").WithFormality("informal"))
+	if err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	if !strings.Contains(informal, "du") {
+		t.Errorf("expected an informal German translation, got %q", informal)
+	}
+
+	if _, err := TranslateWithMetadata("Good morning", NewTranslateOptions().WithTargetLanguage("Portuguese").WithDialect("pt-BR")); err != nil {
+		t.Fatalf("TranslateWithMetadata failed: %v", err)
+	}
+	if !strings.Contains(steering, "pt-BR regional variant") || strings.Contains(steering, "register") {
+		t.Errorf("expected only the dialect instruction, got %q", steering)
+	}
+
+	if _, err := Translate("Hi", NewTranslateOptions().WithTargetLanguage("German").WithFormality("polite")); err == nil {
+		t.Error("expected an unknown formality to be rejected")
+	}
+}
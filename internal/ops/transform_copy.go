@@ -0,0 +1,109 @@
+// package ops - Deterministic copying of shared fields in Transform
+package ops
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// TransformResult is a transformed value with a report of how it was produced
+type TransformResult[U any] struct {
+	// Value is the transformed data
+	Value U `json:"value"`
+
+	// CopiedFields lists the target JSON fields copied verbatim from the input
+	// instead of being produced by the model, sorted
+	CopiedFields []string `json:"copied_fields,omitempty"`
+
+	// PIIFields lists the fields of Value tagged `pii:"true"` that hold a
+	// value (see PIIFields)
+	PIIFields []string `json:"pii_fields,omitempty"`
+}
+
+// TransformWithMetadata transforms input like Transform with field copying
+// enabled, and reports which fields were copied rather than generated.
+//
+// Example:
+//
+//	result, err := TransformWithMetadata[Lead, Customer](lead, NewTransformOptions())
+//	fmt.Println(result.CopiedFields) // e.g. [email id]
+func TransformWithMetadata[T any, U any](input T, opts TransformOptions) (TransformResult[U], error) {
+	opts.CopyMatchingFields = true
+
+	var copied []string
+	value, err := transform[T, U](input, opts, &copied)
+	return TransformResult[U]{Value: value, CopiedFields: copied, PIIFields: PIIFields(value)}, err
+}
+
+// matchingFields returns the sorted JSON names of top-level fields that from
+// and to share with an identical Go type, skipping target fields in exclude
+func matchingFields(from, to reflect.Type, exclude map[string]string) []string {
+	from, to = structType(from), structType(to)
+	if from == nil || to == nil {
+		return nil
+	}
+
+	source := map[string]reflect.Type{}
+	for i := 0; i < from.NumField(); i++ {
+		field := from.Field(i)
+		if field.IsExported() && !field.Anonymous && field.Tag.Get("json") != "-" {
+			source[jsonFieldName(field)] = field.Type
+		}
+	}
+
+	var names []string
+	for i := 0; i < to.NumField(); i++ {
+		field := to.Field(i)
+		if !field.IsExported() || field.Anonymous || field.Tag.Get("json") == "-" {
+			continue
+		}
+		name := jsonFieldName(field)
+		if _, mapped := exclude[name]; mapped {
+			continue
+		}
+		if sourceType, ok := source[name]; ok && sourceType == field.Type {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// structType returns t, or the type it points to, when it is a struct
+func structType(t reflect.Type) reflect.Type {
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
+
+// splitCopiedFields moves the named fields out of a JSON object, returning the
+// object without them and a second object holding only them. Fields absent
+// from the input are dropped from the returned list.
+func splitCopiedFields(inputJSON []byte, fields []string) (remaining, copied []byte, present []string, err error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(inputJSON, &object); err != nil {
+		return nil, nil, nil, err
+	}
+
+	values := make(map[string]json.RawMessage, len(fields))
+	for _, name := range fields {
+		if value, ok := object[name]; ok {
+			values[name] = value
+			present = append(present, name)
+			delete(object, name)
+		}
+	}
+
+	if remaining, err = json.Marshal(object); err != nil {
+		return nil, nil, nil, err
+	}
+	if copied, err = json.Marshal(values); err != nil {
+		return nil, nil, nil, err
+	}
+	return remaining, copied, present, nil
+}
@@ -0,0 +1,170 @@
+// package ops - Deterministic, replayable field transformations
+package ops
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// TransformRule is a single deterministic operation on a top-level string
+// field. Rules are produced by Conform and replayed with ApplyTransformRules.
+type TransformRule struct {
+	// Field is the JSON field name the rule applies to
+	Field string `json:"field"`
+
+	// Op is the operation; see transformOpsDoc for the supported set
+	Op string `json:"op"`
+
+	// From and To parameterize replace, prefix and reformat_date
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+
+	// Mapping parameterizes map (whole value) and abbreviate (per word)
+	Mapping map[string]string `json:"mapping,omitempty"`
+}
+
+// transformOpsDoc documents the supported ops for prompts
+const transformOpsDoc = `- "uppercase", "lowercase", "titlecase", "trim", "collapse_spaces"
+- "digits_only": drop every character except 0-9
+- "prefix": prepend "to" unless the value already starts with it
+- "replace": replace every "from" substring with "to"
+- "map": replace the whole value using "mapping" (case-insensitive keys)
+- "abbreviate": replace whole words using "mapping" (case-insensitive keys)
+- "reformat_date": parse with pattern "from" and format with pattern "to"; patterns use YYYY, YY, MMMM, MMM, MM, M, DD, D, HH, hh, h, mm, ss, A`
+
+var multiSpace = regexp.MustCompile(`\s+`)
+
+// ApplyTransformRules replays rules against input without calling a model.
+// Rules apply in order; a rule naming a missing or non-string field is an error.
+func ApplyTransformRules[T any](input T, rules []TransformRule) (T, error) {
+	var output T
+
+	data, err := json.Marshal(input)
+	if err != nil {
+		return output, fmt.Errorf("failed to marshal input: %w", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return output, fmt.Errorf("transform rules require an object input: %w", err)
+	}
+
+	for i, rule := range rules {
+		value, ok := fields[rule.Field].(string)
+		if !ok {
+			return output, fmt.Errorf("rule %d: field %q is missing or not a string", i, rule.Field)
+		}
+		transformed, err := rule.apply(value)
+		if err != nil {
+			return output, fmt.Errorf("rule %d (%s %s): %w", i, rule.Op, rule.Field, err)
+		}
+		fields[rule.Field] = transformed
+	}
+
+	data, err = json.Marshal(fields)
+	if err != nil {
+		return output, fmt.Errorf("failed to marshal output: %w", err)
+	}
+	if err := json.Unmarshal(data, &output); err != nil {
+		return output, fmt.Errorf("failed to decode output: %w", err)
+	}
+	return output, nil
+}
+
+func (r TransformRule) apply(value string) (string, error) {
+	switch r.Op {
+	case "uppercase":
+		return strings.ToUpper(value), nil
+	case "lowercase":
+		return strings.ToLower(value), nil
+	case "titlecase":
+		words := strings.Fields(strings.ToLower(value))
+		for i, word := range words {
+			runes := []rune(word)
+			runes[0] = unicode.ToUpper(runes[0])
+			words[i] = string(runes)
+		}
+		return strings.Join(words, " "), nil
+	case "trim":
+		return strings.TrimSpace(value), nil
+	case "collapse_spaces":
+		return strings.TrimSpace(multiSpace.ReplaceAllString(value, " ")), nil
+	case "digits_only":
+		return strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, value), nil
+	case "prefix":
+		if strings.HasPrefix(value, r.To) {
+			return value, nil
+		}
+		return r.To + value, nil
+	case "replace":
+		if r.From == "" {
+			return "", fmt.Errorf("replace requires from")
+		}
+		return strings.ReplaceAll(value, r.From, r.To), nil
+	case "map":
+		for from, to := range r.Mapping {
+			if strings.EqualFold(from, value) {
+				return to, nil
+			}
+		}
+		return value, nil
+	case "abbreviate":
+		words := strings.Fields(value)
+		for i, word := range words {
+			for from, to := range r.Mapping {
+				if strings.EqualFold(from, word) {
+					words[i] = to
+					break
+				}
+			}
+		}
+		return strings.Join(words, " "), nil
+	case "reformat_date":
+		parsed, err := time.Parse(datePatternLayout(r.From), value)
+		if err != nil {
+			return "", err
+		}
+		return parsed.Format(datePatternLayout(r.To)), nil
+	}
+	return "", fmt.Errorf("unsupported op %q", r.Op)
+}
+
+// datePatternTokens maps pattern tokens to Go layout fragments, longest first
+var datePatternTokens = []struct{ token, layout string }{
+	{"YYYY", "2006"}, {"MMMM", "January"}, {"MMM", "Jan"}, {"YY", "06"},
+	{"MM", "01"}, {"DD", "02"}, {"HH", "15"}, {"hh", "03"}, {"mm", "04"},
+	{"ss", "05"}, {"M", "1"}, {"D", "2"}, {"h", "3"}, {"A", "PM"},
+}
+
+// datePatternLayout converts a pattern like "MM/DD/YYYY" to a Go time layout.
+// A value that is already a Go layout (contains "2006") is returned unchanged.
+func datePatternLayout(pattern string) string {
+	if strings.Contains(pattern, "2006") {
+		return pattern
+	}
+	var b strings.Builder
+	for i := 0; i < len(pattern); {
+		matched := false
+		for _, t := range datePatternTokens {
+			if strings.HasPrefix(pattern[i:], t.token) {
+				b.WriteString(t.layout)
+				i += len(t.token)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			b.WriteByte(pattern[i])
+			i++
+		}
+	}
+	return b.String()
+}
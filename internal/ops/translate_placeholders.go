@@ -0,0 +1,84 @@
+// package ops - Placeholder and markup protection for translation
+package ops
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// protectedPattern matches the spans Translate must not translate: template
+// placeholders such as {username} or {{count}}, printf verbs such as %s or
+// %1$d, and HTML/XML tags such as <b>, </b> or <a href="...">
+var protectedPattern = regexp.MustCompile(`\{\{[^{}]*\}\}|\{[^{}\s]+\}|%(?:\d+\$)?[sdvfq]|</?[A-Za-z][A-Za-z0-9-]*(?:\s[^<>]*)?/?>`)
+
+// protectedTokenPattern matches the tokens protectPlaceholders substitutes
+var protectedTokenPattern = regexp.MustCompile(`⟦(\d+)⟧`)
+
+// protectedSpans maps the tokens substituted into a translation input back to
+// the placeholders and markup they replaced
+type protectedSpans []string
+
+// protectPlaceholders replaces each placeholder and tag in text with a
+// numbered token (⟦1⟧, ⟦2⟧, ...) that the model copies through untranslated.
+// Text that already contains token brackets is left as is.
+func protectPlaceholders(text string) (string, protectedSpans) {
+	if strings.ContainsAny(text, "⟦⟧") {
+		return text, nil
+	}
+	var spans protectedSpans
+	masked := protectedPattern.ReplaceAllStringFunc(text, func(span string) string {
+		spans = append(spans, span)
+		return fmt.Sprintf("⟦%d⟧", len(spans))
+	})
+	return masked, spans
+}
+
+// rule tells the model how to treat the tokens, or returns "" when nothing
+// was protected
+func (spans protectedSpans) rule() string {
+	if len(spans) == 0 {
+		return ""
+	}
+	return `
+- Tokens such as ⟦1⟧ stand for placeholders and markup: copy every token exactly once and unchanged, placing it where the text it marks belongs in the translation`
+}
+
+// restore puts the original placeholders and markup back in place of their
+// tokens, failing when the translation dropped, repeated or invented a token
+func (spans protectedSpans) restore(text string) (string, error) {
+	if len(spans) == 0 {
+		return text, nil
+	}
+	seen := make([]int, len(spans))
+	var problems []string
+	restored := protectedTokenPattern.ReplaceAllStringFunc(text, func(token string) string {
+		n, _ := strconv.Atoi(protectedTokenPattern.FindStringSubmatch(token)[1])
+		if n < 1 || n > len(spans) {
+			problems = append(problems, "unknown token "+token)
+			return ""
+		}
+		seen[n-1]++
+		return spans[n-1]
+	})
+	for i, count := range seen {
+		switch {
+		case count == 0:
+			problems = append(problems, fmt.Sprintf("missing %q", spans[i]))
+		case count > 1:
+			problems = append(problems, fmt.Sprintf("repeated %q", spans[i]))
+		}
+	}
+	if len(problems) > 0 {
+		return restored, fmt.Errorf("translation did not preserve placeholders and markup: %s", strings.Join(problems, ", "))
+	}
+	return restored, nil
+}
+
+// restoreLenient puts back the tokens the text contains, for secondary text
+// such as alternative phrasings where a partial span is expected
+func (spans protectedSpans) restoreLenient(text string) string {
+	restored, _ := spans.restore(text)
+	return restored
+}
@@ -0,0 +1,273 @@
+// package ops - Unit conversion for numeric fields tagged with a target unit
+package ops
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// unitDefinition places a unit on its dimension's base unit: base = value*scale + offset
+type unitDefinition struct {
+	dimension string
+	scale     float64
+	offset    float64
+}
+
+// units maps unit names and abbreviations to their definitions. Base units
+// are meters, kilograms, liters, Celsius and seconds.
+var units = map[string]unitDefinition{}
+
+func init() {
+	define := func(dimension string, scale, offset float64, names ...string) {
+		for _, name := range names {
+			units[name] = unitDefinition{dimension: dimension, scale: scale, offset: offset}
+		}
+	}
+	define("length", 1, 0, "m", "meter", "meters", "metre", "metres")
+	define("length", 0.01, 0, "cm", "centimeter", "centimeters", "centimetre", "centimetres")
+	define("length", 0.001, 0, "mm", "millimeter", "millimeters", "millimetre", "millimetres")
+	define("length", 1000, 0, "km", "kilometer", "kilometers", "kilometre", "kilometres")
+	define("length", 0.0254, 0, "in", "inch", "inches", "\"")
+	define("length", 0.3048, 0, "ft", "foot", "feet", "'")
+	define("length", 0.9144, 0, "yd", "yard", "yards")
+	define("length", 1609.344, 0, "mi", "mile", "miles")
+
+	define("mass", 1, 0, "kg", "kgs", "kilogram", "kilograms", "kilo", "kilos")
+	define("mass", 0.001, 0, "g", "gram", "grams")
+	define("mass", 1000, 0, "t", "tonne", "tonnes", "metric ton", "metric tons")
+	define("mass", 0.45359237, 0, "lb", "lbs", "pound", "pounds")
+	define("mass", 0.028349523125, 0, "oz", "ounce", "ounces")
+	define("mass", 6.35029318, 0, "st", "stone", "stones")
+
+	define("volume", 1, 0, "l", "liter", "liters", "litre", "litres")
+	define("volume", 0.001, 0, "ml", "milliliter", "milliliters", "millilitre", "millilitres")
+	define("volume", 3.785411784, 0, "gal", "gallon", "gallons")
+	define("volume", 0.946352946, 0, "qt", "quart", "quarts")
+	define("volume", 0.0295735295625, 0, "fl oz", "fluid ounce", "fluid ounces")
+
+	define("temperature", 1, 0, "c", "°c", "celsius", "degrees celsius")
+	define("temperature", 5.0/9, -160.0/9, "f", "°f", "fahrenheit", "degrees fahrenheit")
+	define("temperature", 1, -273.15, "k", "kelvin")
+
+	define("time", 1, 0, "s", "sec", "secs", "second", "seconds")
+	define("time", 60, 0, "min", "mins", "minute", "minutes")
+	define("time", 3600, 0, "h", "hr", "hrs", "hour", "hours")
+	define("time", 86400, 0, "d", "day", "days")
+}
+
+// lookupUnit finds a unit by name, ignoring case, surrounding space and a trailing "."
+func lookupUnit(name string) (unitDefinition, bool) {
+	unit, ok := units[strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")]
+	return unit, ok
+}
+
+// convertUnit converts value from one unit to another of the same dimension
+func convertUnit(value float64, from, to string) (float64, error) {
+	source, ok := lookupUnit(from)
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", from)
+	}
+	target, ok := lookupUnit(to)
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", to)
+	}
+	if source.dimension != target.dimension {
+		return 0, fmt.Errorf("cannot convert %s (%s) to %s (%s)", from, source.dimension, to, target.dimension)
+	}
+	base := value*source.scale + source.offset
+	return (base - target.offset) / target.scale, nil
+}
+
+// unitTag parses a `unit:"meters"` or `unit:"meters,from=feet"` tag into the
+// target unit and the unit assumed for bare numbers
+func unitTag(field reflect.StructField) (target, from string, ok bool) {
+	tag, ok := field.Tag.Lookup("unit")
+	if !ok || tag == "" {
+		return "", "", false
+	}
+	target, options, _ := strings.Cut(tag, ",")
+	for _, option := range strings.Split(options, ",") {
+		if value, found := strings.CutPrefix(strings.TrimSpace(option), "from="); found {
+			from = value
+		}
+	}
+	return strings.TrimSpace(target), from, true
+}
+
+// unitFieldRules describes every unit-tagged field reachable from t as
+// "path (in unit)" for the extraction prompt
+func unitFieldRules(t reflect.Type) []string {
+	var rules []string
+	var walk func(t reflect.Type, path string, seen map[reflect.Type]bool)
+	walk = func(t reflect.Type, path string, seen map[reflect.Type]bool) {
+		for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct || t == timeType || seen[t] {
+			return
+		}
+		seen[t] = true
+		defer delete(seen, t)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() || field.Tag.Get("json") == "-" {
+				continue
+			}
+			name := joinPath(path, jsonFieldName(field))
+			if target, from, ok := unitTag(field); ok {
+				rule := fmt.Sprintf("%s (stored in %s", name, target)
+				if from != "" {
+					rule += fmt.Sprintf(", usually stated in %s", from)
+				}
+				rules = append(rules, rule+")")
+				continue
+			}
+			walk(field.Type, name, seen)
+		}
+	}
+	walk(t, "", map[reflect.Type]bool{})
+	return rules
+}
+
+// normalizeUnitFields converts quantities at unit-tagged positions of a JSON
+// response into the tagged unit. A quantity may be {"value": 5.7, "unit":
+// "feet"}, a string such as "5.7 ft", or a bare number, which is read in the
+// tag's from= unit (or taken as already converted). It returns the rewritten
+// JSON and the paths of quantities that could not be converted; those are
+// cleared so the rest of the response still decodes.
+func normalizeUnitFields(response string, target reflect.Type) (string, []string) {
+	var raw any
+	decoder := json.NewDecoder(strings.NewReader(cleanJSON(response)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return response, nil
+	}
+	var failed []string
+	normalized := normalizeUnitValue(raw, target, "", &failed)
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return response, nil
+	}
+	return string(data), failed
+}
+
+func normalizeUnitValue(value any, t reflect.Type, path string, failed *[]string) any {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := value.(map[string]any)
+		if !ok || t == timeType {
+			return value
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() || field.Tag.Get("json") == "-" {
+				continue
+			}
+			name := jsonFieldName(field)
+			fieldValue, present := obj[name]
+			if !present {
+				continue
+			}
+			if target, from, ok := unitTag(field); ok {
+				converted, err := convertQuantity(fieldValue, target, from)
+				if err != nil {
+					*failed = append(*failed, joinPath(path, name))
+					obj[name] = nil
+					continue
+				}
+				obj[name] = converted
+				continue
+			}
+			obj[name] = normalizeUnitValue(fieldValue, field.Type, joinPath(path, name), failed)
+		}
+		return obj
+	case reflect.Slice, reflect.Array:
+		items, ok := value.([]any)
+		if !ok {
+			return value
+		}
+		for i, item := range items {
+			items[i] = normalizeUnitValue(item, t.Elem(), joinPath(path, strconv.Itoa(i)), failed)
+		}
+		return items
+	case reflect.Map:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return value
+		}
+		for key, item := range obj {
+			obj[key] = normalizeUnitValue(item, t.Elem(), joinPath(path, key), failed)
+		}
+		return obj
+	}
+	return value
+}
+
+// convertQuantity converts one extracted quantity into target
+func convertQuantity(value any, target, from string) (any, error) {
+	var amount float64
+	unit := from
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case json.Number:
+		parsed, err := v.Float64()
+		if err != nil {
+			return nil, err
+		}
+		amount = parsed
+	case string:
+		parsed, stated, err := parseQuantity(v)
+		if err != nil {
+			return nil, err
+		}
+		amount = parsed
+		if stated != "" {
+			unit = stated
+		}
+	case map[string]any:
+		number, ok := v["value"].(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("quantity has no numeric value")
+		}
+		parsed, err := number.Float64()
+		if err != nil {
+			return nil, err
+		}
+		amount = parsed
+		if stated, ok := v["unit"].(string); ok && strings.TrimSpace(stated) != "" {
+			unit = stated
+		}
+	default:
+		return nil, fmt.Errorf("unsupported quantity %v", value)
+	}
+
+	if unit == "" {
+		return amount, nil
+	}
+	return convertUnit(amount, unit, target)
+}
+
+// parseQuantity splits text such as "5.7 feet" or "70kg" into its number and unit
+func parseQuantity(text string) (float64, string, error) {
+	text = strings.TrimSpace(text)
+	end := strings.IndexFunc(text, func(r rune) bool {
+		return !unicode.IsDigit(r) && r != '.' && r != '-' && r != '+' && r != ','
+	})
+	if end < 0 {
+		end = len(text)
+	}
+	amount, err := strconv.ParseFloat(strings.ReplaceAll(text[:end], ",", ""), 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid quantity %q", text)
+	}
+	return amount, strings.TrimSpace(text[end:]), nil
+}
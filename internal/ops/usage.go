@@ -0,0 +1,83 @@
+// package ops - Per-operation token usage accounting
+package ops
+
+import (
+	"context"
+	"sync"
+
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+type usageKey struct{}
+
+// usageRecorder accumulates provider-reported token usage for one operation
+type usageRecorder struct {
+	mu     sync.Mutex
+	tokens int
+}
+
+// withUsageRecorder attaches a recorder that CallLLM reports usage into
+func withUsageRecorder(ctx context.Context) (context.Context, *usageRecorder) {
+	recorder := &usageRecorder{}
+	return context.WithValue(ctx, usageKey{}, recorder), recorder
+}
+
+// recordUsage adds one call's usage to the recorder and the run usage on
+// ctx, if any
+func recordUsage(ctx context.Context, usage types.TokenUsage, cost float64) {
+	if recorder, ok := ctx.Value(usageKey{}).(*usageRecorder); ok {
+		recorder.mu.Lock()
+		recorder.tokens += usage.TotalTokens
+		recorder.mu.Unlock()
+	}
+	if run, ok := ctx.Value(runUsageKey{}).(*RunUsage); ok {
+		run.mu.Lock()
+		run.calls++
+		run.usage.PromptTokens += usage.PromptTokens
+		run.usage.CompletionTokens += usage.CompletionTokens
+		run.usage.TotalTokens += usage.TotalTokens
+		run.cost += cost
+		run.mu.Unlock()
+	}
+}
+
+// total returns the recorded tokens, or an estimate (about 4 characters per
+// token) over texts when the provider reported no usage
+func (r *usageRecorder) total(texts ...string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.tokens > 0 {
+		return r.tokens
+	}
+	chars := 0
+	for _, text := range texts {
+		chars += len(text)
+	}
+	return (chars + 3) / 4
+}
+
+type runUsageKey struct{}
+
+// RunUsage accumulates the token usage and cost of every LLM call made under
+// one context, across all the operations given that context
+type RunUsage struct {
+	mu    sync.Mutex
+	calls int
+	usage types.TokenUsage
+	cost  float64
+}
+
+// WithRunUsage attaches a RunUsage that every LLM call made under the returned
+// context adds its usage to
+func WithRunUsage(ctx context.Context) (context.Context, *RunUsage) {
+	run := &RunUsage{}
+	return context.WithValue(ctx, runUsageKey{}, run), run
+}
+
+// Totals returns the number of LLM calls recorded, their combined token usage
+// and their cost in USD
+func (u *RunUsage) Totals() (int, types.TokenUsage, float64) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.calls, u.usage, u.cost
+}
@@ -1,9 +1,11 @@
 package ops
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/monstercameron/schemaflow/internal/types"
@@ -39,8 +41,7 @@ func GenerateTypeSchema(targetType reflect.Type) string {
 			// Get field type description
 			fieldType := GetTypeDescription(field.Type)
 
-			// Check if field is required (no omitempty tag)
-			required := !strings.Contains(jsonTag, "omitempty")
+			required := isRequiredField(field)
 			requiredStr := ""
 			if required {
 				requiredStr = " (required)"
@@ -65,6 +66,107 @@ func GenerateTypeSchema(targetType reflect.Type) string {
 	}
 }
 
+// isRequiredField reports whether a struct field is required. An explicit
+// `required:"true"` or `required:"false"` tag wins; otherwise fields without
+// omitempty are treated as required.
+func isRequiredField(field reflect.StructField) bool {
+	switch field.Tag.Get("required") {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return !strings.Contains(field.Tag.Get("json"), "omitempty")
+}
+
+// jsonFieldName returns the JSON key used for a struct field
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+// explicitRequiredFields returns the JSON names of top-level fields tagged `required:"true"`
+func explicitRequiredFields(targetType reflect.Type) []string {
+	if targetType.Kind() == reflect.Ptr {
+		targetType = targetType.Elem()
+	}
+	if targetType.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var names []string
+	for i := 0; i < targetType.NumField(); i++ {
+		field := targetType.Field(i)
+		if !field.IsExported() || field.Tag.Get("json") == "-" {
+			continue
+		}
+		if field.Tag.Get("required") == "true" {
+			names = append(names, jsonFieldName(field))
+		}
+	}
+	return names
+}
+
+// triStateBoolFields returns the dotted JSON paths of every *bool field
+// reachable from targetType; nil marks a yes/no value the input leaves unstated
+func triStateBoolFields(targetType reflect.Type) []string {
+	var paths []string
+	var walk func(t reflect.Type, path string, seen map[reflect.Type]bool)
+	walk = func(t reflect.Type, path string, seen map[reflect.Type]bool) {
+		for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct || t == timeType || seen[t] {
+			return
+		}
+		seen[t] = true
+		defer delete(seen, t)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() || field.Tag.Get("json") == "-" {
+				continue
+			}
+			name := joinPath(path, jsonFieldName(field))
+			if field.Type.Kind() == reflect.Ptr && field.Type.Elem().Kind() == reflect.Bool {
+				paths = append(paths, name)
+				continue
+			}
+			walk(field.Type, name, seen)
+		}
+	}
+	walk(targetType, "", map[reflect.Type]bool{})
+	return paths
+}
+
+// missingRequiredFields checks a raw JSON object response for required fields
+// that are absent, null, or empty strings
+func missingRequiredFields(response string, required []string) []string {
+	if len(required) == 0 {
+		return nil
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal([]byte(cleanJSON(response)), &raw); err != nil {
+		return nil
+	}
+
+	var missing []string
+	for _, name := range required {
+		value, ok := raw[name]
+		if !ok || value == nil {
+			missing = append(missing, name)
+			continue
+		}
+		if str, isString := value.(string); isString && strings.TrimSpace(str) == "" {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
 // GetTypeDescription returns a simple description of a type
 func GetTypeDescription(targetType reflect.Type) string {
 	switch targetType.Kind() {
@@ -240,10 +342,29 @@ func contains(slice []string, item string) bool {
 
 // applyDefaults applies default values to OpOptions
 func applyDefaults(opts ...types.OpOptions) types.OpOptions {
+	return applyDefaultsUnder(nil, opts...)
+}
+
+// applyDefaultsUnder is applyDefaults for options run under ctx: ctx picks
+// the scope whose defaults apply and is the context of the result
+func applyDefaultsUnder(ctx context.Context, opts ...types.OpOptions) types.OpOptions {
 	result := types.OpOptions{
 		Mode:         types.TransformMode,
 		Intelligence: types.Smart,
 	}
+	under := ctx
+	for _, opt := range opts {
+		if under == nil && opt.Context != nil {
+			ctx = opt.Context
+		}
+	}
+	if defaults, ok := defaultOptionsFor(ctx); ok {
+		result = defaults
+		result.Context, result.RequestID, result.CorrelationID = nil, "", ""
+	}
+	if result.Persona == "" {
+		result.Persona = defaultPersonaFor(ctx)
+	}
 
 	for _, opt := range opts {
 		// Mode is an int enum, 0 is Strict which is valid
@@ -253,6 +374,9 @@ func applyDefaults(opts ...types.OpOptions) types.OpOptions {
 		if opt.Steering != "" {
 			result.Steering = opt.Steering
 		}
+		if opt.Persona != "" {
+			result.Persona = opt.Persona
+		}
 		if opt.Threshold > 0 {
 			result.Threshold = opt.Threshold
 		}
@@ -262,12 +386,28 @@ func applyDefaults(opts ...types.OpOptions) types.OpOptions {
 		if opt.Context != nil {
 			result.Context = opt.Context
 		}
+		if opt.JSONMode != "" {
+			result.JSONMode = opt.JSONMode
+		}
 		// For enums, we need a different approach - check if explicitly set
 		// Since we can't tell if they're explicitly set, we'll assume any value is intentional
 		// This means callers must always set these explicitly if they differ from defaults
 		result.Mode = opt.Mode
 		result.Intelligence = opt.Intelligence
 	}
+	if under != nil {
+		result.Context = under
+	}
 
 	return result
 }
+
+// sortedKeys returns the keys of m in ascending order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
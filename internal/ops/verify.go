@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/monstercameron/schemaflow/internal/config"
@@ -46,6 +47,11 @@ type VerifyOptions struct {
 
 	// Minimum confidence to mark as verified
 	MinConfidence float64
+
+	// Numeric tolerances: a numeric claim passes when its delta from the
+	// reference value is within max(AbsTolerance, RelTolerance*|reference|)
+	AbsTolerance float64
+	RelTolerance float64
 }
 
 // NewVerifyOptions creates VerifyOptions with defaults
@@ -77,6 +83,9 @@ func (v VerifyOptions) Validate() error {
 	if v.MinConfidence < 0 || v.MinConfidence > 1 {
 		return fmt.Errorf("min confidence must be between 0 and 1, got %f", v.MinConfidence)
 	}
+	if v.AbsTolerance < 0 || v.RelTolerance < 0 {
+		return fmt.Errorf("numeric tolerance cannot be negative, got abs=%f rel=%f", v.AbsTolerance, v.RelTolerance)
+	}
 	return nil
 }
 
@@ -140,6 +149,15 @@ func (v VerifyOptions) WithMinConfidence(confidence float64) VerifyOptions {
 	return v
 }
 
+// WithNumericTolerance sets the absolute and relative tolerance for numeric
+// claims. Claims are checked against the relation they state, so "uptime met
+// 99.9%" passes with an actual 99.99.
+func (v VerifyOptions) WithNumericTolerance(abs, rel float64) VerifyOptions {
+	v.AbsTolerance = abs
+	v.RelTolerance = rel
+	return v
+}
+
 // WithSteering sets the steering prompt
 func (v VerifyOptions) WithSteering(steering string) VerifyOptions {
 	v.CommonOptions = v.CommonOptions.WithSteering(steering)
@@ -171,6 +189,15 @@ type ClaimVerification struct {
 	Reasoning   string   `json:"reasoning,omitempty"`
 	Sources     []int    `json:"sources,omitempty"`
 	Corrections string   `json:"corrections,omitempty"`
+
+	// Numeric claims (when a tolerance is set): the stated value, the
+	// reference value, the relation the claim asserts between them ("=",
+	// ">=", ">", "<=" or "<", read as actual <op> claimed), and the computed
+	// absolute delta between them
+	ClaimedValue *float64 `json:"claimed_value,omitempty"`
+	ActualValue  *float64 `json:"actual_value,omitempty"`
+	Comparator   string   `json:"comparator,omitempty"`
+	Delta        *float64 `json:"delta,omitempty"`
 }
 
 // LogicIssue represents a logical problem found
@@ -234,6 +261,7 @@ func Verify(input any, opts VerifyOptions) (VerifyResult, error) {
 	}
 
 	opt := opts.toOpOptions()
+	opt.Operation = "verify"
 
 	ctx := opt.Context
 	if ctx == nil {
@@ -307,6 +335,12 @@ func Verify(input any, opts VerifyOptions) (VerifyResult, error) {
 		reasoningNote = "\nExplain the reasoning for each verdict."
 	}
 
+	if opts.hasNumericTolerance() {
+		reasoningNote += fmt.Sprintf(`
+For numeric claims, also return "claimed_value" (the number stated) and "actual_value" (the number from the sources or data) as plain numbers, and "comparator": the relation the claim asserts, read as actual <comparator> claimed. Use "=" for claims of an exact value ("revenue was $1.2M"), ">=" or ">" for thresholds met or exceeded ("uptime met 99.9%%"), and "<=" or "<" for limits not exceeded.
+Numeric differences within an absolute tolerance of %g or a relative tolerance of %g are rounding, not errors or inconsistencies.`, opts.AbsTolerance, opts.RelTolerance)
+	}
+
 	systemPrompt := fmt.Sprintf(`You are an expert fact-checker and verification specialist.
 
 Strictness: %s%s%s%s%s%s%s
@@ -369,6 +403,10 @@ Return a JSON object with:
 		return result, fmt.Errorf("failed to parse verification result: %w", err)
 	}
 
+	if opts.hasNumericTolerance() {
+		applyNumericTolerance(&result, opts.AbsTolerance, opts.RelTolerance)
+	}
+
 	log.Debug("Verify operation succeeded",
 		"overallVerdict", result.OverallVerdict,
 		"claimCount", len(result.Claims),
@@ -376,6 +414,75 @@ Return a JSON object with:
 	return result, nil
 }
 
+func (v VerifyOptions) hasNumericTolerance() bool {
+	return v.AbsTolerance > 0 || v.RelTolerance > 0
+}
+
+// applyNumericTolerance re-evaluates numeric claims deterministically: the
+// verdict is "verified" when the actual value satisfies the claim's
+// comparator within tolerance and "false" otherwise. Claims without a
+// recognized comparator only get their delta and keep the model's verdict.
+// The overall verdict is recomputed if any claim changed.
+func applyNumericTolerance(result *VerifyResult, abs, rel float64) {
+	changed := false
+	for i := range result.Claims {
+		claim := &result.Claims[i]
+		if claim.ClaimedValue == nil || claim.ActualValue == nil {
+			continue
+		}
+		claimed, actual := *claim.ClaimedValue, *claim.ActualValue
+		delta := math.Abs(claimed - actual)
+		claim.Delta = &delta
+
+		tolerance := math.Max(abs, rel*math.Abs(actual))
+		var holds bool
+		switch strings.TrimSpace(claim.Comparator) {
+		case "=", "==":
+			holds = delta <= tolerance
+		case ">=":
+			holds = actual+tolerance >= claimed
+		case ">":
+			holds = actual+tolerance > claimed
+		case "<=":
+			holds = actual-tolerance <= claimed
+		case "<":
+			holds = actual-tolerance < claimed
+		default:
+			continue
+		}
+
+		verdict := "false"
+		if holds {
+			verdict = "verified"
+		}
+		if claim.Verdict != verdict {
+			claim.Verdict = verdict
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	verified, falseCount := 0, 0
+	for _, claim := range result.Claims {
+		switch claim.Verdict {
+		case "verified":
+			verified++
+		case "false":
+			falseCount++
+		}
+	}
+	switch {
+	case verified == len(result.Claims):
+		result.OverallVerdict = "verified"
+	case falseCount == len(result.Claims):
+		result.OverallVerdict = "false"
+	default:
+		result.OverallVerdict = "mixed"
+	}
+}
+
 // VerifyClaim verifies a single claim
 func VerifyClaim(claim string, opts VerifyOptions) (ClaimVerification, error) {
 	result, err := Verify(claim, opts)
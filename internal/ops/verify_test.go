@@ -1,7 +1,11 @@
 package ops
 
 import (
+	"context"
+	"strings"
 	"testing"
+
+	"github.com/monstercameron/schemaflow/internal/types"
 )
 
 func TestVerifyOptions(t *testing.T) {
@@ -177,3 +181,74 @@ func TestVerifyClaim(t *testing.T) {
 		}
 	})
 }
+
+func TestVerifyNumericTolerance(t *testing.T) {
+	defer setupMockClient()
+
+	// The model's own verdicts are wrong on purpose; tolerance must decide
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		if !strings.Contains(system, "absolute tolerance of 0.05") {
+			t.Errorf("prompt missing tolerance: %q", system)
+		}
+		return `{
+			"overall_verdict": "false",
+			"claims": [
+				{"claim": "uptime was 99.9%", "verdict": "false", "claimed_value": 99.9, "actual_value": 99.86, "comparator": "="},
+				{"claim": "revenue was $1.20M", "verdict": "verified", "claimed_value": 1.2, "actual_value": 1.14, "comparator": "="}
+			]
+		}`, nil
+	})
+
+	result, err := Verify("Q3 report", NewVerifyOptions().WithNumericTolerance(0.05, 0.01))
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	within, outside := result.Claims[0], result.Claims[1]
+	if within.Verdict != "verified" {
+		t.Errorf("claim within tolerance verdict = %q, want verified", within.Verdict)
+	}
+	if within.Delta == nil || *within.Delta < 0.0399 || *within.Delta > 0.0401 {
+		t.Errorf("within delta = %v, want 0.04", within.Delta)
+	}
+	if outside.Verdict != "false" {
+		t.Errorf("claim outside tolerance verdict = %q, want false", outside.Verdict)
+	}
+	if outside.Delta == nil || *outside.Delta <= 0.05 {
+		t.Errorf("outside delta = %v, want > 0.05", outside.Delta)
+	}
+	if result.OverallVerdict != "mixed" {
+		t.Errorf("OverallVerdict = %q, want mixed", result.OverallVerdict)
+	}
+}
+
+func TestVerifyNumericToleranceHonorsComparator(t *testing.T) {
+	defer setupMockClient()
+
+	setLLMCaller(func(ctx context.Context, system, user string, opts types.OpOptions) (string, error) {
+		return `{
+			"overall_verdict": "mixed",
+			"claims": [
+				{"claim": "uptime met 99.9%", "verdict": "false", "claimed_value": 99.9, "actual_value": 99.99, "comparator": ">="},
+				{"claim": "latency stayed under 200ms", "verdict": "verified", "claimed_value": 200, "actual_value": 240, "comparator": "<"},
+				{"claim": "about 40 incidents", "verdict": "verified", "claimed_value": 40, "actual_value": 43}
+			]
+		}`, nil
+	})
+
+	result, err := Verify("SLA report", NewVerifyOptions().WithNumericTolerance(0.05, 0.01))
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	met, exceeded, unstated := result.Claims[0], result.Claims[1], result.Claims[2]
+	if met.Verdict != "verified" {
+		t.Errorf("threshold met verdict = %q, want verified", met.Verdict)
+	}
+	if exceeded.Verdict != "false" {
+		t.Errorf("limit exceeded verdict = %q, want false", exceeded.Verdict)
+	}
+	if unstated.Verdict != "verified" || unstated.Delta == nil || *unstated.Delta != 3 {
+		t.Errorf("claim without comparator = %q, delta %v; want the model's verdict kept and delta 3", unstated.Verdict, unstated.Delta)
+	}
+}
@@ -0,0 +1,94 @@
+// package ops - Estimating a workflow's token use and cost with a dry run
+package ops
+
+import (
+	"context"
+	"errors"
+	"maps"
+
+	"github.com/monstercameron/schemaflow/internal/types"
+)
+
+// WorkflowEstimate is the estimated token use and cost of a workflow run
+type WorkflowEstimate struct {
+	// Steps holds the estimate of each step, in execution order
+	Steps []StepEstimate `json:"steps"`
+
+	// Calls is the number of LLM requests the steps rendered
+	Calls int `json:"calls"`
+
+	// EstimatedTokens is the total prompt token estimate of the requests
+	EstimatedTokens int `json:"estimated_tokens"`
+
+	// EstimatedCost is the total provider cost estimate in USD
+	EstimatedCost float64 `json:"estimated_cost"`
+}
+
+// StepEstimate is the estimated token use and cost of one workflow step
+type StepEstimate struct {
+	Step            string  `json:"step"`
+	Calls           int     `json:"calls"`
+	EstimatedTokens int     `json:"estimated_tokens"`
+	EstimatedCost   float64 `json:"estimated_cost"`
+
+	// Error is set when the step stopped with an error other than a dry-run
+	// result; requests it would have made afterwards are not counted
+	Error string `json:"error,omitempty"`
+}
+
+// EstimateCost dry-runs the workflow to estimate its token use and cost
+// without calling the provider. Each step runs once, in dependency order,
+// on a copy of the workflow state with input applied over it; the requests
+// its operations render are summed (see WithDryRun). Steps must use the
+// context they are given for their operations to be dry-run.
+//
+// Because dry-run operations return no data, a step that stops at its first
+// dry-run result is counted up to that request, and later steps see only the
+// state the earlier steps wrote before stopping. Compensation and retries
+// are not run, and the workflow state is left unchanged.
+//
+// Example:
+//
+//	estimate, err := wf.EstimateCost(ctx, map[string]any{"ticket": ticket})
+//	fmt.Printf("%d calls, ~%d tokens, ~$%.4f\n", estimate.Calls, estimate.EstimatedTokens, estimate.EstimatedCost)
+func (w *Workflow) EstimateCost(ctx context.Context, input map[string]any) (WorkflowEstimate, error) {
+	var estimate WorkflowEstimate
+	order, err := w.plan()
+	if err != nil {
+		return estimate, err
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, recorder := withDryRunRecorder(ContextWithDryRun(ctx))
+
+	w.mu.RLock()
+	state := maps.Clone(w.State)
+	w.mu.RUnlock()
+	if state == nil {
+		state = make(map[string]any)
+	}
+	maps.Copy(state, input)
+
+	for _, i := range order {
+		step := w.Steps[i]
+		stepEstimate := StepEstimate{Step: step.Name}
+		stepState := maps.Clone(state)
+		if err := step.Execute(ctx, stepState); err != nil && !errors.Is(err, types.ErrDryRun) {
+			stepEstimate.Error = err.Error()
+		} else {
+			state = stepState
+		}
+
+		for _, result := range recorder.take() {
+			stepEstimate.Calls++
+			stepEstimate.EstimatedTokens += result.EstimatedTokens
+			stepEstimate.EstimatedCost += result.EstimatedCost
+		}
+		estimate.Steps = append(estimate.Steps, stepEstimate)
+		estimate.Calls += stepEstimate.Calls
+		estimate.EstimatedTokens += stepEstimate.EstimatedTokens
+		estimate.EstimatedCost += stepEstimate.EstimatedCost
+	}
+	return estimate, nil
+}
@@ -279,6 +279,11 @@ func generateCorrelationID(strategy CorrelationStrategy, requestID string, ctx c
 	}
 }
 
+// NewID returns a random identifier of the form prefix_<hex>
+func NewID(prefix string) string {
+	return generateIdentifier(prefix)
+}
+
 func generateIdentifier(prefix string) string {
 	var bytes [8]byte
 	if _, err := crand.Read(bytes[:]); err == nil {
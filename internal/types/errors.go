@@ -1,6 +1,10 @@
 package types
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 // ClassifyError represents an error during classification
 type ClassifyError struct {
@@ -8,63 +12,111 @@ type ClassifyError struct {
 	Categories []string
 	Reason     string
 	Confidence float64
+
+	// Cause is the underlying error, if any
+	Cause error
 }
 
 func (e ClassifyError) Error() string {
 	return fmt.Sprintf("classification failed: %s (input: %q)", e.Reason, e.Input)
 }
 
+// Unwrap returns the underlying cause
+func (e ClassifyError) Unwrap() error {
+	return e.Cause
+}
+
 // ScoreError represents an error during scoring
 type ScoreError struct {
 	Input  any
 	Reason string
+
+	// Cause is the underlying error, if any
+	Cause error
 }
 
 func (e ScoreError) Error() string {
 	return fmt.Sprintf("scoring failed: %s", e.Reason)
 }
 
+// Unwrap returns the underlying cause
+func (e ScoreError) Unwrap() error {
+	return e.Cause
+}
+
 // CompareError represents an error during comparison
 type CompareError struct {
 	A      any
 	B      any
 	Reason string
+
+	// Cause is the underlying error, if any
+	Cause error
 }
 
 func (e CompareError) Error() string {
 	return fmt.Sprintf("comparison failed: %s", e.Reason)
 }
 
+// Unwrap returns the underlying cause
+func (e CompareError) Unwrap() error {
+	return e.Cause
+}
+
 // ChooseError represents an error during selection
 type ChooseError struct {
 	Options []any
 	Reason  string
+
+	// Cause is the underlying error, if any
+	Cause error
 }
 
 func (e ChooseError) Error() string {
 	return fmt.Sprintf("selection failed: %s", e.Reason)
 }
 
+// Unwrap returns the underlying cause
+func (e ChooseError) Unwrap() error {
+	return e.Cause
+}
+
 // FilterError represents an error during filtering
 type FilterError struct {
 	Items  []any
 	Reason string
+
+	// Cause is the underlying error, if any
+	Cause error
 }
 
 func (e FilterError) Error() string {
 	return fmt.Sprintf("filtering failed: %s", e.Reason)
 }
 
+// Unwrap returns the underlying cause
+func (e FilterError) Unwrap() error {
+	return e.Cause
+}
+
 // SortError represents an error during sorting
 type SortError struct {
 	Items  []any
 	Reason string
+
+	// Cause is the underlying error, if any
+	Cause error
 }
 
 func (e SortError) Error() string {
 	return fmt.Sprintf("sorting failed: %s", e.Reason)
 }
 
+// Unwrap returns the underlying cause
+func (e SortError) Unwrap() error {
+	return e.Cause
+}
+
 // ExtractError represents an error during extraction
 type ExtractError struct {
 	Input      any
@@ -73,12 +125,28 @@ type ExtractError struct {
 	Confidence float64
 	RequestID  string
 	Timestamp  any // Using any to avoid time import if not needed, or add time import
+
+	// MissingRequired lists required fields (tagged `required:"true"`) that
+	// could not be found in the input
+	MissingRequired []string
+
+	// Truncated reports that the model's output kept hitting the max-token
+	// limit, so no complete response could be parsed
+	Truncated bool
+
+	// Cause is the underlying error, if any
+	Cause error
 }
 
 func (e ExtractError) Error() string {
 	return fmt.Sprintf("extraction failed: %s", e.Reason)
 }
 
+// Unwrap returns the underlying cause
+func (e ExtractError) Unwrap() error {
+	return e.Cause
+}
+
 // TransformError represents an error during transformation
 type TransformError struct {
 	Input      any
@@ -88,12 +156,20 @@ type TransformError struct {
 	Confidence float64
 	RequestID  string
 	Timestamp  any
+
+	// Cause is the underlying error, if any
+	Cause error
 }
 
 func (e TransformError) Error() string {
 	return fmt.Sprintf("transformation failed: %s", e.Reason)
 }
 
+// Unwrap returns the underlying cause
+func (e TransformError) Unwrap() error {
+	return e.Cause
+}
+
 // GenerateError represents an error during generation
 type GenerateError struct {
 	Prompt     string
@@ -101,49 +177,177 @@ type GenerateError struct {
 	Reason     string
 	RequestID  string
 	Timestamp  any
+
+	// Cause is the underlying error, if any
+	Cause error
 }
 
 func (e GenerateError) Error() string {
 	return fmt.Sprintf("generation failed: %s", e.Reason)
 }
 
+// Unwrap returns the underlying cause
+func (e GenerateError) Unwrap() error {
+	return e.Cause
+}
+
 // SummarizeError represents an error during summarization
 type SummarizeError struct {
 	Input  string
 	Length int
 	Reason string
+
+	// Cause is the underlying error, if any
+	Cause error
 }
 
 func (e SummarizeError) Error() string {
 	return fmt.Sprintf("summarization failed: %s", e.Reason)
 }
 
+// Unwrap returns the underlying cause
+func (e SummarizeError) Unwrap() error {
+	return e.Cause
+}
+
 // RewriteError represents an error during rewriting
 type RewriteError struct {
 	Input  string
 	Reason string
+
+	// Cause is the underlying error, if any
+	Cause error
 }
 
 func (e RewriteError) Error() string {
 	return fmt.Sprintf("rewrite failed: %s", e.Reason)
 }
 
+// Unwrap returns the underlying cause
+func (e RewriteError) Unwrap() error {
+	return e.Cause
+}
+
 // TranslateError represents an error during translation
 type TranslateError struct {
 	Input  string
 	Reason string
+
+	// Cause is the underlying error, if any
+	Cause error
 }
 
 func (e TranslateError) Error() string {
 	return fmt.Sprintf("translation failed: %s", e.Reason)
 }
 
+// Unwrap returns the underlying cause
+func (e TranslateError) Unwrap() error {
+	return e.Cause
+}
+
 // ExpandError represents an error during expansion
 type ExpandError struct {
 	Input  string
 	Reason string
+
+	// Cause is the underlying error, if any
+	Cause error
 }
 
 func (e ExpandError) Error() string {
 	return fmt.Sprintf("expansion failed: %s", e.Reason)
 }
+
+// Unwrap returns the underlying cause
+func (e ExpandError) Unwrap() error {
+	return e.Cause
+}
+
+// ErrBudgetExhausted is recorded for batch items that were not started
+// because the batch's cost budget would have been exceeded
+var ErrBudgetExhausted = errors.New("batch budget exhausted: item not processed")
+
+// ErrDryRun is returned (wrapped in a DryRunError) when an operation runs in
+// dry-run mode instead of calling the provider
+var ErrDryRun = errors.New("dry run: provider not called")
+
+// ErrTruncated is returned when a JSON response is still cut off by the
+// max-token limit after re-running with the largest allowed limit
+var ErrTruncated = errors.New("response truncated by the max-token limit")
+
+// ErrCapabilityUnsupported is returned when an option requires a provider
+// feature, such as native JSON mode, that the configured provider lacks
+var ErrCapabilityUnsupported = errors.New("provider does not support the requested capability")
+
+// ErrCircuitOpen is returned without calling the provider while a circuit
+// breaker is open after repeated provider failures
+var ErrCircuitOpen = errors.New("circuit breaker open: provider not called")
+
+// ErrInputTooLarge is returned, before any provider call, when an input
+// exceeds the MaxInputBytes limit
+var ErrInputTooLarge = errors.New("input exceeds the maximum size")
+
+// ErrInjectionDetected is returned (wrapped in an InjectionError) when the
+// injection guard finds instructions aimed at the model in user input
+var ErrInjectionDetected = errors.New("prompt injection detected in input")
+
+// InjectionError reports the injection signals found in user input;
+// errors.Is(err, ErrInjectionDetected) reports true
+type InjectionError struct {
+	Signals []string
+}
+
+func (e *InjectionError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrInjectionDetected, strings.Join(e.Signals, ", "))
+}
+
+// Is reports whether target is ErrInjectionDetected
+func (e *InjectionError) Is(target error) bool {
+	return target == ErrInjectionDetected
+}
+
+// TieError is returned by Decide under the error tie policy when several
+// decisions score within the tie epsilon of the best one
+type TieError struct {
+	// Tied lists the indices of the tied decisions
+	Tied []int
+
+	// Scores holds the model's score for every decision
+	Scores []float64
+}
+
+func (e TieError) Error() string {
+	return fmt.Sprintf("decision tied between options %v", e.Tied)
+}
+
+// DryRunResult describes the request an operation would have sent
+type DryRunResult struct {
+	// RenderedPrompt is the final system and user prompt as they would be sent
+	RenderedPrompt string `json:"rendered_prompt"`
+
+	// EstimatedTokens is a rough prompt token estimate (about 4 characters per token)
+	EstimatedTokens int `json:"estimated_tokens"`
+
+	// EstimatedCost is the provider's cost estimate for the request in USD,
+	// including the expected completion; 0 when no provider is configured
+	EstimatedCost float64 `json:"estimated_cost"`
+
+	// Provider and Model that would have served the request
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+}
+
+// DryRunError carries the DryRunResult; errors.Is(err, ErrDryRun) reports true
+type DryRunError struct {
+	Result DryRunResult
+}
+
+func (e *DryRunError) Error() string {
+	return ErrDryRun.Error()
+}
+
+// Is reports whether target is ErrDryRun
+func (e *DryRunError) Is(target error) bool {
+	return target == ErrDryRun
+}
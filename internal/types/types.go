@@ -75,6 +75,10 @@ type OpOptions struct {
 	// Steering provides natural language guidance for the operation.
 	Steering string
 
+	// Persona is system text placed before the operation's own system
+	// prompt, such as "You are a strict financial auditor."
+	Persona string
+
 	// Threshold sets the minimum confidence level (0.0-1.0).
 	Threshold float64
 
@@ -92,8 +96,95 @@ type OpOptions struct {
 
 	// CorrelationID groups related requests across call chains.
 	CorrelationID string
+
+	// Temperature overrides the mode-derived sampling temperature. It is on a
+	// provider-agnostic 0-1 scale unless RawTemperature is set. Nil uses the mode default.
+	Temperature *float64
+
+	// RawTemperature sends Temperature in the provider's native range, bypassing normalization.
+	RawTemperature bool
+
+	// DryRun renders the request without calling the provider; the call
+	// fails with a *DryRunError describing what would have been sent.
+	DryRun bool
+
+	// RequestMetadata is attached to the outgoing provider request.
+	RequestMetadata map[string]string
+
+	// PreserveLanguage keeps the output in the detected language of the input.
+	PreserveLanguage bool
+
+	// InjectionGuard fails the operation with an *InjectionError when user
+	// input appears to carry instructions aimed at the model.
+	InjectionGuard bool
+
+	// MaxInputBytes, when positive, fails requests whose user input is larger
+	// with ErrInputTooLarge before the provider is called.
+	MaxInputBytes int
+
+	// SemanticCacheThreshold, when positive, reuses the response of an
+	// earlier read-style request whose input has at least this cosine
+	// similarity to the current one.
+	SemanticCacheThreshold float64
+
+	// ReasoningEffort ("low", "medium" or "high") is forwarded to providers
+	// with reasoning controls; empty leaves the provider default.
+	ReasoningEffort string
+
+	// JSONMode controls provider-native JSON output; empty means JSONModeAuto.
+	JSONMode JSONMode
+
+	// MaxPromptTokens, when positive, bounds the estimated tokens of the
+	// rendered prompt; TruncationPolicy decides what happens above it.
+	MaxPromptTokens int
+
+	// TruncationPolicy applies when a prompt exceeds MaxPromptTokens; empty
+	// means TruncationError.
+	TruncationPolicy TruncationPolicy
+
+	// Deadline, when set, bounds every provider call the operation makes;
+	// it is fixed when the operation starts from its WithDeadline duration.
+	Deadline time.Time
+
+	// Operation names the operation issuing the request (e.g., "classify").
+	// Operations set it themselves; it is sent as request metadata and
+	// drives local routing, semantic-cache eligibility and logging.
+	Operation string
 }
 
+// JSONMode controls whether operations request provider-native JSON output
+// (response_format) in addition to the JSON instructions in their prompts.
+type JSONMode string
+
+const (
+	// JSONModeAuto requests native JSON for structured operations when the
+	// provider supports it, and relies on the prompt otherwise.
+	JSONModeAuto JSONMode = "auto"
+
+	// JSONModeOn requests native JSON for every call and fails with
+	// ErrCapabilityUnsupported when the provider cannot enforce it.
+	JSONModeOn JSONMode = "on"
+
+	// JSONModeOff never requests native JSON; output format is coaxed
+	// through the prompt alone.
+	JSONModeOff JSONMode = "off"
+)
+
+// TruncationPolicy decides how a prompt larger than MaxPromptTokens is handled
+type TruncationPolicy string
+
+const (
+	// TruncationError fails the request with ErrInputTooLarge.
+	TruncationError TruncationPolicy = "error"
+
+	// TruncateInput cuts the end of the user input until the prompt fits.
+	TruncateInput TruncationPolicy = "truncate-input"
+
+	// SummarizeInput replaces the user input with a summary that fits,
+	// at the cost of extra summarization calls.
+	SummarizeInput TruncationPolicy = "summarize-input"
+)
+
 // Case represents a pattern matching case for the Match function.
 // Used for conditional execution based on fuzzy matching.
 type Case struct {
@@ -16,6 +16,7 @@ package schemaflow
 
 import (
 	"context"
+	"io"
 
 	"github.com/monstercameron/schemaflow/internal/llm"
 	"github.com/monstercameron/schemaflow/internal/ops"
@@ -35,6 +36,12 @@ type (
 	// Speed defines the quality vs latency tradeoff for operations.
 	Speed = types.Speed
 
+	// JSONMode controls whether operations request provider-native JSON output.
+	JSONMode = types.JSONMode
+
+	// TruncationPolicy decides how a prompt above WithMaxPromptTokens is handled.
+	TruncationPolicy = types.TruncationPolicy
+
 	// LoggerConfig configures the global structured logger.
 	LoggerConfig = telemetry.LoggerConfig
 
@@ -53,12 +60,56 @@ type (
 	// ProviderFactory creates a provider from configuration.
 	ProviderFactory = llm.ProviderFactory
 
+	// LocalHandler produces completion content for the local provider.
+	LocalHandler = llm.LocalHandler
+
+	// StreamingProvider is a provider that can deliver content incrementally.
+	StreamingProvider = llm.StreamingProvider
+
+	// ProviderCapabilities describes optional provider features.
+	ProviderCapabilities = llm.ProviderCapabilities
+
 	// CompletionRequest is the low-level provider request shape.
 	CompletionRequest = llm.CompletionRequest
 
 	// CompletionResponse is the low-level provider response shape.
 	CompletionResponse = llm.CompletionResponse
 
+	// TokenUsage counts the prompt and completion tokens of provider calls.
+	TokenUsage = types.TokenUsage
+
+	// ResultMeta is the provider metadata (finish reason, model, system
+	// fingerprint and safety flags) of one LLM call.
+	ResultMeta = ops.ResultMeta
+
+	// ResultMetaRecorder collects the ResultMeta of the LLM calls made under a
+	// context returned by WithResultMeta.
+	ResultMetaRecorder = ops.ResultMetaRecorder
+
+	// CompletionFunc sends one completion request to a provider.
+	CompletionFunc = llm.CompletionFunc
+
+	// Interceptor wraps provider completions with custom logic.
+	Interceptor = llm.Interceptor
+
+	// Cache stores provider responses for WithResponseCache.
+	Cache = llm.Cache
+
+	// MemoryCache is an in-process response cache.
+	MemoryCache = llm.MemoryCache
+
+	// DiskCache is a response cache persisted to a directory.
+	DiskCache = llm.DiskCache
+
+	// OutboundScrubber rewrites provider requests before they are sent, e.g. to mask PII.
+	OutboundScrubber = ops.OutboundScrubber
+
+	// InboundRestorer rewrites provider responses as they arrive, e.g. to restore masked PII.
+	InboundRestorer = ops.InboundRestorer
+
+	// CBConfig configures the circuit breaker added by WithCircuitBreaker.
+	CBConfig = ops.CBConfig
+
 	// RequestTrackingConfig configures request/correlation tracking.
 	RequestTrackingConfig = requesttracking.Config
 
@@ -70,8 +121,131 @@ type (
 
 	// CorrelationStrategy controls how correlation IDs are resolved.
 	CorrelationStrategy = requesttracking.CorrelationStrategy
+
+	// ExtractError describes an extraction failure, including any missing required fields.
+	ExtractError = types.ExtractError
+
+	// DryRunResult describes the request an operation would have sent in dry-run mode.
+	DryRunResult = types.DryRunResult
+
+	// DryRunError is returned by operations in dry-run mode and carries the DryRunResult.
+	DryRunError = types.DryRunError
+
+	// InjectionError is returned when the injection guard finds instructions aimed at the model in user input.
+	InjectionError = types.InjectionError
+
+	// OperationLog is the structured record passed to the Client.WithOperationLog sink.
+	OperationLog = ops.OperationLog
+
+	// Embedder is implemented by providers that can embed text; WithSemanticCache uses it when available.
+	Embedder = llm.Embedder
 )
 
+// ErrDryRun matches (via errors.Is) the error returned by any operation run in dry-run mode.
+var ErrDryRun = types.ErrDryRun
+
+// ErrInputTooLarge matches (via errors.Is) the error returned, before any
+// provider call, when an input exceeds the WithMaxInputBytes limit.
+var ErrInputTooLarge = types.ErrInputTooLarge
+
+// ErrCircuitOpen matches (via errors.Is) the error returned while the
+// circuit breaker set by WithCircuitBreaker is open.
+var ErrCircuitOpen = types.ErrCircuitOpen
+
+// ErrCapabilityUnsupported matches (via errors.Is) the error returned when an
+// option, such as JSONModeOn, needs a feature the provider lacks.
+var ErrCapabilityUnsupported = types.ErrCapabilityUnsupported
+
+// ErrInjectionDetected matches (via errors.Is) the error returned when the injection guard rejects input.
+var ErrInjectionDetected = types.ErrInjectionDetected
+
+// ContextWithDryRun marks ctx so every operation using it renders its request
+// instead of calling the provider.
+//
+// Example:
+//
+//	ctx := schemaflow.ContextWithDryRun(context.Background())
+//	opts := schemaflow.NewExtractOptions()
+//	opts.CommonOptions.Context = ctx
+//	_, err := schemaflow.Extract[Person](text, opts)
+//	plan, _ := schemaflow.AsDryRun(err)
+func ContextWithDryRun(ctx context.Context) context.Context {
+	return ops.ContextWithDryRun(ctx)
+}
+
+// AsDryRun extracts the DryRunResult from an error returned in dry-run mode.
+//
+// Example:
+//
+//	_, err := schemaflow.Extract[Person](text, schemaflow.NewExtractOptions().WithDryRun(true))
+//	if plan, ok := schemaflow.AsDryRun(err); ok {
+//	    fmt.Println(plan.EstimatedTokens, plan.Model)
+//	}
+func AsDryRun(err error) (DryRunResult, bool) {
+	return ops.AsDryRun(err)
+}
+
+// ClearSemanticCache drops every response stored by WithSemanticCache.
+//
+// Example:
+//
+//	opts := schemaflow.NewSummarizeOptions().WithSemanticCache(0.92)
+//	summary, _ := schemaflow.Summarize(ticket, opts)
+//	schemaflow.ClearSemanticCache() // e.g. after changing prompts or models
+func ClearSemanticCache() {
+	ops.ClearSemanticCache()
+}
+
+// ClearChunkCache drops every chunk result stored by WithChunkCache.
+//
+// Example:
+//
+//	opts := schemaflow.NewSummarizeOptions()
+//	opts.CommonOptions = opts.CommonOptions.WithMaxInputBytes(8000).WithAutoChunk(true).WithChunkCache(true)
+//	summary, _ := schemaflow.Summarize(report, opts)
+//	schemaflow.ClearChunkCache() // e.g. after changing prompts or models
+func ClearChunkCache() {
+	ops.ClearChunkCache()
+}
+
+// ClearRuleCache drops every compiled Validate and Conform ruleset, so the
+// next call over each type recompiles its prompt.
+//
+// Example:
+//
+//	result, _ := schemaflow.Validate(order, schemaflow.NewValidateOptions().WithRules("total must be positive"))
+//	schemaflow.ClearRuleCache() // e.g. between tests that count prompt compilations
+func ClearRuleCache() {
+	ops.ClearRuleCache()
+}
+
+// OperationLogWriter returns an operation log sink that writes each record
+// to w as one line of JSON.
+//
+// Example:
+//
+//	client.WithOperationLog(schemaflow.OperationLogWriter(os.Stdout))
+func OperationLogWriter(w io.Writer) func(OperationLog) {
+	return ops.OperationLogWriter(w)
+}
+
+// ContextWithRequestMetadata returns ctx carrying metadata that every
+// operation using it attaches to its provider requests (OpenAI metadata,
+// Anthropic metadata.user_id, the user field of OpenAI-compatible APIs).
+//
+// Example:
+//
+//	ctx := schemaflow.ContextWithRequestMetadata(ctx, map[string]string{"run_id": jobID})
+//	result := pipeline.Execute(ctx, input) // every step's requests carry run_id
+func ContextWithRequestMetadata(ctx context.Context, metadata map[string]string) context.Context {
+	return ops.ContextWithRequestMetadata(ctx, metadata)
+}
+
+// RequestMetadataFromContext returns a copy of the request metadata carried by ctx.
+func RequestMetadataFromContext(ctx context.Context) map[string]string {
+	return ops.RequestMetadataFromContext(ctx)
+}
+
 // Result wraps an operation result with metadata.
 type Result[T any] struct {
 	Value      T              // The actual result value
@@ -85,9 +259,13 @@ type (
 	ExtractOptions     = ops.ExtractOptions
 	TransformOptions   = ops.TransformOptions
 	GenerateOptions    = ops.GenerateOptions
+	JSONSchema         = ops.JSONSchema
+	RelatedEntity      = ops.RelatedEntity
+	Reference          = ops.Reference
 	ChooseOptions      = ops.ChooseOptions
 	FilterOptions      = ops.FilterOptions
 	SortOptions        = ops.SortOptions
+	SortKey            = ops.SortKey
 	ClassifyOptions    = ops.ClassifyOptions
 	ScoreOptions       = ops.ScoreOptions
 	CompareOptions     = ops.CompareOptions
@@ -95,6 +273,7 @@ type (
 	InferOptions       = ops.InferOptions
 	DiffOptions        = ops.DiffOptions
 	DiffResult         = ops.DiffResult
+	DiffSeriesResult   = ops.DiffSeriesResult
 	ExplainOptions     = ops.ExplainOptions
 	ExplainResult      = ops.ExplainResult
 	ParseOptions       = ops.ParseOptions
@@ -118,6 +297,9 @@ type (
 	// Procedural operations types
 	Decision[T any] = ops.Decision[T]
 	DecisionResult  = ops.DecisionResult
+	DecideOptions   = ops.DecideOptions
+	TiePolicy       = ops.TiePolicy
+	TieError        = types.TieError
 	GuardResult     = ops.GuardResult
 
 	// New LLM operation types (v2)
@@ -130,6 +312,8 @@ type (
 	RankOptions               = ops.RankOptions
 	RankedItem[T any]         = ops.RankedItem[T]
 	RankResult[T any]         = ops.RankResult[T]
+	ShortlistScorer           = ops.ShortlistScorer
+	ShortlistStats            = ops.ShortlistStats
 	CompressOptions           = ops.CompressOptions
 	CompressResult[T any]     = ops.CompressResult[T]
 	DecomposeOptions          = ops.DecomposeOptions
@@ -140,6 +324,10 @@ type (
 	NormalizeOptions          = ops.NormalizeOptions
 	NormalizeChange           = ops.NormalizeChange
 	NormalizeResult[T any]    = ops.NormalizeResult[T]
+	BatchResult[T any]        = ops.BatchResult[T]
+	BatchError                = ops.BatchError
+	BatchMetadata             = ops.BatchMetadata
+	BatchOptions              = ops.BatchOptions
 	MatchOptions              = ops.MatchOptions
 	MatchPair[S any, T any]   = ops.MatchPair[S, T]
 	MatchResult[S any, T any] = ops.MatchResult[S, T]
@@ -156,6 +344,7 @@ type (
 	PredictionInterval        = ops.PredictionInterval
 	PredictionScenario        = ops.PredictionScenario
 	PredictionFactor          = ops.PredictionFactor
+	PredictionDriver          = ops.PredictionDriver
 	PredictResult[T any]      = ops.PredictResult[T]
 	VerifyOptions             = ops.VerifyOptions
 	ClaimVerification         = ops.ClaimVerification
@@ -167,10 +356,13 @@ type (
 	ClassifyResult[C any]      = ops.ClassifyResult[C]
 	ClassifyAlternative[C any] = ops.ClassifyAlternative[C]
 	ScoreResult                = ops.ScoreResult
+	Criterion                  = ops.Criterion
+	Rubric                     = ops.Rubric
 	CompareResult[T any]       = ops.CompareResult[T]
 	ComparisonPoint            = ops.ComparisonPoint
 	SimilarResult              = ops.SimilarResult
 	AspectMatch                = ops.AspectMatch
+	AspectContribution         = ops.AspectContribution
 
 	// Data-centric LLM operations (v3)
 	NegotiateOptions       = ops.NegotiateOptions
@@ -188,10 +380,12 @@ type (
 	ConformOptions       = ops.ConformOptions
 	Adjustment           = ops.Adjustment
 	ConformResult[T any] = ops.ConformResult[T]
+	TransformRule        = ops.TransformRule
 
 	InterpolateOptions       = ops.InterpolateOptions
 	FilledItem               = ops.FilledItem
 	InterpolateResult[T any] = ops.InterpolateResult[T]
+	SequenceConstraint       = ops.SequenceConstraint
 
 	ArbitrateOptions       = ops.ArbitrateOptions
 	RuleEvaluation         = ops.RuleEvaluation
@@ -207,6 +401,30 @@ type (
 	AuditSummary       = ops.AuditSummary
 	AuditResult[T any] = ops.AuditResult[T]
 
+	AuditRecordFindings          = ops.AuditRecordFindings
+	AuditCollectionResult[T any] = ops.AuditCollectionResult[T]
+
+	Policy = ops.Policy
+
+	RecordError = ops.RecordError
+
+	FieldGrounding        = ops.FieldGrounding
+	GroundedResult[T any] = ops.GroundedResult[T]
+
+	Candidate                 = ops.Candidate
+	FieldAlternatives         = ops.FieldAlternatives
+	AlternativesResult[T any] = ops.AlternativesResult[T]
+
+	ConsistentResult[T any] = ops.ConsistentResult[T]
+
+	ExtractSnapshot[T any] = ops.ExtractSnapshot[T]
+
+	TransformResult[U any] = ops.TransformResult[U]
+
+	Stage[In any, Out any] = ops.Stage[In, Out]
+
+	DeduplicateStreamOptions = ops.DeduplicateStreamOptions
+
 	ComposeOptions       = ops.ComposeOptions
 	ComposedField        = ops.ComposedField
 	ComposeResult[T any] = ops.ComposeResult[T]
@@ -217,7 +435,10 @@ type (
 	PivotResult[U any] = ops.PivotResult[U]
 
 	// Text operation result types with metadata
+	TextResult             = ops.TextResult
 	SummarizeResult        = ops.SummarizeResult
+	StructuredSummary      = ops.StructuredSummary
+	SummarySection         = ops.SummarySection
 	RewriteResult          = ops.RewriteResult
 	TranslateResult        = ops.TranslateResult
 	TranslationAlternative = ops.TranslationAlternative
@@ -253,6 +474,30 @@ const (
 	Quick = types.Quick
 )
 
+// JSON mode constants
+const (
+	// JSONModeAuto requests native JSON for structured operations when the provider supports it.
+	JSONModeAuto = types.JSONModeAuto
+
+	// JSONModeOn requests native JSON for every call and fails on providers without it.
+	JSONModeOn = types.JSONModeOn
+
+	// JSONModeOff relies on prompt instructions alone.
+	JSONModeOff = types.JSONModeOff
+)
+
+// Truncation policy constants
+const (
+	// TruncationError fails prompts above the limit with ErrInputTooLarge.
+	TruncationError = types.TruncationError
+
+	// TruncateInput cuts the end of the input until the prompt fits.
+	TruncateInput = types.TruncateInput
+
+	// SummarizeInput summarizes the input to fit before the operation runs.
+	SummarizeInput = types.SummarizeInput
+)
+
 // Log level constants.
 const (
 	LogDebug = telemetry.DebugLevel
@@ -285,6 +530,21 @@ const (
 	RedactScramble = ops.RedactScramble
 )
 
+// Validation error code constants
+const (
+	ErrorCodeInvalidFormat = ops.ErrorCodeInvalidFormat
+	ErrorCodeOutOfRange    = ops.ErrorCodeOutOfRange
+	ErrorCodeRequired      = ops.ErrorCodeRequired
+	ErrorCodeEnum          = ops.ErrorCodeEnum
+)
+
+// Decide tie policy constants
+const (
+	TiePolicyFirst    = ops.TiePolicyFirst
+	TiePolicyError    = ops.TiePolicyError
+	TiePolicyEscalate = ops.TiePolicyEscalate
+)
+
 // Jumble mode constants
 const (
 	JumbleBasic     = ops.JumbleBasic
@@ -307,10 +567,12 @@ var (
 	NewTransformOptions = ops.NewTransformOptions
 	NewGenerateOptions  = ops.NewGenerateOptions
 	NewChooseOptions    = ops.NewChooseOptions
+	NewDecideOptions    = ops.NewDecideOptions
 	NewFilterOptions    = ops.NewFilterOptions
 	NewSortOptions      = ops.NewSortOptions
 	NewClassifyOptions  = ops.NewClassifyOptions
 	NewScoreOptions     = ops.NewScoreOptions
+	NewRubric           = ops.NewRubric
 	NewCompareOptions   = ops.NewCompareOptions
 	NewSimilarOptions   = ops.NewSimilarOptions
 	NewInferOptions     = ops.NewInferOptions
@@ -332,6 +594,7 @@ var (
 	NewDecomposeOptions  = ops.NewDecomposeOptions
 	NewEnrichOptions     = ops.NewEnrichOptions
 	NewNormalizeOptions  = ops.NewNormalizeOptions
+	NewBatchOptions      = ops.NewBatchOptions
 	NewMatchOptions      = ops.NewMatchOptions
 	NewCritiqueOptions   = ops.NewCritiqueOptions
 	NewSynthesizeOptions = ops.NewSynthesizeOptions
@@ -349,6 +612,9 @@ var (
 	NewZAIProvider              = llm.NewZAIProvider
 	NewLocalProvider            = llm.NewLocalProvider
 	NewOpenAICompatibleProvider = llm.NewOpenAICompatibleProvider
+	InferOperation              = llm.InferOperation
+	NewMemoryCache              = llm.NewMemoryCache
+	NewDiskCache                = llm.NewDiskCache
 
 	RegisterProvider        = llm.RegisterProvider
 	RegisterProviderFactory = llm.RegisterProviderFactory
@@ -372,6 +638,97 @@ func Extract[T any](input any, opts ExtractOptions) (T, error) {
 	return ops.Extract[T](input, opts)
 }
 
+// ExtractLike extracts data shaped like example, inferring the schema from
+// the example's nested structure instead of a declared type. The result has
+// the example's type.
+//
+// Example:
+//
+//	result, err := schemaflow.ExtractLike(email, map[string]any{
+//	    "sender": map[string]any{"name": "", "email": ""},
+//	    "items":  []any{map[string]any{"sku": "", "qty": 0}},
+//	}, schemaflow.NewExtractOptions())
+func ExtractLike(input, example any, opts ExtractOptions) (any, error) {
+	return ops.ExtractLike(input, example, opts)
+}
+
+// ExtractRecords extracts each record of multi-record input independently,
+// returning the good records and a RecordError for every malformed one.
+//
+// Example:
+//
+//	people, bad, err := schemaflow.ExtractRecords[Person](rows, schemaflow.NewExtractOptions())
+func ExtractRecords[T any](input any, opts ExtractOptions) ([]T, []RecordError, error) {
+	return ops.ExtractRecords[T](input, opts)
+}
+
+// ExtractConsistent extracts T, checks the cross-field rules set with
+// WithConsistencyRules, re-prompts once on violation and reports the repairs.
+//
+// Example:
+//
+//	result, err := schemaflow.ExtractConsistent[Invoice](document, schemaflow.NewExtractOptions().
+//	    WithConsistencyRules([]string{"total == subtotal + tax"}))
+//	fmt.Println(result.ConsistencyRepairs)
+func ExtractConsistent[T any](input any, opts ExtractOptions) (ConsistentResult[T], error) {
+	return ops.ExtractConsistent[T](input, opts)
+}
+
+// ExtractGrounded extracts T and reports the input substring each field was
+// drawn from; fields with no supporting text are left empty and flagged.
+//
+// Example:
+//
+//	result, err := schemaflow.ExtractGrounded[Invoice](document, schemaflow.NewExtractOptions())
+//	fmt.Println(result.Ungrounded)
+func ExtractGrounded[T any](input any, opts ExtractOptions) (GroundedResult[T], error) {
+	return ops.ExtractGrounded[T](input, opts)
+}
+
+// PIIFields lists the fields of value tagged `pii:"true"` that hold a value,
+// as dotted JSON paths, for enforcing data policy when logging or storing.
+//
+// Example:
+//
+//	fields := schemaflow.PIIFields(customer) // e.g. [email phone]
+func PIIFields(value any) []string {
+	return ops.PIIFields(value)
+}
+
+// RedactStruct returns a copy of value with fields tagged `pii:"true"` masked,
+// for logging.
+//
+// Example:
+//
+//	log.Printf("customer: %+v", schemaflow.RedactStruct(customer))
+func RedactStruct[T any](value T) T {
+	return ops.RedactStruct(value)
+}
+
+// ExtractAlternatives extracts T and returns the candidate values, with their
+// confidence, of each top-level field the input reads more than one way.
+//
+// Example:
+//
+//	result, err := schemaflow.ExtractAlternatives[Contact](signature, schemaflow.NewExtractOptions())
+//	fmt.Println(result.Fields["title"].Candidates)
+func ExtractAlternatives[T any](input any, opts ExtractOptions) (AlternativesResult[T], error) {
+	return ops.ExtractAlternatives[T](input, opts)
+}
+
+// ExtractStream extracts T and emits partially populated snapshots as fields
+// arrive from a streaming provider; the last snapshot is Final.
+//
+// Example:
+//
+//	snapshots, err := schemaflow.ExtractStream[Invoice](document, schemaflow.NewExtractOptions())
+//	for snap := range snapshots {
+//	    fmt.Println(snap.Fields, snap.Final)
+//	}
+func ExtractStream[T any](input any, opts ExtractOptions) (<-chan ExtractSnapshot[T], error) {
+	return ops.ExtractStream[T](input, opts)
+}
+
 // Transform converts data from one type to another using LLM intelligence.
 //
 // Example:
@@ -381,6 +738,17 @@ func Transform[T any, U any](input T, opts TransformOptions) (U, error) {
 	return ops.Transform[T, U](input, opts)
 }
 
+// TransformWithMetadata transforms input, copying fields the two types share
+// by name and type verbatim, and reports which fields were copied.
+//
+// Example:
+//
+//	result, err := schemaflow.TransformWithMetadata[Lead, Customer](lead, schemaflow.NewTransformOptions())
+//	fmt.Println(result.CopiedFields)
+func TransformWithMetadata[T any, U any](input T, opts TransformOptions) (TransformResult[U], error) {
+	return ops.TransformWithMetadata[T, U](input, opts)
+}
+
 // Generate creates new data based on a prompt.
 //
 // Example:
@@ -390,6 +758,26 @@ func Generate[T any](prompt string, opts GenerateOptions) (T, error) {
 	return ops.Generate[T](prompt, opts)
 }
 
+// Entity declares a kind of record for GenerateRelated to generate into *into.
+func Entity[T any](name string, count int, into *[]T) RelatedEntity {
+	return ops.Entity(name, count, into)
+}
+
+// GenerateRelated generates related records whose foreign keys reference
+// generated parents.
+//
+// Example:
+//
+//	var users []User
+//	var orders []Order
+//	err := schemaflow.GenerateRelated("Bookshop customers and their orders",
+//	    []schemaflow.RelatedEntity{schemaflow.Entity("users", 3, &users), schemaflow.Entity("orders", 8, &orders)},
+//	    []schemaflow.Reference{{Child: "orders", ForeignKey: "user_id", Parent: "users", Key: "id"}},
+//	    schemaflow.NewGenerateOptions())
+func GenerateRelated(prompt string, entities []RelatedEntity, references []Reference, opts GenerateOptions) error {
+	return ops.GenerateRelated(prompt, entities, references, opts)
+}
+
 // Choose selects the best option from a list based on criteria.
 //
 // Example:
@@ -399,6 +787,29 @@ func Choose[T any](options []T, opts ChooseOptions) (T, error) {
 	return ops.Choose(options, opts)
 }
 
+// ChooseWithShortlist is Choose that also reports how many options were passed
+// in and how many reached the LLM after the shortlist stage.
+//
+// Example:
+//
+//	best, stats, err := schemaflow.ChooseWithShortlist(catalog, schemaflow.NewChooseOptions().
+//	    WithCriteria([]string{"waterproof hiking boots"}).WithShortlistStage(50))
+func ChooseWithShortlist[T any](options []T, opts ChooseOptions) (T, ShortlistStats, error) {
+	return ops.ChooseWithShortlist(options, opts)
+}
+
+// ChooseByDescription selects the best item using a caller-provided description
+// of each option, for items that do not serialize to a common shape. (ChooseBy
+// at the package root is the compact fluent entrypoint.)
+//
+// Example:
+//
+//	best, err := schemaflow.ChooseByDescription(vendors, func(v Vendor) string { return v.Summary() },
+//	    schemaflow.NewChooseOptions().WithCriteria([]string{"lowest total cost"}))
+func ChooseByDescription[T any](items []T, describe func(T) string, opts ChooseOptions) (T, error) {
+	return ops.ChooseBy(items, describe, opts)
+}
+
 // Filter filters items based on natural language criteria.
 //
 // Example:
@@ -408,6 +819,12 @@ func Filter[T any](items []T, opts FilterOptions) ([]T, error) {
 	return ops.Filter(items, opts)
 }
 
+// FilterWithShortlist is Filter that also reports how many items were passed
+// in and how many reached the LLM after the shortlist stage.
+func FilterWithShortlist[T any](items []T, opts FilterOptions) ([]T, ShortlistStats, error) {
+	return ops.FilterWithShortlist(items, opts)
+}
+
 // Sort sorts items based on natural language criteria.
 //
 // Example:
@@ -417,6 +834,13 @@ func Sort[T any](items []T, opts SortOptions) ([]T, error) {
 	return ops.Sort(items, opts)
 }
 
+// SortWithShortlist is Sort that also reports how many items were passed in
+// and how many the LLM sorted after the shortlist stage; items past
+// stats.Shortlisted are not ordered by the criteria.
+func SortWithShortlist[T any](items []T, opts SortOptions) ([]T, ShortlistStats, error) {
+	return ops.SortWithShortlist(items, opts)
+}
+
 // Classify categorizes any Go type into typed categories.
 //
 // Type parameter T specifies the input type.
@@ -431,6 +855,23 @@ func Classify[T any, C any](input T, opts ClassifyOptions) (ClassifyResult[C], e
 	return ops.Classify[T, C](input, opts)
 }
 
+// RegisterEnum declares the constants of a string enum type so Classify can
+// take its categories from the type: with a registered C, omitted categories
+// default to the constants and listed ones must be among them.
+//
+// Example:
+//
+//	type Sentiment string
+//	const (
+//	    Positive Sentiment = "positive"
+//	    Negative Sentiment = "negative"
+//	)
+//	schemaflow.RegisterEnum(Positive, Negative)
+//	result, err := schemaflow.Classify[string, Sentiment](review, schemaflow.NewClassifyOptions())
+func RegisterEnum[C ~string](values ...C) {
+	ops.RegisterEnum(values...)
+}
+
 // Score rates any Go type based on specified criteria.
 //
 // Type parameter T specifies the input type.
@@ -444,6 +885,19 @@ func Score[T any](input T, opts ScoreOptions) (ScoreResult, error) {
 	return ops.Score(input, opts)
 }
 
+// ScoreWithRubric scores an item against a reusable weighted Rubric, combining
+// per-criterion scores into a weighted total.
+//
+// Example:
+//
+//	rubric, _ := schemaflow.NewRubric([]schemaflow.Criterion{
+//	    {Name: "clarity", Weight: 1}, {Name: "accuracy", Weight: 3},
+//	})
+//	result, err := schemaflow.ScoreWithRubric[Essay](essay, rubric, schemaflow.NewScoreOptions())
+func ScoreWithRubric[T any](item T, rubric Rubric, opts ScoreOptions) (ScoreResult, error) {
+	return ops.ScoreWithRubric(item, rubric, opts)
+}
+
 // Compare analyzes similarities and differences between two items of the same type.
 //
 // Type parameter T specifies the type of items being compared.
@@ -487,6 +941,26 @@ func Diff[T any](oldData, newData T, opts DiffOptions) (DiffResult, error) {
 	return ops.Diff(oldData, newData, opts)
 }
 
+// CompareAndMerge diffs two records and proposes a merged record in one call,
+// annotating each differing field with the value chosen and why.
+//
+// Example:
+//
+//	diff, merged, err := schemaflow.CompareAndMerge(crmCustomer, billingCustomer, schemaflow.NewDiffOptions())
+func CompareAndMerge[T any](a, b T, opts DiffOptions) (DiffResult, MergeResult[T], error) {
+	return ops.CompareAndMerge(a, b, opts)
+}
+
+// DiffSeries diffs consecutive versions and combines them into a changelog.
+//
+// Example:
+//
+//	series, err := schemaflow.DiffSeries([]Config{v1, v2, v3}, schemaflow.NewDiffOptions())
+//	fmt.Println(series.Changelog)
+func DiffSeries[T any](versions []T, opts DiffOptions) (DiffSeriesResult, error) {
+	return ops.DiffSeries(versions, opts)
+}
+
 // Explain generates human-readable explanations for complex data.
 //
 // Example:
@@ -526,6 +1000,28 @@ func SummarizeWithMetadata(input string, opts SummarizeOptions) (SummarizeResult
 	return ops.SummarizeWithMetadata(input, opts)
 }
 
+// SummarizeStructured summarizes text into the fields of a struct, such as
+// StructuredSummary with its headline, bullets and sections.
+//
+// Example:
+//
+//	report, err := schemaflow.SummarizeStructured[schemaflow.StructuredSummary](incidentLog,
+//	    schemaflow.NewSummarizeOptions())
+//	fmt.Println(report.Headline, len(report.Sections))
+func SummarizeStructured[T any](input string, opts SummarizeOptions) (T, error) {
+	return ops.SummarizeStructured[T](input, opts)
+}
+
+// SummarizeAll map-reduces a collection into a single summary.
+//
+// Example:
+//
+//	summary, err := schemaflow.SummarizeAll(reviews, schemaflow.NewSummarizeOptions().
+//	    WithField("Body").WithTargetLength(150, "words"))
+func SummarizeAll[T any](items []T, opts SummarizeOptions) (string, error) {
+	return ops.SummarizeAll(items, opts)
+}
+
 // Rewrite rewrites text according to specified instructions.
 //
 // Example:
@@ -547,7 +1043,8 @@ func RewriteWithMetadata(input string, opts RewriteOptions) (RewriteResult, erro
 	return ops.RewriteWithMetadata(input, opts)
 }
 
-// Translate translates text to a target language.
+// Translate translates text to a target language. Placeholders such as
+// {username}, printf verbs and HTML tags pass through untranslated.
 //
 // Example:
 //
@@ -711,6 +1208,15 @@ func Question[T any, A any](data T, opts QuestionOptions) (QuestionResult[A], er
 	return ops.Question[T, A](data, opts)
 }
 
+// QuestionBatch answers several questions about one input in a single call.
+//
+// Example:
+//
+//	results, err := schemaflow.QuestionBatch(report, []string{"Total revenue?", "Top region?"}, schemaflow.NewQuestionOptions(""))
+func QuestionBatch[I any](input I, questions []string, opts QuestionOptions) ([]QuestionResult[string], error) {
+	return ops.QuestionBatch(input, questions, opts)
+}
+
 // QuestionLegacy answers questions about data using the legacy string interface.
 //
 // Example:
@@ -720,6 +1226,401 @@ func QuestionLegacy(data any, question string, opts ...OpOptions) (string, error
 	return ops.QuestionLegacy(data, question, opts...)
 }
 
+// MarshalResult serializes an operation result, such as an AuditResult or a
+// ResolveResult, into a versioned JSON document for storage and replay.
+// Errors held by batch and pipeline results are stored as their messages.
+//
+// Example:
+//
+//	data, err := schemaflow.MarshalResult(audit)
+//	replayed, err := schemaflow.UnmarshalResult[schemaflow.AuditResult[Customer]](data)
+func MarshalResult(result any) ([]byte, error) {
+	return ops.MarshalResult(result)
+}
+
+// UnmarshalResult reads a document written by MarshalResult back into the
+// result type R.
+//
+// Example:
+//
+//	resolved, err := schemaflow.UnmarshalResult[schemaflow.ResolveResult[Customer]](stored)
+func UnmarshalResult[R any](data []byte) (R, error) {
+	return ops.UnmarshalResult[R](data)
+}
+
+// WithResultMeta returns a context that records the provider metadata of every
+// LLM call made under it: the raw finish reason, the model actually used, the
+// system fingerprint and any safety flags. Pass the context to the Ctx
+// operation variants; a RunContext records the same metadata.
+//
+// Example:
+//
+//	ctx, meta := schemaflow.WithResultMeta(ctx)
+//	summary, err := schemaflow.SummarizeCtx(ctx, report, schemaflow.NewSummarizeOptions())
+//	if last, ok := meta.Last(); ok && last.FinishReason != "stop" {
+//	    log.Printf("summary ended with %s on %s", last.FinishReason, last.Model)
+//	}
+func WithResultMeta(ctx context.Context) (context.Context, *ResultMetaRecorder) {
+	return ops.WithResultMeta(ctx)
+}
+
+// ExtractCtx is Extract run under ctx: cancelling ctx or passing its deadline stops
+// the model call and returns an error wrapping ctx.Err(). Every core operation
+// has a Ctx variant; the plain functions run with context.Background().
+//
+// Example:
+//
+//	person, err := schemaflow.ExtractCtx[Person](r.Context(), body, schemaflow.NewExtractOptions())
+func ExtractCtx[T any](ctx context.Context, input any, opts ExtractOptions) (T, error) {
+	return ops.ExtractCtx[T](ctx, input, opts)
+}
+
+// ExtractStreamCtx is ExtractStream run under ctx: cancelling ctx aborts the
+// upstream request and closes the channel, so a consumer can stop early
+// without leaking the stream's goroutine.
+//
+// Example:
+//
+//	ctx, cancel := context.WithCancel(r.Context())
+//	defer cancel() // stops the stream if we return before it ends
+//	snapshots, err := schemaflow.ExtractStreamCtx[Invoice](ctx, document, schemaflow.NewExtractOptions())
+//	for snap := range snapshots {
+//	    if enough(snap.Value) {
+//	        break
+//	    }
+//	}
+func ExtractStreamCtx[T any](ctx context.Context, input any, opts ExtractOptions) (<-chan ExtractSnapshot[T], error) {
+	return ops.ExtractStreamCtx[T](ctx, input, opts)
+}
+
+// TransformCtx is Transform run under ctx.
+func TransformCtx[T any, U any](ctx context.Context, input T, opts TransformOptions) (U, error) {
+	return ops.TransformCtx[T, U](ctx, input, opts)
+}
+
+// GenerateCtx is Generate run under ctx.
+func GenerateCtx[T any](ctx context.Context, prompt string, opts GenerateOptions) (T, error) {
+	return ops.GenerateCtx[T](ctx, prompt, opts)
+}
+
+// SummarizeCtx is Summarize run under ctx.
+func SummarizeCtx(ctx context.Context, input string, opts SummarizeOptions) (string, error) {
+	return ops.SummarizeCtx(ctx, input, opts)
+}
+
+// RewriteCtx is Rewrite run under ctx.
+func RewriteCtx(ctx context.Context, input string, opts RewriteOptions) (string, error) {
+	return ops.RewriteCtx(ctx, input, opts)
+}
+
+// TranslateCtx is Translate run under ctx.
+func TranslateCtx(ctx context.Context, input string, opts TranslateOptions) (string, error) {
+	return ops.TranslateCtx(ctx, input, opts)
+}
+
+// ExpandCtx is Expand run under ctx.
+func ExpandCtx(ctx context.Context, input string, opts ExpandOptions) (string, error) {
+	return ops.ExpandCtx(ctx, input, opts)
+}
+
+// ClassifyCtx is Classify run under ctx.
+func ClassifyCtx[T any, C any](ctx context.Context, input T, opts ClassifyOptions) (ClassifyResult[C], error) {
+	return ops.ClassifyCtx[T, C](ctx, input, opts)
+}
+
+// ScoreCtx is Score run under ctx.
+func ScoreCtx[T any](ctx context.Context, input T, opts ScoreOptions) (ScoreResult, error) {
+	return ops.ScoreCtx(ctx, input, opts)
+}
+
+// CompareCtx is Compare run under ctx.
+func CompareCtx[T any](ctx context.Context, itemA, itemB T, opts CompareOptions) (CompareResult[T], error) {
+	return ops.CompareCtx(ctx, itemA, itemB, opts)
+}
+
+// ChooseCtx is Choose run under ctx.
+func ChooseCtx[T any](ctx context.Context, options []T, opts ChooseOptions) (T, error) {
+	return ops.ChooseCtx(ctx, options, opts)
+}
+
+// FilterCtx is Filter run under ctx.
+func FilterCtx[T any](ctx context.Context, items []T, opts FilterOptions) ([]T, error) {
+	return ops.FilterCtx(ctx, items, opts)
+}
+
+// SortCtx is Sort run under ctx.
+func SortCtx[T any](ctx context.Context, items []T, opts SortOptions) ([]T, error) {
+	return ops.SortCtx(ctx, items, opts)
+}
+
+// ValidateCtx is Validate run under ctx.
+func ValidateCtx[T any](ctx context.Context, data T, opts ValidateOptions) (ValidateResult[T], error) {
+	return ops.ValidateCtx(ctx, data, opts)
+}
+
+// QuestionCtx is Question run under ctx.
+func QuestionCtx[T any, A any](ctx context.Context, data T, opts QuestionOptions) (QuestionResult[A], error) {
+	return ops.QuestionCtx[T, A](ctx, data, opts)
+}
+
+// AnnotateCtx is Annotate run under ctx.
+func AnnotateCtx[T any](ctx context.Context, input T, opts AnnotateOptions) (AnnotateResult, error) {
+	return ops.AnnotateCtx(ctx, input, opts)
+}
+
+// ClusterCtx is Cluster run under ctx.
+func ClusterCtx[T any](ctx context.Context, items []T, opts ClusterOptions) (ClusterResult[T], error) {
+	return ops.ClusterCtx(ctx, items, opts)
+}
+
+// RankCtx is Rank run under ctx.
+func RankCtx[T any](ctx context.Context, items []T, opts RankOptions) (RankResult[T], error) {
+	return ops.RankCtx(ctx, items, opts)
+}
+
+// EnrichCtx is Enrich run under ctx.
+func EnrichCtx[T any, U any](ctx context.Context, input T, opts EnrichOptions) (EnrichResult[U], error) {
+	return ops.EnrichCtx[T, U](ctx, input, opts)
+}
+
+// VerifyCtx is Verify run under ctx.
+func VerifyCtx(ctx context.Context, input any, opts VerifyOptions) (VerifyResult, error) {
+	return ops.VerifyCtx(ctx, input, opts)
+}
+
+// PredictCtx is Predict run under ctx.
+func PredictCtx[T any](ctx context.Context, historicalData any, opts PredictOptions) (PredictResult[T], error) {
+	return ops.PredictCtx[T](ctx, historicalData, opts)
+}
+
+// NormalizeCtx is Normalize run under ctx.
+func NormalizeCtx[T any](ctx context.Context, input T, opts NormalizeOptions) (NormalizeResult[T], error) {
+	return ops.NormalizeCtx(ctx, input, opts)
+}
+
+// CompressCtx is Compress run under ctx.
+func CompressCtx[T any](ctx context.Context, input T, opts CompressOptions) (CompressResult[T], error) {
+	return ops.CompressCtx(ctx, input, opts)
+}
+
+// DecomposeCtx is Decompose run under ctx.
+func DecomposeCtx[T any](ctx context.Context, input T, opts DecomposeOptions) (DecomposeResult[T], error) {
+	return ops.DecomposeCtx(ctx, input, opts)
+}
+
+// CritiqueCtx is Critique run under ctx.
+func CritiqueCtx[T any](ctx context.Context, input T, opts CritiqueOptions) (CritiqueResult, error) {
+	return ops.CritiqueCtx(ctx, input, opts)
+}
+
+// SynthesizeCtx is Synthesize run under ctx.
+func SynthesizeCtx[T any](ctx context.Context, sources []any, opts SynthesizeOptions) (SynthesizeResult[T], error) {
+	return ops.SynthesizeCtx[T](ctx, sources, opts)
+}
+
+// SemanticMatchCtx is SemanticMatch run under ctx.
+func SemanticMatchCtx[S any, T any](ctx context.Context, sources []S, targets []T, opts MatchOptions) (MatchResult[S, T], error) {
+	return ops.SemanticMatchCtx(ctx, sources, targets, opts)
+}
+
+// SuggestCtx is Suggest run under ctx.
+func SuggestCtx[T any](ctx context.Context, input any, opts SuggestOptions) ([]T, error) {
+	return ops.SuggestCtx[T](ctx, input, opts)
+}
+
+// InferCtx is Infer run under ctx.
+func InferCtx[T any](ctx context.Context, partialData T, opts InferOptions) (T, error) {
+	return ops.InferCtx(ctx, partialData, opts)
+}
+
+// DiffCtx is Diff run under ctx.
+func DiffCtx[T any](ctx context.Context, oldData, newData T, opts DiffOptions) (DiffResult, error) {
+	return ops.DiffCtx(ctx, oldData, newData, opts)
+}
+
+// ExplainCtx is Explain run under ctx.
+func ExplainCtx(ctx context.Context, data any, opts ExplainOptions) (ExplainResult, error) {
+	return ops.ExplainCtx(ctx, data, opts)
+}
+
+// ParseCtx is Parse run under ctx.
+func ParseCtx[T any](ctx context.Context, input any, opts ParseOptions) (ParseResult[T], error) {
+	return ops.ParseCtx[T](ctx, input, opts)
+}
+
+// AuditCtx is Audit run under ctx.
+func AuditCtx[T any](ctx context.Context, data T, opts ...AuditOptions) (AuditResult[T], error) {
+	return ops.AuditCtx(ctx, data, opts...)
+}
+
+// NegotiateCtx is Negotiate run under ctx.
+func NegotiateCtx[T any](ctx context.Context, constraints any, opts ...NegotiateOptions) (NegotiateResult[T], error) {
+	return ops.NegotiateCtx[T](ctx, constraints, opts...)
+}
+
+// ConformCtx is Conform run under ctx.
+func ConformCtx[T any](ctx context.Context, input T, standard string, opts ...ConformOptions) (ConformResult[T], error) {
+	return ops.ConformCtx(ctx, input, standard, opts...)
+}
+
+// ArbitrateCtx is Arbitrate run under ctx.
+func ArbitrateCtx[T any](ctx context.Context, options []T, opts ...ArbitrateOptions) (ArbitrateResult[T], error) {
+	return ops.ArbitrateCtx(ctx, options, opts...)
+}
+
+// ProjectCtx is Project run under ctx.
+func ProjectCtx[T any, U any](ctx context.Context, input T, opts ...ProjectOptions) (ProjectResult[U], error) {
+	return ops.ProjectCtx[T, U](ctx, input, opts...)
+}
+
+// PivotCtx is Pivot run under ctx.
+func PivotCtx[T any, U any](ctx context.Context, input T, opts ...PivotOptions) (PivotResult[U], error) {
+	return ops.PivotCtx[T, U](ctx, input, opts...)
+}
+
+// ResolveCtx is Resolve run under ctx.
+func ResolveCtx[T any](ctx context.Context, sources []T, opts ...ResolveOptions) (ResolveResult[T], error) {
+	return ops.ResolveCtx(ctx, sources, opts...)
+}
+
+// DeriveCtx is Derive run under ctx.
+func DeriveCtx[T any, U any](ctx context.Context, input T, opts ...DeriveOptions) (DeriveResult[U], error) {
+	return ops.DeriveCtx[T, U](ctx, input, opts...)
+}
+
+// InterpolateCtx is Interpolate run under ctx.
+func InterpolateCtx[T any](ctx context.Context, items []T, opts ...InterpolateOptions) (InterpolateResult[T], error) {
+	return ops.InterpolateCtx(ctx, items, opts...)
+}
+
+// MergeCtx is Merge run under ctx.
+func MergeCtx[T any](ctx context.Context, sources []T, strategy string, opts ...OpOptions) (T, error) {
+	return ops.MergeCtx(ctx, sources, strategy, opts...)
+}
+
+// FormatCtx is Format run under ctx.
+func FormatCtx(ctx context.Context, data any, template string, opts ...OpOptions) (string, error) {
+	return ops.FormatCtx(ctx, data, template, opts...)
+}
+
+// DecideCtx is Decide run under ctx; input is the value the decisions are made
+// about.
+func DecideCtx[T any](ctx context.Context, input any, decisions []Decision[T], opts ...OpOptions) (T, DecisionResult, error) {
+	return ops.DecideCtx(ctx, input, decisions, opts...)
+}
+
+// TransformWithMetadataCtx is TransformWithMetadata run under ctx.
+func TransformWithMetadataCtx[T any, U any](ctx context.Context, input T, opts TransformOptions) (TransformResult[U], error) {
+	return ops.TransformWithMetadataCtx[T, U](ctx, input, opts)
+}
+
+// SummarizeWithMetadataCtx is SummarizeWithMetadata run under ctx.
+func SummarizeWithMetadataCtx(ctx context.Context, input string, opts SummarizeOptions) (SummarizeResult, error) {
+	return ops.SummarizeWithMetadataCtx(ctx, input, opts)
+}
+
+// RewriteWithMetadataCtx is RewriteWithMetadata run under ctx.
+func RewriteWithMetadataCtx(ctx context.Context, input string, opts RewriteOptions) (RewriteResult, error) {
+	return ops.RewriteWithMetadataCtx(ctx, input, opts)
+}
+
+// TranslateWithMetadataCtx is TranslateWithMetadata run under ctx.
+func TranslateWithMetadataCtx(ctx context.Context, input string, opts TranslateOptions) (TranslateResult, error) {
+	return ops.TranslateWithMetadataCtx(ctx, input, opts)
+}
+
+// ExpandWithMetadataCtx is ExpandWithMetadata run under ctx.
+func ExpandWithMetadataCtx(ctx context.Context, input string, opts ExpandOptions) (ExpandResult, error) {
+	return ops.ExpandWithMetadataCtx(ctx, input, opts)
+}
+
+// MergeWithMetadataCtx is MergeWithMetadata run under ctx.
+func MergeWithMetadataCtx[T any](ctx context.Context, sources []T, strategy string, opts ...OpOptions) (MergeResult[T], error) {
+	return ops.MergeWithMetadataCtx(ctx, sources, strategy, opts...)
+}
+
+// FormatWithMetadataCtx is FormatWithMetadata run under ctx.
+func FormatWithMetadataCtx(ctx context.Context, data any, template string, opts ...OpOptions) (FormatResult, error) {
+	return ops.FormatWithMetadataCtx(ctx, data, template, opts...)
+}
+
+// CompleteCtx is Complete run under ctx.
+func CompleteCtx(ctx context.Context, partialText string, opts CompleteOptions) (CompleteResult, error) {
+	return ops.Complete(ctx, nil, partialText, opts)
+}
+
+// CompleteFieldCtx is CompleteField run under ctx.
+func CompleteFieldCtx[T any](ctx context.Context, data T, opts CompleteFieldOptions) (CompleteFieldResult[T], error) {
+	return ops.CompleteField[T](ctx, nil, data, opts)
+}
+
+// SimilarCtx is Similar run under ctx.
+func SimilarCtx[T any](ctx context.Context, itemA, itemB T, opts SimilarOptions) (SimilarResult, error) {
+	return ops.SimilarCtx(ctx, itemA, itemB, opts)
+}
+
+// RedactCtx is Redact run under ctx. Redaction is local, so ctx is only
+// checked before it starts.
+func RedactCtx[T any](ctx context.Context, input T, opts RedactOptions) (T, error) {
+	return ops.RedactCtx(ctx, input, opts)
+}
+
+// RedactLLMCtx is RedactLLM run under ctx.
+func RedactLLMCtx(ctx context.Context, text string, opts RedactLLMOptions) (RedactLLMResult, error) {
+	return ops.RedactLLM(ctx, text, opts)
+}
+
+// GuardCtx is Guard with the suggestions for failed checks requested under
+// ctx.
+func GuardCtx[T any](ctx context.Context, state T, checks ...func(T) (bool, string)) GuardResult {
+	return ops.GuardCtx(ctx, state, checks...)
+}
+
+// VerifyClaimCtx is VerifyClaim run under ctx.
+func VerifyClaimCtx(ctx context.Context, claim string, opts VerifyOptions) (ClaimVerification, error) {
+	return ops.VerifyClaimCtx(ctx, claim, opts)
+}
+
+// CompressTextCtx is CompressText run under ctx.
+func CompressTextCtx(ctx context.Context, input string, opts CompressOptions) (string, error) {
+	return ops.CompressTextCtx(ctx, input, opts)
+}
+
+// NormalizeTextCtx is NormalizeText run under ctx.
+func NormalizeTextCtx(ctx context.Context, input string, opts NormalizeOptions) (string, error) {
+	return ops.NormalizeTextCtx(ctx, input, opts)
+}
+
+// NormalizeBatchCtx is NormalizeBatch run under ctx.
+func NormalizeBatchCtx[T any](ctx context.Context, items []T, opts NormalizeOptions) (BatchResult[NormalizeResult[T]], error) {
+	return ops.NormalizeBatchCtx(ctx, items, opts)
+}
+
+// MatchOneCtx is MatchOne run under ctx.
+func MatchOneCtx[S any, T any](ctx context.Context, source S, targets []T, opts MatchOptions) ([]MatchPair[S, T], error) {
+	return ops.MatchOneCtx(ctx, source, targets, opts)
+}
+
+// AssembleCtx is Assemble run under ctx.
+func AssembleCtx[T any](ctx context.Context, parts []any, opts ...ComposeOptions) (ComposeResult[T], error) {
+	return ops.AssembleCtx[T](ctx, parts, opts...)
+}
+
+// EnrichInPlaceCtx is EnrichInPlace run under ctx.
+func EnrichInPlaceCtx[T any](ctx context.Context, input T, opts EnrichOptions) (T, error) {
+	return ops.EnrichInPlaceCtx(ctx, input, opts)
+}
+
+// DecomposeToSliceCtx is DecomposeToSlice run under ctx.
+func DecomposeToSliceCtx[T any, U any](ctx context.Context, input T, opts DecomposeOptions) ([]U, error) {
+	return ops.DecomposeToSliceCtx[T, U](ctx, input, opts)
+}
+
+// NegotiateAdversarialCtx is NegotiateAdversarial run under ctx.
+func NegotiateAdversarialCtx[T any](ctx context.Context, adversarial AdversarialContext[T], opts ...AdversarialOptions) (AdversarialResult[T], error) {
+	return ops.NegotiateAdversarialCtx[T](ctx, adversarial, opts...)
+}
+
 // Merge combines multiple data sources using a specified strategy.
 //
 // Example:
@@ -770,6 +1671,17 @@ func Decide[T any](ctx any, decisions []Decision[T], opts ...OpOptions) (T, Deci
 	return ops.Decide(ctx, decisions, opts...)
 }
 
+// DecideWithOptions makes a decision with a configurable policy for options
+// the model scores within a tie epsilon of each other.
+//
+// Example:
+//
+//	department, result, err := schemaflow.DecideWithOptions(ticket, departments,
+//	    schemaflow.NewDecideOptions().WithTiePolicy(schemaflow.TiePolicyEscalate, 0.05))
+func DecideWithOptions[T any](ctx any, decisions []Decision[T], opts DecideOptions) (T, DecisionResult, error) {
+	return ops.DecideWithOptions(ctx, decisions, opts)
+}
+
 // Guard checks if conditions are met before proceeding.
 //
 // Example:
@@ -779,6 +1691,62 @@ func Guard[T any](state T, checks ...func(T) (bool, string)) GuardResult {
 	return ops.Guard(state, checks...)
 }
 
+// GuardPolicies audits state against policies and blocks on blocking violations.
+//
+// Example:
+//
+//	policies, err := schemaflow.LoadPolicies("policies.yaml")
+//	result, err := schemaflow.GuardPolicies(order, policies)
+//	if !result.CanProceed {
+//	    fmt.Println(result.FailedChecks)
+//	}
+func GuardPolicies[T any](state T, policies []Policy, opts ...AuditOptions) (GuardResult, error) {
+	return ops.GuardPolicies(state, policies, opts...)
+}
+
+// LoadPolicies reads named policies from a YAML or JSON policy file.
+//
+// Example:
+//
+//	policies, err := schemaflow.LoadPolicies("compliance/policies.yaml")
+//	result, err := schemaflow.Audit(customer, schemaflow.AuditOptions{PolicySet: policies})
+func LoadPolicies(path string) ([]Policy, error) {
+	return ops.LoadPolicies(path)
+}
+
+// Compose2 composes two typed operations; the second is skipped if the first fails.
+//
+// Example:
+//
+//	extractThenMap := schemaflow.Compose2(
+//	    func(s string) (Person, error) { return schemaflow.Extract[Person](s, schemaflow.NewExtractOptions()) },
+//	    func(p Person) (Employee, error) { return schemaflow.Transform[Person, Employee](p, schemaflow.NewTransformOptions()) },
+//	)
+//	employee, err := extractThenMap(text)
+func Compose2[A any, B any, C any](first func(A) (B, error), second func(B) (C, error)) func(A) (C, error) {
+	return ops.Compose2(first, second)
+}
+
+// Lift adapts a context-free operation into a Stage.
+func Lift[In any, Out any](operation func(In) (Out, error)) Stage[In, Out] {
+	return ops.Lift(operation)
+}
+
+// Chain composes two context-aware stages with error and cancellation short-circuiting.
+//
+// Example:
+//
+//	flow := schemaflow.Chain(schemaflow.Chain(extract, enrich), transform)
+//	out, err := flow(ctx, input)
+func Chain[A any, B any, C any](first Stage[A, B], next Stage[B, C]) Stage[A, C] {
+	return ops.Chain(first, next)
+}
+
+// ComposeStages chains same-typed stages left to right, stopping at the first error.
+func ComposeStages[T any](stages ...Stage[T, T]) Stage[T, T] {
+	return ops.ComposeStages(stages...)
+}
+
 // === New LLM Operations (v2) ===
 
 // Annotate extracts semantic annotations (entities, sentiments, topics) from text.
@@ -880,15 +1848,65 @@ func NormalizeText(input string, opts NormalizeOptions) (string, error) {
 	return ops.NormalizeText(input, opts)
 }
 
-// NormalizeBatch normalizes multiple items at once.
+// NormalizeBatch normalizes multiple items at once. Every item is attempted;
+// err is the first item failure and the result reports each item.
 //
 // Example:
 //
 //	results, err := schemaflow.NormalizeBatch(items, schemaflow.NewNormalizeOptions())
-func NormalizeBatch[T any](items []T, opts NormalizeOptions) ([]NormalizeResult[T], error) {
+//	for _, failure := range results.Failures() {
+//	    log.Printf("item %d: %v", failure.Index, failure.Err)
+//	}
+//	normalized := results.Successes()
+func NormalizeBatch[T any](items []T, opts NormalizeOptions) (BatchResult[NormalizeResult[T]], error) {
 	return ops.NormalizeBatch(items, opts)
 }
 
+// MapOp applies op to every item with bounded concurrency, optional retries
+// and per-item errors. Results stay aligned with items; err is the first
+// item failure.
+//
+// Example:
+//
+//	results, err := schemaflow.MapOp(snippets, func(snippet string) (schemaflow.ScoreResult, error) {
+//	    return schemaflow.Score(snippet, schemaflow.NewScoreOptions())
+//	}, schemaflow.NewBatchOptions().WithConcurrency(4).WithErrorStrategy("retry"))
+//	for _, failure := range results.Failures() {
+//	    log.Printf("snippet %d: %v", failure.Index, failure.Err)
+//	}
+func MapOp[T any, U any](items []T, op func(T) (U, error), opts BatchOptions) (BatchResult[U], error) {
+	return ops.MapOp(items, op, opts)
+}
+
+// ClassifyEach classifies every item with MapOp.
+//
+// Example:
+//
+//	results, err := schemaflow.ClassifyEach[string, string](tickets,
+//	    schemaflow.NewClassifyOptions().WithCategories([]string{"bug", "feature"}),
+//	    schemaflow.NewBatchOptions())
+func ClassifyEach[T any, C any](items []T, opts ClassifyOptions, batch BatchOptions) (BatchResult[ClassifyResult[C]], error) {
+	return ops.ClassifyEach[T, C](items, opts, batch)
+}
+
+// ScoreEach scores every item with MapOp.
+//
+// Example:
+//
+//	results, err := schemaflow.ScoreEach(essays, schemaflow.NewScoreOptions(), schemaflow.NewBatchOptions())
+func ScoreEach[T any](items []T, opts ScoreOptions, batch BatchOptions) (BatchResult[ScoreResult], error) {
+	return ops.ScoreEach(items, opts, batch)
+}
+
+// SummarizeEach summarizes every input with MapOp.
+//
+// Example:
+//
+//	results, err := schemaflow.SummarizeEach(articles, schemaflow.NewSummarizeOptions(), schemaflow.NewBatchOptions())
+func SummarizeEach(inputs []string, opts SummarizeOptions, batch BatchOptions) (BatchResult[string], error) {
+	return ops.SummarizeEach(inputs, opts, batch)
+}
+
 // SemanticMatch pairs items from two sets based on semantic similarity.
 // Note: This is different from the control-flow Match operation.
 //
@@ -984,6 +2002,24 @@ type AdversarialResult[T any] = ops.AdversarialResult[T]
 // AdversarialOptions configures the adversarial negotiation.
 type AdversarialOptions = ops.AdversarialOptions
 
+// TermConstraint is a non-negotiable numeric limit on a deal term.
+type TermConstraint = ops.TermConstraint
+
+// TermAtLeast returns a constraint with only a floor.
+func TermAtLeast(min float64) TermConstraint {
+	return ops.TermAtLeast(min)
+}
+
+// TermAtMost returns a constraint with only a ceiling.
+func TermAtMost(max float64) TermConstraint {
+	return ops.TermAtMost(max)
+}
+
+// TermBetween returns a constraint with both a floor and a ceiling.
+func TermBetween(min, max float64) TermConstraint {
+	return ops.TermBetween(min, max)
+}
+
 // NegotiateAdversarial conducts a two-party adversarial negotiation.
 //
 // This models real-world negotiations where two parties with opposing interests
@@ -1011,6 +2047,31 @@ func NegotiateAdversarial[T any](context AdversarialContext[T], opts ...Adversar
 	return ops.NegotiateAdversarial[T](context, opts...)
 }
 
+// Position is one party's stake in a collaborative negotiation.
+type Position[T any] = ops.Position[T]
+
+// CollaborativeResult contains the outcome of a collaborative negotiation.
+type CollaborativeResult[T any] = ops.CollaborativeResult[T]
+
+// NegotiateCollaborative looks for a deal every party can accept, maximizing
+// their joint satisfaction. Deals outside a party's Ranges are re-prompted
+// once and reported with DealReached false.
+//
+// Example:
+//
+//	type Contract struct {
+//	    Price        int `json:"price"`
+//	    DeliveryDays int `json:"delivery_days"`
+//	}
+//	result, err := schemaflow.NegotiateCollaborative([]schemaflow.Position[Contract]{
+//	    {Party: "buyer", Ideal: Contract{Price: 90}, Ranges: map[string]schemaflow.TermConstraint{"price": schemaflow.TermAtMost(110)}},
+//	    {Party: "supplier", Ideal: Contract{Price: 120}, Ranges: map[string]schemaflow.TermConstraint{"price": schemaflow.TermAtLeast(100)}},
+//	})
+//	fmt.Println(result.Deal.Price, result.Satisfaction)
+func NegotiateCollaborative[T any](parties []Position[T], opts ...NegotiateOptions) (CollaborativeResult[T], error) {
+	return ops.NegotiateCollaborative(parties, opts...)
+}
+
 // Resolve resolves conflicts when multiple typed sources disagree.
 //
 // Type parameter T specifies the type of sources and the resolved output.
@@ -1054,6 +2115,16 @@ func Conform[T any](input T, standard string, opts ...ConformOptions) (ConformRe
 	return ops.Conform[T](input, standard, opts...)
 }
 
+// ApplyTransformRules replays the rules from a ConformResult on new input
+// without calling the model.
+//
+// Example:
+//
+//	normalized, err := schemaflow.ApplyTransformRules(nextAddress, result.Rules)
+func ApplyTransformRules[T any](input T, rules []TransformRule) (T, error) {
+	return ops.ApplyTransformRules(input, rules)
+}
+
 // Interpolate fills gaps in typed sequences intelligently.
 //
 // Type parameter T specifies the type of sequence items.
@@ -1068,6 +2139,22 @@ func Interpolate[T any](items []T, opts ...InterpolateOptions) (InterpolateResul
 	return ops.Interpolate[T](items, opts...)
 }
 
+// SequenceMonotonic requires a field to move in one direction across a
+// sequence: "increasing", "decreasing", "non_decreasing" or "non_increasing".
+func SequenceMonotonic(field, direction string) SequenceConstraint {
+	return ops.SequenceMonotonic(field, direction)
+}
+
+// SequenceRange requires a field to lie within [min, max] for every record.
+func SequenceRange(field string, min, max float64) SequenceConstraint {
+	return ops.SequenceRange(field, min, max)
+}
+
+// SequenceRelation requires a per-record comparison such as "high>=open" to hold.
+func SequenceRelation(expr string) SequenceConstraint {
+	return ops.SequenceRelation(expr)
+}
+
 // Arbitrate makes rule-based decisions with full audit trail.
 //
 // Type parameter T specifies the type of options to choose from.
@@ -1113,6 +2200,48 @@ func Audit[T any](data T, opts ...AuditOptions) (AuditResult[T], error) {
 	return ops.Audit[T](data, opts...)
 }
 
+// VerifyReport reports whether an audit result produced with HashReport still
+// matches input, detecting edits to its policies or findings after the audit.
+//
+// Example:
+//
+//	result, err := schemaflow.Audit(record, schemaflow.AuditOptions{HashReport: true})
+//	ok := schemaflow.VerifyReport(result, record)
+func VerifyReport[T any](result AuditResult[T], input T) bool {
+	return ops.VerifyReport(result, input)
+}
+
+// AuditCollection audits a collection of records with per-record and cross-record policies.
+//
+// Type parameter T specifies the record type.
+//
+// Example:
+//
+//	result, err := schemaflow.AuditCollection(customers, schemaflow.AuditOptions{
+//	    DatasetPolicies: []string{"No duplicate SSNs across records"},
+//	})
+//	for _, f := range result.DatasetFindings {
+//	    fmt.Printf("%s (records %v)\n", f.Issue, f.Records)
+//	}
+func AuditCollection[T any](records []T, opts ...AuditOptions) (AuditCollectionResult[T], error) {
+	return ops.AuditCollection[T](records, opts...)
+}
+
+// DeduplicateStream drops near-duplicates from a live stream with bounded memory.
+// An LSH prefilter skips obvious pairs; only borderline pairs reach the LLM.
+//
+// Example:
+//
+//	unique := schemaflow.DeduplicateStream(events, schemaflow.DeduplicateStreamOptions{
+//	    Window: 5000,
+//	})
+//	for event := range unique {
+//	    handle(event)
+//	}
+func DeduplicateStream[T any](in <-chan T, opts ...DeduplicateStreamOptions) <-chan T {
+	return ops.DeduplicateStream[T](in, opts...)
+}
+
 // Assemble builds a complex typed object from multiple parts.
 //
 // Type parameter T specifies the target type to compose.